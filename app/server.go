@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"totesbackend/config"
+	"totesbackend/metrics"
+)
+
+// runServer atiende HTTPS (y la redirección HTTP y las métricas si están
+// configuradas) hasta recibir SIGINT o SIGTERM. Entonces marca el servicio
+// como no listo, deja de aceptar conexiones, espera las peticiones en curso y
+// las tareas en segundo plano hasta ShutdownTimeout y vuelve para que se
+// cierre la base de datos.
+func runServer(cfg config.ServerConfig) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	servers := []*http.Server{{Addr: cfg.Addr, Handler: router}}
+	serveErr := make(chan error, 3)
+	go func() {
+		serveErr <- servers[0].ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+	}()
+
+	if cfg.HTTPRedirectAddr != "" {
+		redirect := &http.Server{Addr: cfg.HTTPRedirectAddr, Handler: httpsRedirectHandler(cfg.Addr)}
+		servers = append(servers, redirect)
+		go func() {
+			serveErr <- redirect.ListenAndServe()
+		}()
+	}
+
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer := &http.Server{Addr: cfg.MetricsAddr, Handler: mux}
+		servers = append(servers, metricsServer)
+		go func() {
+			serveErr <- metricsServer.ListenAndServe()
+		}()
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining in-flight requests")
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			runErr = err
+		}
+	}
+	stop()
+
+	if healthService != nil {
+		healthService.MarkShuttingDown()
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("draining requests failed", "addr", server.Addr, "error", err)
+		}
+	}
+	for _, stopJob := range onShutdown {
+		stopJob(shutdownCtx)
+	}
+
+	if runErr == nil && errors.Is(shutdownCtx.Err(), context.DeadlineExceeded) {
+		slog.Warn("shutdown exceeded SHUTDOWN_TIMEOUT, in-flight work was cut off")
+	}
+	return runErr
+}
+
+// httpsRedirectHandler responde a todo con una redirección permanente a la
+// misma ruta en HTTPS.
+func httpsRedirectHandler(httpsAddr string) http.Handler {
+	_, httpsPort, _ := net.SplitHostPort(httpsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
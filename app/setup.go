@@ -1,14 +1,22 @@
 package app
 
 import (
+	"context"
+	"log/slog"
+	"os"
 	"time"
 	"totesbackend/config"
 	"totesbackend/controllers"
 	"totesbackend/controllers/utilities"
 	"totesbackend/database"
+	"totesbackend/logging"
+	"totesbackend/metrics"
+	"totesbackend/middleware"
 	"totesbackend/repositories"
 	routes "totesbackend/router"
 	"totesbackend/services"
+	"totesbackend/services/utils"
+	"totesbackend/validation"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -20,38 +28,60 @@ import (
 )
 
 var db *gorm.DB
+var appConfig *config.Config
 var router *gin.Engine
 var authUtil *utilities.AuthorizationUtil
 var logUtil *utilities.LogUtil
+var healthService *services.HealthService
+var securityEventService *services.SecurityEventService
+var passwordHasher utils.PasswordHasher
+var loginGuardService *services.LoginGuardService
+var twoFactorService *services.TwoFactorService
+var apiKeyService *services.APIKeyService
+var sessionService *services.SessionService
+
+// onShutdown son las tareas en segundo plano que se detienen al apagar el
+// servidor, después de drenar las peticiones y antes de cerrar la base.
+var onShutdown []func(ctx context.Context)
 
 // @schemes   https
 
 // SetupAndRunApp initializes and configures the entire application server,
 // including environment variables, database connection, middleware, route handlers,
 // CORS policies, and Swagger documentation.
-// It runs the HTTPS server on port 443 using TLS certificates.
+// It runs the HTTPS server on the configured address using TLS certificates.
 // If any initialization step fails, it returns an error.
 //
 // The function also performs the following steps:
-// - Loads environment variables
+// - Loads and validates the configuration (see config.Load)
 // - Starts and defers closure of the PostgreSQL connection
-// - Applies database migrations
+// - Applies pending database migrations (DB_AUTO_MIGRATE=true) or refuses to start if any are missing
 // - Initializes repositories, services, and utilities
 // - Registers all API route groups (users, roles, auth, billing, etc.)
-// - Enables CORS with specific allowed origins
+// - Enables CORS with the configured allowed origins
+// - Authenticates machine clients that send an X-API-Key header and users that send a session token
 // - Mounts the Swagger UI at /swagger/index.html
-// - Starts the HTTPS server
+// - Registers the /healthz and /readyz probes
+// - Starts the optional HTTP to HTTPS redirect and Prometheus /metrics listeners and the HTTPS server
+// - On SIGINT or SIGTERM drains in-flight requests, stops the background jobs
+//   and only then closes the database pool
 
 func SetupAndRunApp() error {
 
-	// load env
-	err := config.LoadENV()
+	// load config
+	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
+	err = cfg.Server.CheckTLSFiles()
+	if err != nil {
+		return err
+	}
+	appConfig = cfg
+	logging.Setup(cfg.Log, os.Stdout)
 
 	// start database
-	err = database.StartPostgres()
+	err = database.StartPostgres(cfg.Database)
 	if err != nil {
 		return err
 	}
@@ -60,21 +90,74 @@ func SetupAndRunApp() error {
 	defer database.ClosePostgres()
 
 	db = database.GetDB()
+	err = metrics.InstrumentDB(db)
+	if err != nil {
+		return err
+	}
 	userRepo := repositories.NewUserRepository(db)
 	authUtil = utilities.NewAuthorizationUtil(services.NewAuthorizationService(repositories.NewAuthorizationRepository(db), userRepo))
 	logUtil = utilities.NewLogUtil(services.NewUserLogService(repositories.NewUserLogRepository(db)))
-	router = gin.Default()
-	database.MigrateDB() // recordar descomentar para inicializar la base de datos
+	securityEventService = services.NewSecurityEventService(repositories.NewSecurityEventRepository(db))
+	passwordHasher = utils.NewPasswordHasher(cfg.Auth.Hashing)
+	loginGuardService = services.NewLoginGuardService(userRepo, repositories.NewLoginAttemptRepository(db), securityEventService,
+		passwordHasher, cfg.Auth.Login)
+	twoFactorService, err = services.NewTwoFactorService(repositories.NewTwoFactorRepository(db), userRepo, loginGuardService,
+		securityEventService, cfg.Auth.TwoFactor)
+	if err != nil {
+		return err
+	}
+	if cfg.Auth.TwoFactor.EncryptionKey == "" {
+		slog.Warn("TOTP_ENCRYPTION_KEY is not set, two-factor secrets are stored unencrypted")
+	}
+	apiKeyService = services.NewAPIKeyService(repositories.NewAPIKeyRepository(db), repositories.NewAuthorizationRepository(db),
+		securityEventService, cfg.Auth.APIKeys)
+	sessionService = services.NewSessionService(repositories.NewSessionRepository(db), userRepo, securityEventService,
+		cfg.Auth.Sessions)
+	gin.SetMode(cfg.Server.GinMode)
+	router = gin.New()
+
+	// ID de la petición y log de acceso estructurado; van primero para que
+	// el log vea también las respuestas de Recovery
+	router.Use(middleware.RequestID(), middleware.AccessLog(), gin.Recovery())
+
+	// Aplicar las migraciones pendientes o negarse a arrancar si faltan
+	err = database.EnsureSchema(cfg.Database.AutoMigrate)
+	if err != nil {
+		return err
+	}
+
+	// Reglas de validación de los DTOs que consultan la base de datos
+	err = validation.Setup(db, validation.Options{
+		EnforcePriceFloor: cfg.Business.EnforcePriceFloor,
+	})
+	if err != nil {
+		return err
+	}
 
 	// Configurar CORS
 	router.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:5503", "http://127.0.0.1:5500", "http://127.0.0.1:5501"}, // Especifica los orígenes permitidos
+		AllowOrigins:     cfg.CORS.AllowOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Username"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.APIKeyHeader, middleware.RequestIDHeader},
+		ExposeHeaders:    []string{middleware.RequestIDHeader},
 		AllowCredentials: true,
-		MaxAge:           12 * time.Hour,
+		MaxAge:           cfg.CORS.MaxAge,
 	}))
 
+	// Cuenta las peticiones y su latencia; va antes de ErrorHandler para ver
+	// el código de estado final
+	router.Use(middleware.Metrics())
+
+	// Convierte los errores agregados con c.Error en respuestas problem+json
+	router.Use(middleware.ErrorHandler())
+
+	// Autentica a los clientes de máquina que envían X-API-Key
+	router.Use(middleware.APIKey(apiKeyService))
+
+	// Autentica a los usuarios que envían Authorization: Bearer con el token
+	// de su sesión; sin sesión ni llave sólo se atienden las rutas públicas
+	router.Use(middleware.Session(sessionService, publicPaths...))
+
 	setUpUserRouter()
 	setUpItemTypeRouter()
 	setUpItemRouter()
@@ -94,19 +177,48 @@ func SetupAndRunApp() error {
 	setUpPurchaseOrderRouter()
 	setUpDiscountTypeRouter()
 	setUpUserCredentialValidationRouter()
+	setUpPasswordRouter()
+	setUpTwoFactorRouter()
+	setUpInvitationRouter()
+	if appConfig.Auth.OIDC.Enabled() {
+		setUpOIDCRouter()
+	}
 	setUpTaxTypeRouter()
 	setUpBillingRouter()
 	setUpInvoice()
 	setUpExternalSaleRouter()
 	setUpSalesReportRouter()
+	setUpPriceListRouter()
+	setUpMarginReportRouter()
+	setUpScheduledReportRouter()
+	setUpSecurityEventRouter()
+	setUpAPIKeyRouter()
+	setUpSessionRouter()
+	if err := setUpHealthRouter(); err != nil {
+		return err
+	}
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	err = router.RunTLS(":443", "certs/cert.pem", "certs/key.pem")
-	if err != nil {
-		panic(err)
-	}
+	return runServer(cfg.Server)
+}
 
-	return nil
+// publicPaths son las rutas que se atienden sin sesión ni llave de API: las
+// que sirven para iniciar sesión o recuperar el acceso (también el alta del
+// segundo factor, que se exige antes del primer inicio de sesión), las
+// sondas y la documentación. Las métricas no están aquí: se sirven en su propio
+// listener (METRICS_ADDR).
+var publicPaths = []string{
+	"/user-credential-validation",
+	"/two-factor/enroll",
+	"/two-factor/confirm",
+	"/password/forgot",
+	"/password/reset",
+	"/invitations/accept",
+	"/oidc/login",
+	"/oidc/callback",
+	"/healthz",
+	"/readyz",
+	"/swagger/*any",
 }
 
 func setUpPermissionRouter() {
@@ -167,7 +279,8 @@ func setUpIdentifierTypeRouter() {
 
 func setUpUserRouter() {
 	userRepo := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepo)
+	userService := services.NewUserService(userRepo, securityEventService, services.NewPasswordPolicy(appConfig.Auth.Password),
+		passwordHasher)
 	userController := controllers.NewUserController(userService, authUtil, logUtil)
 	routes.RegisterUserRoutes(router, userController)
 }
@@ -181,9 +294,42 @@ func setUpAdditionalExpenseRouter() {
 
 func setUpHistoricalItemPriceRouter() {
 	hisRepo := repositories.NewHistoricalItemPriceRepository(db)
-	hisService := services.NewHistoricalItemPriceService(hisRepo)
+	hisService := services.NewHistoricalItemPriceService(hisRepo, repositories.NewItemRepository(db))
 	hisController := controllers.NewHistoricalItemPriceController(hisService, authUtil, logUtil)
 	routes.RegisterHistoricalItemPriceRoutes(router, hisController)
+
+	onShutdown = append(onShutdown, startScheduledPriceUpdater(hisService, time.Minute))
+}
+
+// startScheduledPriceUpdater aplica periódicamente al precio de venta de los ítems
+// los cambios de precio programados que ya entraron en vigencia. Devuelve la
+// función que lo detiene esperando a que termine la pasada en curso.
+func startScheduledPriceUpdater(service *services.HistoricalItemPriceService, interval time.Duration) func(ctx context.Context) {
+	quit := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := service.ApplyDuePrices(context.Background()); err != nil {
+				slog.Error("applying scheduled item prices failed", "error", err)
+			}
+			select {
+			case <-ticker.C:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	return func(ctx context.Context) {
+		close(quit)
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
 }
 
 func setUpCommentRouter() {
@@ -203,7 +349,7 @@ func setUpAuthRouter() {
 
 func setUpAppointmentRouter() {
 	appointmentRepo := repositories.NewAppointmentRepository(db)
-	appointmentService := services.NewAppointmentService(appointmentRepo)
+	appointmentService := services.NewAppointmentService(appointmentRepo, appConfig.Business.MaxAppointmentsPerSlot)
 	appointmentController := controllers.NewAppointmentController(appointmentService, authUtil, logUtil)
 	routes.RegisterAppointmentRoutes(router, appointmentController)
 }
@@ -231,8 +377,13 @@ func setUpPurchaseOrderRouter() {
 	taxRepo := repositories.NewTaxTypeRepository(db)
 	invoiceRepo := repositories.NewInvoiceRepository(db)
 
-	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo)
-	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepo, itemRepo, billingService, invoiceRepo)
+	priceRepo := repositories.NewHistoricalItemPriceRepository(db)
+	priceListRepo := repositories.NewPriceListRepository(db)
+	customerRepo := repositories.NewCustomerRepository(db)
+	marginRepo := repositories.NewMarginRepository(db)
+
+	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo, priceRepo, priceListRepo, customerRepo, marginRepo)
+	purchaseOrderService := services.NewPurchaseOrderService(purchaseOrderRepo, itemRepo, billingService, invoiceRepo, appConfig.Business.EnterpriseInvoiceData)
 	purchaseOrderController := controllers.NewPurchaseOrderController(purchaseOrderService, authUtil, logUtil)
 
 	routes.RegisterPurchaseOrderRoutes(router, purchaseOrderController)
@@ -246,12 +397,24 @@ func setUpDiscountTypeRouter() {
 }
 
 func setUpUserCredentialValidationRouter() {
-	userRepository := repositories.NewUserRepository(db)
-	userCredentialValidationService := services.NewUserCredentialValidationService(userRepository)
+	userCredentialValidationService := services.NewUserCredentialValidationService(loginGuardService, twoFactorService,
+		sessionService)
 	userCredentialValidationController := controllers.NewUserCredentialValidationController(userCredentialValidationService, authUtil, logUtil)
 	routes.RegisterUserCredentialValidationRoutes(router, userCredentialValidationController)
 }
 
+func setUpPasswordRouter() {
+	passwordService := services.NewPasswordService(repositories.NewUserRepository(db), repositories.NewPasswordRepository(db),
+		loginGuardService, utils.NewMailSender(appConfig.SMTP), securityEventService, passwordHasher, appConfig.Auth.Password)
+	passwordController := controllers.NewPasswordController(passwordService, logUtil)
+	routes.RegisterPasswordRoutes(router, passwordController)
+}
+
+func setUpTwoFactorRouter() {
+	twoFactorController := controllers.NewTwoFactorController(twoFactorService, authUtil, logUtil)
+	routes.RegisterTwoFactorRoutes(router, twoFactorController)
+}
+
 func setUpTaxTypeRouter() {
 	taxTypeRepo := repositories.NewTaxTypeRepository(db)
 	taxTypeService := services.NewTaxTypeService(taxTypeRepo)
@@ -264,7 +427,12 @@ func setUpBillingRouter() {
 	discountRepo := repositories.NewDiscountTypeRepository(db)
 	taxRepo := repositories.NewTaxTypeRepository(db)
 
-	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo)
+	priceRepo := repositories.NewHistoricalItemPriceRepository(db)
+	priceListRepo := repositories.NewPriceListRepository(db)
+	customerRepo := repositories.NewCustomerRepository(db)
+	marginRepo := repositories.NewMarginRepository(db)
+
+	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo, priceRepo, priceListRepo, customerRepo, marginRepo)
 	billingController := controllers.NewBillingController(billingService, authUtil)
 
 	routes.RegisterBillingRoutes(router, billingController)
@@ -277,7 +445,12 @@ func setUpInvoice() {
 	discountRepo := repositories.NewDiscountTypeRepository(db)
 	taxRepo := repositories.NewTaxTypeRepository(db)
 
-	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo)
+	priceRepo := repositories.NewHistoricalItemPriceRepository(db)
+	priceListRepo := repositories.NewPriceListRepository(db)
+	customerRepo := repositories.NewCustomerRepository(db)
+	marginRepo := repositories.NewMarginRepository(db)
+
+	billingService := services.NewBillingService(billingRepo, discountRepo, taxRepo, priceRepo, priceListRepo, customerRepo, marginRepo)
 	invoiceService := services.NewInvoiceService(invoiceRepo, itemRepo, billingService)
 	invoiceController := controllers.NewInvoiceController(invoiceService, authUtil, logUtil)
 
@@ -294,7 +467,84 @@ func setUpExternalSaleRouter() {
 
 func setUpSalesReportRouter() {
 	invoiceRepo := repositories.NewInvoiceRepository(db)
-	salesReportService := services.NewSalesReportService(invoiceRepo)
+	salesReportRepo := repositories.NewSalesReportRepository(db)
+	salesReportService := services.NewSalesReportService(invoiceRepo, salesReportRepo)
 	salesReportController := controllers.NewSalesReportController(salesReportService, authUtil, logUtil)
 	routes.RegisterSalesReportRoutes(router, salesReportController)
 }
+
+func setUpPriceListRouter() {
+	priceListRepo := repositories.NewPriceListRepository(db)
+	customerRepo := repositories.NewCustomerRepository(db)
+	priceListService := services.NewPriceListService(priceListRepo, customerRepo)
+	priceListController := controllers.NewPriceListController(priceListService, authUtil, logUtil)
+	routes.RegisterPriceListRoutes(router, priceListController)
+}
+
+func setUpMarginReportRouter() {
+	marginRepo := repositories.NewMarginRepository(db)
+	itemRepo := repositories.NewItemRepository(db)
+	marginService := services.NewMarginService(marginRepo, itemRepo)
+	marginReportController := controllers.NewMarginReportController(marginService, authUtil, logUtil)
+	routes.RegisterMarginReportRoutes(router, marginReportController)
+}
+
+func setUpScheduledReportRouter() {
+	scheduledReportRepo := repositories.NewScheduledReportRepository(db)
+	salesReportService := services.NewSalesReportService(repositories.NewInvoiceRepository(db), repositories.NewSalesReportRepository(db))
+	scheduledReportService := services.NewScheduledReportService(scheduledReportRepo, salesReportService, utils.NewMailSender(appConfig.SMTP))
+	if err := scheduledReportService.Start(context.Background()); err != nil {
+		slog.Error("starting scheduled reports failed", "error", err)
+	}
+	onShutdown = append(onShutdown, func(ctx context.Context) {
+		select {
+		case <-scheduledReportService.Stop().Done():
+		case <-ctx.Done():
+		}
+	})
+
+	scheduledReportController := controllers.NewScheduledReportController(scheduledReportService, authUtil, logUtil)
+	routes.RegisterScheduledReportRoutes(router, scheduledReportController)
+}
+
+func setUpInvitationRouter() {
+	invitationService := services.NewInvitationService(repositories.NewInvitationRepository(db), repositories.NewUserRepository(db),
+		repositories.NewAuthorizationRepository(db), utils.NewMailSender(appConfig.SMTP), securityEventService,
+		services.NewPasswordPolicy(appConfig.Auth.Password), passwordHasher, appConfig.Auth.Invitation)
+	invitationController := controllers.NewInvitationController(invitationService, authUtil, logUtil)
+	routes.RegisterInvitationRoutes(router, invitationController)
+}
+
+func setUpOIDCRouter() {
+	oidcService := services.NewOIDCService(utils.NewOIDCProvider(appConfig.Auth.OIDC), repositories.NewOIDCRepository(db),
+		repositories.NewUserRepository(db), loginGuardService, twoFactorService, sessionService, securityEventService,
+		appConfig.Auth.OIDC)
+	oidcController := controllers.NewOIDCController(oidcService, logUtil)
+	routes.RegisterOIDCRoutes(router, oidcController)
+}
+
+func setUpSecurityEventRouter() {
+	securityEventController := controllers.NewSecurityEventController(securityEventService, authUtil, logUtil)
+	routes.RegisterSecurityEventRoutes(router, securityEventController)
+}
+
+func setUpAPIKeyRouter() {
+	apiKeyController := controllers.NewAPIKeyController(apiKeyService, authUtil, logUtil)
+	routes.RegisterAPIKeyRoutes(router, apiKeyController)
+}
+
+func setUpSessionRouter() {
+	sessionController := controllers.NewSessionController(sessionService, authUtil, logUtil)
+	routes.RegisterSessionRoutes(router, sessionController)
+}
+
+func setUpHealthRouter() error {
+	expectedVersion, err := database.LatestMigrationVersion()
+	if err != nil {
+		return err
+	}
+	healthService = services.NewHealthService(repositories.NewHealthRepository(db), expectedVersion)
+	healthController := controllers.NewHealthController(healthService)
+	routes.RegisterHealthRoutes(router, healthController)
+	return nil
+}
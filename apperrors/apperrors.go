@@ -0,0 +1,177 @@
+// Package apperrors define los errores de dominio de la aplicación. Cada error
+// lleva un código estable, el estado HTTP con el que se responde, una clave de
+// mensaje para traducirlo en el cliente y detalles opcionales.
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+)
+
+type Error struct {
+	Code       string                 // Código estable, por ejemplo "stock.insufficient"
+	Status     int                    // Estado HTTP de la respuesta
+	MessageKey string                 // Clave para traducir el mensaje, por ejemplo "errors.stock.insufficient"
+	Message    string                 // Mensaje por defecto en inglés
+	Details    map[string]interface{} // Datos adicionales del caso concreto
+	Err        error                  // Causa original, si la hay
+}
+
+// New crea un error de dominio. La clave de mensaje se deriva del código.
+func New(code string, status int, message string) *Error {
+	return &Error{Code: code, Status: status, MessageKey: "errors." + code, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is compara por código, de modo que errors.Is(err, ErrX) funciona también con
+// las copias creadas por WithDetail o Wrap.
+func (e *Error) Is(target error) bool {
+	var other *Error
+	if !errors.As(target, &other) {
+		return false
+	}
+	return e.Code == other.Code
+}
+
+// WithDetail devuelve una copia del error con un detalle agregado.
+func (e *Error) WithDetail(key string, value interface{}) *Error {
+	copied := *e
+	copied.Details = make(map[string]interface{}, len(e.Details)+1)
+	for k, v := range e.Details {
+		copied.Details[k] = v
+	}
+	copied.Details[key] = value
+	return &copied
+}
+
+// Wrap devuelve una copia del error que conserva err como causa.
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+// From busca un error de dominio en la cadena de err.
+func From(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// Errores genéricos.
+var (
+	ErrInternal   = New("internal_error", http.StatusInternalServerError, "internal server error")
+	ErrNotFound   = New("not_found", http.StatusNotFound, "resource not found")
+	ErrBadRequest = New("bad_request", http.StatusBadRequest, "invalid request")
+	ErrConflict   = New("conflict", http.StatusConflict, "the request conflicts with the current state")
+	// ErrValidation lleva en el detalle "fields" la lista de campos inválidos.
+	ErrValidation = New("validation.failed", http.StatusUnprocessableEntity, "request validation failed")
+	// ErrReferenceNotFound indica que la petición menciona un recurso (ítem,
+	// cliente, impuesto...) que no existe.
+	ErrReferenceNotFound = New("reference.not_found", http.StatusUnprocessableEntity, "a referenced resource does not exist")
+)
+
+// Errores de autenticación.
+var (
+	// ErrUnauthenticated indica que la ruta exige una sesión o una llave de
+	// API y la petición no trae ninguna.
+	ErrUnauthenticated    = New("auth.unauthenticated", http.StatusUnauthorized, "authentication is required")
+	ErrInvalidCredentials = New("auth.invalid_credentials", http.StatusUnauthorized, "invalid email or password")
+	ErrUserInactive       = New("auth.user_inactive", http.StatusForbidden, "user account is not active")
+	ErrForbidden          = New("auth.forbidden", http.StatusForbidden, "user does not have permission")
+	// ErrAccountLocked lleva en el detalle "locked_until" el fin del bloqueo.
+	ErrAccountLocked = New("auth.account_locked", http.StatusLocked, "account is locked after too many failed login attempts")
+	// ErrTooManyLoginAttempts lleva en el detalle "retry_after_seconds" la
+	// espera antes del siguiente intento; se responde también como Retry-After.
+	ErrTooManyLoginAttempts = New("auth.too_many_attempts", http.StatusTooManyRequests, "too many failed login attempts, try again later")
+	ErrUserNotLocked        = New("user.not_locked", http.StatusConflict, "user account is not locked")
+	// ErrPasswordLoginDisabled indica que la cuenta sólo entra con el
+	// proveedor de identidad.
+	ErrPasswordLoginDisabled = New("auth.password_login_disabled", http.StatusForbidden, "password login is disabled for this account, use single sign-on")
+)
+
+// Errores del inicio de sesión con el proveedor de identidad (OIDC).
+var (
+	// ErrInvalidSSOState indica que el state no corresponde a un inicio de
+	// sesión pendiente: ya se usó, venció, no lo generó este servidor o no
+	// coincide con la cookie del navegador que inició el flujo.
+	ErrInvalidSSOState = New("sso.invalid_state", http.StatusBadRequest, "single sign-on request is invalid or expired, start again")
+	// ErrSSOFailed cubre los fallos del canje del código y de la validación
+	// del ID token; la causa queda en el log.
+	ErrSSOFailed = New("sso.failed", http.StatusUnauthorized, "single sign-on failed")
+	// ErrSSOUnknownUser indica que el correo del proveedor no corresponde a
+	// ningún usuario; las cuentas se crean antes por invitación o por /users.
+	ErrSSOUnknownUser = New("sso.unknown_user", http.StatusForbidden, "no user account matches the identity provider email")
+)
+
+// Errores del segundo factor.
+var (
+	// ErrTwoFactorRequired indica que falta el código TOTP o de recuperación.
+	ErrTwoFactorRequired = New("auth.two_factor_required", http.StatusUnauthorized, "two-factor code is required")
+	// ErrTwoFactorEnrollmentRequired indica que el tipo de usuario exige TOTP
+	// y el usuario todavía no lo configuró.
+	ErrTwoFactorEnrollmentRequired = New("auth.two_factor_enrollment_required", http.StatusForbidden, "two-factor authentication must be set up before logging in")
+	ErrInvalidTwoFactorCode        = New("auth.invalid_two_factor_code", http.StatusUnauthorized, "invalid two-factor code")
+	ErrTwoFactorAlreadyEnabled     = New("two_factor.already_enabled", http.StatusConflict, "two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled         = New("two_factor.not_enabled", http.StatusConflict, "two-factor authentication is not enabled")
+	ErrTwoFactorNotPending         = New("two_factor.not_pending", http.StatusConflict, "there is no pending two-factor enrollment to confirm")
+	ErrTwoFactorMandatory          = New("two_factor.mandatory", http.StatusConflict, "two-factor authentication is mandatory for this user type")
+)
+
+// Errores de contraseñas.
+var (
+	// ErrWeakPassword lleva en el detalle "violations" las reglas incumplidas.
+	ErrWeakPassword         = New("password.policy", http.StatusUnprocessableEntity, "password does not meet the password policy")
+	ErrPasswordReused       = New("password.reused", http.StatusUnprocessableEntity, "password was used recently")
+	ErrWrongCurrentPassword = New("password.current_invalid", http.StatusForbidden, "current password is incorrect")
+	ErrInvalidResetToken    = New("password.invalid_reset_token", http.StatusBadRequest, "password reset token is invalid or expired")
+)
+
+// Errores de invitaciones.
+var (
+	ErrEmailTaken             = New("user.email_taken", http.StatusConflict, "a user with this email already exists")
+	ErrInvitationAccepted     = New("invitation.accepted", http.StatusConflict, "invitation was already accepted")
+	ErrInvalidInvitationToken = New("invitation.invalid_token", http.StatusBadRequest, "invitation token is invalid or expired")
+)
+
+// Errores de sesiones.
+var (
+	// ErrInvalidSession no distingue entre un token desconocido, vencido o
+	// revocado.
+	ErrInvalidSession = New("session.invalid", http.StatusUnauthorized, "session is invalid, expired or revoked")
+	ErrSessionRevoked = New("session.revoked", http.StatusConflict, "session is already revoked")
+)
+
+// Errores de llaves de API.
+var (
+	// ErrInvalidAPIKey no distingue entre una llave desconocida, vencida o
+	// revocada.
+	ErrInvalidAPIKey  = New("api_key.invalid", http.StatusUnauthorized, "api key is invalid, expired or revoked")
+	ErrAPIKeyInactive = New("api_key.inactive", http.StatusConflict, "api key is already revoked, expired or rotated")
+	// ErrAPIKeyExpiration indica que el vencimiento pedido ya pasó o supera
+	// el máximo configurado.
+	ErrAPIKeyExpiration = New("api_key.invalid_expiration", http.StatusUnprocessableEntity, "api key expiration is in the past or exceeds the maximum lifetime")
+)
+
+// Errores de inventario, órdenes y citas.
+var (
+	ErrInsufficientStock      = New("stock.insufficient", http.StatusConflict, "insufficient stock")
+	ErrInvalidOrderState      = New("purchase_order.invalid_state", http.StatusBadRequest, "invalid purchase order state")
+	ErrInvalidStateTransition = New("purchase_order.invalid_transition", http.StatusConflict, "purchase order cannot transition to the requested state")
+	ErrAppointmentSlotFull    = New("appointment.slot_full", http.StatusConflict, "no appointments available at this date and time")
+	// ErrDiscountRejected indica que un descuento solicitado no aplica a la
+	// compra; el texto del error lleva el motivo.
+	ErrDiscountRejected = New("discount.rejected", http.StatusUnprocessableEntity, "discount rejected")
+)
@@ -0,0 +1,123 @@
+// Package cli implementa los subcomandos de administración del binario. Sin
+// argumentos el binario levanta el servidor; con un subcomando ejecuta la tarea
+// y termina.
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"totesbackend/config"
+	"totesbackend/database"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"migrate", "migrate up | down [-steps N] | status", "apply, roll back or list the database migrations", runMigrate},
+		{"seed", "seed", "insert the missing reference data (identifier types, user and order states, permissions)", runSeed},
+		{"create-admin", "create-admin -email EMAIL [-password PASSWORD]", "create a user whose user type holds every permission", runCreateAdmin},
+		{"reset-password", "reset-password -email EMAIL [-password PASSWORD]", "set a new password for a user", runResetPassword},
+		{"recalculate-totals", "recalculate-totals [-dry-run]", "recompute invoice and purchase order totals from their stored lines", runRecalculateTotals},
+		{"export", "export -out FILE [-tables t1,t2] [-exclude t1,t2]", "dump the data of every table (or the given ones) to a JSON file; two-factor secrets only if encrypted", runExport},
+		{"import", "import -in FILE", "load a file written by export, skipping rows that already exist", runImport},
+	}
+}
+
+// Run ejecuta el subcomando args[0] con el resto de los argumentos y devuelve
+// el código de salida del proceso.
+func Run(args []string) int {
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		err := cmd.run(args[1:])
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	printUsage(os.Stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: totesbackend [command]")
+	fmt.Fprintln(w, "Without a command the HTTPS server is started.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-50s %s\n", cmd.usage, cmd.summary)
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+// connect carga la configuración y abre la base de datos igual que el servidor.
+func connect() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	return database.StartPostgres(cfg.Database)
+}
+
+// connectMigrated es connect para los comandos que trabajan con los datos: se
+// niega a continuar si faltan migraciones.
+func connectMigrated() error {
+	if err := connect(); err != nil {
+		return err
+	}
+	if err := database.CheckMigrations(); err != nil {
+		database.ClosePostgres()
+		return err
+	}
+	return nil
+}
+
+// passwordOrPrompt devuelve la contraseña recibida por flag o, si no se dio,
+// la lee de la entrada estándar para que no quede en el historial del shell.
+func passwordOrPrompt(password string) (string, error) {
+	if password != "" {
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	password = strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("password must not be empty")
+	}
+	return password, nil
+}
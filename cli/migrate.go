@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+	"totesbackend/database"
+)
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up | down [-steps N] | status")
+	}
+	action := args[0]
+
+	fs := newFlagSet("migrate " + action)
+	steps := fs.Int("steps", 1, "number of migrations to roll back (down only)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if err := connect(); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	switch action {
+	case "up":
+		applied, err := database.MigrateUp()
+		fmt.Printf("%d migration(s) applied\n", applied)
+		return err
+	case "down":
+		if *steps < 1 {
+			return fmt.Errorf("-steps must be at least 1")
+		}
+		reverted, err := database.MigrateDown(*steps)
+		fmt.Printf("%d migration(s) rolled back\n", reverted)
+		return err
+	case "status":
+		return printMigrationStatus()
+	default:
+		return fmt.Errorf("unknown migrate action %q, expected up, down or status", action)
+	}
+}
+
+func printMigrationStatus() error {
+	statuses, err := database.MigrationStatuses()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"totesbackend/database"
+)
+
+func runSeed(args []string) error {
+	if err := newFlagSet("seed").Parse(args); err != nil {
+		return err
+	}
+
+	if err := connectMigrated(); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	result, err := database.Seed()
+	if err != nil {
+		return err
+	}
+
+	tables := make([]string, 0, len(result))
+	for table := range result {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("%s: %d row(s) inserted\n", table, result[table])
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"totesbackend/database"
+	"totesbackend/repositories"
+	"totesbackend/services"
+)
+
+func runRecalculateTotals(args []string) error {
+	fs := newFlagSet("recalculate-totals")
+	dryRun := fs.Bool("dry-run", false, "only report how many records would change")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := connectMigrated(); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	db := database.GetDB()
+	invoiceRepo := repositories.NewInvoiceRepository(db)
+	invoiceService := services.NewInvoiceService(invoiceRepo, nil, nil)
+	purchaseOrderService := services.NewPurchaseOrderService(repositories.NewPurchaseOrderRepository(db), nil, nil, invoiceRepo, "")
+
+	ctx := context.Background()
+	verb := "updated"
+	if *dryRun {
+		verb = "would change"
+	}
+
+	invoices, err := invoiceService.RecalculateTotals(ctx, *dryRun)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("invoices: %d %s\n", invoices, verb)
+
+	purchaseOrders, err := purchaseOrderService.RecalculateTotals(ctx, *dryRun)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("purchase orders: %d %s\n", purchaseOrders, verb)
+	return nil
+}
@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"totesbackend/config"
+	"totesbackend/database"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// transferTables son las tablas que exportan e importan los comandos export e
+// import, ordenadas para que cada tabla vaya después de las que referencia.
+// Las tablas nuevas deben agregarse aquí.
+var transferTables = []string{
+	"item_types", "items", "additional_expenses",
+	"permissions", "roles", "role_permission", "role_scopes", "user_types", "user_type_has_role",
+	"identifier_types", "user_state_types", "users", "employees",
+	"historical_item_prices", "comments", "user_logs", "customers", "appointments",
+	"order_state_types", "purchase_orders", "discount_types", "purchase_order_discounts",
+	"tax_types", "purchase_order_taxes", "discount_type_item_types", "discount_type_items",
+	"invoices", "invoice_taxes", "invoice_discounts", "invoice_items", "purchase_order_items",
+	"api_keys", "api_key_permissions", "external_sales", "price_lists", "price_list_customers", "price_list_items", "price_list_rules",
+	"scheduled_reports", "report_runs", "login_attempts", "security_events",
+	"password_histories", "password_reset_tokens", "user_totps", "recovery_codes", "user_invitations",
+	"oidc_auth_requests", "user_sessions",
+}
+
+// Las semillas del segundo factor sólo se exportan cifradas: el archivo no
+// debe llevar semillas en claro, y la base de destino necesita el mismo
+// TOTP_ENCRYPTION_KEY para abrirlas. Sin ellas los usuarios vuelven a
+// configurar el segundo factor.
+const (
+	totpTable          = "user_totps"
+	recoveryCodesTable = "recovery_codes"
+)
+
+// dataDump es el formato del archivo de export. SchemaVersion es la última
+// migración aplicada en la base de origen; import exige que coincida.
+type dataDump struct {
+	SchemaVersion int         `json:"schema_version"`
+	ExportedAt    time.Time   `json:"exported_at"`
+	Tables        []tableDump `json:"tables"`
+}
+
+type tableDump struct {
+	Name string                   `json:"name"`
+	Rows []map[string]interface{} `json:"rows"`
+}
+
+func runExport(args []string) error {
+	fs := newFlagSet("export")
+	out := fs.String("out", "", "file to write")
+	only := fs.String("tables", "", "comma-separated tables to export (all by default)")
+	exclude := fs.String("exclude", "", "comma-separated tables to leave out")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+
+	tables, err := selectTables(*only, *exclude)
+	if err != nil {
+		return err
+	}
+
+	if err := connectMigrated(); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	version, err := schemaVersion()
+	if err != nil {
+		return err
+	}
+
+	dump := dataDump{SchemaVersion: version, ExportedAt: time.Now()}
+	db := database.GetDB()
+	for _, table := range tables {
+		rows := []map[string]interface{}{}
+		if err := db.Table(table).Find(&rows).Error; err != nil {
+			return fmt.Errorf("%s: %w", table, err)
+		}
+		if table == totpTable {
+			if err := checkTOTPSecretsSealed(rows); err != nil {
+				return err
+			}
+			if len(rows) > 0 {
+				fmt.Printf("%s: the secrets are encrypted; the target must use the same TOTP_ENCRYPTION_KEY\n", table)
+			}
+		}
+		dump.Tables = append(dump.Tables, tableDump{Name: table, Rows: rows})
+		fmt.Printf("%s: %d row(s)\n", table, len(rows))
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return json.NewEncoder(file).Encode(dump)
+}
+
+func runImport(args []string) error {
+	fs := newFlagSet("import")
+	in := fs.String("in", "", "file written by export")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+
+	file, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// UseNumber conserva los enteros grandes y los decimales tal como se
+	// exportaron
+	var dump dataDump
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	if err := decoder.Decode(&dump); err != nil {
+		return fmt.Errorf("reading %s: %w", *in, err)
+	}
+
+	if err := connectMigrated(); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	version, err := schemaVersion()
+	if err != nil {
+		return err
+	}
+	if dump.SchemaVersion != version {
+		return fmt.Errorf("the file was exported at schema version %d but the database is at %d", dump.SchemaVersion, version)
+	}
+
+	byName := make(map[string]tableDump, len(dump.Tables))
+	for _, table := range dump.Tables {
+		byName[table.Name] = table
+	}
+	if err := checkTOTPSecretsOpen(byName[totpTable].Rows); err != nil {
+		return err
+	}
+
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, name := range transferTables {
+			table, ok := byName[name]
+			if !ok || len(table.Rows) == 0 {
+				continue
+			}
+			inserted, err := importTable(tx, table)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			fmt.Printf("%s: %d of %d row(s) inserted\n", name, inserted, len(table.Rows))
+		}
+		return nil
+	})
+}
+
+// importTable inserta las filas que no existen. Las columnas bytea se exportan
+// en base64 y se decodifican antes de insertarlas.
+func importTable(tx *gorm.DB, table tableDump) (int64, error) {
+	columnTypes, err := tx.Migrator().ColumnTypes(table.Name)
+	if err != nil {
+		return 0, err
+	}
+	binary := map[string]bool{}
+	for _, column := range columnTypes {
+		if strings.EqualFold(column.DatabaseTypeName(), "bytea") {
+			binary[column.Name()] = true
+		}
+	}
+
+	for _, row := range table.Rows {
+		for column, value := range row {
+			switch v := value.(type) {
+			case json.Number:
+				row[column] = v.String()
+			case string:
+				if binary[column] {
+					decoded, err := base64.StdEncoding.DecodeString(v)
+					if err != nil {
+						return 0, fmt.Errorf("column %s: %w", column, err)
+					}
+					row[column] = decoded
+				}
+			}
+		}
+	}
+
+	res := tx.Table(table.Name).Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(table.Rows, 200)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+
+	if _, ok := table.Rows[0]["id"]; ok {
+		if err := database.ResetSequence(tx, table.Name); err != nil {
+			return 0, err
+		}
+	}
+	return res.RowsAffected, nil
+}
+
+// checkTOTPSecretsSealed se niega a exportar semillas del segundo factor en
+// claro, que quedan así si no se configuró TOTP_ENCRYPTION_KEY.
+func checkTOTPSecretsSealed(rows []map[string]interface{}) error {
+	plain := 0
+	for _, row := range rows {
+		if secret, _ := row["secret"].(string); !utils.IsSealed(secret) {
+			plain++
+		}
+	}
+	if plain > 0 {
+		return fmt.Errorf("%s: %d secret(s) are stored unencrypted; export with -exclude %s,%s and have those users set up two-factor authentication again",
+			totpTable, plain, totpTable, recoveryCodesTable)
+	}
+	return nil
+}
+
+// checkTOTPSecretsOpen comprueba, antes de importar, que el TOTP_ENCRYPTION_KEY
+// de esta instalación abre las semillas exportadas.
+func checkTOTPSecretsOpen(rows []map[string]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	secrets, err := utils.NewSecretBox(cfg.Auth.TwoFactor.EncryptionKey)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		secret, _ := row["secret"].(string)
+		if _, err := secrets.Open(secret); err != nil || !utils.IsSealed(secret) {
+			return fmt.Errorf("%s: the secrets cannot be opened with this TOTP_ENCRYPTION_KEY; use the key of the source database", totpTable)
+		}
+	}
+	return nil
+}
+
+func selectTables(only, exclude string) ([]string, error) {
+	known := make(map[string]bool, len(transferTables))
+	for _, table := range transferTables {
+		known[table] = true
+	}
+	split := func(list string) (map[string]bool, []string, error) {
+		set := map[string]bool{}
+		var ordered []string
+		for _, table := range strings.Split(list, ",") {
+			table = strings.TrimSpace(table)
+			if !known[table] {
+				return nil, nil, fmt.Errorf("unknown table %q", table)
+			}
+			set[table] = true
+			ordered = append(ordered, table)
+		}
+		return set, ordered, nil
+	}
+
+	tables := transferTables
+	if only != "" {
+		var err error
+		if _, tables, err = split(only); err != nil {
+			return nil, err
+		}
+	}
+	if exclude == "" {
+		return tables, nil
+	}
+
+	skip, _, err := split(exclude)
+	if err != nil {
+		return nil, err
+	}
+	var selected []string
+	for _, table := range tables {
+		if !skip[table] {
+			selected = append(selected, table)
+		}
+	}
+	return selected, nil
+}
+
+func schemaVersion() (int, error) {
+	statuses, err := database.MigrationStatuses()
+	if err != nil {
+		return 0, err
+	}
+	version := 0
+	for _, status := range statuses {
+		if status.AppliedAt != nil && status.Version > version {
+			version = status.Version
+		}
+	}
+	return version, nil
+}
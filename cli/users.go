@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"totesbackend/config"
+	"totesbackend/database"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+// adminName es el nombre del rol y del tipo de usuario con todos los permisos.
+const adminName = "Administrator"
+
+func runCreateAdmin(args []string) error {
+	fs := newFlagSet("create-admin")
+	email := fs.String("email", "", "email of the new user")
+	password := fs.String("password", "", "password of the new user (read from stdin if omitted)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	pass, err := passwordOrPrompt(*password)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := hashPassword(pass)
+	if err != nil {
+		return err
+	}
+
+	if err := connectMigrated(); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		var permissions []models.Permission
+		if err := tx.Find(&permissions).Error; err != nil {
+			return err
+		}
+		var active models.UserStateType
+		if err := tx.Where("name = ?", "Active").First(&active).Error; err != nil || len(permissions) == 0 {
+			return errors.New("reference data is missing, run the seed command first")
+		}
+
+		// El rol recibe todos los permisos existentes, también los agregados
+		// después de la última vez que se ejecutó el comando
+		role := models.Role{Name: adminName}
+		if err := tx.Where("name = ?", adminName).
+			Attrs(models.Role{Description: "Every permission"}).FirstOrCreate(&role).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&role).Association("Permissions").Replace(permissions); err != nil {
+			return err
+		}
+
+		userType := models.UserType{Name: adminName}
+		if err := tx.Where("name = ?", adminName).
+			Attrs(models.UserType{Description: "Users with every permission", RequireTwoFactor: true}).FirstOrCreate(&userType).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&userType).Association("Roles").Append(&role); err != nil {
+			return err
+		}
+
+		var existing int64
+		if err := tx.Model(&models.User{}).Where("email = ?", *email).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return fmt.Errorf("a user with email %s already exists, use reset-password to change its password", *email)
+		}
+
+		return tx.Create(&models.User{
+			Email:           *email,
+			Password:        hashedPassword,
+			UserStateTypeID: active.ID,
+			UserTypeID:      int(userType.ID),
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("administrator %s created, set up two-factor authentication (POST /two-factor/enroll) before the first login\n", *email)
+	return nil
+}
+
+func runResetPassword(args []string) error {
+	fs := newFlagSet("reset-password")
+	email := fs.String("email", "", "email of the user")
+	password := fs.String("password", "", "new password (read from stdin if omitted)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return errors.New("-email is required")
+	}
+
+	pass, err := passwordOrPrompt(*password)
+	if err != nil {
+		return err
+	}
+	hashedPassword, err := hashPassword(pass)
+	if err != nil {
+		return err
+	}
+
+	if err := connectMigrated(); err != nil {
+		return err
+	}
+	defer database.ClosePostgres()
+
+	db := database.GetDB()
+	user, err := repositories.NewUserRepository(db).GetUserByEmail(context.Background(), *email)
+	if err != nil {
+		return fmt.Errorf("user %s: %w", *email, err)
+	}
+	if err := db.Model(&models.User{}).Where("id = ?", user.ID).Update("password", hashedPassword).Error; err != nil {
+		return err
+	}
+
+	fmt.Printf("password of %s updated\n", *email)
+	return nil
+}
+
+// hashPassword calcula el hash con el mismo algoritmo y parámetros que usa el
+// servidor.
+func hashPassword(password string) (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	return utils.NewPasswordHasher(cfg.Auth.Hashing).Hash(password)
+}
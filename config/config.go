@@ -0,0 +1,711 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config reúne la configuración del servidor. Se carga una sola vez al arrancar
+// con Load y se pasa a quien la necesite; ningún otro paquete lee el entorno.
+type Config struct {
+	Server   ServerConfig
+	CORS     CORSConfig
+	Database DatabaseConfig
+	SMTP     SMTPConfig
+	Business BusinessConfig
+	Log      LogConfig
+	Auth     AuthConfig
+}
+
+// ServerConfig define dónde escucha el servidor HTTPS.
+type ServerConfig struct {
+	// Addr es la dirección del listener HTTPS (SERVER_ADDR).
+	Addr string
+	// TLSCertFile y TLSKeyFile son el certificado y la llave (TLS_CERT_FILE, TLS_KEY_FILE).
+	TLSCertFile string
+	TLSKeyFile  string
+	// HTTPRedirectAddr, si no está vacío, levanta un listener HTTP que redirige
+	// a HTTPS (HTTP_REDIRECT_ADDR, por ejemplo ":80").
+	HTTPRedirectAddr string
+	// MetricsAddr, si no está vacío, levanta un listener HTTP aparte que sólo
+	// sirve /metrics (METRICS_ADDR, por ejemplo "127.0.0.1:9090"). Las métricas
+	// no se exponen en el listener de la API.
+	MetricsAddr string
+	// GinMode es debug, release o test (GIN_MODE).
+	GinMode string
+	// ShutdownTimeout es cuánto se espera a las peticiones en curso al recibir
+	// SIGTERM antes de cerrarlas (SHUTDOWN_TIMEOUT).
+	ShutdownTimeout time.Duration
+}
+
+// CORSConfig define los orígenes que pueden llamar a la API desde el navegador.
+type CORSConfig struct {
+	// AllowOrigins se lee de CORS_ALLOWED_ORIGINS separado por comas.
+	AllowOrigins []string
+	MaxAge       time.Duration
+}
+
+// DatabaseConfig define la conexión y el pool de PostgreSQL.
+type DatabaseConfig struct {
+	URI             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// ConnectTimeout limita la verificación de la conexión al arrancar.
+	ConnectTimeout time.Duration
+	// AutoMigrate aplica las migraciones pendientes al arrancar (DB_AUTO_MIGRATE).
+	AutoMigrate bool
+}
+
+// SMTPConfig define el servidor de correo de los reportes programados. Sin
+// Host el envío de correos queda deshabilitado.
+type SMTPConfig struct {
+	// Sender es smtp o log (MAIL_SENDER). Con log los correos se escriben en
+	// el log en vez de enviarse, para desarrollo.
+	Sender   string
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// LogConfig define el formato de los logs estructurados.
+type LogConfig struct {
+	// Level es debug, info, warn o error (LOG_LEVEL).
+	Level string
+	// Format es json o text (LOG_FORMAT).
+	Format string
+}
+
+// AuthConfig agrupa los parámetros de autenticación.
+type AuthConfig struct {
+	Login      LoginConfig
+	Password   PasswordConfig
+	Hashing    HashingConfig
+	Invitation InvitationConfig
+	TwoFactor  TwoFactorConfig
+	OIDC       OIDCConfig
+	APIKeys    APIKeyConfig
+	Sessions   SessionConfig
+}
+
+// OIDCConfig define el inicio de sesión con el proveedor de identidad de la
+// empresa (OpenID Connect con código de autorización y PKCE). Sin IssuerURL
+// está desactivado.
+type OIDCConfig struct {
+	// IssuerURL es el emisor; sus endpoints se descubren en
+	// IssuerURL/.well-known/openid-configuration (OIDC_ISSUER_URL).
+	IssuerURL string
+	// ClientID y ClientSecret identifican a la aplicación ante el proveedor
+	// (OIDC_CLIENT_ID, OIDC_CLIENT_SECRET). El secreto es opcional: los
+	// clientes públicos se apoyan sólo en PKCE.
+	ClientID     string
+	ClientSecret string
+	// RedirectURL es la página del cliente que recibe code y state del
+	// proveedor y los envía a POST /oidc/callback (OIDC_REDIRECT_URL).
+	RedirectURL string
+	// Scopes se piden además de openid (OIDC_SCOPES).
+	Scopes []string
+	// EmailClaim es el claim con el correo del usuario (OIDC_EMAIL_CLAIM).
+	EmailClaim string
+	// AllowUnverifiedEmail acepta tokens sin email_verified=true, para
+	// proveedores que no lo envían (OIDC_ALLOW_UNVERIFIED_EMAIL).
+	AllowUnverifiedEmail bool
+	// GroupsClaim es el claim con los grupos del usuario (OIDC_GROUPS_CLAIM).
+	GroupsClaim string
+	// GroupUserTypes asigna el tipo de usuario según los grupos; gana el
+	// primero de la lista que el usuario tenga. Se lee de
+	// OIDC_GROUP_USER_TYPES como "grupo=id_tipo,grupo=id_tipo".
+	GroupUserTypes []GroupUserType
+	// TrustedAMR son los valores del claim amr con los que se acepta que el
+	// proveedor ya pidió un segundo factor (OIDC_TRUSTED_AMR, por ejemplo
+	// "mfa,otp,hwk"). Si el token no trae ninguno, o la lista está vacía, se
+	// exige el segundo factor propio según el tipo de usuario.
+	TrustedAMR []string
+	// LoginTTL es cuánto puede tardar el usuario en volver del proveedor (OIDC_LOGIN_TTL).
+	LoginTTL time.Duration
+}
+
+// GroupUserType asocia un grupo del proveedor con un tipo de usuario.
+type GroupUserType struct {
+	Group      string
+	UserTypeID int
+}
+
+// Enabled indica si se configuró un proveedor.
+func (c OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
+// InvitationConfig define las invitaciones de usuarios por correo.
+type InvitationConfig struct {
+	// TTL es la vigencia del token de la invitación (INVITATION_TTL).
+	TTL time.Duration
+	// AcceptURL es la página del cliente que recibe el token; se le agrega
+	// ?token=... (INVITATION_URL). Vacía, el correo lleva sólo el token.
+	AcceptURL string
+}
+
+// APIKeyConfig define las llaves de API de los clientes de máquina.
+type APIKeyConfig struct {
+	// DefaultTTL es la vigencia de las llaves creadas o rotadas sin
+	// vencimiento explícito (API_KEY_TTL).
+	DefaultTTL time.Duration
+	// MaxTTL es la vigencia máxima que se puede pedir (API_KEY_MAX_TTL).
+	MaxTTL time.Duration
+	// RotationGrace es cuánto sigue sirviendo la llave anterior después de
+	// rotarla, para que el cliente alcance a cambiarla (API_KEY_ROTATION_GRACE).
+	RotationGrace time.Duration
+}
+
+// SessionConfig define la vigencia de las sesiones creadas al iniciar sesión.
+type SessionConfig struct {
+	// IdleTimeout cierra la sesión si no se usa durante ese tiempo
+	// (SESSION_IDLE_TIMEOUT).
+	IdleTimeout time.Duration
+	// MaxLifetime es la vigencia máxima de una sesión aunque se siga usando
+	// (SESSION_MAX_LIFETIME).
+	MaxLifetime time.Duration
+}
+
+// Algoritmos para HashingConfig.Algorithm.
+const (
+	HashArgon2id = "argon2id"
+	HashBcrypt   = "bcrypt"
+)
+
+// HashingConfig define cómo se calculan los hashes de las contraseñas nuevas.
+// Los hashes guardados con otro algoritmo o con otros parámetros se siguen
+// aceptando y se recalculan con estos al iniciar sesión, así que se puede
+// subir el costo sin obligar a cambiar las contraseñas.
+type HashingConfig struct {
+	// Algorithm es argon2id o bcrypt (PASSWORD_HASH_ALGORITHM).
+	Algorithm string
+	// BcryptCost es el costo de bcrypt (PASSWORD_BCRYPT_COST).
+	BcryptCost int
+	// Argon2Memory es la memoria de argon2id en KiB (PASSWORD_ARGON2_MEMORY).
+	Argon2Memory int
+	// Argon2Time es el número de pasadas de argon2id (PASSWORD_ARGON2_TIME).
+	Argon2Time int
+	// Argon2Threads es el paralelismo de argon2id (PASSWORD_ARGON2_THREADS).
+	Argon2Threads int
+}
+
+// TwoFactorConfig define el segundo factor TOTP.
+type TwoFactorConfig struct {
+	// Issuer es el nombre con el que aparece la cuenta en la aplicación de
+	// autenticación (TOTP_ISSUER).
+	Issuer string
+	// EncryptionKey cifra las semillas guardadas (TOTP_ENCRYPTION_KEY). Vacía
+	// se guardan sin cifrar.
+	EncryptionKey string
+	// Skew es cuántos pasos de 30 segundos de desfase de reloj se aceptan
+	// hacia cada lado (TOTP_SKEW).
+	Skew int
+}
+
+// PasswordConfig define las reglas de las contraseñas y el restablecimiento
+// por correo.
+type PasswordConfig struct {
+	// MinLength es la longitud mínima (PASSWORD_MIN_LENGTH). bcrypt sólo usa
+	// los primeros 72 bytes y puede seguir en uso (o en hashes viejos), así
+	// que no se aceptan contraseñas más largas.
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// HistorySize es cuántas contraseñas anteriores, contando la actual, no
+	// se pueden reutilizar (PASSWORD_HISTORY); 0 lo desactiva.
+	HistorySize int
+	// ResetTokenTTL es la vigencia del token enviado por correo (PASSWORD_RESET_TOKEN_TTL).
+	ResetTokenTTL time.Duration
+	// ResetURL es la página del cliente que recibe el token; se le agrega
+	// ?token=... (PASSWORD_RESET_URL). Vacía, el correo lleva sólo el token.
+	ResetURL string
+}
+
+// LoginConfig define la protección contra intentos repetidos de inicio de
+// sesión. Cada fallo dentro de FailureWindow duplica la espera antes del
+// siguiente intento, desde BackoffBase hasta BackoffMax.
+type LoginConfig struct {
+	// MaxFailures es el número de fallos seguidos de una cuenta dentro de la
+	// ventana que la bloquean durante LockDuration (LOGIN_MAX_FAILURES).
+	MaxFailures int
+	// IPMaxFailures es el número de fallos de una misma IP dentro de la
+	// ventana a partir del cual se le impone espera (LOGIN_IP_MAX_FAILURES).
+	IPMaxFailures int
+	FailureWindow time.Duration
+	LockDuration  time.Duration
+	BackoffBase   time.Duration
+	BackoffMax    time.Duration
+}
+
+// BusinessConfig agrupa los parámetros de negocio.
+type BusinessConfig struct {
+	// EnterpriseInvoiceData es el dato de la empresa que se imprime en las
+	// facturas generadas al aprobar una orden de compra.
+	EnterpriseInvoiceData string
+	// EnforcePriceFloor rechaza precios de venta por debajo del costo.
+	EnforcePriceFloor bool
+	// MaxAppointmentsPerSlot es el máximo de citas en una misma fecha y hora.
+	MaxAppointmentsPerSlot int
+}
+
+var defaultCORSOrigins = []string{
+	"http://localhost:3000",
+	"http://127.0.0.1:5500",
+	"http://127.0.0.1:5501",
+	"http://127.0.0.1:5503",
+}
+
+// Load carga el archivo de entorno (ver LoadENV), lee las variables, completa
+// los valores por defecto y valida el resultado. Devuelve todos los problemas
+// encontrados en un único error para poder corregirlos de una vez.
+func Load() (*Config, error) {
+	if err := LoadENV(); err != nil {
+		return nil, err
+	}
+
+	env := &envReader{}
+	cfg := &Config{
+		Server: ServerConfig{
+			Addr:             env.string("SERVER_ADDR", ":443"),
+			TLSCertFile:      env.string("TLS_CERT_FILE", "certs/cert.pem"),
+			TLSKeyFile:       env.string("TLS_KEY_FILE", "certs/key.pem"),
+			HTTPRedirectAddr: env.string("HTTP_REDIRECT_ADDR", ""),
+			MetricsAddr:      env.string("METRICS_ADDR", ""),
+			GinMode:          env.string("GIN_MODE", "debug"),
+			ShutdownTimeout:  env.duration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		},
+		CORS: CORSConfig{
+			AllowOrigins: env.list("CORS_ALLOWED_ORIGINS", defaultCORSOrigins),
+			MaxAge:       env.duration("CORS_MAX_AGE", 12*time.Hour),
+		},
+		Database: DatabaseConfig{
+			URI:             env.string("POSTGRES_URI", ""),
+			MaxOpenConns:    env.int("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    env.int("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime: env.duration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime: env.duration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			ConnectTimeout:  env.duration("DB_CONNECT_TIMEOUT", 10*time.Second),
+			AutoMigrate:     env.bool("DB_AUTO_MIGRATE", false),
+		},
+		SMTP: SMTPConfig{
+			Sender:   strings.ToLower(env.string("MAIL_SENDER", "smtp")),
+			Host:     env.string("SMTP_HOST", ""),
+			Port:     env.int("SMTP_PORT", 25),
+			Username: env.string("SMTP_USERNAME", ""),
+			Password: env.string("SMTP_PASSWORD", ""),
+			From:     env.string("SMTP_FROM", ""),
+		},
+		Business: BusinessConfig{
+			EnterpriseInvoiceData:  env.string("ENTERPRISE_INVOICE_DATA", "TotesBGA"),
+			EnforcePriceFloor:      env.bool("ENFORCE_PRICE_FLOOR", false),
+			MaxAppointmentsPerSlot: env.int("MAX_APPOINTMENTS_PER_SLOT", 3),
+		},
+		Log: LogConfig{
+			Level:  strings.ToLower(env.string("LOG_LEVEL", "info")),
+			Format: strings.ToLower(env.string("LOG_FORMAT", "json")),
+		},
+		Auth: AuthConfig{
+			Login: LoginConfig{
+				MaxFailures:   env.int("LOGIN_MAX_FAILURES", 5),
+				IPMaxFailures: env.int("LOGIN_IP_MAX_FAILURES", 20),
+				FailureWindow: env.duration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+				LockDuration:  env.duration("LOGIN_LOCK_DURATION", 30*time.Minute),
+				BackoffBase:   env.duration("LOGIN_BACKOFF_BASE", time.Second),
+				BackoffMax:    env.duration("LOGIN_BACKOFF_MAX", time.Minute),
+			},
+			Password: PasswordConfig{
+				MinLength:     env.int("PASSWORD_MIN_LENGTH", 10),
+				RequireUpper:  env.bool("PASSWORD_REQUIRE_UPPER", true),
+				RequireLower:  env.bool("PASSWORD_REQUIRE_LOWER", true),
+				RequireDigit:  env.bool("PASSWORD_REQUIRE_DIGIT", true),
+				RequireSymbol: env.bool("PASSWORD_REQUIRE_SYMBOL", false),
+				HistorySize:   env.int("PASSWORD_HISTORY", 5),
+				ResetTokenTTL: env.duration("PASSWORD_RESET_TOKEN_TTL", 30*time.Minute),
+				ResetURL:      env.string("PASSWORD_RESET_URL", ""),
+			},
+			Hashing: HashingConfig{
+				Algorithm:     strings.ToLower(env.string("PASSWORD_HASH_ALGORITHM", HashArgon2id)),
+				BcryptCost:    env.int("PASSWORD_BCRYPT_COST", 10),
+				Argon2Memory:  env.int("PASSWORD_ARGON2_MEMORY", 19*1024),
+				Argon2Time:    env.int("PASSWORD_ARGON2_TIME", 2),
+				Argon2Threads: env.int("PASSWORD_ARGON2_THREADS", 1),
+			},
+			Invitation: InvitationConfig{
+				TTL:       env.duration("INVITATION_TTL", 72*time.Hour),
+				AcceptURL: env.string("INVITATION_URL", ""),
+			},
+			TwoFactor: TwoFactorConfig{
+				Issuer:        env.string("TOTP_ISSUER", "Totes"),
+				EncryptionKey: env.string("TOTP_ENCRYPTION_KEY", ""),
+				Skew:          env.int("TOTP_SKEW", 1),
+			},
+			OIDC: OIDCConfig{
+				IssuerURL:            env.string("OIDC_ISSUER_URL", ""),
+				ClientID:             env.string("OIDC_CLIENT_ID", ""),
+				ClientSecret:         env.string("OIDC_CLIENT_SECRET", ""),
+				RedirectURL:          env.string("OIDC_REDIRECT_URL", ""),
+				Scopes:               env.list("OIDC_SCOPES", []string{"email", "profile"}),
+				EmailClaim:           env.string("OIDC_EMAIL_CLAIM", "email"),
+				AllowUnverifiedEmail: env.bool("OIDC_ALLOW_UNVERIFIED_EMAIL", false),
+				GroupsClaim:          env.string("OIDC_GROUPS_CLAIM", ""),
+				GroupUserTypes:       env.groupUserTypes("OIDC_GROUP_USER_TYPES"),
+				TrustedAMR:           env.list("OIDC_TRUSTED_AMR", nil),
+				LoginTTL:             env.duration("OIDC_LOGIN_TTL", 10*time.Minute),
+			},
+			APIKeys: APIKeyConfig{
+				DefaultTTL:    env.duration("API_KEY_TTL", 90*24*time.Hour),
+				MaxTTL:        env.duration("API_KEY_MAX_TTL", 365*24*time.Hour),
+				RotationGrace: env.duration("API_KEY_ROTATION_GRACE", 24*time.Hour),
+			},
+			Sessions: SessionConfig{
+				IdleTimeout: env.duration("SESSION_IDLE_TIMEOUT", 8*time.Hour),
+				MaxLifetime: env.duration("SESSION_MAX_LIFETIME", 7*24*time.Hour),
+			},
+		},
+	}
+
+	errs := append(env.errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return cfg, nil
+}
+
+// validate revisa los valores ya leídos. Los archivos TLS no se revisan aquí
+// porque los comandos de administración no los necesitan; ver CheckTLSFiles.
+func (c *Config) validate() []error {
+	var errs []error
+	invalid := func(key string, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if _, _, err := net.SplitHostPort(c.Server.Addr); err != nil {
+		invalid("SERVER_ADDR", "%v", err)
+	}
+	if c.Server.HTTPRedirectAddr != "" {
+		if _, _, err := net.SplitHostPort(c.Server.HTTPRedirectAddr); err != nil {
+			invalid("HTTP_REDIRECT_ADDR", "%v", err)
+		} else if c.Server.HTTPRedirectAddr == c.Server.Addr {
+			invalid("HTTP_REDIRECT_ADDR", "must differ from SERVER_ADDR")
+		}
+	}
+	if c.Server.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.Server.MetricsAddr); err != nil {
+			invalid("METRICS_ADDR", "%v", err)
+		} else if c.Server.MetricsAddr == c.Server.Addr || c.Server.MetricsAddr == c.Server.HTTPRedirectAddr {
+			invalid("METRICS_ADDR", "must differ from SERVER_ADDR and HTTP_REDIRECT_ADDR")
+		}
+	}
+	if c.Server.TLSCertFile == "" {
+		invalid("TLS_CERT_FILE", "must not be empty")
+	}
+	if c.Server.TLSKeyFile == "" {
+		invalid("TLS_KEY_FILE", "must not be empty")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		invalid("SHUTDOWN_TIMEOUT", "must be positive")
+	}
+	switch c.Server.GinMode {
+	case "debug", "release", "test":
+	default:
+		invalid("GIN_MODE", "must be debug, release or test, got %q", c.Server.GinMode)
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		invalid("CORS_ALLOWED_ORIGINS", "must list at least one origin")
+	}
+	for _, origin := range c.CORS.AllowOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			invalid("CORS_ALLOWED_ORIGINS", "%q is not an origin like https://example.com", origin)
+		}
+	}
+
+	if c.Database.URI == "" {
+		invalid("POSTGRES_URI", "is required")
+	}
+	if c.Database.MaxOpenConns < 1 {
+		invalid("DB_MAX_OPEN_CONNS", "must be at least 1")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		invalid("DB_MAX_IDLE_CONNS", "must be between 0 and DB_MAX_OPEN_CONNS")
+	}
+	if c.Database.ConnectTimeout <= 0 {
+		invalid("DB_CONNECT_TIMEOUT", "must be positive")
+	}
+
+	if c.SMTP.Port < 1 || c.SMTP.Port > 65535 {
+		invalid("SMTP_PORT", "must be between 1 and 65535")
+	}
+	if c.SMTP.Host != "" && c.SMTP.From == "" {
+		invalid("SMTP_FROM", "is required when SMTP_HOST is set")
+	}
+	switch c.SMTP.Sender {
+	case "smtp", "log":
+	default:
+		invalid("MAIL_SENDER", "must be smtp or log, got %q", c.SMTP.Sender)
+	}
+
+	if strings.TrimSpace(c.Business.EnterpriseInvoiceData) == "" {
+		invalid("ENTERPRISE_INVOICE_DATA", "must not be empty")
+	}
+	if c.Business.MaxAppointmentsPerSlot < 1 {
+		invalid("MAX_APPOINTMENTS_PER_SLOT", "must be at least 1")
+	}
+
+	login := c.Auth.Login
+	if login.MaxFailures < 1 {
+		invalid("LOGIN_MAX_FAILURES", "must be at least 1")
+	}
+	if login.IPMaxFailures < 1 {
+		invalid("LOGIN_IP_MAX_FAILURES", "must be at least 1")
+	}
+	if login.FailureWindow <= 0 {
+		invalid("LOGIN_FAILURE_WINDOW", "must be positive")
+	}
+	if login.LockDuration <= 0 {
+		invalid("LOGIN_LOCK_DURATION", "must be positive")
+	}
+	if login.BackoffBase < 0 {
+		invalid("LOGIN_BACKOFF_BASE", "must not be negative")
+	}
+	if login.BackoffMax < login.BackoffBase {
+		invalid("LOGIN_BACKOFF_MAX", "must not be less than LOGIN_BACKOFF_BASE")
+	}
+
+	password := c.Auth.Password
+	if password.MinLength < 1 || password.MinLength > 72 {
+		invalid("PASSWORD_MIN_LENGTH", "must be between 1 and 72")
+	}
+	if password.HistorySize < 0 {
+		invalid("PASSWORD_HISTORY", "must not be negative")
+	}
+	if password.ResetTokenTTL <= 0 {
+		invalid("PASSWORD_RESET_TOKEN_TTL", "must be positive")
+	}
+	if password.ResetURL != "" {
+		u, err := url.Parse(password.ResetURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("PASSWORD_RESET_URL", "%q is not an absolute http(s) URL", password.ResetURL)
+		}
+	}
+
+	hashing := c.Auth.Hashing
+	switch hashing.Algorithm {
+	case HashArgon2id, HashBcrypt:
+	default:
+		invalid("PASSWORD_HASH_ALGORITHM", "must be argon2id or bcrypt, got %q", hashing.Algorithm)
+	}
+	if hashing.BcryptCost < 10 || hashing.BcryptCost > 31 {
+		invalid("PASSWORD_BCRYPT_COST", "must be between 10 and 31")
+	}
+	if hashing.Argon2Memory < 8*1024 || hashing.Argon2Memory > 4*1024*1024 {
+		invalid("PASSWORD_ARGON2_MEMORY", "must be between 8192 and 4194304 KiB")
+	}
+	if hashing.Argon2Time < 1 || hashing.Argon2Time > 100 {
+		invalid("PASSWORD_ARGON2_TIME", "must be between 1 and 100")
+	}
+	if hashing.Argon2Threads < 1 || hashing.Argon2Threads > 255 {
+		invalid("PASSWORD_ARGON2_THREADS", "must be between 1 and 255")
+	}
+
+	invitation := c.Auth.Invitation
+	if invitation.TTL <= 0 {
+		invalid("INVITATION_TTL", "must be positive")
+	}
+	if invitation.AcceptURL != "" {
+		u, err := url.Parse(invitation.AcceptURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("INVITATION_URL", "%q is not an absolute http(s) URL", invitation.AcceptURL)
+		}
+	}
+
+	apiKeys := c.Auth.APIKeys
+	if apiKeys.DefaultTTL <= 0 {
+		invalid("API_KEY_TTL", "must be positive")
+	}
+	if apiKeys.MaxTTL < apiKeys.DefaultTTL {
+		invalid("API_KEY_MAX_TTL", "must not be less than API_KEY_TTL")
+	}
+	if apiKeys.RotationGrace < 0 {
+		invalid("API_KEY_ROTATION_GRACE", "must not be negative")
+	}
+
+	sessions := c.Auth.Sessions
+	if sessions.IdleTimeout <= 0 {
+		invalid("SESSION_IDLE_TIMEOUT", "must be positive")
+	}
+	if sessions.MaxLifetime < sessions.IdleTimeout {
+		invalid("SESSION_MAX_LIFETIME", "must not be less than SESSION_IDLE_TIMEOUT")
+	}
+
+	if strings.Contains(c.Auth.TwoFactor.Issuer, ":") {
+		invalid("TOTP_ISSUER", "must not contain a colon")
+	}
+	if key := c.Auth.TwoFactor.EncryptionKey; key != "" && len(key) < 16 {
+		invalid("TOTP_ENCRYPTION_KEY", "must be at least 16 characters long")
+	}
+	if c.Auth.TwoFactor.Skew < 0 || c.Auth.TwoFactor.Skew > 3 {
+		invalid("TOTP_SKEW", "must be between 0 and 3")
+	}
+
+	if oidc := c.Auth.OIDC; oidc.Enabled() {
+		u, err := url.Parse(oidc.IssuerURL)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && !(u.Scheme == "http" && isLoopback(u.Hostname()))) {
+			invalid("OIDC_ISSUER_URL", "%q must be an https URL (http is allowed only for localhost)", oidc.IssuerURL)
+		}
+		if oidc.ClientID == "" {
+			invalid("OIDC_CLIENT_ID", "is required when OIDC_ISSUER_URL is set")
+		}
+		u, err = url.Parse(oidc.RedirectURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("OIDC_REDIRECT_URL", "%q is not an absolute http(s) URL", oidc.RedirectURL)
+		}
+		if oidc.EmailClaim == "" {
+			invalid("OIDC_EMAIL_CLAIM", "must not be empty")
+		}
+		if len(oidc.GroupUserTypes) > 0 && oidc.GroupsClaim == "" {
+			invalid("OIDC_GROUPS_CLAIM", "is required when OIDC_GROUP_USER_TYPES is set")
+		}
+		if oidc.LoginTTL <= 0 {
+			invalid("OIDC_LOGIN_TTL", "must be positive")
+		}
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		invalid("LOG_LEVEL", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch c.Log.Format {
+	case "json", "text":
+	default:
+		invalid("LOG_FORMAT", "must be json or text, got %q", c.Log.Format)
+	}
+	return errs
+}
+
+// isLoopback indica si host es localhost o una IP de loopback, donde se
+// permite http (por ejemplo un proveedor de identidad local para desarrollo).
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// CheckTLSFiles verifica que existan el certificado y la llave antes de abrir
+// el listener, para fallar con un mensaje claro en vez de al primer handshake.
+func (s ServerConfig) CheckTLSFiles() error {
+	var errs []error
+	for key, path := range map[string]string{"TLS_CERT_FILE": s.TLSCertFile, "TLS_KEY_FILE": s.TLSKeyFile} {
+		if _, err := os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// envReader lee variables de entorno con valor por defecto y acumula los
+// errores de formato en lugar de detenerse en el primero.
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) lookup(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	value = strings.TrimSpace(value)
+	return value, ok && value != ""
+}
+
+func (r *envReader) string(key, def string) string {
+	if value, ok := r.lookup(key); ok {
+		return value
+	}
+	return def
+}
+
+func (r *envReader) int(key string, def int) int {
+	value, ok := r.lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: %q is not an integer", key, value))
+		return def
+	}
+	return n
+}
+
+func (r *envReader) bool(key string, def bool) bool {
+	value, ok := r.lookup(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: %q is not a boolean", key, value))
+		return def
+	}
+	return b
+}
+
+func (r *envReader) duration(key string, def time.Duration) time.Duration {
+	value, ok := r.lookup(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: %q is not a duration like 30s or 5m", key, value))
+		return def
+	}
+	return d
+}
+
+// groupUserTypes lee una lista "grupo=id_tipo,grupo=id_tipo" conservando el orden.
+func (r *envReader) groupUserTypes(key string) []GroupUserType {
+	var mappings []GroupUserType
+	for _, item := range r.list(key, nil) {
+		group, id, ok := strings.Cut(item, "=")
+		userTypeID, err := strconv.Atoi(strings.TrimSpace(id))
+		group = strings.TrimSpace(group)
+		if !ok || group == "" || err != nil || userTypeID <= 0 {
+			r.errs = append(r.errs, fmt.Errorf("%s: %q is not a group=user_type_id pair", key, item))
+			continue
+		}
+		mappings = append(mappings, GroupUserType{Group: group, UserTypeID: userTypeID})
+	}
+	return mappings
+}
+
+func (r *envReader) list(key string, def []string) []string {
+	value, ok := r.lookup(key)
+	if !ok {
+		return def
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
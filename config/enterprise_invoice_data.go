@@ -1,5 +0,0 @@
-package config
-
-const (
-	ENTERPRISE_INVOICE_DATA = "TotesBGA"
-)
@@ -1,19 +1,30 @@
 package config
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 
 	"github.com/joho/godotenv"
 )
 
-// this function will load the .env file if the GO_ENV environment variable is not set
+// LoadENV carga el archivo de entorno cuando GO_ENV no está definido o vale
+// development. El archivo es ENV_FILE o, por defecto, .env; si no se indicó
+// ENV_FILE y no existe .env se sigue solo con las variables del proceso. Las
+// variables ya definidas en el proceso tienen prioridad sobre el archivo.
 func LoadENV() error {
 	goEnv := os.Getenv("GO_ENV")
-	if goEnv == "" || goEnv == "development" {
+	if goEnv != "" && goEnv != "development" {
+		return nil
+	}
+
+	file := os.Getenv("ENV_FILE")
+	if file == "" {
 		err := godotenv.Load()
-		if err != nil {
-			return err
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
 		}
+		return err
 	}
-	return nil
+	return godotenv.Load(file)
 }
@@ -0,0 +1,12 @@
+package config
+
+import "totesbackend/visibility"
+
+// FieldPermissions asocia cada clase de campos sensibles de las respuestas
+// con el permiso que permite verlos. Sin el permiso los campos se omiten o se
+// enmascaran (ver visibility.Filter).
+var FieldPermissions = map[string]int{
+	visibility.ItemCosts:             PERMISSION_VIEW_ITEM_COSTS,
+	visibility.CustomerContact:       PERMISSION_VIEW_CUSTOMER_CONTACT,
+	visibility.InvoiceEnterpriseData: PERMISSION_VIEW_INVOICE_ENTERPRISE_DATA,
+}
@@ -0,0 +1,147 @@
+package config
+
+// PermissionNames asocia cada permiso con el nombre con el que se siembra en la
+// tabla permissions. Todo permiso nuevo debe agregarse también aquí.
+var PermissionNames = map[int]string{
+	PERMISSION_GET_PERMISSION_BY_ID:                    "GET_PERMISSION_BY_ID",
+	PERMISSION_GET_ALL_PERMISSIONS:                     "GET_ALL_PERMISSIONS",
+	PERMISSION_SEARCH_PERMISSION_BY_ID:                 "SEARCH_PERMISSION_BY_ID",
+	PERMISSION_SEARCH_PERMISSION_BY_NAME:               "SEARCH_PERMISSION_BY_NAME",
+	PERMISSION_GET_ROLE_BY_ID:                          "GET_ROLE_BY_ID",
+	PERMISSION_GET_ALL_ROLES:                           "GET_ALL_ROLES",
+	PERMISSION_GET_ALL_PERMISSIONS_OF_ROLE:             "GET_ALL_PERMISSIONS_OF_ROLE",
+	PERMISSION_EXIST_ROLE:                              "EXIST_ROLE",
+	PERMISSION_SEARCH_ROLE_BY_NAME:                     "SEARCH_ROLE_BY_NAME",
+	PERMISSION_SEARCH_ROLE_BY_ID:                       "SEARCH_ROLE_BY_ID",
+	PERMISSION_SET_ROLE_SCOPES:                         "SET_ROLE_SCOPES",
+	PERMISSION_GET_USER_TYPE_BY_ID:                     "GET_USER_TYPE_BY_ID",
+	PERMISSION_GET_ALL_USER_TYPES:                      "GET_ALL_USER_TYPES",
+	PERMISSION_EXIST_USER_TYPE:                         "EXIST_USER_TYPE",
+	PERMISSION_SEARCH_USER_TYPES_BY_ID:                 "SEARCH_USER_TYPES_BY_ID",
+	PERMISSION_SEARCH_USER_TYPES_BY_NAME:               "SEARCH_USER_TYPES_BY_NAME",
+	PERMISSION_UPDATE_USER_TYPE_TWO_FACTOR:             "UPDATE_USER_TYPE_TWO_FACTOR",
+	PERMISSION_GET_USER_BY_ID:                          "GET_USER_BY_ID",
+	PERMISSION_GET_ALL_USERS:                           "GET_ALL_USERS",
+	PERMISSION_SEARCH_USER_BY_ID:                       "SEARCH_USER_BY_ID",
+	PERMISSION_SEARCH_USERS_BY_EMAIL:                   "SEARCH_USERS_BY_EMAIL",
+	PERMISSION_UPDATE_USER_STATE:                       "UPDATE_USER_STATE",
+	PERMISSION_UPDATE_USER:                             "UPDATE_USER",
+	PERMISSION_CREATE_USER:                             "CREATE_USER",
+	PERMISSION_USER_HAS_PERMISSION:                     "USER_HAS_PERMISSION",
+	PERMISSION_UNLOCK_USER:                             "UNLOCK_USER",
+	PERMISSION_RESET_USER_TWO_FACTOR:                   "RESET_USER_TWO_FACTOR",
+	PERMISSION_INVITE_USER:                             "INVITE_USER",
+	PERMISSION_GET_INVITATIONS:                         "GET_INVITATIONS",
+	PERMISSION_REVOKE_INVITATION:                       "REVOKE_INVITATION",
+	PERMISSION_UPDATE_USER_PASSWORD_LOGIN:              "UPDATE_USER_PASSWORD_LOGIN",
+	PERMISSION_GET_USER_SESSIONS:                       "GET_USER_SESSIONS",
+	PERMISSION_REVOKE_USER_SESSIONS:                    "REVOKE_USER_SESSIONS",
+	PERMISSION_GET_USER_STATE_TYPE_BY_ID:               "GET_USER_STATE_TYPE_BY_ID",
+	PERMISSION_GET_ALL_USER_STATE_TYPES:                "GET_ALL_USER_STATE_TYPES",
+	PERMISSION_GET_ALL_LOGS_FROM_USER:                  "GET_ALL_LOGS_FROM_USER",
+	PERMISSION_GET_EMPLOYEE_BY_ID:                      "GET_EMPLOYEE_BY_ID",
+	PERMISSION_GET_ALL_EMPLOYEES:                       "GET_ALL_EMPLOYEES",
+	PERMISSION_SEARCH_EMPLOYEES_BY_NAME:                "SEARCH_EMPLOYEES_BY_NAME",
+	PERMISSION_CREATE_EMPLOYEE:                         "CREATE_EMPLOYEE",
+	PERMISSION_UPDATE_EMPLOYEE:                         "UPDATE_EMPLOYEE",
+	PERMISSION_SEARCH_EMPLOYEES_BY_ID:                  "SEARCH_EMPLOYEES_BY_ID",
+	PERMISSION_GET_ITEM_TYPES_BY_ID:                    "GET_ITEM_TYPES_BY_ID",
+	PERMISSION_GET_ITEM_TYPES:                          "GET_ITEM_TYPES",
+	PERMISSION_GET_ITEM_BY_ID:                          "GET_ITEM_BY_ID",
+	PERMISSION_GET_ALL_ITEMS:                           "GET_ALL_ITEMS",
+	PERMISSION_SEARCH_ITEMS_BY_ID:                      "SEARCH_ITEMS_BY_ID",
+	PERMISSION_SEARCH_ITEMS_BY_NAME:                    "SEARCH_ITEMS_BY_NAME",
+	PERMISSION_UPDATE_ITEM_STATE:                       "UPDATE_ITEM_STATE",
+	PERMISSION_UPDATE_ITEM:                             "UPDATE_ITEM",
+	PERMISSION_CREATE_ITEM:                             "CREATE_ITEM",
+	PERMISSION_CHECK_ITEM_STOCK:                        "CHECK_ITEM_STOCK",
+	PERMISSION_GET_ADDITIONAL_EXPENSE_BY_ID:            "GET_ADDITIONAL_EXPENSE_BY_ID",
+	PERMISSION_GET_ALL_ADDITIONAL_EXPENSE:              "GET_ALL_ADDITIONAL_EXPENSE",
+	PERMISSION_CREATE_ADDITIONAL_EXPENSE:               "CREATE_ADDITIONAL_EXPENSE",
+	PERMISSION_DELETE_ADDITIONAL_EXPENSE:               "DELETE_ADDITIONAL_EXPENSE",
+	PERMISSION_UPDATE_ADDITIONAL_EXPENSE:               "UPDATE_ADDITIONAL_EXPENSE",
+	PERMISSION_GET_HISTORICAL_ITEM_PRICE:               "GET_HISTORICAL_ITEM_PRICE",
+	PERMISSION_GET_ITEM_PRICE_AS_OF:                    "GET_ITEM_PRICE_AS_OF",
+	PERMISSION_SCHEDULE_ITEM_PRICE:                     "SCHEDULE_ITEM_PRICE",
+	PERMISSION_CANCEL_SCHEDULED_ITEM_PRICE:             "CANCEL_SCHEDULED_ITEM_PRICE",
+	PERMISSION_GET_COMMENT_BY_ID:                       "GET_COMMENT_BY_ID",
+	PERMISSION_GET_ALL_COMMENTS:                        "GET_ALL_COMMENTS",
+	PERMISSION_SEARCH_COMMENTS_BY_EMAIL:                "SEARCH_COMMENTS_BY_EMAIL",
+	PERMISSION_CREATE_COMMENT:                          "CREATE_COMMENT",
+	PERMISSION_UPDATE_COMMENT:                          "UPDATE_COMMENT",
+	PERMISSION_SEARCH_COMMENTS_BY_NAME:                 "SEARCH_COMMENTS_BY_NAME",
+	PERMISSION_SEARCH_COMMENTS_BY_ID:                   "SEARCH_COMMENTS_BY_ID",
+	PERMISSION_GET_APPOINTMENT_BY_ID:                   "GET_APPOINTMENT_BY_ID",
+	PERMISSION_GET_ALL_APPOINTMENTS:                    "GET_ALL_APPOINTMENTS",
+	PERMISSION_SEARCH_APPOINTMENT_BY_STATE:             "SEARCH_APPOINTMENT_BY_STATE",
+	PERMISSION_GET_APPOINTMENT_BY_CUSTOMER_ID:          "GET_APPOINTMENT_BY_CUSTOMER_ID",
+	PERMISSION_CREATE_APPOINTMENT:                      "CREATE_APPOINTMENT",
+	PERMISSION_UPDATE_APPOINTMENT:                      "UPDATE_APPOINTMENT",
+	PERMISSION_SEARCH_APPOINTMENTS_BY_ID:               "SEARCH_APPOINTMENTS_BY_ID",
+	PERMISSION_SEARCH_APPOINTMENTS_BY_NAME:             "SEARCH_APPOINTMENTS_BY_NAME",
+	PERMISSION_GET_APPOINTMENTS_BY_CUSTOMERID_AND_DATE: "GET_APPOINTMENTS_BY_CUSTOMERID_AND_DATE",
+	PERMISSION_DELETE_APPOINTMENT:                      "DELETE_APPOINTMENT",
+	PERMISSION_GET_APPOINTMENTS_BY_HOUR:                "GET_APPOINTMENTS_BY_HOUR",
+	PERMISSION_GET_ALL_CUSTOMERS:                       "GET_ALL_CUSTOMERS",
+	PERMISSION_GET_CUSTOMER_BY_ID:                      "GET_CUSTOMER_BY_ID",
+	PERMISSION_CREATE_CUSTOMER:                         "CREATE_CUSTOMER",
+	PERMISSION_UPDATE_CUSTOMER:                         "UPDATE_CUSTOMER",
+	PERMISSION_GET_CUSTOMER_BY_EMAIL:                   "GET_CUSTOMER_BY_EMAIL",
+	PERMISSION_SEARCH_CUSTOMERS_BY_ID:                  "SEARCH_CUSTOMERS_BY_ID",
+	PERMISSION_SEARCH_CUSTOMERS_BY_NAME:                "SEARCH_CUSTOMERS_BY_NAME",
+	PERMISSION_SEARCH_CUSTOMERS_BY_LASTNAME:            "SEARCH_CUSTOMERS_BY_LASTNAME",
+	PERMISSION_GET_CUSTOMER_BY_CUSTOMERID:              "GET_CUSTOMER_BY_CUSTOMERID",
+	PERMISSION_GET_ALL_IDENTIFIER_TYPES:                "GET_ALL_IDENTIFIER_TYPES",
+	PERMISSION_GET_IDENTIFIER_TYPE_BY_ID:               "GET_IDENTIFIER_TYPE_BY_ID",
+	PERMISSION_GET_ORDER_STATE_TYPE_BY_ID:              "GET_ORDER_STATE_TYPE_BY_ID",
+	PERMISSION_GET_ALL_ORDER_STATE_TYPES:               "GET_ALL_ORDER_STATE_TYPES",
+	PERMISSION_GET_PURCHASE_ORDER_BY_ID:                "GET_PURCHASE_ORDER_BY_ID",
+	PERMISSION_GET_ALL_PURCHASE_ORDERS:                 "GET_ALL_PURCHASE_ORDERS",
+	PERMISSION_SEARCH_PURCHASE_ORDERS_BY_ID:            "SEARCH_PURCHASE_ORDERS_BY_ID",
+	PERMISSION_GET_PURCHASE_ORDERS_BY_CUSTOMER_ID:      "GET_PURCHASE_ORDERS_BY_CUSTOMER_ID",
+	PERMISSION_GET_PURCHASE_ORDERS_BY_SELLER_ID:        "GET_PURCHASE_ORDERS_BY_SELLER_ID",
+	PERMISSION_UPDATE_PURCHASE_ORDER_STATE:             "UPDATE_PURCHASE_ORDER_STATE",
+	PERMISSION_UPDATE_PURCHASE_ORDER:                   "UPDATE_PURCHASE_ORDER",
+	PERMISSION_CREATE_PURCHASE_ORDER:                   "CREATE_PURCHASE_ORDER",
+	PERMISSION_GET_PURCHASE_ORDERS_BY_STATE_ID:         "GET_PURCHASE_ORDERS_BY_STATE_ID",
+	PERMISSION_GET_DISCOUNT_TYPE_BY_ID:                 "GET_DISCOUNT_TYPE_BY_ID",
+	PERMISSION_GET_ALL_DISCOUNT_TYPES:                  "GET_ALL_DISCOUNT_TYPES",
+	PERMISSION_CREATE_DISCOUNT_TYPE:                    "CREATE_DISCOUNT_TYPE",
+	PERMISSION_GET_INVOICE_BY_ID:                       "GET_INVOICE_BY_ID",
+	PERMISSION_GET_ALL_INVOICES:                        "GET_ALL_INVOICES",
+	PERMISSION_SEARCH_INVOICE_BY_ID:                    "SEARCH_INVOICE_BY_ID",
+	PERMISSION_SEARCH_INVOICE_BY_CUSTOMER_PERSONAL_ID:  "SEARCH_INVOICE_BY_CUSTOMER_PERSONAL_ID",
+	PERMISSION_CREATE_INVOICE:                          "CREATE_INVOICE",
+	PERMISSION_CALCULATE_SUBTOTAL:                      "CALCULATE_SUBTOTAL",
+	PERMISSION_CALCULATE_TOTAL:                         "CALCULATE_TOTAL",
+	PERMISSION_GET_TAX_TYPE_BY_ID:                      "GET_TAX_TYPE_BY_ID",
+	PERMISSION_GET_ALL_TAX_TYPES:                       "GET_ALL_TAX_TYPES",
+	PERMISSION_CREATE_TAX_TYPE:                         "CREATE_TAX_TYPE",
+	PERMISSION_GET_EXTERNAL_SALE_BY_ID:                 "GET_EXTERNAL_SALE_BY_ID",
+	PERMISSION_GET_ALL_EXTERNAL_SALES:                  "GET_ALL_EXTERNAL_SALES",
+	PERMISSION_CREATE_EXTERNAL_SALE:                    "CREATE_EXTERNAL_SALE",
+	PERMISSION_VIEW_SALES_REPORT:                       "VIEW_SALES_REPORT",
+	PERMISSION_GET_PRICE_LIST_BY_ID:                    "GET_PRICE_LIST_BY_ID",
+	PERMISSION_GET_ALL_PRICE_LISTS:                     "GET_ALL_PRICE_LISTS",
+	PERMISSION_CREATE_PRICE_LIST:                       "CREATE_PRICE_LIST",
+	PERMISSION_UPDATE_PRICE_LIST:                       "UPDATE_PRICE_LIST",
+	PERMISSION_GET_CUSTOMER_PRICE_LISTS:                "GET_CUSTOMER_PRICE_LISTS",
+	PERMISSION_VIEW_MARGIN_REPORT:                      "VIEW_MARGIN_REPORT",
+	PERMISSION_GET_ITEM_LANDED_COST:                    "GET_ITEM_LANDED_COST",
+	PERMISSION_GET_ALL_SCHEDULED_REPORTS:               "GET_ALL_SCHEDULED_REPORTS",
+	PERMISSION_GET_SCHEDULED_REPORT_BY_ID:              "GET_SCHEDULED_REPORT_BY_ID",
+	PERMISSION_CREATE_SCHEDULED_REPORT:                 "CREATE_SCHEDULED_REPORT",
+	PERMISSION_UPDATE_SCHEDULED_REPORT:                 "UPDATE_SCHEDULED_REPORT",
+	PERMISSION_DELETE_SCHEDULED_REPORT:                 "DELETE_SCHEDULED_REPORT",
+	PERMISSION_RUN_SCHEDULED_REPORT:                    "RUN_SCHEDULED_REPORT",
+	PERMISSION_GET_REPORT_RUNS:                         "GET_REPORT_RUNS",
+	PERMISSION_DOWNLOAD_REPORT_ARTIFACT:                "DOWNLOAD_REPORT_ARTIFACT",
+	PERMISSION_GET_SECURITY_EVENTS:                     "GET_SECURITY_EVENTS",
+	PERMISSION_GET_API_KEYS:                            "GET_API_KEYS",
+	PERMISSION_CREATE_API_KEY:                          "CREATE_API_KEY",
+	PERMISSION_ROTATE_API_KEY:                          "ROTATE_API_KEY",
+	PERMISSION_REVOKE_API_KEY:                          "REVOKE_API_KEY",
+	PERMISSION_VIEW_ITEM_COSTS:                         "VIEW_ITEM_COSTS",
+	PERMISSION_VIEW_CUSTOMER_CONTACT:                   "VIEW_CUSTOMER_CONTACT",
+	PERMISSION_VIEW_INVOICE_ENTERPRISE_DATA:            "VIEW_INVOICE_ENTERPRISE_DATA",
+}
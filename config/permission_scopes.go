@@ -0,0 +1,31 @@
+package config
+
+import "totesbackend/datascope"
+
+// PermissionScopes asocia los permisos de lectura de los recursos con reglas
+// de alcance (listar, buscar y obtener por ID) con su recurso. Al revisar uno
+// de estos permisos se aplican las reglas de los roles del usuario para ese
+// recurso.
+var PermissionScopes = map[int]string{
+	PERMISSION_GET_PURCHASE_ORDER_BY_ID:           datascope.ResourcePurchaseOrders,
+	PERMISSION_GET_ALL_PURCHASE_ORDERS:            datascope.ResourcePurchaseOrders,
+	PERMISSION_SEARCH_PURCHASE_ORDERS_BY_ID:       datascope.ResourcePurchaseOrders,
+	PERMISSION_GET_PURCHASE_ORDERS_BY_CUSTOMER_ID: datascope.ResourcePurchaseOrders,
+	PERMISSION_GET_PURCHASE_ORDERS_BY_SELLER_ID:   datascope.ResourcePurchaseOrders,
+	PERMISSION_GET_PURCHASE_ORDERS_BY_STATE_ID:    datascope.ResourcePurchaseOrders,
+
+	PERMISSION_GET_ALL_CUSTOMERS:            datascope.ResourceCustomers,
+	PERMISSION_GET_CUSTOMER_BY_ID:           datascope.ResourceCustomers,
+	PERMISSION_GET_CUSTOMER_BY_EMAIL:        datascope.ResourceCustomers,
+	PERMISSION_SEARCH_CUSTOMERS_BY_ID:       datascope.ResourceCustomers,
+	PERMISSION_SEARCH_CUSTOMERS_BY_NAME:     datascope.ResourceCustomers,
+	PERMISSION_SEARCH_CUSTOMERS_BY_LASTNAME: datascope.ResourceCustomers,
+	PERMISSION_GET_CUSTOMER_BY_CUSTOMERID:   datascope.ResourceCustomers,
+
+	PERMISSION_GET_APPOINTMENT_BY_ID:                   datascope.ResourceAppointments,
+	PERMISSION_GET_ALL_APPOINTMENTS:                    datascope.ResourceAppointments,
+	PERMISSION_SEARCH_APPOINTMENT_BY_STATE:             datascope.ResourceAppointments,
+	PERMISSION_GET_APPOINTMENT_BY_CUSTOMER_ID:          datascope.ResourceAppointments,
+	PERMISSION_SEARCH_APPOINTMENTS_BY_ID:               datascope.ResourceAppointments,
+	PERMISSION_GET_APPOINTMENTS_BY_CUSTOMERID_AND_DATE: datascope.ResourceAppointments,
+}
@@ -11,11 +11,13 @@ const (
 	PERMISSION_EXIST_ROLE                              = 2004
 	PERMISSION_SEARCH_ROLE_BY_NAME                     = 2005
 	PERMISSION_SEARCH_ROLE_BY_ID                       = 2006
+	PERMISSION_SET_ROLE_SCOPES                         = 2007
 	PERMISSION_GET_USER_TYPE_BY_ID                     = 3001
 	PERMISSION_GET_ALL_USER_TYPES                      = 3002
 	PERMISSION_EXIST_USER_TYPE                         = 3003
 	PERMISSION_SEARCH_USER_TYPES_BY_ID                 = 3004
 	PERMISSION_SEARCH_USER_TYPES_BY_NAME               = 3005
+	PERMISSION_UPDATE_USER_TYPE_TWO_FACTOR             = 3006
 	PERMISSION_GET_USER_BY_ID                          = 4001
 	PERMISSION_GET_ALL_USERS                           = 4002
 	PERMISSION_SEARCH_USER_BY_ID                       = 4003
@@ -24,6 +26,14 @@ const (
 	PERMISSION_UPDATE_USER                             = 4006
 	PERMISSION_CREATE_USER                             = 4007
 	PERMISSION_USER_HAS_PERMISSION                     = 4008
+	PERMISSION_UNLOCK_USER                             = 4009
+	PERMISSION_RESET_USER_TWO_FACTOR                   = 4010
+	PERMISSION_INVITE_USER                             = 4011
+	PERMISSION_GET_INVITATIONS                         = 4012
+	PERMISSION_REVOKE_INVITATION                       = 4013
+	PERMISSION_UPDATE_USER_PASSWORD_LOGIN              = 4014
+	PERMISSION_GET_USER_SESSIONS                       = 4015
+	PERMISSION_REVOKE_USER_SESSIONS                    = 4016
 	PERMISSION_GET_USER_STATE_TYPE_BY_ID               = 5001
 	PERMISSION_GET_ALL_USER_STATE_TYPES                = 5002
 	PERMISSION_GET_ALL_LOGS_FROM_USER                  = 6001
@@ -49,6 +59,9 @@ const (
 	PERMISSION_DELETE_ADDITIONAL_EXPENSE               = 10004
 	PERMISSION_UPDATE_ADDITIONAL_EXPENSE               = 10005
 	PERMISSION_GET_HISTORICAL_ITEM_PRICE               = 11001
+	PERMISSION_GET_ITEM_PRICE_AS_OF                    = 11002
+	PERMISSION_SCHEDULE_ITEM_PRICE                     = 11003
+	PERMISSION_CANCEL_SCHEDULED_ITEM_PRICE             = 11004
 	PERMISSION_GET_COMMENT_BY_ID                       = 12001
 	PERMISSION_GET_ALL_COMMENTS                        = 12002
 	PERMISSION_SEARCH_COMMENTS_BY_EMAIL                = 12003
@@ -106,4 +119,27 @@ const (
 	PERMISSION_GET_ALL_EXTERNAL_SALES                  = 22002
 	PERMISSION_CREATE_EXTERNAL_SALE                    = 22003
 	PERMISSION_VIEW_SALES_REPORT                       = 23001
+	PERMISSION_GET_PRICE_LIST_BY_ID                    = 24001
+	PERMISSION_GET_ALL_PRICE_LISTS                     = 24002
+	PERMISSION_CREATE_PRICE_LIST                       = 24003
+	PERMISSION_UPDATE_PRICE_LIST                       = 24004
+	PERMISSION_GET_CUSTOMER_PRICE_LISTS                = 24005
+	PERMISSION_VIEW_MARGIN_REPORT                      = 25001
+	PERMISSION_GET_ITEM_LANDED_COST                    = 25002
+	PERMISSION_GET_ALL_SCHEDULED_REPORTS               = 26001
+	PERMISSION_GET_SCHEDULED_REPORT_BY_ID              = 26002
+	PERMISSION_CREATE_SCHEDULED_REPORT                 = 26003
+	PERMISSION_UPDATE_SCHEDULED_REPORT                 = 26004
+	PERMISSION_DELETE_SCHEDULED_REPORT                 = 26005
+	PERMISSION_RUN_SCHEDULED_REPORT                    = 26006
+	PERMISSION_GET_REPORT_RUNS                         = 26007
+	PERMISSION_DOWNLOAD_REPORT_ARTIFACT                = 26008
+	PERMISSION_GET_SECURITY_EVENTS                     = 27001
+	PERMISSION_GET_API_KEYS                            = 28001
+	PERMISSION_CREATE_API_KEY                          = 28002
+	PERMISSION_ROTATE_API_KEY                          = 28003
+	PERMISSION_REVOKE_API_KEY                          = 28004
+	PERMISSION_VIEW_ITEM_COSTS                         = 29001
+	PERMISSION_VIEW_CUSTOMER_CONTACT                   = 29002
+	PERMISSION_VIEW_INVOICE_ENTERPRISE_DATA            = 29003
 )
@@ -3,11 +3,13 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -32,15 +34,15 @@ func NewAdditionalExpenseController(service *services.AdditionalExpenseService,
 // @Produce      json
 // @Param        id   path      string                   true  "ID of the additional expense"
 // @Success      200  {object}  models.AdditionalExpense "The additional expense record"
-// @Failure      401  {object}  models.ErrorResponse      "Unauthorized or permission denied"
-// @Failure      404  {object}  models.ErrorResponse      "Additional expense not found"
-// @Failure      500  {object}  models.ErrorResponse      "Internal server error (log registration or DB error)"
+// @Failure      401  {object}  models.ProblemDetails      "Unauthorized or permission denied"
+// @Failure      404  {object}  models.ProblemDetails      "Additional expense not found"
+// @Failure      500  {object}  models.ProblemDetails      "Internal server error (log registration or DB error)"
 // @Router       /additional-expenses/{id} [get]
 func (aec *AdditionalExpenseController) GetAdditionalExpenseByID(c *gin.Context) {
 	idParam := c.Param("id")
 
-	if aec.Log.RegisterLog(c, "Attempting to retrieve AdditionalExpense with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := aec.Log.RegisterLog(c, "Attempting to retrieve AdditionalExpense with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -50,16 +52,16 @@ func (aec *AdditionalExpenseController) GetAdditionalExpenseByID(c *gin.Context)
 		return
 	}
 
-	additionalExpense, err := aec.Service.GetAdditionalExpenseByID(idParam)
+	additionalExpense, err := aec.Service.GetAdditionalExpenseByID(c.Request.Context(), idParam)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "Error retrieving AdditionalExpense with ID "+idParam+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Additional Expense"})
+		_ = c.Error(err)
 		return
 	}
 
 	if additionalExpense == nil {
 		_ = aec.Log.RegisterLog(c, "AdditionalExpense with ID "+idParam+" not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Additional Expense not found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -71,37 +73,49 @@ func (aec *AdditionalExpenseController) GetAdditionalExpenseByID(c *gin.Context)
 // GetAllAdditionalExpenses godoc
 // @Summary      Get all additional expenses
 // @Description  Retrieves all additional expense records. Requires permission to view all additional expenses.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         additional-expenses
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   models.AdditionalExpense   "A list of all additional expenses"
-// @Failure      401  {object}  models.ErrorResponse       "Unauthorized or permission denied"
-// @Failure      500  {object}  models.ErrorResponse       "Error retrieving additional expenses"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}   dtos.PageDTO{data=[]models.AdditionalExpense}   "A list of all additional expenses"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      401  {object}  models.ProblemDetails       "Unauthorized or permission denied"
+// @Failure      500  {object}  models.ProblemDetails       "Error retrieving additional expenses"
 // @Security     ApiKeyAuth
 // @Router       /additional-expenses [get]
 func (aec *AdditionalExpenseController) GetAllAdditionalExpenses(c *gin.Context) {
-	if aec.Log.RegisterLog(c, "Attempting to retrieve all AdditionalExpenses") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := aec.Log.RegisterLog(c, "Attempting to retrieve all AdditionalExpenses"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	permissionId := config.PERMISSION_GET_ALL_ADDITIONAL_EXPENSE
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for GetAllAdditionalExpenses")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
-	additionalExpenses, err := aec.Service.GetAllAdditionalExpenses()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = aec.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	additionalExpenses, page, err := aec.Service.GetAllAdditionalExpenses(c.Request.Context(), query)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "Error retrieving all AdditionalExpenses: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving additional expenses"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = aec.Log.RegisterLog(c, "Successfully retrieved all AdditionalExpenses")
 
-	c.JSON(http.StatusOK, additionalExpenses)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(additionalExpenses, page))
 }
 
 // CreateAdditionalExpense godoc
@@ -112,28 +126,28 @@ func (aec *AdditionalExpenseController) GetAllAdditionalExpenses(c *gin.Context)
 // @Produce      json
 // @Param        expense  body      dtos.UpdateAdditionalExpenseDTO  true  "Additional Expense DTO"
 // @Success      201      {object}  models.AdditionalExpense         "The created additional expense"
-// @Failure      400      {object}  models.ErrorResponse             "Invalid JSON format"
-// @Failure      401      {object}  models.ErrorResponse             "Unauthorized or permission denied"
-// @Failure      500      {object}  models.ErrorResponse             "Error creating additional expense"
+// @Failure      400      {object}  models.ProblemDetails             "Invalid JSON format"
+// @Failure      422      {object}  models.ProblemDetails             "Validation failed"
+// @Failure      401      {object}  models.ProblemDetails             "Unauthorized or permission denied"
+// @Failure      500      {object}  models.ProblemDetails             "Error creating additional expense"
 // @Security     ApiKeyAuth
 // @Router       /additional-expenses [post]
 func (aec *AdditionalExpenseController) CreateAdditionalExpense(c *gin.Context) {
-	if aec.Log.RegisterLog(c, "Attempting to create a new AdditionalExpense") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := aec.Log.RegisterLog(c, "Attempting to create a new AdditionalExpense"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	permissionId := config.PERMISSION_CREATE_ADDITIONAL_EXPENSE
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for CreateAdditionalExpense")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
 	var dto dtos.UpdateAdditionalExpenseDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = aec.Log.RegisterLog(c, "Invalid JSON format for CreateAdditionalExpense: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
@@ -142,12 +156,13 @@ func (aec *AdditionalExpenseController) CreateAdditionalExpense(c *gin.Context)
 		ItemID:      dto.ItemID,
 		Expense:     dto.Expense,
 		Description: dto.Description,
+		Units:       dto.Units,
 	}
 
-	createdExpense, err := aec.Service.CreateAdditionalExpense(newExpense)
+	createdExpense, err := aec.Service.CreateAdditionalExpense(c.Request.Context(), newExpense)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "Error creating AdditionalExpense: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating additional expense"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -164,36 +179,35 @@ func (aec *AdditionalExpenseController) CreateAdditionalExpense(c *gin.Context)
 // @Produce      json
 // @Param        id    path      string                  true  "Additional Expense ID"
 // @Success      200   {object}  models.MessageResponse       "Message indicating successful deletion"
-// @Failure      400   {object}  models.ErrorResponse    "Invalid ID format or request"
-// @Failure      401   {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404   {object}  models.ErrorResponse    "Additional expense not found"
-// @Failure      500   {object}  models.ErrorResponse    "Error deleting additional expense"
+// @Failure      400   {object}  models.ProblemDetails    "Invalid ID format or request"
+// @Failure      401   {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      404   {object}  models.ProblemDetails    "Additional expense not found"
+// @Failure      500   {object}  models.ProblemDetails    "Error deleting additional expense"
 // @Security     ApiKeyAuth
 // @Router       /additional-expenses/{id} [delete]
 func (aec *AdditionalExpenseController) DeleteAdditionalExpense(c *gin.Context) {
 	id := c.Param("id")
 
-	if aec.Log.RegisterLog(c, "Attempting to delete AdditionalExpense with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := aec.Log.RegisterLog(c, "Attempting to delete AdditionalExpense with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	permissionId := config.PERMISSION_DELETE_ADDITIONAL_EXPENSE
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for DeleteAdditionalExpense with ID: "+id)
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
-	err := aec.Service.DeleteAdditionalExpense(id)
+	err := aec.Service.DeleteAdditionalExpense(c.Request.Context(), id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			_ = aec.Log.RegisterLog(c, "AdditionalExpense with ID "+id+" not found")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Additional Expense not found"})
+			_ = c.Error(err)
 			return
 		}
 		_ = aec.Log.RegisterLog(c, "Error deleting AdditionalExpense with ID "+id+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting Additional Expense"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -211,38 +225,38 @@ func (aec *AdditionalExpenseController) DeleteAdditionalExpense(c *gin.Context)
 // @Param        id    path      string                            true  "Additional Expense ID"
 // @Param        body  body      dtos.UpdateAdditionalExpenseDTO   true  "Updated Additional Expense details"
 // @Success      200   {object}  models.AdditionalExpense           "The updated additional expense"
-// @Failure      400   {object}  models.ErrorResponse               "Invalid request or JSON format"
-// @Failure      401   {object}  models.ErrorResponse               "Unauthorized or permission denied"
-// @Failure      404   {object}  models.ErrorResponse               "Additional expense not found"
-// @Failure      500   {object}  models.ErrorResponse               "Internal server error"
+// @Failure      400   {object}  models.ProblemDetails               "Invalid request or JSON format"
+// @Failure      422   {object}  models.ProblemDetails               "Validation failed"
+// @Failure      401   {object}  models.ProblemDetails               "Unauthorized or permission denied"
+// @Failure      404   {object}  models.ProblemDetails               "Additional expense not found"
+// @Failure      500   {object}  models.ProblemDetails               "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /additional-expenses/{id} [put]
 func (aec *AdditionalExpenseController) UpdateAdditionalExpense(c *gin.Context) {
 	id := c.Param("id")
 
-	if aec.Log.RegisterLog(c, "Attempting to update AdditionalExpense with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := aec.Log.RegisterLog(c, "Attempting to update AdditionalExpense with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	permissionId := config.PERMISSION_UPDATE_ADDITIONAL_EXPENSE
 	if !aec.Auth.CheckPermission(c, permissionId) {
 		_ = aec.Log.RegisterLog(c, "Access denied for UpdateAdditionalExpense with ID: "+id)
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
 	var dto dtos.UpdateAdditionalExpenseDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = aec.Log.RegisterLog(c, "Invalid JSON format for UpdateAdditionalExpense with ID: "+id)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	expense, err := aec.Service.GetAdditionalExpenseByID(id)
+	expense, err := aec.Service.GetAdditionalExpenseByID(c.Request.Context(), id)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "AdditionalExpense with ID "+id+" not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "AdditionalExpense not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -250,11 +264,12 @@ func (aec *AdditionalExpenseController) UpdateAdditionalExpense(c *gin.Context)
 	expense.ItemID = dto.ItemID
 	expense.Expense = dto.Expense
 	expense.Description = dto.Description
+	expense.Units = dto.Units
 
-	updatedExpense, err := aec.Service.UpdateAdditionalExpense(expense)
+	updatedExpense, err := aec.Service.UpdateAdditionalExpense(c.Request.Context(), expense)
 	if err != nil {
 		_ = aec.Log.RegisterLog(c, "Error updating AdditionalExpense with ID "+id+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating AdditionalExpense"})
+		_ = c.Error(err)
 		return
 	}
 
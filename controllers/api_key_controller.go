@@ -0,0 +1,226 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/middleware"
+	"totesbackend/models"
+	"totesbackend/services"
+	"totesbackend/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyController struct {
+	Service *services.APIKeyService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewAPIKeyController(service *services.APIKeyService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *APIKeyController {
+	return &APIKeyController{Service: service, Auth: auth, Log: log}
+}
+
+// GetAPIKeys godoc
+// @Summary      Get API keys
+// @Description  Retrieves the API keys of machine clients and external sale reporters, including expired, revoked and rotated ones. Newest first by default.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
+// @Tags         api-keys
+// @Produce      json
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}  dtos.PageDTO{data=[]dtos.APIKeyDTO}  "List of API keys"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving API keys"
+// @Security     ApiKeyAuth
+// @Router       /api-keys [get]
+func (akc *APIKeyController) GetAPIKeys(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_API_KEYS
+
+	if err := akc.Log.RegisterLog(c, "Attempting to retrieve API keys"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !akc.Auth.CheckPermission(c, permissionId) {
+		_ = akc.Log.RegisterLog(c, "Access denied for GetAPIKeys")
+		return
+	}
+
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = akc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	keys, page, err := akc.Service.GetAPIKeys(c.Request.Context(), query)
+	if err != nil {
+		_ = akc.Log.RegisterLog(c, "Error retrieving API keys: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	now := time.Now()
+	keyDTOs := make([]dtos.APIKeyDTO, 0, len(keys))
+	for i := range keys {
+		keyDTOs = append(keyDTOs, apiKeyDTO(&keys[i], now))
+	}
+
+	_ = akc.Log.RegisterLog(c, "Successfully retrieved API keys")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(keyDTOs, page))
+}
+
+// CreateAPIKey godoc
+// @Summary      Create an API key
+// @Description  Creates an API key for a machine client, or for an external sale reporter when reporter_tax_id is set. The key is sent in the X-API-Key header.
+// @Description  The key has only the given permissions, and the caller must hold all of them. expires_at is optional and limited by the maximum lifetime.
+// @Description  The full key is returned only in this response.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.CreateAPIKeyDTO  true  "Name, owner, permissions and expiration of the key"
+// @Success      201   {object}  dtos.IssuedAPIKeyDTO  "Created API key"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      403   {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422   {object}  models.ProblemDetails  "Validation failed or invalid expiration"
+// @Failure      500   {object}  models.ProblemDetails  "Error creating the API key"
+// @Security     ApiKeyAuth
+// @Router       /api-keys [post]
+func (akc *APIKeyController) CreateAPIKey(c *gin.Context) {
+	permissionId := config.PERMISSION_CREATE_API_KEY
+
+	if err := akc.Log.RegisterLog(c, "Attempting to create an API key"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !akc.Auth.CheckPermission(c, permissionId) {
+		_ = akc.Log.RegisterLog(c, "Access denied for CreateAPIKey")
+		return
+	}
+
+	var dto dtos.CreateAPIKeyDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = akc.Log.RegisterLog(c, "Invalid request body for CreateAPIKey")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	key, rawKey, err := akc.Service.CreateAPIKey(c.Request.Context(), &dto, middleware.Caller(c))
+	if err != nil {
+		_ = akc.Log.RegisterLog(c, "Error creating API key for "+dto.Principal+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = akc.Log.RegisterLog(c, "Successfully created API key "+key.Prefix+" for "+key.Principal)
+	c.JSON(http.StatusCreated, dtos.IssuedAPIKeyDTO{Key: rawKey, APIKey: apiKeyDTO(key, time.Now())})
+}
+
+// RotateAPIKey godoc
+// @Summary      Rotate an API key
+// @Description  Creates a new key with the same owner and permissions. The previous key keeps working during the configured grace period and then expires.
+// @Description  The full new key is returned only in this response.
+// @Tags         api-keys
+// @Produce      json
+// @Param        id   path      string  true  "API key ID"
+// @Success      201  {object}  dtos.IssuedAPIKeyDTO  "New API key"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "API key not found"
+// @Failure      409  {object}  models.ProblemDetails  "API key is revoked, expired or already rotated"
+// @Failure      500  {object}  models.ProblemDetails  "Error rotating the API key"
+// @Security     ApiKeyAuth
+// @Router       /api-keys/{id}/rotate [post]
+func (akc *APIKeyController) RotateAPIKey(c *gin.Context) {
+	permissionId := config.PERMISSION_ROTATE_API_KEY
+	id := c.Param("id")
+
+	if err := akc.Log.RegisterLog(c, "Attempting to rotate API key with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !akc.Auth.CheckPermission(c, permissionId) {
+		_ = akc.Log.RegisterLog(c, "Access denied for RotateAPIKey")
+		return
+	}
+
+	key, rawKey, err := akc.Service.RotateAPIKey(c.Request.Context(), id, middleware.Caller(c))
+	if err != nil {
+		_ = akc.Log.RegisterLog(c, "Error rotating API key with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = akc.Log.RegisterLog(c, "Successfully rotated API key with ID "+id+" to "+key.Prefix)
+	c.JSON(http.StatusCreated, dtos.IssuedAPIKeyDTO{Key: rawKey, APIKey: apiKeyDTO(key, time.Now())})
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  Revokes an API key immediately. A key created by rotating it is not affected.
+// @Tags         api-keys
+// @Produce      json
+// @Param        id   path      string  true  "API key ID"
+// @Success      200  {object}  models.MessageResponse  "API key revoked"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "API key not found"
+// @Failure      409  {object}  models.ProblemDetails  "API key already revoked"
+// @Failure      500  {object}  models.ProblemDetails  "Error revoking the API key"
+// @Security     ApiKeyAuth
+// @Router       /api-keys/{id} [delete]
+func (akc *APIKeyController) RevokeAPIKey(c *gin.Context) {
+	permissionId := config.PERMISSION_REVOKE_API_KEY
+	id := c.Param("id")
+
+	if err := akc.Log.RegisterLog(c, "Attempting to revoke API key with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !akc.Auth.CheckPermission(c, permissionId) {
+		_ = akc.Log.RegisterLog(c, "Access denied for RevokeAPIKey")
+		return
+	}
+
+	if err := akc.Service.RevokeAPIKey(c.Request.Context(), id, middleware.Caller(c)); err != nil {
+		_ = akc.Log.RegisterLog(c, "Error revoking API key with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = akc.Log.RegisterLog(c, "Successfully revoked API key with ID: "+id)
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+func apiKeyDTO(key *models.APIKey, now time.Time) dtos.APIKeyDTO {
+	permissions := make([]int, 0, len(key.Permissions))
+	for _, permission := range key.Permissions {
+		permissions = append(permissions, int(permission.ID))
+	}
+	return dtos.APIKeyDTO{
+		ID:            key.ID,
+		Name:          key.Name,
+		Prefix:        key.Prefix,
+		Principal:     key.Principal,
+		ReporterTaxID: key.ReporterTaxID,
+		Permissions:   permissions,
+		ExpiresAt:     key.ExpiresAt,
+		Active:        key.Active(now),
+		LastUsedAt:    key.LastUsedAt,
+		LastUsedIP:    key.LastUsedIP,
+		RevokedAt:     key.RevokedAt,
+		ReplacedByID:  key.ReplacedByID,
+		CreatedBy:     key.CreatedBy,
+		CreatedAt:     key.CreatedAt,
+	}
+}
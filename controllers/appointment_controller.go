@@ -1,18 +1,19 @@
 package controllers
 
 import (
-	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type AppointmentController struct {
@@ -34,15 +35,15 @@ func NewAppointmentController(service *services.AppointmentService, auth *utilit
 // @Produce      json
 // @Param        id   path      int  true  "Appointment ID"
 // @Success      200  {object}  models.Appointment           "The appointment object"
-// @Failure      400  {object}  models.ErrorResponse         "Invalid appointment ID"
-// @Failure      401  {object}  models.ErrorResponse         "Unauthorized or permission denied"
-// @Failure      404  {object}  models.ErrorResponse         "Appointment not found"
-// @Failure      500  {object}  models.ErrorResponse         "Internal server error"
+// @Failure      400  {object}  models.ProblemDetails         "Invalid appointment ID"
+// @Failure      401  {object}  models.ProblemDetails         "Unauthorized or permission denied"
+// @Failure      404  {object}  models.ProblemDetails         "Appointment not found"
+// @Failure      500  {object}  models.ProblemDetails         "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /appointments/{id} [get]
 func (ac *AppointmentController) GetAppointmentByID(c *gin.Context) {
-	if ac.Log.RegisterLog(c, "Attempting to get appointment by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to get appointment by ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -55,35 +56,41 @@ func (ac *AppointmentController) GetAppointmentByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid appointment ID: "+c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	appointment, err := ac.Service.GetAppointmentByID(id)
+	appointment, err := ac.Service.GetAppointmentByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Appointment not found for ID: "+strconv.Itoa(id))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointment retrieved successfully for ID: "+strconv.Itoa(id))
-	c.JSON(http.StatusOK, appointment)
+	ac.Auth.JSON(c, http.StatusOK, appointment)
 }
 
 // GetAllAppointments godoc
 // @Summary      Get all appointments
 // @Description  Retrieves a list of all appointments. Requires proper permission.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         appointments
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   models.Appointment       "List of all appointments"
-// @Failure      401  {object}  models.ErrorResponse     "Unauthorized or permission denied"
-// @Failure      500  {object}  models.ErrorResponse     "Error retrieving appointments or logging"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}   dtos.PageDTO{data=[]models.Appointment}       "List of all appointments"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      401  {object}  models.ProblemDetails     "Unauthorized or permission denied"
+// @Failure      500  {object}  models.ProblemDetails     "Error retrieving appointments or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments [get]
 func (ac *AppointmentController) GetAllAppointments(c *gin.Context) {
-	if ac.Log.RegisterLog(c, "Attempting to retrieve all appointments") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to retrieve all appointments"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -93,15 +100,22 @@ func (ac *AppointmentController) GetAllAppointments(c *gin.Context) {
 		return
 	}
 
-	appointments, err := ac.Service.GetAllAppointments()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = ac.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	appointments, page, err := ac.Service.GetAllAppointments(c.Request.Context(), query)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "All appointments retrieved successfully")
-	c.JSON(http.StatusOK, appointments)
+	ac.Auth.JSON(c, http.StatusOK, dtos.NewPageDTO(appointments, page))
 }
 
 // SearchAppointmentsByID godoc
@@ -112,15 +126,15 @@ func (ac *AppointmentController) GetAllAppointments(c *gin.Context) {
 // @Produce      json
 // @Param        id     query     string  true  "Appointment ID to search"
 // @Success      200    {array}   models.Appointment
-// @Failure      401    {object} models.ErrorResponse  "Unauthorized or permission denied"
-// @Failure      404    {object}  models.ErrorResponse   "No appointments found"
-// @Failure      500    {object}  models.ErrorResponse  "Error retrieving appointments or logging"
+// @Failure      401    {object} models.ProblemDetails  "Unauthorized or permission denied"
+// @Failure      404    {object}  models.ProblemDetails   "No appointments found"
+// @Failure      500    {object}  models.ProblemDetails  "Error retrieving appointments or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/searchByID [get]
 func (ac *AppointmentController) SearchAppointmentsByID(c *gin.Context) {
 
-	if ac.Log.RegisterLog(c, "Attempting to search appointments by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to search appointments by ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -133,21 +147,21 @@ func (ac *AppointmentController) SearchAppointmentsByID(c *gin.Context) {
 	query := c.Query("id")
 	fmt.Println("Searching appointments by ID with:", query)
 
-	appointments, err := ac.Service.SearchAppointmentsByID(query)
+	appointments, err := ac.Service.SearchAppointmentsByID(c.Request.Context(), query)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(appointments) == 0 {
 		_ = ac.Log.RegisterLog(c, "No appointments found for given ID")
-		c.JSON(http.StatusNotFound, gin.H{"message": "No appointments found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointments found by ID successfully")
-	c.JSON(http.StatusOK, appointments)
+	ac.Auth.JSON(c, http.StatusOK, appointments)
 }
 
 // SearchAppointmentsByCustomerID godoc
@@ -158,15 +172,15 @@ func (ac *AppointmentController) SearchAppointmentsByID(c *gin.Context) {
 // @Produce      json
 // @Param        id     query     string  true  "Customer ID to search appointments"
 // @Success      200    {array}   models.Appointment   "List of appointments found"
-// @Failure      401    {object} models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      404    {object}  models.ErrorResponse   "No appointments found for the given customer ID"
-// @Failure      500    {object}  models.ErrorResponse  "Error retrieving appointments or logging"
+// @Failure      401    {object} models.ProblemDetails   "Unauthorized or permission denied"
+// @Failure      404    {object}  models.ProblemDetails   "No appointments found for the given customer ID"
+// @Failure      500    {object}  models.ProblemDetails  "Error retrieving appointments or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/searchByCustomerID [get]
 func (ac *AppointmentController) SearchAppointmentsByCustomerID(c *gin.Context) {
 
-	if ac.Log.RegisterLog(c, "Attempting to search appointments by customer ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to search appointments by customer ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -179,21 +193,21 @@ func (ac *AppointmentController) SearchAppointmentsByCustomerID(c *gin.Context)
 	query := c.Query("id")
 	fmt.Println("Searching appointments by Customer ID with:", query)
 
-	appointments, err := ac.Service.SearchAppointmentsByCustomerID(query)
+	appointments, err := ac.Service.SearchAppointmentsByCustomerID(c.Request.Context(), query)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments by customer ID")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(appointments) == 0 {
 		_ = ac.Log.RegisterLog(c, "No appointments found for given customer ID")
-		c.JSON(http.StatusNotFound, gin.H{"message": "No appointments found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointments found by customer ID successfully")
-	c.JSON(http.StatusOK, appointments)
+	ac.Auth.JSON(c, http.StatusOK, appointments)
 }
 
 // SearchAppointmentsByState godoc
@@ -204,15 +218,15 @@ func (ac *AppointmentController) SearchAppointmentsByCustomerID(c *gin.Context)
 // @Produce      json
 // @Param        state   query     bool    true  "State of the appointment (true for confirmed, false for pending)"
 // @Success      200     {array}   models.Appointment   "List of appointments found based on state"
-// @Failure      400     {object}  models.ErrorResponse   "Invalid state value provided"
-// @Failure      401     {object}  models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      500     {object}  models.ErrorResponse   "Error retrieving appointments or logging"
+// @Failure      401     {object}  models.ProblemDetails   "Unauthorized or permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500     {object}  models.ProblemDetails   "Error retrieving appointments or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/searchByState [get]
 func (ac *AppointmentController) SearchAppointmentsByState(c *gin.Context) {
 
-	if ac.Log.RegisterLog(c, "Attempting to search appointments by state") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to search appointments by state"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -225,19 +239,19 @@ func (ac *AppointmentController) SearchAppointmentsByState(c *gin.Context) {
 	state, err := strconv.ParseBool(c.Query("state"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid state value provided for appointment search")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state value"})
+		_ = c.Error(validation.ParamError("state", "boolean", "", "must be true or false"))
 		return
 	}
 
-	appointments, err := ac.Service.SearchAppointmentsByState(state)
+	appointments, err := ac.Service.SearchAppointmentsByState(c.Request.Context(), state)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments by state")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointments retrieved successfully by state")
-	c.JSON(http.StatusOK, appointments)
+	ac.Auth.JSON(c, http.StatusOK, appointments)
 }
 
 // GetAppointmentsByCustomerID godoc
@@ -248,14 +262,14 @@ func (ac *AppointmentController) SearchAppointmentsByState(c *gin.Context) {
 // @Produce      json
 // @Param        customerID  path      int                          true  "ID of the customer"
 // @Success      200         {array}   models.Appointment           "List of appointments"
-// @Failure      400         {object}  models.ErrorResponse       "Invalid customer ID"
-// @Failure      401         {object} models.ErrorResponse        "Unauthorized or permission denied"
-// @Failure      500         {object}  models.ErrorResponse       "Error retrieving appointments"
+// @Failure      400         {object}  models.ProblemDetails       "Invalid customer ID"
+// @Failure      401         {object} models.ProblemDetails        "Unauthorized or permission denied"
+// @Failure      500         {object}  models.ProblemDetails       "Error retrieving appointments"
 // @Router       /appointments/customer/{customerID} [get]
 func (ac *AppointmentController) GetAppointmentsByCustomerID(c *gin.Context) {
 
-	if ac.Log.RegisterLog(c, "Attempting to retrieve appointments by customer ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to retrieve appointments by customer ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -268,19 +282,19 @@ func (ac *AppointmentController) GetAppointmentsByCustomerID(c *gin.Context) {
 	customerID, err := strconv.Atoi(c.Param("customerID"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid customer ID provided")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("customerID", c.Param("customerID")))
 		return
 	}
 
-	appointments, err := ac.Service.GetAppointmentsByCustomerID(customerID)
+	appointments, err := ac.Service.GetAppointmentsByCustomerID(c.Request.Context(), customerID)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Error retrieving appointments by customer ID")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving appointments"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointments retrieved successfully by customer ID")
-	c.JSON(http.StatusOK, appointments)
+	ac.Auth.JSON(c, http.StatusOK, appointments)
 }
 
 // CreateAppointment godoc
@@ -291,45 +305,40 @@ func (ac *AppointmentController) GetAppointmentsByCustomerID(c *gin.Context) {
 // @Produce      json
 // @Param        appointment  body      models.Appointment  true  "Appointment data to create"
 // @Success      201          {object}  models.Appointment  "Appointment successfully created"
-// @Failure      400          {object}  models.ErrorResponse   "Invalid JSON format or appointment limit reached"
-// @Failure      403          {object}  models.ErrorResponse   "Forbidden, no permission to create appointments"
-// @Failure      500          {object}  models.ErrorResponse   "Error creating appointment or logging"
+// @Failure      400          {object}  models.ProblemDetails   "Invalid JSON format or appointment limit reached"
+// @Failure      422          {object}  models.ProblemDetails   "Validation failed"
+// @Failure      403          {object}  models.ProblemDetails   "Forbidden, no permission to create appointments"
+// @Failure      500          {object}  models.ProblemDetails   "Error creating appointment or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments [post]
 func (ac *AppointmentController) CreateAppointment(c *gin.Context) {
-	if ac.Log.RegisterLog(c, "Attempting to create appointment") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to create appointment"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	permissionId := config.PERMISSION_CREATE_APPOINTMENT
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for CreateAppointment")
-		c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permisos para crear citas"})
 		return
 	}
 
 	var appointment models.Appointment
 	if err := c.ShouldBindJSON(&appointment); err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid JSON format when creating appointment")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Formato JSON inválido"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	createdAppointment, err := ac.Service.CreateAppointment(appointment)
+	createdAppointment, err := ac.Service.CreateAppointment(c.Request.Context(), appointment)
 	if err != nil {
-		if err.Error() == "ya existen 3 citas agendadas para esta fecha y hora" {
-			_ = ac.Log.RegisterLog(c, "limite de citas alcanzado :v")
-			c.JSON(http.StatusBadRequest, gin.H{"error": "no se puede crear la cita. Ya hay 3 citas agendadas para esta fecha y hora."})
-		} else {
-			_ = ac.Log.RegisterLog(c, "Error creando cita")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear la cita"})
-		}
+		_ = ac.Log.RegisterLog(c, "Error creando cita: "+err.Error())
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Cita creada exitosamente")
-	c.JSON(http.StatusCreated, createdAppointment)
+	ac.Auth.JSON(c, http.StatusCreated, createdAppointment)
 }
 
 // UpdateAppointment godoc
@@ -341,16 +350,17 @@ func (ac *AppointmentController) CreateAppointment(c *gin.Context) {
 // @Param        id          path      int                 true  "Appointment ID to update"
 // @Param        appointment body      models.Appointment   true  "Appointment data to update"
 // @Success      200         {object}  models.Appointment   "Appointment successfully updated"
-// @Failure      400         {object}  models.ErrorResponse   "Invalid appointment ID or JSON format"
-// @Failure      403         {object} models.ErrorResponse   "Forbidden, no permission to update appointments"
-// @Failure      404         {object} models.ErrorResponse   "Appointment not found for update"
-// @Failure      500         {object}  models.ErrorResponse   "Error updating appointment or logging"
+// @Failure      400         {object}  models.ProblemDetails   "Invalid appointment ID or JSON format"
+// @Failure      422         {object}  models.ProblemDetails   "Validation failed"
+// @Failure      403         {object} models.ProblemDetails   "Forbidden, no permission to update appointments"
+// @Failure      404         {object} models.ProblemDetails   "Appointment not found for update"
+// @Failure      500         {object}  models.ProblemDetails   "Error updating appointment or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/{id} [put]
 func (ac *AppointmentController) UpdateAppointment(c *gin.Context) {
 
-	if ac.Log.RegisterLog(c, "Attempting to update appointment") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to update appointment"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -364,33 +374,28 @@ func (ac *AppointmentController) UpdateAppointment(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid appointment ID format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
 	var appointment models.Appointment
 	if err := c.ShouldBindJSON(&appointment); err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid JSON format on update appointment")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
 	appointment.ID = id
 
-	err = ac.Service.UpdateAppointment(&appointment)
+	err = ac.Service.UpdateAppointment(c.Request.Context(), &appointment)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			_ = ac.Log.RegisterLog(c, "Appointment not found for update")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
-			return
-		}
 		_ = ac.Log.RegisterLog(c, "Error updating appointment")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating appointment"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ac.Log.RegisterLog(c, "Appointment updated successfully")
-	c.JSON(http.StatusOK, appointment)
+	ac.Auth.JSON(c, http.StatusOK, appointment)
 }
 
 // GetAppointmentByCustomerIDAndDate godoc
@@ -402,16 +407,16 @@ func (ac *AppointmentController) UpdateAppointment(c *gin.Context) {
 // @Param        customerId  query     int    true  "Customer ID"
 // @Param        dateTime    query     string true  "Appointment date and time (format: YYYY-MM-DD HH:MM:SS)"
 // @Success      200         {object}  models.Appointment  "Appointment successfully retrieved"
-// @Failure      400         {object}  models.ErrorResponse   "Invalid customer ID or date format"
-// @Failure      401         {object}  models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      404         {object} models.ErrorResponse   "Appointment not found for the given customer ID and date"
-// @Failure      500         {object}  models.ErrorResponse  "Error retrieving appointment or logging"
+// @Failure      401         {object}  models.ProblemDetails   "Unauthorized or permission denied"
+// @Failure      404         {object} models.ProblemDetails   "Appointment not found for the given customer ID and date"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500         {object}  models.ProblemDetails  "Error retrieving appointment or logging"
 // @Security     ApiKeyAuth
 // @Router       /appointments/byCustomerIdAndDate [get]
 func (ac *AppointmentController) GetAppointmentByCustomerIDAndDate(c *gin.Context) {
 
-	if ac.Log.RegisterLog(c, "Attempting to get appointment by customer ID and date") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to get appointment by customer ID and date"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -425,21 +430,21 @@ func (ac *AppointmentController) GetAppointmentByCustomerIDAndDate(c *gin.Contex
 	customerID, err := strconv.Atoi(c.Query("customerId"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid customer ID format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		_ = c.Error(validation.ParamError("customerId", "numeric", "", "must be an integer"))
 		return
 	}
 
 	dateTime, err := time.Parse("2006-01-02 15:04:05", c.Query("dateTime"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid date format for GetAppointmentByCustomerIDAndDate")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use 'YYYY-MM-DD HH:MM:SS'"})
+		_ = c.Error(validation.ParamError("dateTime", "datetime", "2006-01-02 15:04:05", "must be a date in YYYY-MM-DD HH:MM:SS format"))
 		return
 	}
 
-	appointment, err := ac.Service.GetAppointmentByCustomerIDAndDate(customerID, dateTime)
+	appointment, err := ac.Service.GetAppointmentByCustomerIDAndDate(c.Request.Context(), customerID, dateTime)
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Appointment not found for given customer ID and date")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -454,7 +459,7 @@ func (ac *AppointmentController) GetAppointmentByCustomerIDAndDate(c *gin.Contex
 		"customerID":   appointment.CustomerID,
 	}
 
-	c.JSON(http.StatusOK, response)
+	ac.Auth.JSON(c, http.StatusOK, response)
 }
 
 // DeleteAppointmentByID godoc
@@ -465,41 +470,35 @@ func (ac *AppointmentController) GetAppointmentByCustomerIDAndDate(c *gin.Contex
 // @Produce      json
 // @Param        id  path     int  true  "Appointment ID"
 // @Success      200 {object} models.MessageResponse "Appointment deleted successfully"
-// @Failure      400 {object} models.ErrorResponse  "Invalid appointment ID format"
-// @Failure      401 {object} models.ErrorResponse  "Unauthorized or permission denied"
-// @Failure      404 {object} models.ErrorResponse  "Appointment not found for the given ID"
-// @Failure      500 {object} models.ErrorResponse  "Error deleting the appointment"
+// @Failure      400 {object} models.ProblemDetails  "Invalid appointment ID format"
+// @Failure      401 {object} models.ProblemDetails  "Unauthorized or permission denied"
+// @Failure      404 {object} models.ProblemDetails  "Appointment not found for the given ID"
+// @Failure      500 {object} models.ProblemDetails  "Error deleting the appointment"
 // @Security     ApiKeyAuth
 // @Router       /appointments/deleteAppointment/{id} [delete]
 func (ac *AppointmentController) DeleteAppointmentByID(c *gin.Context) {
-	if ac.Log.RegisterLog(c, "Attempting to delete appointment") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ac.Log.RegisterLog(c, "Attempting to delete appointment"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	permissionId := config.PERMISSION_DELETE_APPOINTMENT
 	if !ac.Auth.CheckPermission(c, permissionId) {
 		_ = ac.Log.RegisterLog(c, "Access denied for DeleteAppointmentByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "No tienes permisos para eliminar citas"})
 		return
 	}
 
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = ac.Log.RegisterLog(c, "Invalid appointment ID: "+c.Param("id"))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appointment ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	err = ac.Service.DeleteAppointmentByID(id)
+	err = ac.Service.DeleteAppointmentByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			_ = ac.Log.RegisterLog(c, "Appointment not found for ID: "+strconv.Itoa(id))
-			c.JSON(http.StatusNotFound, gin.H{"error": "Appointment not found"})
-		} else {
-			_ = ac.Log.RegisterLog(c, "Error deleting appointment")
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		_ = ac.Log.RegisterLog(c, "Error deleting appointment")
+		_ = c.Error(err)
 		return
 	}
 
@@ -516,9 +515,9 @@ func (ac *AppointmentController) DeleteAppointmentByID(c *gin.Context) {
 // @Produce      json
 // @Param        date  query     string  true  "Date in YYYY-MM-DD format"
 // @Success      200  {array}  models.Appointment  "List of hourly appointment counts"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid date format or missing 'date' parameter"
-// @Failure      401  {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving appointment counts"
+// @Failure      401  {object}  models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving appointment counts"
 // @Security     ApiKeyAuth
 // @Router       /appointments/hourly-count [get]
 func (c *AppointmentController) GetAppointmentsByHourRange(ctx *gin.Context) {
@@ -526,25 +525,24 @@ func (c *AppointmentController) GetAppointmentsByHourRange(ctx *gin.Context) {
 	permissionId := config.PERMISSION_GET_APPOINTMENTS_BY_HOUR
 	if !c.Auth.CheckPermission(ctx, permissionId) {
 		_ = c.Log.RegisterLog(ctx, "Access denied for CreateAppointment")
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "No tienes permisos para crear citas"})
 		return
 	}
 
 	dateParam := ctx.Query("date")
 	if dateParam == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Se requiere el parámetro 'date' en formato YYYY-MM-DD"})
+		_ = ctx.Error(validation.ParamError("date", "required", "", "is required"))
 		return
 	}
 
 	date, err := time.Parse("2006-01-02", dateParam)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Formato de fecha inválido. Usa YYYY-MM-DD"})
+		_ = ctx.Error(validation.ParamError("date", "datetime", "2006-01-02", "must be a date in YYYY-MM-DD format"))
 		return
 	}
 
-	counts, err := c.Service.GetHourlyAppointmentCount(date)
+	counts, err := c.Service.GetHourlyAppointmentCount(ctx.Request.Context(), date)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al contar las citas: " + err.Error()})
+		_ = ctx.Error(err)
 		return
 	}
 
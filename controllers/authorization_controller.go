@@ -6,6 +6,7 @@ import (
 
 	"totesbackend/controllers/utilities"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,27 +29,26 @@ func NewAuthorizationController(service *services.AuthorizationService, log *uti
 // @Param        email       query     string  true  "User's email address"
 // @Param        permission_id  query  string  true  "Permission ID to check"
 // @Success      200        {object}  models.MessageResponse   "Response with the permission status"
-// @Failure      400        {object}  models.ErrorResponse   "Invalid or missing parameters"
-// @Failure      500        {object}  models.ErrorResponse   "Error checking permission"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500        {object}  models.ProblemDetails   "Error checking permission"
 // @Router       /auth/check-permission [get]
 func (ac *AuthorizationController) CheckUserPermission(c *gin.Context) {
 	email := c.Query("email")
 	permissionID := c.Query("permission_id")
-	permissionStr, err := strconv.Atoi(permissionID)
-
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Permission ID"})
+	if email == "" {
+		_ = c.Error(validation.ParamError("email", "required", "", "is required"))
 		return
 	}
 
-	if email == "" || permissionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email and permission_id are required"})
+	permissionStr, err := strconv.Atoi(permissionID)
+	if err != nil {
+		_ = c.Error(validation.ParamError("permission_id", "numeric", "", "must be an integer"))
 		return
 	}
 
-	hasPermission, err := ac.Service.UserHasPermission(email, permissionStr)
+	hasPermission, err := ac.Service.UserHasPermission(c.Request.Context(), email, permissionStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking permission"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -2,11 +2,11 @@ package controllers
 
 import (
 	"net/http"
-	"strconv"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -32,9 +32,10 @@ type SubtotalResponse struct {
 // @Produce      json
 // @Param        items  body      []dtos.BillingItemDTO  true  "List of billing items"
 // @Success      200    {object}  SubtotalResponse       "Calculated subtotal"
-// @Failure      400    {object}  models.ErrorResponse    "Invalid request data"
-// @Failure      401    {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404    {object}  models.ErrorResponse    "Calculation error (e.g., related data not found)"
+// @Failure      400    {object}  models.ProblemDetails    "Invalid request data"
+// @Failure      422    {object}  models.ProblemDetails    "Validation failed"
+// @Failure      401    {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      404    {object}  models.ProblemDetails    "Calculation error (e.g., related data not found)"
 // @Security     ApiKeyAuth
 // @Router       /billing/subtotal [post]
 func (bc *BillingController) CalculateSubtotal(c *gin.Context) {
@@ -46,34 +47,33 @@ func (bc *BillingController) CalculateSubtotal(c *gin.Context) {
 
 	var itemsDTO []dtos.BillingItemDTO
 	if err := c.ShouldBindJSON(&itemsDTO); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	subtotal, err := bc.Service.CalculateSubtotal(itemsDTO)
+	subtotal, err := bc.Service.CalculateSubtotal(c.Request.Context(), itemsDTO)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"subtotal": subtotal})
 }
 
-type TotalResponse struct {
-	Total float64 `json:"total"`
-}
-
 // CalculateTotal godoc
 // @Summary      Calculate total
-// @Description  Calculates the total amount based on billing items, discounts, and tax types. Requires permission.
+// @Description  Calculates the total amount based on billing items, discounts, coupon codes and tax types.
+// @Description  Automatic promotions are applied when the purchase meets their conditions, and every requested
+// @Description  discount that does not apply is returned with the reason it was rejected. Requires permission.
 // @Tags         billing
 // @Accept       json
 // @Produce      json
 // @Param        body  body  dtos.CalculateTotalRequestDTO  true  "Billing total calculation input"
-// @Success      200   {object}  TotalResponse         "Calculated total"
-// @Failure      400   {object}  models.ErrorResponse       "Invalid request data"
-// @Failure      401   {object}  models.ErrorResponse       "Unauthorized or permission denied"
-// @Failure      404   {object}  models.ErrorResponse       "Calculation error (e.g., related data not found)"
+// @Success      200   {object}  dtos.BillingBreakdownDTO   "Calculated total with applied and rejected discounts"
+// @Failure      400   {object}  models.ProblemDetails       "Invalid request data"
+// @Failure      422   {object}  models.ProblemDetails       "Validation failed"
+// @Failure      401   {object}  models.ProblemDetails       "Unauthorized or permission denied"
+// @Failure      404   {object}  models.ProblemDetails       "Calculation error (e.g., related data not found)"
 // @Security     ApiKeyAuth
 // @Router       /billing/total [post]
 func (bc *BillingController) CalculateTotal(c *gin.Context) {
@@ -84,27 +84,16 @@ func (bc *BillingController) CalculateTotal(c *gin.Context) {
 	}
 	var request dtos.CalculateTotalRequestDTO
 
-	// Estructura del request con arrays de enteros
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	discountTypesIdsStr := make([]string, len(request.DiscountTypesIds))
-	for i, id := range request.DiscountTypesIds {
-		discountTypesIdsStr[i] = strconv.Itoa(id)
-	}
-
-	taxTypesIdsStr := make([]string, len(request.TaxTypesIds))
-	for i, id := range request.TaxTypesIds {
-		taxTypesIdsStr[i] = strconv.Itoa(id)
-	}
-
-	total, err := bc.Service.CalculateTotal(discountTypesIdsStr, taxTypesIdsStr, request.ItemsDTO)
+	breakdown, err := bc.Service.CalculateTotal(c.Request.Context(), request)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"total": total})
+	bc.Auth.JSON(c, http.StatusOK, breakdown)
 }
@@ -1,17 +1,17 @@
 package controllers
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type CommentController struct {
@@ -32,17 +32,17 @@ func NewCommentController(service *services.CommentService, auth *utilities.Auth
 // @Produce      json
 // @Param        id   path      int  true  "Comment ID"
 // @Success      200  {object}  dtos.GetCommentDTO       "The retrieved comment"
-// @Failure      400  {object}  models.ErrorResponse     "Invalid comment ID"
-// @Failure      401  {object}  models.ErrorResponse     "Unauthorized or permission denied"
-// @Failure      404  {object}  models.ErrorResponse     "Comment not found"
-// @Failure      500  {object}  models.ErrorResponse     "Error retrieving comment or registering log"
+// @Failure      400  {object}  models.ProblemDetails     "Invalid comment ID"
+// @Failure      401  {object}  models.ProblemDetails     "Unauthorized or permission denied"
+// @Failure      404  {object}  models.ProblemDetails     "Comment not found"
+// @Failure      500  {object}  models.ProblemDetails     "Error retrieving comment or registering log"
 // @Security     ApiKeyAuth
 // @Router       /comments/{id} [get]
 func (cc *CommentController) GetCommentByID(c *gin.Context) {
 	idParam := c.Param("id")
 
-	if cc.Log.RegisterLog(c, "Attempting to retrieve Comment with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to retrieve Comment with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -55,20 +55,20 @@ func (cc *CommentController) GetCommentByID(c *gin.Context) {
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid comment ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	comment, err := cc.Service.GetCommentByID(id)
+	comment, err := cc.Service.GetCommentByID(c.Request.Context(), id)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving Comment with ID "+idParam+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving comment"})
+		_ = c.Error(err)
 		return
 	}
 
 	if comment == nil {
 		_ = cc.Log.RegisterLog(c, "Comment with ID "+idParam+" not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -91,17 +91,23 @@ func (cc *CommentController) GetCommentByID(c *gin.Context) {
 // GetAllComments godoc
 // @Summary      Get all comments
 // @Description  Retrieves a list of all submitted comments. Requires permission.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         comments
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}   dtos.GetCommentDTO       "List of all comments"
-// @Failure      401  {object}  models.ErrorResponse     "Unauthorized or permission denied"
-// @Failure      500  {object}  models.ErrorResponse     "Failed to fetch comments or register log"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}   dtos.PageDTO{data=[]dtos.GetCommentDTO}       "List of all comments"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      401  {object}  models.ProblemDetails     "Unauthorized or permission denied"
+// @Failure      500  {object}  models.ProblemDetails     "Failed to fetch comments or register log"
 // @Security     ApiKeyAuth
 // @Router       /comments [get]
 func (cc *CommentController) GetAllComments(c *gin.Context) {
 	if err := cc.Log.RegisterLog(c, "Attempting to retrieve all comments"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -111,14 +117,21 @@ func (cc *CommentController) GetAllComments(c *gin.Context) {
 		return
 	}
 
-	comments, err := cc.Service.GetAllComments()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	comments, page, err := cc.Service.GetAllComments(c.Request.Context(), query)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving all comments: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		_ = c.Error(err)
 		return
 	}
 
-	var commentsDTO []dtos.GetCommentDTO
+	commentsDTO := make([]dtos.GetCommentDTO, 0, len(comments))
 	for _, comment := range comments {
 		commentsDTO = append(commentsDTO, dtos.GetCommentDTO{
 			ID:             comment.ID,
@@ -134,7 +147,7 @@ func (cc *CommentController) GetAllComments(c *gin.Context) {
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved all comments")
 
-	c.JSON(http.StatusOK, commentsDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(commentsDTO, page))
 }
 
 // SearchCommentsByEmail godoc
@@ -145,14 +158,14 @@ func (cc *CommentController) GetAllComments(c *gin.Context) {
 // @Produce      json
 // @Param        email  query     string                  true  "Email address to search comments by"
 // @Success      200    {array}   dtos.GetCommentDTO      "List of matching comments"
-// @Failure      400    {object}  models.ErrorResponse    "Email parameter is required"
-// @Failure      401    {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      500    {object}  models.ErrorResponse    "Failed to search comments or register log"
+// @Failure      401    {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500    {object}  models.ProblemDetails    "Failed to search comments or register log"
 // @Security     ApiKeyAuth
 // @Router       /comments/searchByEmail [get]
 func (cc *CommentController) SearchCommentsByEmail(c *gin.Context) {
 	if err := cc.Log.RegisterLog(c, "Attempting to search comments by email"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -165,14 +178,14 @@ func (cc *CommentController) SearchCommentsByEmail(c *gin.Context) {
 	email := c.Query("email")
 	if email == "" {
 		_ = cc.Log.RegisterLog(c, "Missing 'email' query parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email parameter is required"})
+		_ = c.Error(validation.ParamError("email", "required", "", "is required"))
 		return
 	}
 
-	comments, err := cc.Service.SearchCommentsByEmail(email)
+	comments, err := cc.Service.SearchCommentsByEmail(c.Request.Context(), email)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error searching comments by email '"+email+"': "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search comments"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -203,14 +216,15 @@ func (cc *CommentController) SearchCommentsByEmail(c *gin.Context) {
 // @Produce      json
 // @Param        comment  body      dtos.CreateCommentDTO  true  "Comment data to create"
 // @Success      201      {object}  dtos.GetCommentDTO     "Created comment"
-// @Failure      400      {object}  models.ErrorResponse   "Invalid request data"
-// @Failure      401      {object}  models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      500      {object}  models.ErrorResponse   "Failed to create comment or register log"
+// @Failure      400      {object}  models.ProblemDetails   "Invalid request data"
+// @Failure      422      {object}  models.ProblemDetails   "Validation failed"
+// @Failure      401      {object}  models.ProblemDetails   "Unauthorized or permission denied"
+// @Failure      500      {object}  models.ProblemDetails   "Failed to create comment or register log"
 // @Security     ApiKeyAuth
 // @Router       /comments [post]
 func (cc *CommentController) CreateComment(c *gin.Context) {
 	if err := cc.Log.RegisterLog(c, "Attempting to create a comment"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -223,7 +237,7 @@ func (cc *CommentController) CreateComment(c *gin.Context) {
 	var dto dtos.CreateCommentDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid input for CreateComment: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
@@ -237,10 +251,10 @@ func (cc *CommentController) CreateComment(c *gin.Context) {
 		Comment:        dto.Comment,
 	}
 
-	createdComment, err := cc.Service.CreateComment(comment)
+	createdComment, err := cc.Service.CreateComment(c.Request.Context(), comment)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error creating comment: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -268,10 +282,11 @@ func (cc *CommentController) CreateComment(c *gin.Context) {
 // @Param        id       path      int                  true  "Comment ID"
 // @Param        comment  body      dtos.UpdateCommentDTO  true  "Updated comment data"
 // @Success      200      {object}  dtos.GetCommentDTO     "Updated comment"
-// @Failure      400      {object}  models.ErrorResponse   "Invalid ID or request data"
-// @Failure      401      {object}  models.ErrorResponse   "Unauthorized or permission denied"
-// @Failure      404      {object}  models.ErrorResponse   "Comment not found"
-// @Failure      500      {object}  models.ErrorResponse   "Internal server error or failed update"
+// @Failure      400      {object}  models.ProblemDetails   "Invalid ID or request data"
+// @Failure      422      {object}  models.ProblemDetails   "Validation failed"
+// @Failure      401      {object}  models.ProblemDetails   "Unauthorized or permission denied"
+// @Failure      404      {object}  models.ProblemDetails   "Comment not found"
+// @Failure      500      {object}  models.ProblemDetails   "Internal server error or failed update"
 // @Security     ApiKeyAuth
 // @Router       /comments/{id} [put]
 func (cc *CommentController) UpdateComment(c *gin.Context) {
@@ -285,26 +300,21 @@ func (cc *CommentController) UpdateComment(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid comment ID format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
 	var dto dtos.UpdateCommentDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = cc.Log.RegisterLog(c, "Failed to bind JSON in UpdateComment: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	comment, err := cc.Service.GetCommentByID(id)
+	comment, err := cc.Service.GetCommentByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			_ = cc.Log.RegisterLog(c, "Comment with ID "+strconv.Itoa(id)+" not found")
-			c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
-			return
-		}
 		_ = cc.Log.RegisterLog(c, "Internal error retrieving comment with ID "+strconv.Itoa(id)+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -316,10 +326,10 @@ func (cc *CommentController) UpdateComment(c *gin.Context) {
 	comment.ResidenceCity = dto.ResidenceCity
 	comment.Comment = dto.Comment
 
-	err = cc.Service.UpdateComment(comment)
+	err = cc.Service.UpdateComment(c.Request.Context(), comment)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Failed to update comment with ID "+strconv.Itoa(id)+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -347,10 +357,10 @@ func (cc *CommentController) UpdateComment(c *gin.Context) {
 // @Produce      json
 // @Param        id       query     string               true  "ID to search for comments"
 // @Success      200      {array}   dtos.GetCommentDTO   "List of comments matching the ID"
-// @Failure      400      {object}  models.ErrorResponse "Invalid request parameters"
-// @Failure      401      {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404      {object}  models.ErrorResponse "No comments found for the given ID"
-// @Failure      500      {object}  models.ErrorResponse "Internal server error or failure in processing"
+// @Failure      400      {object}  models.ProblemDetails "Invalid request parameters"
+// @Failure      401      {object}  models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      404      {object}  models.ProblemDetails "No comments found for the given ID"
+// @Failure      500      {object}  models.ProblemDetails "Internal server error or failure in processing"
 // @Security     ApiKeyAuth
 // @Router       /comments/searchByID [get]
 func (cc *CommentController) SearchCommentsByID(c *gin.Context) {
@@ -363,16 +373,16 @@ func (cc *CommentController) SearchCommentsByID(c *gin.Context) {
 		return
 	}
 
-	comments, err := cc.Service.SearchCommentsByID(query)
+	comments, err := cc.Service.SearchCommentsByID(c.Request.Context(), query)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving comments with ID "+query+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving comments"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(comments) == 0 {
 		_ = cc.Log.RegisterLog(c, "No comments found for ID "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No comments found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -402,10 +412,10 @@ func (cc *CommentController) SearchCommentsByID(c *gin.Context) {
 // @Produce      json
 // @Param        name     query     string               true  "Name to search for comments"
 // @Success      200      {array}   dtos.GetCommentDTO   "List of comments matching the name"
-// @Failure      400      {object}  models.ErrorResponse "Invalid request parameters"
-// @Failure      401      {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404      {object}  models.ErrorResponse "No comments found for the given name"
-// @Failure      500      {object}  models.ErrorResponse "Internal server error or failure in processing"
+// @Failure      400      {object}  models.ProblemDetails "Invalid request parameters"
+// @Failure      401      {object}  models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      404      {object}  models.ProblemDetails "No comments found for the given name"
+// @Failure      500      {object}  models.ProblemDetails "Internal server error or failure in processing"
 // @Security     ApiKeyAuth
 // @Router       /comments/searchByName [get]
 func (cc *CommentController) SearchCommentsByName(c *gin.Context) {
@@ -418,16 +428,16 @@ func (cc *CommentController) SearchCommentsByName(c *gin.Context) {
 		return
 	}
 
-	comments, err := cc.Service.SearchCommentsByName(query)
+	comments, err := cc.Service.SearchCommentsByName(c.Request.Context(), query)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving comments with name "+query+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving comments"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(comments) == 0 {
 		_ = cc.Log.RegisterLog(c, "No comments found for name "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No comments found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
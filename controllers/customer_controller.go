@@ -3,11 +3,13 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -26,18 +28,23 @@ func NewCustomerController(service *services.CustomerService, auth *utilities.Au
 // GetAllCustomers godoc
 // @Summary      Retrieve all customers
 // @Description  Retrieves a list of all customers from the system. Requires appropriate permission.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         customers
 // @Accept       json
 // @Produce      json
-// @Success      200      {array}   models.Customer         "List of all customers"
-// @Failure      400      {object}  models.ErrorResponse    "Invalid request parameters"
-// @Failure      401      {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      500      {object}  models.ErrorResponse    "Internal server error or failure in retrieving customers"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200      {object}   dtos.PageDTO{data=[]models.Customer}         "List of all customers"
+// @Failure      400      {object}  models.ProblemDetails    "Invalid request parameters"
+// @Failure      401      {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      500      {object}  models.ProblemDetails    "Internal server error or failure in retrieving customers"
 // @Security     ApiKeyAuth
 // @Router       /customers [get]
 func (cc *CustomerController) GetAllCustomers(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to retrieve all customers") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to retrieve all customers"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -47,15 +54,22 @@ func (cc *CustomerController) GetAllCustomers(c *gin.Context) {
 		return
 	}
 
-	customers, err := cc.Service.GetAllCustomers()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = cc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	customers, page, err := cc.Service.GetAllCustomers(c.Request.Context(), query)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving customers: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving customers"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved all customers")
-	c.JSON(http.StatusOK, customers)
+	cc.Auth.JSON(c, http.StatusOK, dtos.NewPageDTO(customers, page))
 }
 
 // GetCustomerByID godoc
@@ -66,16 +80,16 @@ func (cc *CustomerController) GetAllCustomers(c *gin.Context) {
 // @Produce      json
 // @Param        id       path      int                  true  "Customer ID"
 // @Success      200      {object}  models.Customer      "Customer data"
-// @Failure      400      {object}  models.ErrorResponse "Invalid customer ID"
-// @Failure      401      {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404      {object}  models.ErrorResponse "Customer not found"
-// @Failure      500      {object}  models.ErrorResponse "Internal server error or failure in retrieving customer"
+// @Failure      400      {object}  models.ProblemDetails "Invalid customer ID"
+// @Failure      401      {object}  models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      404      {object}  models.ProblemDetails "Customer not found"
+// @Failure      500      {object}  models.ProblemDetails "Internal server error or failure in retrieving customer"
 // @Security     ApiKeyAuth
 // @Router       /customers/{id} [get]
 func (cc *CustomerController) GetCustomerByID(c *gin.Context) {
 	idParam := c.Param("id")
-	if cc.Log.RegisterLog(c, "Attempting to retrieve customer with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to retrieve customer with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -88,19 +102,19 @@ func (cc *CustomerController) GetCustomerByID(c *gin.Context) {
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid customer ID provided: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	customer, err := cc.Service.GetCustomerByID(id)
+	customer, err := cc.Service.GetCustomerByID(c.Request.Context(), id)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Customer not found with ID: "+idParam)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved customer with ID: "+idParam)
-	c.JSON(http.StatusOK, customer)
+	cc.Auth.JSON(c, http.StatusOK, customer)
 }
 
 // GetCustomerByCustomerID godoc
@@ -111,15 +125,15 @@ func (cc *CustomerController) GetCustomerByID(c *gin.Context) {
 // @Produce      json
 // @Param        customerID   path      string               true  "Customer ID"
 // @Success      200          {object}  models.Customer      "Customer data"
-// @Failure      401          {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404          {object}  models.ErrorResponse "Customer not found"
-// @Failure      500          {object}  models.ErrorResponse "Internal server error or failure in retrieving customer"
+// @Failure      401          {object}  models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      404          {object}  models.ProblemDetails "Customer not found"
+// @Failure      500          {object}  models.ProblemDetails "Internal server error or failure in retrieving customer"
 // @Security     ApiKeyAuth
 // @Router       /customers/customerID/{customerID} [get]
 func (cc *CustomerController) GetCustomerByCustomerID(c *gin.Context) {
 	customerID := c.Param("customerID")
-	if cc.Log.RegisterLog(c, "Attempting to retrieve customer with customerID: "+customerID) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to retrieve customer with customerID: "+customerID); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -129,15 +143,15 @@ func (cc *CustomerController) GetCustomerByCustomerID(c *gin.Context) {
 		return
 	}
 
-	customer, err := cc.Service.GetCustomerByCustomerID(customerID)
+	customer, err := cc.Service.GetCustomerByCustomerID(c.Request.Context(), customerID)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Customer not found with customerID: "+customerID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = cc.Log.RegisterLog(c, "Successfully retrieved customer with customerID: "+customerID)
-	c.JSON(http.StatusOK, customer)
+	cc.Auth.JSON(c, http.StatusOK, customer)
 }
 
 // CreateCustomer godoc
@@ -148,14 +162,15 @@ func (cc *CustomerController) GetCustomerByCustomerID(c *gin.Context) {
 // @Produce      json
 // @Param        customer  body      dtos.CreateCustomerDTO  true  "New customer data"
 // @Success      201       {object}  models.Customer         "The created customer"
-// @Failure      400       {object}  models.ErrorResponse    "Invalid input data (JSON format or missing fields)"
-// @Failure      401       {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      500       {object}  models.ErrorResponse    "Internal server error or failure in creating customer"
+// @Failure      400       {object}  models.ProblemDetails    "Invalid input data (JSON format or missing fields)"
+// @Failure      422       {object}  models.ProblemDetails    "Validation failed"
+// @Failure      401       {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      500       {object}  models.ProblemDetails    "Internal server error or failure in creating customer"
 // @Security     ApiKeyAuth
 // @Router       /customers [post]
 func (cc *CustomerController) CreateCustomer(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to create new customer") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to create new customer"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -168,7 +183,7 @@ func (cc *CustomerController) CreateCustomer(c *gin.Context) {
 	var dto dtos.CreateCustomerDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid JSON format in CreateCustomer request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
@@ -184,15 +199,15 @@ func (cc *CustomerController) CreateCustomer(c *gin.Context) {
 		IdentifierTypeID: dto.IdentifierTypeID,
 	}
 
-	createdCustomer, err := cc.Service.CreateCustomer(customer)
+	createdCustomer, err := cc.Service.CreateCustomer(c.Request.Context(), customer)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error creating customer: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating customer"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = cc.Log.RegisterLog(c, "Customer created successfully with CustomerID: "+createdCustomer.CustomerId)
-	c.JSON(http.StatusCreated, createdCustomer)
+	cc.Auth.JSON(c, http.StatusCreated, createdCustomer)
 }
 
 // UpdateCustomer godoc
@@ -204,15 +219,16 @@ func (cc *CustomerController) CreateCustomer(c *gin.Context) {
 // @Param        id        path      int                    true  "Customer ID"
 // @Param        customer  body      dtos.UpdateCustomerDTO  true  "Updated customer data"
 // @Success      200       {object}  models.Customer         "The updated customer"
-// @Failure      400       {object}  models.ErrorResponse    "Invalid input data (ID format or JSON format)"
-// @Failure      401       {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404       {object}  models.ErrorResponse    "Customer not found"
-// @Failure      500       {object}  models.ErrorResponse    "Internal server error or failure in updating customer"
+// @Failure      400       {object}  models.ProblemDetails    "Invalid input data (ID format or JSON format)"
+// @Failure      422       {object}  models.ProblemDetails    "Validation failed"
+// @Failure      401       {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      404       {object}  models.ProblemDetails    "Customer not found"
+// @Failure      500       {object}  models.ProblemDetails    "Internal server error or failure in updating customer"
 // @Security     ApiKeyAuth
 // @Router       /customers/{id} [put]
 func (cc *CustomerController) UpdateCustomer(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to update customer") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to update customer"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -225,14 +241,14 @@ func (cc *CustomerController) UpdateCustomer(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid customer ID format in URL parameter")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid customer ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
 	var dto dtos.UpdateCustomerDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = cc.Log.RegisterLog(c, "Invalid JSON format in UpdateCustomer request")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
@@ -249,15 +265,15 @@ func (cc *CustomerController) UpdateCustomer(c *gin.Context) {
 		IdentifierTypeID: dto.IdentifierTypeID,
 	}
 
-	err = cc.Service.UpdateCustomer(&customer)
+	err = cc.Service.UpdateCustomer(c.Request.Context(), &customer)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error updating customer with ID "+strconv.Itoa(id)+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating customer"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = cc.Log.RegisterLog(c, "Customer updated successfully with ID: "+strconv.Itoa(id))
-	c.JSON(http.StatusOK, customer)
+	cc.Auth.JSON(c, http.StatusOK, customer)
 }
 
 // GetCustomerByEmail godoc
@@ -268,14 +284,14 @@ func (cc *CustomerController) UpdateCustomer(c *gin.Context) {
 // @Produce      json
 // @Param        email     path      string               true  "Customer email"
 // @Success      200       {object}  models.Customer      "Customer data"
-// @Failure      401       {object}  models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404       {object}  models.ErrorResponse "Customer not found"
-// @Failure      500       {object}  models.ErrorResponse "Internal server error or failure in retrieving customer"
+// @Failure      401       {object}  models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      404       {object}  models.ProblemDetails "Customer not found"
+// @Failure      500       {object}  models.ProblemDetails "Internal server error or failure in retrieving customer"
 // @Security     ApiKeyAuth
 // @Router       /customers/email/{email} [get]
 func (cc *CustomerController) GetCustomerByEmail(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to retrieve customer by email") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to retrieve customer by email"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -287,15 +303,15 @@ func (cc *CustomerController) GetCustomerByEmail(c *gin.Context) {
 
 	email := c.Param("email")
 
-	customer, err := cc.Service.GetCustomerByEmail(email)
+	customer, err := cc.Service.GetCustomerByEmail(c.Request.Context(), email)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Customer not found with email: "+email)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = cc.Log.RegisterLog(c, "Customer retrieved successfully with email: "+email)
-	c.JSON(http.StatusOK, customer)
+	cc.Auth.JSON(c, http.StatusOK, customer)
 }
 
 // SearchCustomersByID godoc
@@ -306,15 +322,15 @@ func (cc *CustomerController) GetCustomerByEmail(c *gin.Context) {
 // @Produce      json
 // @Param        id   query     string                 true  "Customer ID query"
 // @Success      200  {array}   dtos.GetCustomerDTO     "List of customers matching the search"
-// @Failure      400  {object}  models.ErrorResponse    "Invalid query or request format"
-// @Failure      401  {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404  {object}  models.ErrorResponse    "No customers found"
-// @Failure      500  {object}  models.ErrorResponse    "Internal server error or failure in retrieving customers"
+// @Failure      400  {object}  models.ProblemDetails    "Invalid query or request format"
+// @Failure      401  {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      404  {object}  models.ProblemDetails    "No customers found"
+// @Failure      500  {object}  models.ProblemDetails    "Internal server error or failure in retrieving customers"
 // @Security     ApiKeyAuth
 // @Router       /customers/searchByID [get]
 func (cc *CustomerController) SearchCustomersByID(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to search customers by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to search customers by ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -326,16 +342,16 @@ func (cc *CustomerController) SearchCustomersByID(c *gin.Context) {
 
 	query := c.Query("id")
 
-	customers, err := cc.Service.SearchCustomersByID(query)
+	customers, err := cc.Service.SearchCustomersByID(c.Request.Context(), query)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving customers by ID query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving customers"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(customers) == 0 {
 		_ = cc.Log.RegisterLog(c, "No customers found for ID query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No customers found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -356,7 +372,7 @@ func (cc *CustomerController) SearchCustomersByID(c *gin.Context) {
 	}
 
 	_ = cc.Log.RegisterLog(c, "Customers retrieved successfully for ID query: "+query)
-	c.JSON(http.StatusOK, customersDTO)
+	cc.Auth.JSON(c, http.StatusOK, customersDTO)
 }
 
 // SearchCustomersByName godoc
@@ -367,15 +383,15 @@ func (cc *CustomerController) SearchCustomersByID(c *gin.Context) {
 // @Produce      json
 // @Param        name  query     string                 true  "Customer name query"
 // @Success      200   {array}   dtos.GetCustomerDTO     "List of customers matching the search"
-// @Failure      400   {object}  models.ErrorResponse    "Invalid query or request format"
-// @Failure      401   {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404   {object}  models.ErrorResponse    "No customers found"
-// @Failure      500   {object}  models.ErrorResponse    "Internal server error or failure in retrieving customers"
+// @Failure      400   {object}  models.ProblemDetails    "Invalid query or request format"
+// @Failure      401   {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      404   {object}  models.ProblemDetails    "No customers found"
+// @Failure      500   {object}  models.ProblemDetails    "Internal server error or failure in retrieving customers"
 // @Security     ApiKeyAuth
 // @Router       /customers/searchByName [get]
 func (cc *CustomerController) SearchCustomersByName(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to search customers by name") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to search customers by name"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -387,16 +403,16 @@ func (cc *CustomerController) SearchCustomersByName(c *gin.Context) {
 
 	query := c.Query("name")
 
-	customers, err := cc.Service.SearchCustomersByName(query)
+	customers, err := cc.Service.SearchCustomersByName(c.Request.Context(), query)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving customers by name query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving customers"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(customers) == 0 {
 		_ = cc.Log.RegisterLog(c, "No customers found for name query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No customers found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -417,7 +433,7 @@ func (cc *CustomerController) SearchCustomersByName(c *gin.Context) {
 	}
 
 	_ = cc.Log.RegisterLog(c, "Customers retrieved successfully for name query: "+query)
-	c.JSON(http.StatusOK, customersDTO)
+	cc.Auth.JSON(c, http.StatusOK, customersDTO)
 }
 
 // SearchCustomersByLastName godoc
@@ -428,15 +444,15 @@ func (cc *CustomerController) SearchCustomersByName(c *gin.Context) {
 // @Produce      json
 // @Param        lastName  query     string                 true  "Customer last name query"
 // @Success      200       {array}   dtos.GetCustomerDTO     "List of customers matching the search"
-// @Failure      400       {object}  models.ErrorResponse    "Invalid query or request format"
-// @Failure      401       {object}  models.ErrorResponse    "Unauthorized or permission denied"
-// @Failure      404       {object}  models.ErrorResponse    "No customers found"
-// @Failure      500       {object}  models.ErrorResponse    "Internal server error or failure in retrieving customers"
+// @Failure      400       {object}  models.ProblemDetails    "Invalid query or request format"
+// @Failure      401       {object}  models.ProblemDetails    "Unauthorized or permission denied"
+// @Failure      404       {object}  models.ProblemDetails    "No customers found"
+// @Failure      500       {object}  models.ProblemDetails    "Internal server error or failure in retrieving customers"
 // @Security     ApiKeyAuth
 // @Router       /customers/searchByLastName [get]
 func (cc *CustomerController) SearchCustomersByLastName(c *gin.Context) {
-	if cc.Log.RegisterLog(c, "Attempting to search customers by last name") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := cc.Log.RegisterLog(c, "Attempting to search customers by last name"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -448,16 +464,16 @@ func (cc *CustomerController) SearchCustomersByLastName(c *gin.Context) {
 
 	query := c.Query("lastName")
 
-	customers, err := cc.Service.SearchCustomersByLastName(query)
+	customers, err := cc.Service.SearchCustomersByLastName(c.Request.Context(), query)
 	if err != nil {
 		_ = cc.Log.RegisterLog(c, "Error retrieving customers by last name query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving customers"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(customers) == 0 {
 		_ = cc.Log.RegisterLog(c, "No customers found for last name query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No customers found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -478,5 +494,5 @@ func (cc *CustomerController) SearchCustomersByLastName(c *gin.Context) {
 	}
 
 	_ = cc.Log.RegisterLog(c, "Customers retrieved successfully for last name query: "+query)
-	c.JSON(http.StatusOK, customersDTO)
+	cc.Auth.JSON(c, http.StatusOK, customersDTO)
 }
@@ -3,10 +3,12 @@ package controllers
 import (
 	"net/http"
 
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
-	"totesbackend/models"
+	"totesbackend/dtos"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,17 +31,17 @@ func NewDiscountTypeController(service *services.DiscountTypeService, auth *util
 // @Produce      json
 // @Param        id  path     string              true  "Discount Type ID"
 // @Success      200 {object} models.DiscountType "The requested discount type"
-// @Failure      400 {object} models.ErrorResponse "Invalid ID format"
-// @Failure      401 {object} models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      404 {object} models.ErrorResponse "Discount type not found"
-// @Failure      500 {object} models.ErrorResponse "Internal server error or failure in retrieving discount type"
+// @Failure      400 {object} models.ProblemDetails "Invalid ID format"
+// @Failure      401 {object} models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      404 {object} models.ProblemDetails "Discount type not found"
+// @Failure      500 {object} models.ProblemDetails "Internal server error or failure in retrieving discount type"
 // @Security     ApiKeyAuth
 // @Router       /discount-types/{id} [get]
 func (dtc *DiscountTypeController) GetDiscountTypeByID(c *gin.Context) {
 	id := c.Param("id")
 
-	if dtc.Log.RegisterLog(c, "Attempting to retrieve discount type with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := dtc.Log.RegisterLog(c, "Attempting to retrieve discount type with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -49,31 +51,37 @@ func (dtc *DiscountTypeController) GetDiscountTypeByID(c *gin.Context) {
 		return
 	}
 
-	discountType, err := dtc.Service.GetDiscountTypeByID(id)
+	discountType, err := dtc.Service.GetDiscountTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = dtc.Log.RegisterLog(c, "Discount Type with ID "+id+" not found: "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Discount Type not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = dtc.Log.RegisterLog(c, "Successfully retrieved discount type with ID: "+id)
-	c.JSON(http.StatusOK, discountType)
+	dtc.Auth.JSON(c, http.StatusOK, discountType)
 }
 
 // GetAllDiscountTypes godoc
 // @Summary      Get all discount types
 // @Description  Retrieves all available discount types. Requires appropriate permission.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         discount-types
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} models.DiscountType "List of all discount types"
-// @Failure      401 {object} models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      500 {object} models.ErrorResponse "Internal server error or failure in retrieving discount types"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200 {object} dtos.PageDTO{data=[]models.DiscountType} "List of all discount types"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      401 {object} models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      500 {object} models.ProblemDetails "Internal server error or failure in retrieving discount types"
 // @Security     ApiKeyAuth
 // @Router       /discount-types [get]
 func (dtc *DiscountTypeController) GetAllDiscountTypes(c *gin.Context) {
-	if dtc.Log.RegisterLog(c, "Attempting to retrieve all discount types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := dtc.Log.RegisterLog(c, "Attempting to retrieve all discount types"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -83,33 +91,44 @@ func (dtc *DiscountTypeController) GetAllDiscountTypes(c *gin.Context) {
 		return
 	}
 
-	discountTypes, err := dtc.Service.GetAllDiscountTypes()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = dtc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	discountTypes, page, err := dtc.Service.GetAllDiscountTypes(c.Request.Context(), query)
 	if err != nil {
 		_ = dtc.Log.RegisterLog(c, "Error retrieving discount types: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Discount Types"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = dtc.Log.RegisterLog(c, "Successfully retrieved all discount types")
-	c.JSON(http.StatusOK, discountTypes)
+	dtc.Auth.JSON(c, http.StatusOK, dtos.NewPageDTO(discountTypes, page))
 }
 
 // CreateDiscountType godoc
 // @Summary      Create a new discount type
-// @Description  Allows the creation of a new discount type in the system. Requires appropriate permissions.
+// @Description  Allows the creation of a new discount type in the system. A discount type can carry promotion
+// @Description  conditions: validity window, minimum purchase, targeted items or item types, buy X get Y,
+// @Description  per-customer usage limit, coupon code and automatic application. Requires appropriate permissions.
 // @Tags         discount-types
 // @Accept       json
 // @Produce      json
-// @Param        discountType body models.DiscountType true "Discount type details"
+// @Param        discountType body dtos.CreateDiscountTypeDTO true "Discount type details"
 // @Success      201 {object} models.DiscountType "Successfully created discount type"
-// @Failure      400 {object} models.ErrorResponse "Invalid input data"
-// @Failure      401 {object} models.ErrorResponse "Unauthorized or permission denied"
-// @Failure      500 {object} models.ErrorResponse "Internal server error or failure in creating the discount type"
+// @Failure      400 {object} models.ProblemDetails "Invalid input data"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      401 {object} models.ProblemDetails "Unauthorized or permission denied"
+// @Failure      409 {object} models.ProblemDetails "Another discount type already uses the coupon code (case-insensitive)"
+// @Failure      500 {object} models.ProblemDetails "Internal server error or failure in creating the discount type"
 // @Security     ApiKeyAuth
 // @Router       /discount-types [post]
 func (dtc *DiscountTypeController) CreateDiscountType(c *gin.Context) {
-	if dtc.Log.RegisterLog(c, "Attempting to create a new discount type") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := dtc.Log.RegisterLog(c, "Attempting to create a new discount type"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -119,20 +138,20 @@ func (dtc *DiscountTypeController) CreateDiscountType(c *gin.Context) {
 		return
 	}
 
-	var discount models.DiscountType
-	if err := c.ShouldBindJSON(&discount); err != nil {
+	var dto dtos.CreateDiscountTypeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = dtc.Log.RegisterLog(c, "Invalid input for discount creation: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	err := dtc.Service.CreateDiscountType(&discount)
+	discount, err := dtc.Service.CreateDiscountType(c.Request.Context(), &dto)
 	if err != nil {
 		_ = dtc.Log.RegisterLog(c, "Failed to create discount type: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create discount type"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = dtc.Log.RegisterLog(c, "Successfully created new discount type")
-	c.JSON(http.StatusCreated, discount)
+	dtc.Auth.JSON(c, http.StatusCreated, discount)
 }
@@ -1,17 +1,17 @@
 package controllers
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type EmployeeController struct {
@@ -32,31 +32,30 @@ func NewEmployeeController(service *services.EmployeeService, auth *utilities.Au
 // @Produce      json
 // @Param        id path string true "Employee ID"
 // @Success      200 {object} dtos.GetEmployeeDTO "Successfully retrieved employee details"
-// @Failure      400 {object} models.ErrorResponse "Invalid employee ID"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      404 {object} models.ErrorResponse "Employee not found"
+// @Failure      400 {object} models.ProblemDetails "Invalid employee ID"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Employee not found"
 // @Security     ApiKeyAuth
 // @Router       /employees/{id} [get]
 func (ec *EmployeeController) GetEmployeeByID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_EMPLOYEE_BY_ID
 
-	if ec.Log.RegisterLog(c, "Attempting to get employee by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ec.Log.RegisterLog(c, "Attempting to get employee by ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Access denied for GetEmployeeByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	id := c.Param("id")
 
-	employee, err := ec.Service.GetEmployeeByID(id)
+	employee, err := ec.Service.GetEmployeeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Employee not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -78,36 +77,48 @@ func (ec *EmployeeController) GetEmployeeByID(c *gin.Context) {
 // GetAllEmployees godoc
 // @Summary      Get all employees
 // @Description  Retrieves a list of all employees in the system. Requires the appropriate permissions.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         employees
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} dtos.GetEmployeeDTO "Successfully retrieved list of employees"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving employees"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200 {object} dtos.PageDTO{data=[]dtos.GetEmployeeDTO} "Successfully retrieved list of employees"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving employees"
 // @Security     ApiKeyAuth
 // @Router       /employees [get]
 func (ec *EmployeeController) GetAllEmployees(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_EMPLOYEES
 
-	if ec.Log.RegisterLog(c, "Attempting to get all employees") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ec.Log.RegisterLog(c, "Attempting to get all employees"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Access denied for GetAllEmployees")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
-	employees, err := ec.Service.GetAllEmployees()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = ec.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	employees, page, err := ec.Service.GetAllEmployees(c.Request.Context(), query)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error retrieving employees: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving employees"})
+		_ = c.Error(err)
 		return
 	}
 
-	var employeesDTO []dtos.GetEmployeeDTO
+	employeesDTO := make([]dtos.GetEmployeeDTO, 0, len(employees))
 	for _, employee := range employees {
 		employeeDTO := dtos.GetEmployeeDTO{
 			ID:               employee.ID,
@@ -123,7 +134,7 @@ func (ec *EmployeeController) GetAllEmployees(c *gin.Context) {
 	}
 
 	_ = ec.Log.RegisterLog(c, "Successfully retrieved all employees")
-	c.JSON(http.StatusOK, employeesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(employeesDTO, page))
 }
 
 // SearchEmployeesByID godoc
@@ -134,36 +145,35 @@ func (ec *EmployeeController) GetAllEmployees(c *gin.Context) {
 // @Produce      json
 // @Param        id query string true "Employee ID to search for"
 // @Success      200 {array} dtos.GetEmployeeDTO "Successfully found employees matching ID"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      404 {object} models.ErrorResponse "No employees found"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving employees"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "No employees found"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving employees"
 // @Security     ApiKeyAuth
 // @Router       /employees/searchByID [get]
 func (ec *EmployeeController) SearchEmployeesByID(c *gin.Context) {
 	query := c.Query("id")
 	permissionId := config.PERMISSION_SEARCH_EMPLOYEES_BY_ID
 
-	if ec.Log.RegisterLog(c, "Attempting to search employees by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ec.Log.RegisterLog(c, "Attempting to search employees by ID: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Access denied for SearchEmployeesByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
-	employees, err := ec.Service.SearchEmployeesByID(query)
+	employees, err := ec.Service.SearchEmployeesByID(c.Request.Context(), query)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error retrieving employees by ID: "+query+" - "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving employees"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(employees) == 0 {
 		_ = ec.Log.RegisterLog(c, "No employees found with ID: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No employees found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -191,10 +201,10 @@ func (ec *EmployeeController) SearchEmployeesByID(c *gin.Context) {
 // @Produce      json
 // @Param        names query string true "Employee name to search for"
 // @Success      200 {array} dtos.GetEmployeeDTO "Successfully found employees matching name"
-// @Failure      400 {object} models.ErrorResponse "Search query is required"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      404 {object} models.ErrorResponse "No employees found"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving employees"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "No employees found"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving employees"
 // @Security     ApiKeyAuth
 // @Router       /employees/searchByName [get]
 func (ec *EmployeeController) SearchEmployeesByName(c *gin.Context) {
@@ -202,33 +212,32 @@ func (ec *EmployeeController) SearchEmployeesByName(c *gin.Context) {
 
 	query := c.Query("names")
 
-	if ec.Log.RegisterLog(c, "Attempting to search employees by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ec.Log.RegisterLog(c, "Attempting to search employees by name: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Access denied for SearchEmployeesByName")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	if query == "" {
 		_ = ec.Log.RegisterLog(c, "Empty name query provided in SearchEmployeesByName")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		_ = c.Error(validation.ParamError("names", "required", "", "is required"))
 		return
 	}
 
-	employees, err := ec.Service.SearchEmployeesByName(query)
+	employees, err := ec.Service.SearchEmployeesByName(c.Request.Context(), query)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error retrieving employees by name: "+query+" - "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving employees"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(employees) == 0 {
 		_ = ec.Log.RegisterLog(c, "No employees found with name: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No employees found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -258,43 +267,50 @@ func (ec *EmployeeController) SearchEmployeesByName(c *gin.Context) {
 // @Produce      json
 // @Param        employee body dtos.CreateEmployeeDTO true "Employee information"
 // @Success      201 {object} dtos.GetEmployeeDTO "Successfully created employee"
-// @Failure      400 {object} models.ErrorResponse "Invalid JSON format, or missing fields"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      409 {object} models.ErrorResponse "Employee with this Personal ID already exists"
-// @Failure      500 {object} models.ErrorResponse "Error creating employee"
+// @Failure      400 {object} models.ProblemDetails "Invalid JSON format, or missing fields"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      409 {object} models.ProblemDetails "Employee with this Personal ID already exists"
+// @Failure      500 {object} models.ProblemDetails "Error creating employee"
 // @Security     ApiKeyAuth
 // @Router       /employees [post]
 func (ec *EmployeeController) CreateEmployee(c *gin.Context) {
 	permissionId := config.PERMISSION_CREATE_EMPLOYEE
 
-	if ec.Log.RegisterLog(c, "Attempting to create an employee") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ec.Log.RegisterLog(c, "Attempting to create an employee"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Permission denied for CreateEmployee")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
 	var dto dtos.CreateEmployeeDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ec.Log.RegisterLog(c, "Invalid JSON format: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format", "details": err.Error()})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	existingEmployee, _ := ec.Service.GetEmployeeByID(dto.PersonalID)
+	existingEmployee, _ := ec.Service.GetEmployeeByID(c.Request.Context(), dto.PersonalID)
 	if existingEmployee != nil {
 		_ = ec.Log.RegisterLog(c, "Attempt to create duplicate employee with PersonalID: "+dto.PersonalID)
-		c.JSON(http.StatusConflict, gin.H{"error": "An employee with this Personal ID already exists"})
+		_ = c.Error(apperrors.ErrConflict.WithDetail("personal_id", dto.PersonalID))
 		return
 	}
 
-	if dto.UserID <= 0 || dto.IdentifierTypeID <= 0 {
+	var fields []validation.FieldError
+	if dto.UserID <= 0 {
+		fields = append(fields, validation.FieldError{Field: "user_id", Rule: "gt", Param: "0", Message: "must be greater than 0"})
+	}
+	if dto.IdentifierTypeID <= 0 {
+		fields = append(fields, validation.FieldError{Field: "identifier_type_id", Rule: "gt", Param: "0", Message: "must be greater than 0"})
+	}
+	if len(fields) > 0 {
 		_ = ec.Log.RegisterLog(c, "Invalid UserID or IdentifierTypeID: UserID="+strconv.Itoa(dto.UserID)+", IdentifierTypeID="+strconv.Itoa(dto.IdentifierTypeID))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid User ID or Identifier Type ID"})
+		_ = c.Error(apperrors.ErrValidation.WithDetail("fields", fields))
 		return
 	}
 
@@ -308,10 +324,10 @@ func (ec *EmployeeController) CreateEmployee(c *gin.Context) {
 		IdentifierTypeID: dto.IdentifierTypeID,
 	}
 
-	createdEmployee, err := ec.Service.CreateEmployee(employee)
+	createdEmployee, err := ec.Service.CreateEmployee(c.Request.Context(), employee)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error creating employee: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating employee", "details": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -339,23 +355,23 @@ func (ec *EmployeeController) CreateEmployee(c *gin.Context) {
 // @Param        id path string true "Employee ID"
 // @Param        employee body dtos.UpdateEmployeeDTO true "Updated employee information"
 // @Success      200 {object} dtos.GetEmployeeDTO "Successfully updated employee"
-// @Failure      400 {object} models.ErrorResponse "Invalid JSON format"
-// @Failure      403 {object} models.ErrorResponse "Permission denied"
-// @Failure      404 {object} models.ErrorResponse "Employee not found"
-// @Failure      500 {object} models.ErrorResponse "Error updating employee"
+// @Failure      400 {object} models.ProblemDetails "Invalid JSON format"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Employee not found"
+// @Failure      500 {object} models.ProblemDetails "Error updating employee"
 // @Security     ApiKeyAuth
 // @Router       /employees/{id} [put]
 func (ec *EmployeeController) UpdateEmployee(c *gin.Context) {
 	permissionId := config.PERMISSION_UPDATE_EMPLOYEE
 
 	if err := ec.Log.RegisterLog(c, "Attempting to update an employee"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !ec.Auth.CheckPermission(c, permissionId) {
 		_ = ec.Log.RegisterLog(c, "Permission denied for UpdateEmployee")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
@@ -364,19 +380,14 @@ func (ec *EmployeeController) UpdateEmployee(c *gin.Context) {
 	var dto dtos.UpdateEmployeeDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ec.Log.RegisterLog(c, "Invalid JSON in UpdateEmployee: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	employee, err := ec.Service.GetEmployeeByID(id)
+	employee, err := ec.Service.GetEmployeeByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			_ = ec.Log.RegisterLog(c, "Employee not found in UpdateEmployee: ID = "+id)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
-			return
-		}
 		_ = ec.Log.RegisterLog(c, "Error retrieving employee in UpdateEmployee: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -388,10 +399,10 @@ func (ec *EmployeeController) UpdateEmployee(c *gin.Context) {
 	employee.UserID = dto.UserID
 	employee.IdentifierTypeID = dto.IdentifierTypeID
 
-	err = ec.Service.UpdateEmployee(employee)
+	err = ec.Service.UpdateEmployee(c.Request.Context(), employee)
 	if err != nil {
 		_ = ec.Log.RegisterLog(c, "Error updating employee: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -3,11 +3,14 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
+	"totesbackend/middleware"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,16 +33,16 @@ func NewExternalSaleController(service *services.ExternalSaleService, auth *util
 // @Produce      json
 // @Param        id path string true "External Sale ID"
 // @Success      200 {object} dtos.GetExternalSaleDTO "Successfully retrieved external sale"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
-// @Failure      404 {object} models.ErrorResponse "External sale not found"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving external sale"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
+// @Failure      404 {object} models.ProblemDetails "External sale not found"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving external sale"
 // @Security     ApiKeyAuth
 // @Router       /external-sales/{id} [get]
 func (esc *ExternalSaleController) GetExternalSaleByID(c *gin.Context) {
 	id := c.Param("id")
 
-	if esc.Log.RegisterLog(c, "Fetching external sale by ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := esc.Log.RegisterLog(c, "Fetching external sale by ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -49,10 +52,10 @@ func (esc *ExternalSaleController) GetExternalSaleByID(c *gin.Context) {
 		return
 	}
 
-	externalSale, err := esc.Service.GetExternalSaleByID(id)
+	externalSale, err := esc.Service.GetExternalSaleByID(c.Request.Context(), id)
 	if err != nil {
 		_ = esc.Log.RegisterLog(c, "External Sale not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "External Sale not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -65,27 +68,34 @@ func (esc *ExternalSaleController) GetExternalSaleByID(c *gin.Context) {
 		CustomerID:    externalSale.Customer.ID,
 		CustomerEmail: externalSale.Customer.Email,
 		Stock:         externalSale.Stock,
+		APIKeyID:      externalSale.APIKeyID,
 	}
 
 	_ = esc.Log.RegisterLog(c, "Successfully fetched external sale with ID: "+id)
 
-	c.JSON(http.StatusOK, dto)
+	esc.Auth.JSON(c, http.StatusOK, dto)
 }
 
 // GetAllExternalSales godoc
 // @Summary      Retrieve all external sales
 // @Description  Fetches a list of all external sales, including related items and customers.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         external-sales
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} dtos.GetExternalSaleDTO "Successfully retrieved all external sales"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving external sales"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200 {object} dtos.PageDTO{data=[]dtos.GetExternalSaleDTO} "Successfully retrieved all external sales"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving external sales"
 // @Security     ApiKeyAuth
 // @Router       /external-sales [get]
 func (esc *ExternalSaleController) GetAllExternalSales(c *gin.Context) {
-	if esc.Log.RegisterLog(c, "Fetching all external sales") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := esc.Log.RegisterLog(c, "Fetching all external sales"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -95,14 +105,21 @@ func (esc *ExternalSaleController) GetAllExternalSales(c *gin.Context) {
 		return
 	}
 
-	externalSales, err := esc.Service.GetAllExternalSales()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = esc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	externalSales, page, err := esc.Service.GetAllExternalSales(c.Request.Context(), query)
 	if err != nil {
 		_ = esc.Log.RegisterLog(c, "Error retrieving external sales")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving external sales"})
+		_ = c.Error(err)
 		return
 	}
 
-	var externalSalesDTO []dtos.GetExternalSaleDTO
+	externalSalesDTO := make([]dtos.GetExternalSaleDTO, 0, len(externalSales))
 	for _, sale := range externalSales {
 		externalSaleDTO := dtos.GetExternalSaleDTO{
 			ID:            sale.ID,
@@ -113,6 +130,7 @@ func (esc *ExternalSaleController) GetAllExternalSales(c *gin.Context) {
 			CustomerID:    sale.Customer.ID,
 			CustomerEmail: sale.Customer.Email,
 			Stock:         sale.Stock,
+			APIKeyID:      sale.APIKeyID,
 		}
 
 		externalSalesDTO = append(externalSalesDTO, externalSaleDTO)
@@ -120,35 +138,55 @@ func (esc *ExternalSaleController) GetAllExternalSales(c *gin.Context) {
 
 	_ = esc.Log.RegisterLog(c, "Successfully retrieved all external sales")
 
-	c.JSON(http.StatusOK, externalSalesDTO)
+	esc.Auth.JSON(c, http.StatusOK, dtos.NewPageDTO(externalSalesDTO, page))
 }
 
 // CreateExternalSale godoc
 // @Summary      Create a new external sale
 // @Description  Creates a new external sale, including the sale details and customer information.
+// @Description  When called with a reporter's API key (X-API-Key) the sale is attributed to the reporter of the key and reporter_name and reporter_id are ignored; otherwise both are required.
 // @Tags         external-sales
 // @Accept       json
 // @Produce      json
 // @Param        external-sale body dtos.CreateExternalSaleDTO true "External Sale data"
 // @Success      201 {object} dtos.GetExternalSaleDTO "Successfully created external sale"
-// @Failure      400 {object} models.ErrorResponse "Invalid JSON format"
-// @Failure      500 {object} models.ErrorResponse "Error creating external sale"
+// @Failure      400 {object} models.ProblemDetails "Invalid JSON format"
+// @Failure      401 {object} models.ProblemDetails "Invalid, expired or revoked API key"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500 {object} models.ProblemDetails "Error creating external sale"
 // @Security     ApiKeyAuth
 // @Router       /external-sales [post]
 func (esc *ExternalSaleController) CreateExternalSale(c *gin.Context) {
 
-	if esc.Log.RegisterLog(c, "Creating new external sale") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := esc.Log.RegisterLog(c, "Creating new external sale"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_CREATE_EXTERNAL_SALE
+	if !esc.Auth.CheckPermission(c, permissionId) {
+		_ = esc.Log.RegisterLog(c, "Access denied for CreateExternalSale")
 		return
 	}
 
 	var dto dtos.CreateExternalSaleDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = esc.Log.RegisterLog(c, "Invalid JSON format for external sale")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
+	// Con una llave de revendedor el reportante sale de la llave
+	key, _ := middleware.APIKeyFromContext(c)
+	if key == nil || !key.IsReporter() {
+		if fields := missingReporterFields(&dto); len(fields) > 0 {
+			_ = esc.Log.RegisterLog(c, "Missing reporter for external sale")
+			_ = c.Error(apperrors.ErrValidation.WithDetail("fields", fields))
+			return
+		}
+	}
+
 	_ = esc.Log.RegisterLog(c, "Received request to create external sale: "+dto.ReporterName)
 
 	externalSale := models.ExternalSale{
@@ -168,10 +206,10 @@ func (esc *ExternalSaleController) CreateExternalSale(c *gin.Context) {
 		},
 	}
 
-	externalSaleWithID, err := esc.Service.CreateExternalSale(&externalSale)
+	externalSaleWithID, err := esc.Service.CreateExternalSale(c.Request.Context(), &externalSale, key)
 	if err != nil {
-		_ = esc.Log.RegisterLog(c, "Error creating external sale: "+dto.ReporterName)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating external sale"})
+		_ = esc.Log.RegisterLog(c, "Error creating external sale: "+externalSale.ReporterName)
+		_ = c.Error(err)
 		return
 	}
 
@@ -184,9 +222,23 @@ func (esc *ExternalSaleController) CreateExternalSale(c *gin.Context) {
 		CustomerID:    externalSaleWithID.CustomerID,
 		CustomerEmail: externalSaleWithID.Customer.Email,
 		Stock:         externalSaleWithID.Stock,
+		APIKeyID:      externalSaleWithID.APIKeyID,
 	}
 
 	_ = esc.Log.RegisterLog(c, "Successfully created external sale with ID: "+strconv.Itoa(dtoResponse.ID))
 
-	c.JSON(http.StatusCreated, dtoResponse)
+	esc.Auth.JSON(c, http.StatusCreated, dtoResponse)
+}
+
+// missingReporterFields lista los datos del reportante que faltan cuando la
+// venta no se reporta con una llave de revendedor.
+func missingReporterFields(dto *dtos.CreateExternalSaleDTO) []validation.FieldError {
+	var fields []validation.FieldError
+	if dto.ReporterName == "" {
+		fields = append(fields, validation.FieldError{Field: "reporter_name", Rule: "required", Message: "is required"})
+	}
+	if dto.ReporterID == "" {
+		fields = append(fields, validation.FieldError{Field: "reporter_id", Rule: "required", Message: "is required"})
+	}
+	return fields
 }
@@ -2,19 +2,47 @@ package controllers
 
 import (
 	"net/http"
+	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-// ControllerHealthCheck godoc
-// @Summary      Health Check
-// @Description  Returns a 200 status if the server is running correctly.
+// HealthController atiende las sondas del orquestador. No exige permisos ni
+// registra logs de usuario porque se consulta cada pocos segundos.
+type HealthController struct {
+	Service *services.HealthService
+}
+
+func NewHealthController(service *services.HealthService) *HealthController {
+	return &HealthController{Service: service}
+}
+
+// Liveness godoc
+// @Summary      Liveness probe
+// @Description  Returns 200 while the process is able to serve requests. It does not touch the database.
 // @Tags         Health
 // @Produce      json
 // @Success      200  {object}  models.MessageResponse
-// @Router       /health [get]
-func ControllerHealthCheck(c *gin.Context) {
-	// IndentedJSON serializes the given struct as pretty JSON (indented + endlines) into the response body.
-	// Includes de status onf http and the struct
-	c.IndentedJSON(http.StatusOK, gin.H{"message": "Successful Health Check."})
+// @Router       /healthz [get]
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"message": "Successful Health Check."})
+}
+
+// Readiness godoc
+// @Summary      Readiness probe
+// @Description  Pings the database, compares the applied schema version with the one the binary expects and reports connection pool usage.
+// @Description  Returns 503 when any check fails, when the pool is saturated or while the server is shutting down.
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  dtos.ReadinessDTO  "Ready to receive traffic"
+// @Failure      503  {object}  dtos.ReadinessDTO  "Not ready"
+// @Router       /readyz [get]
+func (hc *HealthController) Readiness(c *gin.Context) {
+	report := hc.Service.CheckReadiness(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != services.ReadinessReady {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
 }
@@ -2,9 +2,14 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
+	"time"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -31,16 +36,16 @@ func NewHistoricalItemPriceController(service *services.HistoricalItemPriceServi
 // @Produce      json
 // @Param        id path string true "Item ID"
 // @Success      200 {array} models.HistoricalItemPrice "Successfully retrieved historical prices"
-// @Failure      400 {object} models.ErrorResponse "Invalid Item ID"
-// @Failure      500 {object} models.ErrorResponse "Failed to retrieve historical prices"
-// @Failure      404 {object} models.ErrorResponse "No historical prices found"
+// @Failure      400 {object} models.ProblemDetails "Invalid Item ID"
+// @Failure      500 {object} models.ProblemDetails "Failed to retrieve historical prices"
+// @Failure      404 {object} models.ProblemDetails "No historical prices found"
 // @Security     ApiKeyAuth
 // @Router       /historical-item-prices/{id} [get]
 func (c *HistoricalItemPriceController) GetHistoricalItemPrice(ctx *gin.Context) {
 	itemID := ctx.Param("id")
 
-	if c.Log.RegisterLog(ctx, "Attempting to retrieve historical item price for item ID: "+itemID) != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := c.Log.RegisterLog(ctx, "Attempting to retrieve historical item price for item ID: "+itemID); err != nil {
+		_ = ctx.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -50,19 +55,165 @@ func (c *HistoricalItemPriceController) GetHistoricalItemPrice(ctx *gin.Context)
 		return
 	}
 
-	historicalPrices, err := c.Service.GetHistoricalItemPrice(itemID)
+	historicalPrices, err := c.Service.GetHistoricalItemPrice(ctx.Request.Context(), itemID)
 	if err != nil {
 		_ = c.Log.RegisterLog(ctx, "Error retrieving historical prices for item ID "+itemID+": "+err.Error())
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve historical prices"})
+		_ = ctx.Error(err)
 		return
 	}
 
 	if len(historicalPrices) == 0 {
 		_ = c.Log.RegisterLog(ctx, "No historical prices found for item ID "+itemID)
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "No historical prices found"})
+		_ = ctx.Error(apperrors.ErrNotFound)
 		return
 	}
 
 	_ = c.Log.RegisterLog(ctx, "Successfully retrieved historical prices for item ID: "+itemID)
 	ctx.JSON(http.StatusOK, historicalPrices)
 }
+
+// GetItemPriceAsOf godoc
+// @Summary      Get the price of an item at a point in time
+// @Description  Retrieves the selling price range of an item that was effective at the given date.
+// @Tags         historical-item-prices
+// @Accept       json
+// @Produce      json
+// @Param        id    path  string true "Item ID"
+// @Param        date  query string true "Date (RFC3339 format)"
+// @Success      200 {object} models.HistoricalItemPrice "Price effective at the given date"
+// @Failure      400 {object} models.ProblemDetails "Invalid Item ID"
+// @Failure      404 {object} models.ProblemDetails "No price found for the given date"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500 {object} models.ProblemDetails "Failed to retrieve price"
+// @Security     ApiKeyAuth
+// @Router       /historical-item-prices/{id}/as-of [get]
+func (c *HistoricalItemPriceController) GetItemPriceAsOf(ctx *gin.Context) {
+	itemIDParam := ctx.Param("id")
+	dateParam := ctx.Query("date")
+
+	if err := c.Log.RegisterLog(ctx, "Attempting to retrieve price of item ID "+itemIDParam+" as of "+dateParam); err != nil {
+		_ = ctx.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_GET_ITEM_PRICE_AS_OF
+	if !c.Auth.CheckPermission(ctx, permissionId) {
+		_ = c.Log.RegisterLog(ctx, "Access denied for GetItemPriceAsOf")
+		return
+	}
+
+	itemID, err := strconv.Atoi(itemIDParam)
+	if err != nil {
+		_ = c.Log.RegisterLog(ctx, "Invalid item ID: "+itemIDParam)
+		_ = ctx.Error(apperrors.ErrBadRequest.WithDetail("id", ctx.Param("id")))
+		return
+	}
+
+	date, err := time.Parse(time.RFC3339, dateParam)
+	if err != nil {
+		_ = c.Log.RegisterLog(ctx, "Invalid date: "+dateParam)
+		_ = ctx.Error(validation.ParamError("date", "datetime", time.RFC3339, "must be a date in RFC3339 format"))
+		return
+	}
+
+	price, err := c.Service.GetPriceAsOf(ctx.Request.Context(), itemID, date)
+	if err != nil {
+		_ = c.Log.RegisterLog(ctx, "Error retrieving price for item ID "+itemIDParam+": "+err.Error())
+		_ = ctx.Error(err)
+		return
+	}
+
+	_ = c.Log.RegisterLog(ctx, "Successfully retrieved price of item ID "+itemIDParam+" as of "+dateParam)
+	ctx.JSON(http.StatusOK, price)
+}
+
+// SchedulePriceChange godoc
+// @Summary      Schedule a future price change
+// @Description  Schedules a new selling price for an item, effective from a future date. The item's selling price is updated automatically when the date is reached.
+// @Tags         historical-item-prices
+// @Accept       json
+// @Produce      json
+// @Param        id    path string                      true "Item ID"
+// @Param        body  body dtos.SchedulePriceChangeDTO true "New price and effective date"
+// @Success      201 {object} models.HistoricalItemPrice "Scheduled price"
+// @Failure      400 {object} models.ProblemDetails "Invalid request data"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      404 {object} models.ProblemDetails "Item not found"
+// @Failure      500 {object} models.ProblemDetails "Failed to schedule price change"
+// @Security     ApiKeyAuth
+// @Router       /historical-item-prices/{id}/scheduled [post]
+func (c *HistoricalItemPriceController) SchedulePriceChange(ctx *gin.Context) {
+	itemIDParam := ctx.Param("id")
+
+	if err := c.Log.RegisterLog(ctx, "Attempting to schedule price change for item ID: "+itemIDParam); err != nil {
+		_ = ctx.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_SCHEDULE_ITEM_PRICE
+	if !c.Auth.CheckPermission(ctx, permissionId) {
+		_ = c.Log.RegisterLog(ctx, "Access denied for SchedulePriceChange")
+		return
+	}
+
+	itemID, err := strconv.Atoi(itemIDParam)
+	if err != nil {
+		_ = c.Log.RegisterLog(ctx, "Invalid item ID: "+itemIDParam)
+		_ = ctx.Error(apperrors.ErrBadRequest.WithDetail("id", ctx.Param("id")))
+		return
+	}
+
+	var dto dtos.SchedulePriceChangeDTO
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		_ = c.Log.RegisterLog(ctx, "Invalid request data for scheduled price: "+err.Error())
+		_ = ctx.Error(validation.BindError(err))
+		return
+	}
+
+	price, err := c.Service.SchedulePriceChange(ctx.Request.Context(), itemID, dto.Price, dto.EffectiveFrom)
+	if err != nil {
+		_ = c.Log.RegisterLog(ctx, "Error scheduling price for item ID "+itemIDParam+": "+err.Error())
+		_ = ctx.Error(err)
+		return
+	}
+
+	_ = c.Log.RegisterLog(ctx, "Successfully scheduled price change for item ID: "+itemIDParam)
+	ctx.JSON(http.StatusCreated, price)
+}
+
+// CancelScheduledPrice godoc
+// @Summary      Cancel a scheduled price change
+// @Description  Removes a price change that is not yet effective. The previous price range is extended to cover its period.
+// @Tags         historical-item-prices
+// @Accept       json
+// @Produce      json
+// @Param        priceId path string true "Historical price ID"
+// @Success      200 {object} models.MessageResponse "Scheduled price cancelled"
+// @Failure      400 {object} models.ProblemDetails "Price is already effective"
+// @Failure      404 {object} models.ProblemDetails "Scheduled price not found"
+// @Failure      500 {object} models.ProblemDetails "Failed to cancel scheduled price"
+// @Security     ApiKeyAuth
+// @Router       /historical-item-prices/scheduled/{priceId} [delete]
+func (c *HistoricalItemPriceController) CancelScheduledPrice(ctx *gin.Context) {
+	priceID := ctx.Param("priceId")
+
+	if err := c.Log.RegisterLog(ctx, "Attempting to cancel scheduled price with ID: "+priceID); err != nil {
+		_ = ctx.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_CANCEL_SCHEDULED_ITEM_PRICE
+	if !c.Auth.CheckPermission(ctx, permissionId) {
+		_ = c.Log.RegisterLog(ctx, "Access denied for CancelScheduledPrice")
+		return
+	}
+
+	if err := c.Service.CancelScheduledPrice(ctx.Request.Context(), priceID); err != nil {
+		_ = c.Log.RegisterLog(ctx, "Error cancelling scheduled price with ID "+priceID+": "+err.Error())
+		_ = ctx.Error(err)
+		return
+	}
+
+	_ = c.Log.RegisterLog(ctx, "Successfully cancelled scheduled price with ID: "+priceID)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Scheduled price cancelled"})
+}
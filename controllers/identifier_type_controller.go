@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"net/http"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
@@ -22,37 +24,49 @@ func NewIdentifierTypeController(service *services.IdentifierTypeService, auth *
 // GetAllIdentifierTypes godoc
 // @Summary      Get all identifier types
 // @Description  Retrieves a list of all available identifier types.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         identifier-types
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} models.IdentifierType "Successfully retrieved identifier types"
-// @Failure      500 {object} models.ErrorResponse "Error retrieving identifier types"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200 {object} dtos.PageDTO{data=[]models.IdentifierType} "Successfully retrieved identifier types"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving identifier types"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /identifier-types [get]
 func (itc *IdentifierTypeController) GetAllIdentifierTypes(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_IDENTIFIER_TYPES
 
-	if itc.Log.RegisterLog(c, "Attempting to get all identifier types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := itc.Log.RegisterLog(c, "Attempting to get all identifier types"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !itc.Auth.CheckPermission(c, permissionId) {
 		_ = itc.Log.RegisterLog(c, "Access denied for GetAllIdentifierTypes")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
-	identifierTypes, err := itc.Service.GetAllIdentifierTypes()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	identifierTypes, page, err := itc.Service.GetAllIdentifierTypes(c.Request.Context(), query)
 	if err != nil {
 		_ = itc.Log.RegisterLog(c, "Error retrieving identifier types: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Identifier Types"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = itc.Log.RegisterLog(c, "Successfully retrieved all identifier types")
-	c.JSON(http.StatusOK, identifierTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(identifierTypes, page))
 }
 
 // GetIdentifierTypeByID godoc
@@ -63,30 +77,29 @@ func (itc *IdentifierTypeController) GetAllIdentifierTypes(c *gin.Context) {
 // @Produce      json
 // @Param        id  path      string  true  "Identifier Type ID"
 // @Success      200 {object} models.IdentifierType "Successfully retrieved identifier type"
-// @Failure      404 {object} models.ErrorResponse "Identifier Type not found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Failure      404 {object} models.ProblemDetails "Identifier Type not found"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /identifier-types/{id} [get]
 func (itc *IdentifierTypeController) GetIdentifierTypeByID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_IDENTIFIER_TYPE_BY_ID
 
-	if itc.Log.RegisterLog(c, "Attempting to get identifier type by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := itc.Log.RegisterLog(c, "Attempting to get identifier type by ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !itc.Auth.CheckPermission(c, permissionId) {
 		_ = itc.Log.RegisterLog(c, "Access denied for GetIdentifierTypeByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	id := c.Param("id")
 
-	identifierType, err := itc.Service.GetIdentifierTypeByID(id)
+	identifierType, err := itc.Service.GetIdentifierTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = itc.Log.RegisterLog(c, "Identifier type not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Identifier Type not found"})
+		_ = c.Error(err)
 		return
 	}
 
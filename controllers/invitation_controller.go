@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/middleware"
+	"totesbackend/models"
+	"totesbackend/services"
+	"totesbackend/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InvitationController struct {
+	Service *services.InvitationService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewInvitationController(service *services.InvitationService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *InvitationController {
+	return &InvitationController{Service: service, Auth: auth, Log: log}
+}
+
+// GetPendingInvitations godoc
+// @Summary      Get pending invitations
+// @Description  Retrieves the invitations that have not been accepted yet, including expired ones. Newest first by default.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
+// @Tags         invitations
+// @Produce      json
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}  dtos.PageDTO{data=[]dtos.InvitationDTO}  "List of pending invitations"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving invitations"
+// @Security     ApiKeyAuth
+// @Router       /invitations [get]
+func (ic *InvitationController) GetPendingInvitations(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_INVITATIONS
+
+	if err := ic.Log.RegisterLog(c, "Attempting to retrieve pending invitations"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for GetPendingInvitations")
+		return
+	}
+
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	invitations, page, err := ic.Service.GetPendingInvitations(c.Request.Context(), query)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Error retrieving invitations: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	now := time.Now()
+	invitationDTOs := make([]dtos.InvitationDTO, 0, len(invitations))
+	for i := range invitations {
+		invitationDTOs = append(invitationDTOs, invitationDTO(&invitations[i], now))
+	}
+
+	_ = ic.Log.RegisterLog(c, "Successfully retrieved pending invitations")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(invitationDTOs, page))
+}
+
+// InviteUser godoc
+// @Summary      Invite a user
+// @Description  Creates a pending user with the given email and user type and emails them a single-use link to choose their password.
+// @Description  The account becomes active when the invitation is accepted. sent_at is null if the email could not be sent; resend it then.
+// @Description  The caller must hold every permission of the user type; otherwise the response is 403.
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.InviteUserDTO  true  "Email and user type of the new user"
+// @Success      201   {object}  dtos.InvitationDTO  "Created invitation"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      403   {object}  models.ProblemDetails  "Permission denied, or the user type has permissions the caller does not hold"
+// @Failure      409   {object}  models.ProblemDetails  "A user with this email already exists"
+// @Failure      422   {object}  models.ProblemDetails  "Validation failed"
+// @Failure      500   {object}  models.ProblemDetails  "Error creating the invitation"
+// @Security     ApiKeyAuth
+// @Router       /invitations [post]
+func (ic *InvitationController) InviteUser(c *gin.Context) {
+	permissionId := config.PERMISSION_INVITE_USER
+
+	if err := ic.Log.RegisterLog(c, "Attempting to invite a user"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for InviteUser")
+		return
+	}
+
+	var dto dtos.InviteUserDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid request body for InviteUser")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	invitation, err := ic.Service.InviteUser(c.Request.Context(), dto.Email, dto.UserTypeID, middleware.Caller(c))
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Error inviting "+dto.Email+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = ic.Log.RegisterLog(c, "Successfully invited: "+dto.Email)
+	c.JSON(http.StatusCreated, invitationDTO(invitation, time.Now()))
+}
+
+// ResendInvitation godoc
+// @Summary      Resend an invitation
+// @Description  Emails a new invitation link with a new expiration. The previous link stops working.
+// @Tags         invitations
+// @Produce      json
+// @Param        id   path      string  true  "Invitation ID"
+// @Success      200  {object}  dtos.InvitationDTO  "Resent invitation"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "Invitation not found"
+// @Failure      409  {object}  models.ProblemDetails  "Invitation already accepted"
+// @Failure      500  {object}  models.ProblemDetails  "Error resending the invitation"
+// @Security     ApiKeyAuth
+// @Router       /invitations/{id}/resend [post]
+func (ic *InvitationController) ResendInvitation(c *gin.Context) {
+	permissionId := config.PERMISSION_INVITE_USER
+	id := c.Param("id")
+
+	if err := ic.Log.RegisterLog(c, "Attempting to resend invitation with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for ResendInvitation")
+		return
+	}
+
+	invitation, err := ic.Service.ResendInvitation(c.Request.Context(), id, middleware.Caller(c))
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Error resending invitation with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = ic.Log.RegisterLog(c, "Successfully resent invitation with ID: "+id)
+	c.JSON(http.StatusOK, invitationDTO(invitation, time.Now()))
+}
+
+// RevokeInvitation godoc
+// @Summary      Revoke an invitation
+// @Description  Cancels an invitation that has not been accepted and deletes the pending user, so the email can be invited again.
+// @Tags         invitations
+// @Produce      json
+// @Param        id   path      string  true  "Invitation ID"
+// @Success      200  {object}  models.MessageResponse  "Invitation revoked"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "Invitation not found"
+// @Failure      409  {object}  models.ProblemDetails  "Invitation already accepted"
+// @Failure      500  {object}  models.ProblemDetails  "Error revoking the invitation"
+// @Security     ApiKeyAuth
+// @Router       /invitations/{id} [delete]
+func (ic *InvitationController) RevokeInvitation(c *gin.Context) {
+	permissionId := config.PERMISSION_REVOKE_INVITATION
+	id := c.Param("id")
+
+	if err := ic.Log.RegisterLog(c, "Attempting to revoke invitation with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !ic.Auth.CheckPermission(c, permissionId) {
+		_ = ic.Log.RegisterLog(c, "Access denied for RevokeInvitation")
+		return
+	}
+
+	if err := ic.Service.RevokeInvitation(c.Request.Context(), id, middleware.Caller(c)); err != nil {
+		_ = ic.Log.RegisterLog(c, "Error revoking invitation with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = ic.Log.RegisterLog(c, "Successfully revoked invitation with ID: "+id)
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// AcceptInvitation godoc
+// @Summary      Accept an invitation
+// @Description  Sets the password of an invited user with the token received by email and activates the account.
+// @Description  The token can be used only once and expires. The password must satisfy the password policy.
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.AcceptInvitationDTO  true  "Invitation token and password"
+// @Success      200   {object}  models.MessageResponse  "Invitation accepted"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body or invalid, used or expired token"
+// @Failure      422   {object}  models.ProblemDetails  "Password does not meet the policy"
+// @Failure      500   {object}  models.ProblemDetails  "Error accepting the invitation"
+// @Router       /invitations/accept [post]
+func (ic *InvitationController) AcceptInvitation(c *gin.Context) {
+	if err := ic.Log.RegisterLog(c, "Attempting to accept an invitation"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.AcceptInvitationDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid request body for AcceptInvitation")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	if err := ic.Service.AcceptInvitation(c.Request.Context(), dto.Token, dto.Password, c.ClientIP()); err != nil {
+		_ = ic.Log.RegisterLog(c, "Invitation acceptance failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = ic.Log.RegisterLog(c, "Invitation accepted")
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation accepted, you can now log in"})
+}
+
+func invitationDTO(invitation *models.UserInvitation, now time.Time) dtos.InvitationDTO {
+	return dtos.InvitationDTO{
+		ID:         invitation.ID,
+		UserID:     invitation.UserID,
+		Email:      invitation.User.Email,
+		UserTypeID: invitation.User.UserTypeID,
+		InvitedBy:  invitation.InvitedBy,
+		ExpiresAt:  invitation.ExpiresAt,
+		Expired:    !now.Before(invitation.ExpiresAt),
+		SentAt:     invitation.SentAt,
+		CreatedAt:  invitation.CreatedAt,
+	}
+}
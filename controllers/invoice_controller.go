@@ -3,11 +3,13 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,17 +32,22 @@ func NewInvoiceController(
 // GetAllInvoices godoc
 // @Summary      Get all invoices
 // @Description  Retrieves all invoices from the system.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         invoices
 // @Accept       json
 // @Produce      json
-// @Success      200 {array} dtos.GetInvoiceDTO "List of all invoices"
-// @Failure      404 {object} models.ErrorResponse "No invoices found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200 {object} dtos.PageDTO{data=[]dtos.GetInvoiceDTO} "List of all invoices"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /invoices [get]
 func (ic *InvoiceController) GetAllInvoices(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Attempting to retrieve all invoices") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Attempting to retrieve all invoices"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -50,20 +57,21 @@ func (ic *InvoiceController) GetAllInvoices(c *gin.Context) {
 		return
 	}
 
-	invoices, err := ic.Service.GetAllInvoices()
+	query, err := utilities.ParseListQuery(c)
 	if err != nil {
-		_ = ic.Log.RegisterLog(c, "Error retrieving invoices: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invoices"})
+		_ = ic.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
 		return
 	}
 
-	if len(invoices) == 0 {
-		_ = ic.Log.RegisterLog(c, "No invoices found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "No invoices found"})
+	invoices, page, err := ic.Service.GetAllInvoices(c.Request.Context(), query)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Error retrieving invoices: "+err.Error())
+		_ = c.Error(err)
 		return
 	}
 
-	var invoiceDTOs []dtos.GetInvoiceDTO
+	invoiceDTOs := make([]dtos.GetInvoiceDTO, 0, len(invoices))
 	for _, invoice := range invoices {
 		invoiceDTOs = append(invoiceDTOs, dtos.GetInvoiceDTO{
 			ID:             invoice.ID,
@@ -79,7 +87,7 @@ func (ic *InvoiceController) GetAllInvoices(c *gin.Context) {
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved all invoices")
-	c.JSON(http.StatusOK, invoiceDTOs)
+	ic.Auth.JSON(c, http.StatusOK, dtos.NewPageDTO(invoiceDTOs, page))
 }
 
 // GetInvoiceByID godoc
@@ -90,15 +98,15 @@ func (ic *InvoiceController) GetAllInvoices(c *gin.Context) {
 // @Produce      json
 // @Param        id   path      int  true  "Invoice ID"
 // @Success      200 {object} dtos.GetInvoiceDTO "Invoice details"
-// @Failure      400 {object} models.ErrorResponse "Invalid invoice ID"
-// @Failure      404 {object} models.ErrorResponse "Invoice not found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Failure      400 {object} models.ProblemDetails "Invalid invoice ID"
+// @Failure      404 {object} models.ProblemDetails "Invoice not found"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
 // @Security     ApiKeyAuth
 // @Router       /invoices/{id} [get]
 func (ic *InvoiceController) GetInvoiceByID(c *gin.Context) {
 	idParam := c.Param("id")
-	if ic.Log.RegisterLog(c, "Attempting to retrieve invoice with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Attempting to retrieve invoice with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -111,14 +119,14 @@ func (ic *InvoiceController) GetInvoiceByID(c *gin.Context) {
 	id, err := strconv.Atoi(idParam)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid invoice ID: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	invoice, err := ic.Service.GetInvoiceByID(strconv.Itoa(id))
+	invoice, err := ic.Service.GetInvoiceByID(c.Request.Context(), strconv.Itoa(id))
 	if err != nil {
-		_ = ic.Log.RegisterLog(c, "Invoice not found with ID: "+idParam)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		_ = ic.Log.RegisterLog(c, "Error retrieving invoice with ID: "+idParam+": "+err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -135,7 +143,7 @@ func (ic *InvoiceController) GetInvoiceByID(c *gin.Context) {
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved invoice with ID: "+idParam)
-	c.JSON(http.StatusOK, invoiceDTO)
+	ic.Auth.JSON(c, http.StatusOK, invoiceDTO)
 }
 
 // SearchInvoiceByID godoc
@@ -146,16 +154,16 @@ func (ic *InvoiceController) GetInvoiceByID(c *gin.Context) {
 // @Produce      json
 // @Param        id   query     string  true  "Invoice ID Query"
 // @Success      200 {array} dtos.GetInvoiceDTO "List of invoices found"
-// @Failure      400 {object} models.ErrorResponse "Query parameter is required"
-// @Failure      404 {object} models.ErrorResponse "No invoices found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Failure      404 {object} models.ProblemDetails "No invoices found"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
 // @Security     ApiKeyAuth
 // @Router       /invoices/searchById [get]
 func (ic *InvoiceController) SearchInvoiceByID(c *gin.Context) {
 	query := c.Query("id")
 
-	if ic.Log.RegisterLog(c, "Attempting to search invoice(s) by ID query: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Attempting to search invoice(s) by ID query: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -167,14 +175,14 @@ func (ic *InvoiceController) SearchInvoiceByID(c *gin.Context) {
 
 	if query == "" {
 		_ = ic.Log.RegisterLog(c, "Missing query parameter for SearchInvoiceByID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter is required"})
+		_ = c.Error(validation.ParamError("id", "required", "", "is required"))
 		return
 	}
 
-	invoices, err := ic.Service.SearchInvoiceByID(query)
+	invoices, err := ic.Service.SearchInvoiceByID(c.Request.Context(), query)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error searching invoices by ID query "+query+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching invoices"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -194,7 +202,7 @@ func (ic *InvoiceController) SearchInvoiceByID(c *gin.Context) {
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved "+strconv.Itoa(len(invoiceDTOs))+" invoice(s) for search ID: "+query)
-	c.JSON(http.StatusOK, invoiceDTOs)
+	ic.Auth.JSON(c, http.StatusOK, invoiceDTOs)
 }
 
 // SearchInvoiceByCustomerPersonalId godoc
@@ -205,16 +213,16 @@ func (ic *InvoiceController) SearchInvoiceByID(c *gin.Context) {
 // @Produce      json
 // @Param        personal_id   query     string  true  "Customer Personal ID Query"
 // @Success      200 {array} dtos.GetInvoiceDTO "List of invoices found"
-// @Failure      400 {object} models.ErrorResponse "Query parameter 'personal_id' is required"
-// @Failure      404 {object} models.ErrorResponse "No invoices found"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
+// @Failure      404 {object} models.ProblemDetails "No invoices found"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
 // @Security     ApiKeyAuth
 // @Router       /invoices/searchByPersonalId [get]
 func (ic *InvoiceController) SearchInvoiceByCustomerPersonalId(c *gin.Context) {
 	query := c.Query("personal_id")
 
-	if ic.Log.RegisterLog(c, "Attempting to search invoice(s) by customer personal ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Attempting to search invoice(s) by customer personal ID: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -226,14 +234,14 @@ func (ic *InvoiceController) SearchInvoiceByCustomerPersonalId(c *gin.Context) {
 
 	if query == "" {
 		_ = ic.Log.RegisterLog(c, "Missing query parameter 'personal_id' for SearchInvoiceByCustomerPersonalId")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter 'personal_id' is required"})
+		_ = c.Error(validation.ParamError("personal_id", "required", "", "is required"))
 		return
 	}
 
-	invoices, err := ic.Service.SearchInvoiceByCustomerPersonalId(query)
+	invoices, err := ic.Service.SearchInvoiceByCustomerPersonalId(c.Request.Context(), query)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error searching invoices by customer personal ID "+query+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching invoices by customer personal ID"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -253,25 +261,28 @@ func (ic *InvoiceController) SearchInvoiceByCustomerPersonalId(c *gin.Context) {
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved "+strconv.Itoa(len(invoiceDTOs))+" invoice(s) for customer personal ID: "+query)
-	c.JSON(http.StatusOK, invoiceDTOs)
+	ic.Auth.JSON(c, http.StatusOK, invoiceDTOs)
 }
 
 // CreateInvoice godoc
 // @Summary      Create a new invoice
 // @Description  Create a new invoice based on the provided JSON data. Requires appropriate permissions.
+// @Description  The response includes a warning for each item sold below its landed cost.
 // @Tags         invoices
 // @Accept       json
 // @Produce      json
 // @Param        invoice_body  body      dtos.CreateInvoiceDTO  true  "Invoice data"
 // @Success      201 {object} dtos.GetInvoiceDTO "Created invoice"
-// @Failure      400 {object} models.ErrorResponse "Invalid request data"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
-// @Failure      500 {object} models.ErrorResponse "Error creating invoice"
+// @Failure      400 {object} models.ProblemDetails "Invalid request data"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
+// @Failure      409 {object} models.ProblemDetails "Insufficient stock"
+// @Failure      422 {object} models.ProblemDetails "Validation failed or a requested discount does not apply"
+// @Failure      500 {object} models.ProblemDetails "Error creating invoice"
 // @Security     ApiKeyAuth
 // @Router       /invoices [post]
 func (ic *InvoiceController) CreateInvoice(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Attempting to create new invoice") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Attempting to create new invoice"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -284,14 +295,14 @@ func (ic *InvoiceController) CreateInvoice(c *gin.Context) {
 	var dto dtos.CreateInvoiceDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid invoice creation request data: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	invoice, err := ic.Service.CreateInvoice(&dto)
+	invoice, warnings, err := ic.Service.CreateInvoice(c.Request.Context(), &dto)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error creating invoice: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -305,18 +316,24 @@ func (ic *InvoiceController) CreateInvoice(c *gin.Context) {
 		Items:          extractInvoiceBillingItems(invoice.Items),
 		Discounts:      extractDiscountIds(invoice.Discounts),
 		Taxes:          extractTaxIds(invoice.Taxes),
+		Warnings:       warnings,
+	}
+
+	for _, warning := range warnings {
+		_ = ic.Log.RegisterLog(c, "Warning on invoice "+strconv.Itoa(invoice.ID)+": "+warning.Message)
 	}
 
 	_ = ic.Log.RegisterLog(c, "Successfully created invoice with ID: "+strconv.Itoa(invoice.ID))
-	c.JSON(http.StatusCreated, invoiceDTO)
+	ic.Auth.JSON(c, http.StatusCreated, invoiceDTO)
 }
 
 func extractInvoiceBillingItems(items []models.InvoiceItem) []dtos.BillingItemDTO {
 	var billingItems []dtos.BillingItemDTO
 	for _, item := range items {
 		billingItems = append(billingItems, dtos.BillingItemDTO{
-			ID:    item.ItemID,
-			Stock: item.Amount,
+			ID:        item.ItemID,
+			Stock:     item.Amount,
+			UnitPrice: item.UnitPrice,
 		})
 	}
 	return billingItems
@@ -1,18 +1,18 @@
 package controllers
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type ItemController struct {
@@ -34,17 +34,17 @@ func NewItemController(service *services.ItemService, auth *utilities.Authorizat
 // @Param        id       path     string  true  "Item ID"
 // @Param        quantity query    int     true  "Quantity to check"
 // @Success      200 {boolean} true "Indicates whether the stock is sufficient or not"
-// @Failure      400 {object} models.ErrorResponse "Invalid quantity"
-// @Failure      403 {object} models.ErrorResponse "Access denied"
-// @Failure      500 {object} models.ErrorResponse "Error checking stock"
+// @Failure      403 {object} models.ProblemDetails "Access denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500 {object} models.ProblemDetails "Error checking stock"
 // @Security     ApiKeyAuth
 // @Router       /items/{id}/stock [get]
 func (ic *ItemController) CheckItemStock(c *gin.Context) {
 	idParam := c.Param("id")
 	quantityParam := c.Query("quantity")
 
-	if ic.Log.RegisterLog(c, "Checking stock for item ID: "+idParam+" with quantity: "+quantityParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Checking stock for item ID: "+idParam+" with quantity: "+quantityParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -57,14 +57,14 @@ func (ic *ItemController) CheckItemStock(c *gin.Context) {
 	quantity, err := strconv.Atoi(quantityParam)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid quantity: "+quantityParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quantity"})
+		_ = c.Error(validation.ParamError("quantity", "numeric", "", "must be an integer"))
 		return
 	}
 
-	hasStock, err := ic.Service.HasEnoughStock(idParam, quantity)
+	hasStock, err := ic.Service.HasEnoughStock(c.Request.Context(), idParam, quantity)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error checking stock for item ID "+idParam+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking stock"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -81,22 +81,22 @@ func (ic *ItemController) CheckItemStock(c *gin.Context) {
 // @Produce      json
 // @Param        id   path     string  true  "Item ID"
 // @Success      200  {object} dtos.GetItemDTO "Item found"
-// @Failure      400  {object} models.ErrorResponse "Invalid item ID format"
-// @Failure      404  {object} models.ErrorResponse "Item not found"
-// @Failure      500  {object} models.ErrorResponse "Error fetching item"
+// @Failure      400  {object} models.ProblemDetails "Invalid item ID format"
+// @Failure      404  {object} models.ProblemDetails "Item not found"
+// @Failure      500  {object} models.ProblemDetails "Error fetching item"
 // @Security     ApiKeyAuth
 // @Router       /items/{id} [get]
 func (ic *ItemController) GetItemByID(c *gin.Context) {
 	id := c.Param("id")
 
-	if ic.Log.RegisterLog(c, "Fetching item by ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Fetching item by ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
-	item, err := ic.Service.GetItemByID(id)
+	item, err := ic.Service.GetItemByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Item not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -119,33 +119,46 @@ func (ic *ItemController) GetItemByID(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully fetched item with ID: "+id)
 
-	c.JSON(http.StatusOK, itemDTO)
+	ic.Auth.JSON(c, http.StatusOK, itemDTO)
 }
 
 // GetAllItems godoc
 // @Summary      Get all items
 // @Description  Retrieve a list of all items available in the inventory.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         items
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}  dtos.GetItemDTO "List of items"
-// @Failure      500  {object} models.ErrorResponse "Error retrieving items"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}  dtos.PageDTO{data=[]dtos.GetItemDTO} "List of items"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      500  {object} models.ProblemDetails "Error retrieving items"
 // @Security     ApiKeyAuth
 // @Router       /items [get]
 func (ic *ItemController) GetAllItems(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Fetching all items") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Fetching all items"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	items, err := ic.Service.GetAllItems()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = ic.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	items, page, err := ic.Service.GetAllItems(c.Request.Context(), query)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error retrieving items")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving items"})
+		_ = c.Error(err)
 		return
 	}
 
-	var itemsDTO []dtos.GetItemDTO
+	itemsDTO := make([]dtos.GetItemDTO, 0, len(items))
 	for _, item := range items {
 		additionalExpenseIDs := make([]int, len(item.AdditionalExpenses))
 		for i, expense := range item.AdditionalExpenses {
@@ -169,7 +182,7 @@ func (ic *ItemController) GetAllItems(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved all items")
 
-	c.JSON(http.StatusOK, itemsDTO)
+	ic.Auth.JSON(c, http.StatusOK, dtos.NewPageDTO(itemsDTO, page))
 }
 
 // SearchItemsByID godoc
@@ -180,14 +193,14 @@ func (ic *ItemController) GetAllItems(c *gin.Context) {
 // @Produce      json
 // @Param        id  query     string  true  "Item ID to search for"
 // @Success      200  {array}  dtos.GetItemDTO "List of items matching the search criteria"
-// @Failure      400  {object} models.ErrorResponse "Missing or invalid search query"
-// @Failure      404  {object} models.ErrorResponse "No items found"
-// @Failure      500  {object} models.ErrorResponse "Error retrieving items"
+// @Failure      404  {object} models.ProblemDetails "No items found"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object} models.ProblemDetails "Error retrieving items"
 // @Security     ApiKeyAuth
 // @Router       /items/searchById [get]
 func (ic *ItemController) SearchItemsByID(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Searching items by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Searching items by ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -200,20 +213,20 @@ func (ic *ItemController) SearchItemsByID(c *gin.Context) {
 	query := c.Query("id")
 	if query == "" {
 		_ = ic.Log.RegisterLog(c, "Search query is missing")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		_ = c.Error(validation.ParamError("id", "required", "", "is required"))
 		return
 	}
 
-	items, err := ic.Service.SearchItemsByID(query)
+	items, err := ic.Service.SearchItemsByID(c.Request.Context(), query)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error retrieving items from database")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving items"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(items) == 0 {
 		_ = ic.Log.RegisterLog(c, "No items found for query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No items found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -241,7 +254,7 @@ func (ic *ItemController) SearchItemsByID(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved items for query: "+query)
 
-	c.JSON(http.StatusOK, itemsDTO)
+	ic.Auth.JSON(c, http.StatusOK, itemsDTO)
 }
 
 // SearchItemsByName godoc
@@ -252,14 +265,14 @@ func (ic *ItemController) SearchItemsByID(c *gin.Context) {
 // @Produce      json
 // @Param        name  query     string  true  "Item name to search for"
 // @Success      200   {array}   dtos.GetItemDTO "List of items matching the search criteria"
-// @Failure      400   {object}  models.ErrorResponse "Missing or invalid search query"
-// @Failure      404   {object}  models.ErrorResponse "No items found"
-// @Failure      500   {object}  models.ErrorResponse "Error retrieving items"
+// @Failure      404   {object}  models.ProblemDetails "No items found"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500   {object}  models.ProblemDetails "Error retrieving items"
 // @Security     ApiKeyAuth
 // @Router       /items/searchByName [get]
 func (ic *ItemController) SearchItemsByName(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Searching items by name") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Searching items by name"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -272,20 +285,20 @@ func (ic *ItemController) SearchItemsByName(c *gin.Context) {
 	query := c.Query("name")
 	if query == "" {
 		_ = ic.Log.RegisterLog(c, "Search query is missing")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		_ = c.Error(validation.ParamError("name", "required", "", "is required"))
 		return
 	}
 
-	items, err := ic.Service.SearchItemsByName(query)
+	items, err := ic.Service.SearchItemsByName(c.Request.Context(), query)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error retrieving items from database")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving items"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(items) == 0 {
 		_ = ic.Log.RegisterLog(c, "No items found for query: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No items found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -313,7 +326,7 @@ func (ic *ItemController) SearchItemsByName(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully retrieved items for query: "+query)
 
-	c.JSON(http.StatusOK, itemsDTO)
+	ic.Auth.JSON(c, http.StatusOK, itemsDTO)
 }
 
 // UpdateItemState godoc
@@ -325,14 +338,15 @@ func (ic *ItemController) SearchItemsByName(c *gin.Context) {
 // @Param        id        path     string  true  "ID of the item to update"
 // @Param        item_state  body     bool    true  "New state for the item (true for active, false for inactive)"
 // @Success      200      {object}  dtos.GetItemDTO "Updated item information"
-// @Failure      400      {object}  models.ErrorResponse "Invalid request body"
-// @Failure      404      {object}  models.ErrorResponse "Item not found"
-// @Failure      500      {object}  models.ErrorResponse "Error updating item state"
+// @Failure      400      {object}  models.ProblemDetails "Invalid request body"
+// @Failure      422      {object}  models.ProblemDetails "Validation failed"
+// @Failure      404      {object}  models.ProblemDetails "Item not found"
+// @Failure      500      {object}  models.ProblemDetails "Error updating item state"
 // @Security     ApiKeyAuth
 // @Router       /items/{id}/state [patch]
 func (ic *ItemController) UpdateItemState(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Updating item state") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Updating item state"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -351,14 +365,14 @@ func (ic *ItemController) UpdateItemState(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&request); err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid request body")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	item, err := ic.Service.UpdateItemState(id, request.ItemState)
+	item, err := ic.Service.UpdateItemState(c.Request.Context(), id, request.ItemState)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Item not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -381,7 +395,7 @@ func (ic *ItemController) UpdateItemState(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully updated state for item ID: "+id)
 
-	c.JSON(http.StatusOK, itemDTO)
+	ic.Auth.JSON(c, http.StatusOK, itemDTO)
 }
 
 // UpdateItem godoc
@@ -393,14 +407,15 @@ func (ic *ItemController) UpdateItemState(c *gin.Context) {
 // @Param        id    path      string              true  "ID of the item to update"
 // @Param        item  body      dtos.UpdateItemDTO  true  "Updated item data"
 // @Success      200   {object}  dtos.GetItemDTO      "Item updated successfully"
-// @Failure      400   {object}  models.ErrorResponse "Invalid JSON format"
-// @Failure      404   {object}  models.ErrorResponse "Item not found"
-// @Failure      500   {object}  models.ErrorResponse "Error updating item"
+// @Failure      400   {object}  models.ProblemDetails "Invalid JSON format"
+// @Failure      422   {object}  models.ProblemDetails "Validation failed"
+// @Failure      404   {object}  models.ProblemDetails "Item not found"
+// @Failure      500   {object}  models.ProblemDetails "Error updating item"
 // @Security     ApiKeyAuth
 // @Router       /items/{id} [put]
 func (ic *ItemController) UpdateItem(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Updating item") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Updating item"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -416,20 +431,15 @@ func (ic *ItemController) UpdateItem(c *gin.Context) {
 	var dto dtos.UpdateItemDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid JSON format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
 	// Buscar el item en la base de datos
-	item, err := ic.Service.GetItemByID(id)
+	item, err := ic.Service.GetItemByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			_ = ic.Log.RegisterLog(c, "Item not found with ID: "+id)
-			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
-			return
-		}
 		_ = ic.Log.RegisterLog(c, "Error retrieving item with ID: "+id)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving item"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -443,10 +453,10 @@ func (ic *ItemController) UpdateItem(c *gin.Context) {
 	item.ItemTypeID = dto.ItemTypeID
 
 	// Llamar al servicio para actualizar el item
-	err = ic.Service.UpdateItem(item)
+	err = ic.Service.UpdateItem(c.Request.Context(), item)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error updating item with ID: "+id)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating item"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -469,7 +479,7 @@ func (ic *ItemController) UpdateItem(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully updated item with ID: "+id)
 
-	c.JSON(http.StatusOK, dtoGet)
+	ic.Auth.JSON(c, http.StatusOK, dtoGet)
 }
 
 // CreateItem godoc
@@ -480,13 +490,14 @@ func (ic *ItemController) UpdateItem(c *gin.Context) {
 // @Produce      json
 // @Param        item  body      dtos.UpdateItemDTO  true  "Item to create"
 // @Success      201   {object}  dtos.GetItemDTO      "Item created successfully"
-// @Failure      400   {object}  models.ErrorResponse "Invalid JSON format"
-// @Failure      500   {object}  models.ErrorResponse "Error creating item"
+// @Failure      400   {object}  models.ProblemDetails "Invalid JSON format"
+// @Failure      422   {object}  models.ProblemDetails "Validation failed"
+// @Failure      500   {object}  models.ProblemDetails "Error creating item"
 // @Security     ApiKeyAuth
 // @Router       /items [post]
 func (ic *ItemController) CreateItem(c *gin.Context) {
-	if ic.Log.RegisterLog(c, "Creating new item") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ic.Log.RegisterLog(c, "Creating new item"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -499,7 +510,7 @@ func (ic *ItemController) CreateItem(c *gin.Context) {
 	var dto dtos.UpdateItemDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = ic.Log.RegisterLog(c, "Invalid JSON format")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
@@ -517,10 +528,10 @@ func (ic *ItemController) CreateItem(c *gin.Context) {
 	}
 
 	// Llamar al servicio para crear el item
-	itemWithId, err := ic.Service.CreateItem(&item)
+	itemWithId, err := ic.Service.CreateItem(c.Request.Context(), &item)
 	if err != nil {
 		_ = ic.Log.RegisterLog(c, "Error creating item: "+dto.Name)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating item"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -543,5 +554,5 @@ func (ic *ItemController) CreateItem(c *gin.Context) {
 
 	_ = ic.Log.RegisterLog(c, "Successfully created item with ID: "+strconv.Itoa(dtoGet.ID))
 
-	c.JSON(http.StatusCreated, dtoGet)
+	ic.Auth.JSON(c, http.StatusCreated, dtoGet)
 }
@@ -3,8 +3,10 @@ package controllers
 import (
 	"net/http"
 
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
@@ -27,15 +29,15 @@ func NewItemTypeController(service *services.ItemTypeService, auth *utilities.Au
 // @Produce      json
 // @Param        id   path      string                 true  "Item Type ID"
 // @Success      200  {object}  models.ItemType        "Item Type retrieved successfully"
-// @Failure      404  {object}  models.ErrorResponse   "Item Type not found"
-// @Failure      500  {object}  models.ErrorResponse   "Error registering log"
+// @Failure      404  {object}  models.ProblemDetails   "Item Type not found"
+// @Failure      500  {object}  models.ProblemDetails   "Error registering log"
 // @Security     ApiKeyAuth
 // @Router       /item-types/{id} [get]
 func (itc *ItemTypeController) GetItemTypeByID(c *gin.Context) {
 	id := c.Param("id")
 
-	if itc.Log.RegisterLog(c, "Attempting to retrieve ItemType with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := itc.Log.RegisterLog(c, "Attempting to retrieve ItemType with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -45,10 +47,10 @@ func (itc *ItemTypeController) GetItemTypeByID(c *gin.Context) {
 		return
 	}
 
-	itemType, err := itc.Service.GetItemTypeByID(id)
+	itemType, err := itc.Service.GetItemTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = itc.Log.RegisterLog(c, "Error retrieving ItemType with ID "+id+": "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Item Type not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -59,15 +61,21 @@ func (itc *ItemTypeController) GetItemTypeByID(c *gin.Context) {
 // GetItemTypes godoc
 // @Summary      Get all item types
 // @Description  Retrieves a list of all item types.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         item-types
 // @Produce      json
-// @Success      200  {array}   models.ItemType         "List of item types retrieved successfully"
-// @Failure      500  {object}  models.ErrorResponse    "Error retrieving item types or registering log"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}   dtos.PageDTO{data=[]models.ItemType}         "List of item types retrieved successfully"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      500  {object}  models.ProblemDetails    "Error retrieving item types or registering log"
 // @Security     ApiKeyAuth
 // @Router       /item-types [get]
 func (itc *ItemTypeController) GetItemTypes(c *gin.Context) {
-	if itc.Log.RegisterLog(c, "Attempting to retrieve all ItemTypes") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := itc.Log.RegisterLog(c, "Attempting to retrieve all ItemTypes"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -77,13 +85,20 @@ func (itc *ItemTypeController) GetItemTypes(c *gin.Context) {
 		return
 	}
 
-	itemTypes, err := itc.Service.GetAllItemTypes()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = itc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	itemTypes, page, err := itc.Service.GetAllItemTypes(c.Request.Context(), query)
 	if err != nil {
 		_ = itc.Log.RegisterLog(c, "Error retrieving ItemTypes: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Item Types"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = itc.Log.RegisterLog(c, "Successfully retrieved all ItemTypes")
-	c.JSON(http.StatusOK, itemTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(itemTypes, page))
 }
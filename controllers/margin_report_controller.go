@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+	"totesbackend/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MarginReportController struct {
+	Service *services.MarginService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewMarginReportController(service *services.MarginService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *MarginReportController {
+	return &MarginReportController{Service: service, Auth: auth, Log: log}
+}
+
+// GetItemLandedCost godoc
+// @Summary      Get the landed cost of an item
+// @Description  Returns the purchase price of the item plus each additional expense apportioned per unit.
+// @Description  An expense without units is apportioned over the item's stock plus its invoiced units.
+// @Tags         margin-report
+// @Produce      json
+// @Param        id  path  string  true  "Item ID"
+// @Success      200  {object}  dtos.LandedCostDTO  "Landed cost of the item"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "Item not found"
+// @Failure      500  {object}  models.ProblemDetails  "Error calculating landed cost"
+// @Security     ApiKeyAuth
+// @Router       /margin-report/items/{id}/landed-cost [get]
+func (mrc *MarginReportController) GetItemLandedCost(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := mrc.Log.RegisterLog(c, "Attempting to calculate landed cost of item with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_GET_ITEM_LANDED_COST
+	if !mrc.Auth.CheckPermission(c, permissionId) {
+		_ = mrc.Log.RegisterLog(c, "Access denied for GetItemLandedCost")
+		return
+	}
+
+	landedCost, err := mrc.Service.GetLandedCost(c.Request.Context(), id)
+	if err != nil {
+		_ = mrc.Log.RegisterLog(c, "Error calculating landed cost of item with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = mrc.Log.RegisterLog(c, "Successfully calculated landed cost of item with ID: "+id)
+	c.JSON(http.StatusOK, landedCost)
+}
+
+// GetMarginByItem godoc
+// @Summary      Margin report per item
+// @Description  Revenue, landed cost and margin of each item invoiced between the given dates.
+// @Tags         margin-report
+// @Produce      json
+// @Param        startDate  query  string  true  "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true  "End Date (RFC3339 format)"
+// @Success      200  {array}  dtos.MarginRowDTO  "Margin per item"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating margin report"
+// @Security     ApiKeyAuth
+// @Router       /margin-report/items [get]
+func (mrc *MarginReportController) GetMarginByItem(c *gin.Context) {
+	mrc.marginReport(c, "item", func(startDate, endDate time.Time) ([]dtos.MarginRowDTO, error) {
+		return mrc.Service.GetMarginByItem(c.Request.Context(), startDate, endDate)
+	})
+}
+
+// GetMarginByItemType godoc
+// @Summary      Margin report per item type
+// @Description  Revenue, landed cost and margin of each item type invoiced between the given dates.
+// @Tags         margin-report
+// @Produce      json
+// @Param        startDate  query  string  true  "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true  "End Date (RFC3339 format)"
+// @Success      200  {array}  dtos.MarginRowDTO  "Margin per item type"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating margin report"
+// @Security     ApiKeyAuth
+// @Router       /margin-report/item-types [get]
+func (mrc *MarginReportController) GetMarginByItemType(c *gin.Context) {
+	mrc.marginReport(c, "item type", func(startDate, endDate time.Time) ([]dtos.MarginRowDTO, error) {
+		return mrc.Service.GetMarginByItemType(c.Request.Context(), startDate, endDate)
+	})
+}
+
+// GetMarginByPeriod godoc
+// @Summary      Margin report per period
+// @Description  Revenue, landed cost and margin of everything invoiced between the given dates, grouped by day, week or month.
+// @Tags         margin-report
+// @Produce      json
+// @Param        startDate  query  string  true   "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true   "End Date (RFC3339 format)"
+// @Param        period     query  string  false  "Grouping: day, week or month (default month)"
+// @Success      200  {array}  dtos.MarginRowDTO  "Margin per period"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid period"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating margin report"
+// @Security     ApiKeyAuth
+// @Router       /margin-report/periods [get]
+func (mrc *MarginReportController) GetMarginByPeriod(c *gin.Context) {
+	period := c.Query("period")
+	mrc.marginReport(c, "period", func(startDate, endDate time.Time) ([]dtos.MarginRowDTO, error) {
+		return mrc.Service.GetMarginByPeriod(c.Request.Context(), startDate, endDate, period)
+	})
+}
+
+// marginReport resuelve los pasos comunes de los reportes de márgenes: log,
+// permiso y lectura del rango de fechas.
+func (mrc *MarginReportController) marginReport(c *gin.Context, grouping string,
+	report func(startDate, endDate time.Time) ([]dtos.MarginRowDTO, error)) {
+	startDateStr := c.Query("startDate")
+	endDateStr := c.Query("endDate")
+
+	if err := mrc.Log.RegisterLog(c, "Request margin report per "+grouping+" between "+startDateStr+" and "+endDateStr); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_VIEW_MARGIN_REPORT
+	if !mrc.Auth.CheckPermission(c, permissionId) {
+		_ = mrc.Log.RegisterLog(c, "Access denied for margin report per "+grouping)
+		return
+	}
+
+	startDate, err := time.Parse(time.RFC3339, startDateStr)
+	if err != nil {
+		_ = mrc.Log.RegisterLog(c, "Invalid startDate: "+startDateStr)
+		_ = c.Error(validation.ParamError("startDate", "datetime", time.RFC3339, "must be a date in RFC3339 format"))
+		return
+	}
+
+	endDate, err := time.Parse(time.RFC3339, endDateStr)
+	if err != nil {
+		_ = mrc.Log.RegisterLog(c, "Invalid endDate: "+endDateStr)
+		_ = c.Error(validation.ParamError("endDate", "datetime", time.RFC3339, "must be a date in RFC3339 format"))
+		return
+	}
+
+	rows, err := report(startDate, endDate)
+	if err != nil {
+		_ = mrc.Log.RegisterLog(c, "Error generating margin report per "+grouping+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = mrc.Log.RegisterLog(c, "Successfully generated margin report per "+grouping)
+	c.JSON(http.StatusOK, rows)
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"net/http"
+
+	"totesbackend/apperrors"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+	"totesbackend/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie guarda en el navegador el state del inicio de sesión con el
+// proveedor; FinishLogin exige que coincida con el state recibido.
+const oidcStateCookie = "oidc_state"
+
+type OIDCController struct {
+	Service *services.OIDCService
+	Log     *utilities.LogUtil
+}
+
+func NewOIDCController(service *services.OIDCService, log *utilities.LogUtil) *OIDCController {
+	return &OIDCController{Service: service, Log: log}
+}
+
+// StartLogin godoc
+// @Summary      Start single sign-on
+// @Description  Returns the URL of the company identity provider to send the user to (OpenID Connect authorization code flow with PKCE).
+// @Description  The provider sends the user back to the configured redirect page with code and state, which the client posts to /oidc/callback.
+// @Description  The response sets an HttpOnly oidc_state cookie that must be sent with the callback from the same browser.
+// @Description  Only available when an identity provider is configured.
+// @Tags         authentication
+// @Produce      json
+// @Success      200  {object}  dtos.OIDCLoginDTO  "Authorization URL"
+// @Failure      401  {object}  models.ProblemDetails  "Identity provider unavailable"
+// @Failure      500  {object}  models.ProblemDetails  "Error starting single sign-on"
+// @Router       /oidc/login [get]
+func (oc *OIDCController) StartLogin(c *gin.Context) {
+	if err := oc.Log.RegisterLog(c, "Attempting to start single sign-on"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	authURL, state, err := oc.Service.StartLogin(c.Request.Context())
+	if err != nil {
+		_ = oc.Log.RegisterLog(c, "Error starting single sign-on: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, int(oc.Service.Config.LoginTTL.Seconds()), "/oidc", "", c.Request.TLS != nil, true)
+	_ = oc.Log.RegisterLog(c, "Single sign-on started")
+	c.JSON(http.StatusOK, dtos.OIDCLoginDTO{AuthorizationURL: authURL})
+}
+
+// FinishLogin godoc
+// @Summary      Finish single sign-on
+// @Description  Exchanges the authorization code, validates the ID token and opens a session for the user whose email matches the token.
+// @Description  The returned token is sent as Authorization: Bearer <token>.
+// @Description  If the identity provider groups map to a user type, the user type is updated. Each state can be used only once.
+// @Description  The oidc_state cookie set by /oidc/login must match state; otherwise the response is 400 sso.invalid_state.
+// @Description  Users with two-factor authentication must also send two_factor_code, as in /user-credential-validation, unless the
+// @Description  identity provider reports a trusted second factor in the amr claim. A rejected code needs a new /oidc/login.
+// @Tags         authentication
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.OIDCCallbackDTO  true  "Code and state received from the identity provider"
+// @Success      200   {object}  dtos.OIDCLoginResultDTO  "Login successful"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body, invalid, used or expired state, or state not matching the browser cookie"
+// @Failure      401   {object}  models.ProblemDetails  "Code exchange or ID token validation failed, or two-factor code missing or invalid"
+// @Failure      403   {object}  models.ProblemDetails  "No user matches the email, the account is not active or two-factor setup is required"
+// @Failure      423   {object}  models.ProblemDetails  "Account locked"
+// @Failure      429   {object}  models.ProblemDetails  "Too many failed two-factor attempts, retry after the indicated wait"
+// @Failure      500   {object}  models.ProblemDetails  "Error finishing single sign-on"
+// @Router       /oidc/callback [post]
+func (oc *OIDCController) FinishLogin(c *gin.Context) {
+	if err := oc.Log.RegisterLog(c, "Attempting to finish single sign-on"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.OIDCCallbackDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = oc.Log.RegisterLog(c, "Invalid request body for single sign-on callback")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	browserState, _ := c.Cookie(oidcStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, "", -1, "/oidc", "", c.Request.TLS != nil, true)
+
+	session, token, err := oc.Service.FinishLogin(c.Request.Context(), dto.Code, dto.State, browserState, dto.TwoFactorCode,
+		c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		_ = oc.Log.RegisterLog(c, "Single sign-on failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = oc.Log.RegisterLog(c, "Single sign-on successful for user: "+session.User.Email)
+	c.JSON(http.StatusOK, dtos.OIDCLoginResultDTO{
+		Message:   "Login successful",
+		Email:     session.User.Email,
+		Token:     token,
+		SessionID: session.ID,
+		ExpiresAt: session.ExpiresAt,
+	})
+}
@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"net/http"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
@@ -26,31 +28,30 @@ func NewOrderStateTypeController(service *services.OrderStateTypeService, auth *
 // @Produce      json
 // @Param        id   path      int                         true  "Order State Type ID"
 // @Success      200  {object}  models.OrderStateType       "Order state type retrieved successfully"
-// @Failure      403  {object}  models.ErrorResponse        "Access denied"
-// @Failure      404  {object}  models.ErrorResponse        "Order state type not found"
-// @Failure      500  {object}  models.ErrorResponse        "Internal server error"
+// @Failure      403  {object}  models.ProblemDetails        "Access denied"
+// @Failure      404  {object}  models.ProblemDetails        "Order state type not found"
+// @Failure      500  {object}  models.ProblemDetails        "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /order-state-types/{id} [get]
 func (ostc *OrderStateTypeController) GetOrderStateTypeByID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ORDER_STATE_TYPE_BY_ID
 
-	if ostc.Log.RegisterLog(c, "Attempting to get order state type by ID") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ostc.Log.RegisterLog(c, "Attempting to get order state type by ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !ostc.Auth.CheckPermission(c, permissionId) {
 		_ = ostc.Log.RegisterLog(c, "Access denied for GetOrderStateTypeByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	id := c.Param("id")
 
-	orderStateType, err := ostc.Service.GetOrderStateTypeByID(id)
+	orderStateType, err := ostc.Service.GetOrderStateTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = ostc.Log.RegisterLog(c, "Order state type not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order State Type not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -61,34 +62,46 @@ func (ostc *OrderStateTypeController) GetOrderStateTypeByID(c *gin.Context) {
 // GetAllOrderStateTypes godoc
 // @Summary      Get all order state types
 // @Description  Retrieves a list of all available order state types.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         order-state-types
 // @Produce      json
-// @Success      200  {array}   models.OrderStateType       "List of order state types"
-// @Failure      403  {object}  models.ErrorResponse        "Access denied"
-// @Failure      500  {object}  models.ErrorResponse        "Internal server error"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}   dtos.PageDTO{data=[]models.OrderStateType}       "List of order state types"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403  {object}  models.ProblemDetails        "Access denied"
+// @Failure      500  {object}  models.ProblemDetails        "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /order-state-types [get]
 func (ostc *OrderStateTypeController) GetAllOrderStateTypes(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_ORDER_STATE_TYPES
 
-	if ostc.Log.RegisterLog(c, "Attempting to get all order state types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ostc.Log.RegisterLog(c, "Attempting to get all order state types"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !ostc.Auth.CheckPermission(c, permissionId) {
 		_ = ostc.Log.RegisterLog(c, "Access denied for GetAllOrderStateTypes")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
-	orderStateTypes, err := ostc.Service.GetAllOrderStateTypes()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = ostc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	orderStateTypes, page, err := ostc.Service.GetAllOrderStateTypes(c.Request.Context(), query)
 	if err != nil {
 		_ = ostc.Log.RegisterLog(c, "Error retrieving order state types: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Order State Types"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ostc.Log.RegisterLog(c, "Successfully retrieved all order state types")
-	c.JSON(http.StatusOK, orderStateTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(orderStateTypes, page))
 }
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/http"
+
+	"totesbackend/apperrors"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/middleware"
+	"totesbackend/services"
+	"totesbackend/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PasswordController struct {
+	Service *services.PasswordService
+	Log     *utilities.LogUtil
+}
+
+func NewPasswordController(service *services.PasswordService, log *utilities.LogUtil) *PasswordController {
+	return &PasswordController{Service: service, Log: log}
+}
+
+// ChangePassword godoc
+// @Summary      Change the password
+// @Description  Changes the password of the logged-in user after checking the current password.
+// @Description  The new password must satisfy the password policy and must not match a recently used one.
+// @Description  A wrong current password counts as a failed login attempt.
+// @Tags         authentication
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.ChangePasswordDTO  true  "Current and new password"
+// @Success      200   {object}  models.MessageResponse  "Password changed"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      403   {object}  models.ProblemDetails  "Current password is incorrect or account not active"
+// @Failure      422   {object}  models.ProblemDetails  "Password does not meet the policy or was used recently"
+// @Failure      423   {object}  models.ProblemDetails  "Account locked after too many failed attempts"
+// @Failure      429   {object}  models.ProblemDetails  "Too many failed attempts, retry after the indicated wait"
+// @Failure      500   {object}  models.ProblemDetails  "Error changing the password"
+// @Security     ApiKeyAuth
+// @Router       /password/change [post]
+func (pc *PasswordController) ChangePassword(c *gin.Context) {
+	if err := pc.Log.RegisterLog(c, "Attempting to change password"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.ChangePasswordDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid request body for ChangePassword")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	err := pc.Service.ChangePassword(c.Request.Context(), middleware.Caller(c), dto.CurrentPassword, dto.NewPassword, c.ClientIP())
+	if err != nil {
+		_ = pc.Log.RegisterLog(c, "Password change failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Password changed")
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed"})
+}
+
+// ForgotPassword godoc
+// @Summary      Request a password reset
+// @Description  Sends a single-use password reset token to the email address if it belongs to an account.
+// @Description  The response is the same whether or not the account exists.
+// @Tags         authentication
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.ForgotPasswordDTO  true  "Account email"
+// @Success      202   {object}  models.MessageResponse  "Reset requested"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      422   {object}  models.ProblemDetails  "Validation failed"
+// @Failure      500   {object}  models.ProblemDetails  "Error requesting the reset"
+// @Router       /password/forgot [post]
+func (pc *PasswordController) ForgotPassword(c *gin.Context) {
+	if err := pc.Log.RegisterLog(c, "Attempting to request a password reset"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.ForgotPasswordDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid request body for ForgotPassword")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	if err := pc.Service.RequestPasswordReset(c.Request.Context(), dto.Email, c.ClientIP()); err != nil {
+		_ = pc.Log.RegisterLog(c, "Password reset request failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Password reset requested for: "+dto.Email)
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists, a password reset email has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary      Reset the password
+// @Description  Sets a new password using a token received by email. The token can be used only once and expires.
+// @Tags         authentication
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.ResetPasswordDTO  true  "Reset token and new password"
+// @Success      200   {object}  models.MessageResponse  "Password reset"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body or invalid, used or expired token"
+// @Failure      422   {object}  models.ProblemDetails  "Password does not meet the policy or was used recently"
+// @Failure      500   {object}  models.ProblemDetails  "Error resetting the password"
+// @Router       /password/reset [post]
+func (pc *PasswordController) ResetPassword(c *gin.Context) {
+	if err := pc.Log.RegisterLog(c, "Attempting to reset password"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.ResetPasswordDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = pc.Log.RegisterLog(c, "Invalid request body for ResetPassword")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	if err := pc.Service.ResetPassword(c.Request.Context(), dto.Token, dto.NewPassword); err != nil {
+		_ = pc.Log.RegisterLog(c, "Password reset failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = pc.Log.RegisterLog(c, "Password reset")
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset"})
+}
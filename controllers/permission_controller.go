@@ -3,9 +3,12 @@ package controllers
 import (
 	"fmt"
 	"net/http"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -27,18 +30,18 @@ func NewPermissionController(service *services.PermissionService, auth *utilitie
 // @Produce      json
 // @Param        id   path      int                           true  "Permission ID"
 // @Success      200  {object}  models.Permission             "Permission data"
-// @Failure      400  {object}  models.ErrorResponse          "Invalid permission ID"
-// @Failure      403  {object}  models.ErrorResponse          "Access denied"
-// @Failure      404  {object}  models.ErrorResponse          "Permission not found"
-// @Failure      500  {object}  models.ErrorResponse          "Internal server error"
+// @Failure      400  {object}  models.ProblemDetails          "Invalid permission ID"
+// @Failure      403  {object}  models.ProblemDetails          "Access denied"
+// @Failure      404  {object}  models.ProblemDetails          "Permission not found"
+// @Failure      500  {object}  models.ProblemDetails          "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /permissions/{id} [get]
 func (pc *PermissionController) GetPermissionByID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_PERMISSION_BY_ID
 
 	if !pc.Auth.CheckPermission(c, permissionId) {
-		if pc.Log.RegisterLog(c, "Access denied for GetPermissionByID") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "Access denied for GetPermissionByID"); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
 		return
@@ -47,31 +50,31 @@ func (pc *PermissionController) GetPermissionByID(c *gin.Context) {
 	idParam := c.Param("id")
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
-		if pc.Log.RegisterLog(c, "Invalid permission ID: "+idParam) != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "Invalid permission ID: "+idParam); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid permission ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Attempting to retrieve Permission with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := pc.Log.RegisterLog(c, "Attempting to retrieve Permission with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	permission, err := pc.Service.GetPermissionByID(id)
+	permission, err := pc.Service.GetPermissionByID(c.Request.Context(), id)
 	if err != nil {
-		if pc.Log.RegisterLog(c, "Permission with ID "+idParam+" not found") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "Permission with ID "+idParam+" not found"); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
-		c.JSON(http.StatusNotFound, gin.H{"error": "Permission not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Successfully retrieved Permission with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := pc.Log.RegisterLog(c, "Successfully retrieved Permission with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -81,45 +84,58 @@ func (pc *PermissionController) GetPermissionByID(c *gin.Context) {
 // GetAllPermissions godoc
 // @Summary      Get all permissions
 // @Description  Retrieves a list of all permissions available in the system.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         permissions
 // @Produce      json
-// @Success      200  {array}   models.Permission             "List of permissions"
-// @Failure      403  {object}  models.ErrorResponse          "Access denied"
-// @Failure      500  {object}  models.ErrorResponse          "Internal server error"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}   dtos.PageDTO{data=[]models.Permission}             "List of permissions"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403  {object}  models.ProblemDetails          "Access denied"
+// @Failure      500  {object}  models.ProblemDetails          "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /permissions [get]
 func (pc *PermissionController) GetAllPermissions(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_PERMISSIONS
 
 	if !pc.Auth.CheckPermission(c, permissionId) {
-		if pc.Log.RegisterLog(c, "Access denied for GetAllPermissions") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "Access denied for GetAllPermissions"); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Attempting to retrieve all permissions") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := pc.Log.RegisterLog(c, "Attempting to retrieve all permissions"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	permissions, err := pc.Service.GetAllPermissions()
+	query, err := utilities.ParseListQuery(c)
 	if err != nil {
-		if pc.Log.RegisterLog(c, "Error retrieving all permissions: "+err.Error()) != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = pc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	permissions, page, err := pc.Service.GetAllPermissions(c.Request.Context(), query)
+	if err != nil {
+		if err := pc.Log.RegisterLog(c, "Error retrieving all permissions: "+err.Error()); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving permissions"})
+		_ = c.Error(err)
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Successfully retrieved all permissions") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := pc.Log.RegisterLog(c, "Successfully retrieved all permissions"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, permissions)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(permissions, page))
 }
 
 // SearchPermissionsByID godoc
@@ -129,17 +145,17 @@ func (pc *PermissionController) GetAllPermissions(c *gin.Context) {
 // @Produce      json
 // @Param        id   query     string  true  "ID to search for (partial or full match)"
 // @Success      200  {array}   models.Permission             "List of matching permissions"
-// @Failure      400  {object}  models.ErrorResponse          "Missing or invalid query parameter"
-// @Failure      403  {object}  models.ErrorResponse          "Access denied"
-// @Failure      500  {object}  models.ErrorResponse          "Internal server error"
+// @Failure      403  {object}  models.ProblemDetails          "Access denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails          "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /permissions/searchByID [get]
 func (pc *PermissionController) SearchPermissionsByID(c *gin.Context) {
 	permissionId := config.PERMISSION_SEARCH_PERMISSION_BY_ID
 
 	if !pc.Auth.CheckPermission(c, permissionId) {
-		if pc.Log.RegisterLog(c, "Access denied for SearchPermissionsByID") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "Access denied for SearchPermissionsByID"); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
 		return
@@ -147,31 +163,31 @@ func (pc *PermissionController) SearchPermissionsByID(c *gin.Context) {
 
 	query := c.Query("id")
 	if query == "" {
-		if pc.Log.RegisterLog(c, "SearchPermissionsByID: missing 'id' query parameter") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "SearchPermissionsByID: missing 'id' query parameter"); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		_ = c.Error(validation.ParamError("id", "required", "", "is required"))
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Attempting to search permissions by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := pc.Log.RegisterLog(c, "Attempting to search permissions by ID: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	permissions, err := pc.Service.SearchPermissionsByID(query)
+	permissions, err := pc.Service.SearchPermissionsByID(c.Request.Context(), query)
 	if err != nil {
-		if pc.Log.RegisterLog(c, "Error retrieving permissions by ID: "+err.Error()) != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "Error retrieving permissions by ID: "+err.Error()); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving permissions"})
+		_ = c.Error(err)
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Successfully retrieved permissions by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := pc.Log.RegisterLog(c, "Successfully retrieved permissions by ID: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -185,17 +201,17 @@ func (pc *PermissionController) SearchPermissionsByID(c *gin.Context) {
 // @Produce      json
 // @Param        name   query     string  true  "Name to search for (partial or full match)"
 // @Success      200    {array}   models.Permission             "List of matching permissions"
-// @Failure      400    {object}  models.ErrorResponse          "Missing or invalid query parameter"
-// @Failure      403    {object}  models.ErrorResponse          "Access denied"
-// @Failure      500    {object}  models.ErrorResponse          "Internal server error"
+// @Failure      403    {object}  models.ProblemDetails          "Access denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500    {object}  models.ProblemDetails          "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /permissions/searchByName [get]
 func (pc *PermissionController) SearchPermissionsByName(c *gin.Context) {
 	permissionId := config.PERMISSION_SEARCH_PERMISSION_BY_NAME
 
 	if !pc.Auth.CheckPermission(c, permissionId) {
-		if pc.Log.RegisterLog(c, "Access denied for SearchPermissionsByName") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "Access denied for SearchPermissionsByName"); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
 		return
@@ -203,31 +219,31 @@ func (pc *PermissionController) SearchPermissionsByName(c *gin.Context) {
 
 	query := c.Query("name")
 	if query == "" {
-		if pc.Log.RegisterLog(c, "SearchPermissionsByName: missing 'name' query parameter") != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "SearchPermissionsByName: missing 'name' query parameter"); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query is required"})
+		_ = c.Error(validation.ParamError("name", "required", "", "is required"))
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Attempting to search permissions by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := pc.Log.RegisterLog(c, "Attempting to search permissions by name: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	permissions, err := pc.Service.SearchPermissionsByName(query)
+	permissions, err := pc.Service.SearchPermissionsByName(c.Request.Context(), query)
 	if err != nil {
-		if pc.Log.RegisterLog(c, "Error retrieving permissions by name: "+err.Error()) != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		if err := pc.Log.RegisterLog(c, "Error retrieving permissions by name: "+err.Error()); err != nil {
+			_ = c.Error(apperrors.ErrInternal.Wrap(err))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving permissions"})
+		_ = c.Error(err)
 		return
 	}
 
-	if pc.Log.RegisterLog(c, "Successfully retrieved permissions by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := pc.Log.RegisterLog(c, "Successfully retrieved permissions by name: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/services"
+	"totesbackend/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PriceListController struct {
+	Service *services.PriceListService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewPriceListController(service *services.PriceListService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *PriceListController {
+	return &PriceListController{Service: service, Auth: auth, Log: log}
+}
+
+// GetPriceListByID godoc
+// @Summary      Get a price list by ID
+// @Description  Retrieves a price list with its fixed item prices, percentage rules and assigned customers.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id  path     string  true  "Price List ID"
+// @Success      200 {object} dtos.GetPriceListDTO "The requested price list"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Price list not found"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving price list"
+// @Security     ApiKeyAuth
+// @Router       /price-lists/{id} [get]
+func (plc *PriceListController) GetPriceListByID(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := plc.Log.RegisterLog(c, "Attempting to retrieve price list with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_GET_PRICE_LIST_BY_ID
+	if !plc.Auth.CheckPermission(c, permissionId) {
+		_ = plc.Log.RegisterLog(c, "Access denied for GetPriceListByID")
+		return
+	}
+
+	priceList, err := plc.Service.GetPriceListByID(c.Request.Context(), id)
+	if err != nil {
+		_ = plc.Log.RegisterLog(c, "Error retrieving price list with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = plc.Log.RegisterLog(c, "Successfully retrieved price list with ID: "+id)
+	c.JSON(http.StatusOK, toPriceListDTO(priceList))
+}
+
+// GetAllPriceLists godoc
+// @Summary      Get all price lists
+// @Description  Retrieves all price lists ordered by priority.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200 {object}  dtos.PageDTO{data=[]dtos.GetPriceListDTO} "List of price lists"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving price lists"
+// @Security     ApiKeyAuth
+// @Router       /price-lists [get]
+func (plc *PriceListController) GetAllPriceLists(c *gin.Context) {
+	if err := plc.Log.RegisterLog(c, "Attempting to retrieve all price lists"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_GET_ALL_PRICE_LISTS
+	if !plc.Auth.CheckPermission(c, permissionId) {
+		_ = plc.Log.RegisterLog(c, "Access denied for GetAllPriceLists")
+		return
+	}
+
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = plc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	priceLists, page, err := plc.Service.GetAllPriceLists(c.Request.Context(), query)
+	if err != nil {
+		_ = plc.Log.RegisterLog(c, "Error retrieving price lists: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = plc.Log.RegisterLog(c, "Successfully retrieved all price lists")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(toPriceListDTOs(priceLists), page))
+}
+
+// GetPriceListsForCustomer godoc
+// @Summary      Get the price lists that apply to a customer
+// @Description  Retrieves the active price lists that apply to a customer, in the order they are evaluated when billing:
+// @Description  lists assigned directly to the customer first, then the lists of the customer's segment, each by priority.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        customerID  path     int  true  "Customer ID"
+// @Success      200 {array}  dtos.GetPriceListDTO "Applicable price lists"
+// @Failure      400 {object} models.ProblemDetails "Invalid customer ID"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Customer not found"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving price lists"
+// @Security     ApiKeyAuth
+// @Router       /price-lists/customer/{customerID} [get]
+func (plc *PriceListController) GetPriceListsForCustomer(c *gin.Context) {
+	if err := plc.Log.RegisterLog(c, "Attempting to retrieve price lists by customer ID"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_GET_CUSTOMER_PRICE_LISTS
+	if !plc.Auth.CheckPermission(c, permissionId) {
+		_ = plc.Log.RegisterLog(c, "Access denied for GetPriceListsForCustomer")
+		return
+	}
+
+	customerID, err := strconv.Atoi(c.Param("customerID"))
+	if err != nil {
+		_ = plc.Log.RegisterLog(c, "Invalid customer ID provided")
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("customerID", c.Param("customerID")))
+		return
+	}
+
+	priceLists, err := plc.Service.GetPriceListsForCustomer(c.Request.Context(), customerID)
+	if err != nil {
+		_ = plc.Log.RegisterLog(c, "Error retrieving price lists by customer ID: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = plc.Log.RegisterLog(c, "Price lists retrieved successfully by customer ID")
+	c.JSON(http.StatusOK, toPriceListDTOs(priceLists))
+}
+
+// CreatePriceList godoc
+// @Summary      Create a price list
+// @Description  Creates a price list for a customer segment ("individual" or "business") and/or specific customers.
+// @Description  A list can fix the price of specific items and define percentage rules over the list price,
+// @Description  either for an item type or for every item (rule without item_type_id).
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        priceList body     dtos.CreatePriceListDTO true "Price list data"
+// @Success      201 {object} dtos.GetPriceListDTO "Price list created"
+// @Failure      400 {object} models.ProblemDetails "Invalid input"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      500 {object} models.ProblemDetails "Error creating price list"
+// @Security     ApiKeyAuth
+// @Router       /price-lists [post]
+func (plc *PriceListController) CreatePriceList(c *gin.Context) {
+	if err := plc.Log.RegisterLog(c, "Attempting to create a new price list"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_CREATE_PRICE_LIST
+	if !plc.Auth.CheckPermission(c, permissionId) {
+		_ = plc.Log.RegisterLog(c, "Access denied for CreatePriceList")
+		return
+	}
+
+	var dto dtos.CreatePriceListDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = plc.Log.RegisterLog(c, "Invalid input for price list creation: "+err.Error())
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	priceList, err := plc.Service.CreatePriceList(c.Request.Context(), &dto)
+	if err != nil {
+		_ = plc.Log.RegisterLog(c, "Failed to create price list: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = plc.Log.RegisterLog(c, "Successfully created price list with ID: "+strconv.Itoa(priceList.ID))
+	c.JSON(http.StatusCreated, toPriceListDTO(priceList))
+}
+
+// UpdatePriceList godoc
+// @Summary      Update a price list
+// @Description  Replaces the data, item prices, rules and assigned customers of a price list.
+// @Tags         price-lists
+// @Accept       json
+// @Produce      json
+// @Param        id        path     string                  true "Price List ID"
+// @Param        priceList body     dtos.CreatePriceListDTO true "Price list data"
+// @Success      200 {object} dtos.GetPriceListDTO "Price list updated"
+// @Failure      400 {object} models.ProblemDetails "Invalid input"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Price list not found"
+// @Failure      500 {object} models.ProblemDetails "Error updating price list"
+// @Security     ApiKeyAuth
+// @Router       /price-lists/{id} [put]
+func (plc *PriceListController) UpdatePriceList(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := plc.Log.RegisterLog(c, "Attempting to update price list with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_UPDATE_PRICE_LIST
+	if !plc.Auth.CheckPermission(c, permissionId) {
+		_ = plc.Log.RegisterLog(c, "Access denied for UpdatePriceList")
+		return
+	}
+
+	var dto dtos.CreatePriceListDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = plc.Log.RegisterLog(c, "Invalid input for price list update: "+err.Error())
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	priceList, err := plc.Service.UpdatePriceList(c.Request.Context(), id, &dto)
+	if err != nil {
+		_ = plc.Log.RegisterLog(c, "Failed to update price list with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = plc.Log.RegisterLog(c, "Successfully updated price list with ID: "+id)
+	c.JSON(http.StatusOK, toPriceListDTO(priceList))
+}
+
+func toPriceListDTOs(priceLists []models.PriceList) []dtos.GetPriceListDTO {
+	result := make([]dtos.GetPriceListDTO, 0, len(priceLists))
+	for i := range priceLists {
+		result = append(result, toPriceListDTO(&priceLists[i]))
+	}
+	return result
+}
+
+func toPriceListDTO(priceList *models.PriceList) dtos.GetPriceListDTO {
+	dto := dtos.GetPriceListDTO{
+		ID:          priceList.ID,
+		Name:        priceList.Name,
+		Description: priceList.Description,
+		Segment:     priceList.Segment,
+		Priority:    priceList.Priority,
+		Active:      priceList.Active,
+		Items:       []dtos.PriceListItemDTO{},
+		Rules:       []dtos.PriceListRuleDTO{},
+		CustomerIDs: []int{},
+	}
+	for _, item := range priceList.Items {
+		dto.Items = append(dto.Items, dtos.PriceListItemDTO{ItemID: item.ItemID, Price: item.Price})
+	}
+	for _, rule := range priceList.Rules {
+		dto.Rules = append(dto.Rules, dtos.PriceListRuleDTO{ItemTypeID: rule.ItemTypeID, Percentage: rule.Percentage})
+	}
+	for _, customer := range priceList.Customers {
+		dto.CustomerIDs = append(dto.CustomerIDs, customer.ID)
+	}
+	return dto
+}
@@ -4,11 +4,13 @@ import (
 	"net/http"
 	"strconv"
 
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,24 +32,31 @@ func NewPurchaseOrderController(service *services.PurchaseOrderService, auth *ut
 // @Produce      json
 // @Param        id   path     string  true  "Purchase Order ID"
 // @Success      200  {object}  dtos.GetPurchaseOrderDTO    "Purchase Order details"
-// @Failure      400  {object}  models.ErrorResponse       "Invalid ID format"
-// @Failure      404  {object}  models.ErrorResponse       "Purchase Order not found"
-// @Failure      500  {object}  models.ErrorResponse       "Internal server error"
+// @Failure      400  {object}  models.ProblemDetails       "Invalid ID format"
+// @Failure      403  {object}  models.ProblemDetails      "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails       "Purchase Order not found"
+// @Failure      500  {object}  models.ProblemDetails       "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/{id} [get]
 func (poc *PurchaseOrderController) GetPurchaseOrderByID(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_PURCHASE_ORDER_BY_ID
 
 	if err := poc.Log.RegisterLog(c, "Attempting to retrieve Purchase Order by ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !poc.Auth.CheckPermission(c, permissionId) {
+		_ = poc.Log.RegisterLog(c, "Permission denied for GetPurchaseOrderByID")
 		return
 	}
 
 	id := c.Param("id")
 
-	purchaseOrder, err := poc.Service.GetPurchaseOrderByID(id)
+	purchaseOrder, err := poc.Service.GetPurchaseOrderByID(c.Request.Context(), id)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Purchase Order not found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Order not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -77,42 +86,34 @@ func (poc *PurchaseOrderController) GetPurchaseOrderByID(c *gin.Context) {
 // @Produce      json
 // @Param        stateID  path     string  true  "State ID"
 // @Success      200      {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders"
-// @Failure      400      {object} models.ErrorResponse     "Invalid State ID format"
-// @Failure      403      {object} models.ErrorResponse     "Permission denied"
-// @Failure      404      {object} models.ErrorResponse     "Purchase Orders not found"
-// @Failure      500      {object} models.ErrorResponse     "Internal server error"
+// @Failure      400      {object} models.ProblemDetails    "Invalid State ID format"
+// @Failure      403      {object} models.ProblemDetails    "Permission denied"
+// @Failure      500      {object} models.ProblemDetails    "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/state/{stateID} [get]
 func (poc *PurchaseOrderController) GetPurchaseOrdersByStateID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_PURCHASE_ORDERS_BY_STATE_ID
 
 	if err := poc.Log.RegisterLog(c, "Attempting to retrieve Purchase Orders by State ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for GetPurchaseOrdersByStateID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
 	stateID := c.Param("stateID")
 
-	purchaseOrders, err := poc.Service.GetPurchaseOrdersByStateID(stateID)
+	purchaseOrders, err := poc.Service.GetPurchaseOrdersByStateID(c.Request.Context(), stateID)
 	if err != nil {
-		_ = poc.Log.RegisterLog(c, "Purchase Orders not found for State ID: "+stateID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
-		return
-	}
-
-	if len(purchaseOrders) == 0 {
-		_ = poc.Log.RegisterLog(c, "No Purchase Orders found for State ID: "+stateID)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No purchase orders found"})
+		_ = poc.Log.RegisterLog(c, "Error retrieving Purchase Orders for State ID: "+stateID+": "+err.Error())
+		_ = c.Error(err)
 		return
 	}
 
-	var purchaseOrderDTOs []dtos.GetPurchaseOrderDTO
+	purchaseOrderDTOs := make([]dtos.GetPurchaseOrderDTO, 0, len(purchaseOrders))
 	for _, purchaseOrder := range purchaseOrders {
 		purchaseOrderDTOs = append(purchaseOrderDTOs, dtos.GetPurchaseOrderDTO{
 			ID:            purchaseOrder.ID,
@@ -137,36 +138,48 @@ func (poc *PurchaseOrderController) GetPurchaseOrdersByStateID(c *gin.Context) {
 // GetAllPurchaseOrders godoc
 // @Summary      Get all purchase orders
 // @Description  Retrieves all purchase orders from the system.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         purchase_orders
 // @Produce      json
-// @Success      200      {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders"
-// @Failure      403      {object} models.ErrorResponse     "Permission denied"
-// @Failure      404      {object} models.ErrorResponse     "Purchase Orders not found"
-// @Failure      500      {object} models.ErrorResponse     "Internal server error"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200      {object}  dtos.PageDTO{data=[]dtos.GetPurchaseOrderDTO}  "List of Purchase Orders"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403      {object} models.ProblemDetails     "Permission denied"
+// @Failure      404      {object} models.ProblemDetails     "Purchase Orders not found"
+// @Failure      500      {object} models.ProblemDetails     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders [get]
 func (poc *PurchaseOrderController) GetAllPurchaseOrders(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_PURCHASE_ORDERS
 
 	if err := poc.Log.RegisterLog(c, "Attempting to retrieve all Purchase Orders"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for GetAllPurchaseOrders")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
-	purchaseOrders, err := poc.Service.GetAllPurchaseOrders()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = poc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	purchaseOrders, page, err := poc.Service.GetAllPurchaseOrders(c.Request.Context(), query)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error retrieving all Purchase Orders")
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	var purchaseOrderDTOs []dtos.GetPurchaseOrderDTO
+	purchaseOrderDTOs := make([]dtos.GetPurchaseOrderDTO, 0, len(purchaseOrders))
 	for _, purchaseOrder := range purchaseOrders {
 		purchaseOrderDTOs = append(purchaseOrderDTOs, dtos.GetPurchaseOrderDTO{
 			ID:            purchaseOrder.ID,
@@ -185,7 +198,7 @@ func (poc *PurchaseOrderController) GetAllPurchaseOrders(c *gin.Context) {
 
 	_ = poc.Log.RegisterLog(c, "Successfully retrieved all Purchase Orders")
 
-	c.JSON(http.StatusOK, purchaseOrderDTOs)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(purchaseOrderDTOs, page))
 }
 
 // SearchPurchaseOrdersByID godoc
@@ -195,43 +208,42 @@ func (poc *PurchaseOrderController) GetAllPurchaseOrders(c *gin.Context) {
 // @Produce      json
 // @Param        id  query     string  true  "Purchase Order ID"
 // @Success      200  {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders"
-// @Failure      400  {object} models.ErrorResponse     "Missing 'id' query parameter"
-// @Failure      403  {object} models.ErrorResponse     "Permission denied"
-// @Failure      404  {object} models.ErrorResponse     "Purchase Orders not found"
-// @Failure      500  {object} models.ErrorResponse     "Internal server error"
+// @Failure      403  {object} models.ProblemDetails     "Permission denied"
+// @Failure      404  {object} models.ProblemDetails     "Purchase Orders not found"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object} models.ProblemDetails     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/searchByID [get]
 func (poc *PurchaseOrderController) SearchPurchaseOrdersByID(c *gin.Context) {
 	permissionId := config.PERMISSION_SEARCH_PURCHASE_ORDERS_BY_ID
 
 	if err := poc.Log.RegisterLog(c, "Attempting to search Purchase Orders by ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for SearchPurchaseOrdersByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
 	id := c.Query("id")
 	if id == "" {
 		_ = poc.Log.RegisterLog(c, "Missing 'id' query parameter in SearchPurchaseOrdersByID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter is required"})
+		_ = c.Error(validation.ParamError("id", "required", "", "is required"))
 		return
 	}
 
-	purchaseOrders, err := poc.Service.SearchPurchaseOrdersByID(id)
+	purchaseOrders, err := poc.Service.SearchPurchaseOrdersByID(c.Request.Context(), id)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error retrieving Purchase Orders with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(purchaseOrders) == 0 {
 		_ = poc.Log.RegisterLog(c, "No Purchase Orders found with ID: "+id)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No purchase orders found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -263,37 +275,36 @@ func (poc *PurchaseOrderController) SearchPurchaseOrdersByID(c *gin.Context) {
 // @Produce      json
 // @Param        customerID  path     string  true  "Customer ID"
 // @Success      200         {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders for the specified Customer ID"
-// @Failure      403         {object} models.ErrorResponse     "Permission denied"
-// @Failure      404         {object} models.ErrorResponse     "Purchase Orders not found for the specified Customer ID"
-// @Failure      500         {object} models.ErrorResponse     "Internal server error"
+// @Failure      403         {object} models.ProblemDetails     "Permission denied"
+// @Failure      404         {object} models.ProblemDetails     "Purchase Orders not found for the specified Customer ID"
+// @Failure      500         {object} models.ProblemDetails     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/customers/{customerID} [get]
 func (poc *PurchaseOrderController) GetPurchaseOrdersByCustomerID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_PURCHASE_ORDERS_BY_CUSTOMER_ID
 
 	if err := poc.Log.RegisterLog(c, "Attempting to retrieve Purchase Orders by Customer ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for GetPurchaseOrdersByCustomerID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
 	customerID := c.Param("customerID")
 
-	purchaseOrders, err := poc.Service.GetPurchaseOrdersByCustomerID(customerID)
+	purchaseOrders, err := poc.Service.GetPurchaseOrdersByCustomerID(c.Request.Context(), customerID)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error retrieving Purchase Orders for Customer ID: "+customerID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(purchaseOrders) == 0 {
 		_ = poc.Log.RegisterLog(c, "No Purchase Orders found for Customer ID: "+customerID)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No purchase orders found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -325,31 +336,30 @@ func (poc *PurchaseOrderController) GetPurchaseOrdersByCustomerID(c *gin.Context
 // @Produce      json
 // @Param        sellerID  path     string  true  "Seller ID"
 // @Success      200       {array}  dtos.GetPurchaseOrderDTO  "List of Purchase Orders for the specified Seller ID"
-// @Failure      403       {object} models.ErrorResponse     "Permission denied"
-// @Failure      404       {object} models.ErrorResponse     "Purchase Orders not found for the specified Seller ID"
-// @Failure      500       {object} models.ErrorResponse     "Internal server error"
+// @Failure      403       {object} models.ProblemDetails     "Permission denied"
+// @Failure      404       {object} models.ProblemDetails     "Purchase Orders not found for the specified Seller ID"
+// @Failure      500       {object} models.ProblemDetails     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/seller/{sellerID} [get]
 func (poc *PurchaseOrderController) GetPurchaseOrdersBySellerID(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_PURCHASE_ORDERS_BY_SELLER_ID
 
 	if err := poc.Log.RegisterLog(c, "Attempting to retrieve Purchase Orders by Seller ID"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for GetPurchaseOrdersBySellerID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
 	sellerID := c.Param("sellerID")
 
-	purchaseOrders, err := poc.Service.GetPurchaseOrdersBySellerID(sellerID)
+	purchaseOrders, err := poc.Service.GetPurchaseOrdersBySellerID(c.Request.Context(), sellerID)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error retrieving Purchase Orders for Seller ID: "+sellerID)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Purchase Orders not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -382,23 +392,23 @@ func (poc *PurchaseOrderController) GetPurchaseOrdersBySellerID(c *gin.Context)
 // @Param        id            path     string  true  "Purchase Order ID"
 // @Param        order_state_id  body     int     true  "Order State ID"
 // @Success      200       {object}  models.MessageResponse  "Updated Purchase Order and associated Invoice"
-// @Failure      400       {object}  models.ErrorResponse     "Invalid request body"
-// @Failure      403       {object}  models.ErrorResponse     "Permission denied"
-// @Failure      404       {object}  models.ErrorResponse     "Purchase Order not found"
-// @Failure      500       {object}  models.ErrorResponse     "Internal server error"
+// @Failure      400       {object}  models.ProblemDetails     "Invalid request body"
+// @Failure      422       {object}  models.ProblemDetails     "Validation failed"
+// @Failure      403       {object}  models.ProblemDetails     "Permission denied"
+// @Failure      404       {object}  models.ProblemDetails     "Purchase Order not found"
+// @Failure      500       {object}  models.ProblemDetails     "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders/{id}/state [patch]
 func (poc *PurchaseOrderController) ChangePurchaseOrderState(c *gin.Context) {
 	permissionId := config.PERMISSION_UPDATE_PURCHASE_ORDER_STATE
 
 	if err := poc.Log.RegisterLog(c, "Attempting to update Purchase Order state"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for UpdatePurchaseOrderState")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
@@ -410,16 +420,16 @@ func (poc *PurchaseOrderController) ChangePurchaseOrderState(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&request); err != nil {
 		_ = poc.Log.RegisterLog(c, "Error binding JSON for UpdatePurchaseOrderState: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
 	orderStateIDStr := strconv.Itoa(request.OrderStateID)
 
-	purchaseOrder, invoice, err := poc.Service.ChangePurchaseOrderState(id, orderStateIDStr)
+	purchaseOrder, invoice, err := poc.Service.ChangePurchaseOrderState(c.Request.Context(), id, orderStateIDStr)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -456,7 +466,7 @@ func (poc *PurchaseOrderController) ChangePurchaseOrderState(c *gin.Context) {
 	_ = poc.Log.RegisterLog(c, "Successfully updated Purchase Order state with ID: "+id)
 
 	// Enviar ambos DTOs como JSON
-	c.JSON(http.StatusOK, gin.H{
+	poc.Auth.JSON(c, http.StatusOK, gin.H{
 		"purchase_order": purchaseOrderDTO,
 		"invoice":        invoiceDTO,
 	})
@@ -469,22 +479,22 @@ func (poc *PurchaseOrderController) ChangePurchaseOrderState(c *gin.Context) {
 // @Produce      json
 // @Param        purchase_order  body     dtos.CreatePurchaseOrderDTO  true  "Purchase Order details"
 // @Success      201       {object}  dtos.GetPurchaseOrderDTO     "Created Purchase Order"
-// @Failure      400       {object}  models.ErrorResponse        "Invalid request data"
-// @Failure      403       {object}  models.ErrorResponse        "Permission denied"
-// @Failure      500       {object}  models.ErrorResponse        "Internal server error"
+// @Failure      400       {object}  models.ProblemDetails        "Invalid request data"
+// @Failure      422       {object}  models.ProblemDetails        "Validation failed"
+// @Failure      403       {object}  models.ProblemDetails        "Permission denied"
+// @Failure      500       {object}  models.ProblemDetails        "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /purchase-orders [post]
 func (poc *PurchaseOrderController) CreatePurchaseOrder(c *gin.Context) {
 	permissionId := config.PERMISSION_CREATE_PURCHASE_ORDER
 
 	if err := poc.Log.RegisterLog(c, "Attempting to create a new Purchase Order"); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !poc.Auth.CheckPermission(c, permissionId) {
 		_ = poc.Log.RegisterLog(c, "Permission denied for CreatePurchaseOrder")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 		return
 	}
 
@@ -492,14 +502,14 @@ func (poc *PurchaseOrderController) CreatePurchaseOrder(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = poc.Log.RegisterLog(c, "Invalid request data for CreatePurchaseOrder: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request data"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	purchaseOrder, err := poc.Service.CreatePurchaseOrder(&dto)
+	purchaseOrder, err := poc.Service.CreatePurchaseOrder(c.Request.Context(), &dto)
 	if err != nil {
 		_ = poc.Log.RegisterLog(c, "Error creating Purchase Order: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -518,8 +528,9 @@ func extractPurchaseOrderBillingItems(items []models.PurchaseOrderItem) []dtos.B
 	var billingItems []dtos.BillingItemDTO
 	for _, item := range items {
 		billingItems = append(billingItems, dtos.BillingItemDTO{
-			ID:    item.ItemID,
-			Stock: item.Amount,
+			ID:        item.ItemID,
+			Stock:     item.Amount,
+			UnitPrice: item.UnitPrice,
 		})
 	}
 	return billingItems
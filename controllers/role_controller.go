@@ -3,10 +3,13 @@ package controllers
 import (
 	"fmt"
 	"net/http"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
+	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,15 +26,15 @@ func NewRoleController(service *services.RoleService, auth *utilities.Authorizat
 
 // GetRoleByID godoc
 // @Summary      Get a role by ID
-// @Description  Retrieve a role's details by its ID, including its permissions.
+// @Description  Retrieve a role's details by its ID, including its permissions and data scope rules.
 // @Tags         roles
 // @Produce      json
 // @Param        id  path     int  true  "Role ID"
 // @Success      200  {object}  dtos.RoleDTO  "Role details with permissions"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid role ID"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "Role not found"
-// @Failure      500  {object}  models.ErrorResponse  "Internal server error"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid role ID"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "Role not found"
+// @Failure      500  {object}  models.ProblemDetails  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /roles/{id} [get]
 func (rc *RoleController) GetRoleByID(c *gin.Context) {
@@ -44,29 +47,29 @@ func (rc *RoleController) GetRoleByID(c *gin.Context) {
 
 	idParam := c.Param("id")
 
-	if rc.Log.RegisterLog(c, "Attempting to retrieve role with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := rc.Log.RegisterLog(c, "Attempting to retrieve role with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		_ = rc.Log.RegisterLog(c, "Invalid role ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	role, err := rc.Service.GetRoleByID(id)
+	role, err := rc.Service.GetRoleByID(c.Request.Context(), id)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Role not found with ID: "+idParam)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Role not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	permissionIDs, err := rc.Service.GetRolePermissions(id)
+	permissionIDs, err := rc.Service.GetRolePermissions(c.Request.Context(), id)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error retrieving role permissions for ID: "+idParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving role permissions"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -75,6 +78,7 @@ func (rc *RoleController) GetRoleByID(c *gin.Context) {
 		Name:        role.Name,
 		Description: role.Description,
 		Permissions: make([]string, len(permissionIDs)),
+		Scopes:      roleScopeDTOs(role.Scopes),
 	}
 
 	for i, permissionID := range permissionIDs {
@@ -88,11 +92,17 @@ func (rc *RoleController) GetRoleByID(c *gin.Context) {
 // GetAllRoles godoc
 // @Summary      Get all roles
 // @Description  Retrieve a list of all roles, including their associated permissions.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         roles
 // @Produce      json
-// @Success      200  {array}  dtos.RoleDTO  "List of roles with permissions"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving roles"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}  dtos.PageDTO{data=[]dtos.RoleDTO}  "List of roles with permissions"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving roles"
 // @Security     ApiKeyAuth
 // @Router       /roles [get]
 func (rc *RoleController) GetAllRoles(c *gin.Context) {
@@ -103,24 +113,31 @@ func (rc *RoleController) GetAllRoles(c *gin.Context) {
 		return
 	}
 
-	if rc.Log.RegisterLog(c, "Attempting to retrieve all roles") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := rc.Log.RegisterLog(c, "Attempting to retrieve all roles"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	roles, err := rc.Service.GetAllRoles()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	roles, page, err := rc.Service.GetAllRoles(c.Request.Context(), query)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error retrieving roles")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving roles"})
+		_ = c.Error(err)
 		return
 	}
 
-	var rolesDTO []dtos.RoleDTO
+	rolesDTO := make([]dtos.RoleDTO, 0, len(roles))
 	for _, role := range roles {
-		permissionIDs, err := rc.Service.GetRolePermissions(role.ID)
+		permissionIDs, err := rc.Service.GetRolePermissions(c.Request.Context(), role.ID)
 		if err != nil {
 			_ = rc.Log.RegisterLog(c, "Error retrieving permissions for role ID: "+fmt.Sprintf("%d", role.ID))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving role permissions"})
+			_ = c.Error(err)
 			return
 		}
 
@@ -129,6 +146,7 @@ func (rc *RoleController) GetAllRoles(c *gin.Context) {
 			Name:        role.Name,
 			Description: role.Description,
 			Permissions: make([]string, len(permissionIDs)),
+			Scopes:      roleScopeDTOs(role.Scopes),
 		}
 
 		for i, permissionID := range permissionIDs {
@@ -139,7 +157,7 @@ func (rc *RoleController) GetAllRoles(c *gin.Context) {
 	}
 
 	_ = rc.Log.RegisterLog(c, "Successfully retrieved all roles")
-	c.JSON(http.StatusOK, rolesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(rolesDTO, page))
 }
 
 // GetAllPermissionsOfRole godoc
@@ -149,9 +167,9 @@ func (rc *RoleController) GetAllRoles(c *gin.Context) {
 // @Produce      json
 // @Param        id  path  int  true  "Role ID"
 // @Success      200  {array}  string  "List of permissions for the role"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid role ID"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving permissions for role"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid role ID"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving permissions for role"
 // @Security     ApiKeyAuth
 // @Router       /roles/{id}/permission [get]
 func (rc *RoleController) GetAllPermissionsOfRole(c *gin.Context) {
@@ -164,22 +182,22 @@ func (rc *RoleController) GetAllPermissionsOfRole(c *gin.Context) {
 
 	roleIDParam := c.Param("id")
 
-	if rc.Log.RegisterLog(c, "Attempting to retrieve permissions for role ID: "+roleIDParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := rc.Log.RegisterLog(c, "Attempting to retrieve permissions for role ID: "+roleIDParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	var roleID uint
 	if _, err := fmt.Sscanf(roleIDParam, "%d", &roleID); err != nil {
 		_ = rc.Log.RegisterLog(c, "Invalid role ID format: "+roleIDParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	permissions, err := rc.Service.GetAllPermissionsOfRole(roleID)
+	permissions, err := rc.Service.GetAllPermissionsOfRole(c.Request.Context(), roleID)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error retrieving permissions for role ID: "+roleIDParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving permissions for role"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -194,9 +212,9 @@ func (rc *RoleController) GetAllPermissionsOfRole(c *gin.Context) {
 // @Produce      json
 // @Param        id  path  int  true  "Role ID"
 // @Success      200  {object}  models.MessageResponse  "Returns a boolean indicating if the role exists"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid role ID"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error checking role existence"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid role ID"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error checking role existence"
 // @Security     ApiKeyAuth
 // @Router       /roles/{id}/exist [get]
 func (rc *RoleController) ExistRole(c *gin.Context) {
@@ -209,22 +227,22 @@ func (rc *RoleController) ExistRole(c *gin.Context) {
 
 	idParam := c.Param("id")
 
-	if rc.Log.RegisterLog(c, "Attempting to check existence of role with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := rc.Log.RegisterLog(c, "Attempting to check existence of role with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		_ = rc.Log.RegisterLog(c, "Invalid role ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	exists, err := rc.Service.ExistRole(id)
+	exists, err := rc.Service.ExistRole(c.Request.Context(), id)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error checking existence of role ID: "+idParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking role existence"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -239,9 +257,9 @@ func (rc *RoleController) ExistRole(c *gin.Context) {
 // @Produce      json
 // @Param        id  query  string  true  "Role ID"
 // @Success      200  {array}  models.Role  "Returns the roles matching the search criteria"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid query parameter"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error searching roles by ID"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid query parameter"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error searching roles by ID"
 // @Security     ApiKeyAuth
 // @Router       /roles/searchByID [get]
 func (rc *RoleController) SearchRolesByID(c *gin.Context) {
@@ -254,15 +272,15 @@ func (rc *RoleController) SearchRolesByID(c *gin.Context) {
 
 	query := c.Query("id")
 
-	if rc.Log.RegisterLog(c, "Attempting to search roles by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := rc.Log.RegisterLog(c, "Attempting to search roles by ID: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	roles, err := rc.Service.SearchRolesByID(query)
+	roles, err := rc.Service.SearchRolesByID(c.Request.Context(), query)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error searching roles by ID: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching roles by ID"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -277,9 +295,9 @@ func (rc *RoleController) SearchRolesByID(c *gin.Context) {
 // @Produce      json
 // @Param        name  query  string  true  "Role Name"
 // @Success      200  {array}  models.Role  "Returns the roles matching the search criteria"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid query parameter"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error searching roles by name"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid query parameter"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error searching roles by name"
 // @Security     ApiKeyAuth
 // @Router       /roles/searchByName [get]
 func (rc *RoleController) SearchRolesByName(c *gin.Context) {
@@ -292,18 +310,82 @@ func (rc *RoleController) SearchRolesByName(c *gin.Context) {
 
 	query := c.Query("name")
 
-	if rc.Log.RegisterLog(c, "Attempting to search roles by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := rc.Log.RegisterLog(c, "Attempting to search roles by name: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	roles, err := rc.Service.SearchRolesByName(query)
+	roles, err := rc.Service.SearchRolesByName(c.Request.Context(), query)
 	if err != nil {
 		_ = rc.Log.RegisterLog(c, "Error searching roles by name: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching roles by name"})
+		_ = c.Error(err)
 		return
 	}
 
 	_ = rc.Log.RegisterLog(c, "Successfully searched roles by name: "+query)
 	c.JSON(http.StatusOK, roles)
 }
+
+// SetRoleScopes godoc
+// @Summary      Set the data scope rules of a role
+// @Description  Replaces the data scope rules of a role. With the rule own on purchase_orders, users with the role only list, search and get the purchase orders where their employee is the seller;
+// @Description  on customers, the customers of those orders; on appointments, the appointments of those customers. Records out of scope respond 404.
+// @Description  A rule does not apply if another role of the user grants the same permission without a rule for the resource. An empty list removes all rules.
+// @Tags         roles
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int  true  "Role ID"
+// @Param        body  body      dtos.SetRoleScopesDTO  true  "Data scope rules"
+// @Success      200   {object}  models.MessageResponse  "Scope rules updated"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid role ID or request body"
+// @Failure      403   {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404   {object}  models.ProblemDetails  "Role not found"
+// @Failure      422   {object}  models.ProblemDetails  "Validation failed"
+// @Failure      500   {object}  models.ProblemDetails  "Error updating the role"
+// @Security     ApiKeyAuth
+// @Router       /roles/{id}/scopes [put]
+func (rc *RoleController) SetRoleScopes(c *gin.Context) {
+	permissionId := config.PERMISSION_SET_ROLE_SCOPES
+	idParam := c.Param("id")
+
+	if err := rc.Log.RegisterLog(c, "Attempting to update scope rules of role with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !rc.Auth.CheckPermission(c, permissionId) {
+		_ = rc.Log.RegisterLog(c, "Access denied for SetRoleScopes")
+		return
+	}
+
+	var id uint
+	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid role ID format: "+idParam)
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", idParam))
+		return
+	}
+
+	var dto dtos.SetRoleScopesDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = rc.Log.RegisterLog(c, "Invalid request body for SetRoleScopes")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	if err := rc.Service.SetRoleScopes(c.Request.Context(), id, dto.Scopes); err != nil {
+		_ = rc.Log.RegisterLog(c, "Error updating scope rules of role with ID "+idParam+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = rc.Log.RegisterLog(c, "Successfully updated scope rules of role with ID: "+idParam)
+	c.JSON(http.StatusOK, gin.H{"message": "Scope rules updated"})
+}
+
+func roleScopeDTOs(scopes []models.RoleScope) []dtos.RoleScopeDTO {
+	scopeDTOs := make([]dtos.RoleScopeDTO, 0, len(scopes))
+	for _, scope := range scopes {
+		scopeDTOs = append(scopeDTOs, dtos.RoleScopeDTO{Resource: scope.Resource, Rule: scope.Rule})
+	}
+	return scopeDTOs
+}
@@ -2,12 +2,15 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -30,17 +33,17 @@ func NewSalesReportController(service *services.SalesReportService, auth *utilit
 // @Param        startDate  query  string  true  "Start Date (RFC3339 format)"
 // @Param        endDate    query  string  true  "End Date (RFC3339 format)"
 // @Success      200  {array}  dtos.SalesReportInvoiceDTO  "List of invoices between the given dates"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid date format"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error fetching invoices"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error fetching invoices"
 // @Security     ApiKeyAuth
 // @Router       /sales-report/invoices [get]
 func (src *SalesReportController) GetInvoicesBetweenDates(c *gin.Context) {
 	startDateStr := c.Query("startDate")
 	endDateStr := c.Query("endDate")
 
-	if src.Log.RegisterLog(c, "Request to fetch invoices between "+startDateStr+" and "+endDateStr) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := src.Log.RegisterLog(c, "Request to fetch invoices between "+startDateStr+" and "+endDateStr); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -53,21 +56,21 @@ func (src *SalesReportController) GetInvoicesBetweenDates(c *gin.Context) {
 	startDate, err := time.Parse(time.RFC3339, startDateStr)
 	if err != nil {
 		_ = src.Log.RegisterLog(c, "Invalid startDate: "+startDateStr)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid startDate format. Use RFC3339 format: yyyy-mm-ddTHH:MM:SSZ"})
+		_ = c.Error(validation.ParamError("startDate", "datetime", time.RFC3339, "must be a date in RFC3339 format"))
 		return
 	}
 
 	endDate, err := time.Parse(time.RFC3339, endDateStr)
 	if err != nil {
 		_ = src.Log.RegisterLog(c, "Invalid endDate: "+endDateStr)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endDate format. Use RFC3339 format: yyyy-mm-ddTHH:MM:SSZ"})
+		_ = c.Error(validation.ParamError("endDate", "datetime", time.RFC3339, "must be a date in RFC3339 format"))
 		return
 	}
 
-	invoices, err := src.Service.GetInvoicesBetweenDates(startDate, endDate)
+	invoices, err := src.Service.GetInvoicesBetweenDates(c.Request.Context(), startDate, endDate)
 	if err != nil {
 		_ = src.Log.RegisterLog(c, "Error fetching invoices: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching invoices"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -79,7 +82,239 @@ func (src *SalesReportController) GetInvoicesBetweenDates(c *gin.Context) {
 	}
 
 	_ = src.Log.RegisterLog(c, "Successfully fetched invoices between "+startDateStr+" and "+endDateStr)
-	c.JSON(http.StatusOK, invoiceDTOs)
+	src.Auth.JSON(c, http.StatusOK, invoiceDTOs)
+}
+
+// GetRevenueByPeriod godoc
+// @Summary      Revenue by period
+// @Description  Invoice count, subtotal, discount, tax and total invoiced between the given dates, grouped by day, week or month.
+// @Description  Each period is compared with the previous one. Add format=csv to download the report as CSV.
+// @Tags         sales-report
+// @Produce      json
+// @Produce      text/csv
+// @Param        startDate  query  string  true   "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true   "End Date (RFC3339 format)"
+// @Param        period     query  string  false  "Grouping: day, week or month (default month)"
+// @Param        format     query  string  false  "Response format: json (default) or csv"
+// @Success      200  {array}  dtos.RevenueByPeriodDTO  "Revenue per period"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid period"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating report"
+// @Security     ApiKeyAuth
+// @Router       /sales-report/revenue [get]
+func (src *SalesReportController) GetRevenueByPeriod(c *gin.Context) {
+	period := c.Query("period")
+	src.aggregateReport(c, "revenue by period", "revenue_by_period.csv", func(startDate, endDate time.Time) (interface{}, services.ReportTable, error) {
+		rows, err := src.Service.GetRevenueByPeriod(c.Request.Context(), startDate, endDate, period)
+		if err != nil {
+			return nil, services.ReportTable{}, err
+		}
+		return rows, services.RevenueByPeriodTable(rows), nil
+	})
+}
+
+// GetTopItems godoc
+// @Summary      Top selling items
+// @Description  The N items with the highest quantity or revenue invoiced between the given dates.
+// @Description  Add format=csv to download the report as CSV.
+// @Tags         sales-report
+// @Produce      json
+// @Produce      text/csv
+// @Param        startDate  query  string  true   "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true   "End Date (RFC3339 format)"
+// @Param        orderBy    query  string  false  "Ranking criteria: quantity (default) or revenue"
+// @Param        limit      query  int     false  "Number of items, between 1 and 100 (default 10)"
+// @Param        format     query  string  false  "Response format: json (default) or csv"
+// @Success      200  {array}  dtos.TopItemDTO  "Top items"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid ranking criteria or limit"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating report"
+// @Security     ApiKeyAuth
+// @Router       /sales-report/top-items [get]
+func (src *SalesReportController) GetTopItems(c *gin.Context) {
+	orderBy := c.Query("orderBy")
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil {
+			_ = c.Error(validation.ParamError("limit", "numeric", "", "must be an integer"))
+			return
+		}
+	}
+
+	src.aggregateReport(c, "top items", "top_items.csv", func(startDate, endDate time.Time) (interface{}, services.ReportTable, error) {
+		rows, err := src.Service.GetTopItems(c.Request.Context(), startDate, endDate, orderBy, limit)
+		if err != nil {
+			return nil, services.ReportTable{}, err
+		}
+		return rows, services.TopItemsTable(rows), nil
+	})
+}
+
+// GetRevenueByItemType godoc
+// @Summary      Revenue by item type
+// @Description  Quantity and revenue invoiced between the given dates for each item type.
+// @Description  Add format=csv to download the report as CSV.
+// @Tags         sales-report
+// @Produce      json
+// @Produce      text/csv
+// @Param        startDate  query  string  true   "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true   "End Date (RFC3339 format)"
+// @Param        format     query  string  false  "Response format: json (default) or csv"
+// @Success      200  {array}  dtos.ItemTypeRevenueDTO  "Revenue per item type"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating report"
+// @Security     ApiKeyAuth
+// @Router       /sales-report/item-types [get]
+func (src *SalesReportController) GetRevenueByItemType(c *gin.Context) {
+	src.aggregateReport(c, "revenue by item type", "revenue_by_item_type.csv", func(startDate, endDate time.Time) (interface{}, services.ReportTable, error) {
+		rows, err := src.Service.GetRevenueByItemType(c.Request.Context(), startDate, endDate)
+		if err != nil {
+			return nil, services.ReportTable{}, err
+		}
+		return rows, services.RevenueByItemTypeTable(rows), nil
+	})
+}
+
+// GetRevenueByCustomer godoc
+// @Summary      Revenue by customer
+// @Description  Invoice count, subtotal, discount, tax and total invoiced between the given dates for each customer.
+// @Description  Add format=csv to download the report as CSV.
+// @Tags         sales-report
+// @Produce      json
+// @Produce      text/csv
+// @Param        startDate  query  string  true   "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true   "End Date (RFC3339 format)"
+// @Param        format     query  string  false  "Response format: json (default) or csv"
+// @Success      200  {array}  dtos.CustomerRevenueDTO  "Revenue per customer"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating report"
+// @Security     ApiKeyAuth
+// @Router       /sales-report/customers [get]
+func (src *SalesReportController) GetRevenueByCustomer(c *gin.Context) {
+	src.aggregateReport(c, "revenue by customer", "revenue_by_customer.csv", func(startDate, endDate time.Time) (interface{}, services.ReportTable, error) {
+		rows, err := src.Service.GetRevenueByCustomer(c.Request.Context(), startDate, endDate)
+		if err != nil {
+			return nil, services.ReportTable{}, err
+		}
+		return rows, services.RevenueByCustomerTable(rows), nil
+	})
+}
+
+// GetRevenueBySeller godoc
+// @Summary      Revenue by seller
+// @Description  Invoice count, subtotal, discount, tax and total invoiced between the given dates for each seller,
+// @Description  taken from the purchase order that generated each invoice. Invoices without a purchase order are not included.
+// @Description  Add format=csv to download the report as CSV.
+// @Tags         sales-report
+// @Produce      json
+// @Produce      text/csv
+// @Param        startDate  query  string  true   "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true   "End Date (RFC3339 format)"
+// @Param        format     query  string  false  "Response format: json (default) or csv"
+// @Success      200  {array}  dtos.SellerRevenueDTO  "Revenue per seller"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating report"
+// @Security     ApiKeyAuth
+// @Router       /sales-report/sellers [get]
+func (src *SalesReportController) GetRevenueBySeller(c *gin.Context) {
+	src.aggregateReport(c, "revenue by seller", "revenue_by_seller.csv", func(startDate, endDate time.Time) (interface{}, services.ReportTable, error) {
+		rows, err := src.Service.GetRevenueBySeller(c.Request.Context(), startDate, endDate)
+		if err != nil {
+			return nil, services.ReportTable{}, err
+		}
+		return rows, services.RevenueBySellerTable(rows), nil
+	})
+}
+
+// GetTaxSummary godoc
+// @Summary      Tax summary
+// @Description  Number of invoices, taxable amount and tax collected between the given dates for each tax type.
+// @Description  Add format=csv to download the report as CSV.
+// @Tags         sales-report
+// @Produce      json
+// @Produce      text/csv
+// @Param        startDate  query  string  true   "Start Date (RFC3339 format)"
+// @Param        endDate    query  string  true   "End Date (RFC3339 format)"
+// @Param        format     query  string  false  "Response format: json (default) or csv"
+// @Success      200  {array}  dtos.TaxSummaryDTO  "Tax collected per tax type"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error generating report"
+// @Security     ApiKeyAuth
+// @Router       /sales-report/taxes [get]
+func (src *SalesReportController) GetTaxSummary(c *gin.Context) {
+	src.aggregateReport(c, "tax summary", "tax_summary.csv", func(startDate, endDate time.Time) (interface{}, services.ReportTable, error) {
+		rows, err := src.Service.GetTaxSummary(c.Request.Context(), startDate, endDate)
+		if err != nil {
+			return nil, services.ReportTable{}, err
+		}
+		return rows, services.TaxSummaryTable(rows), nil
+	})
+}
+
+// aggregateReport resuelve los pasos comunes de los reportes agregados: log,
+// permiso, lectura del rango de fechas y respuesta en JSON o CSV.
+func (src *SalesReportController) aggregateReport(c *gin.Context, name string, filename string,
+	report func(startDate, endDate time.Time) (interface{}, services.ReportTable, error)) {
+	startDate, endDate, ok := src.reportDateRange(c, name)
+	if !ok {
+		return
+	}
+
+	rows, table, err := report(startDate, endDate)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Error generating "+name+" report: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully generated "+name+" report")
+	if utilities.WantsCSV(c) {
+		if err := utilities.WriteCSV(c, filename, table.Header, table.Records); err != nil {
+			_ = src.Log.RegisterLog(c, "Error writing "+name+" report as CSV: "+err.Error())
+		}
+		return
+	}
+	c.JSON(http.StatusOK, rows)
+}
+
+// reportDateRange registra el pedido, verifica el permiso y lee las fechas del reporte.
+func (src *SalesReportController) reportDateRange(c *gin.Context, name string) (time.Time, time.Time, bool) {
+	startDateStr := c.Query("startDate")
+	endDateStr := c.Query("endDate")
+
+	if err := src.Log.RegisterLog(c, "Request "+name+" report between "+startDateStr+" and "+endDateStr); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return time.Time{}, time.Time{}, false
+	}
+
+	permissionId := config.PERMISSION_VIEW_SALES_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for "+name+" report")
+		return time.Time{}, time.Time{}, false
+	}
+
+	startDate, err := time.Parse(time.RFC3339, startDateStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid startDate: "+startDateStr)
+		_ = c.Error(validation.ParamError("startDate", "datetime", time.RFC3339, "must be a date in RFC3339 format"))
+		return time.Time{}, time.Time{}, false
+	}
+
+	endDate, err := time.Parse(time.RFC3339, endDateStr)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid endDate: "+endDateStr)
+		_ = c.Error(validation.ParamError("endDate", "datetime", time.RFC3339, "must be a date in RFC3339 format"))
+		return time.Time{}, time.Time{}, false
+	}
+
+	return startDate, endDate, true
 }
 
 // Función para mapear un Invoice a SalesReportInvoiceDTO
@@ -88,8 +323,9 @@ func mapInvoiceToSalesReportDTO(invoice models.Invoice) dtos.SalesReportInvoiceD
 	var billingItems []dtos.BillingItemDTO
 	for _, item := range invoice.Items {
 		billingItems = append(billingItems, dtos.BillingItemDTO{
-			ID:    item.ItemID,
-			Stock: item.Amount,
+			ID:        item.ItemID,
+			Stock:     item.Amount,
+			UnitPrice: item.UnitPrice,
 			// Puedes agregar más campos si tu BillingItemDTO tiene más propiedades
 		})
 	}
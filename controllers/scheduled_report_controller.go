@@ -0,0 +1,411 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/services"
+	"totesbackend/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduledReportController struct {
+	Service *services.ScheduledReportService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewScheduledReportController(service *services.ScheduledReportService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *ScheduledReportController {
+	return &ScheduledReportController{Service: service, Auth: auth, Log: log}
+}
+
+// GetAllScheduledReports godoc
+// @Summary      Get all scheduled reports
+// @Description  Retrieves every scheduled report definition with its next planned run.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
+// @Tags         scheduled-reports
+// @Produce      json
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200 {object}  dtos.PageDTO{data=[]dtos.GetScheduledReportDTO} "List of scheduled reports"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving scheduled reports"
+// @Security     ApiKeyAuth
+// @Router       /scheduled-reports [get]
+func (src *ScheduledReportController) GetAllScheduledReports(c *gin.Context) {
+	if err := src.Log.RegisterLog(c, "Attempting to retrieve all scheduled reports"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_GET_ALL_SCHEDULED_REPORTS
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for GetAllScheduledReports")
+		return
+	}
+
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	reports, page, err := src.Service.GetAllScheduledReports(c.Request.Context(), query)
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Error retrieving scheduled reports: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	reportDTOs := make([]dtos.GetScheduledReportDTO, 0, len(reports))
+	for i := range reports {
+		reportDTOs = append(reportDTOs, src.toScheduledReportDTO(&reports[i]))
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully retrieved all scheduled reports")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(reportDTOs, page))
+}
+
+// GetScheduledReportByID godoc
+// @Summary      Get a scheduled report by ID
+// @Description  Retrieves a scheduled report definition with its next planned run.
+// @Tags         scheduled-reports
+// @Produce      json
+// @Param        id  path     int  true  "Scheduled Report ID"
+// @Success      200 {object} dtos.GetScheduledReportDTO "The requested scheduled report"
+// @Failure      400 {object} models.ProblemDetails "Invalid ID"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Scheduled report not found"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving scheduled report"
+// @Security     ApiKeyAuth
+// @Router       /scheduled-reports/{id} [get]
+func (src *ScheduledReportController) GetScheduledReportByID(c *gin.Context) {
+	if err := src.Log.RegisterLog(c, "Attempting to retrieve scheduled report with ID: "+c.Param("id")); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_GET_SCHEDULED_REPORT_BY_ID
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for GetScheduledReportByID")
+		return
+	}
+
+	id, ok := src.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	report, err := src.Service.GetScheduledReportByID(c.Request.Context(), id)
+	if err != nil {
+		src.respondError(c, "Error retrieving scheduled report", err)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully retrieved scheduled report with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, src.toScheduledReportDTO(report))
+}
+
+// CreateScheduledReport godoc
+// @Summary      Create a scheduled report
+// @Description  Creates a report definition that is generated and emailed according to a cron expression
+// @Description  (five fields or descriptors such as @weekly). Reports: sales_summary, tax_summary, top_items,
+// @Description  revenue_by_item_type, revenue_by_customer, revenue_by_seller. Formats: json, csv, pdf.
+// @Description  The range parameter is relative to each run: previous_day, previous_week, previous_month, last_7_days or last_30_days.
+// @Tags         scheduled-reports
+// @Accept       json
+// @Produce      json
+// @Param        report body     dtos.CreateScheduledReportDTO true "Scheduled report definition"
+// @Success      201 {object} dtos.GetScheduledReportDTO "Scheduled report created"
+// @Failure      400 {object} models.ProblemDetails "Invalid input"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      500 {object} models.ProblemDetails "Error creating scheduled report"
+// @Security     ApiKeyAuth
+// @Router       /scheduled-reports [post]
+func (src *ScheduledReportController) CreateScheduledReport(c *gin.Context) {
+	if err := src.Log.RegisterLog(c, "Attempting to create a scheduled report"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_CREATE_SCHEDULED_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for CreateScheduledReport")
+		return
+	}
+
+	var dto dtos.CreateScheduledReportDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid input for scheduled report creation: "+err.Error())
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	report, err := src.Service.CreateScheduledReport(c.Request.Context(), &dto)
+	if err != nil {
+		src.respondError(c, "Could not create scheduled report", err)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully created scheduled report with ID: "+strconv.Itoa(report.ID))
+	c.JSON(http.StatusCreated, src.toScheduledReportDTO(report))
+}
+
+// UpdateScheduledReport godoc
+// @Summary      Update a scheduled report
+// @Description  Replaces a scheduled report definition and reschedules it.
+// @Tags         scheduled-reports
+// @Accept       json
+// @Produce      json
+// @Param        id     path     int                           true "Scheduled Report ID"
+// @Param        report body     dtos.CreateScheduledReportDTO true "Scheduled report definition"
+// @Success      200 {object} dtos.GetScheduledReportDTO "Scheduled report updated"
+// @Failure      400 {object} models.ProblemDetails "Invalid input"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Scheduled report not found"
+// @Failure      500 {object} models.ProblemDetails "Error updating scheduled report"
+// @Security     ApiKeyAuth
+// @Router       /scheduled-reports/{id} [put]
+func (src *ScheduledReportController) UpdateScheduledReport(c *gin.Context) {
+	if err := src.Log.RegisterLog(c, "Attempting to update scheduled report with ID: "+c.Param("id")); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_UPDATE_SCHEDULED_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for UpdateScheduledReport")
+		return
+	}
+
+	id, ok := src.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	var dto dtos.CreateScheduledReportDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid input for scheduled report update: "+err.Error())
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	report, err := src.Service.UpdateScheduledReport(c.Request.Context(), id, &dto)
+	if err != nil {
+		src.respondError(c, "Could not update scheduled report", err)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully updated scheduled report with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, src.toScheduledReportDTO(report))
+}
+
+// DeleteScheduledReport godoc
+// @Summary      Delete a scheduled report
+// @Description  Deletes a scheduled report definition together with its run history.
+// @Tags         scheduled-reports
+// @Produce      json
+// @Param        id  path     int  true  "Scheduled Report ID"
+// @Success      200 {object} map[string]string "Scheduled report deleted"
+// @Failure      400 {object} models.ProblemDetails "Invalid ID"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Scheduled report not found"
+// @Failure      500 {object} models.ProblemDetails "Error deleting scheduled report"
+// @Security     ApiKeyAuth
+// @Router       /scheduled-reports/{id} [delete]
+func (src *ScheduledReportController) DeleteScheduledReport(c *gin.Context) {
+	if err := src.Log.RegisterLog(c, "Attempting to delete scheduled report with ID: "+c.Param("id")); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_DELETE_SCHEDULED_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for DeleteScheduledReport")
+		return
+	}
+
+	id, ok := src.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := src.Service.DeleteScheduledReport(c.Request.Context(), id); err != nil {
+		src.respondError(c, "Could not delete scheduled report", err)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully deleted scheduled report with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, gin.H{"message": "Scheduled report deleted"})
+}
+
+// RunScheduledReport godoc
+// @Summary      Run a scheduled report now
+// @Description  Generates and delivers the report immediately. The run is recorded like a scheduled one.
+// @Tags         scheduled-reports
+// @Produce      json
+// @Param        id  path     int  true  "Scheduled Report ID"
+// @Success      200 {object} models.ReportRun "The recorded run"
+// @Failure      400 {object} models.ProblemDetails "Invalid ID"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Scheduled report not found"
+// @Failure      500 {object} models.ProblemDetails "Error running scheduled report"
+// @Security     ApiKeyAuth
+// @Router       /scheduled-reports/{id}/run [post]
+func (src *ScheduledReportController) RunScheduledReport(c *gin.Context) {
+	if err := src.Log.RegisterLog(c, "Attempting to run scheduled report with ID: "+c.Param("id")); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_RUN_SCHEDULED_REPORT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for RunScheduledReport")
+		return
+	}
+
+	id, ok := src.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	run, err := src.Service.RunScheduledReport(c.Request.Context(), id)
+	if err != nil {
+		src.respondError(c, "Could not run scheduled report", err)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Scheduled report with ID "+c.Param("id")+" finished with status: "+run.Status)
+	c.JSON(http.StatusOK, run)
+}
+
+// GetReportRuns godoc
+// @Summary      Get the runs of a scheduled report
+// @Description  Retrieves the run history of a scheduled report, most recent first.
+// @Tags         scheduled-reports
+// @Produce      json
+// @Param        id  path     int  true  "Scheduled Report ID"
+// @Success      200 {array}  models.ReportRun "Runs of the scheduled report"
+// @Failure      400 {object} models.ProblemDetails "Invalid ID"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Scheduled report not found"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving runs"
+// @Security     ApiKeyAuth
+// @Router       /scheduled-reports/{id}/runs [get]
+func (src *ScheduledReportController) GetReportRuns(c *gin.Context) {
+	if err := src.Log.RegisterLog(c, "Attempting to retrieve runs of scheduled report with ID: "+c.Param("id")); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_GET_REPORT_RUNS
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for GetReportRuns")
+		return
+	}
+
+	id, ok := src.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	runs, err := src.Service.GetReportRuns(c.Request.Context(), id)
+	if err != nil {
+		src.respondError(c, "Error retrieving runs", err)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully retrieved runs of scheduled report with ID: "+c.Param("id"))
+	c.JSON(http.StatusOK, runs)
+}
+
+// DownloadReportArtifact godoc
+// @Summary      Download the file generated by a report run
+// @Description  Downloads the JSON, CSV or PDF file generated by a run of a scheduled report.
+// @Tags         scheduled-reports
+// @Produce      application/json
+// @Produce      text/csv
+// @Produce      application/pdf
+// @Param        runId  path  int  true  "Report Run ID"
+// @Success      200 {file}   file "Generated report"
+// @Failure      400 {object} models.ProblemDetails "Invalid ID"
+// @Failure      403 {object} models.ProblemDetails "Permission denied"
+// @Failure      404 {object} models.ProblemDetails "Run not found or without a generated file"
+// @Failure      500 {object} models.ProblemDetails "Error retrieving run"
+// @Security     ApiKeyAuth
+// @Router       /scheduled-reports/runs/{runId}/artifact [get]
+func (src *ScheduledReportController) DownloadReportArtifact(c *gin.Context) {
+	if err := src.Log.RegisterLog(c, "Attempting to download artifact of report run with ID: "+c.Param("runId")); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	permissionId := config.PERMISSION_DOWNLOAD_REPORT_ARTIFACT
+	if !src.Auth.CheckPermission(c, permissionId) {
+		_ = src.Log.RegisterLog(c, "Access denied for DownloadReportArtifact")
+		return
+	}
+
+	id, ok := src.parseID(c, "runId")
+	if !ok {
+		return
+	}
+
+	run, err := src.Service.GetReportRunByID(c.Request.Context(), id)
+	if err != nil {
+		src.respondError(c, "Error retrieving run", err)
+		return
+	}
+	if len(run.Artifact) == 0 {
+		_ = src.Log.RegisterLog(c, "Report run with ID "+c.Param("runId")+" has no generated file")
+		_ = c.Error(apperrors.ErrNotFound)
+		return
+	}
+
+	_ = src.Log.RegisterLog(c, "Successfully downloaded artifact of report run with ID: "+c.Param("runId"))
+	c.Header("Content-Disposition", "attachment; filename=\""+run.ArtifactName+"\"")
+	c.Data(http.StatusOK, run.ContentType, run.Artifact)
+}
+
+func (src *ScheduledReportController) parseID(c *gin.Context, param string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		_ = src.Log.RegisterLog(c, "Invalid ID provided: "+c.Param(param))
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail(param, c.Param(param)))
+		return 0, false
+	}
+	return id, true
+}
+
+func (src *ScheduledReportController) respondError(c *gin.Context, message string, err error) {
+	_ = src.Log.RegisterLog(c, message+": "+err.Error())
+	_ = c.Error(err)
+}
+
+func (src *ScheduledReportController) toScheduledReportDTO(report *models.ScheduledReport) dtos.GetScheduledReportDTO {
+	return dtos.GetScheduledReportDTO{
+		ID:         report.ID,
+		Name:       report.Name,
+		Report:     report.Report,
+		Parameters: services.ReportParameters(report),
+		Cron:       report.Cron,
+		Format:     report.Format,
+		Recipients: services.ReportRecipients(report),
+		Active:     report.Active,
+		LastRunAt:  report.LastRunAt,
+		NextRunAt:  src.Service.NextRunAt(report.ID),
+		CreatedAt:  report.CreatedAt,
+	}
+}
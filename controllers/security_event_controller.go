@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"net/http"
+
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SecurityEventController struct {
+	Service *services.SecurityEventService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewSecurityEventController(service *services.SecurityEventService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *SecurityEventController {
+	return &SecurityEventController{Service: service, Auth: auth, Log: log}
+}
+
+// GetAllSecurityEvents godoc
+// @Summary      Get security events
+// @Description  Retrieves the authentication audit log: successful and failed logins, throttled attempts, account locks and unlocks. Newest first by default.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
+// @Tags         security
+// @Produce      json
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}  dtos.PageDTO{data=[]models.SecurityEvent}  "List of security events"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving security events"
+// @Security     ApiKeyAuth
+// @Router       /security-events [get]
+func (sec *SecurityEventController) GetAllSecurityEvents(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_SECURITY_EVENTS
+
+	if err := sec.Log.RegisterLog(c, "Attempting to retrieve security events"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !sec.Auth.CheckPermission(c, permissionId) {
+		_ = sec.Log.RegisterLog(c, "Access denied for GetAllSecurityEvents")
+		return
+	}
+
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = sec.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	events, page, err := sec.Service.GetAllSecurityEvents(c.Request.Context(), query)
+	if err != nil {
+		_ = sec.Log.RegisterLog(c, "Error retrieving security events: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = sec.Log.RegisterLog(c, "Successfully retrieved security events")
+	c.JSON(http.StatusOK, dtos.NewPageDTO(events, page))
+}
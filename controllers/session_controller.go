@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/middleware"
+	"totesbackend/models"
+	"totesbackend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	Service *services.SessionService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewSessionController(service *services.SessionService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *SessionController {
+	return &SessionController{Service: service, Auth: auth, Log: log}
+}
+
+// GetOwnSessions godoc
+// @Summary      Get own sessions
+// @Description  Retrieves the open sessions of the logged-in user with their device, IP, creation and last use. The session used for the request is marked as current.
+// @Tags         sessions
+// @Produce      json
+// @Success      200  {array}   dtos.SessionDTO  "List of sessions"
+// @Failure      404  {object}  models.ProblemDetails  "User not found"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving sessions"
+// @Security     ApiKeyAuth
+// @Router       /sessions [get]
+func (sc *SessionController) GetOwnSessions(c *gin.Context) {
+	if err := sc.Log.RegisterLog(c, "Attempting to retrieve own sessions"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	sessions, err := sc.Service.GetOwnSessions(c.Request.Context(), middleware.Caller(c))
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error retrieving own sessions: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully retrieved own sessions")
+	c.JSON(http.StatusOK, sessionDTOs(c, sessions))
+}
+
+// RevokeOwnSession godoc
+// @Summary      Revoke an own session
+// @Description  Logs out one of the sessions of the logged-in user, for example on a lost device. It can be the current session.
+// @Tags         sessions
+// @Produce      json
+// @Param        id   path      string  true  "Session ID"
+// @Success      200  {object}  models.MessageResponse  "Session revoked"
+// @Failure      404  {object}  models.ProblemDetails  "Session not found"
+// @Failure      409  {object}  models.ProblemDetails  "Session already revoked"
+// @Failure      500  {object}  models.ProblemDetails  "Error revoking the session"
+// @Security     ApiKeyAuth
+// @Router       /sessions/{id} [delete]
+func (sc *SessionController) RevokeOwnSession(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := sc.Log.RegisterLog(c, "Attempting to revoke own session with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if err := sc.Service.RevokeOwnSession(c.Request.Context(), middleware.Caller(c), id); err != nil {
+		_ = sc.Log.RegisterLog(c, "Error revoking own session with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully revoked own session with ID: "+id)
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// GetUserSessions godoc
+// @Summary      Get a user's sessions
+// @Description  Retrieves the open sessions of any user with their device, IP, creation and last use.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {array}   dtos.SessionDTO  "List of sessions"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "User not found"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving sessions"
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/sessions [get]
+func (sc *SessionController) GetUserSessions(c *gin.Context) {
+	permissionId := config.PERMISSION_GET_USER_SESSIONS
+	id := c.Param("id")
+
+	if err := sc.Log.RegisterLog(c, "Attempting to retrieve sessions of user with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for GetUserSessions")
+		return
+	}
+
+	sessions, err := sc.Service.GetUserSessions(c.Request.Context(), id)
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error retrieving sessions of user with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully retrieved sessions of user with ID: "+id)
+	c.JSON(http.StatusOK, sessionDTOs(c, sessions))
+}
+
+// RevokeUserSession godoc
+// @Summary      Revoke a user's session
+// @Description  Logs out one session of any user.
+// @Tags         users
+// @Produce      json
+// @Param        id         path      string  true  "User ID"
+// @Param        sessionId  path      string  true  "Session ID"
+// @Success      200  {object}  models.MessageResponse  "Session revoked"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "User or session not found"
+// @Failure      409  {object}  models.ProblemDetails  "Session already revoked"
+// @Failure      500  {object}  models.ProblemDetails  "Error revoking the session"
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/sessions/{sessionId} [delete]
+func (sc *SessionController) RevokeUserSession(c *gin.Context) {
+	permissionId := config.PERMISSION_REVOKE_USER_SESSIONS
+	id := c.Param("id")
+	sessionID := c.Param("sessionId")
+
+	if err := sc.Log.RegisterLog(c, "Attempting to revoke session "+sessionID+" of user with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for RevokeUserSession")
+		return
+	}
+
+	err := sc.Service.RevokeUserSession(c.Request.Context(), id, sessionID, middleware.Caller(c))
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error revoking session "+sessionID+" of user with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully revoked session "+sessionID+" of user with ID: "+id)
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked"})
+}
+
+// RevokeUserSessions godoc
+// @Summary      Revoke all of a user's sessions
+// @Description  Logs out every open session of any user. The user can log in again unless the account is also deactivated or locked.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  models.MessageResponse  "Sessions revoked"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "User not found"
+// @Failure      500  {object}  models.ProblemDetails  "Error revoking the sessions"
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/sessions [delete]
+func (sc *SessionController) RevokeUserSessions(c *gin.Context) {
+	permissionId := config.PERMISSION_REVOKE_USER_SESSIONS
+	id := c.Param("id")
+
+	if err := sc.Log.RegisterLog(c, "Attempting to revoke all sessions of user with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !sc.Auth.CheckPermission(c, permissionId) {
+		_ = sc.Log.RegisterLog(c, "Access denied for RevokeUserSessions")
+		return
+	}
+
+	count, err := sc.Service.RevokeUserSessions(c.Request.Context(), id, middleware.Caller(c))
+	if err != nil {
+		_ = sc.Log.RegisterLog(c, "Error revoking sessions of user with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = sc.Log.RegisterLog(c, "Successfully revoked "+strconv.FormatInt(count, 10)+" sessions of user with ID: "+id)
+	c.JSON(http.StatusOK, gin.H{"message": strconv.FormatInt(count, 10) + " sessions revoked"})
+}
+
+func sessionDTOs(c *gin.Context, sessions []models.UserSession) []dtos.SessionDTO {
+	currentID := 0
+	if current, ok := middleware.SessionFromContext(c); ok {
+		currentID = current.ID
+	}
+
+	sessionDTOs := make([]dtos.SessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		sessionDTOs = append(sessionDTOs, dtos.SessionDTO{
+			ID:         session.ID,
+			Method:     session.Method,
+			Device:     session.Device,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentID,
+		})
+	}
+	return sessionDTOs
+}
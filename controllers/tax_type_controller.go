@@ -3,10 +3,13 @@ package controllers
 import (
 	"net/http"
 
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,9 +32,9 @@ func NewTaxTypeController(service *services.TaxTypeService,
 // @Produce      json
 // @Param        id  path  string  true  "Tax Type ID"
 // @Success      200  {object}  models.TaxType  "Details of the tax type"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid ID format"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "Tax type not found"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid ID format"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "Tax type not found"
 // @Security     ApiKeyAuth
 // @Router       /tax-types/{id} [get]
 func (ttc *TaxTypeController) GetTaxTypeByID(c *gin.Context) {
@@ -42,9 +45,9 @@ func (ttc *TaxTypeController) GetTaxTypeByID(c *gin.Context) {
 	}
 
 	id := c.Param("id")
-	taxType, err := ttc.Service.GetTaxTypeByID(id)
+	taxType, err := ttc.Service.GetTaxTypeByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Tax Type not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -54,11 +57,17 @@ func (ttc *TaxTypeController) GetTaxTypeByID(c *gin.Context) {
 // GetAllTaxTypes godoc
 // @Summary      Retrieve all tax types
 // @Description  Fetches the list of all available tax types.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         tax-types
 // @Produce      json
-// @Success      200  {array}   models.TaxType  "List of tax types"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving tax types"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}   dtos.PageDTO{data=[]models.TaxType}  "List of tax types"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving tax types"
 // @Security     ApiKeyAuth
 // @Router       /tax-types [get]
 func (ttc *TaxTypeController) GetAllTaxTypes(c *gin.Context) {
@@ -68,12 +77,19 @@ func (ttc *TaxTypeController) GetAllTaxTypes(c *gin.Context) {
 		return
 	}
 
-	taxTypes, err := ttc.Service.GetAllTaxTypes()
+	query, err := utilities.ParseListQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving Tax Types"})
+		_ = ttc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
 		return
 	}
-	c.JSON(http.StatusOK, taxTypes)
+
+	taxTypes, page, err := ttc.Service.GetAllTaxTypes(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, dtos.NewPageDTO(taxTypes, page))
 }
 
 // CreateTaxType godoc
@@ -84,14 +100,15 @@ func (ttc *TaxTypeController) GetAllTaxTypes(c *gin.Context) {
 // @Produce      json
 // @Param        tax  body     models.TaxType  true  "Tax Type Details"
 // @Success      201  {object}  models.TaxType  "Successfully created tax type"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid input data"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500  {object}  models.ErrorResponse  "Error creating tax type"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid input data"
+// @Failure      422  {object}  models.ProblemDetails  "Validation failed"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500  {object}  models.ProblemDetails  "Error creating tax type"
 // @Security     ApiKeyAuth
 // @Router       /tax-types [post]
 func (ttc *TaxTypeController) CreateTaxType(c *gin.Context) {
-	if ttc.Log.RegisterLog(c, "Attempting to create a new tax type") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ttc.Log.RegisterLog(c, "Attempting to create a new tax type"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -104,14 +121,14 @@ func (ttc *TaxTypeController) CreateTaxType(c *gin.Context) {
 	var tax models.TaxType
 	if err := c.ShouldBindJSON(&tax); err != nil {
 		_ = ttc.Log.RegisterLog(c, "Invalid input for tax type creation: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos del impuesto"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	err := ttc.Service.CreateTaxType(&tax)
+	err := ttc.Service.CreateTaxType(c.Request.Context(), &tax)
 	if err != nil {
 		_ = ttc.Log.RegisterLog(c, "Failed to create tax type: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo crear el impuesto"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
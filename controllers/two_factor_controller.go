@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"net/http"
+
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
+	"totesbackend/middleware"
+	"totesbackend/services"
+	"totesbackend/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorController struct {
+	Service *services.TwoFactorService
+	Auth    *utilities.AuthorizationUtil
+	Log     *utilities.LogUtil
+}
+
+func NewTwoFactorController(service *services.TwoFactorService, auth *utilities.AuthorizationUtil, log *utilities.LogUtil) *TwoFactorController {
+	return &TwoFactorController{Service: service, Auth: auth, Log: log}
+}
+
+// GetTwoFactorStatus godoc
+// @Summary      Get two-factor status
+// @Description  Tells whether the logged-in user has two-factor authentication enabled or pending, whether the user type requires it and how many recovery codes are left.
+// @Tags         two_factor
+// @Produce      json
+// @Success      200  {object}  dtos.TwoFactorStatusDTO  "Two-factor status"
+// @Failure      404  {object}  models.ProblemDetails  "User not found"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving the status"
+// @Security     ApiKeyAuth
+// @Router       /two-factor [get]
+func (tfc *TwoFactorController) GetTwoFactorStatus(c *gin.Context) {
+	if err := tfc.Log.RegisterLog(c, "Attempting to retrieve two-factor status"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	status, err := tfc.Service.GetStatus(c.Request.Context(), middleware.Caller(c))
+	if err != nil {
+		_ = tfc.Log.RegisterLog(c, "Error retrieving two-factor status: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = tfc.Log.RegisterLog(c, "Successfully retrieved two-factor status")
+	c.JSON(http.StatusOK, status)
+}
+
+// EnrollTwoFactor godoc
+// @Summary      Start two-factor enrollment
+// @Description  Generates a new TOTP secret for the user identified by email and password.
+// @Description  Show provisioning_uri as a QR code to register it in an authenticator app, then confirm it with /two-factor/confirm.
+// @Description  It does not require a session, so users whose user type requires two-factor authentication can set it up before their first login.
+// @Tags         two_factor
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.TwoFactorEnrollDTO  true  "Email and password"
+// @Success      200   {object}  dtos.TwoFactorEnrollmentDTO  "TOTP secret and provisioning URI"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      401   {object}  models.ProblemDetails  "Invalid email or password"
+// @Failure      409   {object}  models.ProblemDetails  "Two-factor authentication is already enabled"
+// @Failure      500   {object}  models.ProblemDetails  "Error starting the enrollment"
+// @Failure      423   {object}  models.ProblemDetails  "Account locked after too many failed attempts"
+// @Failure      429   {object}  models.ProblemDetails  "Too many failed attempts, retry after the indicated wait"
+// @Router       /two-factor/enroll [post]
+func (tfc *TwoFactorController) EnrollTwoFactor(c *gin.Context) {
+	if err := tfc.Log.RegisterLog(c, "Attempting to enroll two-factor authentication"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.TwoFactorEnrollDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = tfc.Log.RegisterLog(c, "Invalid request body for EnrollTwoFactor")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	enrollment, err := tfc.Service.Enroll(c.Request.Context(), dto.Email, dto.Password, c.ClientIP())
+	if err != nil {
+		_ = tfc.Log.RegisterLog(c, "Two-factor enrollment failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = tfc.Log.RegisterLog(c, "Two-factor enrollment started")
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmTwoFactor godoc
+// @Summary      Confirm two-factor enrollment
+// @Description  Enables the pending TOTP secret with a first code from the authenticator app and returns the recovery codes. They are shown only once.
+// @Description  Like enrollment, it identifies the user by email and password instead of a session.
+// @Tags         two_factor
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.TwoFactorConfirmDTO  true  "Email, password and TOTP code"
+// @Success      200   {object}  dtos.RecoveryCodesDTO  "Recovery codes"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      401   {object}  models.ProblemDetails  "Invalid email, password or two-factor code"
+// @Failure      409   {object}  models.ProblemDetails  "No pending enrollment or already enabled"
+// @Failure      500   {object}  models.ProblemDetails  "Error confirming the enrollment"
+// @Failure      423   {object}  models.ProblemDetails  "Account locked after too many failed attempts"
+// @Failure      429   {object}  models.ProblemDetails  "Too many failed attempts, retry after the indicated wait"
+// @Router       /two-factor/confirm [post]
+func (tfc *TwoFactorController) ConfirmTwoFactor(c *gin.Context) {
+	if err := tfc.Log.RegisterLog(c, "Attempting to confirm two-factor authentication"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.TwoFactorConfirmDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = tfc.Log.RegisterLog(c, "Invalid request body for ConfirmTwoFactor")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	codes, err := tfc.Service.Confirm(c.Request.Context(), dto.Email, dto.Password, dto.Code, c.ClientIP())
+	if err != nil {
+		_ = tfc.Log.RegisterLog(c, "Two-factor confirmation failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = tfc.Log.RegisterLog(c, "Two-factor authentication enabled")
+	c.JSON(http.StatusOK, dtos.RecoveryCodesDTO{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary      Disable two-factor authentication
+// @Description  Removes two-factor authentication from the logged-in user. Requires the password and a TOTP or recovery code.
+// @Description  Not allowed when the user type requires two-factor authentication.
+// @Tags         two_factor
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.TwoFactorDisableDTO  true  "Password and code"
+// @Success      200   {object}  models.MessageResponse  "Two-factor authentication disabled"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      401   {object}  models.ProblemDetails  "Invalid password or two-factor code"
+// @Failure      409   {object}  models.ProblemDetails  "Not enabled or mandatory for the user type"
+// @Failure      500   {object}  models.ProblemDetails  "Error disabling two-factor authentication"
+// @Failure      423   {object}  models.ProblemDetails  "Account locked after too many failed attempts"
+// @Failure      429   {object}  models.ProblemDetails  "Too many failed attempts, retry after the indicated wait"
+// @Security     ApiKeyAuth
+// @Router       /two-factor/disable [post]
+func (tfc *TwoFactorController) DisableTwoFactor(c *gin.Context) {
+	if err := tfc.Log.RegisterLog(c, "Attempting to disable two-factor authentication"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.TwoFactorDisableDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = tfc.Log.RegisterLog(c, "Invalid request body for DisableTwoFactor")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	if err := tfc.Service.Disable(c.Request.Context(), middleware.Caller(c), dto.Password, dto.Code, c.ClientIP()); err != nil {
+		_ = tfc.Log.RegisterLog(c, "Disabling two-factor authentication failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = tfc.Log.RegisterLog(c, "Two-factor authentication disabled")
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary      Regenerate recovery codes
+// @Description  Replaces the recovery codes of the logged-in user. The previous codes stop working. Requires a TOTP or recovery code.
+// @Tags         two_factor
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dtos.TwoFactorCodeDTO  true  "TOTP or recovery code"
+// @Success      200   {object}  dtos.RecoveryCodesDTO  "New recovery codes"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      401   {object}  models.ProblemDetails  "Invalid two-factor code"
+// @Failure      409   {object}  models.ProblemDetails  "Two-factor authentication is not enabled"
+// @Failure      500   {object}  models.ProblemDetails  "Error regenerating the codes"
+// @Failure      423   {object}  models.ProblemDetails  "Account locked after too many failed attempts"
+// @Failure      429   {object}  models.ProblemDetails  "Too many failed attempts, retry after the indicated wait"
+// @Security     ApiKeyAuth
+// @Router       /two-factor/recovery-codes [post]
+func (tfc *TwoFactorController) RegenerateRecoveryCodes(c *gin.Context) {
+	if err := tfc.Log.RegisterLog(c, "Attempting to regenerate recovery codes"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	var dto dtos.TwoFactorCodeDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = tfc.Log.RegisterLog(c, "Invalid request body for RegenerateRecoveryCodes")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	codes, err := tfc.Service.RegenerateRecoveryCodes(c.Request.Context(), middleware.Caller(c), dto.Code, c.ClientIP())
+	if err != nil {
+		_ = tfc.Log.RegisterLog(c, "Regenerating recovery codes failed: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = tfc.Log.RegisterLog(c, "Recovery codes regenerated")
+	c.JSON(http.StatusOK, dtos.RecoveryCodesDTO{RecoveryCodes: codes})
+}
+
+// ResetUserTwoFactor godoc
+// @Summary      Reset a user's two-factor authentication
+// @Description  Removes two-factor authentication from another user, for example after losing the device and the recovery codes. If the user type requires it, the user must enroll again before logging in.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  models.MessageResponse  "Two-factor authentication reset"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "User not found"
+// @Failure      409  {object}  models.ProblemDetails  "Two-factor authentication is not enabled"
+// @Failure      500  {object}  models.ProblemDetails  "Error resetting two-factor authentication"
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/two-factor [delete]
+func (tfc *TwoFactorController) ResetUserTwoFactor(c *gin.Context) {
+	permissionId := config.PERMISSION_RESET_USER_TWO_FACTOR
+	id := c.Param("id")
+
+	if err := tfc.Log.RegisterLog(c, "Attempting to reset two-factor authentication of user with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !tfc.Auth.CheckPermission(c, permissionId) {
+		_ = tfc.Log.RegisterLog(c, "Access denied for ResetUserTwoFactor")
+		return
+	}
+
+	if err := tfc.Service.ResetForUser(c.Request.Context(), id, middleware.Caller(c)); err != nil {
+		_ = tfc.Log.RegisterLog(c, "Error resetting two-factor authentication of user with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = tfc.Log.RegisterLog(c, "Successfully reset two-factor authentication of user with ID: "+id)
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication reset"})
+}
@@ -1,18 +1,19 @@
 package controllers
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
+	"totesbackend/middleware"
 	"totesbackend/models"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 type UserController struct {
@@ -33,45 +34,43 @@ func NewUserController(service *services.UserService, auth *utilities.Authorizat
 // @Produce      json
 // @Param        id  path     string  true  "User ID"
 // @Success      200  {object}  dtos.GetUserDTO  "User details"
-// @Failure      400  {object}  models.ErrorResponse  "Invalid ID format"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "User not found"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving user"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid ID format"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "User not found"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving user"
 // @Security     ApiKeyAuth
 // @Router       /users/{id} [get]
 func (uc *UserController) GetUserByID(c *gin.Context) {
 	id := c.Param("id")
 
 	// Log de intento
-	if uc.Log.RegisterLog(c, "Attempting to retrieve user with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := uc.Log.RegisterLog(c, "Attempting to retrieve user with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	permissionId := config.PERMISSION_GET_USER_BY_ID
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for GetUserByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
-	user, err := uc.Service.GetUserByID(id)
+	user, err := uc.Service.GetUserByID(c.Request.Context(), id)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Error retrieving user with ID "+id+": "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving user"})
+		_ = c.Error(err)
 		return
 	}
 
 	if user == nil {
 		_ = uc.Log.RegisterLog(c, "User with ID "+id+" not found")
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
 	userDTO := dtos.GetUserDTO{
 		ID:          user.ID,
 		Email:       user.Email,
-		Password:    user.Password,
 		UserTypeID:  user.UserTypeID,
 		UserStateID: user.UserStateTypeID,
 	}
@@ -83,43 +82,54 @@ func (uc *UserController) GetUserByID(c *gin.Context) {
 // GetAllUsers godoc
 // @Summary      Get all users
 // @Description  Retrieves a list of all users in the system.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         users
 // @Accept       json
 // @Produce      json
-// @Success      200  {array}  dtos.GetUserDTO  "List of users"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "Users not found"
-// @Failure      500  {object}  models.ErrorResponse  "Error retrieving users"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200  {object}  dtos.PageDTO{data=[]dtos.GetUserDTO}  "List of users"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "Users not found"
+// @Failure      500  {object}  models.ProblemDetails  "Error retrieving users"
 // @Security     ApiKeyAuth
 // @Router       /users [get]
 func (uc *UserController) GetAllUsers(c *gin.Context) {
 	permissionId := config.PERMISSION_GET_ALL_USERS
 
 	// Intento de obtener todos los usuarios
-	if uc.Log.RegisterLog(c, "Attempting to retrieve all users") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := uc.Log.RegisterLog(c, "Attempting to retrieve all users"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for GetAllUsers")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
-	users, err := uc.Service.GetAllUsers()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = uc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	users, page, err := uc.Service.GetAllUsers(c.Request.Context(), query)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Error retrieving all users: "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Users not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	var usersDTO []dtos.GetUserDTO
+	usersDTO := make([]dtos.GetUserDTO, 0, len(users))
 	for _, user := range users {
 		userDTO := dtos.GetUserDTO{
 			ID:          user.ID,
 			Email:       user.Email,
-			Password:    user.Password,
 			UserTypeID:  user.UserTypeID,
 			UserStateID: user.UserStateTypeID,
 		}
@@ -127,7 +137,7 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 	}
 
 	_ = uc.Log.RegisterLog(c, "Successfully retrieved all users")
-	c.JSON(http.StatusOK, usersDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(usersDTO, page))
 }
 
 // SearchUsersByID godoc
@@ -138,10 +148,10 @@ func (uc *UserController) GetAllUsers(c *gin.Context) {
 // @Produce      json
 // @Param        id   query     string  true  "User ID to search"
 // @Success      200  {array}  dtos.GetUserDTO  "List of users matching the search criteria"
-// @Failure      400  {object}  models.ErrorResponse  "Query parameter is required"
-// @Failure      403  {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404  {object}  models.ErrorResponse  "Users not found"
-// @Failure      500  {object}  models.ErrorResponse  "Error searching users"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "Users not found"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500  {object}  models.ProblemDetails  "Error searching users"
 // @Security     ApiKeyAuth
 // @Router       /users/searchByID [get]
 func (uc *UserController) SearchUsersByID(c *gin.Context) {
@@ -149,33 +159,32 @@ func (uc *UserController) SearchUsersByID(c *gin.Context) {
 	query := c.Query("id")
 
 	// Intento de búsqueda
-	if uc.Log.RegisterLog(c, "Attempting to search users by ID with query: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := uc.Log.RegisterLog(c, "Attempting to search users by ID with query: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for SearchUsersByID")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	if query == "" {
 		_ = uc.Log.RegisterLog(c, "Query parameter 'id' is missing for SearchUsersByID")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter is required"})
+		_ = c.Error(validation.ParamError("id", "required", "", "is required"))
 		return
 	}
 
-	users, err := uc.Service.SearchUsersByID(query)
+	users, err := uc.Service.SearchUsersByID(c.Request.Context(), query)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Error searching users by ID "+query+": "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Users not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(users) == 0 {
 		_ = uc.Log.RegisterLog(c, "No users found with ID containing: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No users found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -184,7 +193,6 @@ func (uc *UserController) SearchUsersByID(c *gin.Context) {
 		userDTO := dtos.GetUserDTO{
 			ID:          user.ID,
 			Email:       user.Email,
-			Password:    user.Password,
 			UserTypeID:  user.UserTypeID,
 			UserStateID: user.UserStateTypeID,
 		}
@@ -203,10 +211,10 @@ func (uc *UserController) SearchUsersByID(c *gin.Context) {
 // @Produce      json
 // @Param        email   query     string  true  "User email to search"
 // @Success      200     {array}  dtos.GetUserDTO  "List of users matching the search criteria"
-// @Failure      400     {object}  models.ErrorResponse  "Query parameter is required"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "Users not found"
-// @Failure      500     {object}  models.ErrorResponse  "Error searching users"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404     {object}  models.ProblemDetails  "Users not found"
+// @Failure      422 {object} models.ProblemDetails "Validation failed"
+// @Failure      500     {object}  models.ProblemDetails  "Error searching users"
 // @Security     ApiKeyAuth
 // @Router       /users/searchByEmail [get]
 func (uc *UserController) SearchUsersByEmail(c *gin.Context) {
@@ -214,33 +222,32 @@ func (uc *UserController) SearchUsersByEmail(c *gin.Context) {
 	query := c.Query("email")
 
 	// Intento de búsqueda
-	if uc.Log.RegisterLog(c, "Attempting to search users by email with query: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := uc.Log.RegisterLog(c, "Attempting to search users by email with query: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for SearchUsersByEmail")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	if query == "" {
 		_ = uc.Log.RegisterLog(c, "Query parameter 'email' is missing for SearchUsersByEmail")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter is required"})
+		_ = c.Error(validation.ParamError("email", "required", "", "is required"))
 		return
 	}
 
-	users, err := uc.Service.SearchUsersByEmail(query)
+	users, err := uc.Service.SearchUsersByEmail(c.Request.Context(), query)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Error searching users by email "+query+": "+err.Error())
-		c.JSON(http.StatusNotFound, gin.H{"error": "Users not found"})
+		_ = c.Error(err)
 		return
 	}
 
 	if len(users) == 0 {
 		_ = uc.Log.RegisterLog(c, "No users found with email containing: "+query)
-		c.JSON(http.StatusNotFound, gin.H{"message": "No users found"})
+		_ = c.Error(apperrors.ErrNotFound)
 		return
 	}
 
@@ -249,7 +256,6 @@ func (uc *UserController) SearchUsersByEmail(c *gin.Context) {
 		userDTO := dtos.GetUserDTO{
 			ID:          user.ID,
 			Email:       user.Email,
-			Password:    user.Password,
 			UserTypeID:  user.UserTypeID,
 			UserStateID: user.UserStateTypeID,
 		}
@@ -273,10 +279,11 @@ type request struct {
 // @Param        id      path     string  true  "User ID"
 // @Param        body    body     request  true  "User state to update" // Updated 'object' to 'body' and set 'true'
 // @Success      200     {object}  dtos.GetUserDTO  "Updated user information"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid request body"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "User not found"
-// @Failure      500     {object}  models.ErrorResponse  "Error updating user state"
+// @Failure      400     {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      422     {object}  models.ProblemDetails  "Validation failed"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404     {object}  models.ProblemDetails  "User not found"
+// @Failure      500     {object}  models.ProblemDetails  "Error updating user state"
 // @Security     ApiKeyAuth
 // @Router       /users/{id}/state [patch]
 func (uc *UserController) UpdateUserState(c *gin.Context) {
@@ -285,30 +292,29 @@ func (uc *UserController) UpdateUserState(c *gin.Context) {
 	id := c.Param("id")
 
 	// Log de intento
-	if uc.Log.RegisterLog(c, "Attempting to update user state for ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := uc.Log.RegisterLog(c, "Attempting to update user state for ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	// Check permission
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for UpdateUserState")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	// Bind JSON request body to request struct
 	if err := c.ShouldBindJSON(&request); err != nil {
 		_ = uc.Log.RegisterLog(c, "Invalid request body for UpdateUserState: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
 	// Update user state
-	user, err := uc.Service.UpdateUserState(id, request.UserState)
+	user, err := uc.Service.UpdateUserState(c.Request.Context(), id, request.UserState)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "User not found with ID "+id+" while updating state")
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -316,7 +322,6 @@ func (uc *UserController) UpdateUserState(c *gin.Context) {
 	userDTO := dtos.GetUserDTO{
 		ID:          user.ID,
 		Email:       user.Email,
-		Password:    user.Password,
 		UserTypeID:  user.UserTypeID,
 		UserStateID: user.UserStateTypeID,
 	}
@@ -326,73 +331,158 @@ func (uc *UserController) UpdateUserState(c *gin.Context) {
 	c.JSON(http.StatusOK, userDTO)
 }
 
+// UnlockUser godoc
+// @Summary      Unlock a user account
+// @Description  Unlocks a user account locked after too many failed login attempts, without waiting for the lock to expire, and clears its failed attempts.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  dtos.GetUserDTO  "Unlocked user"
+// @Failure      403  {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404  {object}  models.ProblemDetails  "User not found"
+// @Failure      409  {object}  models.ProblemDetails  "User account is not locked"
+// @Failure      500  {object}  models.ProblemDetails  "Error unlocking user"
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/unlock [post]
+func (uc *UserController) UnlockUser(c *gin.Context) {
+	permissionId := config.PERMISSION_UNLOCK_USER
+	id := c.Param("id")
+
+	if err := uc.Log.RegisterLog(c, "Attempting to unlock user with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !uc.Auth.CheckPermission(c, permissionId) {
+		_ = uc.Log.RegisterLog(c, "Access denied for UnlockUser")
+		return
+	}
+
+	user, err := uc.Service.UnlockUser(c.Request.Context(), id, middleware.Caller(c))
+	if err != nil {
+		_ = uc.Log.RegisterLog(c, "Error unlocking user with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	userDTO := dtos.GetUserDTO{
+		ID:          user.ID,
+		Email:       user.Email,
+		UserTypeID:  user.UserTypeID,
+		UserStateID: user.UserStateTypeID,
+	}
+
+	_ = uc.Log.RegisterLog(c, "Successfully unlocked user with ID: "+id)
+	c.JSON(http.StatusOK, userDTO)
+}
+
+// SetPasswordLogin godoc
+// @Summary      Enable or disable password login for a user
+// @Description  With enabled=false the user can only log in through the company identity provider (/oidc/login); with true the password remains as a fallback.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                       true  "User ID"
+// @Param        body  body      dtos.UpdatePasswordLoginDTO  true  "Whether password login is allowed"
+// @Success      200   {object}  models.MessageResponse  "Password login updated"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      403   {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404   {object}  models.ProblemDetails  "User not found"
+// @Failure      422   {object}  models.ProblemDetails  "Validation failed"
+// @Failure      500   {object}  models.ProblemDetails  "Error updating the user"
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/password-login [patch]
+func (uc *UserController) SetPasswordLogin(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_USER_PASSWORD_LOGIN
+	id := c.Param("id")
+
+	if err := uc.Log.RegisterLog(c, "Attempting to update password login for user with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !uc.Auth.CheckPermission(c, permissionId) {
+		_ = uc.Log.RegisterLog(c, "Access denied for SetPasswordLogin")
+		return
+	}
+
+	var dto dtos.UpdatePasswordLoginDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = uc.Log.RegisterLog(c, "Invalid request body for SetPasswordLogin")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	if _, err := uc.Service.SetPasswordLogin(c.Request.Context(), id, *dto.Enabled); err != nil {
+		_ = uc.Log.RegisterLog(c, "Error updating password login for user with ID "+id+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = uc.Log.RegisterLog(c, "Successfully updated password login for user with ID: "+id)
+	c.JSON(http.StatusOK, gin.H{"message": "Password login updated"})
+}
+
 // UpdateUser godoc
 // @Summary      Update user information
-// @Description  Updates user details such as email, password, user type, and state.
+// @Description  Updates user details such as email, user type, and state. The password is changed through /password/change or the reset flow.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        id      path     string  true  "User ID"
 // @Param        body    body     dtos.UpdateUserDTO  true  "User details to update"
 // @Success      200     {object}  dtos.GetUserDTO  "Updated user information"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid request body"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "User not found"
-// @Failure      500     {object}  models.ErrorResponse  "Error updating user information"
+// @Failure      400     {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      422     {object}  models.ProblemDetails  "Validation failed"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404     {object}  models.ProblemDetails  "User not found"
+// @Failure      500     {object}  models.ProblemDetails  "Error updating user information"
 // @Security     ApiKeyAuth
 // @Router       /users/{id} [put]
 func (uc *UserController) UpdateUser(c *gin.Context) {
 	permissionId := config.PERMISSION_UPDATE_USER
 	id := c.Param("id")
 
-	if uc.Log.RegisterLog(c, "Attempting to update user with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := uc.Log.RegisterLog(c, "Attempting to update user with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for UpdateUser")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	var dto dtos.UpdateUserDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = uc.Log.RegisterLog(c, "Invalid request body for UpdateUser: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	user, err := uc.Service.GetUserByID(id)
+	user, err := uc.Service.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			_ = uc.Log.RegisterLog(c, "User not found with ID: "+id)
-			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
-			return
-		}
 		_ = uc.Log.RegisterLog(c, "Error retrieving user with ID: "+id)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		_ = c.Error(err)
 		return
 	}
 
 	user.Email = dto.Email
-	user.Password = dto.Password
 	user.UserTypeID = dto.UserTypeID
 	user.UserStateTypeID = dto.UserStateID
 
-	err = uc.Service.UpdateUser(user)
+	err = uc.Service.UpdateUser(c.Request.Context(), user)
 
 	dtoUser := dtos.GetUserDTO{
 		ID:          user.ID,
 		Email:       user.Email,
-		Password:    user.Password,
 		UserTypeID:  user.UserTypeID,
 		UserStateID: user.UserStateTypeID,
 	}
 
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Failed to update user with ID: "+id)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -402,43 +492,44 @@ func (uc *UserController) UpdateUser(c *gin.Context) {
 
 // CreateUser godoc
 // @Summary      Create a new user
-// @Description  Creates a new user with the provided email, password, user type, and state.
+// @Description  Creates a new user with the provided email, password, user type, and state. The password must satisfy the password policy.
+// @Description  To let the user choose their own password, invite them through /invitations instead.
 // @Tags         users
 // @Accept       json
 // @Produce      json
 // @Param        body    body     dtos.CreateUserDTO  true  "User details to create"
 // @Success      201     {object}  dtos.GetUserDTO  "Created user information"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid request body"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      409     {object}  models.ErrorResponse  "Email already in use"
-// @Failure      500     {object}  models.ErrorResponse  "Error creating user"
+// @Failure      400     {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      422     {object}  models.ProblemDetails  "Validation failed or password does not meet the policy"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      409     {object}  models.ProblemDetails  "Email already in use"
+// @Failure      500     {object}  models.ProblemDetails  "Error creating user"
 // @Security     ApiKeyAuth
 // @Router       /users [post]
 func (uc *UserController) CreateUser(c *gin.Context) {
 	permissionId := config.PERMISSION_CREATE_USER
 
-	if uc.Log.RegisterLog(c, "Attempting to create new user") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := uc.Log.RegisterLog(c, "Attempting to create new user"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	if !uc.Auth.CheckPermission(c, permissionId) {
 		_ = uc.Log.RegisterLog(c, "Access denied for CreateUser")
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
 
 	var dto dtos.CreateUserDTO
 	if err := c.ShouldBindJSON(&dto); err != nil {
 		_ = uc.Log.RegisterLog(c, "Invalid request body for CreateUser: "+err.Error())
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	existingUser, _ := uc.Service.GetUserByEmail(dto.Email)
+	existingUser, _ := uc.Service.GetUserByEmail(c.Request.Context(), dto.Email)
 	if existingUser != nil {
 		_ = uc.Log.RegisterLog(c, "Email already in use: "+dto.Email)
-		c.JSON(http.StatusConflict, gin.H{"error": "Email already in use"})
+		_ = c.Error(apperrors.ErrEmailTaken)
 		return
 	}
 
@@ -449,17 +540,16 @@ func (uc *UserController) CreateUser(c *gin.Context) {
 		UserStateTypeID: dto.UserStateID,
 	}
 
-	createdUser, err := uc.Service.CreateUser(&newUser)
+	createdUser, err := uc.Service.CreateUser(c.Request.Context(), &newUser)
 	if err != nil {
 		_ = uc.Log.RegisterLog(c, "Failed to create user: "+err.Error())
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
+		_ = c.Error(err)
 		return
 	}
 
 	userDTO := dtos.GetUserDTO{
 		ID:          createdUser.ID,
 		Email:       createdUser.Email,
-		Password:    createdUser.Password,
 		UserTypeID:  createdUser.UserTypeID,
 		UserStateID: createdUser.UserStateTypeID,
 	}
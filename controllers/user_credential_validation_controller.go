@@ -3,8 +3,11 @@ package controllers
 import (
 	"net/http"
 
+	"totesbackend/apperrors"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,25 +26,37 @@ func NewUserCredentialValidationController(service *services.UserCredentialValid
 type LoginData struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	// TwoFactorCode es el código TOTP o un código de recuperación; sólo para
+	// usuarios con segundo factor
+	TwoFactorCode string `json:"two_factor_code,omitempty"`
 }
 
 // ValidateUserCredentials godoc
 // @Summary      Validate user credentials
-// @Description  Validates the user's credentials (email and password) for login.
+// @Description  Validates the user's credentials (email and password) for login and opens a session.
+// @Description  The returned token is sent as Authorization: Bearer <token>. The session ends after the configured idle time, at expires_at or when it is revoked.
+// @Description  Repeated failures for the same account or client IP impose an exponentially growing wait before the next attempt (429 with Retry-After).
+// @Description  After too many consecutive failures the account is locked for a while (423); an administrator can unlock it earlier.
+// @Description  Users with two-factor authentication must also send two_factor_code (a TOTP code or a recovery code); without it the response is 401 auth.two_factor_required.
+// @Description  If the user type requires two-factor authentication and the user has not set it up, the response is 403 auth.two_factor_enrollment_required.
+// @Description  Users whose password login is disabled get 403 auth.password_login_disabled and must use single sign-on (/oidc/login).
 // @Tags         authentication
 // @Accept       json
 // @Produce      json
 // @Param        body    body     LoginData  true  "User credentials to validate"
-// @Success      200     {object}   models.MessageResponse "Login successful message"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid request body"
-// @Failure      403     {object}  models.ErrorResponse  "User account is not active"
-// @Failure      401     {object}  models.ErrorResponse  "Invalid email or password"
-// @Failure      500     {object}  models.ErrorResponse  "Error validating credentials"
+// @Success      200     {object}  dtos.LoginResultDTO  "Login successful, with the session token"
+// @Failure      400     {object}  models.ProblemDetails  "Invalid request body"
+// @Failure      422     {object}  models.ProblemDetails  "Validation failed"
+// @Failure      403     {object}  models.ProblemDetails  "User account is not active, password login is disabled or two-factor setup is required"
+// @Failure      401     {object}  models.ProblemDetails  "Invalid email, password or two-factor code, or two-factor code missing"
+// @Failure      423     {object}  models.ProblemDetails  "Account locked after too many failed attempts"
+// @Failure      429     {object}  models.ProblemDetails  "Too many failed attempts, retry after the indicated wait"
+// @Failure      500     {object}  models.ProblemDetails  "Error validating credentials"
 // @Security     ApiKeyAuth
 // @Router       /login [post]
 func (ucvc *UserCredentialValidationController) ValidateUserCredentials(c *gin.Context) {
-	if ucvc.Log.RegisterLog(c, "Attempting user login") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ucvc.Log.RegisterLog(c, "Attempting user login"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
@@ -49,27 +64,25 @@ func (ucvc *UserCredentialValidationController) ValidateUserCredentials(c *gin.C
 
 	if err := c.ShouldBindJSON(&loginData); err != nil {
 		_ = ucvc.Log.RegisterLog(c, "Invalid request body for login")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		_ = c.Error(validation.BindError(err))
 		return
 	}
 
-	err := ucvc.Service.ValidateUserCredentials(loginData.Email, loginData.Password)
+	session, token, err := ucvc.Service.ValidateUserCredentials(c.Request.Context(), loginData.Email, loginData.Password,
+		loginData.TwoFactorCode, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
-		if err.Error() == "user is not active" {
-			_ = ucvc.Log.RegisterLog(c, "Login attempt for inactive user: "+loginData.Email)
-			c.JSON(http.StatusForbidden, gin.H{"error": "User account is not active"})
-			return
-		}
-
-		_ = ucvc.Log.RegisterLog(c, "Login failed for user: "+loginData.Email)
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		_ = ucvc.Log.RegisterLog(c, "Login failed for user: "+loginData.Email+": "+err.Error())
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ucvc.Log.RegisterLog(c, "Login successful for user: "+loginData.Email)
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Login successful",
+	c.JSON(http.StatusOK, dtos.LoginResultDTO{
+		Message:   "Login successful",
+		Token:     token,
+		SessionID: session.ID,
+		ExpiresAt: session.ExpiresAt,
 	})
 
 }
@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"net/http"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
+	"totesbackend/dtos"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
@@ -27,9 +29,9 @@ func NewUserStateTypeController(service *services.UserStateTypeService, auth *ut
 // @Produce      json
 // @Param        id      path     string  true  "User State Type ID"
 // @Success      200     {object}  models.UserStateType  "User State Type details"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "User State Type not found"
-// @Failure      500     {object}  models.ErrorResponse  "Internal server error"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404     {object}  models.ProblemDetails  "User State Type not found"
+// @Failure      500     {object}  models.ProblemDetails  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /user-state-types/{id} [get]
 func (ustc *UserStateTypeController) GetUserStateTypeByID(c *gin.Context) {
@@ -42,14 +44,14 @@ func (ustc *UserStateTypeController) GetUserStateTypeByID(c *gin.Context) {
 
 	id := c.Param("id")
 
-	if ustc.Log.RegisterLog(c, "Attempting to retrieve user state type with ID: "+id) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ustc.Log.RegisterLog(c, "Attempting to retrieve user state type with ID: "+id); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	userStateType, err := ustc.Service.GetUserStateTypeByID(id)
+	userStateType, err := ustc.Service.GetUserStateTypeByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User State Type not found"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -61,12 +63,18 @@ func (ustc *UserStateTypeController) GetUserStateTypeByID(c *gin.Context) {
 // GetAllUserStateTypes godoc
 // @Summary      Get all user state types
 // @Description  Retrieves a list of all user state types available in the system.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         user_state_types
 // @Accept       json
 // @Produce      json
-// @Success      200     {array}   models.UserStateType  "List of User State Types"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Internal server error"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200     {object}   dtos.PageDTO{data=[]models.UserStateType}  "List of User State Types"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500     {object}  models.ProblemDetails  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /user-state-types [get]
 func (ustc *UserStateTypeController) GetAllUserStateTypes(c *gin.Context) {
@@ -77,18 +85,25 @@ func (ustc *UserStateTypeController) GetAllUserStateTypes(c *gin.Context) {
 		return
 	}
 
-	if ustc.Log.RegisterLog(c, "Attempting to retrieve all user state types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := ustc.Log.RegisterLog(c, "Attempting to retrieve all user state types"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	userStateTypes, err := ustc.Service.GetAllUserStateTypes()
+	query, err := utilities.ParseListQuery(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving User State Types"})
+		_ = ustc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	userStateTypes, page, err := ustc.Service.GetAllUserStateTypes(c.Request.Context(), query)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
 	_ = ustc.Log.RegisterLog(c, "Successfully retrieved all user state types")
 
-	c.JSON(http.StatusOK, userStateTypes)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(userStateTypes, page))
 }
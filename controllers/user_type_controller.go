@@ -3,10 +3,12 @@ package controllers
 import (
 	"fmt"
 	"net/http"
+	"totesbackend/apperrors"
 	"totesbackend/config"
 	"totesbackend/controllers/utilities"
 	"totesbackend/dtos"
 	"totesbackend/services"
+	"totesbackend/validation"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,10 +31,10 @@ func NewUserTypeController(service *services.UserTypeService, auth *utilities.Au
 // @Produce      json
 // @Param        id      path     int     true  "User Type ID"
 // @Success      200     {object}  dtos.UserTypeDTO  "User type information"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid user type ID format"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      404     {object}  models.ErrorResponse  "User type not found"
-// @Failure      500     {object}  models.ErrorResponse  "Internal server error"
+// @Failure      400     {object}  models.ProblemDetails  "Invalid user type ID format"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404     {object}  models.ProblemDetails  "User type not found"
+// @Failure      500     {object}  models.ProblemDetails  "Internal server error"
 // @Security     ApiKeyAuth
 // @Router       /user-types/{id} [get]
 func (utc *UserTypeController) GetUserTypeByID(c *gin.Context) {
@@ -44,37 +46,38 @@ func (utc *UserTypeController) GetUserTypeByID(c *gin.Context) {
 
 	idParam := c.Param("id")
 
-	if utc.Log.RegisterLog(c, "Attempting to retrieve user type with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := utc.Log.RegisterLog(c, "Attempting to retrieve user type with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		_ = utc.Log.RegisterLog(c, "Invalid user type ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user type ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	userType, err := utc.Service.GetUserTypeByID(id)
+	userType, err := utc.Service.GetUserTypeByID(c.Request.Context(), id)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "User type not found with ID: "+idParam)
-		c.JSON(http.StatusNotFound, gin.H{"error": "User type not found"})
+		_ = c.Error(err)
 		return
 	}
 
-	roleIDs, err := utc.Service.GetRolesForUserType(id)
+	roleIDs, err := utc.Service.GetRolesForUserType(c.Request.Context(), id)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error retrieving roles for user type with ID: "+idParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving roles for user type"})
+		_ = c.Error(err)
 		return
 	}
 
 	userTypeDTO := dtos.UserTypeDTO{
-		ID:          userType.ID,
-		Name:        userType.Name,
-		Description: userType.Description,
-		Roles:       make([]string, len(roleIDs)),
+		ID:               userType.ID,
+		Name:             userType.Name,
+		Description:      userType.Description,
+		RequireTwoFactor: userType.RequireTwoFactor,
+		Roles:            make([]string, len(roleIDs)),
 	}
 
 	for i, roleID := range roleIDs {
@@ -88,12 +91,18 @@ func (utc *UserTypeController) GetUserTypeByID(c *gin.Context) {
 // GetAllUserTypes godoc
 // @Summary      Get all user types
 // @Description  Retrieves a list of all user types along with their associated roles.
+// @Description  Any other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.
 // @Tags         user_types
 // @Accept       json
 // @Produce      json
-// @Success      200     {array}   dtos.UserTypeDTO  "List of user types"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Error retrieving user types"
+// @Param        limit   query  int     false  "Page size (default 50, max 200)"
+// @Param        page    query  int     false  "Page number, cannot be combined with cursor"
+// @Param        cursor  query  string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        sort    query  string  false  "Comma-separated fields to sort by, prefixed with - for descending order"
+// @Success      200     {object}   dtos.PageDTO{data=[]dtos.UserTypeDTO}  "List of user types"
+// @Failure      400  {object}  models.ProblemDetails  "Invalid pagination, sort or filter parameters"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500     {object}  models.ProblemDetails  "Error retrieving user types"
 // @Security     ApiKeyAuth
 // @Router       /user-types [get]
 func (utc *UserTypeController) GetAllUserTypes(c *gin.Context) {
@@ -103,32 +112,40 @@ func (utc *UserTypeController) GetAllUserTypes(c *gin.Context) {
 		return
 	}
 
-	if utc.Log.RegisterLog(c, "Attempting to retrieve all user types") != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := utc.Log.RegisterLog(c, "Attempting to retrieve all user types"); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	userTypes, err := utc.Service.ObtainAllUserTypes()
+	query, err := utilities.ParseListQuery(c)
+	if err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid list query: "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	userTypes, page, err := utc.Service.ObtainAllUserTypes(c.Request.Context(), query)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error retrieving all user types")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving user types"})
+		_ = c.Error(err)
 		return
 	}
 
-	var userTypesDTO []dtos.UserTypeDTO
+	userTypesDTO := make([]dtos.UserTypeDTO, 0, len(userTypes))
 	for _, userType := range userTypes {
-		roleIDs, err := utc.Service.GetRolesForUserType(userType.ID)
+		roleIDs, err := utc.Service.GetRolesForUserType(c.Request.Context(), userType.ID)
 		if err != nil {
 			_ = utc.Log.RegisterLog(c, fmt.Sprintf("Error retrieving roles for user type ID: %d", userType.ID))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving roles for user type"})
+			_ = c.Error(err)
 			return
 		}
 
 		userTypeDTO := dtos.UserTypeDTO{
-			ID:          userType.ID,
-			Name:        userType.Name,
-			Description: userType.Description,
-			Roles:       make([]string, len(roleIDs)),
+			ID:               userType.ID,
+			Name:             userType.Name,
+			Description:      userType.Description,
+			RequireTwoFactor: userType.RequireTwoFactor,
+			Roles:            make([]string, len(roleIDs)),
 		}
 
 		for i, roleID := range roleIDs {
@@ -139,7 +156,7 @@ func (utc *UserTypeController) GetAllUserTypes(c *gin.Context) {
 	}
 
 	_ = utc.Log.RegisterLog(c, "Successfully retrieved all user types")
-	c.JSON(http.StatusOK, userTypesDTO)
+	c.JSON(http.StatusOK, dtos.NewPageDTO(userTypesDTO, page))
 }
 
 // ExistsUserType godoc
@@ -150,9 +167,9 @@ func (utc *UserTypeController) GetAllUserTypes(c *gin.Context) {
 // @Produce      json
 // @Param        id      path     string  true  "User Type ID"
 // @Success      200     {object}  models.MessageResponse "Existence status of the user type"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid user type ID"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Error checking user type existence"
+// @Failure      400     {object}  models.ProblemDetails  "Invalid user type ID"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500     {object}  models.ProblemDetails  "Error checking user type existence"
 // @Security     ApiKeyAuth
 // @Router       /user-types/{id}/exists [get]
 func (utc *UserTypeController) ExistsUserType(c *gin.Context) {
@@ -164,22 +181,22 @@ func (utc *UserTypeController) ExistsUserType(c *gin.Context) {
 
 	idParam := c.Param("id")
 
-	if utc.Log.RegisterLog(c, "Attempting to check existence of user type with ID: "+idParam) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := utc.Log.RegisterLog(c, "Attempting to check existence of user type with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
 	var id uint
 	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
 		_ = utc.Log.RegisterLog(c, "Invalid user type ID format: "+idParam)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user type ID"})
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", c.Param("id")))
 		return
 	}
 
-	exists, err := utc.Service.Exists(id)
+	exists, err := utc.Service.Exists(c.Request.Context(), id)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error checking existence for user type ID: "+idParam)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking user type existence"})
+		_ = c.Error(err)
 		return
 	}
 
@@ -195,9 +212,9 @@ func (utc *UserTypeController) ExistsUserType(c *gin.Context) {
 // @Produce      json
 // @Param        id      query    string  true  "User Type ID Query"
 // @Success      200     {array}   dtos.UserTypeDTO  "List of user types matching the ID query"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid query parameter"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Error searching user types"
+// @Failure      400     {object}  models.ProblemDetails  "Invalid query parameter"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500     {object}  models.ProblemDetails  "Error searching user types"
 // @Security     ApiKeyAuth
 // @Router       /user-types/searchByID [get]
 func (utc *UserTypeController) SearchUserTypesByID(c *gin.Context) {
@@ -209,27 +226,28 @@ func (utc *UserTypeController) SearchUserTypesByID(c *gin.Context) {
 
 	query := c.Query("id")
 
-	if utc.Log.RegisterLog(c, "Attempting to search user types by ID: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := utc.Log.RegisterLog(c, "Attempting to search user types by ID: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	userTypes, err := utc.Service.SearchUserTypesByID(query)
+	userTypes, err := utc.Service.SearchUserTypesByID(c.Request.Context(), query)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error retrieving user types by ID query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving user types"})
+		_ = c.Error(err)
 		return
 	}
 
 	var userTypesDTO []dtos.UserTypeDTO
 	for _, userType := range userTypes {
-		roleIDs, _ := utc.Service.GetRolesForUserType(userType.ID)
+		roleIDs, _ := utc.Service.GetRolesForUserType(c.Request.Context(), userType.ID)
 
 		userTypeDTO := dtos.UserTypeDTO{
-			ID:          userType.ID,
-			Name:        userType.Name,
-			Description: userType.Description,
-			Roles:       make([]string, len(roleIDs)),
+			ID:               userType.ID,
+			Name:             userType.Name,
+			Description:      userType.Description,
+			RequireTwoFactor: userType.RequireTwoFactor,
+			Roles:            make([]string, len(roleIDs)),
 		}
 
 		for i, roleID := range roleIDs {
@@ -251,9 +269,9 @@ func (utc *UserTypeController) SearchUserTypesByID(c *gin.Context) {
 // @Produce      json
 // @Param        name    query    string  true  "User Type Name Query"
 // @Success      200     {array}   dtos.UserTypeDTO  "List of user types matching the name query"
-// @Failure      400     {object}  models.ErrorResponse  "Invalid query parameter"
-// @Failure      403     {object}  models.ErrorResponse  "Permission denied"
-// @Failure      500     {object}  models.ErrorResponse  "Error searching user types"
+// @Failure      400     {object}  models.ProblemDetails  "Invalid query parameter"
+// @Failure      403     {object}  models.ProblemDetails  "Permission denied"
+// @Failure      500     {object}  models.ProblemDetails  "Error searching user types"
 // @Security     ApiKeyAuth
 // @Router       /user-types/searchByName [get]
 func (utc *UserTypeController) SearchUserTypesByName(c *gin.Context) {
@@ -265,27 +283,28 @@ func (utc *UserTypeController) SearchUserTypesByName(c *gin.Context) {
 
 	query := c.Query("name")
 
-	if utc.Log.RegisterLog(c, "Attempting to search user types by name: "+query) != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error registering log"})
+	if err := utc.Log.RegisterLog(c, "Attempting to search user types by name: "+query); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return
 	}
 
-	userTypes, err := utc.Service.SearchUserTypesByName(query)
+	userTypes, err := utc.Service.SearchUserTypesByName(c.Request.Context(), query)
 	if err != nil {
 		_ = utc.Log.RegisterLog(c, "Error retrieving user types by name query: "+query)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving user types"})
+		_ = c.Error(err)
 		return
 	}
 
 	var userTypesDTO []dtos.UserTypeDTO
 	for _, userType := range userTypes {
-		roleIDs, _ := utc.Service.GetRolesForUserType(userType.ID)
+		roleIDs, _ := utc.Service.GetRolesForUserType(c.Request.Context(), userType.ID)
 
 		userTypeDTO := dtos.UserTypeDTO{
-			ID:          userType.ID,
-			Name:        userType.Name,
-			Description: userType.Description,
-			Roles:       make([]string, len(roleIDs)),
+			ID:               userType.ID,
+			Name:             userType.Name,
+			Description:      userType.Description,
+			RequireTwoFactor: userType.RequireTwoFactor,
+			Roles:            make([]string, len(roleIDs)),
 		}
 
 		for i, roleID := range roleIDs {
@@ -298,3 +317,57 @@ func (utc *UserTypeController) SearchUserTypesByName(c *gin.Context) {
 	_ = utc.Log.RegisterLog(c, "Successfully searched user types by name query: "+query)
 	c.JSON(http.StatusOK, userTypesDTO)
 }
+
+// SetUserTypeTwoFactor godoc
+// @Summary      Require two-factor authentication for a user type
+// @Description  Makes two-factor authentication mandatory (or optional) for every user of the user type.
+// @Description  Users without it set up cannot log in until they enroll.
+// @Tags         user_types
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int  true  "User Type ID"
+// @Param        body  body      dtos.UpdateUserTypeTwoFactorDTO  true  "Two-factor policy"
+// @Success      200   {object}  models.MessageResponse  "Policy updated"
+// @Failure      400   {object}  models.ProblemDetails  "Invalid user type ID or request body"
+// @Failure      403   {object}  models.ProblemDetails  "Permission denied"
+// @Failure      404   {object}  models.ProblemDetails  "User type not found"
+// @Failure      500   {object}  models.ProblemDetails  "Error updating the user type"
+// @Security     ApiKeyAuth
+// @Router       /user-types/{id}/two-factor [patch]
+func (utc *UserTypeController) SetUserTypeTwoFactor(c *gin.Context) {
+	permissionId := config.PERMISSION_UPDATE_USER_TYPE_TWO_FACTOR
+	idParam := c.Param("id")
+
+	if err := utc.Log.RegisterLog(c, "Attempting to update two-factor policy of user type with ID: "+idParam); err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return
+	}
+
+	if !utc.Auth.CheckPermission(c, permissionId) {
+		_ = utc.Log.RegisterLog(c, "Access denied for SetUserTypeTwoFactor")
+		return
+	}
+
+	var id uint
+	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid user type ID format: "+idParam)
+		_ = c.Error(apperrors.ErrBadRequest.WithDetail("id", idParam))
+		return
+	}
+
+	var dto dtos.UpdateUserTypeTwoFactorDTO
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		_ = utc.Log.RegisterLog(c, "Invalid request body for SetUserTypeTwoFactor")
+		_ = c.Error(validation.BindError(err))
+		return
+	}
+
+	if err := utc.Service.SetRequireTwoFactor(c.Request.Context(), id, *dto.Required); err != nil {
+		_ = utc.Log.RegisterLog(c, "Error updating two-factor policy of user type with ID "+idParam+": "+err.Error())
+		_ = c.Error(err)
+		return
+	}
+
+	_ = utc.Log.RegisterLog(c, "Successfully updated two-factor policy of user type with ID: "+idParam)
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor policy updated"})
+}
@@ -1,8 +1,15 @@
 package utilities
 
 import (
-	"net/http"
+	"log/slog"
+	"strconv"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/datascope"
+	"totesbackend/metrics"
+	"totesbackend/middleware"
 	"totesbackend/services"
+	"totesbackend/visibility"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,19 +22,67 @@ func NewAuthorizationUtil(service *services.AuthorizationService) *Authorization
 	return &AuthorizationUtil{Service: service}
 }
 
+// CheckPermission indica si el usuario de la sesión tiene el permiso.
+// Si la petición se autenticó con una llave de API se revisan los permisos de
+// la llave. Si no lo tiene, registra el error en el contexto para que el
+// middleware de errores responda; el controlador sólo debe retornar.
+// Si los roles del usuario limitan el permiso a sus propios registros, deja el
+// alcance en el contexto de la petición para que lo apliquen los repositorios.
 func (u *AuthorizationUtil) CheckPermission(c *gin.Context, permissionID int) bool {
-	username := c.GetHeader("Username")
-	authResult, err := u.Service.UserHasPermission(username, permissionID)
+	var authResult bool
+	var err error
+	if key, ok := middleware.APIKeyFromContext(c); ok {
+		authResult = key.HasPermission(permissionID)
+	} else {
+		authResult, err = u.Service.UserHasPermission(c.Request.Context(), middleware.Caller(c), permissionID)
+	}
 
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Authorization service error"})
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
 		return false
 	}
 
 	if !authResult {
-		c.JSON(http.StatusForbidden, gin.H{"error": "User does not have permission"})
+		metrics.PermissionDenials.WithLabelValues(strconv.Itoa(permissionID)).Inc()
+		_ = c.Error(apperrors.ErrForbidden.WithDetail("permission_id", permissionID))
 		return false
 	}
 
+	if _, ok := middleware.APIKeyFromContext(c); ok {
+		return true
+	}
+	scope, scoped, err := u.Service.GetScope(c.Request.Context(), middleware.Caller(c), permissionID)
+	if err != nil {
+		_ = c.Error(apperrors.ErrInternal.Wrap(err))
+		return false
+	}
+	if scoped {
+		c.Request = c.Request.WithContext(datascope.WithScope(c.Request.Context(), scope))
+	}
+
 	return true
 }
+
+// JSON responde obj como c.JSON, sin los campos sensibles que quien hace la
+// petición no puede ver (ver visibility.Filter y config.FieldPermissions).
+func (u *AuthorizationUtil) JSON(c *gin.Context, code int, obj interface{}) {
+	c.JSON(code, visibility.Filter(obj, func(class string) bool { return u.canSee(c, class) }))
+}
+
+// canSee indica si quien hace la petición tiene el permiso de la clase de
+// campos. Un error al consultarlo se registra y oculta los campos.
+func (u *AuthorizationUtil) canSee(c *gin.Context, class string) bool {
+	permissionID, ok := config.FieldPermissions[class]
+	if !ok {
+		return false
+	}
+	if key, ok := middleware.APIKeyFromContext(c); ok {
+		return key.HasPermission(permissionID)
+	}
+	allowed, err := u.Service.UserHasPermission(c.Request.Context(), middleware.Caller(c), permissionID)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "checking field visibility failed", "class", class, "error", err)
+		return false
+	}
+	return allowed
+}
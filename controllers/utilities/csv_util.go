@@ -0,0 +1,34 @@
+package utilities
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WantsCSV indica si el cliente pidió la respuesta en CSV, con el parámetro
+// format=csv o con la cabecera Accept: text/csv.
+func WantsCSV(c *gin.Context) bool {
+	if format := c.Query("format"); format != "" {
+		return strings.EqualFold(format, "csv")
+	}
+	return strings.Contains(c.GetHeader("Accept"), "text/csv")
+}
+
+// WriteCSV responde con un archivo CSV con la cabecera y los registros dados.
+func WriteCSV(c *gin.Context, filename string, header []string, records [][]string) error {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename=\""+filename+"\"")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,70 @@
+package utilities
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"totesbackend/dtos"
+
+	"github.com/gin-gonic/gin"
+)
+
+// filterParam reconoce los filtros de los listados: campo=valor o campo[operador]=valor.
+var filterParam = regexp.MustCompile(`^(\w+)(?:\[(\w+)\])?$`)
+
+var filterOperators = map[string]bool{
+	dtos.FilterEq: true, dtos.FilterNe: true, dtos.FilterGt: true, dtos.FilterGte: true,
+	dtos.FilterLt: true, dtos.FilterLte: true, dtos.FilterLike: true, dtos.FilterIn: true,
+}
+
+// ParseListQuery lee los parámetros comunes de los listados: limit, page o
+// cursor, sort (campos separados por coma, con - para orden descendente) y
+// cualquier otro parámetro como filtro, por ejemplo total[gte]=100.
+func ParseListQuery(c *gin.Context) (dtos.ListQuery, error) {
+	var query dtos.ListQuery
+
+	for key, values := range c.Request.URL.Query() {
+		for _, value := range values {
+			switch key {
+			case "limit":
+				limit, err := strconv.Atoi(value)
+				if err != nil || limit <= 0 {
+					return query, fmt.Errorf("%w: limit must be a positive integer", dtos.ErrInvalidListQuery)
+				}
+				query.Limit = limit
+			case "page":
+				page, err := strconv.Atoi(value)
+				if err != nil || page <= 0 {
+					return query, fmt.Errorf("%w: page must be a positive integer", dtos.ErrInvalidListQuery)
+				}
+				query.Page = page
+			case "cursor":
+				query.Cursor = value
+			case "sort":
+				for _, field := range strings.Split(value, ",") {
+					field = strings.TrimSpace(field)
+					if field == "" {
+						continue
+					}
+					desc := strings.HasPrefix(field, "-")
+					query.Sort = append(query.Sort, dtos.SortField{Field: strings.TrimPrefix(field, "-"), Desc: desc})
+				}
+			default:
+				match := filterParam.FindStringSubmatch(key)
+				if match == nil {
+					return query, fmt.Errorf("%w: invalid filter %q", dtos.ErrInvalidListQuery, key)
+				}
+				operator := match[2]
+				if operator == "" {
+					operator = dtos.FilterEq
+				}
+				if !filterOperators[operator] {
+					return query, fmt.Errorf("%w: unknown operator %q", dtos.ErrInvalidListQuery, operator)
+				}
+				query.Filters = append(query.Filters, dtos.ListFilter{Field: match[1], Operator: operator, Value: value})
+			}
+		}
+	}
+	return query, nil
+}
@@ -1,12 +1,17 @@
 package utilities
 
 import (
-	"errors"
+	"totesbackend/middleware"
 	"totesbackend/services"
 
 	"github.com/gin-gonic/gin"
 )
 
+// anonymousSubject es la identidad con la que se registran las peticiones a
+// las rutas públicas (login, recuperación de contraseña...) que no traen
+// credenciales.
+const anonymousSubject = "anonymous"
+
 type LogUtil struct {
 	LogService *services.UserLogService
 }
@@ -15,13 +20,15 @@ func NewLogUtil(logService *services.UserLogService) *LogUtil {
 	return &LogUtil{LogService: logService}
 }
 
+// RegisterLog guarda el mensaje a nombre de quien hace la petición, resuelto
+// por los middleware de autenticación (ver middleware.Caller).
 func (l *LogUtil) RegisterLog(c *gin.Context, logMessage string) error {
-	userEmail := c.GetHeader("Username")
+	userEmail := middleware.Caller(c)
 	if userEmail == "" {
-		return errors.New("missing Username header")
+		userEmail = anonymousSubject
 	}
 
-	_, err := l.LogService.CreateUserLog(userEmail, logMessage)
+	_, err := l.LogService.CreateUserLog(c.Request.Context(), userEmail, logMessage)
 	if err != nil {
 		return err
 	}
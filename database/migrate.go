@@ -0,0 +1,287 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// ErrDatabaseNotMigrated indica que la base tiene migraciones pendientes.
+var ErrDatabaseNotMigrated = errors.New("database has pending migrations")
+
+// migrationLockID identifica el candado de Postgres que serializa las
+// migraciones cuando arrancan varias instancias a la vez.
+const migrationLockID = 7310442
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration es una migración versionada con su script de subida y de bajada.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus indica si una migración está aplicada y desde cuándo.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// EnsureSchema se llama al arrancar. Con autoMigrate aplica las migraciones
+// pendientes; sin él se niega a continuar si falta alguna.
+func EnsureSchema(autoMigrate bool) error {
+	if autoMigrate {
+		_, err := MigrateUp()
+		return err
+	}
+	return CheckMigrations()
+}
+
+// CheckMigrations devuelve ErrDatabaseNotMigrated si hay migraciones sin aplicar.
+func CheckMigrations() error {
+	statuses, err := MigrationStatuses()
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d pending, run \"migrate up\" or start with DB_AUTO_MIGRATE=true", ErrDatabaseNotMigrated, pending)
+	}
+	return nil
+}
+
+// MigrateUp aplica en orden las migraciones pendientes, cada una en su propia
+// transacción, y devuelve cuántas aplicó.
+func MigrateUp() (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if err := ensureMigrationsTable(); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, migration := range migrations {
+		done, err := applyMigration(migration)
+		if err != nil {
+			return applied, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if done {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// MigrateDown deshace las últimas steps migraciones aplicadas y devuelve
+// cuántas deshizo.
+func MigrateDown(steps int) (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if err := ensureMigrationsTable(); err != nil {
+		return 0, err
+	}
+
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	var applied []schemaMigration
+	if err := db.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+		return 0, err
+	}
+
+	reverted := 0
+	for _, row := range applied {
+		migration, ok := byVersion[row.Version]
+		if !ok {
+			return reverted, fmt.Errorf("migration %04d_%s is applied but its files are missing", row.Version, row.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+				return err
+			}
+			if err := execScript(tx, migration.Down); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, "version = ?", migration.Version).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		reverted++
+	}
+	return reverted, nil
+}
+
+// LatestMigrationVersion devuelve la versión de la última migración embebida,
+// es decir, la versión de esquema que espera este binario.
+func LatestMigrationVersion() (int, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// MigrationStatuses lista todas las migraciones conocidas con su estado.
+func MigrationStatuses() ([]MigrationStatus, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt := map[int]time.Time{}
+	if db.Migrator().HasTable(&schemaMigration{}) {
+		var rows []schemaMigration
+		if err := db.Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			appliedAt[row.Version] = row.AppliedAt
+		}
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if at, ok := appliedAt[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func ensureMigrationsTable() error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name varchar(255) NOT NULL,
+		applied_at timestamptz NOT NULL)`).Error
+}
+
+// applyMigration aplica la migración si nadie la aplicó antes. La comprobación
+// se hace con el candado tomado para que dos instancias no la repitan.
+func applyMigration(migration Migration) (bool, error) {
+	applied := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&schemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+
+		if err := execScript(tx, migration.Up); err != nil {
+			return err
+		}
+		applied = true
+		return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+	})
+	return applied, err
+}
+
+// execScript ejecuta un script completo. Sin argumentos pgx usa el protocolo
+// simple, que admite varias sentencias en una sola llamada.
+func execScript(tx *gorm.DB, script string) error {
+	if !hasStatements(script) {
+		return nil
+	}
+	return tx.Exec(script).Error
+}
+
+func hasStatements(script string) bool {
+	for _, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return true
+		}
+	}
+	return false
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %q and %q", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
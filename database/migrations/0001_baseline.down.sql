@@ -0,0 +1,31 @@
+-- Elimina todas las tablas del esquema base.
+
+DROP TABLE IF EXISTS "external_sales";
+DROP TABLE IF EXISTS "purchase_order_items";
+DROP TABLE IF EXISTS "invoice_items";
+DROP TABLE IF EXISTS "invoice_discounts";
+DROP TABLE IF EXISTS "invoice_taxes";
+DROP TABLE IF EXISTS "invoices";
+DROP TABLE IF EXISTS "purchase_order_taxes";
+DROP TABLE IF EXISTS "tax_types";
+DROP TABLE IF EXISTS "purchase_order_discounts";
+DROP TABLE IF EXISTS "discount_types";
+DROP TABLE IF EXISTS "purchase_orders";
+DROP TABLE IF EXISTS "order_state_types";
+DROP TABLE IF EXISTS "appointments";
+DROP TABLE IF EXISTS "customers";
+DROP TABLE IF EXISTS "user_logs";
+DROP TABLE IF EXISTS "comments";
+DROP TABLE IF EXISTS "historical_item_prices";
+DROP TABLE IF EXISTS "employees";
+DROP TABLE IF EXISTS "users";
+DROP TABLE IF EXISTS "user_state_types";
+DROP TABLE IF EXISTS "identifier_types";
+DROP TABLE IF EXISTS "user_type_has_role";
+DROP TABLE IF EXISTS "user_types";
+DROP TABLE IF EXISTS "role_permission";
+DROP TABLE IF EXISTS "roles";
+DROP TABLE IF EXISTS "permissions";
+DROP TABLE IF EXISTS "additional_expenses";
+DROP TABLE IF EXISTS "items";
+DROP TABLE IF EXISTS "item_types";
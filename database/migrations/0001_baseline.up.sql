@@ -0,0 +1,284 @@
+-- Esquema base tal como lo dejaba AutoMigrate antes de las migraciones
+-- versionadas. Usa IF NOT EXISTS para poder registrarse sobre esas bases; las
+-- columnas y tablas posteriores se agregan en 0002.
+
+CREATE TABLE IF NOT EXISTS "item_types" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "items" (
+    "id" bigserial,
+    "name" varchar(255) NOT NULL,
+    "description" varchar(300),
+    "stock" bigint NOT NULL,
+    "selling_price" decimal NOT NULL,
+    "purchase_price" decimal NOT NULL,
+    "item_state" boolean NOT NULL,
+    "item_type_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_items_item_type" FOREIGN KEY ("item_type_id") REFERENCES "item_types"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "additional_expenses" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "item_id" bigint NOT NULL,
+    "expense" decimal NOT NULL,
+    "description" varchar(200),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_items_additional_expenses" FOREIGN KEY ("item_id") REFERENCES "items"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_additional_expenses_item_id" ON "additional_expenses" ("item_id");
+
+CREATE TABLE IF NOT EXISTS "permissions" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "description" varchar(300),
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "roles" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "description" varchar(300),
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "role_permission" (
+    "role_id" bigint,
+    "permission_id" bigint,
+    PRIMARY KEY ("role_id","permission_id"),
+    CONSTRAINT "fk_role_permission_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id"),
+    CONSTRAINT "fk_role_permission_permission" FOREIGN KEY ("permission_id") REFERENCES "permissions"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "user_types" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "description" varchar(300),
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "user_type_has_role" (
+    "user_type_id" bigint,
+    "role_id" bigint,
+    PRIMARY KEY ("user_type_id","role_id"),
+    CONSTRAINT "fk_user_type_has_role_user_type" FOREIGN KEY ("user_type_id") REFERENCES "user_types"("id"),
+    CONSTRAINT "fk_user_type_has_role_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "identifier_types" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "user_state_types" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "email" varchar(80) NOT NULL,
+    "password" varchar(100) NOT NULL,
+    "user_state_type_id" bigint NOT NULL,
+    "user_type_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_user_type" FOREIGN KEY ("user_type_id") REFERENCES "user_types"("id"),
+    CONSTRAINT "fk_users_user_state_type" FOREIGN KEY ("user_state_type_id") REFERENCES "user_state_types"("id"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email")
+);
+
+CREATE TABLE IF NOT EXISTS "employees" (
+    "id" bigserial,
+    "names" varchar(100) NOT NULL,
+    "last_names" varchar(100) NOT NULL,
+    "personal_id" varchar(50) NOT NULL,
+    "address" varchar(200),
+    "phone_numbers" varchar(50),
+    "user_id" bigint NOT NULL,
+    "identifier_type_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_employees_user" FOREIGN KEY ("user_id") REFERENCES "users"("id"),
+    CONSTRAINT "fk_employees_identifier_type" FOREIGN KEY ("identifier_type_id") REFERENCES "identifier_types"("id"),
+    CONSTRAINT "uni_employees_personal_id" UNIQUE ("personal_id")
+);
+
+CREATE TABLE IF NOT EXISTS "historical_item_prices" (
+    "id" bigserial,
+    "item_id" bigint NOT NULL,
+    "price" decimal NOT NULL,
+    "added_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_historical_item_prices_item_id" ON "historical_item_prices" ("item_id");
+
+CREATE TABLE IF NOT EXISTS "comments" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "last_name" varchar(100) NOT NULL,
+    "email" varchar(80) NOT NULL,
+    "phone" varchar(50),
+    "residence_state" varchar(50),
+    "residence_city" varchar(50),
+    "comment" varchar(1000),
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "user_logs" (
+    "id" bigserial,
+    "user_email" varchar(80) NOT NULL,
+    "log" varchar(500) NOT NULL,
+    "date_time" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "customers" (
+    "id" bigserial,
+    "customer_name" varchar(255),
+    "customer_id" varchar(100) NOT NULL,
+    "is_business" boolean NOT NULL,
+    "address" varchar(100),
+    "phone_numbers" varchar(100),
+    "customer_state" boolean NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "last_name" varchar(255) NOT NULL,
+    "identifier_type_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_customers_customer_id" UNIQUE ("customer_id"),
+    CONSTRAINT "uni_customers_email" UNIQUE ("email")
+);
+
+CREATE TABLE IF NOT EXISTS "appointments" (
+    "id" bigserial,
+    "date_time" timestamp NOT NULL,
+    "state" boolean NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "customer_name" varchar(255) NOT NULL,
+    "is_business" boolean NOT NULL,
+    "address" varchar(100),
+    "phone_numbers" varchar(100),
+    "customer_state" boolean NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "last_name" varchar(255) NOT NULL,
+    "identifier_type_id" bigint NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_appointments_customer_id" ON "appointments" ("customer_id");
+
+CREATE TABLE IF NOT EXISTS "order_state_types" (
+    "id" bigserial,
+    "description" varchar(300) NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "purchase_orders" (
+    "id" bigserial,
+    "seller_id" bigint,
+    "customer_id" bigint,
+    "responsible_id" bigint,
+    "date_time" timestamptz,
+    "sub_total" decimal NOT NULL,
+    "order_state_id" bigint NOT NULL,
+    "total" decimal NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_purchase_orders_responsible" FOREIGN KEY ("responsible_id") REFERENCES "employees"("id"),
+    CONSTRAINT "fk_purchase_orders_order_state" FOREIGN KEY ("order_state_id") REFERENCES "order_state_types"("id"),
+    CONSTRAINT "fk_purchase_orders_seller" FOREIGN KEY ("seller_id") REFERENCES "employees"("id"),
+    CONSTRAINT "fk_purchase_orders_customer" FOREIGN KEY ("customer_id") REFERENCES "customers"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "discount_types" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "description" varchar(300),
+    "is_percentage" boolean NOT NULL,
+    "value" decimal NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "purchase_order_discounts" (
+    "purchase_order_id" bigint,
+    "discount_type_id" bigint,
+    PRIMARY KEY ("purchase_order_id","discount_type_id"),
+    CONSTRAINT "fk_purchase_order_discounts_purchase_order" FOREIGN KEY ("purchase_order_id") REFERENCES "purchase_orders"("id"),
+    CONSTRAINT "fk_purchase_order_discounts_discount_type" FOREIGN KEY ("discount_type_id") REFERENCES "discount_types"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "tax_types" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "description" varchar(300),
+    "is_percentage" boolean NOT NULL,
+    "value" decimal NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "purchase_order_taxes" (
+    "purchase_order_id" bigint,
+    "tax_type_id" bigint,
+    PRIMARY KEY ("purchase_order_id","tax_type_id"),
+    CONSTRAINT "fk_purchase_order_taxes_purchase_order" FOREIGN KEY ("purchase_order_id") REFERENCES "purchase_orders"("id"),
+    CONSTRAINT "fk_purchase_order_taxes_tax_type" FOREIGN KEY ("tax_type_id") REFERENCES "tax_types"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "invoices" (
+    "id" bigserial,
+    "enterprise_data" varchar(300) NOT NULL,
+    "date_time" timestamptz NOT NULL,
+    "customer_id" bigint NOT NULL,
+    "subtotal" decimal NOT NULL,
+    "total" decimal NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_invoices_customer" FOREIGN KEY ("customer_id") REFERENCES "customers"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "invoice_taxes" (
+    "invoice_id" bigint,
+    "tax_type_id" bigint,
+    PRIMARY KEY ("invoice_id","tax_type_id"),
+    CONSTRAINT "fk_invoice_taxes_invoice" FOREIGN KEY ("invoice_id") REFERENCES "invoices"("id"),
+    CONSTRAINT "fk_invoice_taxes_tax_type" FOREIGN KEY ("tax_type_id") REFERENCES "tax_types"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "invoice_discounts" (
+    "invoice_id" bigint,
+    "discount_type_id" bigint,
+    PRIMARY KEY ("invoice_id","discount_type_id"),
+    CONSTRAINT "fk_invoice_discounts_invoice" FOREIGN KEY ("invoice_id") REFERENCES "invoices"("id"),
+    CONSTRAINT "fk_invoice_discounts_discount_type" FOREIGN KEY ("discount_type_id") REFERENCES "discount_types"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "invoice_items" (
+    "invoice_id" bigint,
+    "item_id" bigint,
+    "amount" bigint NOT NULL,
+    PRIMARY KEY ("invoice_id","item_id"),
+    CONSTRAINT "fk_invoice_items_item" FOREIGN KEY ("item_id") REFERENCES "items"("id"),
+    CONSTRAINT "fk_invoices_items" FOREIGN KEY ("invoice_id") REFERENCES "invoices"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "purchase_order_items" (
+    "purchase_order_id" bigint,
+    "item_id" bigint,
+    "amount" bigint NOT NULL,
+    PRIMARY KEY ("purchase_order_id","item_id"),
+    CONSTRAINT "fk_purchase_order_items_item" FOREIGN KEY ("item_id") REFERENCES "items"("id"),
+    CONSTRAINT "fk_purchase_orders_items" FOREIGN KEY ("purchase_order_id") REFERENCES "purchase_orders"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "external_sales" (
+    "reporter_name" varchar(255) NOT NULL,
+    "reporter_id" varchar(100) NOT NULL,
+    "stock" bigint NOT NULL,
+    "id" bigserial,
+    "item_id" bigint NOT NULL,
+    "customer_id" bigint NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_external_sales_item" FOREIGN KEY ("item_id") REFERENCES "items"("id"),
+    CONSTRAINT "fk_external_sales_customer" FOREIGN KEY ("customer_id") REFERENCES "customers"("id")
+);
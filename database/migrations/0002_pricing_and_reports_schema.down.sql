@@ -0,0 +1,32 @@
+DROP TABLE IF EXISTS "report_runs";
+DROP TABLE IF EXISTS "scheduled_reports";
+DROP TABLE IF EXISTS "price_list_rules";
+DROP TABLE IF EXISTS "price_list_items";
+DROP TABLE IF EXISTS "price_list_customers";
+DROP TABLE IF EXISTS "price_lists";
+DROP TABLE IF EXISTS "discount_type_items";
+DROP TABLE IF EXISTS "discount_type_item_types";
+
+ALTER TABLE purchase_order_items DROP COLUMN IF EXISTS unit_price;
+ALTER TABLE invoice_items DROP COLUMN IF EXISTS unit_price;
+
+DROP INDEX IF EXISTS "idx_invoices_purchase_order_id";
+ALTER TABLE invoices DROP COLUMN IF EXISTS purchase_order_id;
+ALTER TABLE invoices DROP COLUMN IF EXISTS tax_total;
+ALTER TABLE invoices DROP COLUMN IF EXISTS discount_total;
+
+ALTER TABLE discount_types DROP COLUMN IF EXISTS get_quantity;
+ALTER TABLE discount_types DROP COLUMN IF EXISTS buy_quantity;
+ALTER TABLE discount_types DROP COLUMN IF EXISTS max_uses_per_customer;
+ALTER TABLE discount_types DROP COLUMN IF EXISTS min_purchase_amount;
+ALTER TABLE discount_types DROP COLUMN IF EXISTS valid_to;
+ALTER TABLE discount_types DROP COLUMN IF EXISTS valid_from;
+ALTER TABLE discount_types DROP COLUMN IF EXISTS auto_apply;
+ALTER TABLE discount_types DROP COLUMN IF EXISTS coupon_code;
+
+DROP INDEX IF EXISTS "idx_historical_item_prices_effective_from";
+DROP INDEX IF EXISTS "idx_historical_item_prices_effective_to";
+ALTER TABLE historical_item_prices DROP COLUMN IF EXISTS effective_to;
+ALTER TABLE historical_item_prices DROP COLUMN IF EXISTS effective_from;
+
+ALTER TABLE additional_expenses DROP COLUMN IF EXISTS units;
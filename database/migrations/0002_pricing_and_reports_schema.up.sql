@@ -0,0 +1,111 @@
+-- Agrega las columnas y tablas de precios, descuentos y reportes programados a
+-- las bases creadas por AutoMigrate, donde 0001 no crea nada porque las tablas
+-- ya existen. Debe correr antes de los backfills de 0003 y 0004.
+
+ALTER TABLE additional_expenses ADD COLUMN IF NOT EXISTS units bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE historical_item_prices ADD COLUMN IF NOT EXISTS effective_from timestamptz;
+ALTER TABLE historical_item_prices ADD COLUMN IF NOT EXISTS effective_to timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_historical_item_prices_effective_to" ON "historical_item_prices" ("effective_to");
+CREATE INDEX IF NOT EXISTS "idx_historical_item_prices_effective_from" ON "historical_item_prices" ("effective_from");
+
+ALTER TABLE discount_types ADD COLUMN IF NOT EXISTS coupon_code varchar(50);
+ALTER TABLE discount_types ADD COLUMN IF NOT EXISTS auto_apply boolean NOT NULL DEFAULT false;
+ALTER TABLE discount_types ADD COLUMN IF NOT EXISTS valid_from timestamptz;
+ALTER TABLE discount_types ADD COLUMN IF NOT EXISTS valid_to timestamptz;
+ALTER TABLE discount_types ADD COLUMN IF NOT EXISTS min_purchase_amount decimal NOT NULL DEFAULT 0;
+ALTER TABLE discount_types ADD COLUMN IF NOT EXISTS max_uses_per_customer bigint NOT NULL DEFAULT 0;
+ALTER TABLE discount_types ADD COLUMN IF NOT EXISTS buy_quantity bigint NOT NULL DEFAULT 0;
+ALTER TABLE discount_types ADD COLUMN IF NOT EXISTS get_quantity bigint NOT NULL DEFAULT 0;
+CREATE UNIQUE INDEX IF NOT EXISTS "uni_discount_types_coupon_code" ON "discount_types" ("coupon_code");
+
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS discount_total decimal NOT NULL DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS tax_total decimal NOT NULL DEFAULT 0;
+ALTER TABLE invoices ADD COLUMN IF NOT EXISTS purchase_order_id bigint;
+CREATE INDEX IF NOT EXISTS "idx_invoices_purchase_order_id" ON "invoices" ("purchase_order_id");
+
+ALTER TABLE invoice_items ADD COLUMN IF NOT EXISTS unit_price decimal NOT NULL DEFAULT 0;
+ALTER TABLE purchase_order_items ADD COLUMN IF NOT EXISTS unit_price decimal NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS "discount_type_item_types" (
+    "discount_type_id" bigint,
+    "item_type_id" bigint,
+    PRIMARY KEY ("discount_type_id","item_type_id"),
+    CONSTRAINT "fk_discount_type_item_types_discount_type" FOREIGN KEY ("discount_type_id") REFERENCES "discount_types"("id"),
+    CONSTRAINT "fk_discount_type_item_types_item_type" FOREIGN KEY ("item_type_id") REFERENCES "item_types"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "discount_type_items" (
+    "discount_type_id" bigint,
+    "item_id" bigint,
+    PRIMARY KEY ("discount_type_id","item_id"),
+    CONSTRAINT "fk_discount_type_items_discount_type" FOREIGN KEY ("discount_type_id") REFERENCES "discount_types"("id"),
+    CONSTRAINT "fk_discount_type_items_item" FOREIGN KEY ("item_id") REFERENCES "items"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "price_lists" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "description" varchar(300),
+    "segment" varchar(20),
+    "priority" bigint NOT NULL DEFAULT 0,
+    "active" boolean NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "price_list_customers" (
+    "price_list_id" bigint,
+    "customer_id" bigint,
+    PRIMARY KEY ("price_list_id","customer_id"),
+    CONSTRAINT "fk_price_list_customers_price_list" FOREIGN KEY ("price_list_id") REFERENCES "price_lists"("id"),
+    CONSTRAINT "fk_price_list_customers_customer" FOREIGN KEY ("customer_id") REFERENCES "customers"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "price_list_items" (
+    "price_list_id" bigint,
+    "item_id" bigint,
+    "price" decimal NOT NULL,
+    PRIMARY KEY ("price_list_id","item_id"),
+    CONSTRAINT "fk_price_lists_items" FOREIGN KEY ("price_list_id") REFERENCES "price_lists"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "price_list_rules" (
+    "id" bigserial,
+    "price_list_id" bigint NOT NULL,
+    "item_type_id" bigint,
+    "percentage" decimal NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_price_lists_rules" FOREIGN KEY ("price_list_id") REFERENCES "price_lists"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_price_list_rules_price_list_id" ON "price_list_rules" ("price_list_id");
+
+CREATE TABLE IF NOT EXISTS "scheduled_reports" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "report" varchar(50) NOT NULL,
+    "parameters" text,
+    "cron" varchar(100) NOT NULL,
+    "format" varchar(10) NOT NULL,
+    "recipients" varchar(500) NOT NULL,
+    "active" boolean NOT NULL,
+    "last_run_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "report_runs" (
+    "id" bigserial,
+    "scheduled_report_id" bigint NOT NULL,
+    "started_at" timestamptz NOT NULL,
+    "finished_at" timestamptz,
+    "status" varchar(20) NOT NULL,
+    "error" text,
+    "range_from" timestamptz,
+    "range_to" timestamptz,
+    "artifact_name" varchar(200),
+    "content_type" varchar(100),
+    "artifact" bytea,
+    "delivered_to" varchar(500),
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_report_runs_scheduled_report_id" ON "report_runs" ("scheduled_report_id");
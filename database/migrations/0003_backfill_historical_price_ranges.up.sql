@@ -0,0 +1,11 @@
+-- Completa los rangos de vigencia de los precios históricos registrados antes
+-- de que existieran: cada precio rige desde que se registró hasta que se
+-- registró el siguiente precio del mismo ítem.
+
+UPDATE historical_item_prices SET effective_from = added_at
+WHERE effective_from IS NULL;
+
+UPDATE historical_item_prices h SET effective_to = n.next_from
+FROM (SELECT id, LEAD(effective_from) OVER (PARTITION BY item_id ORDER BY effective_from, id) AS next_from
+    FROM historical_item_prices) n
+WHERE h.id = n.id AND h.effective_to IS NULL AND n.next_from IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Los totales completados no se pueden distinguir de los registrados después,
+-- así que deshacer esta migración no cambia datos.
@@ -0,0 +1,13 @@
+-- Completa el total de impuestos y de descuentos de las facturas emitidas antes
+-- de que se guardaran. Los impuestos se recalculan sobre el subtotal y el
+-- descuento es lo que falta para llegar al total facturado.
+
+UPDATE invoices i SET tax_total = t.tax_total,
+    discount_total = GREATEST(i.subtotal + t.tax_total - i.total, 0)
+FROM (SELECT inv.id, COALESCE(SUM(CASE WHEN tt.is_percentage THEN inv.subtotal * tt.value / 100
+        ELSE tt.value END), 0) AS tax_total
+    FROM invoices inv
+    LEFT JOIN invoice_taxes it ON it.invoice_id = inv.id
+    LEFT JOIN tax_types tt ON tt.id = it.tax_type_id
+    GROUP BY inv.id) t
+WHERE i.id = t.id AND i.tax_total = 0 AND i.discount_total = 0 AND i.total <> i.subtotal;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_logs_request_id;
+ALTER TABLE user_logs DROP COLUMN IF EXISTS request_id;
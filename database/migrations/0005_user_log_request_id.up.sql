@@ -0,0 +1,3 @@
+-- Guarda el X-Request-ID en cada registro de UserLog para cruzarlo con los logs
+ALTER TABLE user_logs ADD COLUMN IF NOT EXISTS request_id varchar(128);
+CREATE INDEX IF NOT EXISTS idx_user_logs_request_id ON user_logs (request_id);
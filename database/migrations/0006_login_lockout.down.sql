@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "security_events";
+DROP TABLE IF EXISTS "login_attempts";
+UPDATE "users" SET "user_state_type_id" = 1 WHERE "user_state_type_id" = 3;
+ALTER TABLE "users" DROP COLUMN IF EXISTS "locked_until";
+DELETE FROM "user_state_types" WHERE "id" = 3;
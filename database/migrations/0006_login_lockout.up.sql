@@ -0,0 +1,34 @@
+-- Bloqueo de cuentas por intentos fallidos de inicio de sesión: estado
+-- "Locked", fin del bloqueo en users, registro de intentos y eventos de
+-- seguridad.
+
+INSERT INTO "user_state_types" ("id", "name") VALUES (3, 'Locked') ON CONFLICT ("id") DO NOTHING;
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "locked_until" timestamptz;
+
+CREATE TABLE IF NOT EXISTS "login_attempts" (
+    "id" bigserial,
+    "email" varchar(80) NOT NULL,
+    "ip" varchar(64) NOT NULL,
+    "success" boolean NOT NULL,
+    "attempted_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_login_attempts_email" ON "login_attempts" ("email");
+CREATE INDEX IF NOT EXISTS "idx_login_attempts_ip" ON "login_attempts" ("ip");
+CREATE INDEX IF NOT EXISTS "idx_login_attempts_attempted_at" ON "login_attempts" ("attempted_at");
+
+CREATE TABLE IF NOT EXISTS "security_events" (
+    "id" bigserial,
+    "type" varchar(40) NOT NULL,
+    "user_email" varchar(80),
+    "ip" varchar(64),
+    "actor" varchar(80),
+    "detail" varchar(500),
+    "request_id" varchar(128),
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_security_events_type" ON "security_events" ("type");
+CREATE INDEX IF NOT EXISTS "idx_security_events_user_email" ON "security_events" ("user_email");
+CREATE INDEX IF NOT EXISTS "idx_security_events_created_at" ON "security_events" ("created_at");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "recovery_codes";
+DROP TABLE IF EXISTS "user_totps";
+ALTER TABLE "user_types" DROP COLUMN IF EXISTS "require_two_factor";
@@ -0,0 +1,35 @@
+-- Segundo factor TOTP: semillas, códigos de recuperación y la política por
+-- tipo de usuario.
+
+ALTER TABLE "user_types" ADD COLUMN IF NOT EXISTS "require_two_factor" boolean NOT NULL DEFAULT false;
+
+-- Lo exigen los tipos de usuario que administran usuarios o facturan:
+-- UPDATE_USER_STATE, UPDATE_USER, CREATE_USER, UNLOCK_USER y CREATE_INVOICE
+UPDATE "user_types" SET "require_two_factor" = true
+WHERE "id" IN (
+    SELECT ut."user_type_id"
+    FROM "user_type_has_role" ut
+    JOIN "role_permission" rp ON rp."role_id" = ut."role_id"
+    WHERE rp."permission_id" IN (4005, 4006, 4007, 4009, 19005)
+);
+
+CREATE TABLE IF NOT EXISTS "user_totps" (
+    "user_id" bigint NOT NULL,
+    "secret" varchar(200) NOT NULL,
+    "confirmed_at" timestamptz,
+    "last_used_step" bigint,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("user_id"),
+    CONSTRAINT "fk_user_totps_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "recovery_codes" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "code_hash" varchar(64) NOT NULL,
+    "used_at" timestamptz,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_recovery_codes_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_recovery_codes_user_id" ON "recovery_codes" ("user_id");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "user_invitations";
+UPDATE "users" SET "user_state_type_id" = 2 WHERE "user_state_type_id" = 4;
+DELETE FROM "user_state_types" WHERE "id" = 4;
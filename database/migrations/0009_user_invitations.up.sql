@@ -0,0 +1,19 @@
+-- Invitaciones de usuarios: estado "Pending" para los invitados que todavía
+-- no eligieron su contraseña y tabla con el token enviado por correo.
+
+INSERT INTO "user_state_types" ("id", "name") VALUES (4, 'Pending') ON CONFLICT ("id") DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS "user_invitations" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "invited_by" varchar(80) NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "sent_at" timestamptz,
+    "accepted_at" timestamptz,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_invitations_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "uni_user_invitations_user_id" UNIQUE ("user_id"),
+    CONSTRAINT "uni_user_invitations_token_hash" UNIQUE ("token_hash")
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "oidc_auth_requests";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "password_login_disabled";
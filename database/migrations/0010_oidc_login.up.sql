@@ -0,0 +1,14 @@
+-- Inicio de sesión con el proveedor de identidad: usuarios que sólo pueden
+-- entrar con él e inicios de sesión pendientes (state, nonce y PKCE).
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "password_login_disabled" boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS "oidc_auth_requests" (
+    "state_hash" varchar(64) NOT NULL,
+    "nonce" varchar(64) NOT NULL,
+    "code_verifier" varchar(128) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("state_hash")
+);
+CREATE INDEX IF NOT EXISTS "idx_oidc_auth_requests_expires_at" ON "oidc_auth_requests" ("expires_at");
@@ -0,0 +1,3 @@
+ALTER TABLE "external_sales" DROP COLUMN IF EXISTS "api_key_id";
+DROP TABLE IF EXISTS "api_key_permissions";
+DROP TABLE IF EXISTS "api_keys";
@@ -0,0 +1,32 @@
+-- Llaves de API para clientes de máquina y revendedores que reportan ventas
+-- externas, con sus permisos, y la llave con la que se reportó cada venta.
+
+CREATE TABLE IF NOT EXISTS "api_keys" (
+    "id" bigserial,
+    "name" varchar(100) NOT NULL,
+    "prefix" varchar(16) NOT NULL,
+    "key_hash" varchar(64) NOT NULL,
+    "principal" varchar(255) NOT NULL,
+    "reporter_tax_id" varchar(100),
+    "expires_at" timestamptz NOT NULL,
+    "last_used_at" timestamptz,
+    "last_used_ip" varchar(64),
+    "revoked_at" timestamptz,
+    "replaced_by_id" bigint,
+    "created_by" varchar(80) NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_api_keys_prefix" UNIQUE ("prefix"),
+    CONSTRAINT "uni_api_keys_key_hash" UNIQUE ("key_hash")
+);
+
+CREATE TABLE IF NOT EXISTS "api_key_permissions" (
+    "api_key_id" bigint,
+    "permission_id" bigint,
+    PRIMARY KEY ("api_key_id","permission_id"),
+    CONSTRAINT "fk_api_key_permissions_api_key" FOREIGN KEY ("api_key_id") REFERENCES "api_keys"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_api_key_permissions_permission" FOREIGN KEY ("permission_id") REFERENCES "permissions"("id")
+);
+
+ALTER TABLE "external_sales" ADD COLUMN IF NOT EXISTS "api_key_id" bigint
+    CONSTRAINT "fk_external_sales_api_key" REFERENCES "api_keys"("id");
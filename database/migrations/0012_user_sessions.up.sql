@@ -0,0 +1,21 @@
+-- Sesiones de usuario en el servidor: se crean al iniciar sesión y se pueden
+-- listar y revocar.
+
+CREATE TABLE IF NOT EXISTS "user_sessions" (
+    "id" bigserial,
+    "user_id" bigint NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "method" varchar(20) NOT NULL,
+    "device" varchar(255),
+    "ip" varchar(64) NOT NULL,
+    "created_at" timestamptz NOT NULL,
+    "last_seen_at" timestamptz NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "revoked_at" timestamptz,
+    "revoked_by" varchar(80),
+    "revoke_reason" varchar(40),
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_user_sessions_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE,
+    CONSTRAINT "uni_user_sessions_token_hash" UNIQUE ("token_hash")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_sessions_user_id" ON "user_sessions" ("user_id");
@@ -0,0 +1,10 @@
+-- Reglas de alcance de datos por rol, por ejemplo que un vendedor sólo vea
+-- sus propias órdenes de compra.
+
+CREATE TABLE IF NOT EXISTS "role_scopes" (
+    "role_id" bigint,
+    "resource" varchar(40),
+    "rule" varchar(20) NOT NULL,
+    PRIMARY KEY ("role_id","resource"),
+    CONSTRAINT "fk_role_scopes_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id") ON DELETE CASCADE
+);
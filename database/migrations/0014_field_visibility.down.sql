@@ -0,0 +1,2 @@
+DELETE FROM "role_permission" WHERE "permission_id" IN (29001, 29002, 29003);
+DELETE FROM "permissions" WHERE "id" IN (29001, 29002, 29003);
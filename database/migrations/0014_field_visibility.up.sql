@@ -0,0 +1,21 @@
+-- Permisos para ver los campos sensibles de las respuestas. Para no ocultarle
+-- datos a quien ya los administra, se dan a los roles que pueden crearlos o
+-- modificarlos: costos de ítems a CREATE_ITEM y UPDATE_ITEM, contacto de
+-- clientes a CREATE_CUSTOMER y UPDATE_CUSTOMER, y datos de empresa de las
+-- facturas a CREATE_INVOICE.
+
+INSERT INTO "permissions" ("id", "name") VALUES
+    (29001, 'VIEW_ITEM_COSTS'),
+    (29002, 'VIEW_CUSTOMER_CONTACT'),
+    (29003, 'VIEW_INVOICE_ENTERPRISE_DATA')
+ON CONFLICT ("id") DO NOTHING;
+
+INSERT INTO "role_permission" ("role_id", "permission_id")
+SELECT DISTINCT rp."role_id", grants."permission_id"
+FROM "role_permission" rp
+JOIN (VALUES
+    (9006, 29001), (9007, 29001),
+    (14003, 29002), (14004, 29002),
+    (19005, 29003)
+) AS grants ("source_id", "permission_id") ON grants."source_id" = rp."permission_id"
+ON CONFLICT DO NOTHING;
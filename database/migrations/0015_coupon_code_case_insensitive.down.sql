@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_discount_types_coupon_code_upper;
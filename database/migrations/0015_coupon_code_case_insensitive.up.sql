@@ -0,0 +1,9 @@
+-- Los cupones se buscan sin distinguir mayúsculas, así que tampoco pueden
+-- repetirse con otra capitalización. Los códigos existentes se pasan a
+-- mayúsculas como los guarda CreateDiscountType; si dos quedan iguales la
+-- migración falla y hay que renombrar uno a mano.
+
+UPDATE discount_types SET coupon_code = UPPER(coupon_code)
+WHERE coupon_code IS NOT NULL AND coupon_code <> UPPER(coupon_code);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_discount_types_coupon_code_upper ON discount_types (UPPER(coupon_code));
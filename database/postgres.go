@@ -1,10 +1,9 @@
 package database
 
 import (
+	"context"
 	"errors"
-	"log"
-	"os"
-	"totesbackend/models"
+	"totesbackend/config"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -17,28 +16,32 @@ func GetDB() *gorm.DB {
 	return db
 }
 
-// StartPostgres inicia la conexión con PostgreSQL
-func StartPostgres() error {
-	// Obtener la URI desde la variable de entorno
-	dsn := os.Getenv("POSTGRES_URI")
-	if dsn == "" {
+// StartPostgres inicia la conexión con PostgreSQL y configura el pool
+func StartPostgres(cfg config.DatabaseConfig) error {
+	if cfg.URI == "" {
 		return errors.New("you must set your 'POSTGRES_URI' environmental variable")
 	}
 
 	// Conectar con PostgreSQL usando GORM
 	var err error
-	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err = gorm.Open(postgres.Open(cfg.URI), &gorm.Config{})
 	if err != nil {
 		return errors.New("failed to connect to PostgreSQL")
 	}
 
-	// Verificar la conexión
 	sqlDB, err := db.DB()
 	if err != nil {
 		return err
 	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	err = sqlDB.Ping()
+	// Verificar la conexión sin esperar más de ConnectTimeout
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+	err = sqlDB.PingContext(ctx)
 	if err != nil {
 		return errors.New("can't verify a connection")
 	}
@@ -59,15 +62,3 @@ func ClosePostgres() {
 	}
 
 }
-
-func MigrateDB() {
-
-	err := db.AutoMigrate(&models.Item{}, &models.ItemType{},
-		&models.AdditionalExpense{}, &models.Permission{}, &models.Role{},
-		&models.UserType{}, &models.IdentifierType{}, &models.UserStateType{}, &models.Employee{}, &models.HistoricalItemPrice{},
-		&models.Comment{}, models.User{}, models.UserLog{}, &models.Customer{}, &models.Appointment{}, models.OrderStateType{}, &models.PurchaseOrder{},
-		&models.DiscountType{}, &models.TaxType{}, &models.Invoice{}, &models.InvoiceItem{}, &models.PurchaseOrderItem{}, &models.ExternalSale{})
-	if err != nil {
-		log.Fatal("Error en la migración de la base de datos:", err)
-	}
-}
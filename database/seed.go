@@ -0,0 +1,80 @@
+package database
+
+import (
+	"sort"
+	"totesbackend/config"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Los identificadores de los estados son fijos: el código compara contra ellos
+// (la máquina de estados de las órdenes y la validación de credenciales).
+var (
+	seedIdentifierTypes = []models.IdentifierType{
+		{ID: 1, Name: "Cédula de ciudadanía"},
+		{ID: 2, Name: "Cédula de extranjería"},
+		{ID: 3, Name: "NIT"},
+		{ID: 4, Name: "Pasaporte"},
+		{ID: 5, Name: "Tarjeta de identidad"},
+	}
+	seedUserStateTypes = []models.UserStateType{
+		{ID: 1, Name: "Active"},
+		{ID: 2, Name: "Inactive"},
+		{ID: 3, Name: "Locked"},
+		{ID: 4, Name: "Pending"},
+	}
+	seedOrderStateTypes = []models.OrderStateType{
+		{ID: 1, Description: "Issued"},
+		{ID: 2, Description: "In transit"},
+		{ID: 3, Description: "Cancelled"},
+		{ID: 4, Description: "Approved"},
+	}
+)
+
+// SeedResult cuenta las filas insertadas por tabla.
+type SeedResult map[string]int64
+
+// Seed inserta los datos de referencia que faltan: tipos de identificación,
+// estados de usuario, estados de orden y permisos. Las filas existentes no se
+// modifican, así que puede ejecutarse varias veces.
+func Seed() (SeedResult, error) {
+	permissions := make([]models.Permission, 0, len(config.PermissionNames))
+	for id, name := range config.PermissionNames {
+		permissions = append(permissions, models.Permission{ID: uint(id), Name: name})
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i].ID < permissions[j].ID })
+
+	result := SeedResult{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		seeds := []struct {
+			table string
+			rows  interface{}
+		}{
+			{"identifier_types", &seedIdentifierTypes},
+			{"user_state_types", &seedUserStateTypes},
+			{"order_state_types", &seedOrderStateTypes},
+			{"permissions", &permissions},
+		}
+		for _, seed := range seeds {
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(seed.rows)
+			if res.Error != nil {
+				return res.Error
+			}
+			result[seed.table] = res.RowsAffected
+			if err := ResetSequence(tx, seed.table); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ResetSequence deja la secuencia del id de la tabla después del mayor id, para
+// que las inserciones posteriores no choquen con filas cargadas con id fijo.
+func ResetSequence(tx *gorm.DB, table string) error {
+	return tx.Exec(`SELECT setval(pg_get_serial_sequence(?, 'id'), COALESCE((SELECT MAX(id) FROM `+
+		tx.Statement.Quote(table)+`), 0) + 1, false)`, table).Error
+}
@@ -0,0 +1,45 @@
+// Package datascope lleva en el contexto de la petición el alcance de datos
+// del usuario: qué registros de un recurso puede ver según las reglas de
+// alcance de sus roles.
+package datascope
+
+import "context"
+
+// Recursos a los que se les pueden poner reglas de alcance.
+const (
+	ResourcePurchaseOrders = "purchase_orders"
+	ResourceCustomers      = "customers"
+	ResourceAppointments   = "appointments"
+)
+
+// RuleOwn limita el recurso a los registros del empleado del usuario: las
+// órdenes de compra en las que es vendedor, los clientes de esas órdenes y
+// las citas de esos clientes.
+const RuleOwn = "own"
+
+// Resources son los recursos válidos en una regla de alcance.
+var Resources = []string{ResourcePurchaseOrders, ResourceCustomers, ResourceAppointments}
+
+// Scope restringe las consultas de Resource según Rule. EmployeeID es el
+// empleado del usuario; sin empleado es 0 y no ve ningún registro propio.
+type Scope struct {
+	Resource   string
+	Rule       string
+	EmployeeID int
+}
+
+type scopeKey struct{}
+
+// WithScope devuelve un contexto que lleva el alcance.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// For devuelve el alcance del contexto si restringe resource.
+func For(ctx context.Context, resource string) (Scope, bool) {
+	scope, ok := ctx.Value(scopeKey{}).(Scope)
+	if !ok || scope.Resource != resource {
+		return Scope{}, false
+	}
+	return scope, true
+}
@@ -30,7 +30,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all additional expense records. Requires permission to view all additional expenses.",
+                "description": "Retrieves all additional expense records. Requires permission to view all additional expenses.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -41,26 +41,70 @@ const docTemplate = `{
                     "additional-expenses"
                 ],
                 "summary": "Get all additional expenses",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "A list of all additional expenses",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.AdditionalExpense"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.AdditionalExpense"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving additional expenses",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -103,19 +147,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid JSON format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error creating additional expense",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -153,19 +203,19 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Additional expense not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error (log registration or DB error)",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -215,25 +265,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request or JSON format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Additional expense not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -274,25 +330,286 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid ID format or request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Additional expense not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error deleting additional expense",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the API keys of machine clients and external sale reporters, including expired, revoked and rotated ones. Newest first by default.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Get API keys",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of API keys",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.APIKeyDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving API keys",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates an API key for a machine client, or for an external sale reporter when reporter_tax_id is set. The key is sent in the X-API-Key header.\nThe key has only the given permissions, and the caller must hold all of them. expires_at is optional and limited by the maximum lifetime.\nThe full key is returned only in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "Name, owner, permissions and expiration of the key",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateAPIKeyDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created API key",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.IssuedAPIKeyDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed or invalid expiration",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating the API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes an API key immediately. A key created by rotating it is not affected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API key revoked",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "API key already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error revoking the API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/{id}/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new key with the same owner and permissions. The previous key keeps working during the configured grace period and then expires.\nThe full new key is returned only in this response.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Rotate an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "New API key",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.IssuedAPIKeyDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "API key is revoked, expired or already rotated",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error rotating the API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -305,7 +622,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all appointments. Requires proper permission.",
+                "description": "Retrieves a list of all appointments. Requires proper permission.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -316,26 +633,70 @@ const docTemplate = `{
                     "appointments"
                 ],
                 "summary": "Get all appointments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "List of all appointments",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Appointment"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Appointment"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving appointments or logging",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -378,19 +739,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid JSON format or appointment limit reached",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Forbidden, no permission to create appointments",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error creating appointment or logging",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -437,28 +804,28 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.Appointment"
                         }
                     },
-                    "400": {
-                        "description": "Invalid customer ID or date format",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Appointment not found for the given customer ID and date",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving appointment or logging",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -499,19 +866,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid customer ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving appointments",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -554,25 +921,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid appointment ID format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Appointment not found for the given ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error deleting the appointment",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -615,22 +982,22 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid date format or missing 'date' parameter",
+                    "401": {
+                        "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized or permission denied",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving appointment counts",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -676,19 +1043,19 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No appointments found for the given customer ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving appointments or logging",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -734,19 +1101,19 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No appointments found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving appointments or logging",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -789,22 +1156,22 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid state value provided",
+                    "401": {
+                        "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized or permission denied",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving appointments or logging",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -847,25 +1214,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid appointment ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Appointment not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -915,25 +1282,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid appointment ID or JSON format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Forbidden, no permission to update appointments",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Appointment not found for update",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error updating appointment or logging",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -975,16 +1348,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid or missing parameters",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error checking permission",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1032,22 +1405,28 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request data",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Calculation error (e.g., related data not found)",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
-                    }
-                }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
             }
         },
         "/billing/total": {
@@ -1057,7 +1436,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Calculates the total amount based on billing items, discounts, and tax types. Requires permission.",
+                "description": "Calculates the total amount based on billing items, discounts, coupon codes and tax types.\nAutomatic promotions are applied when the purchase meets their conditions, and every requested\ndiscount that does not apply is returned with the reason it was rejected. Requires permission.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1081,27 +1460,33 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Calculated total",
+                        "description": "Calculated total with applied and rejected discounts",
                         "schema": {
-                            "$ref": "#/definitions/controllers.TotalResponse"
+                            "$ref": "#/definitions/dtos.BillingBreakdownDTO"
                         }
                     },
                     "400": {
                         "description": "Invalid request data",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Calculation error (e.g., related data not found)",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1114,7 +1499,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all submitted comments. Requires permission.",
+                "description": "Retrieves a list of all submitted comments. Requires permission.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1125,26 +1510,70 @@ const docTemplate = `{
                     "comments"
                 ],
                 "summary": "Get all comments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "List of all comments",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetCommentDTO"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetCommentDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Failed to fetch comments or register log",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1187,19 +1616,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request data",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Failed to create comment or register log",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1242,22 +1677,22 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Email parameter is required",
+                    "401": {
+                        "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized or permission denied",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Failed to search comments or register log",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1303,25 +1738,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No comments found for the given ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in processing",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1367,25 +1802,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid request parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No comments found for the given name",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in processing",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1428,25 +1863,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid comment ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Comment not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving comment or registering log",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1496,25 +1931,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid ID or request data",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Comment not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failed update",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1527,7 +1968,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all customers from the system. Requires appropriate permission.",
+                "description": "Retrieves a list of all customers from the system. Requires appropriate permission.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1538,32 +1979,70 @@ const docTemplate = `{
                     "customers"
                 ],
                 "summary": "Retrieve all customers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "List of all customers",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Customer"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Customer"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
                         "description": "Invalid request parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving customers",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1606,19 +2085,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid input data (JSON format or missing fields)",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in creating customer",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1661,19 +2146,19 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Customer not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving customer",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1716,19 +2201,19 @@ const docTemplate = `{
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Customer not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving customer",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1774,25 +2259,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid query or request format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No customers found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving customers",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1838,25 +2323,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid query or request format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No customers found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving customers",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1902,25 +2387,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid query or request format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No customers found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving customers",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -1963,25 +2448,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid customer ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Customer not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving customer",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2031,25 +2516,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid input data (ID format or JSON format)",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Customer not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in updating customer",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2062,7 +2553,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all available discount types. Requires appropriate permission.",
+                "description": "Retrieves all available discount types. Requires appropriate permission.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2073,26 +2564,70 @@ const docTemplate = `{
                     "discount-types"
                 ],
                 "summary": "Get all discount types",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "List of all discount types",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.DiscountType"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DiscountType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving discount types",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2103,7 +2638,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Allows the creation of a new discount type in the system. Requires appropriate permissions.",
+                "description": "Allows the creation of a new discount type in the system. A discount type can carry promotion\nconditions: validity window, minimum purchase, targeted items or item types, buy X get Y,\nper-customer usage limit, coupon code and automatic application. Requires appropriate permissions.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2121,7 +2656,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.DiscountType"
+                            "$ref": "#/definitions/dtos.CreateDiscountTypeDTO"
                         }
                     }
                 ],
@@ -2135,19 +2670,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid input data",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Another discount type already uses the coupon code (case-insensitive)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in creating the discount type",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2190,25 +2737,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid ID format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "401": {
                         "description": "Unauthorized or permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Discount type not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error or failure in retrieving discount type",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2221,7 +2768,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all employees in the system. Requires the appropriate permissions.",
+                "description": "Retrieves a list of all employees in the system. Requires the appropriate permissions.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2232,26 +2779,70 @@ const docTemplate = `{
                     "employees"
                 ],
                 "summary": "Get all employees",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Successfully retrieved list of employees",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetEmployeeDTO"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetEmployeeDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving employees",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2294,25 +2885,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid JSON format, or missing fields",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "409": {
                         "description": "Employee with this Personal ID already exists",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error creating employee",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2358,19 +2955,19 @@ const docTemplate = `{
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No employees found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving employees",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2413,28 +3010,28 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Search query is required",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "No employees found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving employees",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2477,19 +3074,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid employee ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Employee not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2539,25 +3136,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid JSON format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "Employee not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error updating employee",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2570,7 +3173,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches a list of all external sales, including related items and customers.",
+                "description": "Fetches a list of all external sales, including related items and customers.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2581,26 +3184,70 @@ const docTemplate = `{
                     "external-sales"
                 ],
                 "summary": "Retrieve all external sales",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Successfully retrieved all external sales",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetExternalSaleDTO"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetExternalSaleDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving external sales",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2611,7 +3258,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Creates a new external sale, including the sale details and customer information.",
+                "description": "Creates a new external sale, including the sale details and customer information.\nWhen called with a reporter's API key (X-API-Key) the sale is attributed to the reporter of the key and reporter_name and reporter_id are ignored; otherwise both are required.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2643,13 +3290,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Invalid JSON format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid, expired or revoked API key",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error creating external sale",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
@@ -2692,34 +3357,34 @@ const docTemplate = `{
                     "403": {
                         "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
                         "description": "External sale not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Error retrieving external sale",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/health": {
+        "/healthz": {
             "get": {
-                "description": "Returns a 200 status if the server is running correctly.",
+                "description": "Returns 200 while the process is able to serve requests. It does not touch the database.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Health"
                 ],
-                "summary": "Health Check",
+                "summary": "Liveness probe",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -2730,14 +3395,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/historical-item-prices/{id}": {
-            "get": {
+        "/historical-item-prices/scheduled/{priceId}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves the historical prices for an item based on the item ID provided.",
+                "description": "Removes a price change that is not yet effective. The previous price range is extended to cover its period.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2747,55 +3412,52 @@ const docTemplate = `{
                 "tags": [
                     "historical-item-prices"
                 ],
-                "summary": "Get historical price for an item",
+                "summary": "Cancel a scheduled price change",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID",
-                        "name": "id",
+                        "description": "Historical price ID",
+                        "name": "priceId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved historical prices",
+                        "description": "Scheduled price cancelled",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.HistoricalItemPrice"
-                            }
+                            "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid Item ID",
+                        "description": "Price is already effective",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "No historical prices found",
+                        "description": "Scheduled price not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve historical prices",
+                        "description": "Failed to cancel scheduled price",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/identifier-types": {
+        "/historical-item-prices/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all available identifier types.",
+                "description": "Retrieves the historical prices for an item based on the item ID provided.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2803,42 +3465,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "identifier-types"
+                    "historical-item-prices"
+                ],
+                "summary": "Get historical price for an item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all identifier types",
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved identifier types",
+                        "description": "Successfully retrieved historical prices",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.IdentifierType"
+                                "$ref": "#/definitions/models.HistoricalItemPrice"
                             }
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "400": {
+                        "description": "Invalid Item ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "No historical prices found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving identifier types",
+                        "description": "Failed to retrieve historical prices",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/identifier-types/{id}": {
+        "/historical-item-prices/{id}/as-of": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves an identifier type by its ID.",
+                "description": "Retrieves the selling price range of an item that was effective at the given date.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2846,48 +3523,67 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "identifier-types"
+                    "historical-item-prices"
                 ],
-                "summary": "Get identifier type by ID",
+                "summary": "Get the price of an item at a point in time",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Identifier Type ID",
+                        "description": "Item ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date (RFC3339 format)",
+                        "name": "date",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully retrieved identifier type",
+                        "description": "Price effective at the given date",
                         "schema": {
-                            "$ref": "#/definitions/models.IdentifierType"
+                            "$ref": "#/definitions/models.HistoricalItemPrice"
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "400": {
+                        "description": "Invalid Item ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "Identifier Type not found",
+                        "description": "No price found for the given date",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve price",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/invoices": {
-            "get": {
+        "/historical-item-prices/{id}/scheduled": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all invoices from the system.",
+                "description": "Schedules a new selling price for an item, effective from a future date. The item's selling price is updated automatically when the date is reached.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2895,40 +3591,69 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "invoices"
+                    "historical-item-prices"
                 ],
-                "summary": "Get all invoices",
-                "responses": {
-                    "200": {
-                        "description": "List of all invoices",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetInvoiceDTO"
-                            }
-                        }
+                "summary": "Schedule a future price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
-                    "403": {
-                        "description": "Access denied",
+                    {
+                        "description": "New price and effective date",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SchedulePriceChangeDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Scheduled price",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoricalItemPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "No invoices found",
+                        "description": "Item not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to schedule price change",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/identifier-types": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new invoice based on the provided JSON data. Requires appropriate permissions.",
+                "description": "Retrieves a list of all available identifier types.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2936,56 +3661,86 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "invoices"
+                    "identifier-types"
                 ],
-                "summary": "Create a new invoice",
+                "summary": "Get all identifier types",
                 "parameters": [
                     {
-                        "description": "Invoice data",
-                        "name": "invoice_body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.CreateInvoiceDTO"
-                        }
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created invoice",
+                    "200": {
+                        "description": "Successfully retrieved identifier types",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetInvoiceDTO"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.IdentifierType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error creating invoice",
+                        "description": "Error retrieving identifier types",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/invoices/searchById": {
+        "/identifier-types/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search for invoices using a query parameter for the invoice ID.",
+                "description": "Retrieves an identifier type by its ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2993,115 +3748,130 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "invoices"
+                    "identifier-types"
                 ],
-                "summary": "Search invoices by ID",
+                "summary": "Get identifier type by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Invoice ID Query",
+                        "description": "Identifier Type ID",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of invoices found",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetInvoiceDTO"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Query parameter is required",
+                        "description": "Successfully retrieved identifier type",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.IdentifierType"
                         }
                     },
                     "403": {
                         "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "No invoices found",
+                        "description": "Identifier Type not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/invoices/searchByPersonalId": {
+        "/invitations": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search for invoices using a query parameter for the customer's personal ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the invitations that have not been accepted yet, including expired ones. Newest first by default.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "invoices"
+                    "invitations"
                 ],
-                "summary": "Search invoices by customer personal ID",
+                "summary": "Get pending invitations",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Customer Personal ID Query",
-                        "name": "personal_id",
-                        "in": "query",
-                        "required": true
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of invoices found",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetInvoiceDTO"
-                            }
+                        "description": "List of pending invitations",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.InvitationDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Query parameter 'personal_id' is required",
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
-                        "description": "Access denied",
+                        "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "No invoices found",
+                    "500": {
+                        "description": "Error retrieving invitations",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/invoices/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves an invoice by its unique ID.",
+                "description": "Creates a pending user with the given email and user type and emails them a single-use link to choose their password.\nThe account becomes active when the invitation is accepted. sent_at is null if the email could not be sent; resend it then.\nThe caller must hold every permission of the user type; otherwise the response is 403.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3109,99 +3879,131 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "invoices"
+                    "invitations"
                 ],
-                "summary": "Get invoice by ID",
+                "summary": "Invite a user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Invoice ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Email and user type of the new user",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.InviteUserDTO"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Invoice details",
+                    "201": {
+                        "description": "Created invitation",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetInvoiceDTO"
+                            "$ref": "#/definitions/dtos.InvitationDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid invoice ID",
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
-                        "description": "Access denied",
+                        "description": "Permission denied, or the user type has permissions the caller does not hold",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Invoice not found",
+                    "409": {
+                        "description": "A user with this email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating the invitation",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/item-types": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/invitations/accept": {
+            "post": {
+                "description": "Sets the password of an invited user with the token received by email and activates the account.\nThe token can be used only once and expires. The password must satisfy the password policy.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieves a list of all item types.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "item-types"
+                    "invitations"
+                ],
+                "summary": "Accept an invitation",
+                "parameters": [
+                    {
+                        "description": "Invitation token and password",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.AcceptInvitationDTO"
+                        }
+                    }
                 ],
-                "summary": "Get all item types",
                 "responses": {
                     "200": {
-                        "description": "List of item types retrieved successfully",
+                        "description": "Invitation accepted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ItemType"
-                            }
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or invalid, used or expired token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Password does not meet the policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving item types or registering log",
+                        "description": "Error accepting the invitation",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/item-types/{id}": {
-            "get": {
+        "/invitations/{id}": {
+            "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves the details of a specific item type by its ID.",
+                "description": "Cancels an invitation that has not been accepted and deletes the pending user, so the email can be invited again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "item-types"
+                    "invitations"
                 ],
-                "summary": "Get item type by ID",
+                "summary": "Revoke an invitation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item Type ID",
+                        "description": "Invitation ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3209,69 +4011,104 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Item Type retrieved successfully",
+                        "description": "Invitation revoked",
                         "schema": {
-                            "$ref": "#/definitions/models.ItemType"
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "Item Type not found",
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation already accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error registering log",
+                        "description": "Error revoking the invitation",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/items": {
-            "get": {
+        "/invitations/{id}/resend": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve a list of all items available in the inventory.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Emails a new invitation link with a new expiration. The previous link stops working.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "invitations"
+                ],
+                "summary": "Resend an invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Invitation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all items",
                 "responses": {
                     "200": {
-                        "description": "List of items",
+                        "description": "Resent invitation",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetItemDTO"
-                            }
+                            "$ref": "#/definitions/dtos.InvitationDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Invitation not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Invitation already accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving items",
+                        "description": "Error resending the invitation",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/invoices": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Creates a new item with the provided data.",
+                "description": "Retrieves all invoices from the system.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3279,50 +4116,78 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "invoices"
                 ],
-                "summary": "Create a new item",
+                "summary": "Get all invoices",
                 "parameters": [
                     {
-                        "description": "Item to create",
-                        "name": "item",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.UpdateItemDTO"
-                        }
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Item created successfully",
+                    "200": {
+                        "description": "List of all invoices",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetItemDTO"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetInvoiceDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON format",
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Error creating item",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/items/searchById": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search for items in the inventory by their ID using a query parameter.",
+                "description": "Create a new invoice based on the provided JSON data. Requires appropriate permissions.\nThe response includes a warning for each item sold below its landed cost.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3330,57 +4195,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "invoices"
                 ],
-                "summary": "Search items by ID",
+                "summary": "Create a new invoice",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Item ID to search for",
-                        "name": "id",
-                        "in": "query",
-                        "required": true
+                        "description": "Invoice data",
+                        "name": "invoice_body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateInvoiceDTO"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "List of items matching the search criteria",
+                    "201": {
+                        "description": "Created invoice",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetItemDTO"
-                            }
+                            "$ref": "#/definitions/dtos.GetInvoiceDTO"
                         }
                     },
                     "400": {
-                        "description": "Missing or invalid search query",
+                        "description": "Invalid request data",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "No items found",
+                    "403": {
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Insufficient stock",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed or a requested discount does not apply",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving items",
+                        "description": "Error creating invoice",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/items/searchByName": {
+        "/invoices/searchById": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search for items in the inventory by their name using a query parameter.",
+                "description": "Search for invoices using a query parameter for the invoice ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3388,57 +4264,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "invoices"
                 ],
-                "summary": "Search items by name",
+                "summary": "Search invoices by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item name to search for",
-                        "name": "name",
+                        "description": "Invoice ID Query",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of items matching the search criteria",
+                        "description": "List of invoices found",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dtos.GetItemDTO"
+                                "$ref": "#/definitions/dtos.GetInvoiceDTO"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Missing or invalid search query",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "No items found",
+                        "description": "No invoices found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Error retrieving items",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/items/{id}": {
+        "/invoices/searchByPersonalId": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve an item by its unique ID from the inventory.",
+                "description": "Search for invoices using a query parameter for the customer's personal ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3446,52 +4322,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "invoices"
                 ],
-                "summary": "Get item by ID",
+                "summary": "Search invoices by customer personal ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "Customer Personal ID Query",
+                        "name": "personal_id",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Item found",
+                        "description": "List of invoices found",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetItemDTO"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.GetInvoiceDTO"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid item ID format",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "Item not found",
+                        "description": "No invoices found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Error fetching item",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/invoices/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates the information of an existing item by its ID.",
+                "description": "Retrieves an invoice by its unique ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3499,189 +4380,178 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "invoices"
                 ],
-                "summary": "Update an item",
+                "summary": "Get invoice by ID",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID of the item to update",
+                        "type": "integer",
+                        "description": "Invoice ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated item data",
-                        "name": "item",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.UpdateItemDTO"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Item updated successfully",
+                        "description": "Invoice details",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetItemDTO"
+                            "$ref": "#/definitions/dtos.GetInvoiceDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON format",
+                        "description": "Invalid invoice ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Item not found",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "500": {
-                        "description": "Error updating item",
+                    "404": {
+                        "description": "Invoice not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/items/{id}/state": {
-            "patch": {
+        "/item-types": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates the state (active/inactive) of an item by its ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves a list of all item types.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "item-types"
                 ],
-                "summary": "Update the state of an item",
+                "summary": "Get all item types",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "ID of the item to update",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
-                        "description": "New state for the item (true for active, false for inactive)",
-                        "name": "item_state",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "boolean"
-                        }
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated item information",
+                        "description": "List of item types retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetItemDTO"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ItemType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Item not found",
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error updating item state",
+                        "description": "Error retrieving item types or registering log",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/items/{id}/stock": {
+        "/item-types/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Check if the specified quantity of an item is available in stock.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the details of a specific item type by its ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "items"
+                    "item-types"
                 ],
-                "summary": "Check item stock availability",
+                "summary": "Get item type by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID",
+                        "description": "Item Type ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Quantity to check",
-                        "name": "quantity",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Indicates whether the stock is sufficient or not",
-                        "schema": {
-                            "type": "boolean"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid quantity",
+                        "description": "Item Type retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ItemType"
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "404": {
+                        "description": "Item Type not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error checking stock",
+                        "description": "Error registering log",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/login": {
-            "post": {
+        "/items": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Validates the user's credentials (email and password) for login.",
+                "description": "Retrieve a list of all items available in the inventory.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3689,1335 +4559,1591 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "authentication"
+                    "items"
                 ],
-                "summary": "Validate user credentials",
+                "summary": "Get all items",
                 "parameters": [
                     {
-                        "description": "User credentials to validate",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controllers.LoginData"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Login successful message",
-                        "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
-                        }
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
                     },
-                    "401": {
-                        "description": "Invalid email or password",
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of items",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetItemDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "403": {
-                        "description": "User account is not active",
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error validating credentials",
+                        "description": "Error retrieving items",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/order-state-types": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all available order state types.",
+                "description": "Creates a new item with the provided data.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order-state-types"
+                    "items"
+                ],
+                "summary": "Create a new item",
+                "parameters": [
+                    {
+                        "description": "Item to create",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateItemDTO"
+                        }
+                    }
                 ],
-                "summary": "Get all order state types",
                 "responses": {
-                    "200": {
-                        "description": "List of order state types",
+                    "201": {
+                        "description": "Item created successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.OrderStateType"
-                            }
+                            "$ref": "#/definitions/dtos.GetItemDTO"
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "400": {
+                        "description": "Invalid JSON format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error creating item",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/order-state-types/{id}": {
+        "/items/searchById": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a specific order state type using its ID.",
+                "description": "Search for items in the inventory by their ID using a query parameter.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "order-state-types"
+                    "items"
                 ],
-                "summary": "Get order state type by ID",
+                "summary": "Search items by ID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Order State Type ID",
+                        "type": "string",
+                        "description": "Item ID to search for",
                         "name": "id",
-                        "in": "path",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order state type retrieved successfully",
+                        "description": "List of items matching the search criteria",
                         "schema": {
-                            "$ref": "#/definitions/models.OrderStateType"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.GetItemDTO"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "404": {
+                        "description": "No items found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Order state type not found",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error retrieving items",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/permissions": {
+        "/items/searchByName": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all permissions available in the system.",
+                "description": "Search for items in the inventory by their name using a query parameter.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "items"
+                ],
+                "summary": "Search items by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item name to search for",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all permissions",
                 "responses": {
                     "200": {
-                        "description": "List of permissions",
+                        "description": "List of items matching the search criteria",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Permission"
+                                "$ref": "#/definitions/dtos.GetItemDTO"
                             }
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "404": {
+                        "description": "No items found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error retrieving items",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/permissions/searchByID": {
+        "/items/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of permissions that match the given ID pattern.",
+                "description": "Retrieve an item by its unique ID from the inventory.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "items"
                 ],
-                "summary": "Search permissions by ID",
+                "summary": "Get item by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID to search for (partial or full match)",
+                        "description": "Item ID",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of matching permissions",
+                        "description": "Item found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Permission"
-                            }
+                            "$ref": "#/definitions/dtos.GetItemDTO"
                         }
                     },
                     "400": {
-                        "description": "Missing or invalid query parameter",
+                        "description": "Invalid item ID format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "404": {
+                        "description": "Item not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error fetching item",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/permissions/searchByName": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of permissions that match the given name pattern.",
+                "description": "Updates the information of an existing item by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "items"
                 ],
-                "summary": "Search permissions by name",
+                "summary": "Update an item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Name to search for (partial or full match)",
-                        "name": "name",
-                        "in": "query",
+                        "description": "ID of the item to update",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Updated item data",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateItemDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of matching permissions",
+                        "description": "Item updated successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Permission"
-                            }
+                            "$ref": "#/definitions/dtos.GetItemDTO"
                         }
                     },
                     "400": {
-                        "description": "Missing or invalid query parameter",
+                        "description": "Invalid JSON format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "404": {
+                        "description": "Item not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error updating item",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/permissions/{id}": {
-            "get": {
+        "/items/{id}/state": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a specific permission by its unique ID.",
+                "description": "Updates the state (active/inactive) of an item by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "permissions"
+                    "items"
                 ],
-                "summary": "Get permission by ID",
+                "summary": "Update the state of an item",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Permission ID",
+                        "type": "string",
+                        "description": "ID of the item to update",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New state for the item (true for active, false for inactive)",
+                        "name": "item_state",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "boolean"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Permission data",
+                        "description": "Updated item information",
                         "schema": {
-                            "$ref": "#/definitions/models.Permission"
+                            "$ref": "#/definitions/dtos.GetItemDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid permission ID",
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "403": {
-                        "description": "Access denied",
+                    "404": {
+                        "description": "Item not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Permission not found",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error updating item state",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/purchase-orders": {
+        "/items/{id}/stock": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all purchase orders from the system.",
+                "description": "Check if the specified quantity of an item is available in stock.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "purchase_orders"
+                    "items"
+                ],
+                "summary": "Check item stock availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Quantity to check",
+                        "name": "quantity",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all purchase orders",
                 "responses": {
                     "200": {
-                        "description": "List of Purchase Orders",
+                        "description": "Indicates whether the stock is sufficient or not",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
-                            }
+                            "type": "boolean"
                         }
                     },
                     "403": {
-                        "description": "Permission denied",
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Purchase Orders not found",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error checking stock",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/login": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new Purchase Order with the provided details and return the created order data.",
+                "description": "Validates the user's credentials (email and password) for login and opens a session.\nThe returned token is sent as Authorization: Bearer \u003ctoken\u003e. The session ends after the configured idle time, at expires_at or when it is revoked.\nRepeated failures for the same account or client IP impose an exponentially growing wait before the next attempt (429 with Retry-After).\nAfter too many consecutive failures the account is locked for a while (423); an administrator can unlock it earlier.\nUsers with two-factor authentication must also send two_factor_code (a TOTP code or a recovery code); without it the response is 401 auth.two_factor_required.\nIf the user type requires two-factor authentication and the user has not set it up, the response is 403 auth.two_factor_enrollment_required.\nUsers whose password login is disabled get 403 auth.password_login_disabled and must use single sign-on (/oidc/login).",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "purchase_orders"
+                    "authentication"
                 ],
-                "summary": "Create a new Purchase Order",
+                "summary": "Validate user credentials",
                 "parameters": [
                     {
-                        "description": "Purchase Order details",
-                        "name": "purchase_order",
+                        "description": "User credentials to validate",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dtos.CreatePurchaseOrderDTO"
+                            "$ref": "#/definitions/controllers.LoginData"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created Purchase Order",
+                    "200": {
+                        "description": "Login successful, with the session token",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
+                            "$ref": "#/definitions/dtos.LoginResultDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email, password or two-factor code, or two-factor code missing",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
-                        "description": "Permission denied",
+                        "description": "User account is not active, password login is disabled or two-factor setup is required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked after too many failed attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, retry after the indicated wait",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error validating credentials",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/purchase-orders/customers/{customerID}": {
+        "/margin-report/item-types": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve all Purchase Orders associated with a specific Customer ID.",
+                "description": "Revenue, landed cost and margin of each item type invoiced between the given dates.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "purchase_orders"
+                    "margin-report"
                 ],
-                "summary": "Get Purchase Orders by Customer ID",
+                "summary": "Margin report per item type",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Customer ID",
-                        "name": "customerID",
-                        "in": "path",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of Purchase Orders for the specified Customer ID",
+                        "description": "Margin per item type",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
+                                "$ref": "#/definitions/dtos.MarginRowDTO"
                             }
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Purchase Orders not found for the specified Customer ID",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error generating margin report",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/purchase-orders/searchByID": {
+        "/margin-report/items": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search for Purchase Orders based on a given ID query parameter.",
+                "description": "Revenue, landed cost and margin of each item invoiced between the given dates.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "purchase_orders"
+                    "margin-report"
                 ],
-                "summary": "Search Purchase Orders by ID",
+                "summary": "Margin report per item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Purchase Order ID",
-                        "name": "id",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of Purchase Orders",
+                        "description": "Margin per item",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
+                                "$ref": "#/definitions/dtos.MarginRowDTO"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Missing 'id' query parameter",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Purchase Orders not found",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error generating margin report",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/purchase-orders/seller/{sellerID}": {
+        "/margin-report/items/{id}/landed-cost": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve all Purchase Orders associated with a specific Seller ID.",
+                "description": "Returns the purchase price of the item plus each additional expense apportioned per unit.\nAn expense without units is apportioned over the item's stock plus its invoiced units.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "purchase_orders"
+                    "margin-report"
                 ],
-                "summary": "Get Purchase Orders by Seller ID",
+                "summary": "Get the landed cost of an item",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Seller ID",
-                        "name": "sellerID",
+                        "description": "Item ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of Purchase Orders for the specified Seller ID",
+                        "description": "Landed cost of the item",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
-                            }
+                            "$ref": "#/definitions/dtos.LandedCostDTO"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "Purchase Orders not found for the specified Seller ID",
+                        "description": "Item not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error calculating landed cost",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/purchase-orders/state/{stateID}": {
+        "/margin-report/periods": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all purchase orders that match the given state ID.",
+                "description": "Revenue, landed cost and margin of everything invoiced between the given dates, grouped by day, week or month.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "purchase_orders"
+                    "margin-report"
                 ],
-                "summary": "Get purchase orders by state ID",
+                "summary": "Margin report per period",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "State ID",
-                        "name": "stateID",
-                        "in": "path",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grouping: day, week or month (default month)",
+                        "name": "period",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of Purchase Orders",
+                        "description": "Margin per period",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
+                                "$ref": "#/definitions/dtos.MarginRowDTO"
                             }
                         }
                     },
                     "400": {
-                        "description": "Invalid State ID format",
+                        "description": "Invalid period",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Purchase Orders not found",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error generating margin report",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/purchase-orders/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/oidc/callback": {
+            "post": {
+                "description": "Exchanges the authorization code, validates the ID token and opens a session for the user whose email matches the token.\nThe returned token is sent as Authorization: Bearer \u003ctoken\u003e.\nIf the identity provider groups map to a user type, the user type is updated. Each state can be used only once.\nThe oidc_state cookie set by /oidc/login must match state; otherwise the response is 400 sso.invalid_state.\nUsers with two-factor authentication must also send two_factor_code, as in /user-credential-validation, unless the\nidentity provider reports a trusted second factor in the amr claim. A rejected code needs a new /oidc/login.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieves the details of a purchase order based on the provided ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "purchase_orders"
+                    "authentication"
                 ],
-                "summary": "Get purchase order by ID",
+                "summary": "Finish single sign-on",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Purchase Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Code and state received from the identity provider",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.OIDCCallbackDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Purchase Order details",
+                        "description": "Login successful",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
+                            "$ref": "#/definitions/dtos.OIDCLoginResultDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid request body, invalid, used or expired state, or state not matching the browser cookie",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Purchase Order not found",
+                    "401": {
+                        "description": "Code exchange or ID token validation failed, or two-factor code missing or invalid",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "No user matches the email, the account is not active or two-factor setup is required",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed two-factor attempts, retry after the indicated wait",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error finishing single sign-on",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/purchase-orders/{id}/state": {
-            "patch": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Update the state of a specific Purchase Order based on its ID.",
+        "/oidc/login": {
+            "get": {
+                "description": "Returns the URL of the company identity provider to send the user to (OpenID Connect authorization code flow with PKCE).\nThe provider sends the user back to the configured redirect page with code and state, which the client posts to /oidc/callback.\nThe response sets an HttpOnly oidc_state cookie that must be sent with the callback from the same browser.\nOnly available when an identity provider is configured.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "purchase_orders"
-                ],
-                "summary": "Change the state of a Purchase Order",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Purchase Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Order State ID",
-                        "name": "order_state_id",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "integer"
-                        }
-                    }
+                    "authentication"
                 ],
+                "summary": "Start single sign-on",
                 "responses": {
                     "200": {
-                        "description": "Updated Purchase Order and associated Invoice",
-                        "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Permission denied",
+                        "description": "Authorization URL",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/dtos.OIDCLoginDTO"
                         }
                     },
-                    "404": {
-                        "description": "Purchase Order not found",
+                    "401": {
+                        "description": "Identity provider unavailable",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error starting single sign-on",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/roles": {
+        "/order-state-types": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve a list of all roles, including their associated permissions.",
+                "description": "Retrieves a list of all available order state types.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "order-state-types"
                 ],
-                "summary": "Get all roles",
-                "responses": {
-                    "200": {
-                        "description": "List of roles with permissions",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.RoleDTO"
-                            }
+                "summary": "Get all order state types",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of order state types",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.OrderStateType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
-                        "description": "Permission denied",
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving roles",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/roles/searchByID": {
+        "/order-state-types/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search for roles using a partial or full role ID.",
+                "description": "Retrieves a specific order state type using its ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "order-state-types"
                 ],
-                "summary": "Search roles by ID",
+                "summary": "Get order state type by ID",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Role ID",
+                        "type": "integer",
+                        "description": "Order State Type ID",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns the roles matching the search criteria",
+                        "description": "Order state type retrieved successfully",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Role"
-                            }
+                            "$ref": "#/definitions/models.OrderStateType"
                         }
                     },
-                    "400": {
-                        "description": "Invalid query parameter",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "403": {
-                        "description": "Permission denied",
+                    "404": {
+                        "description": "Order state type not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error searching roles by ID",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/roles/searchByName": {
-            "get": {
+        "/password/change": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Search for roles using a partial or full role name.",
+                "description": "Changes the password of the logged-in user after checking the current password.\nThe new password must satisfy the password policy and must not match a recently used one.\nA wrong current password counts as a failed login attempt.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "authentication"
                 ],
-                "summary": "Search roles by name",
+                "summary": "Change the password",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Role Name",
-                        "name": "name",
-                        "in": "query",
-                        "required": true
+                        "description": "Current and new password",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ChangePasswordDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns the roles matching the search criteria",
+                        "description": "Password changed",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Role"
-                            }
+                            "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid query parameter",
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
-                        "description": "Permission denied",
+                        "description": "Current password is incorrect or account not active",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Password does not meet the policy or was used recently",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked after too many failed attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, retry after the indicated wait",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error searching roles by name",
+                        "description": "Error changing the password",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/roles/{id}": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/password/forgot": {
+            "post": {
+                "description": "Sends a single-use password reset token to the email address if it belongs to an account.\nThe response is the same whether or not the account exists.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Retrieve a role's details by its ID, including its permissions.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "authentication"
                 ],
-                "summary": "Get a role by ID",
+                "summary": "Request a password reset",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Account email",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ForgotPasswordDTO"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Role details with permissions",
+                    "202": {
+                        "description": "Reset requested",
                         "schema": {
-                            "$ref": "#/definitions/dtos.RoleDTO"
+                            "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid role ID",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Permission denied",
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Role not found",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error requesting the reset",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/roles/{id}/exist": {
-            "get": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+        "/password/reset": {
+            "post": {
+                "description": "Sets a new password using a token received by email. The token can be used only once and expires.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Check whether a role exists in the system by its ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "authentication"
                 ],
-                "summary": "Check if a role exists",
+                "summary": "Reset the password",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Role ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Reset token and new password",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ResetPasswordDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Returns a boolean indicating if the role exists",
+                        "description": "Password reset",
                         "schema": {
                             "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid role ID",
+                        "description": "Invalid request body or invalid, used or expired token",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "403": {
-                        "description": "Permission denied",
+                    "422": {
+                        "description": "Password does not meet the policy or was used recently",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error checking role existence",
+                        "description": "Error resetting the password",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/roles/{id}/permission": {
+        "/permissions": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve all permissions assigned to a specific role by its ID.",
+                "description": "Retrieves a list of all permissions available in the system.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "roles"
+                    "permissions"
                 ],
-                "summary": "Get all permissions of a specific role",
+                "summary": "Get all permissions",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Role ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of permissions for the role",
+                        "description": "List of permissions",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Permission"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid role ID",
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
-                        "description": "Permission denied",
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving permissions for role",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/sales-report/invoices": {
+        "/permissions/searchByID": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve invoices that were generated between the given start and end dates.",
+                "description": "Retrieves a list of permissions that match the given ID pattern.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "sales-report"
+                    "permissions"
                 ],
-                "summary": "Fetch invoices between specified dates",
+                "summary": "Search permissions by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Start Date (RFC3339 format)",
-                        "name": "startDate",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "End Date (RFC3339 format)",
-                        "name": "endDate",
+                        "description": "ID to search for (partial or full match)",
+                        "name": "id",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of invoices between the given dates",
+                        "description": "List of matching permissions",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dtos.SalesReportInvoiceDTO"
+                                "$ref": "#/definitions/models.Permission"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Invalid date format",
+                    "403": {
+                        "description": "Access denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "403": {
-                        "description": "Permission denied",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error fetching invoices",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/tax-types": {
+        "/permissions/searchByName": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches the list of all available tax types.",
+                "description": "Retrieves a list of permissions that match the given name pattern.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tax-types"
+                    "permissions"
+                ],
+                "summary": "Search permissions by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name to search for (partial or full match)",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Retrieve all tax types",
                 "responses": {
                     "200": {
-                        "description": "List of tax types",
+                        "description": "List of matching permissions",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.TaxType"
+                                "$ref": "#/definitions/models.Permission"
                             }
                         }
                     },
                     "403": {
-                        "description": "Permission denied",
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving tax types",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/permissions/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Creates a new tax type by providing its details (name, percentage, etc).",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves a specific permission by its unique ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tax-types"
+                    "permissions"
                 ],
-                "summary": "Create a new tax type",
+                "summary": "Get permission by ID",
                 "parameters": [
                     {
-                        "description": "Tax Type Details",
-                        "name": "tax",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.TaxType"
-                        }
+                        "type": "integer",
+                        "description": "Permission ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Successfully created tax type",
+                    "200": {
+                        "description": "Permission data",
                         "schema": {
-                            "$ref": "#/definitions/models.TaxType"
+                            "$ref": "#/definitions/models.Permission"
                         }
                     },
                     "400": {
-                        "description": "Invalid input data",
+                        "description": "Invalid permission ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
-                        "description": "Permission denied",
+                        "description": "Access denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Permission not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error creating tax type",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/tax-types/{id}": {
+        "/price-lists": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Fetches the details of a tax type specified by the provided ID.",
+                "description": "Retrieves all price lists ordered by priority.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tax-types"
+                    "price-lists"
                 ],
-                "summary": "Retrieve a tax type by its ID",
+                "summary": "Get all price lists",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Tax Type ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Details of the tax type",
-                        "schema": {
-                            "$ref": "#/definitions/models.TaxType"
+                        "description": "List of price lists",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetPriceListDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "Tax type not found",
+                    "500": {
+                        "description": "Error retrieving price lists",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/user-state-types": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all user state types available in the system.",
+                "description": "Creates a price list for a customer segment (\"individual\" or \"business\") and/or specific customers.\nA list can fix the price of specific items and define percentage rules over the list price,\neither for an item type or for every item (rule without item_type_id).",
                 "consumes": [
                     "application/json"
                 ],
@@ -5025,42 +6151,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user_state_types"
+                    "price-lists"
+                ],
+                "summary": "Create a price list",
+                "parameters": [
+                    {
+                        "description": "Price list data",
+                        "name": "priceList",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreatePriceListDTO"
+                        }
+                    }
                 ],
-                "summary": "Get all user state types",
                 "responses": {
-                    "200": {
-                        "description": "List of User State Types",
+                    "201": {
+                        "description": "Price list created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.UserStateType"
-                            }
+                            "$ref": "#/definitions/dtos.GetPriceListDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error creating price list",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/user-state-types/{id}": {
+        "/price-lists/customer/{customerID}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a user state type by its unique ID.",
+                "description": "Retrieves the active price lists that apply to a customer, in the order they are evaluated when billing:\nlists assigned directly to the customer first, then the lists of the customer's segment, each by priority.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5068,54 +6214,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user_state_types"
+                    "price-lists"
                 ],
-                "summary": "Get user state type by ID",
+                "summary": "Get the price lists that apply to a customer",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User State Type ID",
-                        "name": "id",
+                        "type": "integer",
+                        "description": "Customer ID",
+                        "name": "customerID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User State Type details",
+                        "description": "Applicable price lists",
                         "schema": {
-                            "$ref": "#/definitions/models.UserStateType"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.GetPriceListDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid customer ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "User State Type not found",
+                        "description": "Customer not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Error retrieving price lists",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/user-types": {
+        "/price-lists/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all user types along with their associated roles.",
+                "description": "Retrieves a price list with its fixed item prices, percentage rules and assigned customers.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5123,42 +6278,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user_types"
+                    "price-lists"
+                ],
+                "summary": "Get a price list by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Price List ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all user types",
                 "responses": {
                     "200": {
-                        "description": "List of user types",
+                        "description": "The requested price list",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.UserTypeDTO"
-                            }
+                            "$ref": "#/definitions/dtos.GetPriceListDTO"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Price list not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving user types",
+                        "description": "Error retrieving price list",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/user-types/searchByID": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Searches for user types based on the provided user type ID query.",
+                "description": "Replaces the data, item prices, rules and assigned customers of a price list.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5166,1075 +6331,5672 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user_types"
+                    "price-lists"
                 ],
-                "summary": "Search user types by ID",
+                "summary": "Update a price list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User Type ID Query",
+                        "description": "Price List ID",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Price list data",
+                        "name": "priceList",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreatePriceListDTO"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of user types matching the ID query",
+                        "description": "Price list updated",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.UserTypeDTO"
-                            }
+                            "$ref": "#/definitions/dtos.GetPriceListDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid query parameter",
+                        "description": "Invalid input",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Price list not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error searching user types",
+                        "description": "Error updating price list",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/user-types/searchByName": {
+        "/purchase-orders": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Searches for user types based on the provided user type name query.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves all purchase orders from the system.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user_types"
+                    "purchase_orders"
                 ],
-                "summary": "Search user types by name",
+                "summary": "Get all purchase orders",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "User Type Name Query",
-                        "name": "name",
-                        "in": "query",
-                        "required": true
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of user types matching the name query",
+                        "description": "List of Purchase Orders",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.UserTypeDTO"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid query parameter",
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Purchase Orders not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error searching user types",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            }
-        },
-        "/user-types/{id}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a user type based on the provided user type ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Create a new Purchase Order with the provided details and return the created order data.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user_types"
+                    "purchase_orders"
                 ],
-                "summary": "Get user type by ID",
+                "summary": "Create a new Purchase Order",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User Type ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Purchase Order details",
+                        "name": "purchase_order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreatePurchaseOrderDTO"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "User type information",
+                    "201": {
+                        "description": "Created Purchase Order",
                         "schema": {
-                            "$ref": "#/definitions/dtos.UserTypeDTO"
+                            "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
                         }
                     },
                     "400": {
-                        "description": "Invalid user type ID format",
+                        "description": "Invalid request data",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "User type not found",
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/user-types/{id}/exists": {
+        "/purchase-orders/customers/{customerID}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Checks if a user type exists based on the provided user type ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve all Purchase Orders associated with a specific Customer ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user_types"
+                    "purchase_orders"
                 ],
-                "summary": "Check if a user type exists",
+                "summary": "Get Purchase Orders by Customer ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User Type ID",
-                        "name": "id",
+                        "description": "Customer ID",
+                        "name": "customerID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Existence status of the user type",
+                        "description": "List of Purchase Orders for the specified Customer ID",
                         "schema": {
-                            "$ref": "#/definitions/models.MessageResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid user type ID",
+                    "403": {
+                        "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "403": {
-                        "description": "Permission denied",
+                    "404": {
+                        "description": "Purchase Orders not found for the specified Customer ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error checking user type existence",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/users": {
+        "/purchase-orders/searchByID": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all users in the system.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Search for Purchase Orders based on a given ID query parameter.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "purchase_orders"
+                ],
+                "summary": "Search Purchase Orders by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Purchase Order ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
                     "200": {
-                        "description": "List of users",
+                        "description": "List of Purchase Orders",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dtos.GetUserDTO"
+                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
                             }
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "Users not found",
+                        "description": "Purchase Orders not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving users",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/purchase-orders/seller/{sellerID}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Creates a new user with the provided email, password, user type, and state.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve all Purchase Orders associated with a specific Seller ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "purchase_orders"
                 ],
-                "summary": "Create a new user",
+                "summary": "Get Purchase Orders by Seller ID",
                 "parameters": [
                     {
-                        "description": "User details to create",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.CreateUserDTO"
-                        }
+                        "type": "string",
+                        "description": "Seller ID",
+                        "name": "sellerID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created user information",
-                        "schema": {
-                            "$ref": "#/definitions/dtos.GetUserDTO"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid request body",
+                    "200": {
+                        "description": "List of Purchase Orders for the specified Seller ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
+                            }
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "409": {
-                        "description": "Email already in use",
+                    "404": {
+                        "description": "Purchase Orders not found for the specified Seller ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error creating user",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/users/searchByEmail": {
+        "/purchase-orders/state/{stateID}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Searches for users by email based on the provided query parameter.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves all purchase orders that match the given state ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "purchase_orders"
                 ],
-                "summary": "Search users by email",
+                "summary": "Get purchase orders by state ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User email to search",
-                        "name": "email",
-                        "in": "query",
+                        "description": "State ID",
+                        "name": "stateID",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of users matching the search criteria",
+                        "description": "List of Purchase Orders",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dtos.GetUserDTO"
+                                "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
                             }
                         }
                     },
                     "400": {
-                        "description": "Query parameter is required",
+                        "description": "Invalid State ID format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Users not found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error searching users",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/users/searchByID": {
+        "/purchase-orders/{id}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Searches for users by ID based on the provided query parameter.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieves the details of a purchase order based on the provided ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "purchase_orders"
                 ],
-                "summary": "Search users by ID",
+                "summary": "Get purchase order by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID to search",
+                        "description": "Purchase Order ID",
                         "name": "id",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of users matching the search criteria",
+                        "description": "Purchase Order details",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dtos.GetUserDTO"
-                            }
+                            "$ref": "#/definitions/dtos.GetPurchaseOrderDTO"
                         }
                     },
                     "400": {
-                        "description": "Query parameter is required",
+                        "description": "Invalid ID format",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "Users not found",
+                        "description": "Purchase Order not found",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error searching users",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/users/{id}": {
-            "get": {
+        "/purchase-orders/{id}/state": {
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a user by their ID. Returns user details if found, otherwise returns an error.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Update the state of a specific Purchase Order based on its ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "purchase_orders"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Change the state of a Purchase Order",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Purchase Order ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Order State ID",
+                        "name": "order_state_id",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "integer"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User details",
+                        "description": "Updated Purchase Order and associated Invoice",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetUserDTO"
+                            "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID format",
+                        "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Purchase Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving user",
+                        "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Pings the database, compares the applied schema version with the one the binary expects and reports connection pool usage.\nReturns 503 when any check fails, when the pool is saturated or while the server is shutting down.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Ready to receive traffic",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ReadinessDTO"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ReadinessDTO"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates user details such as email, password, user type, and state.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve a list of all roles, including their associated permissions.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "roles"
                 ],
-                "summary": "Update user information",
+                "summary": "Get all roles",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
                     },
                     {
-                        "description": "User details to update",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.UpdateUserDTO"
-                        }
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated user information",
+                        "description": "List of roles with permissions",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetUserDTO"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.RoleDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid pagination, sort or filter parameters",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "User not found",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error updating user information",
+                        "description": "Error retrieving roles",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/users/{id}/state": {
-            "patch": {
+        "/roles/searchByID": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Updates the state of a user based on the provided user ID and state value.",
-                "consumes": [
+                "description": "Search for roles using a partial or full role ID.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Search roles by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the roles matching the search criteria",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error searching roles by ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/searchByName": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Search for roles using a partial or full role name.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "roles"
                 ],
-                "summary": "Update user state",
+                "summary": "Search roles by name",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Role Name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Returns the roles matching the search criteria",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error searching roles by name",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve a role's details by its ID, including its permissions and data scope rules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get a role by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Role details with permissions",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RoleDTO"
+                        }
                     },
-                    {
-                        "description": "User state to update",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "Invalid role ID",
                         "schema": {
-                            "$ref": "#/definitions/controllers.request"
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     }
+                }
+            }
+        },
+        "/roles/{id}/exist": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Check whether a role exists in the system by its ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Check if a role exists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Updated user information",
+                        "description": "Returns a boolean indicating if the role exists",
                         "schema": {
-                            "$ref": "#/definitions/dtos.GetUserDTO"
+                            "$ref": "#/definitions/models.MessageResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid role ID",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "403": {
                         "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "500": {
+                        "description": "Error checking role existence",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{id}/permission": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve all permissions assigned to a specific role by its ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Get all permissions of a specific role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of permissions for the role",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/models.ProblemDetails"
                         }
                     },
                     "500": {
-                        "description": "Error updating user state",
+                        "description": "Error retrieving permissions for role",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/roles/{id}/scopes": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the data scope rules of a role. With the rule own on purchase_orders, users with the role only list, search and get the purchase orders where their employee is the seller;\non customers, the customers of those orders; on appointments, the appointments of those customers. Records out of scope respond 404.\nA rule does not apply if another role of the user grants the same permission without a rule for the resource. An empty list removes all rules.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "roles"
+                ],
+                "summary": "Set the data scope rules of a role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Data scope rules",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "$ref": "#/definitions/dtos.SetRoleScopesDTO"
                         }
                     }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scope rules updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role ID or request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Role not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating the role",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-report/customers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Invoice count, subtotal, discount, tax and total invoiced between the given dates for each customer.\nAdd format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "sales-report"
+                ],
+                "summary": "Revenue by customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revenue per customer",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.CustomerRevenueDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error generating report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-report/invoices": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve invoices that were generated between the given start and end dates.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sales-report"
+                ],
+                "summary": "Fetch invoices between specified dates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of invoices between the given dates",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.SalesReportInvoiceDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error fetching invoices",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-report/item-types": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Quantity and revenue invoiced between the given dates for each item type.\nAdd format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "sales-report"
+                ],
+                "summary": "Revenue by item type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revenue per item type",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.ItemTypeRevenueDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error generating report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-report/revenue": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Invoice count, subtotal, discount, tax and total invoiced between the given dates, grouped by day, week or month.\nEach period is compared with the previous one. Add format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "sales-report"
+                ],
+                "summary": "Revenue by period",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Grouping: day, week or month (default month)",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revenue per period",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.RevenueByPeriodDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error generating report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-report/sellers": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Invoice count, subtotal, discount, tax and total invoiced between the given dates for each seller,\ntaken from the purchase order that generated each invoice. Invoices without a purchase order are not included.\nAdd format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "sales-report"
+                ],
+                "summary": "Revenue by seller",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Revenue per seller",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.SellerRevenueDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error generating report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-report/taxes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Number of invoices, taxable amount and tax collected between the given dates for each tax type.\nAdd format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "sales-report"
+                ],
+                "summary": "Tax summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tax collected per tax type",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.TaxSummaryDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error generating report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sales-report/top-items": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The N items with the highest quantity or revenue invoiced between the given dates.\nAdd format=csv to download the report as CSV.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "sales-report"
+                ],
+                "summary": "Top selling items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start Date (RFC3339 format)",
+                        "name": "startDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End Date (RFC3339 format)",
+                        "name": "endDate",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ranking criteria: quantity (default) or revenue",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items, between 1 and 100 (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format: json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Top items",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.TopItemDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ranking criteria or limit",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error generating report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/scheduled-reports": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves every scheduled report definition with its next planned run.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-reports"
+                ],
+                "summary": "Get all scheduled reports",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of scheduled reports",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetScheduledReportDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving scheduled reports",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a report definition that is generated and emailed according to a cron expression\n(five fields or descriptors such as @weekly). Reports: sales_summary, tax_summary, top_items,\nrevenue_by_item_type, revenue_by_customer, revenue_by_seller. Formats: json, csv, pdf.\nThe range parameter is relative to each run: previous_day, previous_week, previous_month, last_7_days or last_30_days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-reports"
+                ],
+                "summary": "Create a scheduled report",
+                "parameters": [
+                    {
+                        "description": "Scheduled report definition",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateScheduledReportDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Scheduled report created",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GetScheduledReportDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating scheduled report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/scheduled-reports/runs/{runId}/artifact": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Downloads the JSON, CSV or PDF file generated by a run of a scheduled report.",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/pdf"
+                ],
+                "tags": [
+                    "scheduled-reports"
+                ],
+                "summary": "Download the file generated by a report run",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Report Run ID",
+                        "name": "runId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Generated report",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Run not found or without a generated file",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving run",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/scheduled-reports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a scheduled report definition with its next planned run.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-reports"
+                ],
+                "summary": "Get a scheduled report by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The requested scheduled report",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GetScheduledReportDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled report not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving scheduled report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a scheduled report definition and reschedules it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-reports"
+                ],
+                "summary": "Update a scheduled report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Scheduled report definition",
+                        "name": "report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateScheduledReportDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scheduled report updated",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GetScheduledReportDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled report not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating scheduled report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a scheduled report definition together with its run history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-reports"
+                ],
+                "summary": "Delete a scheduled report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Scheduled report deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled report not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error deleting scheduled report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/scheduled-reports/{id}/run": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generates and delivers the report immediately. The run is recorded like a scheduled one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-reports"
+                ],
+                "summary": "Run a scheduled report now",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The recorded run",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled report not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error running scheduled report",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/scheduled-reports/{id}/runs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the run history of a scheduled report, most recent first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "scheduled-reports"
+                ],
+                "summary": "Get the runs of a scheduled report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Scheduled Report ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Runs of the scheduled report",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReportRun"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled report not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving runs",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/security-events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the authentication audit log: successful and failed logins, throttled attempts, account locks and unlocks. Newest first by default.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "security"
+                ],
+                "summary": "Get security events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of security events",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.SecurityEvent"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving security events",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the open sessions of the logged-in user with their device, IP, creation and last use. The session used for the request is marked as current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Get own sessions",
+                "responses": {
+                    "200": {
+                        "description": "List of sessions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.SessionDTO"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving sessions",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logs out one of the sessions of the logged-in user, for example on a lost device. It can be the current session.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke an own session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Session already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error revoking the session",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-types": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches the list of all available tax types.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax-types"
+                ],
+                "summary": "Retrieve all tax types",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tax types",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TaxType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving tax types",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new tax type by providing its details (name, percentage, etc).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax-types"
+                ],
+                "summary": "Create a new tax type",
+                "parameters": [
+                    {
+                        "description": "Tax Type Details",
+                        "name": "tax",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxType"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Successfully created tax type",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxType"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input data",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating tax type",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax-types/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fetches the details of a tax type specified by the provided ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tax-types"
+                ],
+                "summary": "Retrieve a tax type by its ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tax Type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Details of the tax type",
+                        "schema": {
+                            "$ref": "#/definitions/models.TaxType"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Tax type not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Tells whether the logged-in user has two-factor authentication enabled or pending, whether the user type requires it and how many recovery codes are left.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two_factor"
+                ],
+                "summary": "Get two-factor status",
+                "responses": {
+                    "200": {
+                        "description": "Two-factor status",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TwoFactorStatusDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving the status",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor/confirm": {
+            "post": {
+                "description": "Enables the pending TOTP secret with a first code from the authenticator app and returns the recovery codes. They are shown only once.\nLike enrollment, it identifies the user by email and password instead of a session.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two_factor"
+                ],
+                "summary": "Confirm two-factor enrollment",
+                "parameters": [
+                    {
+                        "description": "Email, password and TOTP code",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TwoFactorConfirmDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recovery codes",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RecoveryCodesDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email, password or two-factor code",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "No pending enrollment or already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked after too many failed attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, retry after the indicated wait",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error confirming the enrollment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor/disable": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes two-factor authentication from the logged-in user. Requires the password and a TOTP or recovery code.\nNot allowed when the user type requires two-factor authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two_factor"
+                ],
+                "summary": "Disable two-factor authentication",
+                "parameters": [
+                    {
+                        "description": "Password and code",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TwoFactorDisableDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid password or two-factor code",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Not enabled or mandatory for the user type",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked after too many failed attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, retry after the indicated wait",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error disabling two-factor authentication",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor/enroll": {
+            "post": {
+                "description": "Generates a new TOTP secret for the user identified by email and password.\nShow provisioning_uri as a QR code to register it in an authenticator app, then confirm it with /two-factor/confirm.\nIt does not require a session, so users whose user type requires two-factor authentication can set it up before their first login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two_factor"
+                ],
+                "summary": "Start two-factor enrollment",
+                "parameters": [
+                    {
+                        "description": "Email and password",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TwoFactorEnrollDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "TOTP secret and provisioning URI",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TwoFactorEnrollmentDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid email or password",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked after too many failed attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, retry after the indicated wait",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error starting the enrollment",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/two-factor/recovery-codes": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the recovery codes of the logged-in user. The previous codes stop working. Requires a TOTP or recovery code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "two_factor"
+                ],
+                "summary": "Regenerate recovery codes",
+                "parameters": [
+                    {
+                        "description": "TOTP or recovery code",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TwoFactorCodeDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "New recovery codes",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RecoveryCodesDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid two-factor code",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "423": {
+                        "description": "Account locked after too many failed attempts",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed attempts, retry after the indicated wait",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error regenerating the codes",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-state-types": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all user state types available in the system.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_state_types"
+                ],
+                "summary": "Get all user state types",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of User State Types",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UserStateType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-state-types/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a user state type by its unique ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_state_types"
+                ],
+                "summary": "Get user state type by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User State Type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User State Type details",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserStateType"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User State Type not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-types": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all user types along with their associated roles.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_types"
+                ],
+                "summary": "Get all user types",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of user types",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.UserTypeDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving user types",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-types/searchByID": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Searches for user types based on the provided user type ID query.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_types"
+                ],
+                "summary": "Search user types by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User Type ID Query",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of user types matching the ID query",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.UserTypeDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error searching user types",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-types/searchByName": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Searches for user types based on the provided user type name query.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_types"
+                ],
+                "summary": "Search user types by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User Type Name Query",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of user types matching the name query",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.UserTypeDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameter",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error searching user types",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-types/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a user type based on the provided user type ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_types"
+                ],
+                "summary": "Get user type by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User Type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User type information",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UserTypeDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user type ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User type not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-types/{id}/exists": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Checks if a user type exists based on the provided user type ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_types"
+                ],
+                "summary": "Check if a user type exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User Type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Existence status of the user type",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user type ID",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error checking user type existence",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/user-types/{id}/two-factor": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Makes two-factor authentication mandatory (or optional) for every user of the user type.\nUsers without it set up cannot log in until they enroll.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user_types"
+                ],
+                "summary": "Require two-factor authentication for a user type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User Type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Two-factor policy",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateUserTypeTwoFactorDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Policy updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user type ID or request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User type not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating the user type",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all users in the system.\nAny other query parameter filters the list: field=value or field[op]=value with op eq, ne, gt, gte, lt, lte, like or in.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get all users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, cannot be combined with cursor",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to sort by, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.PageDTO"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.GetUserDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination, sort or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Users not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving users",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new user with the provided email, password, user type, and state. The password must satisfy the password policy.\nTo let the user choose their own password, invite them through /invitations instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User details to create",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateUserDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created user information",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GetUserDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Email already in use",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed or password does not meet the policy",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/searchByEmail": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Searches for users by email based on the provided query parameter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Search users by email",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User email to search",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users matching the search criteria",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.GetUserDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Users not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error searching users",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/searchByID": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Searches for users by ID based on the provided query parameter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Search users by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID to search",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users matching the search criteria",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.GetUserDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Users not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error searching users",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a user by their ID. Returns user details if found, otherwise returns an error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User details",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GetUserDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID format",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates user details such as email, user type, and state. The password is changed through /password/change or the reset flow.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User details to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateUserDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated user information",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GetUserDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating user information",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/password-login": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "With enabled=false the user can only log in through the company identity provider (/oidc/login); with true the password remains as a fallback.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Enable or disable password login for a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether password login is allowed",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdatePasswordLoginDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password login updated",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating the user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the open sessions of any user with their device, IP, creation and last use.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user's sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of sessions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.SessionDTO"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving sessions",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logs out every open session of any user. The user can log in again unless the account is also deactivated or locked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Revoke all of a user's sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions revoked",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error revoking the sessions",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Logs out one session of any user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Revoke a user's session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session revoked",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User or session not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Session already revoked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error revoking the session",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/state": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates the state of a user based on the provided user ID and state value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update user state",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User state to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controllers.request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated user information",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GetUserDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating user state",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/two-factor": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes two-factor authentication from another user, for example after losing the device and the recovery codes. If the user type requires it, the user must enroll again before logging in.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Reset a user's two-factor authentication",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication reset",
+                        "schema": {
+                            "$ref": "#/definitions/models.MessageResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error resetting two-factor authentication",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Unlocks a user account locked after too many failed login attempts, without waiting for the lock to expire, and clears its failed attempts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Unlock a user account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unlocked user",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GetUserDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Permission denied",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "User account is not locked",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Error unlocking user",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controllers.LoginData": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "two_factor_code": {
+                    "description": "TwoFactorCode es el código TOTP o un código de recuperación; sólo para\nusuarios con segundo factor",
+                    "type": "string"
+                }
+            }
+        },
+        "controllers.SubtotalResponse": {
+            "type": "object",
+            "properties": {
+                "subtotal": {
+                    "type": "number"
+                }
+            }
+        },
+        "controllers.request": {
+            "type": "object",
+            "properties": {
+                "user_state": {
+                    "description": "Correctly defines the JSON binding",
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.APIKeyDTO": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active indica si la llave sirve ahora; una llave rotada sirve hasta el\nfin del período de gracia",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "principal": {
+                    "type": "string"
+                },
+                "replaced_by_id": {
+                    "type": "integer"
+                },
+                "reporter_tax_id": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AcceptInvitationDTO": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AppliedDiscountDTO": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "automatic": {
+                    "type": "boolean"
+                },
+                "discount_type_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.ApportionedExpenseDTO": {
+            "type": "object",
+            "properties": {
+                "expense": {
+                    "type": "number"
+                },
+                "expense_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "per_unit": {
+                    "type": "number"
+                },
+                "units": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.BelowCostWarningDTO": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "integer"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "landed_cost": {
+                    "type": "number"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.BillingBreakdownDTO": {
+            "type": "object",
+            "properties": {
+                "applied_discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AppliedDiscountDTO"
+                    }
+                },
+                "discount_total": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BillingItemDTO"
+                    }
+                },
+                "rejected_discounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.RejectedDiscountDTO"
+                    }
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax_total": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BelowCostWarningDTO"
+                    }
+                }
+            }
+        },
+        "dtos.BillingItemDTO": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "price_list_id": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.CalculateTotalRequestDTO": {
+            "type": "object",
+            "required": [
+                "itemsDTO"
+            ],
+            "properties": {
+                "couponCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "customerId": {
+                    "type": "integer"
+                },
+                "discountTypesIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "itemsDTO": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.BillingItemDTO"
+                    }
+                },
+                "priceDate": {
+                    "type": "string"
+                },
+                "taxTypesIds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dtos.ChangePasswordDTO": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateAPIKeyDTO": {
+            "type": "object",
+            "required": [
+                "name",
+                "permissions",
+                "principal"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "ExpiresAt es opcional; sin él la llave vence después de la vigencia\nconfigurada",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "permissions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "principal": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "reporter_tax_id": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "dtos.CreateCommentDTO": {
+            "type": "object",
+            "required": [
+                "email",
+                "last_name",
+                "name"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "residence_city": {
+                    "type": "string"
+                },
+                "residence_state": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateCustomerDTO": {
+            "type": "object",
+            "required": [
+                "customerId",
+                "customerName",
+                "email",
+                "identifierTypeId",
+                "lastName"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "customerId": {
+                    "type": "string"
+                },
+                "customerName": {
+                    "type": "string"
+                },
+                "customerState": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "identifierTypeId": {
+                    "type": "integer"
+                },
+                "isBusiness": {
+                    "type": "boolean"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "phoneNumbers": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateDiscountTypeDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "auto_apply": {
+                    "type": "boolean"
+                },
+                "buy_quantity": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "coupon_code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "is_percentage": {
+                    "type": "boolean"
+                },
+                "item_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "item_type_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "max_uses_per_customer": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_purchase_amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.CreateEmployeeDTO": {
+            "type": "object",
+            "required": [
+                "identifier_type_id",
+                "last_names",
+                "names",
+                "personal_id",
+                "user_id"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "identifier_type_id": {
+                    "type": "integer"
+                },
+                "last_names": {
+                    "type": "string"
+                },
+                "names": {
+                    "type": "string"
+                },
+                "personal_id": {
+                    "type": "string"
+                },
+                "phone_numbers": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CreateExternalSaleDTO": {
+            "type": "object",
+            "required": [
+                "customerId",
+                "customerName",
+                "email",
+                "identifierTypeId",
+                "item_id",
+                "lastName"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "customerId": {
+                    "type": "string"
+                },
+                "customerName": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "identifierTypeId": {
+                    "type": "integer"
+                },
+                "isBusiness": {
+                    "type": "boolean"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "phoneNumbers": {
+                    "type": "string"
+                },
+                "reporter_id": {
+                    "type": "string"
+                },
+                "reporter_name": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CreateInvoiceDTO": {
+            "type": "object",
+            "required": [
+                "customer_id",
+                "enterprise_data",
+                "items"
+            ],
+            "properties": {
+                "coupon_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "enterprise_data": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.BillingItemDTO"
+                    }
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dtos.CreatePriceListDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "customer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.PriceListItemDTO"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.PriceListRuleDTO"
+                    }
+                },
+                "segment": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreatePurchaseOrderDTO": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.BillingItemDTO"
+                    }
+                }
+            }
+        },
+        "dtos.CreateScheduledReportDTO": {
+            "type": "object",
+            "required": [
+                "cron",
+                "format",
+                "name",
+                "recipients",
+                "report"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "$ref": "#/definitions/dtos.ScheduledReportParametersDTO"
+                },
+                "recipients": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "report": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateUserDTO": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "user_state",
+                "user_type"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "user_state": {
+                    "type": "integer"
+                },
+                "user_type": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CustomerRevenueDTO": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "discount_total": {
+                    "type": "number"
+                },
+                "invoice_count": {
+                    "type": "integer"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "tax_total": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.DatabaseCheckDTO": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.ForgotPasswordDTO": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.GetCommentDTO": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "residence_city": {
+                    "type": "string"
+                },
+                "residence_state": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.GetCustomerDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "customerId": {
+                    "type": "string"
+                },
+                "customerName": {
+                    "type": "string"
+                },
+                "customerState": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identifierTypeId": {
+                    "type": "integer"
+                },
+                "isBusiness": {
+                    "type": "boolean"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "phoneNumbers": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.GetEmployeeDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "identifier_type_id": {
+                    "type": "integer"
+                },
+                "last_names": {
+                    "type": "string"
+                },
+                "names": {
+                    "type": "string"
+                },
+                "personal_id": {
+                    "type": "string"
+                },
+                "phone_numbers": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.GetExternalSaleDTO": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "integer"
+                },
+                "customer_email": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "item_name": {
+                    "type": "string"
+                },
+                "reporter_id": {
+                    "type": "string"
+                },
+                "reporter_name": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.GetInvoiceDTO": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "type": "integer"
+                },
+                "date_time": {
+                    "type": "string"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "enterprise_data": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BillingItemDTO"
+                    }
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "number"
+                },
+                "warnings": {
+                    "description": "Warnings solo se informa al crear la factura.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BelowCostWarningDTO"
+                    }
+                }
+            }
+        },
+        "dtos.GetItemDTO": {
+            "type": "object",
+            "properties": {
+                "additional_expenses": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_state": {
+                    "type": "boolean"
+                },
+                "item_type_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "purchase_price": {
+                    "type": "number"
+                },
+                "selling_price": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.GetPriceListDTO": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "customer_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.PriceListItemDTO"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.PriceListRuleDTO"
+                    }
+                },
+                "segment": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.GetPurchaseOrderDTO": {
+            "type": "object",
+            "properties": {
+                "customer_id": {
+                    "description": "Cambiado a puntero",
+                    "type": "integer"
+                },
+                "date_time": {
+                    "type": "string"
+                },
+                "discounts": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BillingItemDTO"
+                    }
+                },
+                "order_state_id": {
+                    "type": "integer"
+                },
+                "responsible_id": {
+                    "description": "Cambiado a puntero",
+                    "type": "integer"
+                },
+                "seller_id": {
+                    "description": "Cambiado a puntero",
+                    "type": "integer"
+                },
+                "sub_total": {
+                    "type": "number"
+                },
+                "taxes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.GetScheduledReportDTO": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "$ref": "#/definitions/dtos.ScheduledReportParametersDTO"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "report": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.GetUserDTO": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "user_state": {
+                    "type": "integer"
+                },
+                "user_type": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.InvitationDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "expired": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invited_by": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_type": {
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "controllers.LoginData": {
+        },
+        "dtos.InviteUserDTO": {
             "type": "object",
             "required": [
                 "email",
-                "password"
+                "user_type"
             ],
             "properties": {
                 "email": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
-                }
-            }
-        },
-        "controllers.SubtotalResponse": {
-            "type": "object",
-            "properties": {
-                "subtotal": {
-                    "type": "number"
-                }
-            }
-        },
-        "controllers.TotalResponse": {
-            "type": "object",
-            "properties": {
-                "total": {
-                    "type": "number"
+                "user_type": {
+                    "type": "integer"
                 }
             }
         },
-        "controllers.request": {
+        "dtos.IssuedAPIKeyDTO": {
             "type": "object",
             "properties": {
-                "user_state": {
-                    "description": "Correctly defines the JSON binding",
-                    "type": "integer"
+                "api_key": {
+                    "$ref": "#/definitions/dtos.APIKeyDTO"
+                },
+                "key": {
+                    "type": "string"
                 }
             }
         },
-        "dtos.BillingItemDTO": {
+        "dtos.ItemTypeRevenueDTO": {
             "type": "object",
             "properties": {
-                "id": {
+                "item_type_id": {
                     "type": "integer"
                 },
-                "stock": {
+                "item_type_name": {
+                    "type": "string"
+                },
+                "quantity": {
                     "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
                 }
             }
         },
-        "dtos.CalculateTotalRequestDTO": {
+        "dtos.LandedCostDTO": {
             "type": "object",
             "properties": {
-                "discountTypesIds": {
+                "expenses": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/dtos.ApportionedExpenseDTO"
                     }
                 },
-                "itemsDTO": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dtos.BillingItemDTO"
-                    }
+                "item_id": {
+                    "type": "integer"
                 },
-                "taxTypesIds": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "item_name": {
+                    "type": "string"
+                },
+                "landed_cost": {
+                    "type": "number"
+                },
+                "purchase_price": {
+                    "type": "number"
+                },
+                "selling_price": {
+                    "type": "number"
                 }
             }
         },
-        "dtos.CreateCommentDTO": {
+        "dtos.LoginResultDTO": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "last_name": {
-                    "type": "string"
-                },
-                "name": {
+                "expires_at": {
                     "type": "string"
                 },
-                "phone": {
+                "message": {
                     "type": "string"
                 },
-                "residence_city": {
-                    "type": "string"
+                "session_id": {
+                    "type": "integer"
                 },
-                "residence_state": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "dtos.CreateCustomerDTO": {
+        "dtos.MarginRowDTO": {
             "type": "object",
-            "required": [
-                "customerId",
-                "customerName",
-                "email",
-                "identifierTypeId",
-                "lastName"
-            ],
             "properties": {
-                "address": {
-                    "type": "string"
+                "cost": {
+                    "type": "number"
                 },
-                "customerId": {
-                    "type": "string"
+                "item_id": {
+                    "type": "integer"
                 },
-                "customerName": {
+                "item_name": {
                     "type": "string"
                 },
-                "customerState": {
-                    "type": "boolean"
+                "item_type_id": {
+                    "type": "integer"
                 },
-                "email": {
+                "item_type_name": {
                     "type": "string"
                 },
-                "identifierTypeId": {
-                    "type": "integer"
+                "margin": {
+                    "type": "number"
                 },
-                "isBusiness": {
-                    "type": "boolean"
+                "margin_percent": {
+                    "type": "number"
                 },
-                "lastName": {
+                "period": {
                     "type": "string"
                 },
-                "phoneNumbers": {
-                    "type": "string"
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
                 }
             }
         },
-        "dtos.CreateEmployeeDTO": {
+        "dtos.MigrationsCheckDTO": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "identifier_type_id": {
+                "current_version": {
                     "type": "integer"
                 },
-                "last_names": {
-                    "type": "string"
-                },
-                "names": {
-                    "type": "string"
-                },
-                "personal_id": {
-                    "type": "string"
-                },
-                "phone_numbers": {
+                "error": {
                     "type": "string"
                 },
-                "user_id": {
+                "expected_version": {
                     "type": "integer"
+                },
+                "ok": {
+                    "type": "boolean"
                 }
             }
         },
-        "dtos.CreateExternalSaleDTO": {
+        "dtos.OIDCCallbackDTO": {
             "type": "object",
             "required": [
-                "customerId",
-                "customerName",
-                "email",
-                "identifierTypeId",
-                "item_id",
-                "lastName",
-                "reporter_id",
-                "reporter_name"
+                "code",
+                "state"
             ],
             "properties": {
-                "address": {
+                "code": {
                     "type": "string"
                 },
-                "customerId": {
+                "state": {
                     "type": "string"
                 },
-                "customerName": {
+                "two_factor_code": {
+                    "description": "TwoFactorCode es el código TOTP o un código de recuperación; sólo para\nusuarios con segundo factor.",
                     "type": "string"
-                },
+                }
+            }
+        },
+        "dtos.OIDCLoginDTO": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.OIDCLoginResultDTO": {
+            "type": "object",
+            "properties": {
                 "email": {
                     "type": "string"
                 },
-                "identifierTypeId": {
-                    "type": "integer"
-                },
-                "isBusiness": {
-                    "type": "boolean"
-                },
-                "item_id": {
-                    "type": "integer"
-                },
-                "lastName": {
+                "expires_at": {
                     "type": "string"
                 },
-                "phoneNumbers": {
+                "message": {
                     "type": "string"
                 },
-                "reporter_id": {
+                "session_id": {
+                    "type": "integer"
+                },
+                "token": {
                     "type": "string"
+                }
+            }
+        },
+        "dtos.PageDTO": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "limit": {
+                    "type": "integer"
                 },
-                "reporter_name": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "stock": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "dtos.CreateInvoiceDTO": {
+        "dtos.PoolCheckDTO": {
             "type": "object",
             "properties": {
-                "customer_id": {
+                "idle": {
                     "type": "integer"
                 },
-                "discounts": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "in_use": {
+                    "type": "integer"
                 },
-                "enterprise_data": {
-                    "type": "string"
+                "max_open": {
+                    "type": "integer"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dtos.BillingItemDTO"
-                    }
+                "ok": {
+                    "type": "boolean"
                 },
-                "taxes": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "open": {
+                    "type": "integer"
+                },
+                "wait_count": {
+                    "type": "integer"
+                },
+                "wait_duration_ms": {
+                    "type": "integer"
                 }
             }
         },
-        "dtos.CreatePurchaseOrderDTO": {
+        "dtos.PriceListItemDTO": {
             "type": "object",
+            "required": [
+                "item_id"
+            ],
             "properties": {
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dtos.BillingItemDTO"
-                    }
+                "item_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0
                 }
             }
         },
-        "dtos.CreateUserDTO": {
+        "dtos.PriceListRuleDTO": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                },
-                "user_state": {
+                "item_type_id": {
                     "type": "integer"
                 },
-                "user_type": {
-                    "type": "integer"
+                "percentage": {
+                    "type": "number"
                 }
             }
         },
-        "dtos.GetCommentDTO": {
+        "dtos.ReadinessDTO": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "type": "string"
-                },
-                "email": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "last_name": {
-                    "type": "string"
+                "database": {
+                    "$ref": "#/definitions/dtos.DatabaseCheckDTO"
                 },
-                "name": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
+                "migrations": {
+                    "$ref": "#/definitions/dtos.MigrationsCheckDTO"
                 },
-                "residence_city": {
-                    "type": "string"
+                "pool": {
+                    "$ref": "#/definitions/dtos.PoolCheckDTO"
                 },
-                "residence_state": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "dtos.GetCustomerDTO": {
+        "dtos.RecoveryCodesDTO": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "customerId": {
-                    "type": "string"
-                },
-                "customerName": {
-                    "type": "string"
-                },
-                "customerState": {
-                    "type": "boolean"
-                },
-                "email": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.RejectedDiscountDTO": {
+            "type": "object",
+            "properties": {
+                "coupon_code": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "identifierTypeId": {
+                "discount_type_id": {
                     "type": "integer"
                 },
-                "isBusiness": {
-                    "type": "boolean"
-                },
-                "lastName": {
+                "message": {
                     "type": "string"
                 },
-                "phoneNumbers": {
+                "reason": {
                     "type": "string"
                 }
             }
         },
-        "dtos.GetEmployeeDTO": {
+        "dtos.ResetPasswordDTO": {
             "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
             "properties": {
-                "address": {
+                "new_password": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.RevenueByPeriodDTO": {
+            "type": "object",
+            "properties": {
+                "discount_total": {
+                    "type": "number"
                 },
-                "identifier_type_id": {
+                "invoice_count": {
                     "type": "integer"
                 },
-                "last_names": {
+                "period": {
                     "type": "string"
                 },
-                "names": {
-                    "type": "string"
+                "previous_total": {
+                    "type": "number"
                 },
-                "personal_id": {
-                    "type": "string"
+                "subtotal": {
+                    "type": "number"
                 },
-                "phone_numbers": {
-                    "type": "string"
+                "tax_total": {
+                    "type": "number"
                 },
-                "user_id": {
-                    "type": "integer"
+                "total": {
+                    "type": "number"
+                },
+                "total_change": {
+                    "type": "number"
+                },
+                "total_change_percent": {
+                    "type": "number"
                 }
             }
         },
-        "dtos.GetExternalSaleDTO": {
+        "dtos.RoleDTO": {
             "type": "object",
             "properties": {
-                "customer_email": {
+                "description": {
                     "type": "string"
                 },
-                "customer_id": {
-                    "type": "integer"
-                },
                 "id": {
                     "type": "integer"
                 },
-                "item_id": {
-                    "type": "integer"
-                },
-                "item_name": {
-                    "type": "string"
-                },
-                "reporter_id": {
+                "name": {
                     "type": "string"
                 },
-                "reporter_name": {
-                    "type": "string"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "stock": {
-                    "type": "integer"
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.RoleScopeDTO"
+                    }
                 }
             }
         },
-        "dtos.GetInvoiceDTO": {
+        "dtos.RoleScopeDTO": {
             "type": "object",
+            "required": [
+                "resource",
+                "rule"
+            ],
             "properties": {
-                "customer_id": {
-                    "type": "integer"
+                "resource": {
+                    "type": "string",
+                    "enum": [
+                        "purchase_orders",
+                        "customers",
+                        "appointments"
+                    ]
                 },
+                "rule": {
+                    "type": "string",
+                    "enum": [
+                        "own"
+                    ]
+                }
+            }
+        },
+        "dtos.SalesReportInvoiceDTO": {
+            "type": "object",
+            "properties": {
                 "date_time": {
                     "type": "string"
                 },
                 "discounts": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.DiscountType"
                     }
                 },
-                "enterprise_data": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "integer"
                 },
@@ -6250,7 +12012,7 @@ const docTemplate = `{
                 "taxes": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/models.TaxType"
                     }
                 },
                 "total": {
@@ -6258,185 +12020,266 @@ const docTemplate = `{
                 }
             }
         },
-        "dtos.GetItemDTO": {
+        "dtos.SchedulePriceChangeDTO": {
             "type": "object",
+            "required": [
+                "effective_from",
+                "price"
+            ],
             "properties": {
-                "additional_expenses": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "effective_from": {
+                    "type": "string"
                 },
-                "description": {
+                "price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.ScheduledReportParametersDTO": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "orderBy": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "period": {
+                    "type": "string"
                 },
-                "item_state": {
-                    "type": "boolean"
+                "range": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SellerRevenueDTO": {
+            "type": "object",
+            "properties": {
+                "discount_total": {
+                    "type": "number"
                 },
-                "item_type_id": {
+                "invoice_count": {
                     "type": "integer"
                 },
-                "name": {
+                "seller_id": {
+                    "type": "integer"
+                },
+                "seller_name": {
                     "type": "string"
                 },
-                "purchase_price": {
+                "subtotal": {
                     "type": "number"
                 },
-                "selling_price": {
+                "tax_total": {
                     "type": "number"
                 },
-                "stock": {
-                    "type": "integer"
+                "total": {
+                    "type": "number"
                 }
             }
         },
-        "dtos.GetPurchaseOrderDTO": {
+        "dtos.SessionDTO": {
             "type": "object",
             "properties": {
-                "customer_id": {
-                    "description": "Cambiado a puntero",
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "date_time": {
+                "current": {
+                    "description": "Current indica si es la sesión con la que se hizo la petición",
+                    "type": "boolean"
+                },
+                "device": {
                     "type": "string"
                 },
-                "discounts": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "expires_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "items": {
+                "ip": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SetRoleScopesDTO": {
+            "type": "object",
+            "required": [
+                "scopes"
+            ],
+            "properties": {
+                "scopes": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dtos.BillingItemDTO"
+                        "$ref": "#/definitions/dtos.RoleScopeDTO"
                     }
-                },
-                "order_state_id": {
+                }
+            }
+        },
+        "dtos.TaxSummaryDTO": {
+            "type": "object",
+            "properties": {
+                "invoice_count": {
                     "type": "integer"
                 },
-                "responsible_id": {
-                    "description": "Cambiado a puntero",
-                    "type": "integer"
+                "tax_total": {
+                    "type": "number"
                 },
-                "seller_id": {
-                    "description": "Cambiado a puntero",
+                "tax_type_id": {
                     "type": "integer"
                 },
-                "sub_total": {
+                "tax_type_name": {
+                    "type": "string"
+                },
+                "taxable_amount": {
                     "type": "number"
+                }
+            }
+        },
+        "dtos.TopItemDTO": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "integer"
                 },
-                "taxes": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "item_name": {
+                    "type": "string"
                 },
-                "total": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "revenue": {
                     "type": "number"
                 }
             }
         },
-        "dtos.GetUserDTO": {
+        "dtos.TwoFactorCodeDTO": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.TwoFactorConfirmDTO": {
             "type": "object",
+            "required": [
+                "code",
+                "email",
+                "password"
+            ],
             "properties": {
+                "code": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.TwoFactorDisableDTO": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.TwoFactorEnrollDTO": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
                 },
                 "password": {
                     "type": "string"
-                },
-                "user_state": {
-                    "type": "integer"
-                },
-                "user_type": {
-                    "type": "integer"
                 }
             }
         },
-        "dtos.RoleDTO": {
+        "dtos.TwoFactorEnrollmentDTO": {
             "type": "object",
             "properties": {
-                "description": {
+                "provisioning_uri": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
+                "secret": {
                     "type": "string"
-                },
-                "permissions": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
                 }
             }
         },
-        "dtos.SalesReportInvoiceDTO": {
+        "dtos.TwoFactorStatusDTO": {
             "type": "object",
             "properties": {
-                "date_time": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "discounts": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.DiscountType"
-                    }
+                "pending": {
+                    "type": "boolean"
                 },
-                "id": {
+                "recovery_codes_left": {
                     "type": "integer"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dtos.BillingItemDTO"
-                    }
-                },
-                "subtotal": {
-                    "type": "number"
-                },
-                "taxes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.TaxType"
-                    }
-                },
-                "total": {
-                    "type": "number"
+                "required": {
+                    "type": "boolean"
                 }
             }
         },
         "dtos.UpdateAdditionalExpenseDTO": {
             "type": "object",
+            "required": [
+                "item_id",
+                "name"
+            ],
             "properties": {
                 "description": {
                     "type": "string"
                 },
                 "expense": {
-                    "type": "number"
+                    "type": "number",
+                    "minimum": 0
                 },
                 "item_id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
+                },
+                "units": {
+                    "type": "integer"
                 }
             }
         },
         "dtos.UpdateCommentDTO": {
             "type": "object",
+            "required": [
+                "email",
+                "last_name",
+                "name"
+            ],
             "properties": {
                 "comment": {
                     "type": "string"
@@ -6463,6 +12306,13 @@ const docTemplate = `{
         },
         "dtos.UpdateCustomerDTO": {
             "type": "object",
+            "required": [
+                "customerId",
+                "customerName",
+                "email",
+                "identifierTypeId",
+                "lastName"
+            ],
             "properties": {
                 "address": {
                     "type": "string"
@@ -6495,6 +12345,13 @@ const docTemplate = `{
         },
         "dtos.UpdateEmployeeDTO": {
             "type": "object",
+            "required": [
+                "identifier_type_id",
+                "last_names",
+                "names",
+                "personal_id",
+                "user_id"
+            ],
             "properties": {
                 "address": {
                     "type": "string"
@@ -6521,6 +12378,10 @@ const docTemplate = `{
         },
         "dtos.UpdateItemDTO": {
             "type": "object",
+            "required": [
+                "item_type_id",
+                "name"
+            ],
             "properties": {
                 "description": {
                     "type": "string"
@@ -6535,25 +12396,41 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "purchase_price": {
-                    "type": "number"
+                    "type": "number",
+                    "minimum": 0
                 },
                 "selling_price": {
-                    "type": "number"
+                    "type": "number",
+                    "minimum": 0
                 },
                 "stock": {
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.UpdatePasswordLoginDTO": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
                 }
             }
         },
         "dtos.UpdateUserDTO": {
             "type": "object",
+            "required": [
+                "email",
+                "user_state",
+                "user_type"
+            ],
             "properties": {
                 "email": {
                     "type": "string"
                 },
-                "password": {
-                    "type": "string"
-                },
                 "user_state": {
                     "type": "integer"
                 },
@@ -6562,6 +12439,17 @@ const docTemplate = `{
                 }
             }
         },
+        "dtos.UpdateUserTypeTwoFactorDTO": {
+            "type": "object",
+            "required": [
+                "required"
+            ],
+            "properties": {
+                "required": {
+                    "type": "boolean"
+                }
+            }
+        },
         "dtos.UserTypeDTO": {
             "type": "object",
             "properties": {
@@ -6574,6 +12462,9 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "require_two_factor": {
+                    "type": "boolean"
+                },
                 "roles": {
                     "type": "array",
                     "items": {
@@ -6599,11 +12490,23 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "units": {
+                    "description": "Units es la cantidad de unidades del ítem que cubre el gasto. Si es 0, el gasto\nse reparte entre las unidades en stock y las ya facturadas del ítem.",
+                    "type": "integer"
                 }
             }
         },
         "models.Appointment": {
             "type": "object",
+            "required": [
+                "customerId",
+                "customerName",
+                "dateTime",
+                "email",
+                "identifierTypeId",
+                "lastName"
+            ],
             "properties": {
                 "address": {
                     "type": "string"
@@ -6682,34 +12585,69 @@ const docTemplate = `{
         "models.DiscountType": {
             "type": "object",
             "properties": {
+                "auto_apply": {
+                    "type": "boolean"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "coupon_code": {
+                    "description": "Condiciones de la promoción. Todas son opcionales: un descuento sin\ncondiciones se comporta como antes y se aplica siempre que se solicite.",
+                    "type": "string"
+                },
                 "description": {
                     "type": "string"
                 },
+                "get_quantity": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "is_percentage": {
                     "type": "boolean"
                 },
+                "item_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ItemType"
+                    }
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Item"
+                    }
+                },
+                "max_uses_per_customer": {
+                    "type": "integer"
+                },
+                "min_purchase_amount": {
+                    "type": "number"
+                },
                 "name": {
                     "type": "string"
                 },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                },
                 "value": {
                     "type": "number"
                 }
             }
         },
-        "models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
-                }
-            }
-        },
         "models.HistoricalItemPrice": {
             "type": "object",
             "properties": {
+                "effective_from": {
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -6735,6 +12673,41 @@ const docTemplate = `{
                 }
             }
         },
+        "models.Item": {
+            "type": "object",
+            "properties": {
+                "additional_expenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AdditionalExpense"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_state": {
+                    "type": "boolean"
+                },
+                "item_type": {
+                    "$ref": "#/definitions/models.ItemType"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "purchase_price": {
+                    "type": "number"
+                },
+                "selling_price": {
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.ItemType": {
             "type": "object",
             "properties": {
@@ -6779,6 +12752,78 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ProblemDetails": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "message_key": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "RequestID permite buscar en los logs el error de una respuesta concreta.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportRun": {
+            "type": "object",
+            "properties": {
+                "artifact_name": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "delivered_to": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "range_from": {
+                    "type": "string"
+                },
+                "range_to": {
+                    "type": "string"
+                },
+                "scheduled_report_id": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Role": {
             "type": "object",
             "properties": {
@@ -6796,11 +12841,61 @@ const docTemplate = `{
                     "items": {
                         "$ref": "#/definitions/models.Permission"
                     }
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RoleScope"
+                    }
+                }
+            }
+        },
+        "models.RoleScope": {
+            "type": "object",
+            "properties": {
+                "resource": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SecurityEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor es quien provocó el evento cuando no es el propio usuario, por\nejemplo el administrador que desbloquea una cuenta",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
         "models.TaxType": {
             "type": "object",
+            "required": [
+                "name"
+            ],
             "properties": {
                 "description": {
                     "type": "string"
@@ -6815,7 +12910,8 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "value": {
-                    "type": "number"
+                    "type": "number",
+                    "minimum": 0
                 }
             }
         },
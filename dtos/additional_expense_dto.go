@@ -1,8 +1,9 @@
 package dtos
 
 type UpdateAdditionalExpenseDTO struct {
-	Name        string  `json:"name"`
-	ItemID      int     `json:"item_id"`
-	Expense     float64 `json:"expense"`
+	Name        string  `json:"name" binding:"required"`
+	ItemID      int     `json:"item_id" binding:"required,exists=items"`
+	Expense     float64 `json:"expense" binding:"gte=0"`
 	Description string  `json:"description,omitempty"`
+	Units       int     `json:"units" binding:"gt=0"`
 }
@@ -0,0 +1,43 @@
+package dtos
+
+import "time"
+
+// CreateAPIKeyDTO crea una llave para un cliente de máquina. Con
+// ReporterTaxID la llave es de un revendedor: Principal es su nombre y las
+// ventas externas que reporte quedan a nombre de ambos.
+type CreateAPIKeyDTO struct {
+	Name          string  `json:"name" binding:"required,max=100"`
+	Principal     string  `json:"principal" binding:"required,max=255"`
+	ReporterTaxID *string `json:"reporter_tax_id" binding:"omitempty,min=1,max=100"`
+	Permissions   []int   `json:"permissions" binding:"required,min=1,dive,exists=permissions"`
+	// ExpiresAt es opcional; sin él la llave vence después de la vigencia
+	// configurada
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeyDTO no lleva la llave: sólo se muestra al crearla o rotarla.
+type APIKeyDTO struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	Prefix        string    `json:"prefix"`
+	Principal     string    `json:"principal"`
+	ReporterTaxID *string   `json:"reporter_tax_id,omitempty"`
+	Permissions   []int     `json:"permissions"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	// Active indica si la llave sirve ahora; una llave rotada sirve hasta el
+	// fin del período de gracia
+	Active       bool       `json:"active"`
+	LastUsedAt   *time.Time `json:"last_used_at"`
+	LastUsedIP   string     `json:"last_used_ip,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	ReplacedByID *int       `json:"replaced_by_id,omitempty"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IssuedAPIKeyDTO es la respuesta al crear o rotar una llave. Key no se
+// guarda y no se puede volver a consultar.
+type IssuedAPIKeyDTO struct {
+	Key    string    `json:"key"`
+	APIKey APIKeyDTO `json:"api_key"`
+}
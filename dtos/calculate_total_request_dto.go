@@ -1,7 +1,37 @@
 package dtos
 
+import "time"
+
 type CalculateTotalRequestDTO struct {
-	DiscountTypesIds []int            `json:"discountTypesIds"`
-	TaxTypesIds      []int            `json:"taxTypesIds"`
-	ItemsDTO         []BillingItemDTO `json:"itemsDTO"`
+	DiscountTypesIds []int            `json:"discountTypesIds" binding:"dive,exists=discount_types"`
+	TaxTypesIds      []int            `json:"taxTypesIds" binding:"dive,exists=tax_types"`
+	ItemsDTO         []BillingItemDTO `json:"itemsDTO" binding:"required,min=1,dive"`
+	CustomerID       *int             `json:"customerId,omitempty" binding:"omitempty,exists=customers"`
+	CouponCodes      []string         `json:"couponCodes"`
+	PriceDate        *time.Time       `json:"priceDate,omitempty"`
+}
+
+type AppliedDiscountDTO struct {
+	DiscountTypeID int     `json:"discount_type_id"`
+	Name           string  `json:"name"`
+	Amount         float64 `json:"amount"`
+	Automatic      bool    `json:"automatic"`
+}
+
+type RejectedDiscountDTO struct {
+	DiscountTypeID int    `json:"discount_type_id,omitempty"`
+	CouponCode     string `json:"coupon_code,omitempty"`
+	Reason         string `json:"reason"`
+	Message        string `json:"message"`
+}
+
+type BillingBreakdownDTO struct {
+	Items             []BillingItemDTO      `json:"items"`
+	Subtotal          float64               `json:"subtotal"`
+	DiscountTotal     float64               `json:"discount_total"`
+	TaxTotal          float64               `json:"tax_total"`
+	Total             float64               `json:"total"`
+	AppliedDiscounts  []AppliedDiscountDTO  `json:"applied_discounts"`
+	RejectedDiscounts []RejectedDiscountDTO `json:"rejected_discounts"`
+	Warnings          []BelowCostWarningDTO `json:"warnings,omitempty"`
 }
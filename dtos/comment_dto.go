@@ -12,9 +12,9 @@ type GetCommentDTO struct {
 }
 
 type UpdateCommentDTO struct {
-	Name           string `json:"name"`
-	LastName       string `json:"last_name"`
-	Email          string `json:"email"`
+	Name           string `json:"name" binding:"required"`
+	LastName       string `json:"last_name" binding:"required"`
+	Email          string `json:"email" binding:"required,email"`
 	Phone          string `json:"phone,omitempty"`
 	ResidenceState string `json:"residence_state,omitempty"`
 	ResidenceCity  string `json:"residence_city,omitempty"`
@@ -22,9 +22,9 @@ type UpdateCommentDTO struct {
 }
 
 type CreateCommentDTO struct {
-	Name           string `json:"name"`
-	LastName       string `json:"last_name"`
-	Email          string `json:"email"`
+	Name           string `json:"name" binding:"required"`
+	LastName       string `json:"last_name" binding:"required"`
+	Email          string `json:"email" binding:"required,email"`
 	Phone          string `json:"phone,omitempty"`
 	ResidenceState string `json:"residence_state,omitempty"`
 	ResidenceCity  string `json:"residence_city,omitempty"`
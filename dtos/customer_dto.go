@@ -1,14 +1,16 @@
 package dtos
 
+// GetCustomerDTO omite la dirección y enmascara el teléfono y el correo a
+// quien no tiene PERMISSION_VIEW_CUSTOMER_CONTACT.
 type GetCustomerDTO struct {
 	ID               int    `json:"id"`
 	CustomerName     string `json:"customerName"`
 	CustomerId       string `json:"customerId"`
 	IsBusiness       bool   `json:"isBusiness"`
-	Address          string `json:"address,omitempty"`
-	PhoneNumbers     string `json:"phoneNumbers,omitempty"`
+	Address          string `json:"address,omitempty" visible:"customer_contact"`
+	PhoneNumbers     string `json:"phoneNumbers,omitempty" visible:"customer_contact,mask"`
 	CustomerState    bool   `json:"customerState"`
-	Email            string `json:"email"`
+	Email            string `json:"email" visible:"customer_contact,mask"`
 	LastName         string `json:"lastName"`
 	IdentifierTypeID int    `json:"identifierTypeId"`
 }
@@ -22,17 +24,17 @@ type CreateCustomerDTO struct {
 	CustomerState    bool   `json:"customerState"`
 	Email            string `json:"email" binding:"required,email"`
 	LastName         string `json:"lastName" binding:"required"`
-	IdentifierTypeID int    `json:"identifierTypeId" binding:"required"`
+	IdentifierTypeID int    `json:"identifierTypeId" binding:"required,exists=identifier_types"`
 }
 
 type UpdateCustomerDTO struct {
-	CustomerName     string `json:"customerName"`
-	CustomerId       string `json:"customerId"`
+	CustomerName     string `json:"customerName" binding:"required"`
+	CustomerId       string `json:"customerId" binding:"required"`
 	IsBusiness       bool   `json:"isBusiness"`
 	Address          string `json:"address,omitempty"`
 	PhoneNumbers     string `json:"phoneNumbers,omitempty"`
 	CustomerState    bool   `json:"customerState"`
-	Email            string `json:"email"`
-	LastName         string `json:"lastName"`
-	IdentifierTypeID int    `json:"identifierTypeId"`
+	Email            string `json:"email" binding:"required,email"`
+	LastName         string `json:"lastName" binding:"required"`
+	IdentifierTypeID int    `json:"identifierTypeId" binding:"required,exists=identifier_types"`
 }
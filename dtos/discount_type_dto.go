@@ -0,0 +1,20 @@
+package dtos
+
+import "time"
+
+type CreateDiscountTypeDTO struct {
+	Name               string     `json:"name" binding:"required"`
+	Description        string     `json:"description,omitempty"`
+	IsPercentage       bool       `json:"is_percentage"`
+	Value              float64    `json:"value" binding:"gte=0"`
+	CouponCode         *string    `json:"coupon_code,omitempty"`
+	AutoApply          bool       `json:"auto_apply"`
+	ValidFrom          *time.Time `json:"valid_from,omitempty"`
+	ValidTo            *time.Time `json:"valid_to,omitempty"`
+	MinPurchaseAmount  float64    `json:"min_purchase_amount" binding:"gte=0"`
+	MaxUsesPerCustomer int        `json:"max_uses_per_customer" binding:"gte=0"`
+	BuyQuantity        int        `json:"buy_quantity" binding:"gte=0"`
+	GetQuantity        int        `json:"get_quantity" binding:"gte=0"`
+	ItemIDs            []int      `json:"item_ids" binding:"dive,exists=items"`
+	ItemTypeIDs        []int      `json:"item_type_ids" binding:"dive,exists=item_types"`
+}
@@ -12,21 +12,21 @@ type GetEmployeeDTO struct {
 }
 
 type UpdateEmployeeDTO struct {
-	Names            string `json:"names"`
-	LastNames        string `json:"last_names"`
-	PersonalID       string `json:"personal_id"`
+	Names            string `json:"names" binding:"required"`
+	LastNames        string `json:"last_names" binding:"required"`
+	PersonalID       string `json:"personal_id" binding:"required"`
 	Address          string `json:"address,omitempty"`
 	PhoneNumbers     string `json:"phone_numbers,omitempty"`
-	UserID           int    `json:"user_id"`
-	IdentifierTypeID int    `json:"identifier_type_id"`
+	UserID           int    `json:"user_id" binding:"required,exists=users"`
+	IdentifierTypeID int    `json:"identifier_type_id" binding:"required,exists=identifier_types"`
 }
 
 type CreateEmployeeDTO struct {
-	Names            string `json:"names"`
-	LastNames        string `json:"last_names"`
-	PersonalID       string `json:"personal_id"`
+	Names            string `json:"names" binding:"required"`
+	LastNames        string `json:"last_names" binding:"required"`
+	PersonalID       string `json:"personal_id" binding:"required"`
 	Address          string `json:"address,omitempty"`
 	PhoneNumbers     string `json:"phone_numbers,omitempty"`
-	UserID           int    `json:"user_id"`
-	IdentifierTypeID int    `json:"identifier_type_id"`
+	UserID           int    `json:"user_id" binding:"required,exists=users"`
+	IdentifierTypeID int    `json:"identifier_type_id" binding:"required,exists=identifier_types"`
 }
@@ -8,14 +8,17 @@ type GetExternalSaleDTO struct {
 	ItemID        int    `json:"item_id"`
 	ItemName      string `json:"item_name"`
 	CustomerID    int    `json:"customer_id"`
-	CustomerEmail string `json:"customer_email"`
+	CustomerEmail string `json:"customer_email" visible:"customer_contact,mask"`
+	APIKeyID      *int   `json:"api_key_id,omitempty"`
 }
 
+// CreateExternalSaleDTO lleva el reportante sólo cuando la venta no se reporta
+// con una llave de revendedor; con ella reporter_name y reporter_id se ignoran.
 type CreateExternalSaleDTO struct {
-	ReporterName     string `json:"reporter_name" binding:"required"`
-	ReporterID       string `json:"reporter_id" binding:"required"`
-	Stock            int    `gorm:"not null" json:"stock"`
-	ItemID           int    `json:"item_id" binding:"required"`
+	ReporterName     string `json:"reporter_name"`
+	ReporterID       string `json:"reporter_id"`
+	Stock            int    `gorm:"not null" json:"stock" binding:"gt=0"`
+	ItemID           int    `json:"item_id" binding:"required,exists=items"`
 	CustomerName     string `json:"customerName" binding:"required"`
 	CustomerID       string `json:"customerId" binding:"required"`
 	IsBusiness       bool   `json:"isBusiness"`
@@ -23,5 +26,5 @@ type CreateExternalSaleDTO struct {
 	PhoneNumbers     string `json:"phoneNumbers,omitempty"`
 	Email            string `json:"email" binding:"required,email"`
 	LastName         string `json:"lastName" binding:"required"`
-	IdentifierTypeID int    `json:"identifierTypeId" binding:"required"`
+	IdentifierTypeID int    `json:"identifierTypeId" binding:"required,exists=identifier_types"`
 }
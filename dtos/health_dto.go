@@ -0,0 +1,36 @@
+package dtos
+
+// ReadinessDTO es la respuesta de /readyz. Status es "ready" solo si todos los
+// chequeos pasan; si no, es "not_ready" o "shutting_down".
+type ReadinessDTO struct {
+	Status     string             `json:"status"`
+	Database   DatabaseCheckDTO   `json:"database"`
+	Migrations MigrationsCheckDTO `json:"migrations"`
+	Pool       PoolCheckDTO       `json:"pool"`
+}
+
+type DatabaseCheckDTO struct {
+	OK        bool   `json:"ok"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// MigrationsCheckDTO compara la última migración aplicada con la última que
+// trae el binario.
+type MigrationsCheckDTO struct {
+	OK              bool   `json:"ok"`
+	CurrentVersion  int    `json:"current_version"`
+	ExpectedVersion int    `json:"expected_version"`
+	Error           string `json:"error,omitempty"`
+}
+
+// PoolCheckDTO falla cuando todas las conexiones del pool están en uso.
+type PoolCheckDTO struct {
+	OK             bool  `json:"ok"`
+	MaxOpen        int   `json:"max_open"`
+	Open           int   `json:"open"`
+	InUse          int   `json:"in_use"`
+	Idle           int   `json:"idle"`
+	WaitCount      int64 `json:"wait_count"`
+	WaitDurationMs int64 `json:"wait_duration_ms"`
+}
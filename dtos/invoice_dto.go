@@ -7,7 +7,7 @@ import (
 
 type GetInvoiceDTO struct {
 	ID             int              `json:"id"`
-	EnterpriseData string           `json:"enterprise_data"`
+	EnterpriseData string           `json:"enterprise_data" visible:"invoice_enterprise_data"`
 	DateTime       time.Time        `json:"date_time"`
 	CustomerID     int              `json:"customer_id"`
 	Total          float64          `json:"total"`
@@ -15,6 +15,8 @@ type GetInvoiceDTO struct {
 	Items          []BillingItemDTO `json:"items"`
 	Discounts      []int            `json:"discounts"`
 	Taxes          []int            `json:"taxes"`
+	// Warnings solo se informa al crear la factura.
+	Warnings []BelowCostWarningDTO `json:"warnings,omitempty"`
 }
 
 type SalesReportInvoiceDTO struct {
@@ -28,9 +30,12 @@ type SalesReportInvoiceDTO struct {
 }
 
 type CreateInvoiceDTO struct {
-	EnterpriseData string           `json:"enterprise_data"`
-	CustomerID     int              `json:"customer_id"`
-	Items          []BillingItemDTO `json:"items"`
-	Discounts      []int            `json:"discounts"`
-	Taxes          []int            `json:"taxes"`
+	EnterpriseData string           `json:"enterprise_data" binding:"required"`
+	CustomerID     int              `json:"customer_id" binding:"required,exists=customers"`
+	Items          []BillingItemDTO `json:"items" binding:"required,min=1,dive"`
+	Discounts      []int            `json:"discounts" binding:"dive,exists=discount_types"`
+	CouponCodes    []string         `json:"coupon_codes"`
+	Taxes          []int            `json:"taxes" binding:"dive,exists=tax_types"`
+	// PurchaseOrderID solo se asigna al facturar una orden de compra aprobada.
+	PurchaseOrderID *int `json:"-"`
 }
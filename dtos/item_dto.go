@@ -1,28 +1,39 @@
 package dtos
 
+import "time"
+
+// GetItemDTO omite el precio de compra y los gastos adicionales a quien no
+// tiene PERMISSION_VIEW_ITEM_COSTS.
 type GetItemDTO struct {
 	ID                 int     `json:"id"`
 	Name               string  `json:"name"`
 	Description        string  `json:"description,omitempty"`
 	Stock              int     `json:"stock"`
 	SellingPrice       float64 `json:"selling_price"`
-	PurchasePrice      float64 `json:"purchase_price"`
+	PurchasePrice      float64 `json:"purchase_price" visible:"item_costs"`
 	ItemState          bool    `json:"item_state"`
 	ItemTypeID         int     `json:"item_type_id"`
-	AdditionalExpenses []int   `json:"additional_expenses"`
+	AdditionalExpenses []int   `json:"additional_expenses" visible:"item_costs"`
 }
 
 type UpdateItemDTO struct {
-	Name          string  `json:"name"`
+	Name          string  `json:"name" binding:"required"`
 	Description   string  `json:"description,omitempty"`
-	Stock         int     `json:"stock"`
-	SellingPrice  float64 `json:"selling_price"`
-	PurchasePrice float64 `json:"purchase_price"`
+	Stock         int     `json:"stock" binding:"gte=0"`
+	SellingPrice  float64 `json:"selling_price" binding:"gte=0,pricefloor=PurchasePrice"`
+	PurchasePrice float64 `json:"purchase_price" binding:"gte=0"`
 	ItemState     bool    `json:"item_state"`
-	ItemTypeID    int     `json:"item_type_id"`
+	ItemTypeID    int     `json:"item_type_id" binding:"required,exists=item_types"`
 }
 
 type BillingItemDTO struct {
-	ID    int `json:"id"`
-	Stock int `json:"stock"`
+	ID          int     `json:"id" binding:"required,exists=items"`
+	Stock       int     `json:"stock" binding:"gt=0"`
+	UnitPrice   float64 `json:"unit_price,omitempty" binding:"gte=0"`
+	PriceListID *int    `json:"price_list_id,omitempty" binding:"omitempty,exists=price_lists"`
+}
+
+type SchedulePriceChangeDTO struct {
+	Price         float64   `json:"price" binding:"required,gt=0"`
+	EffectiveFrom time.Time `json:"effective_from" binding:"required"`
 }
@@ -0,0 +1,64 @@
+package dtos
+
+import (
+	"net/http"
+	"totesbackend/apperrors"
+)
+
+// ErrInvalidListQuery indica que los parámetros de paginación, orden o filtro
+// de un listado no son válidos.
+var ErrInvalidListQuery = apperrors.New("list.invalid_query", http.StatusBadRequest, "invalid list query")
+
+// Operadores admitidos en los filtros de los listados.
+const (
+	FilterEq   = "eq"
+	FilterNe   = "ne"
+	FilterGt   = "gt"
+	FilterGte  = "gte"
+	FilterLt   = "lt"
+	FilterLte  = "lte"
+	FilterLike = "like"
+	FilterIn   = "in"
+)
+
+// ListQuery describe la página pedida de un listado. Page y Cursor son
+// excluyentes: Page pagina por desplazamiento y Cursor continúa después de la
+// última fila de la página anterior.
+type ListQuery struct {
+	Limit   int
+	Page    int
+	Cursor  string
+	Sort    []SortField
+	Filters []ListFilter
+}
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+type ListFilter struct {
+	Field    string
+	Operator string
+	Value    string
+}
+
+// PageInfo acompaña a las filas de una página con el total de filas que
+// cumplen los filtros y el cursor de la página siguiente.
+type PageInfo struct {
+	Total      int64
+	Limit      int
+	NextCursor string
+}
+
+// PageDTO es el sobre común de las respuestas de los listados.
+type PageDTO struct {
+	Data       interface{} `json:"data"`
+	Total      int64       `json:"total"`
+	Limit      int         `json:"limit"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+func NewPageDTO(data interface{}, page *PageInfo) PageDTO {
+	return PageDTO{Data: data, Total: page.Total, Limit: page.Limit, NextCursor: page.NextCursor}
+}
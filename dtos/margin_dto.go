@@ -0,0 +1,44 @@
+package dtos
+
+type ApportionedExpenseDTO struct {
+	ExpenseID int     `json:"expense_id"`
+	Name      string  `json:"name"`
+	Expense   float64 `json:"expense"`
+	Units     int     `json:"units"`
+	PerUnit   float64 `json:"per_unit"`
+}
+
+type LandedCostDTO struct {
+	ItemID        int                     `json:"item_id"`
+	ItemName      string                  `json:"item_name"`
+	PurchasePrice float64                 `json:"purchase_price"`
+	Expenses      []ApportionedExpenseDTO `json:"expenses"`
+	LandedCost    float64                 `json:"landed_cost"`
+	SellingPrice  float64                 `json:"selling_price"`
+}
+
+// MarginRowDTO es una fila del reporte de márgenes. Según la agrupación se
+// informa el ítem, el tipo de ítem o el período.
+type MarginRowDTO struct {
+	ItemID        int     `json:"item_id,omitempty"`
+	ItemName      string  `json:"item_name,omitempty"`
+	ItemTypeID    int     `json:"item_type_id,omitempty"`
+	ItemTypeName  string  `json:"item_type_name,omitempty"`
+	Period        string  `json:"period,omitempty"`
+	Quantity      int     `json:"quantity"`
+	Revenue       float64 `json:"revenue"`
+	Cost          float64 `json:"cost"`
+	Margin        float64 `json:"margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+// BelowCostWarningDTO advierte de un ítem vendido por debajo de su costo. El
+// costo sólo lo ve quien puede ver los costos de los ítems, y por eso no se
+// repite en el mensaje.
+type BelowCostWarningDTO struct {
+	ItemID     int     `json:"item_id"`
+	ItemName   string  `json:"item_name"`
+	UnitPrice  float64 `json:"unit_price"`
+	LandedCost float64 `json:"landed_cost" visible:"item_costs"`
+	Message    string  `json:"message"`
+}
@@ -0,0 +1,34 @@
+package dtos
+
+type PriceListItemDTO struct {
+	ItemID int     `json:"item_id" binding:"required,exists=items"`
+	Price  float64 `json:"price" binding:"gte=0"`
+}
+
+type PriceListRuleDTO struct {
+	ItemTypeID *int    `json:"item_type_id,omitempty" binding:"omitempty,exists=item_types"`
+	Percentage float64 `json:"percentage"`
+}
+
+type GetPriceListDTO struct {
+	ID          int                `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Segment     string             `json:"segment,omitempty"`
+	Priority    int                `json:"priority"`
+	Active      bool               `json:"active"`
+	Items       []PriceListItemDTO `json:"items"`
+	Rules       []PriceListRuleDTO `json:"rules"`
+	CustomerIDs []int              `json:"customer_ids"`
+}
+
+type CreatePriceListDTO struct {
+	Name        string             `json:"name" binding:"required"`
+	Description string             `json:"description,omitempty"`
+	Segment     string             `json:"segment,omitempty"`
+	Priority    int                `json:"priority"`
+	Active      *bool              `json:"active,omitempty"`
+	Items       []PriceListItemDTO `json:"items" binding:"dive"`
+	Rules       []PriceListRuleDTO `json:"rules" binding:"dive"`
+	CustomerIDs []int              `json:"customer_ids" binding:"dive,exists=customers"`
+}
@@ -17,7 +17,7 @@ type GetPurchaseOrderDTO struct {
 }
 
 type CreatePurchaseOrderDTO struct {
-	Items []BillingItemDTO `json:"items"`
+	Items []BillingItemDTO `json:"items" binding:"required,min=1,dive"`
 }
 
 type UpdatePurchaseOrderDTO struct {
@@ -1,8 +1,22 @@
 package dtos
 
 type RoleDTO struct {
-	ID          uint     `json:"id"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Permissions []string `json:"permissions"`
+	ID          uint           `json:"id"`
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Permissions []string       `json:"permissions"`
+	Scopes      []RoleScopeDTO `json:"scopes"`
+}
+
+// RoleScopeDTO es una regla de alcance de datos del rol. Resource es
+// purchase_orders, customers o appointments; la única regla es own.
+type RoleScopeDTO struct {
+	Resource string `json:"resource" binding:"required,oneof=purchase_orders customers appointments"`
+	Rule     string `json:"rule" binding:"required,oneof=own"`
+}
+
+// SetRoleScopesDTO reemplaza todas las reglas de alcance del rol; una lista
+// vacía las quita.
+type SetRoleScopesDTO struct {
+	Scopes []RoleScopeDTO `json:"scopes" binding:"required,dive"`
 }
@@ -0,0 +1,59 @@
+package dtos
+
+import "time"
+
+// RevenueByPeriodDTO resume lo facturado en un período y lo compara con el período anterior.
+type RevenueByPeriodDTO struct {
+	Period             time.Time `json:"period"`
+	InvoiceCount       int       `json:"invoice_count"`
+	Subtotal           float64   `json:"subtotal"`
+	DiscountTotal      float64   `json:"discount_total"`
+	TaxTotal           float64   `json:"tax_total"`
+	Total              float64   `json:"total"`
+	PreviousTotal      float64   `json:"previous_total"`
+	TotalChange        float64   `json:"total_change"`
+	TotalChangePercent *float64  `json:"total_change_percent"`
+}
+
+type TopItemDTO struct {
+	ItemID   int     `json:"item_id"`
+	ItemName string  `json:"item_name"`
+	Quantity int     `json:"quantity"`
+	Revenue  float64 `json:"revenue"`
+}
+
+type ItemTypeRevenueDTO struct {
+	ItemTypeID   int     `json:"item_type_id"`
+	ItemTypeName string  `json:"item_type_name"`
+	Quantity     int     `json:"quantity"`
+	Revenue      float64 `json:"revenue"`
+}
+
+type CustomerRevenueDTO struct {
+	CustomerID    int     `json:"customer_id"`
+	CustomerName  string  `json:"customer_name"`
+	InvoiceCount  int     `json:"invoice_count"`
+	Subtotal      float64 `json:"subtotal"`
+	DiscountTotal float64 `json:"discount_total"`
+	TaxTotal      float64 `json:"tax_total"`
+	Total         float64 `json:"total"`
+}
+
+type SellerRevenueDTO struct {
+	SellerID      int     `json:"seller_id"`
+	SellerName    string  `json:"seller_name"`
+	InvoiceCount  int     `json:"invoice_count"`
+	Subtotal      float64 `json:"subtotal"`
+	DiscountTotal float64 `json:"discount_total"`
+	TaxTotal      float64 `json:"tax_total"`
+	Total         float64 `json:"total"`
+}
+
+// TaxSummaryDTO resume lo recaudado por un tipo de impuesto.
+type TaxSummaryDTO struct {
+	TaxTypeID     int     `json:"tax_type_id"`
+	TaxTypeName   string  `json:"tax_type_name"`
+	InvoiceCount  int     `json:"invoice_count"`
+	TaxableAmount float64 `json:"taxable_amount"`
+	TaxTotal      float64 `json:"tax_total"`
+}
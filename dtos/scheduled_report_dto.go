@@ -0,0 +1,36 @@
+package dtos
+
+import "time"
+
+// ScheduledReportParametersDTO son los parámetros de un reporte programado.
+// Range indica el rango de fechas relativo al momento de la ejecución.
+type ScheduledReportParametersDTO struct {
+	Range   string `json:"range,omitempty"`
+	Period  string `json:"period,omitempty"`
+	OrderBy string `json:"orderBy,omitempty"`
+	Limit   int    `json:"limit,omitempty" binding:"gte=0"`
+}
+
+type GetScheduledReportDTO struct {
+	ID         int                          `json:"id"`
+	Name       string                       `json:"name"`
+	Report     string                       `json:"report"`
+	Parameters ScheduledReportParametersDTO `json:"parameters"`
+	Cron       string                       `json:"cron"`
+	Format     string                       `json:"format"`
+	Recipients []string                     `json:"recipients"`
+	Active     bool                         `json:"active"`
+	LastRunAt  *time.Time                   `json:"last_run_at,omitempty"`
+	NextRunAt  *time.Time                   `json:"next_run_at,omitempty"`
+	CreatedAt  time.Time                    `json:"created_at"`
+}
+
+type CreateScheduledReportDTO struct {
+	Name       string                       `json:"name" binding:"required"`
+	Report     string                       `json:"report" binding:"required"`
+	Parameters ScheduledReportParametersDTO `json:"parameters"`
+	Cron       string                       `json:"cron" binding:"required"`
+	Format     string                       `json:"format" binding:"required"`
+	Recipients []string                     `json:"recipients" binding:"required,min=1,dive,email"`
+	Active     *bool                        `json:"active,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dtos
+
+import "time"
+
+// LoginResultDTO es la respuesta a un inicio de sesión. Token se envía como
+// Authorization: Bearer <token> y no se puede volver a consultar.
+type LoginResultDTO struct {
+	Message   string    `json:"message"`
+	Token     string    `json:"token"`
+	SessionID int       `json:"session_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type SessionDTO struct {
+	ID         int       `json:"id"`
+	Method     string    `json:"method"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current indica si es la sesión con la que se hizo la petición
+	Current bool `json:"current"`
+}
@@ -1,23 +1,132 @@
 package dtos
 
+import "time"
+
 type GetUserDTO struct {
 	ID          int    `json:"id"`
 	Email       string `json:"email"`
-	Password    string `json:"password"`
 	UserTypeID  int    `json:"user_type"`
 	UserStateID int    `json:"user_state"`
 }
 
+// UpdateUserDTO no incluye la contraseña: se cambia con /password/change o
+// con el restablecimiento por correo.
 type UpdateUserDTO struct {
-	Email       string `json:"email"`
-	Password    string `json:"password"`
-	UserTypeID  int    `json:"user_type"`
-	UserStateID int    `json:"user_state"`
+	Email       string `json:"email" binding:"required,email"`
+	UserTypeID  int    `json:"user_type" binding:"required,exists=user_types"`
+	UserStateID int    `json:"user_state" binding:"required,exists=user_state_types"`
 }
 
 type CreateUserDTO struct {
-	Email       string `json:"email"`
-	Password    string `json:"password"`
-	UserTypeID  int    `json:"user_type"`
-	UserStateID int    `json:"user_state"`
+	Email       string `json:"email" binding:"required,email"`
+	Password    string `json:"password" binding:"required"`
+	UserTypeID  int    `json:"user_type" binding:"required,exists=user_types"`
+	UserStateID int    `json:"user_state" binding:"required,exists=user_state_types"`
+}
+
+type ChangePasswordDTO struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+type ForgotPasswordDTO struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordDTO struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// TwoFactorEnrollDTO y TwoFactorConfirmDTO identifican al usuario con correo
+// y contraseña en lugar de una sesión, porque quien todavía no configuró un
+// segundo factor obligatorio no puede iniciar sesión.
+type TwoFactorEnrollDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+type TwoFactorConfirmDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// TwoFactorEnrollmentDTO lleva la semilla para escribirla a mano y la URI
+// otpauth:// que el cliente muestra como código QR.
+type TwoFactorEnrollmentDTO struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorCodeDTO struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorDisableDTO struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorStatusDTO struct {
+	Enabled           bool  `json:"enabled"`
+	Pending           bool  `json:"pending"`
+	Required          bool  `json:"required"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+type InviteUserDTO struct {
+	Email      string `json:"email" binding:"required,email"`
+	UserTypeID int    `json:"user_type" binding:"required,exists=user_types"`
+}
+
+// InvitationDTO no lleva el token: sólo lo recibe el invitado por correo.
+// SentAt es nil si el correo no se pudo enviar.
+type InvitationDTO struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Email      string     `json:"email"`
+	UserTypeID int        `json:"user_type"`
+	InvitedBy  string     `json:"invited_by"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Expired    bool       `json:"expired"`
+	SentAt     *time.Time `json:"sent_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type AcceptInvitationDTO struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// UpdatePasswordLoginDTO activa o desactiva el inicio de sesión con
+// contraseña; desactivado, el usuario sólo entra con el proveedor de identidad.
+type UpdatePasswordLoginDTO struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+type OIDCLoginDTO struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// OIDCCallbackDTO lleva los parámetros con los que el proveedor devolvió al
+// usuario a la página del cliente.
+type OIDCCallbackDTO struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+	// TwoFactorCode es el código TOTP o un código de recuperación; sólo para
+	// usuarios con segundo factor.
+	TwoFactorCode string `json:"two_factor_code,omitempty"`
+}
+
+type OIDCLoginResultDTO struct {
+	Message   string    `json:"message"`
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	SessionID int       `json:"session_id"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
@@ -1,8 +1,13 @@
 package dtos
 
 type UserTypeDTO struct {
-	ID          uint     `json:"id"`
-	Name        string   `json:"name"`
-	Description string   `json:"description"`
-	Roles       []string `json:"roles"`
+	ID               uint     `json:"id"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	RequireTwoFactor bool     `json:"require_two_factor"`
+	Roles            []string `json:"roles"`
+}
+
+type UpdateUserTypeTwoFactorDTO struct {
+	Required *bool `json:"required" binding:"required"`
 }
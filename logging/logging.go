@@ -0,0 +1,80 @@
+// Package logging configura el logger estructurado (slog) del servidor y
+// transporta en el contexto de la petición los datos que se agregan a cada
+// línea: el ID de la petición y el usuario.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"totesbackend/config"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	userKey
+)
+
+// Setup instala como logger por defecto un handler JSON (o texto) con el nivel
+// configurado. Los mensajes del paquete log también pasan por él.
+func Setup(cfg config.LogConfig, w io.Writer) {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(cfg.Level))
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if cfg.Format == "text" {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	slog.SetDefault(slog.New(contextHandler{handler}))
+}
+
+// WithRequestID devuelve un contexto que lleva el ID de la petición.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID devuelve el ID de la petición guardado en el contexto, o "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithUser devuelve un contexto que lleva el usuario que hace la petición.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// User devuelve el usuario guardado en el contexto, o "".
+func User(ctx context.Context) string {
+	user, _ := ctx.Value(userKey).(string)
+	return user
+}
+
+// contextHandler agrega request_id y user a los registros hechos con
+// slog.*Context cuando el contexto los tiene.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if user := User(ctx); user != "" {
+		record.AddAttrs(slog.String("user", user))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"log"
+	"os"
 	"totesbackend/app"
+	"totesbackend/cli"
 	_ "totesbackend/docs"
 )
 
@@ -21,6 +24,13 @@ import (
 
 // @schemes http https
 func main() {
+	// Con argumentos se ejecuta un subcomando de administración
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:]))
+	}
+
 	// Load environment variables and run the application
-	app.SetupAndRunApp()
+	if err := app.SetupAndRunApp(); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,96 @@
+// Package metrics define las métricas de Prometheus del servidor: HTTP, base de
+// datos y contadores de negocio. Se registran en un registro propio que expone
+// Handler, para no mezclarlas con las de otras librerías.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "totes"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of the queries run through gorm by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Queries run through gorm that failed, by operation and table. Record not found is not counted.",
+	}, []string{"operation", "table"})
+
+	InvoicesCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "invoices_created_total",
+		Help:      "Invoices created, by source (direct or purchase_order approval).",
+	}, []string{"source"})
+
+	OrderTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "purchase_order_transitions_total",
+		Help:      "Purchase order state changes by origin and target state ID.",
+	}, []string{"from", "to"})
+
+	StockOutRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "stock_out_rejections_total",
+		Help:      "Operations rejected for insufficient stock, by operation.",
+	}, []string{"operation"})
+
+	PermissionDenials = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "permission_denials_total",
+		Help:      "Requests rejected because the user lacks a permission, by permission ID.",
+	}, []string{"permission_id"})
+)
+
+// Valores de la etiqueta source de InvoicesCreated.
+const (
+	InvoiceSourceDirect        = "direct"
+	InvoiceSourcePurchaseOrder = "purchase_order"
+)
+
+// Valores de la etiqueta operation de StockOutRejections.
+const (
+	StockOperationInvoice       = "invoice"
+	StockOperationPurchaseOrder = "purchase_order"
+	StockOperationOrderTransit  = "order_in_transit"
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPRequestDuration,
+		DBQueryDuration, DBQueryErrors,
+		InvoicesCreated, OrderTransitions, StockOutRejections, PermissionDenials,
+	)
+}
+
+// Handler expone las métricas en el formato de texto de Prometheus.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"totesbackend/logging"
+	"totesbackend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader es la cabecera con la que los clientes de máquina envían su
+// llave.
+const APIKeyHeader = "X-API-Key"
+
+const apiKeyContextKey = "api_key"
+
+// APIKeyAuthenticator valida una llave recibida y devuelve la guardada.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey, ip string) (*models.APIKey, error)
+}
+
+// APIKey autentica las peticiones que traen la cabecera X-API-Key. Con una
+// llave válida la llave queda en el contexto: los registros de UserLog se
+// atribuyen a su identidad (ver Caller) y se revisan sus permisos en lugar de
+// los de un usuario. Debe ir después de ErrorHandler.
+func APIKey(auth APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(APIKeyHeader)
+		if rawKey == "" {
+			c.Next()
+			return
+		}
+
+		key, err := auth.Authenticate(c.Request.Context(), rawKey, c.ClientIP())
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(logging.WithUser(c.Request.Context(), key.Subject()))
+		c.Set(apiKeyContextKey, key)
+		c.Next()
+	}
+}
+
+// APIKeyFromContext devuelve la llave con la que se autenticó la petición, si
+// se usó una.
+func APIKeyFromContext(c *gin.Context) (*models.APIKey, bool) {
+	key, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil, false
+	}
+	apiKey, ok := key.(*models.APIKey)
+	return apiKey, ok
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"totesbackend/apperrors"
+	"totesbackend/logging"
+	"totesbackend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ProblemContentType es el tipo de contenido de las respuestas de error (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// ErrorHandler responde como problem+json el último error que un controlador
+// registró con c.Error, siempre que el controlador no haya escrito ya la
+// respuesta. Los errores sin tipo de dominio se responden como error interno
+// sin exponer su texto. Si el error indica cuánto esperar
+// (detalle retry_after_seconds) se agrega la cabecera Retry-After.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		appErr := resolveError(err)
+
+		problem := models.ProblemDetails{
+			Type:       "/problems/" + appErr.Code,
+			Title:      appErr.Message,
+			Status:     appErr.Status,
+			Instance:   c.Request.URL.Path,
+			Code:       appErr.Code,
+			MessageKey: appErr.MessageKey,
+			Details:    appErr.Details,
+			RequestID:  logging.RequestID(c.Request.Context()),
+		}
+		if appErr.Status < 500 {
+			problem.Detail = err.Error()
+		}
+
+		if retryAfter, ok := appErr.Details["retry_after_seconds"]; ok {
+			c.Header("Retry-After", fmt.Sprint(retryAfter))
+		}
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(appErr.Status, problem)
+	}
+}
+
+func resolveError(err error) *apperrors.Error {
+	if appErr, ok := apperrors.From(err); ok {
+		return appErr
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.ErrNotFound.Wrap(err)
+	}
+	return apperrors.ErrInternal.Wrap(err)
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+	"totesbackend/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics cuenta las peticiones y mide su latencia por método, ruta y código
+// de estado. Se usa la plantilla de la ruta (/items/:id) y no la URL, para que
+// la cantidad de series no crezca con los IDs; las rutas inexistentes se
+// agrupan como "unmatched". Debe registrarse antes de ErrorHandler para ver el
+// código de estado que este escribe.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
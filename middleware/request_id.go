@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"totesbackend/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader es la cabecera con la que se recibe y se devuelve el ID.
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limita el ID recibido para que no se puedan inyectar
+// caracteres de control ni valores enormes en los logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID usa el X-Request-ID recibido si es válido o genera uno nuevo, lo
+// devuelve en la respuesta y lo guarda en el contexto de la petición para que
+// lo vean los logs y los registros de UserLog. El usuario lo agregan APIKey y
+// Session al autenticar la petición.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"totesbackend/apperrors"
+	"totesbackend/logging"
+	"totesbackend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const sessionContextKey = "session"
+
+// SessionAuthenticator valida un token de sesión y devuelve la sesión con su
+// usuario.
+type SessionAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*models.UserSession, error)
+}
+
+// Session autentica las peticiones que traen Authorization: Bearer <token> y
+// deja la sesión, con su usuario, en el contexto. Las peticiones ya
+// autenticadas con una llave de API no se revisan. Las que no traen ni sesión
+// ni llave sólo pueden usar las rutas de publicPaths (por ejemplo el login);
+// en las demás se responde 401. La cabecera Username que envíe el cliente se
+// descarta: la identidad sale sólo de la sesión o de la llave (ver Caller).
+// Debe ir después de APIKey.
+func Session(auth SessionAuthenticator, publicPaths ...string) gin.HandlerFunc {
+	public := make(map[string]bool, len(publicPaths))
+	for _, path := range publicPaths {
+		public[path] = true
+	}
+
+	return func(c *gin.Context) {
+		c.Request.Header.Del("Username")
+
+		if _, ok := APIKeyFromContext(c); ok {
+			c.Next()
+			return
+		}
+		token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			// Sin ruta (404) se deja responder a gin
+			if path := c.FullPath(); path != "" && !public[path] {
+				_ = c.Error(apperrors.ErrUnauthenticated)
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		session, err := auth.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(logging.WithUser(c.Request.Context(), session.User.Email))
+		c.Set(sessionContextKey, session)
+		c.Next()
+	}
+}
+
+// SessionFromContext devuelve la sesión con la que se autenticó la petición,
+// si se usó una.
+func SessionFromContext(c *gin.Context) (*models.UserSession, bool) {
+	value, ok := c.Get(sessionContextKey)
+	if !ok {
+		return nil, false
+	}
+	session, ok := value.(*models.UserSession)
+	return session, ok
+}
+
+// Caller devuelve la identidad autenticada de la petición: el correo del
+// usuario de la sesión o la identidad de la llave de API. Es vacía en las
+// rutas públicas sin credenciales.
+func Caller(c *gin.Context) string {
+	if key, ok := APIKeyFromContext(c); ok {
+		return key.Subject()
+	}
+	if session, ok := SessionFromContext(c); ok {
+		return session.User.Email
+	}
+	return ""
+}
@@ -6,4 +6,7 @@ type AdditionalExpense struct {
 	ItemID      int     `gorm:"size:50;not null;index" json:"item_id"`
 	Expense     float64 `gorm:"not null" json:"expense"`
 	Description string  `gorm:"size:200" json:"description,omitempty"`
+	// Units es la cantidad de unidades del ítem que cubre el gasto. Si es 0, el gasto
+	// se reparte entre las unidades en stock y las ya facturadas del ítem.
+	Units int `gorm:"not null;default:0" json:"units"`
 }
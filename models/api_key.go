@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// APIKeySubjectPrefix antecede al prefijo de la llave en la identidad con la
+// que se registran las peticiones hechas con ella (UserLog y eventos de
+// seguridad).
+const APIKeySubjectPrefix = "api-key:"
+
+// APIKey es una llave de API para clientes de máquina. Pertenece a un cliente
+// (Principal) o, si tiene ReporterTaxID, a un revendedor que reporta ventas
+// externas. Sólo se guarda el SHA-256 de la llave; Prefix es la parte visible
+// con la que se identifica. Sus permisos no dependen de ningún tipo de
+// usuario.
+type APIKey struct {
+	ID     int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name   string `gorm:"size:100;not null" json:"name"`
+	Prefix string `gorm:"size:16;not null;unique" json:"prefix"`
+	// KeyHash es el SHA-256 en hexadecimal de la llave completa
+	KeyHash string `gorm:"size:64;not null;unique" json:"-"`
+	// Principal es el nombre del cliente de máquina o del revendedor
+	Principal     string       `gorm:"size:255;not null" json:"principal"`
+	ReporterTaxID *string      `gorm:"size:100" json:"reporter_tax_id,omitempty"`
+	Permissions   []Permission `gorm:"many2many:api_key_permissions;" json:"permissions"`
+	ExpiresAt     time.Time    `gorm:"not null" json:"expires_at"`
+	LastUsedAt    *time.Time   `json:"last_used_at"`
+	LastUsedIP    string       `gorm:"size:64" json:"last_used_ip,omitempty"`
+	RevokedAt     *time.Time   `json:"revoked_at,omitempty"`
+	// ReplacedByID es la llave creada al rotar esta; la anterior sigue
+	// sirviendo hasta su nuevo vencimiento
+	ReplacedByID *int      `json:"replaced_by_id,omitempty"`
+	CreatedBy    string    `gorm:"size:80;not null" json:"created_by"`
+	CreatedAt    time.Time `gorm:"not null" json:"created_at"`
+}
+
+// Subject es la identidad de la llave en los registros.
+func (k *APIKey) Subject() string {
+	return APIKeySubjectPrefix + k.Prefix
+}
+
+// IsReporter indica si la llave pertenece a un revendedor.
+func (k *APIKey) IsReporter() bool {
+	return k.ReporterTaxID != nil
+}
+
+// Active indica si la llave sirve en el momento now.
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// HasPermission indica si la llave tiene el permiso.
+func (k *APIKey) HasPermission(permissionID int) bool {
+	for _, permission := range k.Permissions {
+		if int(permission.ID) == permissionID {
+			return true
+		}
+	}
+	return false
+}
@@ -4,15 +4,15 @@ import "time"
 
 type Appointment struct {
 	ID               int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	DateTime         time.Time `gorm:"type:timestamp;not null" json:"dateTime"`
+	DateTime         time.Time `gorm:"type:timestamp;not null" json:"dateTime" binding:"required"`
 	State            bool      `gorm:"not null" json:"state"`
-	CustomerID       int       `gorm:"not null;index" json:"customerId"`
-	CustomerName     string    `gorm:"size:255;not null" json:"customerName"`
+	CustomerID       int       `gorm:"not null;index" json:"customerId" binding:"required,exists=customers"`
+	CustomerName     string    `gorm:"size:255;not null" json:"customerName" binding:"required"`
 	IsBusiness       bool      `gorm:"not null" json:"isBusiness"`
-	Address          string    `gorm:"size:100" json:"address,omitempty"`
-	PhoneNumbers     string    `gorm:"size:100" json:"phoneNumbers,omitempty"`
+	Address          string    `gorm:"size:100" json:"address,omitempty" visible:"customer_contact"`
+	PhoneNumbers     string    `gorm:"size:100" json:"phoneNumbers,omitempty" visible:"customer_contact,mask"`
 	CustomerState    bool      `gorm:"not null" json:"customerState"`
-	Email            string    `gorm:"size:255;not null" json:"email"`
-	LastName         string    `gorm:"size:255;not null" json:"lastName"`
-	IdentifierTypeID int       `gorm:"not null" json:"identifierTypeId"`
+	Email            string    `gorm:"size:255;not null" json:"email" binding:"required,email" visible:"customer_contact,mask"`
+	LastName         string    `gorm:"size:255;not null" json:"lastName" binding:"required"`
+	IdentifierTypeID int       `gorm:"not null" json:"identifierTypeId" binding:"required,exists=identifier_types"`
 }
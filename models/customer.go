@@ -5,10 +5,10 @@ type Customer struct {
 	CustomerName     string `gorm:"size:255; null" json:"customerName"` // puede ser nulo
 	CustomerId       string `gorm:"size:100;not null;unique" json:"customerId"`
 	IsBusiness       bool   `gorm:"not null" json:"isBusiness"`
-	Address          string `gorm:"size:100" json:"address,omitempty"`
-	PhoneNumbers     string `gorm:"size:100" json:"phoneNumbers,omitempty"`
+	Address          string `gorm:"size:100" json:"address,omitempty" visible:"customer_contact"`
+	PhoneNumbers     string `gorm:"size:100" json:"phoneNumbers,omitempty" visible:"customer_contact,mask"`
 	CustomerState    bool   `gorm:"not null" json:"customerState"`
-	Email            string `gorm:"size:255;not null;unique" json:"email"`
+	Email            string `gorm:"size:255;not null;unique" json:"email" visible:"customer_contact,mask"`
 	LastName         string `gorm:"size:255;not null" json:"lastName"`
 	IdentifierTypeID int    `gorm:"not null" json:"identifierTypeId"`
 }
@@ -1,9 +1,24 @@
 package models
 
+import "time"
+
 type DiscountType struct {
 	ID           int     `gorm:"primaryKey;autoIncrement;size:50" json:"id"`
 	Name         string  `gorm:"size:100;not null" json:"name"`
 	Description  string  `gorm:"size:300" json:"description,omitempty"`
 	IsPercentage bool    `gorm:"not null" json:"is_percentage"`
 	Value        float64 `gorm:"not null" json:"value"`
+
+	// Condiciones de la promoción. Todas son opcionales: un descuento sin
+	// condiciones se comporta como antes y se aplica siempre que se solicite.
+	CouponCode         *string    `gorm:"size:50;unique" json:"coupon_code,omitempty"`
+	AutoApply          bool       `gorm:"not null;default:false" json:"auto_apply"`
+	ValidFrom          *time.Time `json:"valid_from,omitempty"`
+	ValidTo            *time.Time `json:"valid_to,omitempty"`
+	MinPurchaseAmount  float64    `gorm:"not null;default:0" json:"min_purchase_amount"`
+	MaxUsesPerCustomer int        `gorm:"not null;default:0" json:"max_uses_per_customer"`
+	BuyQuantity        int        `gorm:"not null;default:0" json:"buy_quantity"`
+	GetQuantity        int        `gorm:"not null;default:0" json:"get_quantity"`
+	Items              []Item     `gorm:"many2many:discount_type_items;" json:"items,omitempty"`
+	ItemTypes          []ItemType `gorm:"many2many:discount_type_item_types;" json:"item_types,omitempty"`
 }
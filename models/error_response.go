@@ -1,6 +1,16 @@
 package models
 
-// ErrorResponse representa una respuesta de error estándar
-type ErrorResponse struct {
-	Error string `json:"error"`
+// ProblemDetails es el cuerpo de error según RFC 7807 (application/problem+json).
+// Code y MessageKey son estables y permiten a los clientes distinguir los errores.
+type ProblemDetails struct {
+	Type       string                 `json:"type"`
+	Title      string                 `json:"title"`
+	Status     int                    `json:"status"`
+	Detail     string                 `json:"detail,omitempty"`
+	Instance   string                 `json:"instance,omitempty"`
+	Code       string                 `json:"code"`
+	MessageKey string                 `json:"message_key"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	// RequestID permite buscar en los logs el error de una respuesta concreta.
+	RequestID string `json:"request_id,omitempty"`
 }
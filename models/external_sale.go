@@ -9,4 +9,7 @@ type ExternalSale struct {
 	Item         Item     `gorm:"foreignKey:ItemID;references:ID" json:"item"`
 	CustomerID   int      `gorm:"size:50;not null" json:"-"`
 	Customer     Customer `gorm:"foreignKey:CustomerID;references:ID" json:"customer"`
+	// APIKeyID es la llave de revendedor con la que se reportó la venta; en
+	// ese caso el nombre y el NIT del reportante salen de la llave
+	APIKeyID *int `json:"api_key_id,omitempty"`
 }
@@ -2,9 +2,14 @@ package models
 
 import "time"
 
+// HistoricalItemPrice representa el precio de venta de un ítem durante un rango
+// de tiempo. El rango abierto (EffectiveTo nulo) es el precio vigente o el último
+// cambio programado. Un rango con EffectiveFrom en el futuro es un cambio programado.
 type HistoricalItemPrice struct {
-	ID      int       `gorm:"primaryKey;autoIncrement" json:"id"`
-	ItemID  int       `gorm:"size:50;not null;index" json:"item_id"`
-	Price   float64   `gorm:"not null" json:"price"`
-	AddedAt time.Time `gorm:"not null" json:"modified_at,omitempty"`
+	ID            int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	ItemID        int        `gorm:"size:50;not null;index" json:"item_id"`
+	Price         float64    `gorm:"not null" json:"price"`
+	AddedAt       time.Time  `gorm:"not null" json:"modified_at,omitempty"`
+	EffectiveFrom time.Time  `gorm:"index" json:"effective_from"`
+	EffectiveTo   *time.Time `gorm:"index" json:"effective_to,omitempty"`
 }
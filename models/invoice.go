@@ -4,7 +4,7 @@ import "time"
 
 type Invoice struct {
 	ID             int            `gorm:"primaryKey;autoIncrement;size:50" json:"id"`
-	EnterpriseData string         `gorm:"size:300;not null" json:"enterprise_data"`
+	EnterpriseData string         `gorm:"size:300;not null" json:"enterprise_data" visible:"invoice_enterprise_data"`
 	DateTime       time.Time      `gorm:"not null" json:"date_time"`
 	CustomerID     int            `gorm:"not null" json:"-"`
 	Customer       Customer       `gorm:"foreignKey:CustomerID;references:ID" json:"customer"`
@@ -12,7 +12,11 @@ type Invoice struct {
 	Subtotal       float64        `gorm:"not null" json:"subtotal"`
 	Discounts      []DiscountType `gorm:"many2many:invoice_discounts;" json:"discounts"`
 	Taxes          []TaxType      `gorm:"many2many:invoice_taxes;" json:"taxes"`
+	DiscountTotal  float64        `gorm:"not null;default:0" json:"discount_total"`
+	TaxTotal       float64        `gorm:"not null;default:0" json:"tax_total"`
 	Total          float64        `gorm:"not null" json:"total"`
+	// PurchaseOrderID es la orden de compra que originó la factura, si la hay.
+	PurchaseOrderID *int `gorm:"index" json:"purchase_order_id,omitempty"`
 }
 
 type InvoiceItem struct {
@@ -20,5 +24,6 @@ type InvoiceItem struct {
 	ItemID    int `gorm:"primaryKey"`
 	Invoice   Invoice
 	Item      Item
-	Amount    int `gorm:"not null"`
+	Amount    int     `gorm:"not null"`
+	UnitPrice float64 `gorm:"not null;default:0"` // Precio unitario cobrado al momento de facturar
 }
@@ -6,9 +6,9 @@ type Item struct {
 	Description        string              `gorm:"size:300" json:"description,omitempty"`
 	Stock              int                 `gorm:"not null" json:"stock"`
 	SellingPrice       float64             `gorm:"not null" json:"selling_price"`
-	PurchasePrice      float64             `gorm:"not null" json:"purchase_price"`
+	PurchasePrice      float64             `gorm:"not null" json:"purchase_price" visible:"item_costs"`
 	ItemState          bool                `gorm:"not null" json:"item_state"`
 	ItemTypeID         int                 `gorm:"size:50;not null" json:"-"`
 	ItemType           ItemType            `gorm:"foreignKey:ItemTypeID;references:ID" json:"item_type"`
-	AdditionalExpenses []AdditionalExpense `gorm:"foreignKey:ItemID" json:"additional_expenses"`
+	AdditionalExpenses []AdditionalExpense `gorm:"foreignKey:ItemID" json:"additional_expenses" visible:"item_costs"`
 }
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// OIDCAuthRequest es un inicio de sesión con el proveedor de identidad que
+// espera la vuelta del usuario. Se identifica por el SHA-256 del state y se
+// borra al usarlo.
+type OIDCAuthRequest struct {
+	StateHash    string    `gorm:"primaryKey;size:64"`
+	Nonce        string    `gorm:"size:64;not null"`
+	CodeVerifier string    `gorm:"size:128;not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time `gorm:"not null"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// PasswordHistory guarda los hashes de contraseñas anteriores de un usuario
+// para impedir que las reutilice.
+type PasswordHistory struct {
+	ID           int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID       int       `gorm:"not null;index" json:"user_id"`
+	PasswordHash string    `gorm:"size:100;not null" json:"-"`
+	CreatedAt    time.Time `gorm:"not null" json:"created_at"`
+}
+
+// PasswordResetToken es un token de un solo uso para restablecer la
+// contraseña. Sólo se guarda el SHA-256 del token enviado por correo.
+type PasswordResetToken struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	TokenHash string     `gorm:"size:64;not null;unique" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"not null" json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+const (
+	PriceListSegmentIndividual = "individual"
+	PriceListSegmentBusiness   = "business"
+)
+
+// PriceList es una lista de precios con nombre. Se aplica a los clientes asignados
+// directamente o, si tiene segmento, a todos los clientes de ese segmento.
+type PriceList struct {
+	ID          int             `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string          `gorm:"size:100;not null" json:"name"`
+	Description string          `gorm:"size:300" json:"description,omitempty"`
+	Segment     string          `gorm:"size:20" json:"segment,omitempty"`
+	Priority    int             `gorm:"not null;default:0" json:"priority"`
+	Active      bool            `gorm:"not null" json:"active"`
+	Items       []PriceListItem `gorm:"foreignKey:PriceListID" json:"items"`
+	Rules       []PriceListRule `gorm:"foreignKey:PriceListID" json:"rules"`
+	Customers   []Customer      `gorm:"many2many:price_list_customers;" json:"-"`
+}
+
+// PriceListItem fija el precio de un ítem dentro de una lista.
+type PriceListItem struct {
+	PriceListID int     `gorm:"primaryKey" json:"-"`
+	ItemID      int     `gorm:"primaryKey" json:"item_id"`
+	Price       float64 `gorm:"not null" json:"price"`
+}
+
+// PriceListRule ajusta el precio de lista en un porcentaje (negativo para rebajar).
+// Sin tipo de ítem, la regla aplica a todos los ítems de la lista.
+type PriceListRule struct {
+	ID          int     `gorm:"primaryKey;autoIncrement" json:"id"`
+	PriceListID int     `gorm:"not null;index" json:"-"`
+	ItemTypeID  *int    `json:"item_type_id,omitempty"`
+	Percentage  float64 `gorm:"not null" json:"percentage"`
+}
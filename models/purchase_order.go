@@ -27,5 +27,6 @@ type PurchaseOrderItem struct {
 	ItemID          int `gorm:"primaryKey"`
 	PurchaseOrder   PurchaseOrder
 	Item            Item
-	Amount          int `gorm:"not null"`
+	Amount          int     `gorm:"not null"`
+	UnitPrice       float64 `gorm:"not null;default:0"` // Precio unitario vigente al crear la orden
 }
@@ -5,4 +5,5 @@ type Role struct {
 	Name        string       `gorm:"size:100;not null" json:"name"`
 	Description string       `gorm:"size:300" json:"description,omitempty"`
 	Permissions []Permission `gorm:"many2many:role_permission;" json:"permissions"`
+	Scopes      []RoleScope  `gorm:"foreignKey:RoleID" json:"scopes"`
 }
@@ -0,0 +1,10 @@
+package models
+
+// RoleScope limita lo que ven los usuarios con el rol en un recurso (ver
+// datascope). Si el usuario tiene otro rol que le da el mismo permiso sin
+// regla para el recurso, la regla no se aplica.
+type RoleScope struct {
+	RoleID   uint   `gorm:"primaryKey" json:"-"`
+	Resource string `gorm:"primaryKey;size:40" json:"resource"`
+	Rule     string `gorm:"size:20;not null" json:"rule"`
+}
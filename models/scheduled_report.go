@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Reportes que se pueden programar.
+const (
+	ReportSalesSummary      = "sales_summary"
+	ReportTaxSummary        = "tax_summary"
+	ReportTopItems          = "top_items"
+	ReportRevenueByItemType = "revenue_by_item_type"
+	ReportRevenueByCustomer = "revenue_by_customer"
+	ReportRevenueBySeller   = "revenue_by_seller"
+)
+
+// Formatos en los que se genera un reporte programado.
+const (
+	ReportFormatJSON = "json"
+	ReportFormatCSV  = "csv"
+	ReportFormatPDF  = "pdf"
+)
+
+// Estados de una ejecución de un reporte programado.
+const (
+	ReportRunRunning   = "running"
+	ReportRunSucceeded = "succeeded"
+	ReportRunFailed    = "failed"
+)
+
+// ScheduledReport es la definición de un reporte que se genera y se envía por
+// correo según una expresión cron.
+type ScheduledReport struct {
+	ID     int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name   string `gorm:"size:100;not null" json:"name"`
+	Report string `gorm:"size:50;not null" json:"report"`
+	// Parameters guarda en JSON los parámetros del reporte (range, period, orderBy, limit).
+	Parameters string     `gorm:"type:text" json:"-"`
+	Cron       string     `gorm:"size:100;not null" json:"cron"`
+	Format     string     `gorm:"size:10;not null" json:"format"`
+	Recipients string     `gorm:"size:500;not null" json:"-"` // Correos separados por coma
+	Active     bool       `gorm:"not null" json:"active"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ReportRun registra una ejecución de un reporte programado y guarda el archivo generado.
+type ReportRun struct {
+	ID                int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	ScheduledReportID int        `gorm:"not null;index" json:"scheduled_report_id"`
+	StartedAt         time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+	Status            string     `gorm:"size:20;not null" json:"status"`
+	Error             string     `gorm:"type:text" json:"error,omitempty"`
+	RangeFrom         time.Time  `json:"range_from"`
+	RangeTo           time.Time  `json:"range_to"`
+	ArtifactName      string     `gorm:"size:200" json:"artifact_name,omitempty"`
+	ContentType       string     `gorm:"size:100" json:"content_type,omitempty"`
+	Artifact          []byte     `json:"-"`
+	DeliveredTo       string     `gorm:"size:500" json:"delivered_to,omitempty"`
+}
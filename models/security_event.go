@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Tipos de eventos de seguridad.
+const (
+	SecurityEventLoginSucceeded  = "login_succeeded"
+	SecurityEventLoginFailed     = "login_failed"
+	SecurityEventLoginThrottled  = "login_throttled"
+	SecurityEventAccountLocked   = "account_locked"
+	SecurityEventAccountUnlocked = "account_unlocked"
+	SecurityEventPasswordChanged = "password_changed"
+	// SecurityEventPasswordResetRequested se registra también cuando el
+	// correo no corresponde a ninguna cuenta
+	SecurityEventPasswordResetRequested = "password_reset_requested"
+	SecurityEventPasswordReset          = "password_reset"
+	SecurityEventTwoFactorEnabled       = "two_factor_enabled"
+	SecurityEventTwoFactorDisabled      = "two_factor_disabled"
+	SecurityEventTwoFactorFailed        = "two_factor_failed"
+	SecurityEventRecoveryCodeUsed       = "recovery_code_used"
+	SecurityEventRecoveryCodesRenewed   = "recovery_codes_renewed"
+	SecurityEventUserInvited            = "user_invited"
+	SecurityEventInvitationResent       = "invitation_resent"
+	SecurityEventInvitationRevoked      = "invitation_revoked"
+	SecurityEventInvitationAccepted     = "invitation_accepted"
+	// SecurityEventUserTypeChanged se registra cuando los grupos del
+	// proveedor de identidad cambian el tipo de usuario al iniciar sesión
+	SecurityEventUserTypeChanged = "user_type_changed"
+	SecurityEventSessionRevoked  = "session_revoked"
+	SecurityEventAPIKeyCreated   = "api_key_created"
+	SecurityEventAPIKeyRotated   = "api_key_rotated"
+	SecurityEventAPIKeyRevoked   = "api_key_revoked"
+	// SecurityEventAPIKeyRejected se registra cuando llega una llave
+	// desconocida, vencida o revocada
+	SecurityEventAPIKeyRejected = "api_key_rejected"
+)
+
+// LoginAttempt es un intento de inicio de sesión. Los fallidos recientes de
+// una cuenta o de una IP determinan la espera entre intentos y el bloqueo.
+type LoginAttempt struct {
+	ID          int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	Email       string    `gorm:"size:80;not null;index" json:"email"`
+	IP          string    `gorm:"size:64;not null;index" json:"ip"`
+	Success     bool      `gorm:"not null" json:"success"`
+	AttemptedAt time.Time `gorm:"not null;index" json:"attempted_at"`
+}
+
+// SecurityEvent es un registro de auditoría de autenticación: inicios de
+// sesión, esperas impuestas, bloqueos y desbloqueos.
+type SecurityEvent struct {
+	ID        int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type      string `gorm:"size:40;not null;index" json:"type"`
+	UserEmail string `gorm:"size:80;index" json:"email"`
+	IP        string `gorm:"size:64" json:"ip,omitempty"`
+	// Actor es quien provocó el evento cuando no es el propio usuario, por
+	// ejemplo el administrador que desbloquea una cuenta
+	Actor     string    `gorm:"size:80" json:"actor,omitempty"`
+	Detail    string    `gorm:"size:500" json:"detail,omitempty"`
+	RequestID string    `gorm:"size:128" json:"request_id,omitempty"`
+	CreatedAt time.Time `gorm:"not null;index" json:"created_at"`
+}
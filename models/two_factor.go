@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// UserTOTP es el segundo factor TOTP de un usuario. Queda pendiente hasta que
+// el usuario lo confirma con un primer código.
+type UserTOTP struct {
+	UserID int `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	// Secret es la semilla base32, cifrada si se configuró TOTP_ENCRYPTION_KEY
+	Secret      string     `gorm:"size:200;not null" json:"-"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+	// LastUsedStep es el último paso de 30 segundos aceptado; un código no
+	// se acepta dos veces
+	LastUsedStep *int64    `json:"-"`
+	CreatedAt    time.Time `gorm:"not null" json:"created_at"`
+}
+
+// RecoveryCode es un código de recuperación de un solo uso para entrar sin la
+// aplicación de autenticación. Sólo se guarda su SHA-256.
+type RecoveryCode struct {
+	ID        int        `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int        `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `gorm:"not null" json:"created_at"`
+}
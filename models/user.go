@@ -1,11 +1,18 @@
 package models
 
+import "time"
+
 type User struct {
 	ID              int           `gorm:"primaryKey;autoIncrement" json:"id"`
 	Email           string        `gorm:"size:80;not null;unique" json:"email"`
-	Password        string        `gorm:"size:100;not null" json:"password"`
+	Password        string        `gorm:"size:100;not null" json:"-"`
 	UserStateTypeID int           `gorm:"not null" json:"-"`
 	UserTypeID      int           `gorm:"not null" json:"-"`
 	UserType        UserType      `gorm:"foreignKey:UserTypeID;references:ID" json:"user_type"`
 	UserStateType   UserStateType `gorm:"foreignKey:UserStateTypeID;references:ID" json:"user_state_type"`
+	// LockedUntil es el fin del bloqueo cuando el estado es UserStateLocked
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+	// PasswordLoginDisabled deja sólo el inicio de sesión con el proveedor de
+	// identidad (OIDC); con false la contraseña queda como alternativa
+	PasswordLoginDisabled bool `gorm:"not null;default:false" json:"password_login_disabled"`
 }
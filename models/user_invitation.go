@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// UserInvitation es la invitación de un usuario creado sin contraseña en
+// estado UserStatePending. Sólo se guarda el SHA-256 del token enviado por
+// correo; al reenviarla el token se reemplaza.
+type UserInvitation struct {
+	ID        int       `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int       `gorm:"not null;unique" json:"user_id"`
+	User      User      `gorm:"foreignKey:UserID" json:"-"`
+	InvitedBy string    `gorm:"size:80;not null" json:"invited_by"`
+	TokenHash string    `gorm:"size:64;not null;unique" json:"-"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	// SentAt es el último envío exitoso del correo; nil si falló y hay que
+	// reenviarla
+	SentAt     *time.Time `json:"sent_at"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"not null" json:"created_at"`
+}
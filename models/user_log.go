@@ -9,4 +9,6 @@ type UserLog struct {
 	UserEmail string    `gorm:"size:80;not null" json:"email"`
 	Log       string    `gorm:"size:500;not null" json:"log"`
 	DateTime  time.Time `gorm:"not null" json:"date_time,omitempty"`
+	// RequestID es el X-Request-ID de la petición que generó el registro
+	RequestID string `gorm:"size:128;index" json:"request_id,omitempty"`
 }
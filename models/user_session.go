@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Motivos con los que se revoca una sesión.
+const (
+	// SessionRevokedManually es una sesión cerrada por el propio usuario o
+	// por un administrador
+	SessionRevokedManually    = "revoked"
+	SessionRevokedUserState   = "user_state_changed"
+	SessionRevokedUserType    = "user_type_changed"
+	SessionRevokedAccountLock = "account_locked"
+)
+
+// UserSession es una sesión iniciada con contraseña o con el proveedor de
+// identidad. El cliente la presenta como Authorization: Bearer <token>; sólo
+// se guarda el SHA-256 del token. Vence por inactividad o al cumplir su
+// vigencia máxima (ExpiresAt).
+type UserSession struct {
+	ID        int    `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    int    `gorm:"not null;index" json:"user_id"`
+	User      User   `gorm:"foreignKey:UserID" json:"-"`
+	TokenHash string `gorm:"size:64;not null;unique" json:"-"`
+	// Method es password u oidc
+	Method string `gorm:"size:20;not null" json:"method"`
+	// Device es el User-Agent con el que se inició la sesión
+	Device       string     `gorm:"size:255" json:"device"`
+	IP           string     `gorm:"size:64;not null" json:"ip"`
+	CreatedAt    time.Time  `gorm:"not null" json:"created_at"`
+	LastSeenAt   time.Time  `gorm:"not null" json:"last_seen_at"`
+	ExpiresAt    time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy    string     `gorm:"size:80" json:"revoked_by,omitempty"`
+	RevokeReason string     `gorm:"size:40" json:"revoke_reason,omitempty"`
+}
+
+// Active indica si la sesión sirve en el momento now con el tiempo máximo de
+// inactividad idle.
+func (s *UserSession) Active(now time.Time, idle time.Duration) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt) && now.Before(s.LastSeenAt.Add(idle))
+}
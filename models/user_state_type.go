@@ -1,5 +1,17 @@
 package models
 
+// Identificadores fijos de los estados de usuario (ver database/seed.go).
+const (
+	UserStateActive   = 1
+	UserStateInactive = 2
+	// UserStateLocked es una cuenta bloqueada por intentos fallidos de inicio
+	// de sesión; se desbloquea sola al pasar User.LockedUntil.
+	UserStateLocked = 3
+	// UserStatePending es un usuario invitado que todavía no eligió su
+	// contraseña; pasa a UserStateActive al aceptar la invitación.
+	UserStatePending = 4
+)
+
 type UserStateType struct {
 	ID   int    `gorm:"primaryKey;autoIncrement" json:"id"`
 	Name string `gorm:"not null;size:100" json:"name"`
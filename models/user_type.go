@@ -4,5 +4,7 @@ type UserType struct {
 	ID          uint   `gorm:"primaryKey;autoIncrement" json:"id"`
 	Name        string `gorm:"size:100;not null" json:"name"`
 	Description string `gorm:"size:300" json:"description,omitempty"`
-	Roles       []Role `gorm:"many2many:user_type_has_role;" json:"permissions"`
+	// RequireTwoFactor obliga a los usuarios de este tipo a usar TOTP
+	RequireTwoFactor bool   `gorm:"not null;default:false" json:"require_two_factor"`
+	Roles            []Role `gorm:"many2many:user_type_has_role;" json:"permissions"`
 }
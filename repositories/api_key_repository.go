@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type APIKeyRepository struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{DB: db}
+}
+
+var apiKeyList = listSpec{
+	Fields: map[string]string{
+		"id":              "id",
+		"name":            "name",
+		"prefix":          "prefix",
+		"principal":       "principal",
+		"reporter_tax_id": "reporter_tax_id",
+		"expires_at":      "expires_at",
+		"last_used_at":    "last_used_at",
+		"revoked_at":      "revoked_at",
+		"created_by":      "created_by",
+		"created_at":      "created_at",
+	},
+	Sort:     []dtos.SortField{{Field: "created_at", Desc: true}},
+	Preloads: []string{"Permissions"},
+}
+
+// GetAPIKeys lista todas las llaves, incluidas las vencidas y revocadas.
+func (r *APIKeyRepository) GetAPIKeys(ctx context.Context, query dtos.ListQuery) ([]models.APIKey, *dtos.PageInfo, error) {
+	var keys []models.APIKey
+	page, err := paginate(r.DB.WithContext(ctx), query, apiKeyList, &keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return keys, page, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByID(ctx context.Context, id string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.DB.WithContext(ctx).Preload("Permissions").First(&key, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetAPIKeyByHash busca la llave por el hash de la llave completa, esté o no
+// vigente.
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	err := r.DB.WithContext(ctx).Preload("Permissions").First(&key, "key_hash = ?", keyHash).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// CreateAPIKey guarda la llave y sus permisos. Los permisos deben existir;
+// sólo se insertan las filas de api_key_permissions.
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	return r.DB.WithContext(ctx).Omit("Permissions.*").Create(key).Error
+}
+
+// RotateAPIKey guarda la llave nueva y deja la anterior vencer en
+// oldExpiresAt. Devuelve false, sin guardar nada, si la anterior ya estaba
+// revocada o rotada.
+func (r *APIKeyRepository) RotateAPIKey(ctx context.Context, oldID int, key *models.APIKey, oldExpiresAt time.Time) (bool, error) {
+	rotated := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var old models.APIKey
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND revoked_at IS NULL AND replaced_by_id IS NULL", oldID).
+			Take(&old).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Permissions.*").Create(key).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&old).Updates(map[string]interface{}{"replaced_by_id": key.ID, "expires_at": oldExpiresAt}).Error
+		if err != nil {
+			return err
+		}
+		rotated = true
+		return nil
+	})
+	return rotated, err
+}
+
+// RevokeAPIKey revoca la llave. Devuelve false si ya estaba revocada.
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, id int, now time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+// TouchAPIKey registra el último uso de la llave. Para no escribir en cada
+// petición sólo actualiza si el uso anterior es de antes de since.
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, id int, ip string, now, since time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, since).
+		Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error
+}
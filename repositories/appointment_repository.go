@@ -1,7 +1,10 @@
 package repositories
 
 import (
+	"context"
 	"time"
+	"totesbackend/datascope"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -15,93 +18,106 @@ func NewAppointmentRepository(db *gorm.DB) *AppointmentRepository {
 	return &AppointmentRepository{DB: db}
 }
 
-func (r *AppointmentRepository) GetAppointmentByID(id int) (*models.Appointment, error) {
+func (r *AppointmentRepository) GetAppointmentByID(ctx context.Context, id int) (*models.Appointment, error) {
 	var appointment models.Appointment
-	err := r.DB.First(&appointment, "id = ?", id).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceAppointments).First(&appointment, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &appointment, nil
 }
 
-func (r *AppointmentRepository) GetAllAppointments() ([]models.Appointment, error) {
+var appointmentList = listSpec{
+	Fields: map[string]string{
+		"id":           "id",
+		"dateTime":     "date_time",
+		"state":        "state",
+		"customerId":   "customer_id",
+		"customerName": "customer_name",
+		"lastName":     "last_name",
+		"email":        "email",
+		"isBusiness":   "is_business",
+	},
+}
+
+func (r *AppointmentRepository) GetAllAppointments(ctx context.Context, query dtos.ListQuery) ([]models.Appointment, *dtos.PageInfo, error) {
 	var appointments []models.Appointment
-	err := r.DB.Find(&appointments).Error
+	page, err := paginate(scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceAppointments), query, appointmentList, &appointments)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return appointments, nil
+	return appointments, page, nil
 }
 
-func (r *AppointmentRepository) SearchAppointmentsByState(state bool) ([]models.Appointment, error) {
+func (r *AppointmentRepository) SearchAppointmentsByState(ctx context.Context, state bool) ([]models.Appointment, error) {
 	var appointments []models.Appointment
-	err := r.DB.Where("state = ?", state).Find(&appointments).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceAppointments).Where("state = ?", state).Find(&appointments).Error
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (r *AppointmentRepository) GetAppointmentsByCustomerID(customerID int) ([]models.Appointment, error) {
+func (r *AppointmentRepository) GetAppointmentsByCustomerID(ctx context.Context, customerID int) ([]models.Appointment, error) {
 	var appointments []models.Appointment
-	err := r.DB.Where("customer_id = ?", customerID).Find(&appointments).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceAppointments).Where("customer_id = ?", customerID).Find(&appointments).Error
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (r *AppointmentRepository) CreateAppointment(appointment *models.Appointment) (*models.Appointment, error) {
-	if err := r.DB.Create(appointment).Error; err != nil {
+func (r *AppointmentRepository) CreateAppointment(ctx context.Context, appointment *models.Appointment) (*models.Appointment, error) {
+	if err := r.DB.WithContext(ctx).Create(appointment).Error; err != nil {
 		return nil, err
 	}
 	return appointment, nil
 }
 
-func (r *AppointmentRepository) UpdateAppointment(appointment *models.Appointment) error {
-	if err := r.DB.Save(appointment).Error; err != nil {
+func (r *AppointmentRepository) UpdateAppointment(ctx context.Context, appointment *models.Appointment) error {
+	if err := r.DB.WithContext(ctx).Save(appointment).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *AppointmentRepository) SearchAppointmentsByID(query string) ([]models.Appointment, error) {
+func (r *AppointmentRepository) SearchAppointmentsByID(ctx context.Context, query string) ([]models.Appointment, error) {
 	var appointments []models.Appointment
-	err := r.DB.Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&appointments).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceAppointments).Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&appointments).Error
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (r *AppointmentRepository) SearchAppointmentsByCustomerID(query string) ([]models.Appointment, error) {
+func (r *AppointmentRepository) SearchAppointmentsByCustomerID(ctx context.Context, query string) ([]models.Appointment, error) {
 	var appointments []models.Appointment
-	err := r.DB.Where("CAST(customer_id AS TEXT) LIKE ?", query+"%").Find(&appointments).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceAppointments).Where("CAST(customer_id AS TEXT) LIKE ?", query+"%").Find(&appointments).Error
 	if err != nil {
 		return nil, err
 	}
 	return appointments, nil
 }
 
-func (r *AppointmentRepository) GetAppointmentByCustomerIDAndDate(customerID int, dateTime time.Time) (*models.Appointment, error) {
+func (r *AppointmentRepository) GetAppointmentByCustomerIDAndDate(ctx context.Context, customerID int, dateTime time.Time) (*models.Appointment, error) {
 	var appointment models.Appointment
-	err := r.DB.Where("customer_id = ? AND date_time = ?", customerID, dateTime).First(&appointment).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceAppointments).Where("customer_id = ? AND date_time = ?", customerID, dateTime).First(&appointment).Error
 	if err != nil {
 		return nil, err
 	}
 	return &appointment, nil
 }
 
-func (r *AppointmentRepository) CountAppointmentsAtDateTime(dateTime time.Time) (int64, error) {
+func (r *AppointmentRepository) CountAppointmentsAtDateTime(ctx context.Context, dateTime time.Time) (int64, error) {
 	var count int64
-	err := r.DB.Model(&models.Appointment{}).
+	err := r.DB.WithContext(ctx).Model(&models.Appointment{}).
 		Where("date_time = ?", dateTime).
 		Count(&count).Error
 	return count, err
 }
 
-func (r *AppointmentRepository) DeleteAppointmentByID(id int) error {
-	result := r.DB.Delete(&models.Appointment{}, id)
+func (r *AppointmentRepository) DeleteAppointmentByID(ctx context.Context, id int) error {
+	result := r.DB.WithContext(ctx).Delete(&models.Appointment{}, id)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -111,14 +127,14 @@ func (r *AppointmentRepository) DeleteAppointmentByID(id int) error {
 	return nil
 }
 
-func (r *AppointmentRepository) CountAppointmentsByHourOnDate(date time.Time) ([]int, error) {
+func (r *AppointmentRepository) CountAppointmentsByHourOnDate(ctx context.Context, date time.Time) ([]int, error) {
 	counts := make([]int, 9) // from 9:00 to 17:00
 
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 9, 0, 0, 0, date.Location())
 	endOfDay := time.Date(date.Year(), date.Month(), date.Day(), 17, 59, 59, 0, date.Location())
 
 	var appointments []models.Appointment
-	err := r.DB.Where("date_time BETWEEN ? AND ?", startOfDay, endOfDay).Find(&appointments).Error
+	err := r.DB.WithContext(ctx).Where("date_time BETWEEN ? AND ?", startOfDay, endOfDay).Find(&appointments).Error
 	if err != nil {
 		return nil, err
 	}
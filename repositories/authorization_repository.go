@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 )
 
@@ -12,9 +14,9 @@ func NewAuthorizationRepository(db *gorm.DB) *AuthorizationRepository {
 	return &AuthorizationRepository{DB: db}
 }
 
-func (r *AuthorizationRepository) UserHasPermission(email string, permissionID int) (bool, error) {
+func (r *AuthorizationRepository) UserHasPermission(ctx context.Context, email string, permissionID int) (bool, error) {
 	var count int64
-	err := r.DB.Table("users").
+	err := r.DB.WithContext(ctx).Table("users").
 		Joins("JOIN user_types ON users.user_type_id = user_types.id").
 		Joins("JOIN user_type_has_role ON user_types.id = user_type_has_role.user_type_id").
 		Joins("JOIN roles ON user_type_has_role.role_id = roles.id").
@@ -29,3 +31,47 @@ func (r *AuthorizationRepository) UserHasPermission(email string, permissionID i
 
 	return count > 0, nil
 }
+
+// GetUserTypePermissionIDs devuelve los permisos que dan los roles del tipo
+// de usuario, sin repetidos.
+func (r *AuthorizationRepository) GetUserTypePermissionIDs(ctx context.Context, userTypeID int) ([]int, error) {
+	var ids []int
+	err := r.DB.WithContext(ctx).Table("user_type_has_role").
+		Joins("JOIN role_permission ON user_type_has_role.role_id = role_permission.role_id").
+		Where("user_type_has_role.user_type_id = ?", userTypeID).
+		Distinct().
+		Order("role_permission.permission_id").
+		Pluck("role_permission.permission_id", &ids).Error
+	return ids, err
+}
+
+// GetPermissionRules devuelve, por cada rol del usuario que le da el permiso,
+// su regla de alcance para resource, o "" si el rol no tiene regla.
+func (r *AuthorizationRepository) GetPermissionRules(ctx context.Context, email string, permissionID int, resource string) ([]string, error) {
+	var rules []string
+	err := r.DB.WithContext(ctx).Table("users").
+		Joins("JOIN user_type_has_role ON users.user_type_id = user_type_has_role.user_type_id").
+		Joins("JOIN role_permission ON user_type_has_role.role_id = role_permission.role_id").
+		Joins("LEFT JOIN role_scopes ON role_scopes.role_id = role_permission.role_id AND role_scopes.resource = ?", resource).
+		Where("users.email = ? AND role_permission.permission_id = ?", email, permissionID).
+		Pluck("COALESCE(role_scopes.rule, '')", &rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetEmployeeIDByEmail devuelve el ID del empleado del usuario, o 0 si el
+// usuario no es empleado.
+func (r *AuthorizationRepository) GetEmployeeIDByEmail(ctx context.Context, email string) (int, error) {
+	var ids []int
+	err := r.DB.WithContext(ctx).Table("employees").
+		Joins("JOIN users ON employees.user_id = users.id").
+		Where("users.email = ?", email).
+		Limit(1).
+		Pluck("employees.id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	return ids[0], nil
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,61 +16,72 @@ func NewCommentRepository(db *gorm.DB) *CommentRepository {
 	return &CommentRepository{DB: db}
 }
 
-func (r *CommentRepository) GetCommentByID(id int) (*models.Comment, error) {
+func (r *CommentRepository) GetCommentByID(ctx context.Context, id int) (*models.Comment, error) {
 	var comment models.Comment
-	err := r.DB.First(&comment, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&comment, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &comment, nil
 }
 
-func (r *CommentRepository) GetAllComments() ([]models.Comment, error) {
+var commentList = listSpec{
+	Fields: map[string]string{
+		"id":             "id",
+		"name":           "name",
+		"lastname":       "last_name",
+		"email":          "email",
+		"residenceState": "residence_state",
+		"residenceCity":  "residence_city",
+	},
+}
+
+func (r *CommentRepository) GetAllComments(ctx context.Context, query dtos.ListQuery) ([]models.Comment, *dtos.PageInfo, error) {
 	var comments []models.Comment
-	err := r.DB.Find(&comments).Error
+	page, err := paginate(r.DB.WithContext(ctx), query, commentList, &comments)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return comments, nil
+	return comments, page, nil
 }
 
-func (r *CommentRepository) SearchCommentsByEmail(email string) ([]models.Comment, error) {
+func (r *CommentRepository) SearchCommentsByEmail(ctx context.Context, email string) ([]models.Comment, error) {
 	var comments []models.Comment
-	err := r.DB.Where("LOWER(email) LIKE LOWER(?)", email+"%").Find(&comments).Error
+	err := r.DB.WithContext(ctx).Where("LOWER(email) LIKE LOWER(?)", email+"%").Find(&comments).Error
 	if err != nil {
 		return nil, err
 	}
 	return comments, nil
 }
 
-func (r *CommentRepository) CreateComment(comment *models.Comment) (*models.Comment, error) {
-	if err := r.DB.Create(comment).Error; err != nil {
+func (r *CommentRepository) CreateComment(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+	if err := r.DB.WithContext(ctx).Create(comment).Error; err != nil {
 		return nil, err
 	}
 	return comment, nil
 }
 
-func (r *CommentRepository) UpdateComment(comment *models.Comment) error {
+func (r *CommentRepository) UpdateComment(ctx context.Context, comment *models.Comment) error {
 	var existingComment models.Comment
-	if err := r.DB.First(&existingComment, "id = ?", comment.ID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&existingComment, "id = ?", comment.ID).Error; err != nil {
 		return err
 	}
 
-	return r.DB.Save(comment).Error
+	return r.DB.WithContext(ctx).Save(comment).Error
 }
 
-func (r *CommentRepository) SearchCommentsByID(query string) ([]models.Comment, error) {
+func (r *CommentRepository) SearchCommentsByID(ctx context.Context, query string) ([]models.Comment, error) {
 	var comments []models.Comment
-	err := r.DB.Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&comments).Error
+	err := r.DB.WithContext(ctx).Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&comments).Error
 	if err != nil {
 		return nil, err
 	}
 	return comments, nil
 }
 
-func (r *CommentRepository) SearchCommentsByName(name string) ([]models.Comment, error) {
+func (r *CommentRepository) SearchCommentsByName(ctx context.Context, name string) ([]models.Comment, error) {
 	var comments []models.Comment
-	err := r.DB.Where("LOWER(name) LIKE LOWER(?)", name+"%").Find(&comments).Error
+	err := r.DB.WithContext(ctx).Where("LOWER(name) LIKE LOWER(?)", name+"%").Find(&comments).Error
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/datascope"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,77 +17,90 @@ func NewCustomerRepository(db *gorm.DB) *CustomerRepository {
 	return &CustomerRepository{DB: db}
 }
 
-func (r *CustomerRepository) GetCustomerByID(id int) (*models.Customer, error) {
+func (r *CustomerRepository) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
 	var customer models.Customer
-	err := r.DB.First(&customer, "id = ?", id).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceCustomers).First(&customer, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &customer, nil
 }
 
-func (r *CustomerRepository) GetCustomerByCustomerID(customerID string) (*models.Customer, error) {
+func (r *CustomerRepository) GetCustomerByCustomerID(ctx context.Context, customerID string) (*models.Customer, error) {
 	var customer models.Customer
-	err := r.DB.First(&customer, "customer_id = ?", customerID).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceCustomers).First(&customer, "customer_id = ?", customerID).Error
 	if err != nil {
 		return nil, err
 	}
 	return &customer, nil
 }
 
-func (r *CustomerRepository) GetAllCustomers() ([]models.Customer, error) {
+var customerList = listSpec{
+	Fields: map[string]string{
+		"id":               "id",
+		"customerName":     "customer_name",
+		"customerId":       "customer_id",
+		"isBusiness":       "is_business",
+		"customerState":    "customer_state",
+		"email":            "email",
+		"lastName":         "last_name",
+		"identifierTypeId": "identifier_type_id",
+	},
+}
+
+func (r *CustomerRepository) GetAllCustomers(ctx context.Context, query dtos.ListQuery) ([]models.Customer, *dtos.PageInfo, error) {
 	var customers []models.Customer
-	err := r.DB.Find(&customers).Error
+	page, err := paginate(scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceCustomers), query, customerList, &customers)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return customers, nil
+	return customers, page, nil
 }
 
-func (r *CustomerRepository) GetCustomerByEmail(email string) (*models.Customer, error) {
+func (r *CustomerRepository) GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
 	var customer models.Customer
-	err := r.DB.First(&customer, "email = ?", email).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceCustomers).First(&customer, "email = ?", email).Error
 	if err != nil {
 		return nil, err
 	}
 	return &customer, nil
 }
 
-func (r *CustomerRepository) CreateCustomer(customer *models.Customer) (*models.Customer, error) {
-	if err := r.DB.Create(customer).Error; err != nil {
+func (r *CustomerRepository) CreateCustomer(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	if err := r.DB.WithContext(ctx).Create(customer).Error; err != nil {
 		return nil, err
 	}
 	return customer, nil
 }
 
-func (r *CustomerRepository) UpdateCustomer(customer *models.Customer) error {
-	if err := r.DB.Save(customer).Error; err != nil {
+func (r *CustomerRepository) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	if err := r.DB.WithContext(ctx).Save(customer).Error; err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *CustomerRepository) SearchCustomersByID(id string) ([]models.Customer, error) {
+func (r *CustomerRepository) SearchCustomersByID(ctx context.Context, id string) ([]models.Customer, error) {
 	var customers []models.Customer
-	err := r.DB.Where("CAST(id AS TEXT) LIKE ?", id+"%").Find(&customers).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceCustomers).Where("CAST(id AS TEXT) LIKE ?", id+"%").Find(&customers).Error
 	if err != nil {
 		return nil, err
 	}
 	return customers, nil
 }
 
-func (r *CustomerRepository) SearchCustomersByName(name string) ([]models.Customer, error) {
+func (r *CustomerRepository) SearchCustomersByName(ctx context.Context, name string) ([]models.Customer, error) {
 	var customers []models.Customer
-	err := r.DB.Where("LOWER(customer_name) LIKE LOWER(?)", name+"%").Find(&customers).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceCustomers).Where("LOWER(customer_name) LIKE LOWER(?)", name+"%").Find(&customers).Error
 	if err != nil {
 		return nil, err
 	}
 	return customers, nil
 }
 
-func (r *CustomerRepository) SearchCustomersByLastName(lastname string) ([]models.Customer, error) {
+func (r *CustomerRepository) SearchCustomersByLastName(ctx context.Context, lastname string) ([]models.Customer, error) {
 	var customers []models.Customer
-	err := r.DB.Where("LOWER(last_name) LIKE LOWER(?)", lastname+"%").Find(&customers).Error
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourceCustomers).Where("LOWER(last_name) LIKE LOWER(?)", lastname+"%").Find(&customers).Error
 	if err != nil {
 		return nil, err
 	}
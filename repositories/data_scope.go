@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/datascope"
+
+	"gorm.io/gorm"
+)
+
+// ownCustomers son los clientes con alguna orden de compra del vendedor.
+const ownCustomers = "SELECT customer_id FROM purchase_orders WHERE seller_id = ? AND customer_id IS NOT NULL"
+
+// scoped restringe db a los registros de resource que permite el alcance de
+// la petición, si lo hay. Lo usan las consultas de listar, buscar y obtener
+// por ID; un registro fuera del alcance se comporta como inexistente.
+func scoped(ctx context.Context, db *gorm.DB, resource string) *gorm.DB {
+	scope, ok := datascope.For(ctx, resource)
+	if !ok {
+		return db
+	}
+	switch resource {
+	case datascope.ResourcePurchaseOrders:
+		return db.Where("purchase_orders.seller_id = ?", scope.EmployeeID)
+	case datascope.ResourceCustomers:
+		return db.Where("customers.id IN ("+ownCustomers+")", scope.EmployeeID)
+	case datascope.ResourceAppointments:
+		return db.Where("appointments.customer_id IN ("+ownCustomers+")", scope.EmployeeID)
+	}
+	return db
+}
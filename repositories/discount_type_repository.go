@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,21 +16,97 @@ func NewDiscountTypeRepository(db *gorm.DB) *DiscountTypeRepository {
 	return &DiscountTypeRepository{DB: db}
 }
 
-func (r *DiscountTypeRepository) GetAllDiscountTypes() ([]models.DiscountType, error) {
+var discountTypeList = listSpec{
+	Fields: map[string]string{
+		"id":                  "id",
+		"name":                "name",
+		"is_percentage":       "is_percentage",
+		"value":               "value",
+		"coupon_code":         "coupon_code",
+		"auto_apply":          "auto_apply",
+		"valid_from":          "valid_from",
+		"valid_to":            "valid_to",
+		"min_purchase_amount": "min_purchase_amount",
+	},
+	Preloads: []string{"Items", "ItemTypes"},
+}
+
+func (r *DiscountTypeRepository) GetAllDiscountTypes(ctx context.Context, query dtos.ListQuery) ([]models.DiscountType, *dtos.PageInfo, error) {
 	var discountTypes []models.DiscountType
-	err := r.DB.Find(&discountTypes).Error
-	return discountTypes, err
+	page, err := paginate(r.DB.WithContext(ctx), query, discountTypeList, &discountTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return discountTypes, page, nil
+}
+
+func (r *DiscountTypeRepository) GetDiscountTypeByID(ctx context.Context, id string) (*models.DiscountType, error) {
+	var discountType models.DiscountType
+	err := r.DB.WithContext(ctx).Preload("Items").Preload("ItemTypes").First(&discountType, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &discountType, nil
 }
 
-func (r *DiscountTypeRepository) GetDiscountTypeByID(id string) (*models.DiscountType, error) {
+func (r *DiscountTypeRepository) GetDiscountTypeByCouponCode(ctx context.Context, code string) (*models.DiscountType, error) {
 	var discountType models.DiscountType
-	err := r.DB.First(&discountType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("Items").Preload("ItemTypes").
+		First(&discountType, "UPPER(coupon_code) = UPPER(?)", code).Error
 	if err != nil {
 		return nil, err
 	}
 	return &discountType, nil
 }
 
-func (r *DiscountTypeRepository) CreateDiscountType(discount *models.DiscountType) error {
-	return r.DB.Create(discount).Error
+func (r *DiscountTypeRepository) GetAutoApplyDiscountTypes(ctx context.Context) ([]models.DiscountType, error) {
+	var discountTypes []models.DiscountType
+	err := r.DB.WithContext(ctx).Preload("Items").Preload("ItemTypes").
+		Where("auto_apply = ?", true).
+		Find(&discountTypes).Error
+	return discountTypes, err
+}
+
+// CountCustomerUsages cuenta cuántas facturas del cliente ya incluyen el descuento.
+func (r *DiscountTypeRepository) CountCustomerUsages(ctx context.Context, discountID int, customerID int) (int64, error) {
+	return countCustomerUsages(r.DB.WithContext(ctx), discountID, customerID)
+}
+
+func countCustomerUsages(db *gorm.DB, discountID int, customerID int) (int64, error) {
+	var count int64
+	err := db.Table("invoice_discounts").
+		Joins("JOIN invoices ON invoices.id = invoice_discounts.invoice_id").
+		Where("invoice_discounts.discount_type_id = ? AND invoices.customer_id = ?", discountID, customerID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *DiscountTypeRepository) CreateDiscountType(ctx context.Context, discount *models.DiscountType, itemIDs []int, itemTypeIDs []int) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Items", "ItemTypes").Create(discount).Error; err != nil {
+			return err
+		}
+
+		if len(itemIDs) > 0 {
+			var items []models.Item
+			if err := tx.Where("id IN ?", itemIDs).Find(&items).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(discount).Association("Items").Append(items); err != nil {
+				return err
+			}
+		}
+
+		if len(itemTypeIDs) > 0 {
+			var itemTypes []models.ItemType
+			if err := tx.Where("id IN ?", itemTypeIDs).Find(&itemTypes).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(discount).Association("ItemTypes").Append(itemTypes); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
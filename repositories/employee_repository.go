@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,18 +16,18 @@ func NewEmployeeRepository(db *gorm.DB) *EmployeeRepository {
 	return &EmployeeRepository{DB: db}
 }
 
-func (r *EmployeeRepository) GetEmployeeByID(id string) (*models.Employee, error) {
+func (r *EmployeeRepository) GetEmployeeByID(ctx context.Context, id string) (*models.Employee, error) {
 	var employee models.Employee
-	err := r.DB.Preload("User").Preload("IdentifierType").First(&employee, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("User").Preload("IdentifierType").First(&employee, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &employee, nil
 }
 
-func (r *EmployeeRepository) SearchEmployeesByID(query string) ([]models.Employee, error) {
+func (r *EmployeeRepository) SearchEmployeesByID(ctx context.Context, query string) ([]models.Employee, error) {
 	var employees []models.Employee
-	err := r.DB.Preload("User").Preload("IdentifierType").
+	err := r.DB.WithContext(ctx).Preload("User").Preload("IdentifierType").
 		Where("CAST(id AS TEXT) LIKE ?", query+"%").
 		Find(&employees).Error
 	if err != nil {
@@ -34,9 +36,9 @@ func (r *EmployeeRepository) SearchEmployeesByID(query string) ([]models.Employe
 	return employees, nil
 }
 
-func (r *EmployeeRepository) SearchEmployeesByName(names string) ([]models.Employee, error) {
+func (r *EmployeeRepository) SearchEmployeesByName(ctx context.Context, names string) ([]models.Employee, error) {
 	var employees []models.Employee
-	err := r.DB.Preload("User").Preload("IdentifierType").
+	err := r.DB.WithContext(ctx).Preload("User").Preload("IdentifierType").
 		Where("LOWER(names) LIKE LOWER(?)", names+"%").
 		Find(&employees).Error
 	if err != nil {
@@ -45,17 +47,29 @@ func (r *EmployeeRepository) SearchEmployeesByName(names string) ([]models.Emplo
 	return employees, nil
 }
 
-func (r *EmployeeRepository) GetAllEmployees() ([]models.Employee, error) {
+var employeeList = listSpec{
+	Fields: map[string]string{
+		"id":                 "id",
+		"names":              "names",
+		"last_names":         "last_names",
+		"personal_id":        "personal_id",
+		"user_id":            "user_id",
+		"identifier_type_id": "identifier_type_id",
+	},
+	Preloads: []string{"User", "IdentifierType"},
+}
+
+func (r *EmployeeRepository) GetAllEmployees(ctx context.Context, query dtos.ListQuery) ([]models.Employee, *dtos.PageInfo, error) {
 	var employees []models.Employee
-	err := r.DB.Preload("User").Preload("IdentifierType").Find(&employees).Error
+	page, err := paginate(r.DB.WithContext(ctx), query, employeeList, &employees)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return employees, nil
+	return employees, page, nil
 }
 
-func (r *EmployeeRepository) UpdateEmployee(employee *models.Employee) error {
-	return r.DB.Model(&models.Employee{}).
+func (r *EmployeeRepository) UpdateEmployee(ctx context.Context, employee *models.Employee) error {
+	return r.DB.WithContext(ctx).Model(&models.Employee{}).
 		Where("id = ?", employee.ID).
 		Updates(map[string]interface{}{
 			"names":              employee.Names,
@@ -68,8 +82,8 @@ func (r *EmployeeRepository) UpdateEmployee(employee *models.Employee) error {
 		}).Error
 }
 
-func (r *EmployeeRepository) CreateEmployee(employee *models.Employee) (*models.Employee, error) {
-	if err := r.DB.Create(employee).Error; err != nil {
+func (r *EmployeeRepository) CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error) {
+	if err := r.DB.WithContext(ctx).Create(employee).Error; err != nil {
 		return nil, err
 	}
 	return employee, nil
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,9 +16,9 @@ func NewExternalSaleRepository(db *gorm.DB) *ExternalSaleRepository {
 	return &ExternalSaleRepository{DB: db}
 }
 
-func (r *ExternalSaleRepository) GetExternalSaleByID(id string) (*models.ExternalSale, error) {
+func (r *ExternalSaleRepository) GetExternalSaleByID(ctx context.Context, id string) (*models.ExternalSale, error) {
 	var externalSale models.ExternalSale
-	err := r.DB.
+	err := r.DB.WithContext(ctx).
 		Preload("Item").
 		Preload("Item.ItemType").
 		Preload("Item.AdditionalExpenses").
@@ -30,25 +32,31 @@ func (r *ExternalSaleRepository) GetExternalSaleByID(id string) (*models.Externa
 	return &externalSale, nil
 }
 
-func (r *ExternalSaleRepository) GetAllExternalSales() ([]models.ExternalSale, error) {
-	var externalSales []models.ExternalSale
-	err := r.DB.
-		Preload("Item").
-		Preload("Item.ItemType").
-		Preload("Item.AdditionalExpenses").
-		Preload("Customer").
-		Find(&externalSales).Error
+var externalSaleList = listSpec{
+	Fields: map[string]string{
+		"id":              "id",
+		"reporter_name":   "reporter_name",
+		"reporter_tax_id": "reporter_id",
+		"stock":           "stock",
+		"item_id":         "item_id",
+		"customer_id":     "customer_id",
+		"api_key_id":      "api_key_id",
+	},
+	Preloads: []string{"Item", "Item.ItemType", "Item.AdditionalExpenses", "Customer"},
+}
 
+func (r *ExternalSaleRepository) GetAllExternalSales(ctx context.Context, query dtos.ListQuery) ([]models.ExternalSale, *dtos.PageInfo, error) {
+	var externalSales []models.ExternalSale
+	page, err := paginate(r.DB.WithContext(ctx), query, externalSaleList, &externalSales)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	return externalSales, nil
+	return externalSales, page, nil
 }
 
-func (r *ExternalSaleRepository) CreateExternalSale(externalSale *models.ExternalSale) error {
+func (r *ExternalSaleRepository) CreateExternalSale(ctx context.Context, externalSale *models.ExternalSale) error {
 
-	if err := r.DB.Create(externalSale).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Create(externalSale).Error; err != nil {
 		return err
 	}
 	return nil
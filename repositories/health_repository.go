@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+type HealthRepository struct {
+	DB *gorm.DB
+}
+
+func NewHealthRepository(db *gorm.DB) *HealthRepository {
+	return &HealthRepository{DB: db}
+}
+
+// Ping verifica que la base responda dentro del plazo del contexto.
+func (r *HealthRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// PoolStats devuelve el estado actual del pool de conexiones.
+func (r *HealthRepository) PoolStats() (sql.DBStats, error) {
+	sqlDB, err := r.DB.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
+
+// GetSchemaVersion devuelve la última migración aplicada, o 0 si no hay ninguna.
+func (r *HealthRepository) GetSchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := r.DB.WithContext(ctx).
+		Table("schema_migrations").
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error
+	return version, err
+}
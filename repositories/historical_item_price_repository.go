@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"context"
+	"errors"
+	"time"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,12 +17,114 @@ func NewHistoricalItemPriceRepository(db *gorm.DB) *HistoricalItemPriceRepositor
 	return &HistoricalItemPriceRepository{DB: db}
 }
 
-func (r *HistoricalItemPriceRepository) CreateHistoricalItemPrice(price *models.HistoricalItemPrice) error {
-	return r.DB.Create(price).Error
+func (r *HistoricalItemPriceRepository) CreateHistoricalItemPrice(ctx context.Context, price *models.HistoricalItemPrice) error {
+	return r.DB.WithContext(ctx).Create(price).Error
 }
 
-func (r *HistoricalItemPriceRepository) GetHistoricalItemPrice(itemID string) ([]models.HistoricalItemPrice, error) {
+func (r *HistoricalItemPriceRepository) GetHistoricalItemPrice(ctx context.Context, itemID string) ([]models.HistoricalItemPrice, error) {
 	var historicalPrices []models.HistoricalItemPrice
-	err := r.DB.Where("item_id = ?", itemID).Order("added_at DESC").Find(&historicalPrices).Error
+	err := r.DB.WithContext(ctx).Where("item_id = ?", itemID).Order("effective_from DESC").Find(&historicalPrices).Error
 	return historicalPrices, err
 }
+
+func (r *HistoricalItemPriceRepository) GetHistoricalItemPriceByID(ctx context.Context, id string) (*models.HistoricalItemPrice, error) {
+	var price models.HistoricalItemPrice
+	err := r.DB.WithContext(ctx).First(&price, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// GetPriceAsOf devuelve el rango de precio vigente para el ítem en el instante dado.
+func (r *HistoricalItemPriceRepository) GetPriceAsOf(ctx context.Context, itemID int, at time.Time) (*models.HistoricalItemPrice, error) {
+	var price models.HistoricalItemPrice
+	err := r.DB.WithContext(ctx).Where("item_id = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", itemID, at, at).
+		Order("effective_from DESC").
+		First(&price).Error
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// CreatePriceRange registra un precio vigente desde el instante dado. El rango que
+// cubría ese instante se corta y el nuevo rango termina donde terminaba el anterior,
+// de modo que los cambios programados posteriores se conservan.
+func (r *HistoricalItemPriceRepository) CreatePriceRange(ctx context.Context, itemID int, price float64, from time.Time) (*models.HistoricalItemPrice, error) {
+	newRange := &models.HistoricalItemPrice{
+		ItemID:        itemID,
+		Price:         price,
+		AddedAt:       time.Now(),
+		EffectiveFrom: from,
+	}
+
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.HistoricalItemPrice
+		err := tx.Where("item_id = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", itemID, from, from).
+			Order("effective_from DESC").
+			First(&current).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Sin rango vigente: el nuevo rango termina donde empieza el siguiente cambio programado
+			var next models.HistoricalItemPrice
+			err := tx.Where("item_id = ? AND effective_from > ?", itemID, from).
+				Order("effective_from ASC").
+				First(&next).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if err == nil {
+				newRange.EffectiveTo = &next.EffectiveFrom
+			}
+			return tx.Create(newRange).Error
+		}
+
+		if current.EffectiveFrom.Equal(from) {
+			// Mismo instante de inicio: se reemplaza el precio del rango existente
+			if err := tx.Model(&current).Updates(map[string]interface{}{"price": price, "added_at": newRange.AddedAt}).Error; err != nil {
+				return err
+			}
+			*newRange = current
+			newRange.Price = price
+			return nil
+		}
+
+		newRange.EffectiveTo = current.EffectiveTo
+		if err := tx.Model(&current).Update("effective_to", from).Error; err != nil {
+			return err
+		}
+		return tx.Create(newRange).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newRange, nil
+}
+
+// DeletePriceRange elimina un rango de precio y extiende el rango anterior hasta
+// donde terminaba el eliminado.
+func (r *HistoricalItemPriceRepository) DeletePriceRange(ctx context.Context, price *models.HistoricalItemPrice) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.HistoricalItemPrice{}).
+			Where("item_id = ? AND effective_to = ?", price.ItemID, price.EffectiveFrom).
+			Update("effective_to", price.EffectiveTo).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.HistoricalItemPrice{}, price.ID).Error
+	})
+}
+
+// ApplyDuePrices actualiza el precio de venta de los ítems cuyo precio vigente según
+// el historial difiere del almacenado, lo que activa los cambios programados ya vencidos.
+func (r *HistoricalItemPriceRepository) ApplyDuePrices(ctx context.Context, at time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Exec(`UPDATE items SET selling_price = h.price
+		FROM historical_item_prices h
+		WHERE h.item_id = items.id
+		AND h.effective_from <= ? AND (h.effective_to IS NULL OR h.effective_to > ?)
+		AND items.selling_price <> h.price`, at, at)
+	return result.RowsAffected, result.Error
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,18 +16,25 @@ func NewIdentifierTypeRepository(db *gorm.DB) *IdentifierTypeRepository {
 	return &IdentifierTypeRepository{DB: db}
 }
 
-func (r *IdentifierTypeRepository) GetAllIdentifierTypes() ([]models.IdentifierType, error) {
-	var IdentifierTypes []models.IdentifierType
-	err := r.DB.Find(&IdentifierTypes).Error
+var identifierTypeList = listSpec{
+	Fields: map[string]string{
+		"id":   "id",
+		"name": "name",
+	},
+}
+
+func (r *IdentifierTypeRepository) GetAllIdentifierTypes(ctx context.Context, query dtos.ListQuery) ([]models.IdentifierType, *dtos.PageInfo, error) {
+	var identifierTypes []models.IdentifierType
+	page, err := paginate(r.DB.WithContext(ctx), query, identifierTypeList, &identifierTypes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return IdentifierTypes, nil
+	return identifierTypes, page, nil
 }
 
-func (r *IdentifierTypeRepository) GetIdentifierTypeByID(id string) (*models.IdentifierType, error) {
+func (r *IdentifierTypeRepository) GetIdentifierTypeByID(ctx context.Context, id string) (*models.IdentifierType, error) {
 	var IdentifierType models.IdentifierType
-	err := r.DB.First(&IdentifierType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&IdentifierType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type InvitationRepository struct {
+	DB *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
+	return &InvitationRepository{DB: db}
+}
+
+var invitationList = listSpec{
+	Fields: map[string]string{
+		"id":         "id",
+		"user_id":    "user_id",
+		"invited_by": "invited_by",
+		"expires_at": "expires_at",
+		"sent_at":    "sent_at",
+		"created_at": "created_at",
+	},
+	Sort:     []dtos.SortField{{Field: "created_at", Desc: true}},
+	Preloads: []string{"User"},
+}
+
+// GetPendingInvitations lista las invitaciones que todavía no se aceptaron,
+// vencidas o no.
+func (r *InvitationRepository) GetPendingInvitations(ctx context.Context, query dtos.ListQuery) ([]models.UserInvitation, *dtos.PageInfo, error) {
+	var invitations []models.UserInvitation
+	page, err := paginate(r.DB.WithContext(ctx).Where("accepted_at IS NULL"), query, invitationList, &invitations)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invitations, page, nil
+}
+
+func (r *InvitationRepository) GetInvitationByID(ctx context.Context, id string) (*models.UserInvitation, error) {
+	var invitation models.UserInvitation
+	err := r.DB.WithContext(ctx).Preload("User").First(&invitation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetValidInvitation busca por su hash una invitación sin aceptar y vigente.
+func (r *InvitationRepository) GetValidInvitation(ctx context.Context, tokenHash string, now time.Time) (*models.UserInvitation, error) {
+	var invitation models.UserInvitation
+	err := r.DB.WithContext(ctx).Preload("User").
+		Where("token_hash = ? AND accepted_at IS NULL AND expires_at > ?", tokenHash, now).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// CreateInvitation crea el usuario pendiente y su invitación.
+func (r *InvitationRepository) CreateInvitation(ctx context.Context, user *models.User, invitation *models.UserInvitation) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("UserType", "UserStateType").Create(user).Error; err != nil {
+			return err
+		}
+		invitation.UserID = user.ID
+		return tx.Omit("User").Create(invitation).Error
+	})
+}
+
+// RenewInvitation reemplaza el token y el vencimiento; el token anterior deja
+// de servir.
+func (r *InvitationRepository) RenewInvitation(ctx context.Context, id int, tokenHash string, expiresAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.UserInvitation{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"token_hash": tokenHash, "expires_at": expiresAt, "sent_at": nil}).Error
+}
+
+func (r *InvitationRepository) MarkSent(ctx context.Context, id int, sentAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.UserInvitation{}).Where("id = ?", id).Update("sent_at", sentAt).Error
+}
+
+// RevokeInvitation borra el usuario pendiente, y con él la invitación, para
+// que el correo quede libre. No borra nada si el usuario ya no está pendiente.
+func (r *InvitationRepository) RevokeInvitation(ctx context.Context, invitation *models.UserInvitation) (bool, error) {
+	result := r.DB.WithContext(ctx).Where("id = ? AND user_state_type_id = ?", invitation.UserID, models.UserStatePending).
+		Delete(&models.User{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// AcceptInvitation guarda la contraseña, activa al usuario y marca la
+// invitación como aceptada. Devuelve false si otra petición la aceptó antes.
+func (r *InvitationRepository) AcceptInvitation(ctx context.Context, invitation *models.UserInvitation, passwordHash string, now time.Time) (bool, error) {
+	accepted := false
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UserInvitation{}).Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		err := tx.Model(&models.User{}).Where("id = ? AND user_state_type_id = ?", invitation.UserID, models.UserStatePending).
+			Updates(map[string]interface{}{"password": passwordHash, "user_state_type_id": models.UserStateActive}).Error
+		if err != nil {
+			return err
+		}
+		accepted = true
+		return nil
+	})
+	return accepted, err
+}
@@ -1,12 +1,16 @@
 package repositories
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
+	"totesbackend/apperrors"
 	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type InvoiceRepository struct {
@@ -17,35 +21,49 @@ func NewInvoiceRepository(db *gorm.DB) *InvoiceRepository {
 	return &InvoiceRepository{DB: db}
 }
 
-func (r *InvoiceRepository) GetInvoiceByID(id string) (*models.Invoice, error) {
+func (r *InvoiceRepository) GetInvoiceByID(ctx context.Context, id string) (*models.Invoice, error) {
 	var invoice models.Invoice
-	err := r.DB.Preload("Customer").
+	err := r.DB.WithContext(ctx).Preload("Customer").
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
-		Find(&invoice).Error
+		First(&invoice, "id = ?", id).Error
 	if err != nil {
-		return nil, errors.New("invoice not found")
+		return nil, err
 	}
 	return &invoice, nil
 }
 
-func (r *InvoiceRepository) GetAllInvoices() ([]models.Invoice, error) {
+var invoiceList = listSpec{
+	Fields: map[string]string{
+		"id":                "id",
+		"enterprise_data":   "enterprise_data",
+		"date_time":         "date_time",
+		"customer_id":       "customer_id",
+		"subtotal":          "subtotal",
+		"discount_total":    "discount_total",
+		"tax_total":         "tax_total",
+		"total":             "total",
+		"purchase_order_id": "purchase_order_id",
+	},
+	Preloads: []string{"Customer", "Items.Item", "Discounts", "Taxes"},
+}
+
+func (r *InvoiceRepository) GetAllInvoices(ctx context.Context, query dtos.ListQuery) ([]models.Invoice, *dtos.PageInfo, error) {
 	var invoices []models.Invoice
-	err := r.DB.Preload("Customer").
-		Preload("Items.Item").
-		Preload("Discounts").
-		Preload("Taxes").
-		Find(&invoices).Error
+	page, err := paginate(r.DB.WithContext(ctx), query, invoiceList, &invoices)
 	if err != nil {
-		return nil, errors.New("error retrieving invoices")
+		if isListQueryError(err) {
+			return nil, nil, err
+		}
+		return nil, nil, errors.New("error retrieving invoices")
 	}
-	return invoices, nil
+	return invoices, page, nil
 }
 
-func (r *InvoiceRepository) GetInvoicesByDateRange(startDate, endDate time.Time) ([]models.Invoice, error) {
+func (r *InvoiceRepository) GetInvoicesByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Invoice, error) {
 	var invoices []models.Invoice
-	err := r.DB.Preload("Customer").
+	err := r.DB.WithContext(ctx).Preload("Customer").
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
@@ -57,9 +75,9 @@ func (r *InvoiceRepository) GetInvoicesByDateRange(startDate, endDate time.Time)
 	return invoices, nil
 }
 
-func (r *InvoiceRepository) SearchInvoiceByID(query string) ([]models.Invoice, error) {
+func (r *InvoiceRepository) SearchInvoiceByID(ctx context.Context, query string) ([]models.Invoice, error) {
 	var invoices []models.Invoice
-	err := r.DB.Preload("Customer").Preload("Items.Item").Preload("Discounts").Preload("Taxes").
+	err := r.DB.WithContext(ctx).Preload("Customer").Preload("Items.Item").Preload("Discounts").Preload("Taxes").
 		Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&invoices).Error
 
 	if err != nil {
@@ -68,9 +86,9 @@ func (r *InvoiceRepository) SearchInvoiceByID(query string) ([]models.Invoice, e
 	return invoices, nil
 }
 
-func (r *InvoiceRepository) SearchInvoiceByCustomerPersonalId(query string) ([]models.Invoice, error) {
+func (r *InvoiceRepository) SearchInvoiceByCustomerPersonalId(ctx context.Context, query string) ([]models.Invoice, error) {
 	var invoices []models.Invoice
-	err := r.DB.Preload("Customer").
+	err := r.DB.WithContext(ctx).Preload("Customer").
 		Preload("Items.Item").
 		Preload("Discounts").
 		Preload("Taxes").
@@ -83,20 +101,65 @@ func (r *InvoiceRepository) SearchInvoiceByCustomerPersonalId(query string) ([]m
 	}
 	return invoices, nil
 }
-func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal float64, total float64) (*models.Invoice, error) {
+
+// InvoiceTotals son los importes calculados de una factura.
+type InvoiceTotals struct {
+	Subtotal      float64
+	DiscountTotal float64
+	TaxTotal      float64
+	Total         float64
+}
+
+// FindInvoicesInBatches recorre todas las facturas, con sus líneas e impuestos,
+// en lotes de batchSize.
+func (r *InvoiceRepository) FindInvoicesInBatches(ctx context.Context, batchSize int, fn func([]models.Invoice) error) error {
+	var invoices []models.Invoice
+	return r.DB.WithContext(ctx).Preload("Items").Preload("Taxes").
+		FindInBatches(&invoices, batchSize, func(tx *gorm.DB, batch int) error {
+			return fn(invoices)
+		}).Error
+}
+
+func (r *InvoiceRepository) UpdateInvoiceTotals(ctx context.Context, id int, totals InvoiceTotals) error {
+	return r.DB.WithContext(ctx).Model(&models.Invoice{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"subtotal":       totals.Subtotal,
+		"discount_total": totals.DiscountTotal,
+		"tax_total":      totals.TaxTotal,
+		"total":          totals.Total,
+	}).Error
+}
+
+func (r *InvoiceRepository) CreateInvoice(ctx context.Context, dto *dtos.CreateInvoiceDTO, totals InvoiceTotals) (*models.Invoice, error) {
 	invoice := &models.Invoice{
-		EnterpriseData: dto.EnterpriseData,
-		DateTime:       time.Now(),
-		CustomerID:     dto.CustomerID,
-		Subtotal:       subtotal,
-		Total:          total,
+		EnterpriseData:  dto.EnterpriseData,
+		DateTime:        time.Now(),
+		CustomerID:      dto.CustomerID,
+		Subtotal:        totals.Subtotal,
+		DiscountTotal:   totals.DiscountTotal,
+		TaxTotal:        totals.TaxTotal,
+		Total:           totals.Total,
+		PurchaseOrderID: dto.PurchaseOrderID,
 	}
 
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
 
+	// Cargar los descuentos y revisar sus límites de uso con el cliente
+	// bloqueado, antes de tocar el stock
+	var discounts []models.DiscountType
+	if len(dto.Discounts) > 0 {
+		if err := tx.Where("id IN ?", dto.Discounts).Find(&discounts).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := checkDiscountUsageLimits(tx, dto.CustomerID, discounts); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// Restar stock de los Items
 	for _, billingItem := range dto.Items {
 		if err := tx.Model(&models.Item{}).
@@ -119,6 +182,7 @@ func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal f
 			InvoiceID: invoice.ID,
 			ItemID:    billingItem.ID,
 			Amount:    billingItem.Stock,
+			UnitPrice: billingItem.UnitPrice,
 		}
 
 		if err := tx.Create(invoiceItem).Error; err != nil {
@@ -128,12 +192,7 @@ func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal f
 	}
 
 	// Registrar descuentos en la relación many-to-many
-	var discounts []models.DiscountType
-	if len(dto.Discounts) > 0 {
-		if err := tx.Where("id IN ?", dto.Discounts).Find(&discounts).Error; err != nil {
-			tx.Rollback()
-			return nil, err
-		}
+	if len(discounts) > 0 {
 		if err := tx.Model(invoice).Association("Discounts").Append(discounts); err != nil {
 			tx.Rollback()
 			return nil, err
@@ -160,7 +219,7 @@ func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal f
 
 	// Cargar Items con Join
 	var fullInvoice models.Invoice
-	if err := r.DB.
+	if err := r.DB.WithContext(ctx).
 		Preload("Discounts").
 		Preload("Taxes").
 		Preload("Items.Item"). // Carga los items y sus productos
@@ -171,20 +230,68 @@ func (r *InvoiceRepository) CreateInvoice(dto *dtos.CreateInvoiceDTO, subtotal f
 	return &fullInvoice, nil
 }
 
-func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(dto *dtos.CreateInvoiceDTO, subtotal float64, total float64) (*models.Invoice, error) {
+// checkDiscountUsageLimits revisa, dentro de la transacción de la factura, que
+// el cliente no haya alcanzado el límite de usos de los descuentos. Bloquea al
+// cliente hasta el fin de tx para que dos facturas simultáneas suyas no
+// superen el límite: la segunda espera a la primera y cuenta su uso.
+func checkDiscountUsageLimits(tx *gorm.DB, customerID int, discounts []models.DiscountType) error {
+	locked := false
+	for _, discount := range discounts {
+		if discount.MaxUsesPerCustomer <= 0 {
+			continue
+		}
+		if !locked {
+			var customer models.Customer
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&customer, customerID).Error; err != nil {
+				return err
+			}
+			locked = true
+		}
+
+		uses, err := countCustomerUsages(tx, discount.ID, customerID)
+		if err != nil {
+			return err
+		}
+		if uses >= int64(discount.MaxUsesPerCustomer) {
+			return fmt.Errorf("%w: customer already used discount %d %d time(s) (usage_limit_reached)",
+				apperrors.ErrDiscountRejected.WithDetail("discount_type_id", discount.ID), discount.ID, uses)
+		}
+	}
+	return nil
+}
+
+func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(ctx context.Context, dto *dtos.CreateInvoiceDTO, totals InvoiceTotals) (*models.Invoice, error) {
 	invoice := &models.Invoice{
-		EnterpriseData: dto.EnterpriseData,
-		DateTime:       time.Now(),
-		CustomerID:     dto.CustomerID,
-		Subtotal:       subtotal,
-		Total:          total,
+		EnterpriseData:  dto.EnterpriseData,
+		DateTime:        time.Now(),
+		CustomerID:      dto.CustomerID,
+		Subtotal:        totals.Subtotal,
+		DiscountTotal:   totals.DiscountTotal,
+		TaxTotal:        totals.TaxTotal,
+		Total:           totals.Total,
+		PurchaseOrderID: dto.PurchaseOrderID,
 	}
 
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
 
+	// Cargar los descuentos y revisar sus límites de uso con el cliente
+	// bloqueado, igual que en CreateInvoice: la orden de compra aprobada no
+	// debe permitir superar MaxUsesPerCustomer
+	var discounts []models.DiscountType
+	if len(dto.Discounts) > 0 {
+		if err := tx.Where("id IN ?", dto.Discounts).Find(&discounts).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if err := checkDiscountUsageLimits(tx, dto.CustomerID, discounts); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// NO se resta el stock de los Items aquí
 
 	// Crear Invoice
@@ -199,6 +306,7 @@ func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(dto *dtos.CreateI
 			InvoiceID: invoice.ID,
 			ItemID:    billingItem.ID,
 			Amount:    billingItem.Stock,
+			UnitPrice: billingItem.UnitPrice,
 		}
 
 		if err := tx.Create(invoiceItem).Error; err != nil {
@@ -208,12 +316,7 @@ func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(dto *dtos.CreateI
 	}
 
 	// Registrar descuentos en la relación many-to-many
-	var discounts []models.DiscountType
-	if len(dto.Discounts) > 0 {
-		if err := tx.Where("id IN ?", dto.Discounts).Find(&discounts).Error; err != nil {
-			tx.Rollback()
-			return nil, err
-		}
+	if len(discounts) > 0 {
 		if err := tx.Model(invoice).Association("Discounts").Append(discounts); err != nil {
 			tx.Rollback()
 			return nil, err
@@ -240,7 +343,7 @@ func (r *InvoiceRepository) CreateInvoiceWithoutStockReduction(dto *dtos.CreateI
 
 	// Cargar Items con Join
 	var fullInvoice models.Invoice
-	if err := r.DB.
+	if err := r.DB.WithContext(ctx).
 		Preload("Discounts").
 		Preload("Taxes").
 		Preload("Items.Item").
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,36 +16,49 @@ func NewItemRepository(db *gorm.DB) *ItemRepository {
 	return &ItemRepository{DB: db}
 }
 
-func (r *ItemRepository) GetItemByID(id string) (*models.Item, error) {
+func (r *ItemRepository) GetItemByID(ctx context.Context, id string) (*models.Item, error) {
 	var item models.Item
-	err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").First(&item, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").First(&item, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
 
-func (r *ItemRepository) HasEnoughStock(id string, quantity int) (bool, error) {
+func (r *ItemRepository) HasEnoughStock(ctx context.Context, id string, quantity int) (bool, error) {
 	var stock int
-	err := r.DB.Model(&models.Item{}).Select("stock").Where("id = ?", id).Scan(&stock).Error
+	err := r.DB.WithContext(ctx).Model(&models.Item{}).Select("stock").Where("id = ?", id).Scan(&stock).Error
 	if err != nil {
 		return false, err
 	}
 	return stock >= quantity, nil
 }
 
-func (r *ItemRepository) GetAllItems() ([]models.Item, error) {
+var itemList = listSpec{
+	Fields: map[string]string{
+		"id":             "id",
+		"name":           "name",
+		"stock":          "stock",
+		"selling_price":  "selling_price",
+		"purchase_price": "purchase_price",
+		"item_state":     "item_state",
+		"item_type_id":   "item_type_id",
+	},
+	Preloads: []string{"ItemType", "AdditionalExpenses"},
+}
+
+func (r *ItemRepository) GetAllItems(ctx context.Context, query dtos.ListQuery) ([]models.Item, *dtos.PageInfo, error) {
 	var items []models.Item
-	err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").Find(&items).Error
+	page, err := paginate(r.DB.WithContext(ctx), query, itemList, &items)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return items, nil
+	return items, page, nil
 }
 
-func (r *ItemRepository) SearchItemsByID(query string) ([]models.Item, error) {
+func (r *ItemRepository) SearchItemsByID(ctx context.Context, query string) ([]models.Item, error) {
 	var items []models.Item
-	err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").
+	err := r.DB.WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").
 		Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&items).Error
 	if err != nil {
 		return nil, err
@@ -51,9 +66,9 @@ func (r *ItemRepository) SearchItemsByID(query string) ([]models.Item, error) {
 	return items, nil
 }
 
-func (r *ItemRepository) SearchItemsByName(query string) ([]models.Item, error) {
+func (r *ItemRepository) SearchItemsByName(ctx context.Context, query string) ([]models.Item, error) {
 	var items []models.Item
-	err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").
+	err := r.DB.WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").
 		Where("LOWER(name) LIKE LOWER(?)", query+"%").
 		Find(&items).Error
 	if err != nil {
@@ -62,24 +77,24 @@ func (r *ItemRepository) SearchItemsByName(query string) ([]models.Item, error)
 	return items, nil
 }
 
-func (r *ItemRepository) UpdateItemState(id string, state bool) (*models.Item, error) {
+func (r *ItemRepository) UpdateItemState(ctx context.Context, id string, state bool) (*models.Item, error) {
 	var item models.Item
-	if err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").First(&item, "id = ?", id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").First(&item, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
 	item.ItemState = state
 
-	if err := r.DB.Save(&item).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Save(&item).Error; err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
 
-func (r *ItemRepository) UpdateItem(item *models.Item) (bool, error) {
+func (r *ItemRepository) UpdateItem(ctx context.Context, item *models.Item) (bool, error) {
 
 	var existingItem models.Item
-	if err := r.DB.Preload("ItemType").Preload("AdditionalExpenses").First(&existingItem, "id = ?", item.ID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("ItemType").Preload("AdditionalExpenses").First(&existingItem, "id = ?", item.ID).Error; err != nil {
 		return false, err
 	}
 
@@ -87,27 +102,27 @@ func (r *ItemRepository) UpdateItem(item *models.Item) (bool, error) {
 
 	existingItem.ItemTypeID = item.ItemTypeID
 
-	if err := r.DB.Model(&existingItem).Updates(item).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&existingItem).Updates(item).Error; err != nil {
 		return false, err
 	}
 
-	if err := r.DB.Model(&existingItem).Select("ItemState").Updates(item).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&existingItem).Select("ItemState").Updates(item).Error; err != nil {
 		return false, err
 	}
 
 	return priceChanged, nil
 }
 
-func (r *ItemRepository) CreateItem(item *models.Item) (*models.Item, error) {
+func (r *ItemRepository) CreateItem(ctx context.Context, item *models.Item) (*models.Item, error) {
 
-	if err := r.DB.Create(item).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Create(item).Error; err != nil {
 		return nil, err
 	}
 	return item, nil
 }
 
-func (r *ItemRepository) SubtractItemsFromInventory(itemID string, amount int) error {
-	if err := r.DB.Model(&models.Item{}).
+func (r *ItemRepository) SubtractItemsFromInventory(ctx context.Context, itemID string, amount int) error {
+	if err := r.DB.WithContext(ctx).Model(&models.Item{}).
 		Where("id = ?", itemID).
 		UpdateColumn("stock", gorm.Expr("stock - ?", amount)).Error; err != nil {
 		return err
@@ -115,8 +130,8 @@ func (r *ItemRepository) SubtractItemsFromInventory(itemID string, amount int) e
 	return nil
 }
 
-func (r *ItemRepository) ReturnItemsToInventory(itemID string, amount int) error {
-	if err := r.DB.Model(&models.Item{}).
+func (r *ItemRepository) ReturnItemsToInventory(ctx context.Context, itemID string, amount int) error {
+	if err := r.DB.WithContext(ctx).Model(&models.Item{}).
 		Where("id = ?", itemID).
 		UpdateColumn("stock", gorm.Expr("stock + ?", amount)).Error; err != nil {
 		return err
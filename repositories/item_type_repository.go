@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,15 +16,25 @@ func NewItemTypeRepository(db *gorm.DB) *ItemTypeRepository {
 	return &ItemTypeRepository{DB: db}
 }
 
-func (r *ItemTypeRepository) GetAllItemTypes() ([]models.ItemType, error) {
+var itemTypeList = listSpec{
+	Fields: map[string]string{
+		"id":   "id",
+		"name": "name",
+	},
+}
+
+func (r *ItemTypeRepository) GetAllItemTypes(ctx context.Context, query dtos.ListQuery) ([]models.ItemType, *dtos.PageInfo, error) {
 	var itemTypes []models.ItemType
-	err := r.DB.Find(&itemTypes).Error
-	return itemTypes, err
+	page, err := paginate(r.DB.WithContext(ctx), query, itemTypeList, &itemTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return itemTypes, page, nil
 }
 
-func (r *ItemTypeRepository) GetItemTypeByID(id string) (*models.ItemType, error) {
+func (r *ItemTypeRepository) GetItemTypeByID(ctx context.Context, id string) (*models.ItemType, error) {
 	var itemType models.ItemType
-	err := r.DB.First(&itemType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&itemType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,353 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/dtos"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+// listSpec describe cómo se lista un modelo: los campos por los que se puede
+// filtrar y ordenar (nombre en la API -> columna), el orden por defecto y las
+// relaciones que se cargan con cada fila. Los campos opcionales sólo se pueden
+// filtrar, porque un NULL en la columna de orden cortaría el cursor.
+type listSpec struct {
+	Fields   map[string]string
+	Sort     []dtos.SortField
+	Preloads []string
+}
+
+// listCursor es el contenido del cursor: el orden con el que se generó y los
+// valores de las columnas de orden de la última fila entregada.
+type listCursor struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+// paginate aplica los filtros, el orden y la paginación de query sobre db y
+// carga en dest (puntero a slice del modelo) la página pedida. El total se
+// cuenta antes de paginar y las relaciones se cargan sólo para la página.
+func paginate(db *gorm.DB, query dtos.ListQuery, spec listSpec, dest interface{}) (*dtos.PageInfo, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(dest); err != nil {
+		return nil, err
+	}
+	modelSchema := stmt.Schema
+	if modelSchema.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("model %s has no primary key to paginate by", modelSchema.Name)
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		limit = MaxListLimit
+	}
+	if query.Page > 0 && query.Cursor != "" {
+		return nil, fmt.Errorf("%w: page and cursor cannot be combined", dtos.ErrInvalidListQuery)
+	}
+
+	tx := db.Model(dest)
+	for _, filter := range query.Filters {
+		condition, err := filterCondition(modelSchema, spec, filter)
+		if err != nil {
+			return nil, err
+		}
+		tx = tx.Where(condition)
+	}
+
+	sortFields, err := sortColumns(modelSchema, spec, query.Sort)
+	if err != nil {
+		return nil, err
+	}
+	sortKey := sortSignature(sortFields)
+
+	var total int64
+	if err := tx.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	tx = tx.Session(&gorm.Session{})
+	if query.Cursor != "" {
+		condition, err := cursorCondition(sortFields, sortKey, query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		tx = tx.Where(condition)
+	}
+	for _, field := range sortFields {
+		tx = tx.Order(clause.OrderByColumn{Column: currentColumn(field.column.DBName), Desc: field.desc})
+	}
+	if query.Page > 1 {
+		tx = tx.Offset((query.Page - 1) * limit)
+	}
+	for _, preload := range spec.Preloads {
+		tx = tx.Preload(preload)
+	}
+
+	// Se pide una fila de más para saber si hay una página siguiente.
+	if err := tx.Limit(limit + 1).Find(dest).Error; err != nil {
+		return nil, err
+	}
+
+	page := &dtos.PageInfo{Total: total, Limit: limit}
+	rows := reflect.ValueOf(dest).Elem()
+	if rows.Len() > limit {
+		rows.Set(rows.Slice(0, limit))
+		page.NextCursor, err = encodeCursor(sortFields, sortKey, rows.Index(limit-1))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+type sortColumn struct {
+	name   string
+	column *schema.Field
+	desc   bool
+}
+
+// sortColumns resuelve el orden pedido (o el de la especificación) y le agrega
+// la llave primaria para que el orden sea total y el cursor no repita filas.
+func sortColumns(modelSchema *schema.Schema, spec listSpec, sort []dtos.SortField) ([]sortColumn, error) {
+	if len(sort) == 0 {
+		sort = spec.Sort
+	}
+
+	primaryKey := modelSchema.PrioritizedPrimaryField
+	var columns []sortColumn
+	hasPrimaryKey := false
+	for _, field := range sort {
+		column, err := listColumn(modelSchema, spec, field.Field)
+		if err != nil {
+			return nil, err
+		}
+		if column.FieldType.Kind() == reflect.Ptr {
+			return nil, fmt.Errorf("%w: optional field %q can be filtered but not sorted", dtos.ErrInvalidListQuery, field.Field)
+		}
+		hasPrimaryKey = hasPrimaryKey || column == primaryKey
+		columns = append(columns, sortColumn{name: field.Field, column: column, desc: field.Desc})
+	}
+	if !hasPrimaryKey {
+		columns = append(columns, sortColumn{name: primaryKey.DBName, column: primaryKey})
+	}
+	return columns, nil
+}
+
+func sortSignature(columns []sortColumn) string {
+	parts := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column.desc {
+			parts = append(parts, "-"+column.name)
+		} else {
+			parts = append(parts, column.name)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func listColumn(modelSchema *schema.Schema, spec listSpec, name string) (*schema.Field, error) {
+	columnName, ok := spec.Fields[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown field %q", dtos.ErrInvalidListQuery, name)
+	}
+	column := modelSchema.LookUpField(columnName)
+	if column == nil {
+		return nil, fmt.Errorf("column %s not found in %s", columnName, modelSchema.Name)
+	}
+	return column, nil
+}
+
+func currentColumn(name string) clause.Column {
+	return clause.Column{Table: clause.CurrentTable, Name: name}
+}
+
+// likeEscaper escapa los comodines de LIKE para que el valor del filtro se
+// busque literalmente.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func filterCondition(modelSchema *schema.Schema, spec listSpec, filter dtos.ListFilter) (clause.Expression, error) {
+	column, err := listColumn(modelSchema, spec, filter.Field)
+	if err != nil {
+		return nil, err
+	}
+	target := currentColumn(column.DBName)
+
+	if filter.Operator == dtos.FilterLike {
+		if indirectType(column.FieldType).Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: operator like is only allowed on text fields", dtos.ErrInvalidListQuery)
+		}
+		return clause.Expr{SQL: `? ILIKE ? ESCAPE '\'`, Vars: []interface{}{target, "%" + likeEscaper.Replace(filter.Value) + "%"}}, nil
+	}
+
+	if filter.Operator == dtos.FilterIn {
+		var values []interface{}
+		for _, raw := range strings.Split(filter.Value, ",") {
+			value, err := filterValue(column, filter.Field, raw)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return clause.IN{Column: target, Values: values}, nil
+	}
+
+	value, err := filterValue(column, filter.Field, filter.Value)
+	if err != nil {
+		return nil, err
+	}
+	switch filter.Operator {
+	case dtos.FilterEq, "":
+		return clause.Eq{Column: target, Value: value}, nil
+	case dtos.FilterNe:
+		return clause.Neq{Column: target, Value: value}, nil
+	case dtos.FilterGt:
+		return clause.Gt{Column: target, Value: value}, nil
+	case dtos.FilterGte:
+		return clause.Gte{Column: target, Value: value}, nil
+	case dtos.FilterLt:
+		return clause.Lt{Column: target, Value: value}, nil
+	case dtos.FilterLte:
+		return clause.Lte{Column: target, Value: value}, nil
+	}
+	return nil, fmt.Errorf("%w: unknown operator %q", dtos.ErrInvalidListQuery, filter.Operator)
+}
+
+// filterValue convierte el valor de un filtro al tipo de la columna para que
+// la base de datos lo compare sin conversiones implícitas.
+func filterValue(column *schema.Field, name, raw string) (interface{}, error) {
+	fieldType := indirectType(column.FieldType)
+	invalid := fmt.Errorf("%w: invalid value %q for field %q", dtos.ErrInvalidListQuery, raw, name)
+
+	if fieldType == reflect.TypeOf(time.Time{}) {
+		if value, err := time.Parse(time.RFC3339, raw); err == nil {
+			return value, nil
+		}
+		if value, err := time.Parse("2006-01-02", raw); err == nil {
+			return value, nil
+		}
+		return nil, invalid
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, invalid
+		}
+		return value, nil
+	}
+	return nil, fmt.Errorf("%w: field %q cannot be filtered", dtos.ErrInvalidListQuery, name)
+}
+
+func indirectType(fieldType reflect.Type) reflect.Type {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+	return fieldType
+}
+
+func encodeCursor(columns []sortColumn, sortKey string, row reflect.Value) (string, error) {
+	cursor := listCursor{Sort: sortKey}
+	for _, column := range columns {
+		value, _ := column.column.ValueOf(context.Background(), row)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		cursor.Values = append(cursor.Values, raw)
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// cursorCondition arma la condición que deja sólo las filas posteriores al
+// cursor según el orden: (a > va) OR (a = va AND b > vb) OR ...
+func cursorCondition(columns []sortColumn, sortKey, encoded string) (clause.Expression, error) {
+	invalid := fmt.Errorf("%w: invalid cursor", dtos.ErrInvalidListQuery)
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+	var cursor listCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || len(cursor.Values) != len(columns) {
+		return nil, invalid
+	}
+	if cursor.Sort != sortKey {
+		return nil, fmt.Errorf("%w: cursor was generated with sort %q", dtos.ErrInvalidListQuery, cursor.Sort)
+	}
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		value := reflect.New(column.column.FieldType)
+		if err := json.Unmarshal(cursor.Values[i], value.Interface()); err != nil {
+			return nil, invalid
+		}
+		values[i] = value.Elem().Interface()
+	}
+
+	var alternatives []string
+	var vars []interface{}
+	for i, column := range columns {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, "? = ?")
+			vars = append(vars, currentColumn(columns[j].column.DBName), values[j])
+		}
+		if column.desc {
+			parts = append(parts, "? < ?")
+		} else {
+			parts = append(parts, "? > ?")
+		}
+		vars = append(vars, currentColumn(column.column.DBName), values[i])
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+	return clause.Expr{SQL: "(" + strings.Join(alternatives, " OR ") + ")", Vars: vars}, nil
+}
+
+// isListQueryError indica si err se debe a parámetros de listado inválidos y
+// no a un fallo de la base de datos.
+func isListQueryError(err error) bool {
+	return errors.Is(err, dtos.ErrInvalidListQuery)
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository struct {
+	DB *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{DB: db}
+}
+
+func (r *LoginAttemptRepository) CreateLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error {
+	return r.DB.WithContext(ctx).Create(attempt).Error
+}
+
+// AccountFailures cuenta los intentos fallidos de la cuenta posteriores a
+// since y al último inicio de sesión exitoso, y devuelve la fecha del último.
+func (r *LoginAttemptRepository) AccountFailures(ctx context.Context, email string, since time.Time) (int64, *time.Time, error) {
+	var lastSuccess struct {
+		Last *time.Time
+	}
+	err := r.DB.WithContext(ctx).Model(&models.LoginAttempt{}).
+		Select("MAX(attempted_at) AS last").
+		Where("email = ? AND success = ?", email, true).
+		Scan(&lastSuccess).Error
+	if err != nil {
+		return 0, nil, err
+	}
+	if lastSuccess.Last != nil && lastSuccess.Last.After(since) {
+		since = *lastSuccess.Last
+	}
+	return r.failuresSince(ctx, "email", email, since)
+}
+
+// IPFailures cuenta los intentos fallidos desde la IP posteriores a since. Un
+// inicio de sesión exitoso no los reinicia: varias personas pueden compartir IP.
+func (r *LoginAttemptRepository) IPFailures(ctx context.Context, ip string, since time.Time) (int64, *time.Time, error) {
+	return r.failuresSince(ctx, "ip", ip, since)
+}
+
+func (r *LoginAttemptRepository) failuresSince(ctx context.Context, column, value string, since time.Time) (int64, *time.Time, error) {
+	var stats struct {
+		Count int64
+		Last  *time.Time
+	}
+	err := r.DB.WithContext(ctx).Model(&models.LoginAttempt{}).
+		Select("COUNT(*) AS count, MAX(attempted_at) AS last").
+		Where(column+" = ? AND success = ? AND attempted_at > ?", value, false, since).
+		Scan(&stats).Error
+	if err != nil {
+		return 0, nil, err
+	}
+	return stats.Count, stats.Last, nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type MarginRepository struct {
+	DB *gorm.DB
+}
+
+func NewMarginRepository(db *gorm.DB) *MarginRepository {
+	return &MarginRepository{DB: db}
+}
+
+// GetInvoicedLines devuelve las líneas de las facturas emitidas en el rango de fechas,
+// con el ítem, su tipo y sus gastos adicionales.
+func (r *MarginRepository) GetInvoicedLines(ctx context.Context, startDate, endDate time.Time) ([]models.InvoiceItem, error) {
+	var lines []models.InvoiceItem
+	err := r.DB.WithContext(ctx).Preload("Invoice").
+		Preload("Item.ItemType").
+		Preload("Item.AdditionalExpenses").
+		Joins("JOIN invoices ON invoices.id = invoice_items.invoice_id").
+		Where("invoices.date_time BETWEEN ? AND ?", startDate, endDate).
+		Order("invoices.date_time").
+		Find(&lines).Error
+	return lines, err
+}
+
+// GetInvoicedUnits devuelve, por ítem, la cantidad total de unidades facturadas.
+func (r *MarginRepository) GetInvoicedUnits(ctx context.Context, itemIDs []int) (map[int]int, error) {
+	var rows []struct {
+		ItemID int
+		Units  int
+	}
+	err := r.DB.WithContext(ctx).Model(&models.InvoiceItem{}).
+		Select("item_id, COALESCE(SUM(amount), 0) AS units").
+		Where("item_id IN ?", itemIDs).
+		Group("item_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	units := make(map[int]int, len(rows))
+	for _, row := range rows {
+		units[row.ItemID] = row.Units
+	}
+	return units, nil
+}
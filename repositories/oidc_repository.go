@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OIDCRepository struct {
+	DB *gorm.DB
+}
+
+func NewOIDCRepository(db *gorm.DB) *OIDCRepository {
+	return &OIDCRepository{DB: db}
+}
+
+// CreateAuthRequest guarda el inicio de sesión pendiente y borra los vencidos.
+func (r *OIDCRepository) CreateAuthRequest(ctx context.Context, request *models.OIDCAuthRequest) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("expires_at <= ?", request.CreatedAt).Delete(&models.OIDCAuthRequest{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(request).Error
+	})
+}
+
+// ConsumeAuthRequest borra y devuelve el inicio de sesión pendiente, de modo
+// que cada state sirve una sola vez. Devuelve gorm.ErrRecordNotFound si no
+// existe o ya venció.
+func (r *OIDCRepository) ConsumeAuthRequest(ctx context.Context, stateHash string, now time.Time) (*models.OIDCAuthRequest, error) {
+	var requests []models.OIDCAuthRequest
+	err := r.DB.WithContext(ctx).Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).Delete(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 || !now.Before(requests[0].ExpiresAt) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &requests[0], nil
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,20 +16,27 @@ func NewOrderStateTypeRepository(db *gorm.DB) *OrderStateTypeRepository {
 	return &OrderStateTypeRepository{DB: db}
 }
 
-func (r *OrderStateTypeRepository) GetOrderStateTypeByID(id string) (*models.OrderStateType, error) {
+func (r *OrderStateTypeRepository) GetOrderStateTypeByID(ctx context.Context, id string) (*models.OrderStateType, error) {
 	var OrderStateType models.OrderStateType
-	err := r.DB.First(&OrderStateType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&OrderStateType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &OrderStateType, nil
 }
 
-func (r *OrderStateTypeRepository) GetAllOrderStateTypes() ([]models.OrderStateType, error) {
-	var OrderStateTypes []models.OrderStateType
-	err := r.DB.Find(&OrderStateTypes).Error
+var orderStateTypeList = listSpec{
+	Fields: map[string]string{
+		"id":          "id",
+		"description": "description",
+	},
+}
+
+func (r *OrderStateTypeRepository) GetAllOrderStateTypes(ctx context.Context, query dtos.ListQuery) ([]models.OrderStateType, *dtos.PageInfo, error) {
+	var orderStateTypes []models.OrderStateType
+	page, err := paginate(r.DB.WithContext(ctx), query, orderStateTypeList, &orderStateTypes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return OrderStateTypes, nil
+	return orderStateTypes, page, nil
 }
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type PasswordRepository struct {
+	DB *gorm.DB
+}
+
+func NewPasswordRepository(db *gorm.DB) *PasswordRepository {
+	return &PasswordRepository{DB: db}
+}
+
+// GetPasswordHistory devuelve los últimos limit hashes anteriores del usuario,
+// del más reciente al más antiguo.
+func (r *PasswordRepository) GetPasswordHistory(ctx context.Context, userID int, limit int) ([]models.PasswordHistory, error) {
+	var history []models.PasswordHistory
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").Limit(limit).Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// SetPassword reemplaza la contraseña del usuario por newHash. Con keep > 0 el
+// hash anterior pasa al historial, que se recorta a los keep más recientes.
+// Los tokens de restablecimiento pendientes dejan de servir.
+func (r *PasswordRepository) SetPassword(ctx context.Context, user *models.User, newHash string, keep int) error {
+	now := time.Now()
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if keep > 0 {
+			entry := models.PasswordHistory{UserID: user.ID, PasswordHash: user.Password, CreatedAt: now}
+			if err := tx.Create(&entry).Error; err != nil {
+				return err
+			}
+			stale := tx.Model(&models.PasswordHistory{}).Select("id").Where("user_id = ?", user.ID).
+				Order("created_at DESC, id DESC").Offset(keep)
+			if err := tx.Where("id IN (?)", stale).Delete(&models.PasswordHistory{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.User{}).Where("id = ?", user.ID).Update("password", newHash).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.PasswordResetToken{}).Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", now).Error
+	})
+}
+
+func (r *PasswordRepository) CreateResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	return r.DB.WithContext(ctx).Create(token).Error
+}
+
+// GetValidResetToken busca un token sin usar y vigente por su hash.
+func (r *PasswordRepository) GetValidResetToken(ctx context.Context, tokenHash string, now time.Time) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.DB.WithContext(ctx).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,38 +16,45 @@ func NewPermissionRepository(db *gorm.DB) *PermissionRepository {
 	return &PermissionRepository{DB: db}
 }
 
-func (r *PermissionRepository) GetPermissionByID(id uint) (*models.Permission, error) {
+func (r *PermissionRepository) GetPermissionByID(ctx context.Context, id uint) (*models.Permission, error) {
 	var permission models.Permission
-	err := r.DB.First(&permission, id).Error
+	err := r.DB.WithContext(ctx).First(&permission, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &permission, nil
 }
 
-func (r *PermissionRepository) SearchPermissionsByID(query string) ([]models.Permission, error) {
+func (r *PermissionRepository) SearchPermissionsByID(ctx context.Context, query string) ([]models.Permission, error) {
 	var permissions []models.Permission
-	err := r.DB.Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&permissions).Error
+	err := r.DB.WithContext(ctx).Where("CAST(id AS TEXT) LIKE ?", query+"%").Find(&permissions).Error
 	if err != nil {
 		return nil, err
 	}
 	return permissions, nil
 }
 
-func (r *PermissionRepository) SearchPermissionsByName(query string) ([]models.Permission, error) {
+func (r *PermissionRepository) SearchPermissionsByName(ctx context.Context, query string) ([]models.Permission, error) {
 	var permissions []models.Permission
-	err := r.DB.Where("LOWER(name) LIKE LOWER(?)", query+"%").Find(&permissions).Error
+	err := r.DB.WithContext(ctx).Where("LOWER(name) LIKE LOWER(?)", query+"%").Find(&permissions).Error
 	if err != nil {
 		return nil, err
 	}
 	return permissions, nil
 }
 
-func (r *PermissionRepository) GetAllPermissions() ([]models.Permission, error) {
+var permissionList = listSpec{
+	Fields: map[string]string{
+		"id":   "id",
+		"name": "name",
+	},
+}
+
+func (r *PermissionRepository) GetAllPermissions(ctx context.Context, query dtos.ListQuery) ([]models.Permission, *dtos.PageInfo, error) {
 	var permissions []models.Permission
-	err := r.DB.Find(&permissions).Error
+	page, err := paginate(r.DB.WithContext(ctx), query, permissionList, &permissions)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return permissions, nil
+	return permissions, page, nil
 }
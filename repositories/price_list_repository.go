@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type PriceListRepository struct {
+	DB *gorm.DB
+}
+
+func NewPriceListRepository(db *gorm.DB) *PriceListRepository {
+	return &PriceListRepository{DB: db}
+}
+
+var priceListList = listSpec{
+	Fields: map[string]string{
+		"id":       "id",
+		"name":     "name",
+		"segment":  "segment",
+		"priority": "priority",
+		"active":   "active",
+	},
+	Sort:     []dtos.SortField{{Field: "priority", Desc: true}},
+	Preloads: []string{"Items", "Rules", "Customers"},
+}
+
+func (r *PriceListRepository) GetAllPriceLists(ctx context.Context, query dtos.ListQuery) ([]models.PriceList, *dtos.PageInfo, error) {
+	var priceLists []models.PriceList
+	page, err := paginate(r.DB.WithContext(ctx), query, priceListList, &priceLists)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priceLists, page, nil
+}
+
+func (r *PriceListRepository) GetPriceListByID(ctx context.Context, id string) (*models.PriceList, error) {
+	var priceList models.PriceList
+	err := r.DB.WithContext(ctx).Preload("Items").Preload("Rules").Preload("Customers").
+		First(&priceList, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &priceList, nil
+}
+
+// GetApplicablePriceLists devuelve las listas activas que aplican al cliente, en
+// orden de precedencia: primero las asignadas directamente al cliente y luego las
+// de su segmento, cada grupo ordenado por prioridad descendente.
+func (r *PriceListRepository) GetApplicablePriceLists(ctx context.Context, customerID int, segment string) ([]models.PriceList, error) {
+	var direct []models.PriceList
+	err := r.DB.WithContext(ctx).Preload("Items").Preload("Rules").
+		Joins("JOIN price_list_customers ON price_list_customers.price_list_id = price_lists.id").
+		Where("price_list_customers.customer_id = ? AND price_lists.active = ?", customerID, true).
+		Order("price_lists.priority DESC, price_lists.id").
+		Find(&direct).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var bySegment []models.PriceList
+	err = r.DB.WithContext(ctx).Preload("Items").Preload("Rules").
+		Where("segment = ? AND active = ?", segment, true).
+		Order("priority DESC, id").
+		Find(&bySegment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[int]bool{}
+	for _, priceList := range direct {
+		seen[priceList.ID] = true
+	}
+	for _, priceList := range bySegment {
+		if !seen[priceList.ID] {
+			direct = append(direct, priceList)
+		}
+	}
+	return direct, nil
+}
+
+func (r *PriceListRepository) CreatePriceList(ctx context.Context, priceList *models.PriceList, customerIDs []int) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Customers").Create(priceList).Error; err != nil {
+			return err
+		}
+		return replacePriceListCustomers(tx, priceList, customerIDs)
+	})
+}
+
+// UpdatePriceList reemplaza los datos de la lista junto con sus precios, reglas y clientes.
+func (r *PriceListRepository) UpdatePriceList(ctx context.Context, priceList *models.PriceList, customerIDs []int) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PriceList{}).Where("id = ?", priceList.ID).
+			Select("Name", "Description", "Segment", "Priority", "Active").
+			Updates(priceList).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("price_list_id = ?", priceList.ID).Delete(&models.PriceListItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("price_list_id = ?", priceList.ID).Delete(&models.PriceListRule{}).Error; err != nil {
+			return err
+		}
+
+		for i := range priceList.Items {
+			priceList.Items[i].PriceListID = priceList.ID
+		}
+		for i := range priceList.Rules {
+			priceList.Rules[i].ID = 0
+			priceList.Rules[i].PriceListID = priceList.ID
+		}
+		if len(priceList.Items) > 0 {
+			if err := tx.Create(&priceList.Items).Error; err != nil {
+				return err
+			}
+		}
+		if len(priceList.Rules) > 0 {
+			if err := tx.Create(&priceList.Rules).Error; err != nil {
+				return err
+			}
+		}
+
+		return replacePriceListCustomers(tx, priceList, customerIDs)
+	})
+}
+
+func replacePriceListCustomers(tx *gorm.DB, priceList *models.PriceList, customerIDs []int) error {
+	var customers []models.Customer
+	if len(customerIDs) > 0 {
+		if err := tx.Where("id IN ?", customerIDs).Find(&customers).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Model(priceList).Association("Customers").Replace(customers)
+}
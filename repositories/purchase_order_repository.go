@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"time"
+	"totesbackend/apperrors"
+	"totesbackend/datascope"
 	"totesbackend/dtos"
 	"totesbackend/models"
 
@@ -18,9 +21,9 @@ func NewPurchaseOrderRepository(db *gorm.DB) *PurchaseOrderRepository {
 	return &PurchaseOrderRepository{DB: db}
 }
 
-func (r *PurchaseOrderRepository) GetPurchaseOrderByID(id string) (*models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) GetPurchaseOrderByID(ctx context.Context, id string) (*models.PurchaseOrder, error) {
 	var purchaseOrder models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourcePurchaseOrders).Preload("Seller").
 		Preload("Responsible").
 		Preload("Customer").
 		Preload("OrderState").
@@ -28,17 +31,15 @@ func (r *PurchaseOrderRepository) GetPurchaseOrderByID(id string) (*models.Purch
 		Preload("Discounts"). // Ahora sí debería funcionar
 		Preload("Taxes").
 		First(&purchaseOrder, "id = ?", id).Error
-
-	println(purchaseOrder.ID)
 	if err != nil {
 		return nil, err
 	}
 	return &purchaseOrder, nil
 }
 
-func (r *PurchaseOrderRepository) GetPurchaseOrdersByStateID(stateID string) ([]models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) GetPurchaseOrdersByStateID(ctx context.Context, stateID string) ([]models.PurchaseOrder, error) {
 	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourcePurchaseOrders).Preload("Seller").
 		Preload("Responsible").
 		Preload("Customer").
 		Preload("OrderState").
@@ -54,9 +55,9 @@ func (r *PurchaseOrderRepository) GetPurchaseOrdersByStateID(stateID string) ([]
 	return purchaseOrders, nil
 }
 
-func (r *PurchaseOrderRepository) GetPurchaseOrdersByCustomerID(customerID string) ([]models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) GetPurchaseOrdersByCustomerID(ctx context.Context, customerID string) ([]models.PurchaseOrder, error) {
 	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourcePurchaseOrders).Preload("Seller").
 		Preload("Responsible").
 		Preload("Customer").
 		Preload("OrderState").
@@ -72,9 +73,9 @@ func (r *PurchaseOrderRepository) GetPurchaseOrdersByCustomerID(customerID strin
 	return purchaseOrders, nil
 }
 
-func (r *PurchaseOrderRepository) GetPurchaseOrdersBySellerID(sellerID string) ([]models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) GetPurchaseOrdersBySellerID(ctx context.Context, sellerID string) ([]models.PurchaseOrder, error) {
 	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourcePurchaseOrders).Preload("Seller").
 		Preload("Responsible").
 		Preload("Customer").
 		Preload("OrderState").
@@ -90,26 +91,35 @@ func (r *PurchaseOrderRepository) GetPurchaseOrdersBySellerID(sellerID string) (
 	return purchaseOrders, nil
 }
 
-func (r *PurchaseOrderRepository) GetAllPurchaseOrders() ([]models.PurchaseOrder, error) {
-	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
-		Preload("Responsible").
-		Preload("Customer").
-		Preload("OrderState").
-		Preload("Items.Item").
-		Preload("Discounts").
-		Preload("Taxes").
-		Find(&purchaseOrders).Error
+var purchaseOrderList = listSpec{
+	Fields: map[string]string{
+		"id":             "id",
+		"date_time":      "date_time",
+		"seller_id":      "seller_id",
+		"customer_id":    "customer_id",
+		"responsible_id": "responsible_id",
+		"sub_total":      "sub_total",
+		"total":          "total",
+		"order_state_id": "order_state_id",
+	},
+	Preloads: []string{"Seller", "Responsible", "Customer", "OrderState", "Items.Item", "Discounts", "Taxes"},
+}
 
+func (r *PurchaseOrderRepository) GetAllPurchaseOrders(ctx context.Context, query dtos.ListQuery) ([]models.PurchaseOrder, *dtos.PageInfo, error) {
+	var purchaseOrders []models.PurchaseOrder
+	page, err := paginate(scoped(ctx, r.DB.WithContext(ctx), datascope.ResourcePurchaseOrders), query, purchaseOrderList, &purchaseOrders)
 	if err != nil {
-		return nil, errors.New("error retrieving purchase orders")
+		if isListQueryError(err) {
+			return nil, nil, err
+		}
+		return nil, nil, errors.New("error retrieving purchase orders")
 	}
-	return purchaseOrders, nil
+	return purchaseOrders, page, nil
 }
 
-func (r *PurchaseOrderRepository) SearchPurchaseOrdersByID(query string) ([]models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) SearchPurchaseOrdersByID(ctx context.Context, query string) ([]models.PurchaseOrder, error) {
 	var purchaseOrders []models.PurchaseOrder
-	err := r.DB.Preload("Seller").
+	err := scoped(ctx, r.DB.WithContext(ctx), datascope.ResourcePurchaseOrders).Preload("Seller").
 		Preload("Responsible").
 		Preload("Customer").
 		Preload("OrderState").
@@ -125,11 +135,11 @@ func (r *PurchaseOrderRepository) SearchPurchaseOrdersByID(query string) ([]mode
 	return purchaseOrders, nil
 }
 
-func (r *PurchaseOrderRepository) UpdatePurchaseOrder(purchaseOrder *models.PurchaseOrder) error {
+func (r *PurchaseOrderRepository) UpdatePurchaseOrder(ctx context.Context, purchaseOrder *models.PurchaseOrder) error {
 	var existingPurchaseOrder models.PurchaseOrder
 
 	// Preload completo de todas las relaciones relevantes
-	if err := r.DB.Preload("Seller").
+	if err := r.DB.WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
 		Preload("Customer").
 		Preload("OrderState").
@@ -141,14 +151,29 @@ func (r *PurchaseOrderRepository) UpdatePurchaseOrder(purchaseOrder *models.Purc
 	}
 
 	// Actualización de la orden de compra
-	if err := r.DB.Model(&existingPurchaseOrder).Updates(purchaseOrder).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&existingPurchaseOrder).Updates(purchaseOrder).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (r *PurchaseOrderRepository) CreatePurchaseOrder(dto *dtos.CreatePurchaseOrderDTO, subtotal float64, total float64) (*models.PurchaseOrder, error) {
+// FindPurchaseOrdersInBatches recorre todas las órdenes de compra, con sus
+// líneas, en lotes de batchSize.
+func (r *PurchaseOrderRepository) FindPurchaseOrdersInBatches(ctx context.Context, batchSize int, fn func([]models.PurchaseOrder) error) error {
+	var purchaseOrders []models.PurchaseOrder
+	return r.DB.WithContext(ctx).Preload("Items").
+		FindInBatches(&purchaseOrders, batchSize, func(tx *gorm.DB, batch int) error {
+			return fn(purchaseOrders)
+		}).Error
+}
+
+func (r *PurchaseOrderRepository) UpdatePurchaseOrderTotals(ctx context.Context, id int, subtotal float64, total float64) error {
+	return r.DB.WithContext(ctx).Model(&models.PurchaseOrder{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"sub_total": subtotal, "total": total}).Error
+}
+
+func (r *PurchaseOrderRepository) CreatePurchaseOrder(ctx context.Context, dto *dtos.CreatePurchaseOrderDTO, subtotal float64, total float64) (*models.PurchaseOrder, error) {
 	purchaseOrder := &models.PurchaseOrder{
 		SellerID:      nil,
 		CustomerID:    nil,
@@ -159,7 +184,7 @@ func (r *PurchaseOrderRepository) CreatePurchaseOrder(dto *dtos.CreatePurchaseOr
 		OrderStateID:  1, // Estado inicial
 	}
 
-	tx := r.DB.Begin()
+	tx := r.DB.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
 	}
@@ -176,6 +201,7 @@ func (r *PurchaseOrderRepository) CreatePurchaseOrder(dto *dtos.CreatePurchaseOr
 			PurchaseOrderID: purchaseOrder.ID,
 			ItemID:          billingItem.ID,
 			Amount:          billingItem.Stock,
+			UnitPrice:       billingItem.UnitPrice,
 		}
 
 		if err := tx.Create(purchaseOrderItem).Error; err != nil {
@@ -191,34 +217,34 @@ func (r *PurchaseOrderRepository) CreatePurchaseOrder(dto *dtos.CreatePurchaseOr
 
 	// Cargar datos completos de la orden
 	var fullPurchaseOrder models.PurchaseOrder
-	if err := r.DB.Preload("Discounts").Preload("Taxes").Preload("Items.Item").First(&fullPurchaseOrder, purchaseOrder.ID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("Discounts").Preload("Taxes").Preload("Items.Item").First(&fullPurchaseOrder, purchaseOrder.ID).Error; err != nil {
 		return nil, err
 	}
 
 	return &fullPurchaseOrder, nil
 }
 
-func (r *PurchaseOrderRepository) ChangePurchaseOrderState(id string, state string) (*models.PurchaseOrder, error) {
+func (r *PurchaseOrderRepository) ChangePurchaseOrderState(ctx context.Context, id string, state string) (*models.PurchaseOrder, error) {
 	var purchaseOrder models.PurchaseOrder
 
 	// Buscar solo por ID sin preloads inicialmente
-	if err := r.DB.First(&purchaseOrder, "id = ?", id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&purchaseOrder, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
 	// Convertir el string del estado a entero
 	stateInt, err := strconv.Atoi(state)
 	if err != nil {
-		return nil, errors.New("invalid state ID: " + err.Error())
+		return nil, apperrors.ErrInvalidOrderState.Wrap(err).WithDetail("state_id", state)
 	}
 
 	// Actualizar solo el campo 'order_state_id'
-	if err := r.DB.Model(&purchaseOrder).Update("order_state_id", stateInt).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Model(&purchaseOrder).Update("order_state_id", stateInt).Error; err != nil {
 		return nil, err
 	}
 
 	// Recargar la orden completa con sus relaciones
-	if err := r.DB.Preload("Seller").
+	if err := r.DB.WithContext(ctx).Preload("Seller").
 		Preload("Responsible").
 		Preload("Customer").
 		Preload("OrderState").
@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,27 +16,35 @@ func NewRoleRepository(db *gorm.DB) *RoleRepository {
 	return &RoleRepository{DB: db}
 }
 
-func (r *RoleRepository) GetAllRoles() ([]models.Role, error) {
+var roleList = listSpec{
+	Fields: map[string]string{
+		"id":   "id",
+		"name": "name",
+	},
+	Preloads: []string{"Permissions", "Scopes"},
+}
+
+func (r *RoleRepository) GetAllRoles(ctx context.Context, query dtos.ListQuery) ([]models.Role, *dtos.PageInfo, error) {
 	var roles []models.Role
-	err := r.DB.Preload("Permissions").Find(&roles).Error
+	page, err := paginate(r.DB.WithContext(ctx), query, roleList, &roles)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return roles, nil
+	return roles, page, nil
 }
 
-func (r *RoleRepository) GetRoleByID(id uint) (*models.Role, error) {
+func (r *RoleRepository) GetRoleByID(ctx context.Context, id uint) (*models.Role, error) {
 	var role models.Role
-	err := r.DB.Preload("Permissions").First(&role, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("Permissions").Preload("Scopes").First(&role, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &role, nil
 }
 
-func (r *RoleRepository) GetRolePermissions(roleID uint) ([]uint, error) {
+func (r *RoleRepository) GetRolePermissions(ctx context.Context, roleID uint) ([]uint, error) {
 	var permissionIDs []uint
-	err := r.DB.Table("role_permission").Select("permission_id").
+	err := r.DB.WithContext(ctx).Table("role_permission").Select("permission_id").
 		Where("role_id = ?", roleID).
 		Pluck("permission_id", &permissionIDs).Error
 	if err != nil {
@@ -43,9 +53,9 @@ func (r *RoleRepository) GetRolePermissions(roleID uint) ([]uint, error) {
 	return permissionIDs, nil
 }
 
-func (r *RoleRepository) GetAllPermissionsOfRole(roleID uint) ([]models.Permission, error) {
+func (r *RoleRepository) GetAllPermissionsOfRole(ctx context.Context, roleID uint) ([]models.Permission, error) {
 	var permissions []models.Permission
-	err := r.DB.Joins("JOIN role_permission rp ON permissions.id = rp.permission_id").
+	err := r.DB.WithContext(ctx).Joins("JOIN role_permission rp ON permissions.id = rp.permission_id").
 		Where("rp.role_id = ?", roleID).
 		Find(&permissions).Error
 	if err != nil {
@@ -54,18 +64,18 @@ func (r *RoleRepository) GetAllPermissionsOfRole(roleID uint) ([]models.Permissi
 	return permissions, nil
 }
 
-func (r *RoleRepository) ExistRole(roleID uint) (bool, error) {
+func (r *RoleRepository) ExistRole(ctx context.Context, roleID uint) (bool, error) {
 	var count int64
-	err := r.DB.Table("roles").Where("id = ?", roleID).Count(&count).Error
+	err := r.DB.WithContext(ctx).Table("roles").Where("id = ?", roleID).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *RoleRepository) SearchRolesByID(query string) ([]models.Role, error) {
+func (r *RoleRepository) SearchRolesByID(ctx context.Context, query string) ([]models.Role, error) {
 	var roles []models.Role
-	err := r.DB.
+	err := r.DB.WithContext(ctx).
 		Where("CAST(id AS TEXT) LIKE ?", query+"%").
 		Find(&roles).Error
 	if err != nil {
@@ -74,9 +84,9 @@ func (r *RoleRepository) SearchRolesByID(query string) ([]models.Role, error) {
 	return roles, nil
 }
 
-func (r *RoleRepository) SearchRolesByName(query string) ([]models.Role, error) {
+func (r *RoleRepository) SearchRolesByName(ctx context.Context, query string) ([]models.Role, error) {
 	var roles []models.Role
-	err := r.DB.
+	err := r.DB.WithContext(ctx).
 		Where("LOWER(name) LIKE LOWER(?)", query+"%").
 		Find(&roles).Error
 	if err != nil {
@@ -84,3 +94,19 @@ func (r *RoleRepository) SearchRolesByName(query string) ([]models.Role, error)
 	}
 	return roles, nil
 }
+
+// SetRoleScopes reemplaza las reglas de alcance del rol.
+func (r *RoleRepository) SetRoleScopes(ctx context.Context, roleID uint, scopes []models.RoleScope) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Role{}, "id = ?", roleID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", roleID).Delete(&models.RoleScope{}).Error; err != nil {
+			return err
+		}
+		if len(scopes) == 0 {
+			return nil
+		}
+		return tx.Create(&scopes).Error
+	})
+}
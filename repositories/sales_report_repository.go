@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+
+	"gorm.io/gorm"
+)
+
+// Criterios de orden del ranking de ítems más vendidos.
+const (
+	TopItemsByQuantity = "quantity"
+	TopItemsByRevenue  = "revenue"
+)
+
+// lineRevenue es el importe de una línea de factura. Las facturas emitidas antes
+// de guardar el precio cobrado no lo tienen y se usa el precio de venta del ítem.
+const lineRevenue = "ii.amount * COALESCE(NULLIF(ii.unit_price, 0), it.selling_price)"
+
+type SalesReportRepository struct {
+	DB *gorm.DB
+}
+
+func NewSalesReportRepository(db *gorm.DB) *SalesReportRepository {
+	return &SalesReportRepository{DB: db}
+}
+
+// GetRevenueByPeriod agrupa lo facturado entre las fechas por día, semana o mes.
+// Devuelve también el período inmediatamente anterior al rango, completo, para
+// poder comparar el primer período. Los períodos sin facturas se devuelven en cero.
+func (r *SalesReportRepository) GetRevenueByPeriod(ctx context.Context, startDate, endDate time.Time, period string) ([]dtos.RevenueByPeriodDTO, error) {
+	var rows []dtos.RevenueByPeriodDTO
+	err := r.DB.WithContext(ctx).Raw(`
+		WITH periods AS (
+			SELECT generate_series(
+				date_trunc(@period, CAST(@start AS timestamptz)) - CAST(@step AS interval),
+				date_trunc(@period, CAST(@end AS timestamptz)),
+				CAST(@step AS interval)) AS period
+		)
+		SELECT p.period,
+			COUNT(i.id) AS invoice_count,
+			COALESCE(SUM(i.subtotal), 0) AS subtotal,
+			COALESCE(SUM(i.discount_total), 0) AS discount_total,
+			COALESCE(SUM(i.tax_total), 0) AS tax_total,
+			COALESCE(SUM(i.total), 0) AS total
+		FROM periods p
+		LEFT JOIN invoices i ON date_trunc(@period, i.date_time) = p.period
+			AND i.date_time <= @end
+			AND (i.date_time >= @start OR p.period < date_trunc(@period, CAST(@start AS timestamptz)))
+		GROUP BY p.period
+		ORDER BY p.period`,
+		map[string]interface{}{
+			"period": period,
+			"step":   "1 " + period,
+			"start":  startDate,
+			"end":    endDate,
+		}).Scan(&rows).Error
+	return rows, err
+}
+
+// GetTopItems devuelve los ítems más vendidos entre las fechas, ordenados por
+// cantidad o por ingresos.
+func (r *SalesReportRepository) GetTopItems(ctx context.Context, startDate, endDate time.Time, orderBy string, limit int) ([]dtos.TopItemDTO, error) {
+	order := "quantity DESC, revenue DESC"
+	if orderBy == TopItemsByRevenue {
+		order = "revenue DESC, quantity DESC"
+	}
+
+	var rows []dtos.TopItemDTO
+	err := r.DB.WithContext(ctx).Table("invoice_items ii").
+		Select("ii.item_id, it.name AS item_name, SUM(ii.amount) AS quantity, SUM("+lineRevenue+") AS revenue").
+		Joins("JOIN invoices i ON i.id = ii.invoice_id").
+		Joins("JOIN items it ON it.id = ii.item_id").
+		Where("i.date_time BETWEEN ? AND ?", startDate, endDate).
+		Group("ii.item_id, it.name").
+		Order(order).
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetRevenueByItemType agrupa lo facturado entre las fechas por tipo de ítem.
+func (r *SalesReportRepository) GetRevenueByItemType(ctx context.Context, startDate, endDate time.Time) ([]dtos.ItemTypeRevenueDTO, error) {
+	var rows []dtos.ItemTypeRevenueDTO
+	err := r.DB.WithContext(ctx).Table("invoice_items ii").
+		Select("t.id AS item_type_id, t.name AS item_type_name, SUM(ii.amount) AS quantity, SUM("+lineRevenue+") AS revenue").
+		Joins("JOIN invoices i ON i.id = ii.invoice_id").
+		Joins("JOIN items it ON it.id = ii.item_id").
+		Joins("JOIN item_types t ON t.id = it.item_type_id").
+		Where("i.date_time BETWEEN ? AND ?", startDate, endDate).
+		Group("t.id, t.name").
+		Order("revenue DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetRevenueByCustomer agrupa lo facturado entre las fechas por cliente.
+func (r *SalesReportRepository) GetRevenueByCustomer(ctx context.Context, startDate, endDate time.Time) ([]dtos.CustomerRevenueDTO, error) {
+	var rows []dtos.CustomerRevenueDTO
+	err := r.DB.WithContext(ctx).Table("invoices i").
+		Select(`c.id AS customer_id, TRIM(COALESCE(c.customer_name, '') || ' ' || c.last_name) AS customer_name,
+			COUNT(i.id) AS invoice_count, SUM(i.subtotal) AS subtotal, SUM(i.discount_total) AS discount_total,
+			SUM(i.tax_total) AS tax_total, SUM(i.total) AS total`).
+		Joins("JOIN customers c ON c.id = i.customer_id").
+		Where("i.date_time BETWEEN ? AND ?", startDate, endDate).
+		Group("c.id, c.customer_name, c.last_name").
+		Order("total DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetRevenueBySeller agrupa lo facturado entre las fechas por el vendedor de la
+// orden de compra que originó cada factura. Las facturas sin orden de compra no
+// tienen vendedor y no se incluyen.
+func (r *SalesReportRepository) GetRevenueBySeller(ctx context.Context, startDate, endDate time.Time) ([]dtos.SellerRevenueDTO, error) {
+	var rows []dtos.SellerRevenueDTO
+	err := r.DB.WithContext(ctx).Table("invoices i").
+		Select(`e.id AS seller_id, TRIM(e.names || ' ' || e.last_names) AS seller_name,
+			COUNT(i.id) AS invoice_count, SUM(i.subtotal) AS subtotal, SUM(i.discount_total) AS discount_total,
+			SUM(i.tax_total) AS tax_total, SUM(i.total) AS total`).
+		Joins("JOIN purchase_orders po ON po.id = i.purchase_order_id").
+		Joins("JOIN employees e ON e.id = po.seller_id").
+		Where("i.date_time BETWEEN ? AND ?", startDate, endDate).
+		Group("e.id, e.names, e.last_names").
+		Order("total DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetTaxSummary agrupa por tipo de impuesto lo recaudado en las facturas emitidas
+// entre las fechas. Los impuestos porcentuales se calculan sobre el subtotal.
+func (r *SalesReportRepository) GetTaxSummary(ctx context.Context, startDate, endDate time.Time) ([]dtos.TaxSummaryDTO, error) {
+	var rows []dtos.TaxSummaryDTO
+	err := r.DB.WithContext(ctx).Table("invoice_taxes it").
+		Select(`tt.id AS tax_type_id, tt.name AS tax_type_name, COUNT(i.id) AS invoice_count,
+			SUM(i.subtotal) AS taxable_amount,
+			SUM(CASE WHEN tt.is_percentage THEN i.subtotal * tt.value / 100 ELSE tt.value END) AS tax_total`).
+		Joins("JOIN invoices i ON i.id = it.invoice_id").
+		Joins("JOIN tax_types tt ON tt.id = it.tax_type_id").
+		Where("i.date_time BETWEEN ? AND ?", startDate, endDate).
+		Group("tt.id, tt.name").
+		Order("tt.id").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type ScheduledReportRepository struct {
+	DB *gorm.DB
+}
+
+func NewScheduledReportRepository(db *gorm.DB) *ScheduledReportRepository {
+	return &ScheduledReportRepository{DB: db}
+}
+
+var scheduledReportList = listSpec{
+	Fields: map[string]string{
+		"id":         "id",
+		"name":       "name",
+		"report":     "report",
+		"format":     "format",
+		"active":     "active",
+		"created_at": "created_at",
+	},
+}
+
+func (r *ScheduledReportRepository) GetAllScheduledReports(ctx context.Context, query dtos.ListQuery) ([]models.ScheduledReport, *dtos.PageInfo, error) {
+	var reports []models.ScheduledReport
+	page, err := paginate(r.DB.WithContext(ctx), query, scheduledReportList, &reports)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reports, page, nil
+}
+
+func (r *ScheduledReportRepository) GetActiveScheduledReports(ctx context.Context) ([]models.ScheduledReport, error) {
+	var reports []models.ScheduledReport
+	err := r.DB.WithContext(ctx).Where("active = ?", true).Find(&reports).Error
+	return reports, err
+}
+
+func (r *ScheduledReportRepository) GetScheduledReportByID(ctx context.Context, id int) (*models.ScheduledReport, error) {
+	var report models.ScheduledReport
+	err := r.DB.WithContext(ctx).First(&report, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *ScheduledReportRepository) CreateScheduledReport(ctx context.Context, report *models.ScheduledReport) error {
+	return r.DB.WithContext(ctx).Create(report).Error
+}
+
+func (r *ScheduledReportRepository) UpdateScheduledReport(ctx context.Context, report *models.ScheduledReport) error {
+	return r.DB.WithContext(ctx).Model(&models.ScheduledReport{}).Where("id = ?", report.ID).
+		Select("Name", "Report", "Parameters", "Cron", "Format", "Recipients", "Active").
+		Updates(report).Error
+}
+
+// DeleteScheduledReport elimina la definición junto con el historial de sus ejecuciones.
+func (r *ScheduledReportRepository) DeleteScheduledReport(ctx context.Context, id int) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("scheduled_report_id = ?", id).Delete(&models.ReportRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ScheduledReport{}, id).Error
+	})
+}
+
+func (r *ScheduledReportRepository) UpdateLastRunAt(ctx context.Context, id int, at time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.ScheduledReport{}).Where("id = ?", id).Update("last_run_at", at).Error
+}
+
+func (r *ScheduledReportRepository) CreateReportRun(ctx context.Context, run *models.ReportRun) error {
+	return r.DB.WithContext(ctx).Create(run).Error
+}
+
+func (r *ScheduledReportRepository) SaveReportRun(ctx context.Context, run *models.ReportRun) error {
+	return r.DB.WithContext(ctx).Save(run).Error
+}
+
+// GetReportRuns devuelve las ejecuciones de un reporte, la más reciente primero,
+// sin cargar el archivo generado.
+func (r *ScheduledReportRepository) GetReportRuns(ctx context.Context, scheduledReportID int) ([]models.ReportRun, error) {
+	var runs []models.ReportRun
+	err := r.DB.WithContext(ctx).Omit("Artifact").
+		Where("scheduled_report_id = ?", scheduledReportID).
+		Order("started_at DESC").
+		Find(&runs).Error
+	return runs, err
+}
+
+func (r *ScheduledReportRepository) GetReportRunByID(ctx context.Context, id int) (*models.ReportRun, error) {
+	var run models.ReportRun
+	err := r.DB.WithContext(ctx).First(&run, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"totesbackend/dtos"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type SecurityEventRepository struct {
+	DB *gorm.DB
+}
+
+func NewSecurityEventRepository(db *gorm.DB) *SecurityEventRepository {
+	return &SecurityEventRepository{DB: db}
+}
+
+func (r *SecurityEventRepository) CreateSecurityEvent(ctx context.Context, event *models.SecurityEvent) error {
+	return r.DB.WithContext(ctx).Create(event).Error
+}
+
+var securityEventList = listSpec{
+	Fields: map[string]string{
+		"id":         "id",
+		"type":       "type",
+		"email":      "user_email",
+		"ip":         "ip",
+		"actor":      "actor",
+		"request_id": "request_id",
+		"created_at": "created_at",
+	},
+	Sort: []dtos.SortField{{Field: "created_at", Desc: true}},
+}
+
+func (r *SecurityEventRepository) GetAllSecurityEvents(ctx context.Context, query dtos.ListQuery) ([]models.SecurityEvent, *dtos.PageInfo, error) {
+	var events []models.SecurityEvent
+	page, err := paginate(r.DB.WithContext(ctx), query, securityEventList, &events)
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, page, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	DB *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{DB: db}
+}
+
+func (r *SessionRepository) CreateSession(ctx context.Context, session *models.UserSession) error {
+	return r.DB.WithContext(ctx).Omit("User").Create(session).Error
+}
+
+// GetSessionByTokenHash busca la sesión por el hash de su token, esté o no
+// vigente, con su usuario.
+func (r *SessionRepository) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*models.UserSession, error) {
+	var session models.UserSession
+	err := r.DB.WithContext(ctx).Preload("User").First(&session, "token_hash = ?", tokenHash).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActiveSessions lista las sesiones vigentes del usuario: sin revocar, sin
+// vencer y usadas después de idleSince. Las más recientes primero.
+func (r *SessionRepository) GetActiveSessions(ctx context.Context, userID int, now, idleSince time.Time) ([]models.UserSession, error) {
+	var sessions []models.UserSession
+	err := r.DB.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ? AND last_seen_at > ?", userID, now, idleSince).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepository) GetUserSession(ctx context.Context, userID int, id string) (*models.UserSession, error) {
+	var session models.UserSession
+	err := r.DB.WithContext(ctx).First(&session, "id = ? AND user_id = ?", id, userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// RevokeSession revoca la sesión. Devuelve false si ya estaba revocada.
+func (r *SessionRepository) RevokeSession(ctx context.Context, id int, actor string, now time.Time) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.UserSession{}).Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_by": actor, "revoke_reason": models.SessionRevokedManually})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RevokeUserSessions revoca todas las sesiones abiertas del usuario y
+// devuelve cuántas eran.
+func (r *SessionRepository) RevokeUserSessions(ctx context.Context, userID int, actor string, now time.Time) (int64, error) {
+	return revokeUserSessions(r.DB.WithContext(ctx), userID, actor, models.SessionRevokedManually, now)
+}
+
+// TouchSession registra el último uso de la sesión. Para no escribir en cada
+// petición sólo actualiza si el uso anterior es de antes de since.
+func (r *SessionRepository) TouchSession(ctx context.Context, id int, now, since time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.UserSession{}).
+		Where("id = ? AND last_seen_at < ?", id, since).
+		Update("last_seen_at", now).Error
+}
+
+// revokeUserSessions revoca las sesiones abiertas del usuario en tx. La usan
+// también los cambios de estado y de tipo de usuario de UserRepository.
+func revokeUserSessions(tx *gorm.DB, userID int, actor, reason string, now time.Time) (int64, error) {
+	result := tx.Model(&models.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_by": actor, "revoke_reason": reason})
+	return result.RowsAffected, result.Error
+}
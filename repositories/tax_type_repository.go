@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,21 +16,33 @@ func NewTaxTypeRepository(db *gorm.DB) *TaxTypeRepository {
 	return &TaxTypeRepository{DB: db}
 }
 
-func (r *TaxTypeRepository) GetAllTaxTypes() ([]models.TaxType, error) {
+var taxTypeList = listSpec{
+	Fields: map[string]string{
+		"id":            "id",
+		"name":          "name",
+		"is_percentage": "is_percentage",
+		"value":         "value",
+	},
+}
+
+func (r *TaxTypeRepository) GetAllTaxTypes(ctx context.Context, query dtos.ListQuery) ([]models.TaxType, *dtos.PageInfo, error) {
 	var taxTypes []models.TaxType
-	err := r.DB.Find(&taxTypes).Error
-	return taxTypes, err
+	page, err := paginate(r.DB.WithContext(ctx), query, taxTypeList, &taxTypes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return taxTypes, page, nil
 }
 
-func (r *TaxTypeRepository) GetTaxTypeByID(id string) (*models.TaxType, error) {
+func (r *TaxTypeRepository) GetTaxTypeByID(ctx context.Context, id string) (*models.TaxType, error) {
 	var taxType models.TaxType
-	err := r.DB.First(&taxType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&taxType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &taxType, nil
 }
 
-func (r *TaxTypeRepository) CreateTaxType(taxType *models.TaxType) error {
-	return r.DB.Create(taxType).Error
+func (r *TaxTypeRepository) CreateTaxType(ctx context.Context, taxType *models.TaxType) error {
+	return r.DB.WithContext(ctx).Create(taxType).Error
 }
@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"totesbackend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TwoFactorRepository struct {
+	DB *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{DB: db}
+}
+
+func (r *TwoFactorRepository) GetTOTP(ctx context.Context, userID int) (*models.UserTOTP, error) {
+	var totp models.UserTOTP
+	if err := r.DB.WithContext(ctx).First(&totp, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &totp, nil
+}
+
+// SavePendingTOTP guarda una semilla sin confirmar, reemplazando la pendiente
+// anterior si la había.
+func (r *TwoFactorRepository) SavePendingTOTP(ctx context.Context, totp *models.UserTOTP) error {
+	return r.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "confirmed_at", "last_used_step", "created_at"}),
+	}).Create(totp).Error
+}
+
+// ConfirmTOTP activa el segundo factor y reemplaza los códigos de recuperación.
+func (r *TwoFactorRepository) ConfirmTOTP(ctx context.Context, userID int, step int64, codes []models.RecoveryCode) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.UserTOTP{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{"confirmed_at": time.Now(), "last_used_step": step}).Error
+		if err != nil {
+			return err
+		}
+		return replaceRecoveryCodes(tx, userID, codes)
+	})
+}
+
+// UseStep registra que se aceptó el código del paso step. Devuelve false si ya
+// se había aceptado ese paso o uno posterior, es decir, si el código se repite.
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.UserTOTP{}).
+		Where("user_id = ? AND (last_used_step IS NULL OR last_used_step < ?)", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UseRecoveryCode marca como usado el código con ese hash. Devuelve false si
+// no existe o ya se usó.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *TwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID int) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID int, codes []models.RecoveryCode) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codes)
+	})
+}
+
+// DeleteTwoFactor quita el segundo factor y los códigos de recuperación.
+func (r *TwoFactorRepository) DeleteTwoFactor(ctx context.Context, userID int) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserTOTP{}).Error
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID int, codes []models.RecoveryCode) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return nil
+	}
+	return tx.Create(&codes).Error
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,8 +15,8 @@ func NewUserLogRepository(db *gorm.DB) *UserLogRepository {
 	return &UserLogRepository{DB: db}
 }
 
-func (r *UserLogRepository) CreateUserLog(userLog *models.UserLog) (*models.UserLog, error) {
-	if err := r.DB.Create(userLog).Error; err != nil {
+func (r *UserLogRepository) CreateUserLog(ctx context.Context, userLog *models.UserLog) (*models.UserLog, error) {
+	if err := r.DB.WithContext(ctx).Create(userLog).Error; err != nil {
 		return nil, err
 	}
 	return userLog, nil
@@ -1,6 +1,10 @@
 package repositories
 
 import (
+	"context"
+	"strings"
+	"time"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,36 +18,58 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{DB: db}
 }
 
-func (r *UserRepository) GetUserByID(id string) (*models.User, error) {
+func (r *UserRepository) GetUserByID(ctx context.Context, id string) (*models.User, error) {
 	var user models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").First(&user, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").First(&user, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetUserByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").First(&user, "email = ?", email).Error
+	err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").First(&user, "email = ?", email).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetAllUsers() ([]models.User, error) {
-	var users []models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").Find(&users).Error
+// GetUserByEmailFold busca el correo sin distinguir mayúsculas, para los
+// correos que llegan del proveedor de identidad.
+func (r *UserRepository) GetUserByEmailFold(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").
+		Order("id").First(&user, "LOWER(email) = LOWER(?)", email).Error
 	if err != nil {
 		return nil, err
 	}
-	return users, nil
+	return &user, nil
+}
+
+var userList = listSpec{
+	Fields: map[string]string{
+		"id":         "id",
+		"email":      "email",
+		"user_type":  "user_type_id",
+		"user_state": "user_state_type_id",
+	},
+	Preloads: []string{"UserStateType", "UserType"},
 }
 
-func (r *UserRepository) SearchUsersByID(query string) ([]models.User, error) {
+func (r *UserRepository) GetAllUsers(ctx context.Context, query dtos.ListQuery) ([]models.User, *dtos.PageInfo, error) {
 	var users []models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").
+	page, err := paginate(r.DB.WithContext(ctx), query, userList, &users)
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, page, nil
+}
+
+func (r *UserRepository) SearchUsersByID(ctx context.Context, query string) ([]models.User, error) {
+	var users []models.User
+	err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").
 		Where("CAST(id AS TEXT)  LIKE ?", query+"%").Find(&users).Error
 	if err != nil {
 		return nil, err
@@ -51,45 +77,141 @@ func (r *UserRepository) SearchUsersByID(query string) ([]models.User, error) {
 	return users, nil
 }
 
-func (r *UserRepository) SearchUsersByEmail(query string) ([]models.User, error) {
+func (r *UserRepository) SearchUsersByEmail(ctx context.Context, query string) ([]models.User, error) {
 	var users []models.User
-	err := r.DB.Preload("UserStateType").Preload("UserType").Where("LOWER(email) LIKE LOWER(?)", query+"%").Find(&users).Error
+	err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").Where("LOWER(email) LIKE LOWER(?)", query+"%").Find(&users).Error
 	if err != nil {
 		return nil, err
 	}
 	return users, nil
 }
 
-func (r *UserRepository) UpdateUserState(id string, state int) (*models.User, error) {
+func (r *UserRepository) UpdateUserState(ctx context.Context, id string, state int) (*models.User, error) {
 	var user models.User
-	if err := r.DB.Preload("UserStateType").Preload("UserType").First(&user, "id = ?", id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").First(&user, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 
+	previousState := user.UserStateTypeID
 	user.UserStateType.ID = state
+	if state != models.UserStateLocked {
+		user.LockedUntil = nil
+	}
 
-	if err := r.DB.Save(&user).Error; err != nil {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		return revokeOnAccessChange(tx, user.ID, previousState, state, user.UserTypeID, user.UserTypeID)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) UpdateUser(user *models.User) error {
+func (r *UserRepository) UpdateUser(ctx context.Context, user *models.User) error {
 	var existingUser models.User
-	if err := r.DB.Preload("UserStateType").Preload("UserType").First(&existingUser, "id = ?", user.ID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").First(&existingUser, "id = ?", user.ID).Error; err != nil {
 		return err
 	}
-	// Realizar la actualización
-	if err := r.DB.Model(&existingUser).Updates(user).Error; err != nil {
-		return err
+	// Updates no cambia los campos en cero
+	newState, newType := existingUser.UserStateTypeID, existingUser.UserTypeID
+	if user.UserStateTypeID != 0 {
+		newState = user.UserStateTypeID
+	}
+	if user.UserTypeID != 0 {
+		newType = user.UserTypeID
 	}
-	return nil
+
+	// Realizar la actualización; la contraseña sólo cambia por PasswordRepository
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&existingUser).Omit("Password").Updates(user).Error; err != nil {
+			return err
+		}
+		return revokeOnAccessChange(tx, existingUser.ID, existingUser.UserStateTypeID, newState,
+			existingUser.UserTypeID, newType)
+	})
 }
 
-func (r *UserRepository) CreateUser(user *models.User) (*models.User, error) {
+func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	// Intentar crear el usuario en la base de datos
-	if err := r.DB.Preload("UserStateType").Preload("UserType").Create(user).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("UserStateType").Preload("UserType").Create(user).Error; err != nil {
 		return nil, err
 	}
 	return user, nil
 }
+
+// LockUser bloquea la cuenta hasta until y revoca sus sesiones.
+func (r *UserRepository) LockUser(ctx context.Context, id int, until time.Time) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ?", id).
+			Updates(map[string]interface{}{"user_state_type_id": models.UserStateLocked, "locked_until": until}).Error
+		if err != nil {
+			return err
+		}
+		_, err = revokeUserSessions(tx, id, "", models.SessionRevokedAccountLock, time.Now())
+		return err
+	})
+}
+
+// RehashPassword reemplaza el hash de la contraseña por otro de la misma
+// contraseña. Sólo lo hace si el hash guardado sigue siendo oldHash, para no
+// pisar un cambio de contraseña hecho mientras tanto.
+func (r *UserRepository) RehashPassword(ctx context.Context, id int, oldHash, newHash string) error {
+	return r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ? AND password = ?", id, oldHash).
+		Update("password", newHash).Error
+}
+
+// SetUserType cambia el tipo de usuario y revoca sus sesiones, que tenían los
+// permisos del tipo anterior.
+func (r *UserRepository) SetUserType(ctx context.Context, id int, userTypeID int) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ? AND user_type_id <> ?", id, userTypeID).Update("user_type_id", userTypeID)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		_, err := revokeUserSessions(tx, id, "", models.SessionRevokedUserType, time.Now())
+		return err
+	})
+}
+
+func (r *UserRepository) SetPasswordLoginDisabled(ctx context.Context, id string, disabled bool) (*models.User, error) {
+	var user models.User
+	if err := r.DB.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.DB.WithContext(ctx).Model(&user).Update("password_login_disabled", disabled).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UnlockUser reactiva una cuenta bloqueada y borra sus intentos fallidos para
+// que la espera entre intentos y el conteo para el bloqueo empiecen de cero.
+func (r *UserRepository) UnlockUser(ctx context.Context, id int, email string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).Where("id = ? AND user_state_type_id = ?", id, models.UserStateLocked).
+			Updates(map[string]interface{}{"user_state_type_id": models.UserStateActive, "locked_until": nil}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("email = ? AND success = ?", strings.ToLower(email), false).Delete(&models.LoginAttempt{}).Error
+	})
+}
+
+// revokeOnAccessChange revoca las sesiones del usuario si deja de estar activo
+// o cambia su tipo de usuario.
+func revokeOnAccessChange(tx *gorm.DB, userID, oldState, newState, oldType, newType int) error {
+	reason := ""
+	switch {
+	case newState != oldState && newState != models.UserStateActive:
+		reason = models.SessionRevokedUserState
+	case newType != oldType:
+		reason = models.SessionRevokedUserType
+	default:
+		return nil
+	}
+	_, err := revokeUserSessions(tx, userID, "", reason, time.Now())
+	return err
+}
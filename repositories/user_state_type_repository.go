@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,20 +16,27 @@ func NewUserStateTypeRepository(db *gorm.DB) *UserStateTypeRepository {
 	return &UserStateTypeRepository{DB: db}
 }
 
-func (r *UserStateTypeRepository) GetUserStateTypeByID(id string) (*models.UserStateType, error) {
+func (r *UserStateTypeRepository) GetUserStateTypeByID(ctx context.Context, id string) (*models.UserStateType, error) {
 	var UserStateType models.UserStateType
-	err := r.DB.First(&UserStateType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).First(&UserStateType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &UserStateType, nil
 }
 
-func (r *UserStateTypeRepository) GetAllUserStateTypes() ([]models.UserStateType, error) {
-	var UserStateTypes []models.UserStateType
-	err := r.DB.Find(&UserStateTypes).Error
+var userStateTypeList = listSpec{
+	Fields: map[string]string{
+		"id":   "id",
+		"name": "name",
+	},
+}
+
+func (r *UserStateTypeRepository) GetAllUserStateTypes(ctx context.Context, query dtos.ListQuery) ([]models.UserStateType, *dtos.PageInfo, error) {
+	var userStateTypes []models.UserStateType
+	page, err := paginate(r.DB.WithContext(ctx), query, userStateTypeList, &userStateTypes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return UserStateTypes, nil
+	return userStateTypes, page, nil
 }
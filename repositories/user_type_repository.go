@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 
 	"gorm.io/gorm"
@@ -14,36 +16,44 @@ func NewUserTypeRepository(db *gorm.DB) *UserTypeRepository {
 	return &UserTypeRepository{DB: db}
 }
 
-func (r *UserTypeRepository) ObtainAllUserTypes() ([]models.UserType, error) {
+var userTypeList = listSpec{
+	Fields: map[string]string{
+		"id":   "id",
+		"name": "name",
+	},
+	Preloads: []string{"Roles"},
+}
+
+func (r *UserTypeRepository) ObtainAllUserTypes(ctx context.Context, query dtos.ListQuery) ([]models.UserType, *dtos.PageInfo, error) {
 	var userTypes []models.UserType
-	err := r.DB.Preload("Roles").Find(&userTypes).Error
+	page, err := paginate(r.DB.WithContext(ctx), query, userTypeList, &userTypes)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return userTypes, nil
+	return userTypes, page, nil
 }
 
-func (r *UserTypeRepository) GetUserTypeByID(id uint) (*models.UserType, error) {
+func (r *UserTypeRepository) GetUserTypeByID(ctx context.Context, id uint) (*models.UserType, error) {
 	var userType models.UserType
-	err := r.DB.Preload("Roles").First(&userType, "id = ?", id).Error
+	err := r.DB.WithContext(ctx).Preload("Roles").First(&userType, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &userType, nil
 }
 
-func (r *UserTypeRepository) Exists(userTypeID uint) (bool, error) {
+func (r *UserTypeRepository) Exists(ctx context.Context, userTypeID uint) (bool, error) {
 	var count int64
-	err := r.DB.Table("user_types").Where("id = ?", userTypeID).Count(&count).Error
+	err := r.DB.WithContext(ctx).Table("user_types").Where("id = ?", userTypeID).Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *UserTypeRepository) GetRolesForUserType(userTypeID uint) ([]uint, error) {
+func (r *UserTypeRepository) GetRolesForUserType(ctx context.Context, userTypeID uint) ([]uint, error) {
 	var roleIDs []uint
-	err := r.DB.Table("user_type_has_role").Select("role_id").
+	err := r.DB.WithContext(ctx).Table("user_type_has_role").Select("role_id").
 		Where("user_type_id = ?", userTypeID).
 		Pluck("role_id", &roleIDs).Error
 	if err != nil {
@@ -52,9 +62,9 @@ func (r *UserTypeRepository) GetRolesForUserType(userTypeID uint) ([]uint, error
 	return roleIDs, nil
 }
 
-func (r *UserTypeRepository) SearchUserTypesByID(query string) ([]models.UserType, error) {
+func (r *UserTypeRepository) SearchUserTypesByID(ctx context.Context, query string) ([]models.UserType, error) {
 	var userTypes []models.UserType
-	err := r.DB.Preload("Roles").
+	err := r.DB.WithContext(ctx).Preload("Roles").
 		Where("CAST(id AS TEXT) LIKE ?", query+"%").
 		Find(&userTypes).Error
 	if err != nil {
@@ -63,9 +73,9 @@ func (r *UserTypeRepository) SearchUserTypesByID(query string) ([]models.UserTyp
 	return userTypes, nil
 }
 
-func (r *UserTypeRepository) SearchUserTypesByName(query string) ([]models.UserType, error) {
+func (r *UserTypeRepository) SearchUserTypesByName(ctx context.Context, query string) ([]models.UserType, error) {
 	var userTypes []models.UserType
-	err := r.DB.Preload("Roles").
+	err := r.DB.WithContext(ctx).Preload("Roles").
 		Where("LOWER(name) LIKE LOWER(?)", query+"%").
 		Find(&userTypes).Error
 	if err != nil {
@@ -73,3 +83,14 @@ func (r *UserTypeRepository) SearchUserTypesByName(query string) ([]models.UserT
 	}
 	return userTypes, nil
 }
+
+func (r *UserTypeRepository) SetRequireTwoFactor(ctx context.Context, id uint, required bool) error {
+	result := r.DB.WithContext(ctx).Model(&models.UserType{}).Where("id = ?", id).Update("require_two_factor", required)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -38,6 +38,7 @@ func RegisterRoleRoutes(router *gin.Engine, controller *controllers.RoleControll
 	router.GET("/roles", controller.GetAllRoles)
 	router.GET("/roles/searchByID", controller.SearchRolesByID)
 	router.GET("/roles/searchByName", controller.SearchRolesByName)
+	router.PUT("/roles/:id/scopes", controller.SetRoleScopes)
 }
 
 func RegisterUserTypeRoutes(router *gin.Engine,
@@ -47,6 +48,7 @@ func RegisterUserTypeRoutes(router *gin.Engine,
 	router.GET("/user-types/:id/exists", controller.ExistsUserType)
 	router.GET("/user-types/searchByID", controller.SearchUserTypesByID)
 	router.GET("/user-types/searchByName", controller.SearchUserTypesByName)
+	router.PATCH("/user-types/:id/two-factor", controller.SetUserTypeTwoFactor)
 }
 
 func RegisterUserStateTypeRoutes(router *gin.Engine,
@@ -67,6 +69,8 @@ func RegisterUserRoutes(router *gin.Engine,
 	router.GET("/users/searchByID", controller.SearchUsersByID)
 	router.GET("/users/searchByEmail", controller.SearchUsersByEmail)
 	router.PATCH("/users/:id/state", controller.UpdateUserState)
+	router.POST("/users/:id/unlock", controller.UnlockUser)
+	router.PATCH("/users/:id/password-login", controller.SetPasswordLogin)
 	router.PUT("/users/:id", controller.UpdateUser)
 	router.POST("/users", controller.CreateUser)
 }
@@ -91,6 +95,9 @@ func RegisterAdditionalExpenseRoutes(router *gin.Engine,
 
 func RegisterHistoricalItemPriceRoutes(router *gin.Engine, controller *controllers.HistoricalItemPriceController) {
 	router.GET("/historical-item-prices/:id", controller.GetHistoricalItemPrice)
+	router.GET("/historical-item-prices/:id/as-of", controller.GetItemPriceAsOf)
+	router.POST("/historical-item-prices/:id/scheduled", controller.SchedulePriceChange)
+	router.DELETE("/historical-item-prices/scheduled/:priceId", controller.CancelScheduledPrice)
 }
 
 func RegisterCommentRoutes(router *gin.Engine,
@@ -162,6 +169,21 @@ func RegisterUserCredentialValidationRoutes(router *gin.Engine, controller *cont
 	router.POST("/user-credential-validation", controller.ValidateUserCredentials)
 }
 
+func RegisterPasswordRoutes(router *gin.Engine, controller *controllers.PasswordController) {
+	router.POST("/password/change", controller.ChangePassword)
+	router.POST("/password/forgot", controller.ForgotPassword)
+	router.POST("/password/reset", controller.ResetPassword)
+}
+
+func RegisterTwoFactorRoutes(router *gin.Engine, controller *controllers.TwoFactorController) {
+	router.GET("/two-factor", controller.GetTwoFactorStatus)
+	router.POST("/two-factor/enroll", controller.EnrollTwoFactor)
+	router.POST("/two-factor/confirm", controller.ConfirmTwoFactor)
+	router.POST("/two-factor/disable", controller.DisableTwoFactor)
+	router.POST("/two-factor/recovery-codes", controller.RegenerateRecoveryCodes)
+	router.DELETE("/users/:id/two-factor", controller.ResetUserTwoFactor)
+}
+
 func RegisterTaxTypeRoutes(router *gin.Engine, controller *controllers.TaxTypeController) {
 	router.GET("/tax-types", controller.GetAllTaxTypes)
 	router.GET("/tax-types/:id", controller.GetTaxTypeByID)
@@ -187,4 +209,73 @@ func RegisterExternalSaleRoutes(router *gin.Engine, controller *controllers.Exte
 }
 func RegisterSalesReportRoutes(router *gin.Engine, controller *controllers.SalesReportController) {
 	router.GET("/sales-report/invoices", controller.GetInvoicesBetweenDates)
+	router.GET("/sales-report/revenue", controller.GetRevenueByPeriod)
+	router.GET("/sales-report/top-items", controller.GetTopItems)
+	router.GET("/sales-report/item-types", controller.GetRevenueByItemType)
+	router.GET("/sales-report/customers", controller.GetRevenueByCustomer)
+	router.GET("/sales-report/sellers", controller.GetRevenueBySeller)
+	router.GET("/sales-report/taxes", controller.GetTaxSummary)
+}
+
+func RegisterPriceListRoutes(router *gin.Engine, controller *controllers.PriceListController) {
+	router.GET("/price-lists", controller.GetAllPriceLists)
+	router.GET("/price-lists/:id", controller.GetPriceListByID)
+	router.GET("/price-lists/customer/:customerID", controller.GetPriceListsForCustomer)
+	router.POST("/price-lists", controller.CreatePriceList)
+	router.PUT("/price-lists/:id", controller.UpdatePriceList)
+}
+
+func RegisterMarginReportRoutes(router *gin.Engine, controller *controllers.MarginReportController) {
+	router.GET("/margin-report/items", controller.GetMarginByItem)
+	router.GET("/margin-report/items/:id/landed-cost", controller.GetItemLandedCost)
+	router.GET("/margin-report/item-types", controller.GetMarginByItemType)
+	router.GET("/margin-report/periods", controller.GetMarginByPeriod)
+}
+
+func RegisterScheduledReportRoutes(router *gin.Engine, controller *controllers.ScheduledReportController) {
+	router.GET("/scheduled-reports", controller.GetAllScheduledReports)
+	router.GET("/scheduled-reports/:id", controller.GetScheduledReportByID)
+	router.GET("/scheduled-reports/:id/runs", controller.GetReportRuns)
+	router.GET("/scheduled-reports/runs/:runId/artifact", controller.DownloadReportArtifact)
+	router.POST("/scheduled-reports", controller.CreateScheduledReport)
+	router.POST("/scheduled-reports/:id/run", controller.RunScheduledReport)
+	router.PUT("/scheduled-reports/:id", controller.UpdateScheduledReport)
+	router.DELETE("/scheduled-reports/:id", controller.DeleteScheduledReport)
+}
+
+func RegisterInvitationRoutes(router *gin.Engine, controller *controllers.InvitationController) {
+	router.GET("/invitations", controller.GetPendingInvitations)
+	router.POST("/invitations", controller.InviteUser)
+	router.POST("/invitations/accept", controller.AcceptInvitation)
+	router.POST("/invitations/:id/resend", controller.ResendInvitation)
+	router.DELETE("/invitations/:id", controller.RevokeInvitation)
+}
+
+func RegisterOIDCRoutes(router *gin.Engine, controller *controllers.OIDCController) {
+	router.GET("/oidc/login", controller.StartLogin)
+	router.POST("/oidc/callback", controller.FinishLogin)
+}
+
+func RegisterSecurityEventRoutes(router *gin.Engine, controller *controllers.SecurityEventController) {
+	router.GET("/security-events", controller.GetAllSecurityEvents)
+}
+
+func RegisterAPIKeyRoutes(router *gin.Engine, controller *controllers.APIKeyController) {
+	router.GET("/api-keys", controller.GetAPIKeys)
+	router.POST("/api-keys", controller.CreateAPIKey)
+	router.POST("/api-keys/:id/rotate", controller.RotateAPIKey)
+	router.DELETE("/api-keys/:id", controller.RevokeAPIKey)
+}
+
+func RegisterSessionRoutes(router *gin.Engine, controller *controllers.SessionController) {
+	router.GET("/sessions", controller.GetOwnSessions)
+	router.DELETE("/sessions/:id", controller.RevokeOwnSession)
+	router.GET("/users/:id/sessions", controller.GetUserSessions)
+	router.DELETE("/users/:id/sessions", controller.RevokeUserSessions)
+	router.DELETE("/users/:id/sessions/:sessionId", controller.RevokeUserSession)
+}
+
+func RegisterHealthRoutes(router *gin.Engine, controller *controllers.HealthController) {
+	router.GET("/healthz", controller.Liveness)
+	router.GET("/readyz", controller.Readiness)
 }
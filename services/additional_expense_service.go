@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,22 +15,22 @@ func NewAdditionalExpenseService(repo *repositories.AdditionalExpenseRepository)
 	return &AdditionalExpenseService{Repo: repo}
 }
 
-func (s *AdditionalExpenseService) GetAllAdditionalExpenses() ([]models.AdditionalExpense, error) {
-	return s.Repo.GetAllAdditionalExpenses()
+func (s *AdditionalExpenseService) GetAllAdditionalExpenses(ctx context.Context, query dtos.ListQuery) ([]models.AdditionalExpense, *dtos.PageInfo, error) {
+	return s.Repo.GetAllAdditionalExpenses(ctx, query)
 }
 
-func (s *AdditionalExpenseService) GetAdditionalExpenseByID(id string) (*models.AdditionalExpense, error) {
-	return s.Repo.GetAdditionalExpenseByID(id)
+func (s *AdditionalExpenseService) GetAdditionalExpenseByID(ctx context.Context, id string) (*models.AdditionalExpense, error) {
+	return s.Repo.GetAdditionalExpenseByID(ctx, id)
 }
 
-func (s *AdditionalExpenseService) CreateAdditionalExpense(expense *models.AdditionalExpense) (*models.AdditionalExpense, error) {
-	return s.Repo.CreateAdditionalExpense(expense)
+func (s *AdditionalExpenseService) CreateAdditionalExpense(ctx context.Context, expense *models.AdditionalExpense) (*models.AdditionalExpense, error) {
+	return s.Repo.CreateAdditionalExpense(ctx, expense)
 }
 
-func (s *AdditionalExpenseService) DeleteAdditionalExpense(id string) error {
-	return s.Repo.DeleteAdditionalExpense(id)
+func (s *AdditionalExpenseService) DeleteAdditionalExpense(ctx context.Context, id string) error {
+	return s.Repo.DeleteAdditionalExpense(ctx, id)
 }
 
-func (s *AdditionalExpenseService) UpdateAdditionalExpense(expense *models.AdditionalExpense) (*models.AdditionalExpense, error) {
-	return s.Repo.UpdateAdditionalExpense(expense)
+func (s *AdditionalExpenseService) UpdateAdditionalExpense(ctx context.Context, expense *models.AdditionalExpense) (*models.AdditionalExpense, error) {
+	return s.Repo.UpdateAdditionalExpense(ctx, expense)
 }
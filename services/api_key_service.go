@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// apiKeyPrefixTag antecede a la parte visible de las llaves para
+	// reconocerlas, por ejemplo en un escaneo de secretos.
+	apiKeyPrefixTag = "tk_"
+	// apiKeyPrefixBytes son los bytes aleatorios del prefijo, que se muestra
+	// en hexadecimal.
+	apiKeyPrefixBytes = 6
+	// apiKeyTouchInterval es cada cuánto, como máximo, se actualiza el último
+	// uso de una llave.
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyService administra las llaves de API de los clientes de máquina y
+// las valida en cada petición. Una llave es tk_<prefijo>_<secreto>; sólo se
+// guarda su SHA-256 y se muestra completa una única vez. Quien crea o rota
+// una llave debe tener todos los permisos que le da, para que las llaves no
+// sirvan para escalar privilegios.
+type APIKeyService struct {
+	Repo     *repositories.APIKeyRepository
+	AuthRepo *repositories.AuthorizationRepository
+	Events   *SecurityEventService
+	Config   config.APIKeyConfig
+}
+
+func NewAPIKeyService(repo *repositories.APIKeyRepository, authRepo *repositories.AuthorizationRepository,
+	events *SecurityEventService, cfg config.APIKeyConfig) *APIKeyService {
+	return &APIKeyService{Repo: repo, AuthRepo: authRepo, Events: events, Config: cfg}
+}
+
+func (s *APIKeyService) GetAPIKeys(ctx context.Context, query dtos.ListQuery) ([]models.APIKey, *dtos.PageInfo, error) {
+	return s.Repo.GetAPIKeys(ctx, query)
+}
+
+// Authenticate devuelve la llave vigente que corresponde a rawKey y registra
+// su uso. Una llave desconocida, vencida o revocada da ErrInvalidAPIKey.
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey, ip string) (*models.APIKey, error) {
+	key, err := s.Repo.GetAPIKeyByHash(ctx, hashSecretToken(rawKey))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, s.reject(ctx, visiblePrefix(rawKey), ip, "unknown key")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, s.reject(ctx, key.Prefix, ip, "expired or revoked key")
+	}
+	if err := s.Repo.TouchAPIKey(ctx, key.ID, ip, now, now.Add(-apiKeyTouchInterval)); err != nil {
+		slog.ErrorContext(ctx, "recording api key use failed", "api_key_id", key.ID, "error", err)
+	}
+	return key, nil
+}
+
+// CreateAPIKey crea la llave y la devuelve junto con su valor completo.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, dto *dtos.CreateAPIKeyDTO, actor string) (*models.APIKey, string, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.Config.DefaultTTL)
+	if dto.ExpiresAt != nil {
+		if !dto.ExpiresAt.After(now) || dto.ExpiresAt.After(now.Add(s.Config.MaxTTL)) {
+			return nil, "", apperrors.ErrAPIKeyExpiration.WithDetail("max_ttl_hours", int(s.Config.MaxTTL.Hours()))
+		}
+		expiresAt = *dto.ExpiresAt
+	}
+
+	permissions, err := s.grantablePermissions(ctx, dto.Permissions, actor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &models.APIKey{
+		Name:          dto.Name,
+		Principal:     dto.Principal,
+		ReporterTaxID: dto.ReporterTaxID,
+		Permissions:   permissions,
+		ExpiresAt:     expiresAt,
+		CreatedBy:     actor,
+		CreatedAt:     now,
+	}
+	rawKey, err := newAPIKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.Repo.CreateAPIKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventAPIKeyCreated, UserEmail: key.Subject(), Actor: actor,
+		Detail: "principal " + key.Principal})
+	return key, rawKey, nil
+}
+
+// RotateAPIKey crea una llave nueva con los mismos datos y permisos y la
+// vigencia configurada. La anterior sigue sirviendo durante el período de
+// gracia, o hasta su vencimiento si es antes.
+func (s *APIKeyService) RotateAPIKey(ctx context.Context, id, actor string) (*models.APIKey, string, error) {
+	old, err := s.Repo.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	if !old.Active(now) || old.ReplacedByID != nil {
+		return nil, "", apperrors.ErrAPIKeyInactive
+	}
+
+	permissionIDs := make([]int, 0, len(old.Permissions))
+	for _, permission := range old.Permissions {
+		permissionIDs = append(permissionIDs, int(permission.ID))
+	}
+	if _, err := s.grantablePermissions(ctx, permissionIDs, actor); err != nil {
+		return nil, "", err
+	}
+
+	key := &models.APIKey{
+		Name:          old.Name,
+		Principal:     old.Principal,
+		ReporterTaxID: old.ReporterTaxID,
+		Permissions:   old.Permissions,
+		ExpiresAt:     now.Add(s.Config.DefaultTTL),
+		CreatedBy:     actor,
+		CreatedAt:     now,
+	}
+	rawKey, err := newAPIKey(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	graceEnd := now.Add(s.Config.RotationGrace)
+	if old.ExpiresAt.Before(graceEnd) {
+		graceEnd = old.ExpiresAt
+	}
+	rotated, err := s.Repo.RotateAPIKey(ctx, old.ID, key, graceEnd)
+	if err != nil {
+		return nil, "", err
+	}
+	if !rotated {
+		return nil, "", apperrors.ErrAPIKeyInactive
+	}
+
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventAPIKeyRotated, UserEmail: old.Subject(), Actor: actor,
+		Detail: "replaced by " + key.Prefix})
+	return key, rawKey, nil
+}
+
+// RevokeAPIKey revoca la llave de inmediato.
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id, actor string) error {
+	key, err := s.Repo.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	revoked, err := s.Repo.RevokeAPIKey(ctx, key.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return apperrors.ErrAPIKeyInactive
+	}
+
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventAPIKeyRevoked, UserEmail: key.Subject(), Actor: actor})
+	return nil
+}
+
+// grantablePermissions quita los repetidos y revisa que actor tenga cada
+// permiso. Una llave no tiene permisos propios, así que otra llave nunca
+// puede crear llaves.
+func (s *APIKeyService) grantablePermissions(ctx context.Context, ids []int, actor string) ([]models.Permission, error) {
+	seen := map[int]bool{}
+	permissions := make([]models.Permission, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		allowed, err := s.AuthRepo.UserHasPermission(ctx, actor, id)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, apperrors.ErrForbidden.WithDetail("permission_id", id)
+		}
+		// Las llaves no tienen alcance de datos: un permiso limitado a los
+		// registros propios no se puede dar
+		_, scoped, err := userScope(ctx, s.AuthRepo, actor, id)
+		if err != nil {
+			return nil, err
+		}
+		if scoped {
+			return nil, apperrors.ErrForbidden.WithDetail("permission_id", id)
+		}
+		permissions = append(permissions, models.Permission{ID: uint(id)})
+	}
+	return permissions, nil
+}
+
+func (s *APIKeyService) reject(ctx context.Context, prefix, ip, reason string) error {
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventAPIKeyRejected, UserEmail: models.APIKeySubjectPrefix + prefix,
+		IP: ip, Detail: reason})
+	return apperrors.ErrInvalidAPIKey
+}
+
+// newAPIKey genera el prefijo y el secreto, guarda en key el prefijo y el
+// hash y devuelve la llave completa.
+func newAPIKey(key *models.APIKey) (string, error) {
+	buf := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	secret, err := newSecretToken()
+	if err != nil {
+		return "", err
+	}
+
+	key.Prefix = apiKeyPrefixTag + hex.EncodeToString(buf)
+	rawKey := key.Prefix + "_" + secret
+	key.KeyHash = hashSecretToken(rawKey)
+	return rawKey, nil
+}
+
+// visiblePrefix es el prefijo de una llave recibida, para el registro de las
+// rechazadas. Si no tiene el formato esperado no se registra nada de ella.
+func visiblePrefix(rawKey string) string {
+	size := len(apiKeyPrefixTag) + 2*apiKeyPrefixBytes
+	if len(rawKey) <= size || !strings.HasPrefix(rawKey, apiKeyPrefixTag) || rawKey[size] != '_' {
+		return "?"
+	}
+	if _, err := hex.DecodeString(rawKey[len(apiKeyPrefixTag):size]); err != nil {
+		return "?"
+	}
+	return rawKey[:size]
+}
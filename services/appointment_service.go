@@ -1,72 +1,77 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"time"
+	"totesbackend/apperrors"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
 type AppointmentService struct {
 	Repo *repositories.AppointmentRepository
+	// MaxPerSlot es el máximo de citas en una misma fecha y hora
+	MaxPerSlot int
 }
 
-func NewAppointmentService(repo *repositories.AppointmentRepository) *AppointmentService {
-	return &AppointmentService{Repo: repo}
+func NewAppointmentService(repo *repositories.AppointmentRepository, maxPerSlot int) *AppointmentService {
+	return &AppointmentService{Repo: repo, MaxPerSlot: maxPerSlot}
 }
 
-func (s *AppointmentService) GetAppointmentByID(id int) (*models.Appointment, error) {
-	return s.Repo.GetAppointmentByID(id)
+func (s *AppointmentService) GetAppointmentByID(ctx context.Context, id int) (*models.Appointment, error) {
+	return s.Repo.GetAppointmentByID(ctx, id)
 }
 
-func (s *AppointmentService) GetAllAppointments() ([]models.Appointment, error) {
-	return s.Repo.GetAllAppointments()
+func (s *AppointmentService) GetAllAppointments(ctx context.Context, query dtos.ListQuery) ([]models.Appointment, *dtos.PageInfo, error) {
+	return s.Repo.GetAllAppointments(ctx, query)
 }
 
-func (s *AppointmentService) SearchAppointmentsByState(state bool) ([]models.Appointment, error) {
-	return s.Repo.SearchAppointmentsByState(state)
+func (s *AppointmentService) SearchAppointmentsByState(ctx context.Context, state bool) ([]models.Appointment, error) {
+	return s.Repo.SearchAppointmentsByState(ctx, state)
 }
 
-func (s *AppointmentService) GetAppointmentsByCustomerID(customerID int) ([]models.Appointment, error) {
-	return s.Repo.GetAppointmentsByCustomerID(customerID)
+func (s *AppointmentService) GetAppointmentsByCustomerID(ctx context.Context, customerID int) ([]models.Appointment, error) {
+	return s.Repo.GetAppointmentsByCustomerID(ctx, customerID)
 }
 
-func (s *AppointmentService) CreateAppointment(appointment models.Appointment) (*models.Appointment, error) {
-	count, err := s.Repo.CountAppointmentsAtDateTime(appointment.DateTime)
+func (s *AppointmentService) CreateAppointment(ctx context.Context, appointment models.Appointment) (*models.Appointment, error) {
+	count, err := s.Repo.CountAppointmentsAtDateTime(ctx, appointment.DateTime)
 	if err != nil {
 		return nil, err
 	}
 
-	if count >= 3 {
-		return nil, errors.New("no hay mas citas disponibles en este horario :v")
+	if count >= int64(s.MaxPerSlot) {
+		return nil, apperrors.ErrAppointmentSlotFull.WithDetail("date_time", appointment.DateTime)
 	}
 
-	return s.Repo.CreateAppointment(&appointment)
+	return s.Repo.CreateAppointment(ctx, &appointment)
 }
 
-func (s *AppointmentService) UpdateAppointment(appointment *models.Appointment) error {
-	return s.Repo.UpdateAppointment(appointment)
+func (s *AppointmentService) UpdateAppointment(ctx context.Context, appointment *models.Appointment) error {
+	return s.Repo.UpdateAppointment(ctx, appointment)
 }
 
-func (s *AppointmentService) SearchAppointmentsByID(query string) ([]models.Appointment, error) {
-	return s.Repo.SearchAppointmentsByID(query)
+func (s *AppointmentService) SearchAppointmentsByID(ctx context.Context, query string) ([]models.Appointment, error) {
+	return s.Repo.SearchAppointmentsByID(ctx, query)
 }
 
-func (s *AppointmentService) SearchAppointmentsByCustomerID(query string) ([]models.Appointment, error) {
-	return s.Repo.SearchAppointmentsByCustomerID(query)
+func (s *AppointmentService) SearchAppointmentsByCustomerID(ctx context.Context, query string) ([]models.Appointment, error) {
+	return s.Repo.SearchAppointmentsByCustomerID(ctx, query)
 }
 
-func (s *AppointmentService) GetAppointmentByCustomerIDAndDate(customerID int, dateTime time.Time) (*models.Appointment, error) {
-	return s.Repo.GetAppointmentByCustomerIDAndDate(customerID, dateTime)
+func (s *AppointmentService) GetAppointmentByCustomerIDAndDate(ctx context.Context, customerID int, dateTime time.Time) (*models.Appointment, error) {
+	return s.Repo.GetAppointmentByCustomerIDAndDate(ctx, customerID, dateTime)
 }
 
-func (s *AppointmentService) DeleteAppointmentByID(id int) error {
-	return s.Repo.DeleteAppointmentByID(id)
+func (s *AppointmentService) DeleteAppointmentByID(ctx context.Context, id int) error {
+	return s.Repo.DeleteAppointmentByID(ctx, id)
 }
 
-func (s *AppointmentService) GetHourlyAppointmentCount(date time.Time) ([]int, error) {
+func (s *AppointmentService) GetHourlyAppointmentCount(ctx context.Context, date time.Time) ([]int, error) {
 	if s.Repo == nil {
 		return nil, errors.New("appointment repository is not initialized")
 	}
-	return s.Repo.CountAppointmentsByHourOnDate(date)
+	return s.Repo.CountAppointmentsByHourOnDate(ctx, date)
 }
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"context"
+	"totesbackend/config"
+	"totesbackend/datascope"
 	"totesbackend/repositories"
 )
 
@@ -13,6 +16,38 @@ func NewAuthorizationService(repo *repositories.AuthorizationRepository, userRep
 	return &AuthorizationService{Repo: repo, UserRepo: userRepo}
 }
 
-func (s *AuthorizationService) UserHasPermission(email string, permissionID int) (bool, error) {
-	return s.Repo.UserHasPermission(email, permissionID)
+func (s *AuthorizationService) UserHasPermission(ctx context.Context, email string, permissionID int) (bool, error) {
+	return s.Repo.UserHasPermission(ctx, email, permissionID)
+}
+
+// GetScope devuelve el alcance de datos con el que el usuario usa el permiso.
+// Sólo hay alcance si el permiso es de lectura de un recurso con reglas (ver
+// config.PermissionScopes) y todos los roles del usuario que se lo dan tienen
+// una regla para ese recurso; basta un rol sin regla para ver todo.
+func (s *AuthorizationService) GetScope(ctx context.Context, email string, permissionID int) (datascope.Scope, bool, error) {
+	return userScope(ctx, s.Repo, email, permissionID)
+}
+
+func userScope(ctx context.Context, repo *repositories.AuthorizationRepository, email string, permissionID int) (datascope.Scope, bool, error) {
+	resource, ok := config.PermissionScopes[permissionID]
+	if !ok {
+		return datascope.Scope{}, false, nil
+	}
+
+	rules, err := repo.GetPermissionRules(ctx, email, permissionID, resource)
+	if err != nil || len(rules) == 0 {
+		return datascope.Scope{}, false, err
+	}
+	for _, rule := range rules {
+		if rule == "" {
+			return datascope.Scope{}, false, nil
+		}
+	}
+
+	employeeID, err := repo.GetEmployeeIDByEmail(ctx, email)
+	if err != nil {
+		return datascope.Scope{}, false, err
+	}
+	// Hoy la única regla es datascope.RuleOwn
+	return datascope.Scope{Resource: resource, Rule: datascope.RuleOwn, EmployeeID: employeeID}, true, nil
 }
@@ -1,70 +1,292 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"strconv"
+	"time"
+	"totesbackend/apperrors"
 	"totesbackend/dtos"
+	"totesbackend/models"
 	"totesbackend/repositories"
+
+	"gorm.io/gorm"
 )
 
 type BillingService struct {
-	Repo         *repositories.ItemRepository
-	DiscountRepo *repositories.DiscountTypeRepository
-	TaxRepo      *repositories.TaxTypeRepository
+	Repo          *repositories.ItemRepository
+	DiscountRepo  *repositories.DiscountTypeRepository
+	TaxRepo       *repositories.TaxTypeRepository
+	PriceRepo     *repositories.HistoricalItemPriceRepository
+	PriceListRepo *repositories.PriceListRepository
+	CustomerRepo  *repositories.CustomerRepository
+	MarginRepo    *repositories.MarginRepository
 }
 
-func NewBillingService(repo *repositories.ItemRepository, discountRepo *repositories.DiscountTypeRepository, taxRepo *repositories.TaxTypeRepository) *BillingService {
-	return &BillingService{Repo: repo, DiscountRepo: discountRepo, TaxRepo: taxRepo}
+func NewBillingService(repo *repositories.ItemRepository, discountRepo *repositories.DiscountTypeRepository,
+	taxRepo *repositories.TaxTypeRepository, priceRepo *repositories.HistoricalItemPriceRepository,
+	priceListRepo *repositories.PriceListRepository, customerRepo *repositories.CustomerRepository,
+	marginRepo *repositories.MarginRepository) *BillingService {
+	return &BillingService{
+		Repo:          repo,
+		DiscountRepo:  discountRepo,
+		TaxRepo:       taxRepo,
+		PriceRepo:     priceRepo,
+		PriceListRepo: priceListRepo,
+		CustomerRepo:  customerRepo,
+		MarginRepo:    marginRepo,
+	}
 }
 
-func (s *BillingService) CalculateSubtotal(itemsDTO []dtos.BillingItemDTO) (float64, error) {
-	var subtotal float64 = 0
+// billingLine es una línea de facturación con el ítem ya resuelto y el precio
+// unitario que se le cobrará al cliente.
+type billingLine struct {
+	Item        *models.Item
+	Quantity    int
+	UnitPrice   float64
+	PriceListID *int
+}
+
+func (l billingLine) Total() float64 {
+	return l.UnitPrice * float64(l.Quantity)
+}
+
+// resolveLines resuelve los ítems y el precio unitario vigente en el instante dado.
+// El precio de lista se toma del historial de precios o, si el ítem no tiene historial
+// para ese instante, de su precio de venta actual. Cuando se indica un cliente, se
+// aplica la primera de sus listas de precios que fije un precio para el ítem.
+func (s *BillingService) resolveLines(ctx context.Context, itemsDTO []dtos.BillingItemDTO, at time.Time, customerID *int) ([]billingLine, error) {
+	priceLists, err := s.customerPriceLists(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
 
+	lines := make([]billingLine, 0, len(itemsDTO))
 	for _, dto := range itemsDTO {
-		item, err := s.Repo.GetItemByID(strconv.Itoa(dto.ID))
+		item, err := s.Repo.GetItemByID(ctx, strconv.Itoa(dto.ID))
 		if err != nil {
-			return 0, errors.New("item not found with ID: " + strconv.Itoa(dto.ID))
+			return nil, apperrors.ErrReferenceNotFound.Wrap(err).WithDetail("item_id", dto.ID)
 		}
-		subtotal += item.SellingPrice * float64(dto.Stock)
+
+		unitPrice := item.SellingPrice
+		price, err := s.PriceRepo.GetPriceAsOf(ctx, item.ID, at)
+		if err == nil {
+			unitPrice = price.Price
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		line := billingLine{Item: item, Quantity: dto.Stock, UnitPrice: unitPrice}
+		for i := range priceLists {
+			if listPrice, ok := priceFromList(&priceLists[i], item, unitPrice); ok {
+				line.UnitPrice = listPrice
+				line.PriceListID = &priceLists[i].ID
+				break
+			}
+		}
+
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
+
+func (s *BillingService) customerPriceLists(ctx context.Context, customerID *int) ([]models.PriceList, error) {
+	if customerID == nil {
+		return nil, nil
 	}
 
-	return subtotal, nil
+	customer, err := s.CustomerRepo.GetCustomerByID(ctx, *customerID)
+	if err != nil {
+		return nil, apperrors.ErrReferenceNotFound.Wrap(err).WithDetail("customer_id", *customerID)
+	}
+	return s.PriceListRepo.GetApplicablePriceLists(ctx, customer.ID, customerSegment(customer))
+}
+
+func linesToBillingItems(lines []billingLine) []dtos.BillingItemDTO {
+	items := make([]dtos.BillingItemDTO, 0, len(lines))
+	for _, line := range lines {
+		items = append(items, dtos.BillingItemDTO{
+			ID:          line.Item.ID,
+			Stock:       line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			PriceListID: line.PriceListID,
+		})
+	}
+	return items
+}
+
+func sumLines(lines []billingLine) float64 {
+	var subtotal float64 = 0
+	for _, line := range lines {
+		subtotal += line.Total()
+	}
+	return subtotal
 }
 
-func (s *BillingService) CalculateTotal(discountTypesIds []string, taxTypesIds []string,
-	itemsDTO []dtos.BillingItemDTO) (float64, error) {
-	subtotal, err := s.CalculateSubtotal(itemsDTO)
+func (s *BillingService) CalculateSubtotal(ctx context.Context, itemsDTO []dtos.BillingItemDTO) (float64, error) {
+	lines, err := s.resolveLines(ctx, itemsDTO, time.Now(), nil)
 	if err != nil {
 		return 0, err
 	}
+	return sumLines(lines), nil
+}
+
+// PriceItems devuelve los ítems con el precio unitario vigente en el instante dado
+// y el subtotal correspondiente, para guardar el precio efectivamente cobrado.
+func (s *BillingService) PriceItems(ctx context.Context, itemsDTO []dtos.BillingItemDTO, at time.Time, customerID *int) ([]dtos.BillingItemDTO, float64, error) {
+	lines, err := s.resolveLines(ctx, itemsDTO, at, customerID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return linesToBillingItems(lines), sumLines(lines), nil
+}
 
-	total := subtotal
+// CalculateTotal calcula el total de la compra. Los descuentos solicitados por ID
+// o por código de cupón se validan contra las condiciones de cada promoción, y las
+// promociones automáticas se aplican cuando el pedido cumple sus condiciones.
+// Los descuentos solicitados que no aplican se devuelven con el motivo del rechazo.
+// Los precios se toman del historial vigente en PriceDate, o en el momento actual si no se indica,
+// y de la lista de precios del cliente cuando se indica uno.
+func (s *BillingService) CalculateTotal(ctx context.Context, request dtos.CalculateTotalRequestDTO) (*dtos.BillingBreakdownDTO, error) {
+	now := time.Now()
+	priceDate := now
+	if request.PriceDate != nil {
+		priceDate = *request.PriceDate
+	}
 
-	for _, discountID := range discountTypesIds {
-		discount, err := s.DiscountRepo.GetDiscountTypeByID(discountID)
+	lines, err := s.resolveLines(ctx, request.ItemsDTO, priceDate, request.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	subtotal := sumLines(lines)
+	breakdown := &dtos.BillingBreakdownDTO{
+		Items:             linesToBillingItems(lines),
+		Subtotal:          subtotal,
+		AppliedDiscounts:  []dtos.AppliedDiscountDTO{},
+		RejectedDiscounts: []dtos.RejectedDiscountDTO{},
+	}
+
+	evaluator := &promotionEvaluator{
+		repo:       s.DiscountRepo,
+		lines:      lines,
+		subtotal:   subtotal,
+		customerID: request.CustomerID,
+		now:        now,
+		applied:    map[int]bool{},
+	}
+
+	for _, id := range request.DiscountTypesIds {
+		discount, err := s.DiscountRepo.GetDiscountTypeByID(ctx, strconv.Itoa(id))
 		if err != nil {
-			return 0, errors.New("discount not found with ID: " + discountID)
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			breakdown.RejectedDiscounts = append(breakdown.RejectedDiscounts, dtos.RejectedDiscountDTO{
+				DiscountTypeID: id,
+				Reason:         DiscountRejectedNotFound,
+				Message:        "discount not found with ID: " + strconv.Itoa(id),
+			})
+			continue
 		}
+		if err := evaluator.request(ctx, breakdown, discount, ""); err != nil {
+			return nil, err
+		}
+	}
 
-		if discount.IsPercentage {
-			total -= (subtotal * (discount.Value / 100))
-		} else {
-			total -= discount.Value
+	for _, code := range request.CouponCodes {
+		discount, err := s.DiscountRepo.GetDiscountTypeByCouponCode(ctx, code)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			breakdown.RejectedDiscounts = append(breakdown.RejectedDiscounts, dtos.RejectedDiscountDTO{
+				CouponCode: code,
+				Reason:     DiscountRejectedInvalidCoupon,
+				Message:    "coupon code does not exist: " + code,
+			})
+			continue
+		}
+		if err := evaluator.request(ctx, breakdown, discount, code); err != nil {
+			return nil, err
+		}
+	}
+
+	automatic, err := s.DiscountRepo.GetAutoApplyDiscountTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range automatic {
+		if err := evaluator.automatic(ctx, breakdown, &automatic[i]); err != nil {
+			return nil, err
 		}
 	}
 
-	for _, taxID := range taxTypesIds {
-		tax, err := s.TaxRepo.GetTaxTypeByID(taxID)
+	for _, applied := range breakdown.AppliedDiscounts {
+		breakdown.DiscountTotal += applied.Amount
+	}
+	if breakdown.DiscountTotal > subtotal {
+		breakdown.DiscountTotal = subtotal
+	}
+
+	for _, taxID := range request.TaxTypesIds {
+		tax, err := s.TaxRepo.GetTaxTypeByID(ctx, strconv.Itoa(taxID))
 		if err != nil {
-			return 0, errors.New("tax not found with ID: " + taxID)
+			return nil, apperrors.ErrReferenceNotFound.Wrap(err).WithDetail("tax_type_id", taxID)
 		}
 
 		if tax.IsPercentage {
-			total += (subtotal * (tax.Value / 100))
+			breakdown.TaxTotal += (subtotal * (tax.Value / 100))
 		} else {
-			total += tax.Value
+			breakdown.TaxTotal += tax.Value
 		}
 	}
 
-	return total, nil
+	breakdown.Total = subtotal - breakdown.DiscountTotal + breakdown.TaxTotal
+
+	breakdown.Warnings, err = s.belowCostWarnings(ctx, lines, subtotal, breakdown.DiscountTotal)
+	if err != nil {
+		return nil, err
+	}
+	return breakdown, nil
+}
+
+// belowCostWarnings advierte de los ítems que se venden por debajo de su costo
+// puesto en destino. El precio de cada línea se compara después de repartir
+// proporcionalmente los descuentos aplicados a la compra.
+func (s *BillingService) belowCostWarnings(ctx context.Context, lines []billingLine, subtotal float64, discountTotal float64) ([]dtos.BelowCostWarningDTO, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+
+	itemIDs := make([]int, 0, len(lines))
+	for _, line := range lines {
+		itemIDs = append(itemIDs, line.Item.ID)
+	}
+	invoiced, err := s.MarginRepo.GetInvoicedUnits(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	netFactor := 1.0
+	if subtotal > 0 {
+		netFactor = (subtotal - discountTotal) / subtotal
+	}
+
+	var warnings []dtos.BelowCostWarningDTO
+	for _, line := range lines {
+		netPrice := line.UnitPrice * netFactor
+		cost := landedUnitCost(line.Item, invoiced[line.Item.ID])
+		if netPrice < cost {
+			warnings = append(warnings, dtos.BelowCostWarningDTO{
+				ItemID:     line.Item.ID,
+				ItemName:   line.Item.Name,
+				UnitPrice:  netPrice,
+				LandedCost: cost,
+				Message:    fmt.Sprintf("item %s is sold at %.2f, below its landed cost", line.Item.Name, netPrice),
+			})
+		}
+	}
+	return warnings, nil
 }
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,30 +15,30 @@ func NewCommentService(repo *repositories.CommentRepository) *CommentService {
 	return &CommentService{Repo: repo}
 }
 
-func (s *CommentService) GetCommentByID(id int) (*models.Comment, error) {
-	return s.Repo.GetCommentByID(id)
+func (s *CommentService) GetCommentByID(ctx context.Context, id int) (*models.Comment, error) {
+	return s.Repo.GetCommentByID(ctx, id)
 }
 
-func (s *CommentService) SearchCommentsByEmail(email string) ([]models.Comment, error) {
-	return s.Repo.SearchCommentsByEmail(email)
+func (s *CommentService) SearchCommentsByEmail(ctx context.Context, email string) ([]models.Comment, error) {
+	return s.Repo.SearchCommentsByEmail(ctx, email)
 }
 
-func (s *CommentService) GetAllComments() ([]models.Comment, error) {
-	return s.Repo.GetAllComments()
+func (s *CommentService) GetAllComments(ctx context.Context, query dtos.ListQuery) ([]models.Comment, *dtos.PageInfo, error) {
+	return s.Repo.GetAllComments(ctx, query)
 }
 
-func (s *CommentService) UpdateComment(comment *models.Comment) error {
-	return s.Repo.UpdateComment(comment)
+func (s *CommentService) UpdateComment(ctx context.Context, comment *models.Comment) error {
+	return s.Repo.UpdateComment(ctx, comment)
 }
 
-func (s *CommentService) CreateComment(comment models.Comment) (*models.Comment, error) {
-	return s.Repo.CreateComment(&comment)
+func (s *CommentService) CreateComment(ctx context.Context, comment models.Comment) (*models.Comment, error) {
+	return s.Repo.CreateComment(ctx, &comment)
 }
 
-func (s *CommentService) SearchCommentsByID(query string) ([]models.Comment, error) {
-	return s.Repo.SearchCommentsByID(query)
+func (s *CommentService) SearchCommentsByID(ctx context.Context, query string) ([]models.Comment, error) {
+	return s.Repo.SearchCommentsByID(ctx, query)
 }
 
-func (s *CommentService) SearchCommentsByName(name string) ([]models.Comment, error) {
-	return s.Repo.SearchCommentsByName(name)
+func (s *CommentService) SearchCommentsByName(ctx context.Context, name string) ([]models.Comment, error) {
+	return s.Repo.SearchCommentsByName(ctx, name)
 }
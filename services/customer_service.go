@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,38 +15,38 @@ func NewCustomerService(repo *repositories.CustomerRepository) *CustomerService
 	return &CustomerService{Repo: repo}
 }
 
-func (s *CustomerService) GetCustomerByID(id int) (*models.Customer, error) {
-	return s.Repo.GetCustomerByID(id)
+func (s *CustomerService) GetCustomerByID(ctx context.Context, id int) (*models.Customer, error) {
+	return s.Repo.GetCustomerByID(ctx, id)
 }
 
-func (s *CustomerService) GetCustomerByCustomerID(customerID string) (*models.Customer, error) {
-	return s.Repo.GetCustomerByCustomerID(customerID)
+func (s *CustomerService) GetCustomerByCustomerID(ctx context.Context, customerID string) (*models.Customer, error) {
+	return s.Repo.GetCustomerByCustomerID(ctx, customerID)
 }
 
-func (s *CustomerService) GetAllCustomers() ([]models.Customer, error) {
-	return s.Repo.GetAllCustomers()
+func (s *CustomerService) GetAllCustomers(ctx context.Context, query dtos.ListQuery) ([]models.Customer, *dtos.PageInfo, error) {
+	return s.Repo.GetAllCustomers(ctx, query)
 }
 
-func (s *CustomerService) GetCustomerByEmail(email string) (*models.Customer, error) {
-	return s.Repo.GetCustomerByEmail(email)
+func (s *CustomerService) GetCustomerByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	return s.Repo.GetCustomerByEmail(ctx, email)
 }
 
-func (s *CustomerService) CreateCustomer(customer models.Customer) (*models.Customer, error) {
-	return s.Repo.CreateCustomer(&customer)
+func (s *CustomerService) CreateCustomer(ctx context.Context, customer models.Customer) (*models.Customer, error) {
+	return s.Repo.CreateCustomer(ctx, &customer)
 }
 
-func (s *CustomerService) UpdateCustomer(customer *models.Customer) error {
-	return s.Repo.UpdateCustomer(customer)
+func (s *CustomerService) UpdateCustomer(ctx context.Context, customer *models.Customer) error {
+	return s.Repo.UpdateCustomer(ctx, customer)
 }
 
-func (s *CustomerService) SearchCustomersByID(id string) ([]models.Customer, error) {
-	return s.Repo.SearchCustomersByID(id)
+func (s *CustomerService) SearchCustomersByID(ctx context.Context, id string) ([]models.Customer, error) {
+	return s.Repo.SearchCustomersByID(ctx, id)
 }
 
-func (s *CustomerService) SearchCustomersByName(name string) ([]models.Customer, error) {
-	return s.Repo.SearchCustomersByName(name)
+func (s *CustomerService) SearchCustomersByName(ctx context.Context, name string) ([]models.Customer, error) {
+	return s.Repo.SearchCustomersByName(ctx, name)
 }
 
-func (s *CustomerService) SearchCustomersByLastName(lastname string) ([]models.Customer, error) {
-	return s.Repo.SearchCustomersByLastName(lastname)
+func (s *CustomerService) SearchCustomersByLastName(ctx context.Context, lastname string) ([]models.Customer, error) {
+	return s.Repo.SearchCustomersByLastName(ctx, lastname)
 }
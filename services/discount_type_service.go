@@ -1,10 +1,23 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"totesbackend/apperrors"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
+
+	"gorm.io/gorm"
 )
 
+// ErrInvalidDiscountType indica que las condiciones de la promoción no son coherentes.
+var ErrInvalidDiscountType = apperrors.New("discount_type.invalid", http.StatusBadRequest, "invalid discount type")
+
 type DiscountTypeService struct {
 	Repo *repositories.DiscountTypeRepository
 }
@@ -13,14 +26,58 @@ func NewDiscountTypeService(repo *repositories.DiscountTypeRepository) *Discount
 	return &DiscountTypeService{Repo: repo}
 }
 
-func (s *DiscountTypeService) GetAllDiscountTypes() ([]models.DiscountType, error) {
-	return s.Repo.GetAllDiscountTypes()
+func (s *DiscountTypeService) GetAllDiscountTypes(ctx context.Context, query dtos.ListQuery) ([]models.DiscountType, *dtos.PageInfo, error) {
+	return s.Repo.GetAllDiscountTypes(ctx, query)
 }
 
-func (s *DiscountTypeService) GetDiscountTypeByID(id string) (*models.DiscountType, error) {
-	return s.Repo.GetDiscountTypeByID(id)
+func (s *DiscountTypeService) GetDiscountTypeByID(ctx context.Context, id string) (*models.DiscountType, error) {
+	return s.Repo.GetDiscountTypeByID(ctx, id)
 }
 
-func (s *DiscountTypeService) CreateDiscountType(discount *models.DiscountType) error {
-	return s.Repo.CreateDiscountType(discount)
+func (s *DiscountTypeService) CreateDiscountType(ctx context.Context, dto *dtos.CreateDiscountTypeDTO) (*models.DiscountType, error) {
+	if dto.ValidFrom != nil && dto.ValidTo != nil && dto.ValidTo.Before(*dto.ValidFrom) {
+		return nil, fmt.Errorf("%w: valid_to must be after valid_from", ErrInvalidDiscountType)
+	}
+	if (dto.BuyQuantity > 0) != (dto.GetQuantity > 0) {
+		return nil, fmt.Errorf("%w: buy_quantity and get_quantity must be set together", ErrInvalidDiscountType)
+	}
+	if dto.MinPurchaseAmount < 0 || dto.MaxUsesPerCustomer < 0 || dto.Value < 0 {
+		return nil, fmt.Errorf("%w: value, min_purchase_amount and max_uses_per_customer cannot be negative", ErrInvalidDiscountType)
+	}
+
+	var couponCode *string
+	if dto.CouponCode != nil && strings.TrimSpace(*dto.CouponCode) != "" {
+		code := strings.ToUpper(strings.TrimSpace(*dto.CouponCode))
+		// El índice único sobre UPPER(coupon_code) lo garantiza; esto sólo da
+		// un error más claro que la violación de la restricción
+		_, err := s.Repo.GetDiscountTypeByCouponCode(ctx, code)
+		if err == nil {
+			return nil, apperrors.ErrConflict.WithDetail("coupon_code", code)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		couponCode = &code
+	}
+
+	discount := &models.DiscountType{
+		Name:               dto.Name,
+		Description:        dto.Description,
+		IsPercentage:       dto.IsPercentage,
+		Value:              dto.Value,
+		CouponCode:         couponCode,
+		AutoApply:          dto.AutoApply,
+		ValidFrom:          dto.ValidFrom,
+		ValidTo:            dto.ValidTo,
+		MinPurchaseAmount:  dto.MinPurchaseAmount,
+		MaxUsesPerCustomer: dto.MaxUsesPerCustomer,
+		BuyQuantity:        dto.BuyQuantity,
+		GetQuantity:        dto.GetQuantity,
+	}
+
+	if err := s.Repo.CreateDiscountType(ctx, discount, dto.ItemIDs, dto.ItemTypeIDs); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetDiscountTypeByID(ctx, strconv.Itoa(discount.ID))
 }
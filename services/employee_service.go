@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,26 +15,26 @@ func NewEmployeeService(repo *repositories.EmployeeRepository) *EmployeeService
 	return &EmployeeService{Repo: repo}
 }
 
-func (s *EmployeeService) GetEmployeeByID(id string) (*models.Employee, error) {
-	return s.Repo.GetEmployeeByID(id)
+func (s *EmployeeService) GetEmployeeByID(ctx context.Context, id string) (*models.Employee, error) {
+	return s.Repo.GetEmployeeByID(ctx, id)
 }
 
-func (s *EmployeeService) SearchEmployeesByID(query string) ([]models.Employee, error) {
-	return s.Repo.SearchEmployeesByID(query)
+func (s *EmployeeService) SearchEmployeesByID(ctx context.Context, query string) ([]models.Employee, error) {
+	return s.Repo.SearchEmployeesByID(ctx, query)
 }
 
-func (s *EmployeeService) SearchEmployeesByName(names string) ([]models.Employee, error) {
-	return s.Repo.SearchEmployeesByName(names)
+func (s *EmployeeService) SearchEmployeesByName(ctx context.Context, names string) ([]models.Employee, error) {
+	return s.Repo.SearchEmployeesByName(ctx, names)
 }
 
-func (s *EmployeeService) GetAllEmployees() ([]models.Employee, error) {
-	return s.Repo.GetAllEmployees()
+func (s *EmployeeService) GetAllEmployees(ctx context.Context, query dtos.ListQuery) ([]models.Employee, *dtos.PageInfo, error) {
+	return s.Repo.GetAllEmployees(ctx, query)
 }
 
-func (s *EmployeeService) UpdateEmployee(employee *models.Employee) error {
-	return s.Repo.UpdateEmployee(employee)
+func (s *EmployeeService) UpdateEmployee(ctx context.Context, employee *models.Employee) error {
+	return s.Repo.UpdateEmployee(ctx, employee)
 }
 
-func (s *EmployeeService) CreateEmployee(employee *models.Employee) (*models.Employee, error) {
-	return s.Repo.CreateEmployee(employee)
+func (s *EmployeeService) CreateEmployee(ctx context.Context, employee *models.Employee) (*models.Employee, error) {
+	return s.Repo.CreateEmployee(ctx, employee)
 }
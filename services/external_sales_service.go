@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 
@@ -17,17 +19,25 @@ func NewExternalSaleService(repo *repositories.ExternalSaleRepository, customerR
 	return &ExternalSaleService{Repo: repo, CustomerRepo: customerRepo}
 }
 
-func (s *ExternalSaleService) GetExternalSaleByID(id string) (*models.ExternalSale, error) {
-	return s.Repo.GetExternalSaleByID(id)
+func (s *ExternalSaleService) GetExternalSaleByID(ctx context.Context, id string) (*models.ExternalSale, error) {
+	return s.Repo.GetExternalSaleByID(ctx, id)
 }
 
-func (s *ExternalSaleService) GetAllExternalSales() ([]models.ExternalSale, error) {
-	return s.Repo.GetAllExternalSales()
+func (s *ExternalSaleService) GetAllExternalSales(ctx context.Context, query dtos.ListQuery) ([]models.ExternalSale, *dtos.PageInfo, error) {
+	return s.Repo.GetAllExternalSales(ctx, query)
 }
 
-func (s *ExternalSaleService) CreateExternalSale(externalSale *models.ExternalSale) (*models.ExternalSale, error) {
+// CreateExternalSale guarda la venta y crea el cliente si no existe. key es la
+// llave de API de la petición, o nil; si es de un revendedor la venta queda a
+// su nombre, sin importar el reportante que traiga.
+func (s *ExternalSaleService) CreateExternalSale(ctx context.Context, externalSale *models.ExternalSale, key *models.APIKey) (*models.ExternalSale, error) {
+	if key != nil && key.IsReporter() {
+		externalSale.ReporterName = key.Principal
+		externalSale.ReporterID = *key.ReporterTaxID
+		externalSale.APIKeyID = &key.ID
+	}
 
-	customer, err := s.CustomerRepo.GetCustomerByEmail(externalSale.Customer.Email)
+	customer, err := s.CustomerRepo.GetCustomerByEmail(ctx, externalSale.Customer.Email)
 	if err != nil {
 
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -35,7 +45,7 @@ func (s *ExternalSaleService) CreateExternalSale(externalSale *models.ExternalSa
 		}
 
 		customer = &externalSale.Customer
-		createdCustomer, err := s.CustomerRepo.CreateCustomer(customer)
+		createdCustomer, err := s.CustomerRepo.CreateCustomer(ctx, customer)
 		if err != nil {
 			return nil, err
 		}
@@ -44,7 +54,7 @@ func (s *ExternalSaleService) CreateExternalSale(externalSale *models.ExternalSa
 
 	externalSale.Customer = *customer
 
-	if err := s.Repo.CreateExternalSale(externalSale); err != nil {
+	if err := s.Repo.CreateExternalSale(ctx, externalSale); err != nil {
 		return nil, err
 	}
 
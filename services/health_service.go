@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/repositories"
+)
+
+const (
+	ReadinessReady        = "ready"
+	ReadinessNotReady     = "not_ready"
+	ReadinessShuttingDown = "shutting_down"
+)
+
+// readinessTimeout limita cada consulta a la base durante el chequeo.
+const readinessTimeout = 2 * time.Second
+
+type HealthService struct {
+	Repo *repositories.HealthRepository
+	// ExpectedSchemaVersion es la última migración que trae el binario.
+	ExpectedSchemaVersion int
+	shuttingDown          atomic.Bool
+}
+
+func NewHealthService(repo *repositories.HealthRepository, expectedSchemaVersion int) *HealthService {
+	return &HealthService{Repo: repo, ExpectedSchemaVersion: expectedSchemaVersion}
+}
+
+// MarkShuttingDown hace que /readyz falle desde ese momento para que el
+// balanceador deje de enviar tráfico mientras se drenan las peticiones.
+func (s *HealthService) MarkShuttingDown() {
+	s.shuttingDown.Store(true)
+}
+
+// CheckReadiness verifica la conexión a la base, la versión del esquema y la
+// saturación del pool.
+func (s *HealthService) CheckReadiness(ctx context.Context) dtos.ReadinessDTO {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	report := dtos.ReadinessDTO{
+		Migrations: dtos.MigrationsCheckDTO{ExpectedVersion: s.ExpectedSchemaVersion},
+	}
+
+	start := time.Now()
+	if err := s.Repo.Ping(ctx); err != nil {
+		report.Database.Error = err.Error()
+	} else {
+		report.Database.OK = true
+	}
+	report.Database.LatencyMs = time.Since(start).Milliseconds()
+
+	if report.Database.OK {
+		version, err := s.Repo.GetSchemaVersion(ctx)
+		if err != nil {
+			report.Migrations.Error = err.Error()
+		} else {
+			report.Migrations.CurrentVersion = version
+			report.Migrations.OK = version >= s.ExpectedSchemaVersion
+		}
+	}
+
+	if stats, err := s.Repo.PoolStats(); err == nil {
+		report.Pool = dtos.PoolCheckDTO{
+			OK:             stats.MaxOpenConnections == 0 || stats.InUse < stats.MaxOpenConnections,
+			MaxOpen:        stats.MaxOpenConnections,
+			Open:           stats.OpenConnections,
+			InUse:          stats.InUse,
+			Idle:           stats.Idle,
+			WaitCount:      stats.WaitCount,
+			WaitDurationMs: stats.WaitDuration.Milliseconds(),
+		}
+	}
+
+	switch {
+	case s.shuttingDown.Load():
+		report.Status = ReadinessShuttingDown
+	case report.Database.OK && report.Migrations.OK && report.Pool.OK:
+		report.Status = ReadinessReady
+	default:
+		report.Status = ReadinessNotReady
+	}
+	return report
+}
@@ -1,19 +1,66 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"totesbackend/apperrors"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+// ErrInvalidScheduledPrice indica que el cambio de precio programado no es válido.
+var ErrInvalidScheduledPrice = apperrors.New("item_price.invalid_schedule", http.StatusBadRequest, "invalid scheduled price")
+
 type HistoricalItemPriceService struct {
-	Repo *repositories.HistoricalItemPriceRepository
+	Repo     *repositories.HistoricalItemPriceRepository
+	ItemRepo *repositories.ItemRepository
 }
 
-func NewHistoricalItemPriceService(repo *repositories.HistoricalItemPriceRepository) *HistoricalItemPriceService {
-	return &HistoricalItemPriceService{Repo: repo}
+func NewHistoricalItemPriceService(repo *repositories.HistoricalItemPriceRepository, itemRepo *repositories.ItemRepository) *HistoricalItemPriceService {
+	return &HistoricalItemPriceService{Repo: repo, ItemRepo: itemRepo}
 }
 
 // Obtener historial de precios de un ítem específico
-func (s *HistoricalItemPriceService) GetHistoricalItemPrice(itemID string) ([]models.HistoricalItemPrice, error) {
-	return s.Repo.GetHistoricalItemPrice(itemID)
+func (s *HistoricalItemPriceService) GetHistoricalItemPrice(ctx context.Context, itemID string) ([]models.HistoricalItemPrice, error) {
+	return s.Repo.GetHistoricalItemPrice(ctx, itemID)
+}
+
+// GetPriceAsOf devuelve el precio de venta del ítem vigente en el instante dado.
+func (s *HistoricalItemPriceService) GetPriceAsOf(ctx context.Context, itemID int, at time.Time) (*models.HistoricalItemPrice, error) {
+	return s.Repo.GetPriceAsOf(ctx, itemID, at)
+}
+
+// SchedulePriceChange programa un nuevo precio de venta a partir de una fecha futura.
+func (s *HistoricalItemPriceService) SchedulePriceChange(ctx context.Context, itemID int, price float64, from time.Time) (*models.HistoricalItemPrice, error) {
+	if price < 0 {
+		return nil, fmt.Errorf("%w: price cannot be negative", ErrInvalidScheduledPrice)
+	}
+	if !from.After(time.Now()) {
+		return nil, fmt.Errorf("%w: effective_from must be in the future", ErrInvalidScheduledPrice)
+	}
+	if _, err := s.ItemRepo.GetItemByID(ctx, strconv.Itoa(itemID)); err != nil {
+		return nil, err
+	}
+	return s.Repo.CreatePriceRange(ctx, itemID, price, from)
+}
+
+// CancelScheduledPrice elimina un cambio de precio programado que todavía no entró en vigencia.
+func (s *HistoricalItemPriceService) CancelScheduledPrice(ctx context.Context, priceID string) error {
+	price, err := s.Repo.GetHistoricalItemPriceByID(ctx, priceID)
+	if err != nil {
+		return err
+	}
+	if !price.EffectiveFrom.After(time.Now()) {
+		return fmt.Errorf("%w: only prices that are not yet effective can be cancelled", ErrInvalidScheduledPrice)
+	}
+	return s.Repo.DeletePriceRange(ctx, price)
+}
+
+// ApplyDuePrices sincroniza el precio de venta de los ítems con los cambios
+// programados que ya entraron en vigencia.
+func (s *HistoricalItemPriceService) ApplyDuePrices(ctx context.Context) (int64, error) {
+	return s.Repo.ApplyDuePrices(ctx, time.Now())
 }
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,10 +15,10 @@ func NewIdentifierTypeService(repo *repositories.IdentifierTypeRepository) *Iden
 	return &IdentifierTypeService{Repo: repo}
 }
 
-func (s *IdentifierTypeService) GetAllIdentifierTypes() ([]models.IdentifierType, error) {
-	return s.Repo.GetAllIdentifierTypes()
+func (s *IdentifierTypeService) GetAllIdentifierTypes(ctx context.Context, query dtos.ListQuery) ([]models.IdentifierType, *dtos.PageInfo, error) {
+	return s.Repo.GetAllIdentifierTypes(ctx, query)
 }
 
-func (s *IdentifierTypeService) GetIdentifierTypeByID(id string) (*models.IdentifierType, error) {
-	return s.Repo.GetIdentifierTypeByID(id)
+func (s *IdentifierTypeService) GetIdentifierTypeByID(ctx context.Context, id string) (*models.IdentifierType, error) {
+	return s.Repo.GetIdentifierTypeByID(ctx, id)
 }
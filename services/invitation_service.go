@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+// InvitationService da de alta usuarios por invitación: el administrador
+// indica el correo y el tipo de usuario, y el invitado elige su contraseña
+// con el token que recibe por correo. Aceptar la invitación verifica además
+// que el correo es suyo.
+type InvitationService struct {
+	Repo     *repositories.InvitationRepository
+	UserRepo *repositories.UserRepository
+	AuthRepo *repositories.AuthorizationRepository
+	Mailer   utils.MailSender
+	Events   *SecurityEventService
+	Policy   PasswordPolicy
+	Hasher   utils.PasswordHasher
+	Config   config.InvitationConfig
+}
+
+func NewInvitationService(repo *repositories.InvitationRepository, userRepo *repositories.UserRepository,
+	authRepo *repositories.AuthorizationRepository, mailer utils.MailSender, events *SecurityEventService, policy PasswordPolicy,
+	hasher utils.PasswordHasher, cfg config.InvitationConfig) *InvitationService {
+	return &InvitationService{
+		Repo:     repo,
+		UserRepo: userRepo,
+		AuthRepo: authRepo,
+		Mailer:   mailer,
+		Events:   events,
+		Policy:   policy,
+		Hasher:   hasher,
+		Config:   cfg,
+	}
+}
+
+func (s *InvitationService) GetPendingInvitations(ctx context.Context, query dtos.ListQuery) ([]models.UserInvitation, *dtos.PageInfo, error) {
+	return s.Repo.GetPendingInvitations(ctx, query)
+}
+
+// InviteUser crea un usuario pendiente, sin contraseña, y le envía la
+// invitación. actor es quien invita y debe tener todos los permisos del tipo
+// de usuario (ver checkGrantableUserType). Si el correo no se pudo enviar la
+// invitación queda con SentAt nil para reenviarla.
+func (s *InvitationService) InviteUser(ctx context.Context, email string, userTypeID int, actor string) (*models.UserInvitation, error) {
+	if err := s.checkGrantableUserType(ctx, userTypeID, actor); err != nil {
+		return nil, err
+	}
+
+	_, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err == nil {
+		return nil, apperrors.ErrEmailTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user := &models.User{Email: email, UserStateTypeID: models.UserStatePending, UserTypeID: userTypeID}
+	invitation := &models.UserInvitation{
+		InvitedBy: actor,
+		TokenHash: hashSecretToken(token),
+		ExpiresAt: now.Add(s.Config.TTL),
+		CreatedAt: now,
+	}
+	if err := s.Repo.CreateInvitation(ctx, user, invitation); err != nil {
+		return nil, err
+	}
+	invitation.User = *user
+
+	s.send(ctx, invitation, token, models.SecurityEventUserInvited, actor)
+	return invitation, nil
+}
+
+// checkGrantableUserType revisa que actor tenga cada permiso del tipo de
+// usuario, para que nadie pueda invitar a alguien con más permisos que él,
+// como en APIKeyService.grantablePermissions. Una llave de API no tiene
+// permisos propios, así que sólo puede invitar a tipos sin permisos.
+func (s *InvitationService) checkGrantableUserType(ctx context.Context, userTypeID int, actor string) error {
+	permissionIDs, err := s.AuthRepo.GetUserTypePermissionIDs(ctx, userTypeID)
+	if err != nil {
+		return err
+	}
+	for _, id := range permissionIDs {
+		allowed, err := s.AuthRepo.UserHasPermission(ctx, actor, id)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return apperrors.ErrForbidden.WithDetail("user_type_id", userTypeID).WithDetail("permission_id", id)
+		}
+	}
+	return nil
+}
+
+// ResendInvitation genera un token nuevo, con un vencimiento nuevo, y vuelve
+// a enviar el correo. El token anterior deja de servir.
+func (s *InvitationService) ResendInvitation(ctx context.Context, id, actor string) (*models.UserInvitation, error) {
+	invitation, err := s.pendingInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	tokenHash := hashSecretToken(token)
+	expiresAt := time.Now().Add(s.Config.TTL)
+	if err := s.Repo.RenewInvitation(ctx, invitation.ID, tokenHash, expiresAt); err != nil {
+		return nil, err
+	}
+	invitation.TokenHash = tokenHash
+	invitation.ExpiresAt = expiresAt
+	invitation.SentAt = nil
+
+	s.send(ctx, invitation, token, models.SecurityEventInvitationResent, actor)
+	return invitation, nil
+}
+
+// RevokeInvitation anula una invitación sin aceptar. El usuario pendiente se
+// borra para que el correo pueda volver a invitarse.
+func (s *InvitationService) RevokeInvitation(ctx context.Context, id, actor string) error {
+	invitation, err := s.pendingInvitation(ctx, id)
+	if err != nil {
+		return err
+	}
+	revoked, err := s.Repo.RevokeInvitation(ctx, invitation)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return apperrors.ErrInvitationAccepted
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventInvitationRevoked, UserEmail: attemptKey(invitation.User.Email),
+		Actor: actor})
+	return nil
+}
+
+// AcceptInvitation guarda la contraseña elegida por el invitado y activa la
+// cuenta. El token sirve una sola vez.
+func (s *InvitationService) AcceptInvitation(ctx context.Context, token, password, ip string) error {
+	invitation, err := s.Repo.GetValidInvitation(ctx, hashSecretToken(token), time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.ErrInvalidInvitationToken
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.Policy.Check(password, invitation.User.Email); err != nil {
+		return err
+	}
+	hashed, err := s.Hasher.Hash(password)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+	accepted, err := s.Repo.AcceptInvitation(ctx, invitation, hashed, time.Now())
+	if err != nil {
+		return err
+	}
+	if !accepted {
+		return apperrors.ErrInvalidInvitationToken
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventInvitationAccepted,
+		UserEmail: attemptKey(invitation.User.Email), IP: ip})
+	return nil
+}
+
+func (s *InvitationService) pendingInvitation(ctx context.Context, id string) (*models.UserInvitation, error) {
+	invitation, err := s.Repo.GetInvitationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.AcceptedAt != nil {
+		return nil, apperrors.ErrInvitationAccepted
+	}
+	return invitation, nil
+}
+
+// send envía el correo de la invitación y registra el evento. Un fallo del
+// envío no se devuelve: queda en el log y la invitación sin SentAt.
+func (s *InvitationService) send(ctx context.Context, invitation *models.UserInvitation, token, eventType, actor string) {
+	event := models.SecurityEvent{Type: eventType, UserEmail: attemptKey(invitation.User.Email), Actor: actor}
+	defer func() { s.Events.Record(ctx, event) }()
+
+	err := s.Mailer.Send([]string{invitation.User.Email}, "You have been invited", s.mailBody(token, invitation.ExpiresAt))
+	if err != nil {
+		slog.ErrorContext(ctx, "sending invitation mail failed", "invitation_id", invitation.ID, "error", err)
+		event.Detail = "mail not sent"
+		return
+	}
+
+	now := time.Now()
+	if err := s.Repo.MarkSent(ctx, invitation.ID, now); err != nil {
+		slog.ErrorContext(ctx, "marking invitation as sent failed", "invitation_id", invitation.ID, "error", err)
+		return
+	}
+	invitation.SentAt = &now
+}
+
+func (s *InvitationService) mailBody(token string, expiresAt time.Time) string {
+	return fmt.Sprintf("An account has been created for you.\n\n"+
+		"Use the following to choose your password and activate the account before %s:\n\n%s\n\n"+
+		"If you were not expecting this invitation, ignore this message.\n",
+		expiresAt.UTC().Format("2006-01-02 15:04 MST"), tokenLink(s.Config.AcceptURL, token))
+}
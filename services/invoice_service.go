@@ -1,9 +1,13 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"math"
 	"strconv"
+	"totesbackend/apperrors"
 	"totesbackend/dtos"
+	"totesbackend/metrics"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -22,63 +26,132 @@ func NewInvoiceService(invoiceRepo *repositories.InvoiceRepository,
 		BillingService: billingService,
 	}
 }
-func (s *InvoiceService) CreateInvoice(dto *dtos.CreateInvoiceDTO) (*models.Invoice, error) {
+
+// CreateInvoice crea la factura y devuelve además las advertencias de los ítems
+// vendidos por debajo de su costo puesto en destino.
+func (s *InvoiceService) CreateInvoice(ctx context.Context, dto *dtos.CreateInvoiceDTO) (*models.Invoice, []dtos.BelowCostWarningDTO, error) {
 	// Verificar si hay suficiente stock para cada item
 	for _, item := range dto.Items {
 		itemID := strconv.Itoa(item.ID)
-		hasStock, err := s.ItemRepo.HasEnoughStock(itemID, item.Stock)
+		hasStock, err := s.ItemRepo.HasEnoughStock(ctx, itemID, item.Stock)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if !hasStock {
-			return nil, errors.New("stock insuficiente para el item con ID " + itemID)
+			metrics.StockOutRejections.WithLabelValues(metrics.StockOperationInvoice).Inc()
+			return nil, nil, fmt.Errorf("%w: item with ID %s", apperrors.ErrInsufficientStock.WithDetail("item_id", item.ID), itemID)
 		}
 	}
 
-	// Calcular subtotal
-	subtotal, err := s.BillingService.CalculateSubtotal(dto.Items)
+	// Calcular total evaluando las promociones solicitadas y las automáticas
+	customerID := dto.CustomerID
+	breakdown, err := s.BillingService.CalculateTotal(ctx, dtos.CalculateTotalRequestDTO{
+		DiscountTypesIds: dto.Discounts,
+		TaxTypesIds:      dto.Taxes,
+		ItemsDTO:         dto.Items,
+		CustomerID:       &customerID,
+		CouponCodes:      dto.CouponCodes,
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Convertir los IDs de descuentos e impuestos a strings
-	var discountIDs []string
-	for _, id := range dto.Discounts {
-		discountIDs = append(discountIDs, strconv.Itoa(id))
+	if len(breakdown.RejectedDiscounts) > 0 {
+		rejected := breakdown.RejectedDiscounts[0]
+		return nil, nil, fmt.Errorf("%w: %s (%s)", apperrors.ErrDiscountRejected, rejected.Message, rejected.Reason)
 	}
 
-	var taxIDs []string
-	for _, id := range dto.Taxes {
-		taxIDs = append(taxIDs, strconv.Itoa(id))
-	}
-
-	// Calcular total
-	total, err := s.BillingService.CalculateTotal(discountIDs, taxIDs, dto.Items)
-	if err != nil {
-		return nil, err
+	// La factura guarda el precio unitario efectivamente cobrado y todos los
+	// descuentos aplicados, incluidos los automáticos
+	dto.Items = breakdown.Items
+	dto.Discounts = nil
+	for _, applied := range breakdown.AppliedDiscounts {
+		dto.Discounts = append(dto.Discounts, applied.DiscountTypeID)
 	}
 
 	// Crear la factura con los valores calculados
-	invoice, err := s.InvoiceRepo.CreateInvoice(dto, subtotal, total)
+	invoice, err := s.InvoiceRepo.CreateInvoice(ctx, dto, repositories.InvoiceTotals{
+		Subtotal:      breakdown.Subtotal,
+		DiscountTotal: breakdown.DiscountTotal,
+		TaxTotal:      breakdown.TaxTotal,
+		Total:         breakdown.Total,
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	metrics.InvoicesCreated.WithLabelValues(metrics.InvoiceSourceDirect).Inc()
 
-	return invoice, nil
+	return invoice, breakdown.Warnings, nil
 }
 
-func (s *InvoiceService) GetInvoiceByID(id string) (*models.Invoice, error) {
-	return s.InvoiceRepo.GetInvoiceByID(id)
+func (s *InvoiceService) GetInvoiceByID(ctx context.Context, id string) (*models.Invoice, error) {
+	return s.InvoiceRepo.GetInvoiceByID(ctx, id)
 }
 
-func (s *InvoiceService) GetAllInvoices() ([]models.Invoice, error) {
-	return s.InvoiceRepo.GetAllInvoices()
+func (s *InvoiceService) GetAllInvoices(ctx context.Context, query dtos.ListQuery) ([]models.Invoice, *dtos.PageInfo, error) {
+	return s.InvoiceRepo.GetAllInvoices(ctx, query)
 }
 
-func (s *InvoiceService) SearchInvoiceByID(query string) ([]models.Invoice, error) {
-	return s.InvoiceRepo.SearchInvoiceByID(query)
+func (s *InvoiceService) SearchInvoiceByID(ctx context.Context, query string) ([]models.Invoice, error) {
+	return s.InvoiceRepo.SearchInvoiceByID(ctx, query)
+}
+
+func (s *InvoiceService) SearchInvoiceByCustomerPersonalId(ctx context.Context, query string) ([]models.Invoice, error) {
+	return s.InvoiceRepo.SearchInvoiceByCustomerPersonalId(ctx, query)
+}
+
+// RecalculateTotals recalcula los importes de las facturas a partir de las
+// líneas e impuestos guardados y devuelve cuántas facturas cambiaron. El
+// descuento registrado se conserva porque las promociones vigentes ya no son
+// las de la fecha de la factura. Se omiten las facturas con alguna línea sin
+// precio unitario, emitidas antes de que se guardara.
+func (s *InvoiceService) RecalculateTotals(ctx context.Context, dryRun bool) (int, error) {
+	changed := 0
+	err := s.InvoiceRepo.FindInvoicesInBatches(ctx, 100, func(invoices []models.Invoice) error {
+		for _, invoice := range invoices {
+			totals, ok := recalculatedInvoiceTotals(invoice)
+			if !ok || !totalsDiffer(invoice, totals) {
+				continue
+			}
+			changed++
+			if dryRun {
+				continue
+			}
+			if err := s.InvoiceRepo.UpdateInvoiceTotals(ctx, invoice.ID, totals); err != nil {
+				return fmt.Errorf("invoice %d: %w", invoice.ID, err)
+			}
+		}
+		return nil
+	})
+	return changed, err
+}
+
+func recalculatedInvoiceTotals(invoice models.Invoice) (repositories.InvoiceTotals, bool) {
+	var totals repositories.InvoiceTotals
+	for _, line := range invoice.Items {
+		if line.UnitPrice == 0 {
+			return totals, false
+		}
+		totals.Subtotal += line.UnitPrice * float64(line.Amount)
+	}
+
+	for _, tax := range invoice.Taxes {
+		if tax.IsPercentage {
+			totals.TaxTotal += totals.Subtotal * (tax.Value / 100)
+		} else {
+			totals.TaxTotal += tax.Value
+		}
+	}
+
+	totals.DiscountTotal = math.Min(invoice.DiscountTotal, totals.Subtotal)
+	totals.Total = totals.Subtotal - totals.DiscountTotal + totals.TaxTotal
+	return totals, true
 }
 
-func (s *InvoiceService) SearchInvoiceByCustomerPersonalId(query string) ([]models.Invoice, error) {
-	return s.InvoiceRepo.SearchInvoiceByCustomerPersonalId(query)
+func totalsDiffer(invoice models.Invoice, totals repositories.InvoiceTotals) bool {
+	const epsilon = 0.005
+	return math.Abs(invoice.Subtotal-totals.Subtotal) > epsilon ||
+		math.Abs(invoice.DiscountTotal-totals.DiscountTotal) > epsilon ||
+		math.Abs(invoice.TaxTotal-totals.TaxTotal) > epsilon ||
+		math.Abs(invoice.Total-totals.Total) > epsilon
 }
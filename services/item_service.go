@@ -1,8 +1,9 @@
 package services
 
 import (
-	"strconv"
+	"context"
 	"time"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -15,34 +16,34 @@ func NewItemService(repo *repositories.ItemRepository) *ItemService {
 	return &ItemService{Repo: repo}
 }
 
-func (s *ItemService) GetItemByID(id string) (*models.Item, error) {
-	return s.Repo.GetItemByID(id)
+func (s *ItemService) GetItemByID(ctx context.Context, id string) (*models.Item, error) {
+	return s.Repo.GetItemByID(ctx, id)
 }
 
-func (s *ItemService) GetAllItems() ([]models.Item, error) {
-	return s.Repo.GetAllItems()
+func (s *ItemService) GetAllItems(ctx context.Context, query dtos.ListQuery) ([]models.Item, *dtos.PageInfo, error) {
+	return s.Repo.GetAllItems(ctx, query)
 }
 
-func (s *ItemService) SearchItemsByID(query string) ([]models.Item, error) {
-	return s.Repo.SearchItemsByID(query)
+func (s *ItemService) SearchItemsByID(ctx context.Context, query string) ([]models.Item, error) {
+	return s.Repo.SearchItemsByID(ctx, query)
 }
 
-func (s *ItemService) SearchItemsByName(query string) ([]models.Item, error) {
-	return s.Repo.SearchItemsByName(query)
+func (s *ItemService) SearchItemsByName(ctx context.Context, query string) ([]models.Item, error) {
+	return s.Repo.SearchItemsByName(ctx, query)
 }
 
-func (s *ItemService) UpdateItemState(id string, state bool) (*models.Item, error) {
-	return s.Repo.UpdateItemState(id, state)
+func (s *ItemService) UpdateItemState(ctx context.Context, id string, state bool) (*models.Item, error) {
+	return s.Repo.UpdateItemState(ctx, id, state)
 }
 
-func (s *ItemService) HasEnoughStock(id string, quantity int) (bool, error) {
-	return s.Repo.HasEnoughStock(id, quantity)
+func (s *ItemService) HasEnoughStock(ctx context.Context, id string, quantity int) (bool, error) {
+	return s.Repo.HasEnoughStock(ctx, id, quantity)
 }
 
-func (s *ItemService) UpdateItem(item *models.Item) error {
+func (s *ItemService) UpdateItem(ctx context.Context, item *models.Item) error {
 	hisRepo := repositories.NewHistoricalItemPriceRepository(s.Repo.DB)
 
-	SellingPriceChanged, err := s.Repo.UpdateItem(item)
+	SellingPriceChanged, err := s.Repo.UpdateItem(ctx, item)
 	if err != nil {
 		return err
 	}
@@ -50,33 +51,25 @@ func (s *ItemService) UpdateItem(item *models.Item) error {
 	if !SellingPriceChanged {
 		return nil
 	}
-	oldItem, _ := s.Repo.GetItemByID(strconv.Itoa(item.ID))
-	historicalPrice := models.HistoricalItemPrice{
-		ItemID:  item.ID,
-		Price:   oldItem.SellingPrice,
-		AddedAt: time.Now(),
-	}
 
-	if err := hisRepo.CreateHistoricalItemPrice(&historicalPrice); err != nil {
+	// El rango del precio anterior se cierra y el nuevo precio queda vigente desde ahora
+	if _, err := hisRepo.CreatePriceRange(ctx, item.ID, item.SellingPrice, time.Now()); err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *ItemService) CreateItem(item *models.Item) (*models.Item, error) {
+func (s *ItemService) CreateItem(ctx context.Context, item *models.Item) (*models.Item, error) {
 	hisRepo := repositories.NewHistoricalItemPriceRepository(s.Repo.DB)
-	item, err := s.Repo.CreateItem(item)
+	item, err := s.Repo.CreateItem(ctx, item)
 
 	if err != nil {
 		return item, err
 	}
 
-	historicalPrice := models.HistoricalItemPrice{
-		ItemID:  item.ID,
-		Price:   item.SellingPrice,
-		AddedAt: time.Now(),
+	if _, err := hisRepo.CreatePriceRange(ctx, item.ID, item.SellingPrice, time.Now()); err != nil {
+		return item, err
 	}
-	hisRepo.CreateHistoricalItemPrice(&historicalPrice)
 
 	return item, err
 }
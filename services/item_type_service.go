@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,10 +15,10 @@ func NewItemTypeService(repo *repositories.ItemTypeRepository) *ItemTypeService
 	return &ItemTypeService{Repo: repo}
 }
 
-func (s *ItemTypeService) GetAllItemTypes() ([]models.ItemType, error) {
-	return s.Repo.GetAllItemTypes()
+func (s *ItemTypeService) GetAllItemTypes(ctx context.Context, query dtos.ListQuery) ([]models.ItemType, *dtos.PageInfo, error) {
+	return s.Repo.GetAllItemTypes(ctx, query)
 }
 
-func (s *ItemTypeService) GetItemTypeByID(id string) (*models.ItemType, error) {
-	return s.Repo.GetItemTypeByID(id)
+func (s *ItemTypeService) GetItemTypeByID(ctx context.Context, id string) (*models.ItemType, error) {
+	return s.Repo.GetItemTypeByID(ctx, id)
 }
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+// LoginGuardService protege todo lo que verifica una contraseña o un código
+// de segundo factor contra intentos repetidos. Cada fallo de una cuenta o de
+// una IP dentro de la ventana duplica la espera antes del siguiente intento y,
+// al llegar a Limits.MaxFailures, la cuenta se bloquea durante
+// Limits.LockDuration.
+type LoginGuardService struct {
+	UserRepo    LoginUserStore
+	AttemptRepo LoginAttemptStore
+	Events      SecurityEventRecorder
+	Hasher      utils.PasswordHasher
+	Limits      config.LoginConfig
+}
+
+// Dependencias de LoginGuardService. Las cumplen UserRepository y
+// LoginAttemptRepository; son interfaces para poder probar el inicio de
+// sesión sin base de datos.
+type (
+	LoginUserStore interface {
+		GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+		LockUser(ctx context.Context, id int, until time.Time) error
+		UnlockUser(ctx context.Context, id int, email string) error
+		RehashPassword(ctx context.Context, id int, oldHash, newHash string) error
+	}
+	LoginAttemptStore interface {
+		CreateLoginAttempt(ctx context.Context, attempt *models.LoginAttempt) error
+		AccountFailures(ctx context.Context, email string, since time.Time) (int64, *time.Time, error)
+		IPFailures(ctx context.Context, ip string, since time.Time) (int64, *time.Time, error)
+	}
+)
+
+func NewLoginGuardService(userRepo *repositories.UserRepository, attemptRepo *repositories.LoginAttemptRepository,
+	events *SecurityEventService, hasher utils.PasswordHasher, limits config.LoginConfig) *LoginGuardService {
+	return &LoginGuardService{UserRepo: userRepo, AttemptRepo: attemptRepo, Events: events, Hasher: hasher, Limits: limits}
+}
+
+// CheckPassword aplica la espera, busca al usuario, levanta el bloqueo si ya
+// venció y verifica la contraseña. Devuelve el usuario si todo es correcto.
+// Si el hash se hizo con otro algoritmo o parámetros lo recalcula con los
+// actuales.
+func (s *LoginGuardService) CheckPassword(ctx context.Context, email, password, ip string) (*models.User, error) {
+	if err := s.Throttle(ctx, email, ip); err != nil {
+		return nil, err
+	}
+
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, s.Fail(ctx, email, ip, nil, apperrors.ErrInvalidCredentials)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.CheckAccount(ctx, user, ip); err != nil {
+		return nil, err
+	}
+	if user.PasswordLoginDisabled {
+		return nil, apperrors.ErrPasswordLoginDisabled
+	}
+
+	ok, needsRehash := s.Hasher.Verify(password, user.Password)
+	if !ok {
+		return nil, s.Fail(ctx, email, ip, user, apperrors.ErrInvalidCredentials)
+	}
+	if needsRehash {
+		s.rehash(ctx, user, password)
+	}
+	return user, nil
+}
+
+// CheckAccount comprueba que la cuenta pueda iniciar sesión, con cualquier
+// método: levanta el bloqueo si ya venció y rechaza las cuentas bloqueadas o
+// no activas.
+func (s *LoginGuardService) CheckAccount(ctx context.Context, user *models.User, ip string) error {
+	if user.UserStateTypeID == models.UserStateLocked {
+		if user.LockedUntil == nil || time.Now().Before(*user.LockedUntil) {
+			return accountLocked(user.LockedUntil)
+		}
+		key := attemptKey(user.Email)
+		if err := s.UserRepo.UnlockUser(ctx, user.ID, key); err != nil {
+			return err
+		}
+		user.UserStateTypeID = models.UserStateActive
+		s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventAccountUnlocked, UserEmail: key, IP: ip,
+			Detail: "lock expired"})
+	}
+
+	if user.UserStateTypeID != models.UserStateActive {
+		return apperrors.ErrUserInactive
+	}
+	return nil
+}
+
+// Throttle devuelve ErrTooManyLoginAttempts si todavía no pasó la espera que
+// imponen los fallos recientes de la cuenta o de la IP.
+func (s *LoginGuardService) Throttle(ctx context.Context, email, ip string) error {
+	key := attemptKey(email)
+	wait, err := s.requiredWait(ctx, key, ip, time.Now())
+	if err != nil {
+		return err
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	seconds := int(math.Ceil(wait.Seconds()))
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventLoginThrottled, UserEmail: key, IP: ip,
+		Detail: fmt.Sprintf("retry after %ds", seconds)})
+	return apperrors.ErrTooManyLoginAttempts.WithDetail("retry_after_seconds", seconds)
+}
+
+// Fail registra el intento fallido y bloquea la cuenta si se llegó al máximo;
+// si no, devuelve cause. user es nil cuando el correo no corresponde a
+// ninguna cuenta; el intento se registra igual para que la espera no revele
+// qué correos existen.
+func (s *LoginGuardService) Fail(ctx context.Context, email, ip string, user *models.User, cause error) error {
+	now := time.Now()
+	key := attemptKey(email)
+	err := s.AttemptRepo.CreateLoginAttempt(ctx, &models.LoginAttempt{Email: key, IP: ip, Success: false, AttemptedAt: now})
+	if err != nil {
+		return err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventLoginFailed, UserEmail: key, IP: ip, Detail: cause.Error()})
+	if user == nil {
+		return cause
+	}
+
+	failures, _, err := s.AttemptRepo.AccountFailures(ctx, key, now.Add(-s.Limits.FailureWindow))
+	if err != nil {
+		return err
+	}
+	if failures < int64(s.Limits.MaxFailures) {
+		return cause
+	}
+
+	until := now.Add(s.Limits.LockDuration)
+	if err := s.UserRepo.LockUser(ctx, user.ID, until); err != nil {
+		return err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventAccountLocked, UserEmail: key, IP: ip,
+		Detail: fmt.Sprintf("%d failed attempts, locked until %s", failures, until.Format(time.RFC3339))})
+	return accountLocked(&until)
+}
+
+// Succeed registra un inicio de sesión exitoso, que reinicia el conteo de
+// fallos de la cuenta. method (password, oidc) queda en el detalle del evento.
+func (s *LoginGuardService) Succeed(ctx context.Context, email, ip, method string) error {
+	key := attemptKey(email)
+	err := s.AttemptRepo.CreateLoginAttempt(ctx, &models.LoginAttempt{Email: key, IP: ip, Success: true, AttemptedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventLoginSucceeded, UserEmail: key, IP: ip, Detail: method})
+	return nil
+}
+
+// rehash recalcula el hash de la contraseña con la configuración actual. Un
+// fallo no impide el inicio de sesión: se vuelve a intentar la próxima vez.
+func (s *LoginGuardService) rehash(ctx context.Context, user *models.User, password string) {
+	hashed, err := s.Hasher.Hash(password)
+	if err == nil {
+		err = s.UserRepo.RehashPassword(ctx, user.ID, user.Password, hashed)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "could not rehash password", "user_id", user.ID, "error", err)
+		return
+	}
+	user.Password = hashed
+}
+
+// requiredWait devuelve cuánto falta para que se permita otro intento: la
+// mayor de las esperas que imponen los fallos recientes de la cuenta y de la IP.
+func (s *LoginGuardService) requiredWait(ctx context.Context, key, ip string, now time.Time) (time.Duration, error) {
+	since := now.Add(-s.Limits.FailureWindow)
+
+	accountFailures, accountLast, err := s.AttemptRepo.AccountFailures(ctx, key, since)
+	if err != nil {
+		return 0, err
+	}
+	wait := remaining(accountLast, s.backoff(accountFailures), now)
+
+	ipFailures, ipLast, err := s.AttemptRepo.IPFailures(ctx, ip, since)
+	if err != nil {
+		return 0, err
+	}
+	// La IP tiene un margen de fallos sin espera porque la pueden compartir
+	// varias personas
+	if excess := ipFailures - int64(s.Limits.IPMaxFailures) + 1; excess > 0 {
+		if ipWait := remaining(ipLast, s.backoff(excess), now); ipWait > wait {
+			wait = ipWait
+		}
+	}
+	return wait, nil
+}
+
+// backoff duplica la espera con cada fallo: BackoffBase tras el primero, el
+// doble tras el segundo y así hasta BackoffMax.
+func (s *LoginGuardService) backoff(failures int64) time.Duration {
+	delay := s.Limits.BackoffBase
+	if failures <= 0 || delay <= 0 {
+		return 0
+	}
+	for i := int64(1); i < failures && delay < s.Limits.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > s.Limits.BackoffMax {
+		delay = s.Limits.BackoffMax
+	}
+	return delay
+}
+
+// attemptKey normaliza el correo: los intentos se cuentan sin distinguir
+// mayúsculas para que cambiarlas no reinicie el conteo.
+func attemptKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func remaining(last *time.Time, delay time.Duration, now time.Time) time.Duration {
+	if last == nil || delay <= 0 {
+		return 0
+	}
+	return last.Add(delay).Sub(now)
+}
+
+func accountLocked(until *time.Time) error {
+	if until == nil {
+		return apperrors.ErrAccountLocked
+	}
+	return apperrors.ErrAccountLocked.WithDetail("locked_until", until.UTC().Format(time.RFC3339))
+}
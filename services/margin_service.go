@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+type MarginService struct {
+	Repo     *repositories.MarginRepository
+	ItemRepo *repositories.ItemRepository
+}
+
+func NewMarginService(repo *repositories.MarginRepository, itemRepo *repositories.ItemRepository) *MarginService {
+	return &MarginService{Repo: repo, ItemRepo: itemRepo}
+}
+
+// GetLandedCost devuelve el costo puesto en destino de un ítem con el detalle
+// de cómo se reparte cada gasto adicional por unidad.
+func (s *MarginService) GetLandedCost(ctx context.Context, id string) (*dtos.LandedCostDTO, error) {
+	item, err := s.ItemRepo.GetItemByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	invoiced, err := s.Repo.GetInvoicedUnits(ctx, []int{item.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dtos.LandedCostDTO{
+		ItemID:        item.ID,
+		ItemName:      item.Name,
+		PurchasePrice: item.PurchasePrice,
+		Expenses:      []dtos.ApportionedExpenseDTO{},
+		LandedCost:    item.PurchasePrice,
+		SellingPrice:  item.SellingPrice,
+	}
+	for _, expense := range item.AdditionalExpenses {
+		units := apportionUnits(item, expense, invoiced[item.ID])
+		perUnit := expense.Expense / float64(units)
+		result.Expenses = append(result.Expenses, dtos.ApportionedExpenseDTO{
+			ExpenseID: expense.ID,
+			Name:      expense.Name,
+			Expense:   expense.Expense,
+			Units:     units,
+			PerUnit:   perUnit,
+		})
+		result.LandedCost += perUnit
+	}
+	return result, nil
+}
+
+// GetMarginByItem calcula el margen de cada ítem facturado en el rango de fechas.
+func (s *MarginService) GetMarginByItem(ctx context.Context, startDate, endDate time.Time) ([]dtos.MarginRowDTO, error) {
+	return s.marginReport(ctx, startDate, endDate, func(line models.InvoiceItem) (string, dtos.MarginRowDTO) {
+		return fmt.Sprintf("%010d", line.ItemID), dtos.MarginRowDTO{ItemID: line.ItemID, ItemName: line.Item.Name}
+	})
+}
+
+// GetMarginByItemType calcula el margen de cada tipo de ítem facturado en el rango de fechas.
+func (s *MarginService) GetMarginByItemType(ctx context.Context, startDate, endDate time.Time) ([]dtos.MarginRowDTO, error) {
+	return s.marginReport(ctx, startDate, endDate, func(line models.InvoiceItem) (string, dtos.MarginRowDTO) {
+		itemType := line.Item.ItemType
+		return fmt.Sprintf("%010d", itemType.ID), dtos.MarginRowDTO{ItemTypeID: itemType.ID, ItemTypeName: itemType.Name}
+	})
+}
+
+// GetMarginByPeriod calcula el margen de lo facturado en el rango de fechas
+// agrupado por día, semana o mes.
+func (s *MarginService) GetMarginByPeriod(ctx context.Context, startDate, endDate time.Time, period string) ([]dtos.MarginRowDTO, error) {
+	period, err := validateReportPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.marginReport(ctx, startDate, endDate, func(line models.InvoiceItem) (string, dtos.MarginRowDTO) {
+		key := periodKey(line.Invoice.DateTime, period)
+		return key, dtos.MarginRowDTO{Period: key}
+	})
+}
+
+// marginReport agrupa las líneas facturadas según la clave que devuelve group y
+// acumula cantidades, ingresos y costo. El costo se calcula con el costo puesto
+// en destino actual de cada ítem.
+func (s *MarginService) marginReport(ctx context.Context, startDate, endDate time.Time,
+	group func(line models.InvoiceItem) (string, dtos.MarginRowDTO)) ([]dtos.MarginRowDTO, error) {
+	lines, err := s.Repo.GetInvoicedLines(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var itemIDs []int
+	for _, line := range lines {
+		itemIDs = append(itemIDs, line.ItemID)
+	}
+	invoiced := map[int]int{}
+	if len(itemIDs) > 0 {
+		invoiced, err = s.Repo.GetInvoicedUnits(ctx, itemIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var keys []string
+	rows := map[string]*dtos.MarginRowDTO{}
+	for _, line := range lines {
+		key, row := group(line)
+		if _, ok := rows[key]; !ok {
+			keys = append(keys, key)
+			rows[key] = &row
+		}
+
+		// Las facturas emitidas antes de guardar el precio cobrado no tienen
+		// precio unitario; se usa el precio de venta actual del ítem.
+		unitPrice := line.UnitPrice
+		if unitPrice == 0 {
+			unitPrice = line.Item.SellingPrice
+		}
+
+		current := rows[key]
+		current.Quantity += line.Amount
+		current.Revenue += unitPrice * float64(line.Amount)
+		current.Cost += landedUnitCost(&line.Item, invoiced[line.ItemID]) * float64(line.Amount)
+	}
+
+	sort.Strings(keys)
+	result := make([]dtos.MarginRowDTO, 0, len(keys))
+	for _, key := range keys {
+		row := rows[key]
+		row.Margin = row.Revenue - row.Cost
+		if row.Revenue != 0 {
+			row.MarginPercent = row.Margin / row.Revenue * 100
+		}
+		result = append(result, *row)
+	}
+	return result, nil
+}
+
+// landedUnitCost calcula el costo puesto en destino de una unidad del ítem: su
+// precio de compra más la parte de cada gasto adicional que le corresponde.
+func landedUnitCost(item *models.Item, invoicedUnits int) float64 {
+	cost := item.PurchasePrice
+	for _, expense := range item.AdditionalExpenses {
+		cost += expense.Expense / float64(apportionUnits(item, expense, invoicedUnits))
+	}
+	return cost
+}
+
+// apportionUnits devuelve entre cuántas unidades se reparte un gasto adicional.
+// Si el gasto no indica sus unidades se reparte entre el stock y lo ya facturado.
+func apportionUnits(item *models.Item, expense models.AdditionalExpense, invoicedUnits int) int {
+	if expense.Units > 0 {
+		return expense.Units
+	}
+	if units := item.Stock + invoicedUnits; units > 0 {
+		return units
+	}
+	return 1
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+// OIDCService inicia sesión con el proveedor de identidad de la empresa. El
+// cliente pide la URL de autorización, el proveedor devuelve al usuario a
+// Config.RedirectURL con code y state, y el cliente los envía a FinishLogin.
+// El correo del ID token debe corresponder a un usuario existente. El segundo
+// factor se pide como en el inicio de sesión con contraseña, salvo que el
+// token acredite uno del proveedor (Config.TrustedAMR).
+type OIDCService struct {
+	Provider  *utils.OIDCProvider
+	Repo      OIDCAuthRequestStore
+	UserRepo  OIDCUserStore
+	Guard     OIDCLoginGuard
+	TwoFactor OIDCTwoFactorVerifier
+	Sessions  SessionCreator
+	Events    SecurityEventRecorder
+	Config    config.OIDCConfig
+}
+
+// Dependencias de OIDCService. Las cumplen OIDCRepository, UserRepository,
+// LoginGuardService y TwoFactorService; son interfaces para poder probar el flujo contra un
+// proveedor de prueba sin base de datos.
+type (
+	OIDCAuthRequestStore interface {
+		CreateAuthRequest(ctx context.Context, request *models.OIDCAuthRequest) error
+		ConsumeAuthRequest(ctx context.Context, stateHash string, now time.Time) (*models.OIDCAuthRequest, error)
+	}
+	OIDCUserStore interface {
+		GetUserByEmailFold(ctx context.Context, email string) (*models.User, error)
+		SetUserType(ctx context.Context, id int, userTypeID int) error
+	}
+	OIDCLoginGuard interface {
+		CheckAccount(ctx context.Context, user *models.User, ip string) error
+		Throttle(ctx context.Context, email, ip string) error
+		Fail(ctx context.Context, email, ip string, user *models.User, cause error) error
+		Succeed(ctx context.Context, email, ip, method string) error
+	}
+	OIDCTwoFactorVerifier interface {
+		VerifyLogin(ctx context.Context, user *models.User, code string) error
+	}
+)
+
+func NewOIDCService(provider *utils.OIDCProvider, repo *repositories.OIDCRepository, userRepo *repositories.UserRepository,
+	guard *LoginGuardService, twoFactor *TwoFactorService, sessions *SessionService, events *SecurityEventService,
+	cfg config.OIDCConfig) *OIDCService {
+	return &OIDCService{Provider: provider, Repo: repo, UserRepo: userRepo, Guard: guard, TwoFactor: twoFactor, Sessions: sessions,
+		Events: events, Config: cfg}
+}
+
+// StartLogin genera state, nonce y el verificador PKCE, los guarda y devuelve
+// la URL del proveedor a la que hay que enviar al usuario y el state, que el
+// controlador deja además en una cookie del navegador (ver FinishLogin).
+func (s *OIDCService) StartLogin(ctx context.Context) (string, string, error) {
+	state, err := newSecretToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := newSecretToken()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := utils.NewPKCEVerifier()
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := s.Provider.AuthCodeURL(ctx, state, nonce, utils.PKCEChallenge(verifier))
+	if err != nil {
+		return "", "", apperrors.ErrSSOFailed.Wrap(err)
+	}
+
+	now := time.Now()
+	err = s.Repo.CreateAuthRequest(ctx, &models.OIDCAuthRequest{
+		StateHash:    hashSecretToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    now.Add(s.Config.LoginTTL),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, state, nil
+}
+
+// FinishLogin canjea el código, valida el ID token y abre una sesión desde
+// device para el usuario con el correo del token; devuelve la sesión y su
+// token. Si los grupos del token corresponden a un tipo de usuario
+// configurado, se le asigna ese tipo. Después se comprueba twoFactorCode como
+// en ValidateUserCredentials, salvo que el proveedor ya haya pedido un segundo
+// factor (ver trustsProviderMFA). browserState es el state de la cookie
+// que recibió el navegador al iniciar el flujo: debe coincidir con state para
+// que nadie pueda hacer que otro complete un inicio de sesión empezado por él.
+func (s *OIDCService) FinishLogin(ctx context.Context, code, state, browserState, twoFactorCode, ip,
+	device string) (*models.UserSession, string, error) {
+	if browserState == "" || subtle.ConstantTimeCompare([]byte(browserState), []byte(state)) != 1 {
+		return nil, "", apperrors.ErrInvalidSSOState
+	}
+
+	request, err := s.Repo.ConsumeAuthRequest(ctx, hashSecretToken(state), time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", apperrors.ErrInvalidSSOState
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	rawToken, err := s.Provider.Exchange(ctx, code, request.CodeVerifier)
+	if err != nil {
+		return nil, "", s.fail(ctx, "", ip, apperrors.ErrSSOFailed.Wrap(err))
+	}
+	claims, err := s.Provider.VerifyIDToken(ctx, rawToken, request.Nonce, time.Now())
+	if err != nil {
+		return nil, "", s.fail(ctx, "", ip, apperrors.ErrSSOFailed.Wrap(err))
+	}
+
+	email, _ := claims[s.Config.EmailClaim].(string)
+	if email == "" {
+		return nil, "", s.fail(ctx, "", ip, apperrors.ErrSSOFailed.Wrap(fmt.Errorf("id token has no %q claim", s.Config.EmailClaim)))
+	}
+	if verified, _ := claims["email_verified"].(bool); !verified && !s.Config.AllowUnverifiedEmail {
+		return nil, "", s.fail(ctx, email, ip, apperrors.ErrSSOFailed.Wrap(errors.New("email is not verified by the identity provider")))
+	}
+
+	user, err := s.UserRepo.GetUserByEmailFold(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", s.fail(ctx, email, ip, apperrors.ErrSSOUnknownUser)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.Guard.CheckAccount(ctx, user, ip); err != nil {
+		return nil, "", s.fail(ctx, user.Email, ip, err)
+	}
+
+	userTypeID := user.UserTypeID
+	if err := s.applyGroups(ctx, user, claims, ip); err != nil {
+		return nil, "", err
+	}
+	if user.UserTypeID != userTypeID {
+		// La política del segundo factor es la del tipo nuevo
+		if user, err = s.UserRepo.GetUserByEmailFold(ctx, user.Email); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if !s.trustsProviderMFA(claims) {
+		if err := s.Guard.Throttle(ctx, user.Email, ip); err != nil {
+			return nil, "", err
+		}
+		if err := s.TwoFactor.VerifyLogin(ctx, user, twoFactorCode); err != nil {
+			if errors.Is(err, apperrors.ErrInvalidTwoFactorCode) {
+				return nil, "", s.Guard.Fail(ctx, user.Email, ip, user, err)
+			}
+			return nil, "", err
+		}
+	}
+
+	if err := s.Guard.Succeed(ctx, user.Email, ip, "oidc"); err != nil {
+		return nil, "", err
+	}
+	return s.Sessions.CreateSession(ctx, user, "oidc", ip, device)
+}
+
+// applyGroups asigna el tipo de usuario del primer grupo configurado que
+// traiga el token. Sin coincidencias el tipo no cambia.
+func (s *OIDCService) applyGroups(ctx context.Context, user *models.User, claims map[string]interface{}, ip string) error {
+	if s.Config.GroupsClaim == "" || len(s.Config.GroupUserTypes) == 0 {
+		return nil
+	}
+	groups := map[string]bool{}
+	for _, group := range utils.ClaimStrings(claims[s.Config.GroupsClaim]) {
+		groups[group] = true
+	}
+
+	for _, mapping := range s.Config.GroupUserTypes {
+		if !groups[mapping.Group] {
+			continue
+		}
+		if user.UserTypeID == mapping.UserTypeID {
+			return nil
+		}
+		if err := s.UserRepo.SetUserType(ctx, user.ID, mapping.UserTypeID); err != nil {
+			return err
+		}
+		s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventUserTypeChanged, UserEmail: attemptKey(user.Email), IP: ip,
+			Detail: fmt.Sprintf("user type %d -> %d from identity provider group %q", user.UserTypeID, mapping.UserTypeID, mapping.Group)})
+		user.UserTypeID = mapping.UserTypeID
+		return nil
+	}
+	return nil
+}
+
+// trustsProviderMFA indica si el claim amr del token trae alguno de los
+// métodos de Config.TrustedAMR, es decir, si el proveedor ya pidió un segundo
+// factor.
+func (s *OIDCService) trustsProviderMFA(claims map[string]interface{}) bool {
+	for _, method := range utils.ClaimStrings(claims["amr"]) {
+		for _, trusted := range s.Config.TrustedAMR {
+			if method == trusted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fail registra el inicio de sesión fallido y devuelve err. No cuenta para la
+// espera entre intentos: no hay contraseña que adivinar.
+func (s *OIDCService) fail(ctx context.Context, email, ip string, err error) error {
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventLoginFailed, UserEmail: attemptKey(email), IP: ip,
+		Detail: "oidc: " + err.Error()})
+	return err
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/services/utils"
+	"totesbackend/services/utils/oidctest"
+
+	"gorm.io/gorm"
+)
+
+const testOIDCClientID = "totes-backend"
+
+// Dobles de las dependencias de OIDCService. El proveedor es el de
+// oidctest, que corre en un httptest.Server.
+type fakeAuthRequests struct {
+	requests map[string]models.OIDCAuthRequest
+}
+
+func (f *fakeAuthRequests) CreateAuthRequest(_ context.Context, request *models.OIDCAuthRequest) error {
+	f.requests[request.StateHash] = *request
+	return nil
+}
+
+func (f *fakeAuthRequests) ConsumeAuthRequest(_ context.Context, stateHash string, now time.Time) (*models.OIDCAuthRequest, error) {
+	request, ok := f.requests[stateHash]
+	delete(f.requests, stateHash)
+	if !ok || !now.Before(request.ExpiresAt) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &request, nil
+}
+
+type fakeOIDCUsers struct {
+	users       map[string]*models.User
+	userTypes   map[int]models.UserType
+	typeChanges map[int]int
+}
+
+func (f *fakeOIDCUsers) GetUserByEmailFold(_ context.Context, email string) (*models.User, error) {
+	user, ok := f.users[strings.ToLower(email)]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (f *fakeOIDCUsers) SetUserType(_ context.Context, id int, userTypeID int) error {
+	f.typeChanges[id] = userTypeID
+	for _, user := range f.users {
+		if user.ID == id {
+			user.UserTypeID = userTypeID
+			user.UserType = f.userTypes[userTypeID]
+		}
+	}
+	return nil
+}
+
+type fakeOIDCGuard struct {
+	succeeded []string
+	failed    []string
+}
+
+func (f *fakeOIDCGuard) CheckAccount(_ context.Context, user *models.User, _ string) error {
+	if user.UserStateTypeID != models.UserStateActive {
+		return apperrors.ErrUserInactive
+	}
+	return nil
+}
+
+func (f *fakeOIDCGuard) Throttle(context.Context, string, string) error {
+	return nil
+}
+
+func (f *fakeOIDCGuard) Fail(_ context.Context, email, _ string, _ *models.User, cause error) error {
+	f.failed = append(f.failed, email)
+	return cause
+}
+
+func (f *fakeOIDCGuard) Succeed(_ context.Context, email, _, method string) error {
+	f.succeeded = append(f.succeeded, email+" "+method)
+	return nil
+}
+
+// fakeOIDCTwoFactor aplica la política de TwoFactorService.VerifyLogin con un
+// código fijo por usuario.
+type fakeOIDCTwoFactor struct {
+	codes map[int]string
+}
+
+func (f *fakeOIDCTwoFactor) VerifyLogin(_ context.Context, user *models.User, code string) error {
+	want, enrolled := f.codes[user.ID]
+	switch {
+	case !enrolled && user.UserType.RequireTwoFactor:
+		return apperrors.ErrTwoFactorEnrollmentRequired
+	case !enrolled:
+		return nil
+	case code == "":
+		return apperrors.ErrTwoFactorRequired
+	case code != want:
+		return apperrors.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+type fakeOIDCSessions struct{}
+
+func (fakeOIDCSessions) CreateSession(_ context.Context, user *models.User, method, ip, device string) (*models.UserSession, string, error) {
+	return &models.UserSession{ID: 1, UserID: user.ID, User: *user, Method: method, IP: ip, Device: device}, "session-token", nil
+}
+
+type fakeEvents struct {
+	events []models.SecurityEvent
+}
+
+func (f *fakeEvents) Record(_ context.Context, event models.SecurityEvent) {
+	f.events = append(f.events, event)
+}
+
+type oidcTestEnv struct {
+	service   *OIDCService
+	idp       *oidctest.IdP
+	users     *fakeOIDCUsers
+	guard     *fakeOIDCGuard
+	twoFactor *fakeOIDCTwoFactor
+	events    *fakeEvents
+}
+
+func newOIDCTestEnv(t *testing.T, configure func(*config.OIDCConfig)) *oidcTestEnv {
+	t.Helper()
+	idp := oidctest.New(t, testOIDCClientID)
+	cfg := config.OIDCConfig{
+		IssuerURL:   idp.Issuer,
+		ClientID:    testOIDCClientID,
+		RedirectURL: "https://app.example.com/sso",
+		EmailClaim:  "email",
+		GroupsClaim: "groups",
+		GroupUserTypes: []config.GroupUserType{
+			{Group: "managers", UserTypeID: 1},
+			{Group: "sellers", UserTypeID: 2},
+			{Group: "admins", UserTypeID: 3},
+		},
+		LoginTTL: 10 * time.Minute,
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	env := &oidcTestEnv{
+		idp: idp,
+		users: &fakeOIDCUsers{
+			users: map[string]*models.User{
+				"ana@example.com":  {ID: 7, Email: "ana@example.com", UserTypeID: 2, UserStateTypeID: models.UserStateActive},
+				"luis@example.com": {ID: 8, Email: "luis@example.com", UserTypeID: 2, UserStateTypeID: models.UserStateInactive},
+			},
+			userTypes: map[int]models.UserType{
+				1: {ID: 1, Name: "Manager"},
+				2: {ID: 2, Name: "Seller"},
+				3: {ID: 3, Name: "Administrator", RequireTwoFactor: true},
+			},
+			typeChanges: map[int]int{},
+		},
+		guard:     &fakeOIDCGuard{},
+		twoFactor: &fakeOIDCTwoFactor{codes: map[int]string{}},
+		events:    &fakeEvents{},
+	}
+	env.service = &OIDCService{
+		Provider:  utils.NewOIDCProvider(cfg),
+		Repo:      &fakeAuthRequests{requests: map[string]models.OIDCAuthRequest{}},
+		UserRepo:  env.users,
+		Guard:     env.guard,
+		TwoFactor: env.twoFactor,
+		Sessions:  fakeOIDCSessions{},
+		Events:    env.events,
+		Config:    cfg,
+	}
+	return env
+}
+
+// login recorre el flujo completo: inicio, autorización en el proveedor con
+// claims y canje con el state que el navegador guardó en la cookie.
+func (env *oidcTestEnv) login(t *testing.T, claims map[string]interface{}) (*models.UserSession, error) {
+	t.Helper()
+	return env.loginWithCode(t, claims, "")
+}
+
+// loginWithCode es login enviando además el código del segundo factor.
+func (env *oidcTestEnv) loginWithCode(t *testing.T, claims map[string]interface{}, twoFactorCode string) (*models.UserSession, error) {
+	t.Helper()
+	ctx := context.Background()
+	authURL, state, err := env.service.StartLogin(ctx)
+	if err != nil {
+		t.Fatalf("StartLogin: %v", err)
+	}
+	code, returnedState, err := env.idp.Authorize(authURL, claims)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	session, _, err := env.service.FinishLogin(ctx, code, returnedState, state, twoFactorCode, "10.0.0.1", "test-agent")
+	return session, err
+}
+
+func TestOIDCFinishLoginOpensSession(t *testing.T) {
+	env := newOIDCTestEnv(t, nil)
+
+	session, err := env.login(t, map[string]interface{}{"email": "Ana@Example.com", "email_verified": true})
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if session.User.ID != 7 || session.Method != "oidc" {
+		t.Errorf("session = user %d method %q, want user 7 method oidc", session.User.ID, session.Method)
+	}
+	if len(env.guard.succeeded) != 1 || env.guard.succeeded[0] != "ana@example.com oidc" {
+		t.Errorf("successful logins = %v", env.guard.succeeded)
+	}
+	if len(env.users.typeChanges) != 0 {
+		t.Errorf("user type changed without groups: %v", env.users.typeChanges)
+	}
+}
+
+func TestOIDCFinishLoginRequiresBrowserState(t *testing.T) {
+	env := newOIDCTestEnv(t, nil)
+	ctx := context.Background()
+
+	authURL, state, err := env.service.StartLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, returnedState, err := env.idp.Authorize(authURL, map[string]interface{}{"email": "ana@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Otro navegador, sin la cookie o con la de su propio inicio de sesión
+	for _, browserState := range []string{"", "state-of-another-login"} {
+		_, _, err = env.service.FinishLogin(ctx, code, returnedState, browserState, "", "10.0.0.1", "test-agent")
+		if !errors.Is(err, apperrors.ErrInvalidSSOState) {
+			t.Errorf("browser state %q: err = %v, want ErrInvalidSSOState", browserState, err)
+		}
+	}
+
+	// El state no se consumió: el navegador que inició el flujo lo termina,
+	// pero sólo una vez
+	if _, _, err := env.service.FinishLogin(ctx, code, returnedState, state, "", "10.0.0.1", "test-agent"); err != nil {
+		t.Fatalf("FinishLogin from the original browser: %v", err)
+	}
+	_, _, err = env.service.FinishLogin(ctx, code, returnedState, state, "", "10.0.0.1", "test-agent")
+	if !errors.Is(err, apperrors.ErrInvalidSSOState) {
+		t.Errorf("reused state: err = %v, want ErrInvalidSSOState", err)
+	}
+}
+
+func TestOIDCFinishLoginRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   *apperrors.Error
+	}{
+		{"nonce mismatch", map[string]interface{}{"email": "ana@example.com", "email_verified": true, "nonce": "another-nonce"}, apperrors.ErrSSOFailed},
+		{"email not verified", map[string]interface{}{"email": "ana@example.com", "email_verified": false}, apperrors.ErrSSOFailed},
+		{"email_verified missing", map[string]interface{}{"email": "ana@example.com"}, apperrors.ErrSSOFailed},
+		{"without email", map[string]interface{}{"email_verified": true}, apperrors.ErrSSOFailed},
+		{"wrong audience", map[string]interface{}{"email": "ana@example.com", "email_verified": true, "aud": "another-client"}, apperrors.ErrSSOFailed},
+		{"expired", map[string]interface{}{"email": "ana@example.com", "email_verified": true, "exp": time.Now().Add(-time.Hour).Unix()}, apperrors.ErrSSOFailed},
+		{"unknown user", map[string]interface{}{"email": "nadie@example.com", "email_verified": true}, apperrors.ErrSSOUnknownUser},
+		{"inactive user", map[string]interface{}{"email": "luis@example.com", "email_verified": true}, apperrors.ErrUserInactive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t, nil)
+			_, err := env.login(t, tt.claims)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %s", err, tt.want.Code)
+			}
+			if len(env.guard.succeeded) != 0 {
+				t.Errorf("failed login recorded as successful: %v", env.guard.succeeded)
+			}
+			if len(env.events.events) == 0 || env.events.events[len(env.events.events)-1].Type != models.SecurityEventLoginFailed {
+				t.Errorf("failed login not recorded as a security event: %+v", env.events.events)
+			}
+		})
+	}
+}
+
+func TestOIDCFinishLoginAcceptsUnverifiedEmailWhenAllowed(t *testing.T) {
+	env := newOIDCTestEnv(t, func(cfg *config.OIDCConfig) { cfg.AllowUnverifiedEmail = true })
+
+	if _, err := env.login(t, map[string]interface{}{"email": "ana@example.com"}); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+}
+
+func TestOIDCFinishLoginMapsGroupsToUserType(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []string
+		want   map[int]int
+	}{
+		{"first configured group wins", []string{"sellers", "managers"}, map[int]int{7: 1}},
+		{"group of the current type", []string{"sellers"}, map[int]int{}},
+		{"no configured group", []string{"marketing"}, map[int]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t, nil)
+			session, err := env.login(t, map[string]interface{}{"email": "ana@example.com", "email_verified": true, "groups": tt.groups})
+			if err != nil {
+				t.Fatalf("FinishLogin: %v", err)
+			}
+			if len(env.users.typeChanges) != len(tt.want) || env.users.typeChanges[7] != tt.want[7] {
+				t.Fatalf("user type changes = %v, want %v", env.users.typeChanges, tt.want)
+			}
+			if want, changed := tt.want[7]; changed && session.User.UserTypeID != want {
+				t.Errorf("session user type = %d, want %d", session.User.UserTypeID, want)
+			}
+		})
+	}
+}
+
+func TestOIDCFinishLoginEnforcesTwoFactor(t *testing.T) {
+	verified := map[string]interface{}{"email": "ana@example.com", "email_verified": true}
+	withAMR := func(amr ...string) map[string]interface{} {
+		return map[string]interface{}{"email": "ana@example.com", "email_verified": true, "amr": amr}
+	}
+	tests := []struct {
+		name       string
+		trusted    []string
+		enrolled   bool
+		claims     map[string]interface{}
+		code       string
+		want       *apperrors.Error
+		wantFailed bool
+	}{
+		{name: "without a code", enrolled: true, claims: verified, want: apperrors.ErrTwoFactorRequired},
+		{name: "wrong code", enrolled: true, claims: verified, code: "000000", want: apperrors.ErrInvalidTwoFactorCode, wantFailed: true},
+		{name: "right code", enrolled: true, claims: verified, code: "123456"},
+		{name: "amr not trusted by default", enrolled: true, claims: withAMR("mfa"), want: apperrors.ErrTwoFactorRequired},
+		{name: "trusted amr", trusted: []string{"mfa", "otp"}, enrolled: true, claims: withAMR("pwd", "otp")},
+		{name: "untrusted amr", trusted: []string{"mfa"}, enrolled: true, claims: withAMR("pwd"), want: apperrors.ErrTwoFactorRequired},
+		{name: "group maps to a type that requires it", claims: map[string]interface{}{"email": "ana@example.com", "email_verified": true,
+			"groups": []string{"admins"}}, want: apperrors.ErrTwoFactorEnrollmentRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newOIDCTestEnv(t, func(cfg *config.OIDCConfig) { cfg.TrustedAMR = tt.trusted })
+			if tt.enrolled {
+				env.twoFactor.codes[7] = "123456"
+			}
+
+			session, err := env.loginWithCode(t, tt.claims, tt.code)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("FinishLogin: %v", err)
+				}
+				if session.User.ID != 7 {
+					t.Errorf("session user = %d, want 7", session.User.ID)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %s", err, tt.want.Code)
+			}
+			if len(env.guard.succeeded) != 0 {
+				t.Errorf("login without the second factor recorded as successful: %v", env.guard.succeeded)
+			}
+			if failed := len(env.guard.failed) > 0; failed != tt.wantFailed {
+				t.Errorf("failed attempts = %v, want recorded %v", env.guard.failed, tt.wantFailed)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,10 +15,10 @@ func NewOrderStateTypeService(repo *repositories.OrderStateTypeRepository) *Orde
 	return &OrderStateTypeService{Repo: repo}
 }
 
-func (s *OrderStateTypeService) GetAllOrderStateTypes() ([]models.OrderStateType, error) {
-	return s.Repo.GetAllOrderStateTypes()
+func (s *OrderStateTypeService) GetAllOrderStateTypes(ctx context.Context, query dtos.ListQuery) ([]models.OrderStateType, *dtos.PageInfo, error) {
+	return s.Repo.GetAllOrderStateTypes(ctx, query)
 }
 
-func (s *OrderStateTypeService) GetOrderStateTypeByID(id string) (*models.OrderStateType, error) {
-	return s.Repo.GetOrderStateTypeByID(id)
+func (s *OrderStateTypeService) GetOrderStateTypeByID(ctx context.Context, id string) (*models.OrderStateType, error) {
+	return s.Repo.GetOrderStateTypeByID(ctx, id)
 }
@@ -1,10 +1,11 @@
 package orderstatemachine
 
 import (
-	"errors"
-	"totesbackend/config"
+	"fmt"
+	"totesbackend/apperrors"
 	"totesbackend/dtos"
 	"totesbackend/models"
+	"totesbackend/repositories"
 )
 
 type ApprovedState struct {
@@ -21,8 +22,9 @@ func NewApprovedState(context *OrderStateMachine) *ApprovedState {
 	var billingItems []dtos.BillingItemDTO
 	for _, item := range po.Items {
 		billingItems = append(billingItems, dtos.BillingItemDTO{
-			ID:    item.ItemID,
-			Stock: item.Amount,
+			ID:        item.ItemID,
+			Stock:     item.Amount,
+			UnitPrice: item.UnitPrice,
 		})
 	}
 
@@ -32,25 +34,37 @@ func NewApprovedState(context *OrderStateMachine) *ApprovedState {
 	}
 
 	var taxIDs []int
+	var taxTotal float64 = 0
 	for _, t := range po.Taxes {
 		taxIDs = append(taxIDs, t.ID)
+		if t.IsPercentage {
+			taxTotal += po.SubTotal * (t.Value / 100)
+		} else {
+			taxTotal += t.Value
+		}
 	}
 
 	dto := &dtos.CreateInvoiceDTO{
-		EnterpriseData: config.ENTERPRISE_INVOICE_DATA, // Se deja vacío por ahora
+		EnterpriseData: context.EnterpriseData,
 		CustomerID: func() int { // Usamos una función anónima para manejar el puntero
 			if po.CustomerID != nil {
 				return *po.CustomerID // Desreferenciamos el puntero
 			}
 			return 0 // Valor por defecto en caso de que sea nil
 		}(),
-		Items:     billingItems,
-		Discounts: discountIDs,
-		Taxes:     taxIDs,
+		Items:           billingItems,
+		Discounts:       discountIDs,
+		Taxes:           taxIDs,
+		PurchaseOrderID: &po.ID,
 	}
 
 	// Crear la factura usando el repositorio
-	invoice, err := invoiceRepo.CreateInvoiceWithoutStockReduction(dto, po.SubTotal, po.Total)
+	invoice, err := invoiceRepo.CreateInvoiceWithoutStockReduction(context.Ctx, dto, repositories.InvoiceTotals{
+		Subtotal:      po.SubTotal,
+		DiscountTotal: po.SubTotal + taxTotal - po.Total,
+		TaxTotal:      taxTotal,
+		Total:         po.Total,
+	})
 	if err != nil {
 		invoice = nil
 	}
@@ -66,7 +80,7 @@ func NewApprovedState(context *OrderStateMachine) *ApprovedState {
 }
 
 func (s *ApprovedState) ChangeState(stateID string) error {
-	return errors.New("cannot change state: approved orders cannot transition to another state")
+	return fmt.Errorf("%w: approved orders cannot transition to another state", apperrors.ErrInvalidStateTransition.WithDetail("state_id", stateID))
 }
 
 func (s *ApprovedState) GetId() int {
@@ -1,7 +1,8 @@
 package orderstatemachine
 
 import (
-	"errors"
+	"fmt"
+	"totesbackend/apperrors"
 	"totesbackend/models"
 )
 
@@ -21,7 +22,7 @@ func NewCancelledState(context *OrderStateMachine) *CancelledState {
 }
 
 func (s *CancelledState) ChangeState(stateID string) error {
-	return errors.New("cannot change state: cancelled orders cannot transition to another state")
+	return fmt.Errorf("%w: cancelled orders cannot transition to another state", apperrors.ErrInvalidStateTransition.WithDetail("state_id", stateID))
 }
 
 func (s *CancelledState) GetId() int {
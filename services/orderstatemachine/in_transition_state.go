@@ -1,8 +1,9 @@
 package orderstatemachine
 
 import (
-	"errors"
+	"fmt"
 	"strconv"
+	"totesbackend/apperrors"
 	"totesbackend/models"
 )
 
@@ -28,7 +29,7 @@ func (s *InTransitState) ChangeState(stateID string) error {
 	case "4":
 		return s.changeInTransitToAccepted(stateID)
 	default:
-		return errors.New("cannot transition from InTransit to this state: " + stateID)
+		return fmt.Errorf("%w: from InTransit to state %s", apperrors.ErrInvalidStateTransition.WithDetail("state_id", stateID), stateID)
 	}
 }
 
@@ -43,15 +44,15 @@ func (s *InTransitState) GetDescription() string {
 func (s *InTransitState) changeInTransitToCancelled(stateID string) error {
 	for _, item := range s.context.PurchaseOrder.Items {
 		itemIDStr := strconv.Itoa(item.ItemID)
-		if err := s.context.ItemRepo.ReturnItemsToInventory(itemIDStr, item.Amount); err != nil {
-			return errors.New("failed to return stock for item with ID: " + itemIDStr + " - " + err.Error())
+		if err := s.context.ItemRepo.ReturnItemsToInventory(s.context.Ctx, itemIDStr, item.Amount); err != nil {
+			return fmt.Errorf("failed to return stock for item with ID %s: %w", itemIDStr, err)
 		}
 	}
 
 	orderIDStr := strconv.Itoa(s.context.PurchaseOrder.ID)
-	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(orderIDStr, stateID)
+	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(s.context.Ctx, orderIDStr, stateID)
 	if err != nil {
-		return errors.New("failed to change purchase order state with ID: " + orderIDStr + " - " + err.Error())
+		return fmt.Errorf("failed to change purchase order state with ID %s: %w", orderIDStr, err)
 	}
 	s.context.PurchaseOrder = newPurchaseOrder
 	s.context.CurrentState = NewCancelledState(s.context)
@@ -62,9 +63,9 @@ func (s *InTransitState) changeInTransitToAccepted(stateID string) error {
 	// TODO: Call invoice repo here
 
 	orderIDStr := strconv.Itoa(s.context.PurchaseOrder.ID)
-	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(orderIDStr, stateID)
+	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(s.context.Ctx, orderIDStr, stateID)
 	if err != nil {
-		return errors.New("failed to accept purchase order with ID: " + orderIDStr + " - " + err.Error())
+		return fmt.Errorf("failed to accept purchase order with ID %s: %w", orderIDStr, err)
 	}
 
 	s.context.PurchaseOrder = newPurchaseOrder
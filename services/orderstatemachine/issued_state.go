@@ -1,8 +1,10 @@
 package orderstatemachine
 
 import (
-	"errors"
+	"fmt"
 	"strconv"
+	"totesbackend/apperrors"
+	"totesbackend/metrics"
 	"totesbackend/models"
 )
 
@@ -29,7 +31,7 @@ func (s *IssuedState) ChangeState(stateID string) error {
 	case "3":
 		return s.changeIssuedToCancelled(stateID)
 	default:
-		return errors.New("no se puede cambiar de Issued a este estado: " + stateID)
+		return fmt.Errorf("%w: from Issued to state %s", apperrors.ErrInvalidStateTransition.WithDetail("state_id", stateID), stateID)
 	}
 
 }
@@ -46,26 +48,27 @@ func (s *IssuedState) changeIssuedToInTransit(stateID string) error {
 
 	for _, item := range s.context.PurchaseOrder.Items {
 		itemIDStr := strconv.Itoa(item.ItemID)
-		hasStock, err := s.context.ItemRepo.HasEnoughStock(itemIDStr, item.Amount)
+		hasStock, err := s.context.ItemRepo.HasEnoughStock(s.context.Ctx, itemIDStr, item.Amount)
 		if err != nil {
-			return errors.New("error checking stock for item with ID: " + itemIDStr + " - " + err.Error())
+			return fmt.Errorf("error checking stock for item with ID %s: %w", itemIDStr, err)
 		}
 		if !hasStock {
-			return errors.New("insufficient stock for item with ID: " + itemIDStr)
+			metrics.StockOutRejections.WithLabelValues(metrics.StockOperationOrderTransit).Inc()
+			return fmt.Errorf("%w: item with ID %s", apperrors.ErrInsufficientStock.WithDetail("item_id", item.ItemID), itemIDStr)
 		}
 	}
 
 	for _, item := range s.context.PurchaseOrder.Items {
 		itemIDStr := strconv.Itoa(item.ItemID)
-		if err := s.context.ItemRepo.SubtractItemsFromInventory(itemIDStr, item.Amount); err != nil {
-			return errors.New("error subtracting stock for item with ID: " + itemIDStr + " - " + err.Error())
+		if err := s.context.ItemRepo.SubtractItemsFromInventory(s.context.Ctx, itemIDStr, item.Amount); err != nil {
+			return fmt.Errorf("error subtracting stock for item with ID %s: %w", itemIDStr, err)
 		}
 	}
 
 	orderIDStr := strconv.Itoa(s.context.PurchaseOrder.ID)
-	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(orderIDStr, stateID)
+	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(s.context.Ctx, orderIDStr, stateID)
 	if err != nil {
-		return errors.New("error changing state of purchase order with ID: " + orderIDStr + " - " + err.Error())
+		return fmt.Errorf("error changing state of purchase order with ID %s: %w", orderIDStr, err)
 	}
 	s.context.PurchaseOrder = newPurchaseOrder
 	s.context.CurrentState = NewInTransitState(s.context)
@@ -75,9 +78,9 @@ func (s *IssuedState) changeIssuedToInTransit(stateID string) error {
 
 func (s *IssuedState) changeIssuedToCancelled(stateID string) error {
 	orderIDStr := strconv.Itoa(s.context.PurchaseOrder.ID)
-	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(orderIDStr, stateID)
+	newPurchaseOrder, err := s.context.PurchaseOrderRepo.ChangePurchaseOrderState(s.context.Ctx, orderIDStr, stateID)
 	if err != nil {
-		return errors.New("error cancelling purchase order with ID: " + orderIDStr + " - " + err.Error())
+		return fmt.Errorf("error cancelling purchase order with ID %s: %w", orderIDStr, err)
 	}
 	s.context.PurchaseOrder = newPurchaseOrder
 	s.context.CurrentState = NewCancelledState(s.context)
@@ -1,6 +1,7 @@
 package orderstatemachine
 
 import (
+	"context"
 	"fmt"
 	"totesbackend/models"
 	"totesbackend/repositories"
@@ -9,22 +10,29 @@ import (
 )
 
 type OrderStateMachine struct {
+	// Ctx es el contexto de la petición que provoca la transición; la máquina
+	// vive solo durante esa petición
+	Ctx               context.Context
 	DB                *gorm.DB
 	CurrentState      OrderState
 	PurchaseOrder     *models.PurchaseOrder
 	ItemRepo          *repositories.ItemRepository
 	PurchaseOrderRepo *repositories.PurchaseOrderRepository
 	InvoiceRepo       *repositories.InvoiceRepository
+	// EnterpriseData es el dato de la empresa de las facturas que se generan
+	EnterpriseData string
 }
 
 // NewStateMachine construye la máquina y setea el estado actual según el estado de la orden
-func NewStateMachine(po *models.PurchaseOrder,
-	itemRepo *repositories.ItemRepository, purchaseOrderRepo *repositories.PurchaseOrderRepository, invoiceRepo *repositories.InvoiceRepository) (*OrderStateMachine, error) {
+func NewStateMachine(ctx context.Context, po *models.PurchaseOrder,
+	itemRepo *repositories.ItemRepository, purchaseOrderRepo *repositories.PurchaseOrderRepository, invoiceRepo *repositories.InvoiceRepository, enterpriseData string) (*OrderStateMachine, error) {
 	sm := &OrderStateMachine{
+		Ctx:               ctx,
 		PurchaseOrder:     po,
 		ItemRepo:          itemRepo,
 		PurchaseOrderRepo: purchaseOrderRepo,
 		InvoiceRepo:       invoiceRepo,
+		EnterpriseData:    enterpriseData,
 	}
 
 	// Determinar estado inicial en base al OrderStateID de la orden
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"unicode"
+)
+
+// maxPasswordBytes es un tope de la política, no del hasher configurado:
+// argon2id no tiene límite, pero se mantiene el de bcrypt (72 bytes) para que
+// una contraseña válida siga funcionando si se vuelve a bcrypt y para acotar
+// el costo de calcular el hash.
+const maxPasswordBytes = 72
+
+// PasswordPolicy valida las contraseñas nuevas según la configuración.
+type PasswordPolicy struct {
+	Config config.PasswordConfig
+}
+
+func NewPasswordPolicy(cfg config.PasswordConfig) PasswordPolicy {
+	return PasswordPolicy{Config: cfg}
+}
+
+// Check devuelve ErrWeakPassword con la lista de reglas incumplidas, o nil.
+func (p PasswordPolicy) Check(password, email string) error {
+	var violations []string
+	if len([]rune(password)) < p.Config.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.Config.MinLength))
+	}
+	if len(password) > maxPasswordBytes {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes long", maxPasswordBytes))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if p.Config.RequireUpper && !upper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.Config.RequireLower && !lower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.Config.RequireDigit && !digit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.Config.RequireSymbol && !symbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	if local, _, ok := strings.Cut(strings.ToLower(email), "@"); ok && len(local) >= 3 &&
+		strings.Contains(strings.ToLower(password), local) {
+		violations = append(violations, "must not contain the email address")
+	}
+
+	if len(violations) > 0 {
+		return apperrors.ErrWeakPassword.WithDetail("violations", violations)
+	}
+	return nil
+}
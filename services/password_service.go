@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+type PasswordService struct {
+	UserRepo *repositories.UserRepository
+	Repo     *repositories.PasswordRepository
+	Guard    *LoginGuardService
+	Mailer   utils.MailSender
+	Events   *SecurityEventService
+	Policy   PasswordPolicy
+	Hasher   utils.PasswordHasher
+	Config   config.PasswordConfig
+}
+
+func NewPasswordService(userRepo *repositories.UserRepository, repo *repositories.PasswordRepository, guard *LoginGuardService,
+	mailer utils.MailSender, events *SecurityEventService, hasher utils.PasswordHasher, cfg config.PasswordConfig) *PasswordService {
+	return &PasswordService{
+		UserRepo: userRepo,
+		Repo:     repo,
+		Guard:    guard,
+		Mailer:   mailer,
+		Events:   events,
+		Policy:   NewPasswordPolicy(cfg),
+		Hasher:   hasher,
+		Config:   cfg,
+	}
+}
+
+// ChangePassword cambia la contraseña del usuario después de verificar la
+// actual. La verificación cuenta para la espera y el bloqueo como un inicio
+// de sesión.
+func (s *PasswordService) ChangePassword(ctx context.Context, email, currentPassword, newPassword, ip string) error {
+	user, err := s.Guard.CheckPassword(ctx, email, currentPassword, ip)
+	if errors.Is(err, apperrors.ErrInvalidCredentials) {
+		return apperrors.ErrWrongCurrentPassword
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.setPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventPasswordChanged, UserEmail: strings.ToLower(user.Email)})
+	return nil
+}
+
+// RequestPasswordReset envía al correo del usuario un token para restablecer
+// la contraseña. No informa si el correo existe: sin cuenta activa o si falla
+// el envío sólo queda el evento y el log.
+func (s *PasswordService) RequestPasswordReset(ctx context.Context, email, ip string) error {
+	event := models.SecurityEvent{Type: models.SecurityEventPasswordResetRequested, UserEmail: strings.ToLower(email), IP: ip}
+
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		event.Detail = "unknown email"
+		s.Events.Record(ctx, event)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.UserStateTypeID == models.UserStateInactive || user.UserStateTypeID == models.UserStatePending {
+		event.Detail = "account is not active"
+		s.Events.Record(ctx, event)
+		return nil
+	}
+	if user.PasswordLoginDisabled {
+		event.Detail = "password login is disabled"
+		s.Events.Record(ctx, event)
+		return nil
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	expiresAt := now.Add(s.Config.ResetTokenTTL)
+	err = s.Repo.CreateResetToken(ctx, &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashSecretToken(token),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.Mailer.Send([]string{user.Email}, "Password reset", s.resetMailBody(token, expiresAt)); err != nil {
+		slog.ErrorContext(ctx, "sending password reset mail failed", "user_id", user.ID, "error", err)
+		event.Detail = "mail not sent"
+	}
+	s.Events.Record(ctx, event)
+	return nil
+}
+
+// ResetPassword cambia la contraseña con un token recibido por correo. El
+// token sirve una sola vez y cambiar la contraseña anula los demás pendientes.
+func (s *PasswordService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	resetToken, err := s.Repo.GetValidResetToken(ctx, hashSecretToken(token), time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperrors.ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	user, err := s.UserRepo.GetUserByID(ctx, fmt.Sprint(resetToken.UserID))
+	if err != nil {
+		return err
+	}
+	if err := s.setPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventPasswordReset, UserEmail: strings.ToLower(user.Email)})
+	return nil
+}
+
+// setPassword valida la contraseña nueva contra la política y el historial y
+// la guarda.
+func (s *PasswordService) setPassword(ctx context.Context, user *models.User, password string) error {
+	if err := s.Policy.Check(password, user.Email); err != nil {
+		return err
+	}
+
+	if s.Config.HistorySize > 0 {
+		if ok, _ := s.Hasher.Verify(password, user.Password); ok {
+			return apperrors.ErrPasswordReused
+		}
+		// El historial guarda las anteriores a la actual, que ya se comparó
+		history, err := s.Repo.GetPasswordHistory(ctx, user.ID, s.Config.HistorySize-1)
+		if err != nil {
+			return err
+		}
+		for _, previous := range history {
+			if ok, _ := s.Hasher.Verify(password, previous.PasswordHash); ok {
+				return apperrors.ErrPasswordReused
+			}
+		}
+	}
+
+	hashed, err := s.Hasher.Hash(password)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+	return s.Repo.SetPassword(ctx, user, hashed, s.Config.HistorySize-1)
+}
+
+func (s *PasswordService) resetMailBody(token string, expiresAt time.Time) string {
+	link := tokenLink(s.Config.ResetURL, token)
+	return fmt.Sprintf("A password reset was requested for your account.\n\n"+
+		"Use the following to choose a new password before %s:\n\n%s\n\n"+
+		"If you did not request it, ignore this message; your password has not changed.\n",
+		expiresAt.UTC().Format("2006-01-02 15:04 MST"), link)
+}
+
+// tokenLink agrega el token a la página del cliente que lo recibe. Sin
+// página devuelve sólo el token.
+func tokenLink(page, token string) string {
+	if page == "" {
+		return token
+	}
+	if strings.Contains(page, "?") {
+		return page + "&token=" + url.QueryEscape(token)
+	}
+	return page + "?token=" + url.QueryEscape(token)
+}
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,18 +15,18 @@ func NewPermissionService(repo *repositories.PermissionRepository) *PermissionSe
 	return &PermissionService{Repo: repo}
 }
 
-func (s *PermissionService) GetPermissionByID(id uint) (*models.Permission, error) {
-	return s.Repo.GetPermissionByID(id)
+func (s *PermissionService) GetPermissionByID(ctx context.Context, id uint) (*models.Permission, error) {
+	return s.Repo.GetPermissionByID(ctx, id)
 }
 
-func (s *PermissionService) GetAllPermissions() ([]models.Permission, error) {
-	return s.Repo.GetAllPermissions()
+func (s *PermissionService) GetAllPermissions(ctx context.Context, query dtos.ListQuery) ([]models.Permission, *dtos.PageInfo, error) {
+	return s.Repo.GetAllPermissions(ctx, query)
 }
 
-func (s *PermissionService) SearchPermissionsByID(query string) ([]models.Permission, error) {
-	return s.Repo.SearchPermissionsByID(query)
+func (s *PermissionService) SearchPermissionsByID(ctx context.Context, query string) ([]models.Permission, error) {
+	return s.Repo.SearchPermissionsByID(ctx, query)
 }
 
-func (s *PermissionService) SearchPermissionsByName(query string) ([]models.Permission, error) {
-	return s.Repo.SearchPermissionsByName(query)
+func (s *PermissionService) SearchPermissionsByName(ctx context.Context, query string) ([]models.Permission, error) {
+	return s.Repo.SearchPermissionsByName(ctx, query)
 }
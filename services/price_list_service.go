@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"totesbackend/apperrors"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+// ErrInvalidPriceList indica que los datos de la lista de precios no son válidos.
+var ErrInvalidPriceList = apperrors.New("price_list.invalid", http.StatusBadRequest, "invalid price list")
+
+type PriceListService struct {
+	Repo         *repositories.PriceListRepository
+	CustomerRepo *repositories.CustomerRepository
+}
+
+func NewPriceListService(repo *repositories.PriceListRepository, customerRepo *repositories.CustomerRepository) *PriceListService {
+	return &PriceListService{Repo: repo, CustomerRepo: customerRepo}
+}
+
+func (s *PriceListService) GetAllPriceLists(ctx context.Context, query dtos.ListQuery) ([]models.PriceList, *dtos.PageInfo, error) {
+	return s.Repo.GetAllPriceLists(ctx, query)
+}
+
+func (s *PriceListService) GetPriceListByID(ctx context.Context, id string) (*models.PriceList, error) {
+	return s.Repo.GetPriceListByID(ctx, id)
+}
+
+// GetPriceListsForCustomer devuelve las listas que aplican al cliente en orden de precedencia.
+func (s *PriceListService) GetPriceListsForCustomer(ctx context.Context, customerID int) ([]models.PriceList, error) {
+	customer, err := s.CustomerRepo.GetCustomerByID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.GetApplicablePriceLists(ctx, customer.ID, customerSegment(customer))
+}
+
+func (s *PriceListService) CreatePriceList(ctx context.Context, dto *dtos.CreatePriceListDTO) (*models.PriceList, error) {
+	priceList, err := priceListFromDTO(dto)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreatePriceList(ctx, priceList, dto.CustomerIDs); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetPriceListByID(ctx, strconv.Itoa(priceList.ID))
+}
+
+func (s *PriceListService) UpdatePriceList(ctx context.Context, id string, dto *dtos.CreatePriceListDTO) (*models.PriceList, error) {
+	existing, err := s.Repo.GetPriceListByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	priceList, err := priceListFromDTO(dto)
+	if err != nil {
+		return nil, err
+	}
+	priceList.ID = existing.ID
+
+	if err := s.Repo.UpdatePriceList(ctx, priceList, dto.CustomerIDs); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetPriceListByID(ctx, id)
+}
+
+func priceListFromDTO(dto *dtos.CreatePriceListDTO) (*models.PriceList, error) {
+	if dto.Segment != "" && dto.Segment != models.PriceListSegmentIndividual && dto.Segment != models.PriceListSegmentBusiness {
+		return nil, fmt.Errorf("%w: segment must be '%s' or '%s'", ErrInvalidPriceList,
+			models.PriceListSegmentIndividual, models.PriceListSegmentBusiness)
+	}
+
+	priceList := &models.PriceList{
+		Name:        dto.Name,
+		Description: dto.Description,
+		Segment:     dto.Segment,
+		Priority:    dto.Priority,
+		Active:      true,
+	}
+	if dto.Active != nil {
+		priceList.Active = *dto.Active
+	}
+
+	seenItems := map[int]bool{}
+	for _, item := range dto.Items {
+		if item.Price < 0 {
+			return nil, fmt.Errorf("%w: price for item %d cannot be negative", ErrInvalidPriceList, item.ItemID)
+		}
+		if seenItems[item.ItemID] {
+			return nil, fmt.Errorf("%w: item %d is listed more than once", ErrInvalidPriceList, item.ItemID)
+		}
+		seenItems[item.ItemID] = true
+		priceList.Items = append(priceList.Items, models.PriceListItem{ItemID: item.ItemID, Price: item.Price})
+	}
+
+	for _, rule := range dto.Rules {
+		if rule.Percentage <= -100 {
+			return nil, fmt.Errorf("%w: rule percentage must be greater than -100", ErrInvalidPriceList)
+		}
+		priceList.Rules = append(priceList.Rules, models.PriceListRule{ItemTypeID: rule.ItemTypeID, Percentage: rule.Percentage})
+	}
+
+	return priceList, nil
+}
+
+// customerSegment devuelve el segmento de precios al que pertenece el cliente.
+func customerSegment(customer *models.Customer) string {
+	if customer.IsBusiness {
+		return models.PriceListSegmentBusiness
+	}
+	return models.PriceListSegmentIndividual
+}
+
+// priceFromList calcula el precio de un ítem según una lista. Un precio fijo para
+// el ítem tiene prioridad; si no existe, se aplica la regla de su tipo de ítem y,
+// en su defecto, la regla general de la lista. Devuelve false si la lista no fija
+// precio para el ítem.
+func priceFromList(priceList *models.PriceList, item *models.Item, listPrice float64) (float64, bool) {
+	for _, listItem := range priceList.Items {
+		if listItem.ItemID == item.ID {
+			return listItem.Price, true
+		}
+	}
+
+	var general *models.PriceListRule
+	for i, rule := range priceList.Rules {
+		if rule.ItemTypeID == nil {
+			if general == nil {
+				general = &priceList.Rules[i]
+			}
+			continue
+		}
+		if *rule.ItemTypeID == item.ItemTypeID {
+			return listPrice * (1 + rule.Percentage/100), true
+		}
+	}
+
+	if general != nil {
+		return listPrice * (1 + general.Percentage/100), true
+	}
+	return 0, false
+}
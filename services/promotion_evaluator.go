@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+// Motivos por los que un descuento solicitado puede ser rechazado.
+const (
+	DiscountRejectedNotFound          = "not_found"
+	DiscountRejectedInvalidCoupon     = "invalid_coupon"
+	DiscountRejectedCouponRequired    = "coupon_required"
+	DiscountRejectedNotYetValid       = "not_yet_valid"
+	DiscountRejectedExpired           = "expired"
+	DiscountRejectedMinPurchase       = "min_purchase_not_met"
+	DiscountRejectedCustomerRequired  = "customer_required"
+	DiscountRejectedUsageLimitReached = "usage_limit_reached"
+	DiscountRejectedNoEligibleItems   = "no_eligible_items"
+	DiscountRejectedBuyQuantityNotMet = "buy_quantity_not_met"
+	DiscountRejectedAlreadyApplied    = "already_applied"
+)
+
+type promotionRejection struct {
+	Reason  string
+	Message string
+}
+
+// promotionEvaluator evalúa las condiciones de las promociones contra un pedido
+// concreto y acumula los descuentos aplicados en el desglose de facturación.
+type promotionEvaluator struct {
+	repo       *repositories.DiscountTypeRepository
+	lines      []billingLine
+	subtotal   float64
+	customerID *int
+	now        time.Time
+	applied    map[int]bool
+}
+
+// request evalúa un descuento pedido explícitamente por ID o por cupón.
+// Si no aplica, se agrega a los descuentos rechazados con su motivo.
+func (e *promotionEvaluator) request(ctx context.Context, breakdown *dtos.BillingBreakdownDTO, discount *models.DiscountType, couponCode string) error {
+	if e.applied[discount.ID] {
+		breakdown.RejectedDiscounts = append(breakdown.RejectedDiscounts, dtos.RejectedDiscountDTO{
+			DiscountTypeID: discount.ID,
+			CouponCode:     couponCode,
+			Reason:         DiscountRejectedAlreadyApplied,
+			Message:        "discount is already applied to this purchase",
+		})
+		return nil
+	}
+
+	amount, rejection, err := e.evaluate(ctx, discount, couponCode != "")
+	if err != nil {
+		return err
+	}
+	if rejection != nil {
+		breakdown.RejectedDiscounts = append(breakdown.RejectedDiscounts, dtos.RejectedDiscountDTO{
+			DiscountTypeID: discount.ID,
+			CouponCode:     couponCode,
+			Reason:         rejection.Reason,
+			Message:        rejection.Message,
+		})
+		return nil
+	}
+
+	e.apply(breakdown, discount, amount, false)
+	return nil
+}
+
+// automatic evalúa una promoción de aplicación automática. Las promociones
+// automáticas que no aplican se ignoran sin reportarse como rechazadas.
+func (e *promotionEvaluator) automatic(ctx context.Context, breakdown *dtos.BillingBreakdownDTO, discount *models.DiscountType) error {
+	if e.applied[discount.ID] || discount.CouponCode != nil {
+		return nil
+	}
+
+	amount, rejection, err := e.evaluate(ctx, discount, false)
+	if err != nil || rejection != nil {
+		return err
+	}
+
+	e.apply(breakdown, discount, amount, true)
+	return nil
+}
+
+func (e *promotionEvaluator) apply(breakdown *dtos.BillingBreakdownDTO, discount *models.DiscountType, amount float64, automatic bool) {
+	e.applied[discount.ID] = true
+	breakdown.AppliedDiscounts = append(breakdown.AppliedDiscounts, dtos.AppliedDiscountDTO{
+		DiscountTypeID: discount.ID,
+		Name:           discount.Name,
+		Amount:         amount,
+		Automatic:      automatic,
+	})
+}
+
+func (e *promotionEvaluator) evaluate(ctx context.Context, discount *models.DiscountType, viaCoupon bool) (float64, *promotionRejection, error) {
+	if discount.CouponCode != nil && !viaCoupon {
+		return 0, &promotionRejection{DiscountRejectedCouponRequired, "discount can only be applied with its coupon code"}, nil
+	}
+
+	if discount.ValidFrom != nil && e.now.Before(*discount.ValidFrom) {
+		return 0, &promotionRejection{DiscountRejectedNotYetValid, "discount is valid from " + discount.ValidFrom.Format(time.RFC3339)}, nil
+	}
+
+	if discount.ValidTo != nil && e.now.After(*discount.ValidTo) {
+		return 0, &promotionRejection{DiscountRejectedExpired, "discount expired on " + discount.ValidTo.Format(time.RFC3339)}, nil
+	}
+
+	if e.subtotal < discount.MinPurchaseAmount {
+		return 0, &promotionRejection{DiscountRejectedMinPurchase,
+			fmt.Sprintf("minimum purchase amount is %.2f, current subtotal is %.2f", discount.MinPurchaseAmount, e.subtotal)}, nil
+	}
+
+	// El conteo sirve para el cálculo; al crear la factura se vuelve a revisar
+	// con el cliente bloqueado (ver InvoiceRepository.CreateInvoice)
+	if discount.MaxUsesPerCustomer > 0 {
+		if e.customerID == nil {
+			return 0, &promotionRejection{DiscountRejectedCustomerRequired, "discount is limited per customer and no customer was given"}, nil
+		}
+		uses, err := e.repo.CountCustomerUsages(ctx, discount.ID, *e.customerID)
+		if err != nil {
+			return 0, nil, err
+		}
+		if uses >= int64(discount.MaxUsesPerCustomer) {
+			return 0, &promotionRejection{DiscountRejectedUsageLimitReached,
+				"customer already used this discount " + strconv.FormatInt(uses, 10) + " time(s)"}, nil
+		}
+	}
+
+	eligible := e.eligibleLines(discount)
+	if len(eligible) == 0 {
+		return 0, &promotionRejection{DiscountRejectedNoEligibleItems, "no item in the purchase is targeted by this discount"}, nil
+	}
+
+	if discount.BuyQuantity > 0 && discount.GetQuantity > 0 {
+		amount := buyXGetYAmount(discount, eligible)
+		if amount == 0 {
+			return 0, &promotionRejection{DiscountRejectedBuyQuantityNotMet,
+				fmt.Sprintf("buy %d get %d requires at least %d units of an eligible item",
+					discount.BuyQuantity, discount.GetQuantity, discount.BuyQuantity+discount.GetQuantity)}, nil
+		}
+		return amount, nil, nil
+	}
+
+	base := sumLines(eligible)
+	if discount.IsPercentage {
+		return base * (discount.Value / 100), nil, nil
+	}
+	if discount.Value > base {
+		return base, nil, nil
+	}
+	return discount.Value, nil, nil
+}
+
+// eligibleLines devuelve las líneas a las que aplica el descuento. Un descuento
+// sin ítems ni tipos de ítem asociados aplica a todo el pedido.
+func (e *promotionEvaluator) eligibleLines(discount *models.DiscountType) []billingLine {
+	if len(discount.Items) == 0 && len(discount.ItemTypes) == 0 {
+		return e.lines
+	}
+
+	itemIDs := map[int]bool{}
+	for _, item := range discount.Items {
+		itemIDs[item.ID] = true
+	}
+	itemTypeIDs := map[int]bool{}
+	for _, itemType := range discount.ItemTypes {
+		itemTypeIDs[itemType.ID] = true
+	}
+
+	var eligible []billingLine
+	for _, line := range e.lines {
+		if itemIDs[line.Item.ID] || itemTypeIDs[line.Item.ItemTypeID] {
+			eligible = append(eligible, line)
+		}
+	}
+	return eligible
+}
+
+// buyXGetYAmount calcula el descuento de una promoción "compra X lleva Y":
+// por cada grupo completo de X+Y unidades de un mismo ítem, Y unidades son gratis.
+func buyXGetYAmount(discount *models.DiscountType, lines []billingLine) float64 {
+	group := discount.BuyQuantity + discount.GetQuantity
+	var amount float64 = 0
+	for _, line := range lines {
+		freeUnits := (line.Quantity / group) * discount.GetQuantity
+		amount += float64(freeUnits) * line.UnitPrice
+	}
+	return amount
+}
@@ -1,9 +1,14 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"fmt"
+	"math"
 	"strconv"
+	"time"
+	"totesbackend/apperrors"
 	"totesbackend/dtos"
+	"totesbackend/metrics"
 	"totesbackend/models"
 	"totesbackend/repositories"
 	"totesbackend/services/orderstatemachine"
@@ -14,39 +19,43 @@ type PurchaseOrderService struct {
 	ItemRepo          *repositories.ItemRepository
 	InvoiceRepo       *repositories.InvoiceRepository
 	BillingService    *BillingService
+	EnterpriseData    string
 }
 
 func NewPurchaseOrderService(purchaseOrderRepo *repositories.PurchaseOrderRepository,
-	itemRepo *repositories.ItemRepository, billingService *BillingService, invoiceRepo *repositories.InvoiceRepository) *PurchaseOrderService {
+	itemRepo *repositories.ItemRepository, billingService *BillingService, invoiceRepo *repositories.InvoiceRepository, enterpriseData string) *PurchaseOrderService {
 	return &PurchaseOrderService{
 		PurchaseOrderRepo: purchaseOrderRepo,
 		ItemRepo:          itemRepo,
 		BillingService:    billingService,
 		InvoiceRepo:       invoiceRepo,
+		EnterpriseData:    enterpriseData,
 	}
 }
 
-func (s *PurchaseOrderService) CreatePurchaseOrder(dto *dtos.CreatePurchaseOrderDTO) (*models.PurchaseOrder, error) {
+func (s *PurchaseOrderService) CreatePurchaseOrder(ctx context.Context, dto *dtos.CreatePurchaseOrderDTO) (*models.PurchaseOrder, error) {
 	// Verificar stock de los items
 	for _, item := range dto.Items {
 		itemID := strconv.Itoa(item.ID)
-		hasStock, err := s.ItemRepo.HasEnoughStock(itemID, item.Stock)
+		hasStock, err := s.ItemRepo.HasEnoughStock(ctx, itemID, item.Stock)
 		if err != nil {
 			return nil, err
 		}
 		if !hasStock {
-			return nil, errors.New("stock insuficiente para el item con ID " + itemID)
+			metrics.StockOutRejections.WithLabelValues(metrics.StockOperationPurchaseOrder).Inc()
+			return nil, fmt.Errorf("%w: item with ID %s", apperrors.ErrInsufficientStock.WithDetail("item_id", item.ID), itemID)
 		}
 	}
 
-	// Calcular subtotal
-	subtotal, err := s.BillingService.CalculateSubtotal(dto.Items)
+	// Calcular subtotal con los precios vigentes, que quedan registrados en la orden
+	pricedItems, subtotal, err := s.BillingService.PriceItems(ctx, dto.Items, time.Now(), nil)
 	if err != nil {
 		return nil, err
 	}
+	dto.Items = pricedItems
 
 	// Crear la orden de compra
-	purchaseOrder, err := s.PurchaseOrderRepo.CreatePurchaseOrder(dto, subtotal, subtotal)
+	purchaseOrder, err := s.PurchaseOrderRepo.CreatePurchaseOrder(ctx, dto, subtotal, subtotal)
 	if err != nil {
 		return nil, err
 	}
@@ -54,33 +63,34 @@ func (s *PurchaseOrderService) CreatePurchaseOrder(dto *dtos.CreatePurchaseOrder
 	return purchaseOrder, nil
 }
 
-func (s *PurchaseOrderService) GetPurchaseOrderByID(id string) (*models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetPurchaseOrderByID(id)
+func (s *PurchaseOrderService) GetPurchaseOrderByID(ctx context.Context, id string) (*models.PurchaseOrder, error) {
+	return s.PurchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
 }
 
-func (s *PurchaseOrderService) GetAllPurchaseOrders() ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetAllPurchaseOrders()
+func (s *PurchaseOrderService) GetAllPurchaseOrders(ctx context.Context, query dtos.ListQuery) ([]models.PurchaseOrder, *dtos.PageInfo, error) {
+	return s.PurchaseOrderRepo.GetAllPurchaseOrders(ctx, query)
 }
 
-func (s *PurchaseOrderService) SearchPurchaseOrdersByID(query string) ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.SearchPurchaseOrdersByID(query)
+func (s *PurchaseOrderService) SearchPurchaseOrdersByID(ctx context.Context, query string) ([]models.PurchaseOrder, error) {
+	return s.PurchaseOrderRepo.SearchPurchaseOrdersByID(ctx, query)
 }
 
-func (s *PurchaseOrderService) GetPurchaseOrdersByCustomerID(customerID string) ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetPurchaseOrdersByCustomerID(customerID)
+func (s *PurchaseOrderService) GetPurchaseOrdersByCustomerID(ctx context.Context, customerID string) ([]models.PurchaseOrder, error) {
+	return s.PurchaseOrderRepo.GetPurchaseOrdersByCustomerID(ctx, customerID)
 }
 
-func (s *PurchaseOrderService) GetPurchaseOrdersBySellerID(sellerID string) ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetPurchaseOrdersBySellerID(sellerID)
+func (s *PurchaseOrderService) GetPurchaseOrdersBySellerID(ctx context.Context, sellerID string) ([]models.PurchaseOrder, error) {
+	return s.PurchaseOrderRepo.GetPurchaseOrdersBySellerID(ctx, sellerID)
 }
 
-func (s *PurchaseOrderService) ChangePurchaseOrderState(id string, targetStateID string) (*models.PurchaseOrder, *models.Invoice, error) {
-	po, err := s.PurchaseOrderRepo.GetPurchaseOrderByID(id)
+func (s *PurchaseOrderService) ChangePurchaseOrderState(ctx context.Context, id string, targetStateID string) (*models.PurchaseOrder, *models.Invoice, error) {
+	po, err := s.PurchaseOrderRepo.GetPurchaseOrderByID(ctx, id)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	stateMachine, err := orderstatemachine.NewStateMachine(po, s.ItemRepo, s.PurchaseOrderRepo, s.InvoiceRepo)
+	fromStateID := po.OrderStateID
+	stateMachine, err := orderstatemachine.NewStateMachine(ctx, po, s.ItemRepo, s.PurchaseOrderRepo, s.InvoiceRepo, s.EnterpriseData)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -88,17 +98,62 @@ func (s *PurchaseOrderService) ChangePurchaseOrderState(id string, targetStateID
 	if err := stateMachine.ChangeState(targetStateID); err != nil {
 		return nil, nil, err
 	}
+	metrics.OrderTransitions.WithLabelValues(strconv.Itoa(fromStateID), strconv.Itoa(stateMachine.CurrentState.GetId())).Inc()
+
 	if generator, ok := stateMachine.CurrentState.(orderstatemachine.InvoiceGenerator); ok {
-		return stateMachine.PurchaseOrder, generator.GetGeneratedInvoice(), nil
+		invoice := generator.GetGeneratedInvoice()
+		if invoice != nil {
+			metrics.InvoicesCreated.WithLabelValues(metrics.InvoiceSourcePurchaseOrder).Inc()
+		}
+		return stateMachine.PurchaseOrder, invoice, nil
 	}
 
 	return stateMachine.PurchaseOrder, nil, nil
 }
 
-func (s *PurchaseOrderService) UpdatePurchaseOrder(purchaseOrder *models.PurchaseOrder) error {
-	return s.PurchaseOrderRepo.UpdatePurchaseOrder(purchaseOrder)
+func (s *PurchaseOrderService) UpdatePurchaseOrder(ctx context.Context, purchaseOrder *models.PurchaseOrder) error {
+	return s.PurchaseOrderRepo.UpdatePurchaseOrder(ctx, purchaseOrder)
+}
+
+func (s *PurchaseOrderService) GetPurchaseOrdersByStateID(ctx context.Context, stateID string) ([]models.PurchaseOrder, error) {
+	if _, err := strconv.Atoi(stateID); err != nil {
+		return nil, apperrors.ErrInvalidOrderState.WithDetail("state_id", stateID)
+	}
+	return s.PurchaseOrderRepo.GetPurchaseOrdersByStateID(ctx, stateID)
+}
+
+// RecalculateTotals recalcula el subtotal de las órdenes de compra a partir de
+// las líneas guardadas y devuelve cuántas órdenes cambiaron. Como al crearlas,
+// el total es igual al subtotal. Se omiten las órdenes con alguna línea sin
+// precio unitario, creadas antes de que se guardara.
+func (s *PurchaseOrderService) RecalculateTotals(ctx context.Context, dryRun bool) (int, error) {
+	changed := 0
+	err := s.PurchaseOrderRepo.FindPurchaseOrdersInBatches(ctx, 100, func(purchaseOrders []models.PurchaseOrder) error {
+		for _, purchaseOrder := range purchaseOrders {
+			subtotal, ok := purchaseOrderSubtotal(purchaseOrder)
+			if !ok || (math.Abs(purchaseOrder.SubTotal-subtotal) <= 0.005 && math.Abs(purchaseOrder.Total-subtotal) <= 0.005) {
+				continue
+			}
+			changed++
+			if dryRun {
+				continue
+			}
+			if err := s.PurchaseOrderRepo.UpdatePurchaseOrderTotals(ctx, purchaseOrder.ID, subtotal, subtotal); err != nil {
+				return fmt.Errorf("purchase order %d: %w", purchaseOrder.ID, err)
+			}
+		}
+		return nil
+	})
+	return changed, err
 }
 
-func (s *PurchaseOrderService) GetPurchaseOrdersByStateID(stateID string) ([]models.PurchaseOrder, error) {
-	return s.PurchaseOrderRepo.GetPurchaseOrdersByStateID(stateID)
+func purchaseOrderSubtotal(purchaseOrder models.PurchaseOrder) (float64, bool) {
+	subtotal := 0.0
+	for _, line := range purchaseOrder.Items {
+		if line.UnitPrice == 0 {
+			return 0, false
+		}
+		subtotal += line.UnitPrice * float64(line.Amount)
+	}
+	return subtotal, true
 }
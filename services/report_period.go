@@ -0,0 +1,42 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"totesbackend/apperrors"
+)
+
+// Agrupaciones por período de los reportes.
+const (
+	ReportPeriodDay   = "day"
+	ReportPeriodWeek  = "week"
+	ReportPeriodMonth = "month"
+)
+
+// ErrInvalidReportPeriod indica que la agrupación por período no es válida.
+var ErrInvalidReportPeriod = apperrors.New("report.invalid_period", http.StatusBadRequest, "invalid report period")
+
+// validateReportPeriod valida la agrupación pedida. Si no se indica, se agrupa por mes.
+func validateReportPeriod(period string) (string, error) {
+	if period == "" {
+		return ReportPeriodMonth, nil
+	}
+	if period != ReportPeriodDay && period != ReportPeriodWeek && period != ReportPeriodMonth {
+		return "", fmt.Errorf("%w: period must be '%s', '%s' or '%s'", ErrInvalidReportPeriod,
+			ReportPeriodDay, ReportPeriodWeek, ReportPeriodMonth)
+	}
+	return period, nil
+}
+
+func periodKey(date time.Time, period string) string {
+	switch period {
+	case ReportPeriodDay:
+		return date.Format("2006-01-02")
+	case ReportPeriodWeek:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return date.Format("2006-01")
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"time"
+	"totesbackend/models"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// renderedReport es el archivo generado para un reporte.
+type renderedReport struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// renderReport genera el archivo del reporte en el formato indicado. El JSON usa
+// las filas del reporte; CSV y PDF usan su versión tabular.
+func renderReport(name string, format string, rows interface{}, table ReportTable, from, to time.Time) (*renderedReport, error) {
+	filename := fmt.Sprintf("%s_%s_%s.%s", name, from.Format("20060102"), to.Format("20060102"), format)
+
+	switch format {
+	case models.ReportFormatJSON:
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &renderedReport{Filename: filename, ContentType: "application/json", Data: data}, nil
+
+	case models.ReportFormatCSV:
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(table.Header); err != nil {
+			return nil, err
+		}
+		if err := writer.WriteAll(table.Records); err != nil {
+			return nil, err
+		}
+		return &renderedReport{Filename: filename, ContentType: "text/csv", Data: buf.Bytes()}, nil
+
+	case models.ReportFormatPDF:
+		data, err := renderPDF(table, from, to)
+		if err != nil {
+			return nil, err
+		}
+		return &renderedReport{Filename: filename, ContentType: "application/pdf", Data: data}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported report format: %s", format)
+}
+
+func renderPDF(table ReportTable, from, to time.Time) ([]byte, error) {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(0, 10, tr(table.Title), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 8, fmt.Sprintf("%s - %s", from.Format("2006-01-02"), to.Format("2006-01-02")), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	columnWidth := (pageWidth - left - right) / float64(len(table.Header))
+
+	pdf.SetFont("Helvetica", "B", 8)
+	for _, column := range table.Header {
+		pdf.CellFormat(columnWidth, 7, tr(column), "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 8)
+	for _, record := range table.Records {
+		for _, value := range record {
+			pdf.CellFormat(columnWidth, 6, tr(value), "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"strconv"
+	"time"
+	"totesbackend/dtos"
+)
+
+// ReportTable es la versión tabular de un reporte, usada para exportarlo a CSV o PDF.
+type ReportTable struct {
+	Title   string
+	Header  []string
+	Records [][]string
+}
+
+func formatAmount(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func RevenueByPeriodTable(rows []dtos.RevenueByPeriodDTO) ReportTable {
+	table := ReportTable{
+		Title: "Revenue by period",
+		Header: []string{"period", "invoice_count", "subtotal", "discount_total", "tax_total", "total",
+			"previous_total", "total_change", "total_change_percent"},
+	}
+	for _, row := range rows {
+		changePercent := ""
+		if row.TotalChangePercent != nil {
+			changePercent = formatAmount(*row.TotalChangePercent)
+		}
+		table.Records = append(table.Records, []string{
+			row.Period.Format(time.RFC3339), strconv.Itoa(row.InvoiceCount),
+			formatAmount(row.Subtotal), formatAmount(row.DiscountTotal), formatAmount(row.TaxTotal), formatAmount(row.Total),
+			formatAmount(row.PreviousTotal), formatAmount(row.TotalChange), changePercent,
+		})
+	}
+	return table
+}
+
+func TopItemsTable(rows []dtos.TopItemDTO) ReportTable {
+	table := ReportTable{Title: "Top items", Header: []string{"item_id", "item_name", "quantity", "revenue"}}
+	for _, row := range rows {
+		table.Records = append(table.Records, []string{
+			strconv.Itoa(row.ItemID), row.ItemName, strconv.Itoa(row.Quantity), formatAmount(row.Revenue),
+		})
+	}
+	return table
+}
+
+func RevenueByItemTypeTable(rows []dtos.ItemTypeRevenueDTO) ReportTable {
+	table := ReportTable{Title: "Revenue by item type", Header: []string{"item_type_id", "item_type_name", "quantity", "revenue"}}
+	for _, row := range rows {
+		table.Records = append(table.Records, []string{
+			strconv.Itoa(row.ItemTypeID), row.ItemTypeName, strconv.Itoa(row.Quantity), formatAmount(row.Revenue),
+		})
+	}
+	return table
+}
+
+func RevenueByCustomerTable(rows []dtos.CustomerRevenueDTO) ReportTable {
+	table := ReportTable{
+		Title:  "Revenue by customer",
+		Header: []string{"customer_id", "customer_name", "invoice_count", "subtotal", "discount_total", "tax_total", "total"},
+	}
+	for _, row := range rows {
+		table.Records = append(table.Records, []string{
+			strconv.Itoa(row.CustomerID), row.CustomerName, strconv.Itoa(row.InvoiceCount),
+			formatAmount(row.Subtotal), formatAmount(row.DiscountTotal), formatAmount(row.TaxTotal), formatAmount(row.Total),
+		})
+	}
+	return table
+}
+
+func RevenueBySellerTable(rows []dtos.SellerRevenueDTO) ReportTable {
+	table := ReportTable{
+		Title:  "Revenue by seller",
+		Header: []string{"seller_id", "seller_name", "invoice_count", "subtotal", "discount_total", "tax_total", "total"},
+	}
+	for _, row := range rows {
+		table.Records = append(table.Records, []string{
+			strconv.Itoa(row.SellerID), row.SellerName, strconv.Itoa(row.InvoiceCount),
+			formatAmount(row.Subtotal), formatAmount(row.DiscountTotal), formatAmount(row.TaxTotal), formatAmount(row.Total),
+		})
+	}
+	return table
+}
+
+func TaxSummaryTable(rows []dtos.TaxSummaryDTO) ReportTable {
+	table := ReportTable{
+		Title:  "Tax summary",
+		Header: []string{"tax_type_id", "tax_type_name", "invoice_count", "taxable_amount", "tax_total"},
+	}
+	for _, row := range rows {
+		table.Records = append(table.Records, []string{
+			strconv.Itoa(row.TaxTypeID), row.TaxTypeName, strconv.Itoa(row.InvoiceCount),
+			formatAmount(row.TaxableAmount), formatAmount(row.TaxTotal),
+		})
+	}
+	return table
+}
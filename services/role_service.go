@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,31 +15,45 @@ func NewRoleService(repo *repositories.RoleRepository) *RoleService {
 	return &RoleService{Repo: repo}
 }
 
-func (s *RoleService) GetAllRoles() ([]models.Role, error) {
-	return s.Repo.GetAllRoles()
+func (s *RoleService) GetAllRoles(ctx context.Context, query dtos.ListQuery) ([]models.Role, *dtos.PageInfo, error) {
+	return s.Repo.GetAllRoles(ctx, query)
 }
 
-func (s *RoleService) GetRoleByID(id uint) (*models.Role, error) {
-	return s.Repo.GetRoleByID(id)
+func (s *RoleService) GetRoleByID(ctx context.Context, id uint) (*models.Role, error) {
+	return s.Repo.GetRoleByID(ctx, id)
 
 }
 
-func (s *RoleService) GetRolePermissions(id uint) ([]uint, error) {
-	return s.Repo.GetRolePermissions(id)
+func (s *RoleService) GetRolePermissions(ctx context.Context, id uint) ([]uint, error) {
+	return s.Repo.GetRolePermissions(ctx, id)
 }
 
-func (s *RoleService) GetAllPermissionsOfRole(id uint) ([]models.Permission, error) {
-	return s.Repo.GetAllPermissionsOfRole(id)
+func (s *RoleService) GetAllPermissionsOfRole(ctx context.Context, id uint) ([]models.Permission, error) {
+	return s.Repo.GetAllPermissionsOfRole(ctx, id)
 }
 
-func (s *RoleService) ExistRole(id uint) (bool, error) {
-	return s.Repo.ExistRole(id)
+func (s *RoleService) ExistRole(ctx context.Context, id uint) (bool, error) {
+	return s.Repo.ExistRole(ctx, id)
 }
 
-func (s *RoleService) SearchRolesByID(id string) ([]models.Role, error) {
-	return s.Repo.SearchRolesByID(id)
+func (s *RoleService) SearchRolesByID(ctx context.Context, id string) ([]models.Role, error) {
+	return s.Repo.SearchRolesByID(ctx, id)
 }
 
-func (s *RoleService) SearchRolesByName(name string) ([]models.Role, error) {
-	return s.Repo.SearchRolesByName(name)
+func (s *RoleService) SearchRolesByName(ctx context.Context, name string) ([]models.Role, error) {
+	return s.Repo.SearchRolesByName(ctx, name)
+}
+
+// SetRoleScopes reemplaza las reglas de alcance del rol. Si un recurso se
+// repite queda la última regla.
+func (s *RoleService) SetRoleScopes(ctx context.Context, id uint, dtoScopes []dtos.RoleScopeDTO) error {
+	byResource := map[string]string{}
+	for _, scope := range dtoScopes {
+		byResource[scope.Resource] = scope.Rule
+	}
+	scopes := make([]models.RoleScope, 0, len(byResource))
+	for resource, rule := range byResource {
+		scopes = append(scopes, models.RoleScope{RoleID: id, Resource: resource, Rule: rule})
+	}
+	return s.Repo.SetRoleScopes(ctx, id, scopes)
 }
@@ -1,22 +1,105 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"time"
+	"totesbackend/apperrors"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
 
+// ErrInvalidSalesReportQuery indica que los parámetros del reporte no son válidos.
+var ErrInvalidSalesReportQuery = apperrors.New("sales_report.invalid_query", http.StatusBadRequest, "invalid sales report query")
+
+// Cantidad de ítems del ranking cuando no se indica y máximo permitido.
+const (
+	defaultTopItemsLimit = 10
+	maxTopItemsLimit     = 100
+)
+
 type SalesReportService struct {
 	InvoiceRepo *repositories.InvoiceRepository
+	Repo        *repositories.SalesReportRepository
 }
 
-func NewSalesReportService(invoiceRepo *repositories.InvoiceRepository) *SalesReportService {
+func NewSalesReportService(invoiceRepo *repositories.InvoiceRepository, repo *repositories.SalesReportRepository) *SalesReportService {
 	return &SalesReportService{
 		InvoiceRepo: invoiceRepo,
+		Repo:        repo,
 	}
 }
 
 // GetInvoicesBetweenDates devuelve las facturas registradas en un rango de fechas.
-func (s *SalesReportService) GetInvoicesBetweenDates(startDate, endDate time.Time) ([]models.Invoice, error) {
-	return s.InvoiceRepo.GetInvoicesByDateRange(startDate, endDate)
+func (s *SalesReportService) GetInvoicesBetweenDates(ctx context.Context, startDate, endDate time.Time) ([]models.Invoice, error) {
+	return s.InvoiceRepo.GetInvoicesByDateRange(ctx, startDate, endDate)
+}
+
+// GetRevenueByPeriod devuelve lo facturado por día, semana o mes, incluidos los
+// impuestos y descuentos, comparando cada período con el anterior.
+func (s *SalesReportService) GetRevenueByPeriod(ctx context.Context, startDate, endDate time.Time, period string) ([]dtos.RevenueByPeriodDTO, error) {
+	period, err := validateReportPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Repo.GetRevenueByPeriod(ctx, startDate, endDate, period)
+	if err != nil {
+		return nil, err
+	}
+
+	// La primera fila es el período anterior al rango y solo sirve de comparación
+	result := make([]dtos.RevenueByPeriodDTO, 0, len(rows))
+	for i := 1; i < len(rows); i++ {
+		row := rows[i]
+		row.PreviousTotal = rows[i-1].Total
+		row.TotalChange = row.Total - row.PreviousTotal
+		if row.PreviousTotal != 0 {
+			percent := row.TotalChange / row.PreviousTotal * 100
+			row.TotalChangePercent = &percent
+		}
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// GetTopItems devuelve los ítems más vendidos por cantidad o por ingresos.
+func (s *SalesReportService) GetTopItems(ctx context.Context, startDate, endDate time.Time, orderBy string, limit int) ([]dtos.TopItemDTO, error) {
+	if orderBy == "" {
+		orderBy = repositories.TopItemsByQuantity
+	}
+	if orderBy != repositories.TopItemsByQuantity && orderBy != repositories.TopItemsByRevenue {
+		return nil, fmt.Errorf("%w: orderBy must be '%s' or '%s'", ErrInvalidSalesReportQuery,
+			repositories.TopItemsByQuantity, repositories.TopItemsByRevenue)
+	}
+
+	if limit == 0 {
+		limit = defaultTopItemsLimit
+	}
+	if limit < 0 || limit > maxTopItemsLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSalesReportQuery, maxTopItemsLimit)
+	}
+
+	return s.Repo.GetTopItems(ctx, startDate, endDate, orderBy, limit)
+}
+
+func (s *SalesReportService) GetRevenueByItemType(ctx context.Context, startDate, endDate time.Time) ([]dtos.ItemTypeRevenueDTO, error) {
+	return s.Repo.GetRevenueByItemType(ctx, startDate, endDate)
+}
+
+func (s *SalesReportService) GetRevenueByCustomer(ctx context.Context, startDate, endDate time.Time) ([]dtos.CustomerRevenueDTO, error) {
+	return s.Repo.GetRevenueByCustomer(ctx, startDate, endDate)
+}
+
+// GetRevenueBySeller devuelve lo facturado por vendedor, según la orden de compra
+// que originó cada factura.
+func (s *SalesReportService) GetRevenueBySeller(ctx context.Context, startDate, endDate time.Time) ([]dtos.SellerRevenueDTO, error) {
+	return s.Repo.GetRevenueBySeller(ctx, startDate, endDate)
+}
+
+// GetTaxSummary devuelve lo recaudado por cada tipo de impuesto.
+func (s *SalesReportService) GetTaxSummary(ctx context.Context, startDate, endDate time.Time) ([]dtos.TaxSummaryDTO, error) {
+	return s.Repo.GetTaxSummary(ctx, startDate, endDate)
 }
@@ -0,0 +1,411 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Rangos de fechas de un reporte programado, relativos al momento de la ejecución.
+const (
+	ReportRangePreviousDay   = "previous_day"
+	ReportRangePreviousWeek  = "previous_week"
+	ReportRangePreviousMonth = "previous_month"
+	ReportRangeLast7Days     = "last_7_days"
+	ReportRangeLast30Days    = "last_30_days"
+)
+
+// ErrInvalidScheduledReport indica que la definición del reporte programado no es válida.
+var ErrInvalidScheduledReport = apperrors.New("scheduled_report.invalid", http.StatusBadRequest, "invalid scheduled report")
+
+// ScheduledReportService administra las definiciones de reportes programados y
+// las ejecuta en segundo plano según su expresión cron.
+type ScheduledReportService struct {
+	Repo               *repositories.ScheduledReportRepository
+	SalesReportService *SalesReportService
+	Mailer             utils.MailSender
+
+	mu      sync.Mutex
+	cron    *cron.Cron
+	entries map[int]cron.EntryID
+}
+
+func NewScheduledReportService(repo *repositories.ScheduledReportRepository,
+	salesReportService *SalesReportService, mailer utils.MailSender) *ScheduledReportService {
+	return &ScheduledReportService{
+		Repo:               repo,
+		SalesReportService: salesReportService,
+		Mailer:             mailer,
+		cron:               cron.New(),
+		entries:            map[int]cron.EntryID{},
+	}
+}
+
+// Start programa todas las definiciones activas e inicia el planificador.
+func (s *ScheduledReportService) Start(ctx context.Context) error {
+	reports, err := s.Repo.GetActiveScheduledReports(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range reports {
+		if err := s.schedule(&reports[i]); err != nil {
+			slog.ErrorContext(ctx, "scheduling report failed", "report_id", reports[i].ID, "error", err)
+		}
+	}
+	s.cron.Start()
+	return nil
+}
+
+// Stop detiene el planificador. El contexto devuelto termina cuando finalizan
+// las ejecuciones en curso.
+func (s *ScheduledReportService) Stop() context.Context {
+	return s.cron.Stop()
+}
+
+func (s *ScheduledReportService) GetAllScheduledReports(ctx context.Context, query dtos.ListQuery) ([]models.ScheduledReport, *dtos.PageInfo, error) {
+	return s.Repo.GetAllScheduledReports(ctx, query)
+}
+
+func (s *ScheduledReportService) GetScheduledReportByID(ctx context.Context, id int) (*models.ScheduledReport, error) {
+	return s.Repo.GetScheduledReportByID(ctx, id)
+}
+
+func (s *ScheduledReportService) CreateScheduledReport(ctx context.Context, dto *dtos.CreateScheduledReportDTO) (*models.ScheduledReport, error) {
+	report, err := scheduledReportFromDTO(dto)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateScheduledReport(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.schedule(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *ScheduledReportService) UpdateScheduledReport(ctx context.Context, id int, dto *dtos.CreateScheduledReportDTO) (*models.ScheduledReport, error) {
+	existing, err := s.Repo.GetScheduledReportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := scheduledReportFromDTO(dto)
+	if err != nil {
+		return nil, err
+	}
+	report.ID = existing.ID
+	report.LastRunAt = existing.LastRunAt
+	report.CreatedAt = existing.CreatedAt
+
+	if err := s.Repo.UpdateScheduledReport(ctx, report); err != nil {
+		return nil, err
+	}
+	if err := s.schedule(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *ScheduledReportService) DeleteScheduledReport(ctx context.Context, id int) error {
+	if _, err := s.Repo.GetScheduledReportByID(ctx, id); err != nil {
+		return err
+	}
+	s.unschedule(id)
+	return s.Repo.DeleteScheduledReport(ctx, id)
+}
+
+// NextRunAt devuelve la próxima ejecución programada del reporte, si está activo.
+func (s *ScheduledReportService) NextRunAt(id int) *time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entryID, ok := s.entries[id]
+	if !ok {
+		return nil
+	}
+	next := s.cron.Entry(entryID).Next
+	if next.IsZero() {
+		return nil
+	}
+	return &next
+}
+
+// RunScheduledReport ejecuta el reporte en el momento, sin esperar a su programación.
+func (s *ScheduledReportService) RunScheduledReport(ctx context.Context, id int) (*models.ReportRun, error) {
+	report, err := s.Repo.GetScheduledReportByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, report, time.Now())
+}
+
+func (s *ScheduledReportService) GetReportRuns(ctx context.Context, id int) ([]models.ReportRun, error) {
+	if _, err := s.Repo.GetScheduledReportByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.Repo.GetReportRuns(ctx, id)
+}
+
+func (s *ScheduledReportService) GetReportRunByID(ctx context.Context, id int) (*models.ReportRun, error) {
+	return s.Repo.GetReportRunByID(ctx, id)
+}
+
+// ReportParameters devuelve los parámetros guardados de la definición.
+func ReportParameters(report *models.ScheduledReport) dtos.ScheduledReportParametersDTO {
+	var parameters dtos.ScheduledReportParametersDTO
+	if report.Parameters != "" {
+		_ = json.Unmarshal([]byte(report.Parameters), &parameters)
+	}
+	return parameters
+}
+
+// ReportRecipients devuelve los destinatarios de la definición.
+func ReportRecipients(report *models.ScheduledReport) []string {
+	if report.Recipients == "" {
+		return []string{}
+	}
+	return strings.Split(report.Recipients, ",")
+}
+
+// schedule (re)programa la definición en el planificador. Las definiciones
+// inactivas solo se quitan de la programación.
+func (s *ScheduledReportService) schedule(report *models.ScheduledReport) error {
+	s.unschedule(report.ID)
+	if !report.Active {
+		return nil
+	}
+
+	reportID := report.ID
+	entryID, err := s.cron.AddFunc(report.Cron, func() {
+		// Las ejecuciones programadas no dependen de la petición que las programó
+		ctx := context.Background()
+		current, err := s.Repo.GetScheduledReportByID(ctx, reportID)
+		if err != nil {
+			slog.ErrorContext(ctx, "loading scheduled report failed", "report_id", reportID, "error", err)
+			return
+		}
+		if _, err := s.run(ctx, current, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "scheduled report run failed", "report_id", reportID, "error", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.entries[report.ID] = entryID
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *ScheduledReportService) unschedule(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entryID, ok := s.entries[id]; ok {
+		s.cron.Remove(entryID)
+		delete(s.entries, id)
+	}
+}
+
+// run genera el reporte, guarda el archivo en una nueva ejecución y lo envía a
+// los destinatarios. La ejecución queda registrada aunque falle.
+func (s *ScheduledReportService) run(ctx context.Context, report *models.ScheduledReport, now time.Time) (*models.ReportRun, error) {
+	parameters := ReportParameters(report)
+	from, to, err := reportRange(parameters.Range, report.Report, now)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.ReportRun{
+		ScheduledReportID: report.ID,
+		StartedAt:         now,
+		Status:            models.ReportRunRunning,
+		RangeFrom:         from,
+		RangeTo:           to,
+	}
+	if err := s.Repo.CreateReportRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	runErr := s.generateAndDeliver(ctx, report, parameters, run)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = models.ReportRunSucceeded
+	if runErr != nil {
+		run.Status = models.ReportRunFailed
+		run.Error = runErr.Error()
+	}
+	if err := s.Repo.SaveReportRun(ctx, run); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateLastRunAt(ctx, report.ID, now); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *ScheduledReportService) generateAndDeliver(ctx context.Context, report *models.ScheduledReport,
+	parameters dtos.ScheduledReportParametersDTO, run *models.ReportRun) error {
+	rows, table, err := s.generate(ctx, report.Report, parameters, run.RangeFrom, run.RangeTo)
+	if err != nil {
+		return err
+	}
+
+	rendered, err := renderReport(report.Report, report.Format, rows, table, run.RangeFrom, run.RangeTo)
+	if err != nil {
+		return err
+	}
+	run.ArtifactName = rendered.Filename
+	run.ContentType = rendered.ContentType
+	run.Artifact = rendered.Data
+
+	recipients := ReportRecipients(report)
+	subject := fmt.Sprintf("%s (%s - %s)", report.Name, run.RangeFrom.Format("2006-01-02"), run.RangeTo.Format("2006-01-02"))
+	body := fmt.Sprintf("Attached is the report \"%s\" for %s to %s.\n",
+		report.Name, run.RangeFrom.Format("2006-01-02"), run.RangeTo.Format("2006-01-02"))
+	err = s.Mailer.Send(recipients, subject, body, utils.Attachment{
+		Filename:    rendered.Filename,
+		ContentType: rendered.ContentType,
+		Data:        rendered.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("report generated but could not be delivered: %w", err)
+	}
+	run.DeliveredTo = report.Recipients
+	return nil
+}
+
+// generate obtiene las filas del reporte y su versión tabular.
+func (s *ScheduledReportService) generate(ctx context.Context, name string, parameters dtos.ScheduledReportParametersDTO,
+	from, to time.Time) (interface{}, ReportTable, error) {
+	switch name {
+	case models.ReportSalesSummary:
+		period := parameters.Period
+		if period == "" {
+			period = ReportPeriodDay
+		}
+		rows, err := s.SalesReportService.GetRevenueByPeriod(ctx, from, to, period)
+		return rows, RevenueByPeriodTable(rows), err
+	case models.ReportTaxSummary:
+		rows, err := s.SalesReportService.GetTaxSummary(ctx, from, to)
+		return rows, TaxSummaryTable(rows), err
+	case models.ReportTopItems:
+		rows, err := s.SalesReportService.GetTopItems(ctx, from, to, parameters.OrderBy, parameters.Limit)
+		return rows, TopItemsTable(rows), err
+	case models.ReportRevenueByItemType:
+		rows, err := s.SalesReportService.GetRevenueByItemType(ctx, from, to)
+		return rows, RevenueByItemTypeTable(rows), err
+	case models.ReportRevenueByCustomer:
+		rows, err := s.SalesReportService.GetRevenueByCustomer(ctx, from, to)
+		return rows, RevenueByCustomerTable(rows), err
+	case models.ReportRevenueBySeller:
+		rows, err := s.SalesReportService.GetRevenueBySeller(ctx, from, to)
+		return rows, RevenueBySellerTable(rows), err
+	}
+	return nil, ReportTable{}, fmt.Errorf("%w: unknown report '%s'", ErrInvalidScheduledReport, name)
+}
+
+// reportRange calcula el rango de fechas de una ejecución. Si no se indica, el
+// resumen de impuestos cubre el mes anterior y los demás reportes la semana anterior.
+func reportRange(rangeName string, report string, now time.Time) (time.Time, time.Time, error) {
+	if rangeName == "" {
+		rangeName = ReportRangePreviousWeek
+		if report == models.ReportTaxSummary {
+			rangeName = ReportRangePreviousMonth
+		}
+	}
+
+	// Las consultas incluyen ambos extremos, por lo que los rangos de días, semanas
+	// y meses completos terminan un instante antes de que empiece el siguiente
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch rangeName {
+	case ReportRangePreviousDay:
+		return today.AddDate(0, 0, -1), today.Add(-time.Nanosecond), nil
+	case ReportRangePreviousWeek:
+		// Las semanas empiezan el lunes
+		weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return weekStart.AddDate(0, 0, -7), weekStart.Add(-time.Nanosecond), nil
+	case ReportRangePreviousMonth:
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		return monthStart.AddDate(0, -1, 0), monthStart.Add(-time.Nanosecond), nil
+	case ReportRangeLast7Days:
+		return now.AddDate(0, 0, -7), now, nil
+	case ReportRangeLast30Days:
+		return now.AddDate(0, 0, -30), now, nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("%w: unknown range '%s'", ErrInvalidScheduledReport, rangeName)
+}
+
+func scheduledReportFromDTO(dto *dtos.CreateScheduledReportDTO) (*models.ScheduledReport, error) {
+	switch dto.Report {
+	case models.ReportSalesSummary, models.ReportTaxSummary, models.ReportTopItems,
+		models.ReportRevenueByItemType, models.ReportRevenueByCustomer, models.ReportRevenueBySeller:
+	default:
+		return nil, fmt.Errorf("%w: unknown report '%s'", ErrInvalidScheduledReport, dto.Report)
+	}
+
+	if dto.Format != models.ReportFormatJSON && dto.Format != models.ReportFormatCSV && dto.Format != models.ReportFormatPDF {
+		return nil, fmt.Errorf("%w: format must be '%s', '%s' or '%s'", ErrInvalidScheduledReport,
+			models.ReportFormatJSON, models.ReportFormatCSV, models.ReportFormatPDF)
+	}
+
+	if _, err := cron.ParseStandard(dto.Cron); err != nil {
+		return nil, fmt.Errorf("%w: invalid cron expression: %s", ErrInvalidScheduledReport, err.Error())
+	}
+
+	if len(dto.Recipients) == 0 {
+		return nil, fmt.Errorf("%w: at least one recipient is required", ErrInvalidScheduledReport)
+	}
+	recipients := make([]string, 0, len(dto.Recipients))
+	for _, recipient := range dto.Recipients {
+		address, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid recipient '%s'", ErrInvalidScheduledReport, recipient)
+		}
+		recipients = append(recipients, address.Address)
+	}
+
+	if _, _, err := reportRange(dto.Parameters.Range, dto.Report, time.Now()); err != nil {
+		return nil, err
+	}
+	if dto.Parameters.Period != "" {
+		if _, err := validateReportPeriod(dto.Parameters.Period); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScheduledReport, err.Error())
+		}
+	}
+
+	parameters, err := json.Marshal(dto.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ScheduledReport{
+		Name:       dto.Name,
+		Report:     dto.Report,
+		Parameters: string(parameters),
+		Cron:       dto.Cron,
+		Format:     dto.Format,
+		Recipients: strings.Join(recipients, ","),
+		Active:     true,
+	}
+	if dto.Active != nil {
+		report.Active = *dto.Active
+	}
+	return report, nil
+}
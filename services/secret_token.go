@@ -0,0 +1,25 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// newSecretToken genera los secretos aleatorios que se entregan una sola vez:
+// tokens enviados por correo (restablecimiento de contraseña, invitaciones),
+// tokens de sesión, llaves de API y el state del inicio de sesión con el
+// proveedor de identidad. Sólo se guarda hashSecretToken.
+func newSecretToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+	"totesbackend/dtos"
+	"totesbackend/logging"
+	"totesbackend/models"
+	"totesbackend/repositories"
+)
+
+// SecurityEventRecorder es lo que usan de SecurityEventService los servicios
+// de autenticación.
+type SecurityEventRecorder interface {
+	Record(ctx context.Context, event models.SecurityEvent)
+}
+
+type SecurityEventService struct {
+	Repo *repositories.SecurityEventRepository
+}
+
+func NewSecurityEventService(repo *repositories.SecurityEventRepository) *SecurityEventService {
+	return &SecurityEventService{Repo: repo}
+}
+
+// Record guarda el evento con la fecha y el ID de la petición y lo escribe
+// también en el log. Si no se puede guardar sólo se registra el error: la
+// auditoría no debe impedir iniciar sesión.
+func (s *SecurityEventService) Record(ctx context.Context, event models.SecurityEvent) {
+	event.CreatedAt = time.Now()
+	event.RequestID = logging.RequestID(ctx)
+
+	slog.InfoContext(ctx, "security event", "type", event.Type, "email", event.UserEmail,
+		"ip", event.IP, "actor", event.Actor, "detail", event.Detail)
+	if err := s.Repo.CreateSecurityEvent(ctx, &event); err != nil {
+		slog.ErrorContext(ctx, "recording security event failed", "type", event.Type, "error", err)
+	}
+}
+
+func (s *SecurityEventService) GetAllSecurityEvents(ctx context.Context, query dtos.ListQuery) ([]models.SecurityEvent, *dtos.PageInfo, error) {
+	return s.Repo.GetAllSecurityEvents(ctx, query)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/repositories"
+
+	"gorm.io/gorm"
+)
+
+// sessionTouchInterval es cada cuánto, como máximo, se actualiza el último
+// uso de una sesión.
+const sessionTouchInterval = time.Minute
+
+// maxSessionDeviceLength es el largo de la columna user_sessions.device.
+const maxSessionDeviceLength = 255
+
+// SessionCreator es lo que usan de SessionService los inicios de sesión.
+type SessionCreator interface {
+	CreateSession(ctx context.Context, user *models.User, method, ip, device string) (*models.UserSession, string, error)
+}
+
+// SessionService crea las sesiones al iniciar sesión, las valida en cada
+// petición y permite listarlas y revocarlas. Los cambios de estado y de tipo
+// de usuario las revocan en UserRepository.
+type SessionService struct {
+	Repo     *repositories.SessionRepository
+	UserRepo *repositories.UserRepository
+	Events   *SecurityEventService
+	Config   config.SessionConfig
+}
+
+func NewSessionService(repo *repositories.SessionRepository, userRepo *repositories.UserRepository,
+	events *SecurityEventService, cfg config.SessionConfig) *SessionService {
+	return &SessionService{Repo: repo, UserRepo: userRepo, Events: events, Config: cfg}
+}
+
+// CreateSession abre una sesión para el usuario y devuelve su token, que no
+// se guarda. method es password u oidc; device es el User-Agent del cliente.
+func (s *SessionService) CreateSession(ctx context.Context, user *models.User, method, ip, device string) (*models.UserSession, string, error) {
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, "", err
+	}
+	device = sessionDevice(device)
+
+	now := time.Now()
+	session := &models.UserSession{
+		UserID:     user.ID,
+		TokenHash:  hashSecretToken(token),
+		Method:     method,
+		Device:     device,
+		IP:         ip,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.Config.MaxLifetime),
+	}
+	if err := s.Repo.CreateSession(ctx, session); err != nil {
+		return nil, "", err
+	}
+	session.User = *user
+	return session, token, nil
+}
+
+// Authenticate devuelve la sesión vigente que corresponde al token, con su
+// usuario, y registra su uso. Un token desconocido, una sesión vencida o
+// revocada y un usuario que ya no está activo dan ErrInvalidSession.
+func (s *SessionService) Authenticate(ctx context.Context, token string) (*models.UserSession, error) {
+	session, err := s.Repo.GetSessionByTokenHash(ctx, hashSecretToken(token))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrInvalidSession
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !session.Active(now, s.Config.IdleTimeout) || session.User.UserStateTypeID != models.UserStateActive {
+		return nil, apperrors.ErrInvalidSession
+	}
+	if err := s.Repo.TouchSession(ctx, session.ID, now, now.Add(-sessionTouchInterval)); err != nil {
+		slog.ErrorContext(ctx, "recording session use failed", "session_id", session.ID, "error", err)
+	}
+	return session, nil
+}
+
+// GetOwnSessions lista las sesiones vigentes del usuario con el correo dado.
+func (s *SessionService) GetOwnSessions(ctx context.Context, email string) ([]models.UserSession, error) {
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	return s.activeSessions(ctx, user.ID)
+}
+
+// RevokeOwnSession revoca una sesión del usuario con el correo dado.
+func (s *SessionService) RevokeOwnSession(ctx context.Context, email, id string) error {
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	return s.revoke(ctx, user, id, email)
+}
+
+// GetUserSessions lista las sesiones vigentes del usuario con el ID dado.
+func (s *SessionService) GetUserSessions(ctx context.Context, userID string) ([]models.UserSession, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.activeSessions(ctx, user.ID)
+}
+
+// RevokeUserSession revoca una sesión de otro usuario; actor es quien la
+// revoca.
+func (s *SessionService) RevokeUserSession(ctx context.Context, userID, id, actor string) error {
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.revoke(ctx, user, id, actor)
+}
+
+// RevokeUserSessions revoca todas las sesiones abiertas del usuario y
+// devuelve cuántas eran.
+func (s *SessionService) RevokeUserSessions(ctx context.Context, userID, actor string) (int64, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	count, err := s.Repo.RevokeUserSessions(ctx, user.ID, actor, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventSessionRevoked, UserEmail: user.Email, Actor: actor,
+			Detail: "all sessions (" + strconv.FormatInt(count, 10) + ")"})
+	}
+	return count, nil
+}
+
+func (s *SessionService) activeSessions(ctx context.Context, userID int) ([]models.UserSession, error) {
+	now := time.Now()
+	return s.Repo.GetActiveSessions(ctx, userID, now, now.Add(-s.Config.IdleTimeout))
+}
+
+// revoke revoca la sesión id del usuario. Una sesión de otro usuario se trata
+// como inexistente.
+func (s *SessionService) revoke(ctx context.Context, user *models.User, id, actor string) error {
+	session, err := s.Repo.GetUserSession(ctx, user.ID, id)
+	if err != nil {
+		return err
+	}
+	revoked, err := s.Repo.RevokeSession(ctx, session.ID, actor, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return apperrors.ErrSessionRevoked
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventSessionRevoked, UserEmail: user.Email, Actor: actor,
+		Detail: "session " + strconv.Itoa(session.ID)})
+	return nil
+}
+
+// sessionDevice deja el User-Agent en los 255 caracteres de la columna. Se
+// corta por runas para no partir un carácter multibyte, y los bytes inválidos
+// se reemplazan porque Postgres rechaza texto que no sea UTF-8.
+func sessionDevice(userAgent string) string {
+	device := strings.ToValidUTF8(userAgent, "\uFFFD")
+	if runes := []rune(device); len(runes) > maxSessionDeviceLength {
+		device = string(runes[:maxSessionDeviceLength])
+	}
+	return device
+}
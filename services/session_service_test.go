@@ -0,0 +1,32 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSessionDevice(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		wantRunes int
+	}{
+		{"short", "Mozilla/5.0", 11},
+		{"long ascii", strings.Repeat("a", 300), maxSessionDeviceLength},
+		// 254 letras y luego caracteres de 3 bytes: cortar por bytes partiría uno
+		{"multibyte at the limit", strings.Repeat("a", 254) + strings.Repeat("€", 10), maxSessionDeviceLength},
+		{"invalid utf-8", "agent\xff\xfe", 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device := sessionDevice(tt.userAgent)
+			if !utf8.ValidString(device) {
+				t.Fatalf("device %q is not valid UTF-8", device)
+			}
+			if got := utf8.RuneCountInString(device); got != tt.wantRunes {
+				t.Errorf("device has %d characters, want %d", got, tt.wantRunes)
+			}
+		})
+	}
+}
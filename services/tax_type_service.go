@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,14 +15,14 @@ func NewTaxTypeService(repo *repositories.TaxTypeRepository) *TaxTypeService {
 	return &TaxTypeService{Repo: repo}
 }
 
-func (s *TaxTypeService) GetAllTaxTypes() ([]models.TaxType, error) {
-	return s.Repo.GetAllTaxTypes()
+func (s *TaxTypeService) GetAllTaxTypes(ctx context.Context, query dtos.ListQuery) ([]models.TaxType, *dtos.PageInfo, error) {
+	return s.Repo.GetAllTaxTypes(ctx, query)
 }
 
-func (s *TaxTypeService) GetTaxTypeByID(id string) (*models.TaxType, error) {
-	return s.Repo.GetTaxTypeByID(id)
+func (s *TaxTypeService) GetTaxTypeByID(ctx context.Context, id string) (*models.TaxType, error) {
+	return s.Repo.GetTaxTypeByID(ctx, id)
 }
 
-func (s *TaxTypeService) CreateTaxType(taxType *models.TaxType) error {
-	return s.Repo.CreateTaxType(taxType)
+func (s *TaxTypeService) CreateTaxType(ctx context.Context, taxType *models.TaxType) error {
+	return s.Repo.CreateTaxType(ctx, taxType)
 }
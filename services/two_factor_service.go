@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/dtos"
+	"totesbackend/models"
+	"totesbackend/repositories"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+// recoveryCodeCount es cuántos códigos de recuperación se entregan.
+const recoveryCodeCount = 10
+
+// TwoFactorService administra el segundo factor TOTP. Las contraseñas y los
+// códigos que recibe se verifican a través de Guard, así que cuentan para la
+// espera entre intentos y el bloqueo igual que en el inicio de sesión.
+type TwoFactorService struct {
+	Repo     TwoFactorStore
+	UserRepo TwoFactorUserStore
+	Guard    *LoginGuardService
+	Events   SecurityEventRecorder
+	Secrets  *utils.SecretBox
+	Config   config.TwoFactorConfig
+}
+
+// Dependencias de TwoFactorService. Las cumplen TwoFactorRepository y
+// UserRepository; son interfaces para poder probar el alta y el inicio de
+// sesión sin base de datos.
+type (
+	TwoFactorStore interface {
+		GetTOTP(ctx context.Context, userID int) (*models.UserTOTP, error)
+		SavePendingTOTP(ctx context.Context, totp *models.UserTOTP) error
+		ConfirmTOTP(ctx context.Context, userID int, step int64, codes []models.RecoveryCode) error
+		UseStep(ctx context.Context, userID int, step int64) (bool, error)
+		UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+		CountUnusedRecoveryCodes(ctx context.Context, userID int) (int64, error)
+		ReplaceRecoveryCodes(ctx context.Context, userID int, codes []models.RecoveryCode) error
+		DeleteTwoFactor(ctx context.Context, userID int) error
+	}
+	TwoFactorUserStore interface {
+		GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+		GetUserByID(ctx context.Context, id string) (*models.User, error)
+	}
+)
+
+func NewTwoFactorService(repo *repositories.TwoFactorRepository, userRepo *repositories.UserRepository, guard *LoginGuardService,
+	events *SecurityEventService, cfg config.TwoFactorConfig) (*TwoFactorService, error) {
+	secrets, err := utils.NewSecretBox(cfg.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return &TwoFactorService{Repo: repo, UserRepo: userRepo, Guard: guard, Events: events, Secrets: secrets, Config: cfg}, nil
+}
+
+// GetStatus indica si el usuario tiene el segundo factor activo o pendiente
+// y si su tipo de usuario lo exige.
+func (s *TwoFactorService) GetStatus(ctx context.Context, email string) (*dtos.TwoFactorStatusDTO, error) {
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	status := &dtos.TwoFactorStatusDTO{Required: user.UserType.RequireTwoFactor}
+
+	totp, err := s.Repo.GetTOTP(ctx, user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status.Enabled = totp.ConfirmedAt != nil
+	status.Pending = totp.ConfirmedAt == nil
+	if status.Enabled {
+		if status.RecoveryCodesLeft, err = s.Repo.CountUnusedRecoveryCodes(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Enroll genera una semilla nueva sin confirmar. Pide la contraseña en lugar
+// de una sesión para que pueda configurarlo también quien no puede entrar
+// porque su tipo de usuario lo exige.
+func (s *TwoFactorService) Enroll(ctx context.Context, email, password, ip string) (*dtos.TwoFactorEnrollmentDTO, error) {
+	user, err := s.Guard.CheckPassword(ctx, email, password, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.Repo.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.ConfirmedAt != nil {
+		return nil, apperrors.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.Secrets.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.SavePendingTOTP(ctx, &models.UserTOTP{UserID: user.ID, Secret: sealed, CreatedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+
+	return &dtos.TwoFactorEnrollmentDTO{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.Config.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm activa la semilla pendiente con el primer código de la aplicación y
+// devuelve los códigos de recuperación, que no se vuelven a mostrar. Como
+// Enroll, pide la contraseña en lugar de una sesión.
+func (s *TwoFactorService) Confirm(ctx context.Context, email, password, code, ip string) ([]string, error) {
+	user, err := s.Guard.CheckPassword(ctx, email, password, ip)
+	if err != nil {
+		return nil, err
+	}
+	totp, err := s.Repo.GetTOTP(ctx, user.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperrors.ErrTwoFactorNotPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	if totp.ConfirmedAt != nil {
+		return nil, apperrors.ErrTwoFactorAlreadyEnabled
+	}
+
+	step, err := s.validateCode(totp, code)
+	if errors.Is(err, apperrors.ErrInvalidTwoFactorCode) {
+		return nil, s.Guard.Fail(ctx, user.Email, ip, user, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	codes, rows, err := newRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.ConfirmTOTP(ctx, user.ID, step, rows); err != nil {
+		return nil, err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventTwoFactorEnabled, UserEmail: attemptKey(user.Email)})
+	return codes, nil
+}
+
+// Disable quita el segundo factor con la contraseña y un código válido. No se
+// permite si el tipo de usuario lo exige.
+func (s *TwoFactorService) Disable(ctx context.Context, email, password, code, ip string) error {
+	user, err := s.Guard.CheckPassword(ctx, email, password, ip)
+	if err != nil {
+		return err
+	}
+	if user.UserType.RequireTwoFactor {
+		return apperrors.ErrTwoFactorMandatory
+	}
+	totp, err := s.confirmedTOTP(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkCode(ctx, user, totp, code, ip); err != nil {
+		return err
+	}
+
+	if err := s.Repo.DeleteTwoFactor(ctx, user.ID); err != nil {
+		return err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventTwoFactorDisabled, UserEmail: attemptKey(user.Email)})
+	return nil
+}
+
+// RegenerateRecoveryCodes reemplaza los códigos de recuperación; los
+// anteriores dejan de servir.
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, email, code, ip string) ([]string, error) {
+	user, err := s.UserRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	totp, err := s.confirmedTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCode(ctx, user, totp, code, ip); err != nil {
+		return nil, err
+	}
+
+	codes, rows, err := newRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.ReplaceRecoveryCodes(ctx, user.ID, rows); err != nil {
+		return nil, err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventRecoveryCodesRenewed, UserEmail: attemptKey(user.Email)})
+	return codes, nil
+}
+
+// ResetForUser quita el segundo factor de otro usuario, por ejemplo si perdió
+// el teléfono y los códigos. actor es quien lo hace.
+func (s *TwoFactorService) ResetForUser(ctx context.Context, id, actor string) error {
+	user, err := s.UserRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.Repo.GetTOTP(ctx, user.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apperrors.ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+
+	if err := s.Repo.DeleteTwoFactor(ctx, user.ID); err != nil {
+		return err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventTwoFactorDisabled, UserEmail: attemptKey(user.Email),
+		Actor: actor, Detail: "reset by administrator"})
+	return nil
+}
+
+// VerifyLogin comprueba el segundo factor de un usuario que ya dio bien la
+// contraseña. Sin segundo factor configurado sólo falla si su tipo de usuario
+// lo exige.
+func (s *TwoFactorService) VerifyLogin(ctx context.Context, user *models.User, code string) error {
+	totp, err := s.Repo.GetTOTP(ctx, user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if totp == nil || totp.ConfirmedAt == nil {
+		if user.UserType.RequireTwoFactor {
+			return apperrors.ErrTwoFactorEnrollmentRequired
+		}
+		return nil
+	}
+	if strings.TrimSpace(code) == "" {
+		return apperrors.ErrTwoFactorRequired
+	}
+	return s.verify(ctx, user, totp, code)
+}
+
+// checkCode es verify con la espera y el conteo de fallos de Guard.
+func (s *TwoFactorService) checkCode(ctx context.Context, user *models.User, totp *models.UserTOTP, code, ip string) error {
+	if err := s.Guard.Throttle(ctx, user.Email, ip); err != nil {
+		return err
+	}
+	err := s.verify(ctx, user, totp, code)
+	if errors.Is(err, apperrors.ErrInvalidTwoFactorCode) {
+		return s.Guard.Fail(ctx, user.Email, ip, user, err)
+	}
+	return err
+}
+
+// verify acepta un código TOTP (6 dígitos) o un código de recuperación. Cada
+// código sirve una sola vez.
+func (s *TwoFactorService) verify(ctx context.Context, user *models.User, totp *models.UserTOTP, code string) error {
+	code = strings.TrimSpace(code)
+	event := models.SecurityEvent{Type: models.SecurityEventTwoFactorFailed, UserEmail: attemptKey(user.Email)}
+	if isTOTPCode(code) {
+		step, err := s.validateCode(totp, code)
+		if err != nil {
+			s.Events.Record(ctx, event)
+			return err
+		}
+		fresh, err := s.Repo.UseStep(ctx, user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			event.Detail = "code reused"
+			s.Events.Record(ctx, event)
+			return apperrors.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := s.Repo.UseRecoveryCode(ctx, user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		event.Detail = "invalid recovery code"
+		s.Events.Record(ctx, event)
+		return apperrors.ErrInvalidTwoFactorCode
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventRecoveryCodeUsed, UserEmail: attemptKey(user.Email)})
+	return nil
+}
+
+// confirmedTOTP devuelve el segundo factor activo del usuario o
+// ErrTwoFactorNotEnabled si no tiene o está pendiente.
+func (s *TwoFactorService) confirmedTOTP(ctx context.Context, userID int) (*models.UserTOTP, error) {
+	totp, err := s.Repo.GetTOTP(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && totp.ConfirmedAt == nil) {
+		return nil, apperrors.ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+	return totp, nil
+}
+
+func (s *TwoFactorService) validateCode(totp *models.UserTOTP, code string) (int64, error) {
+	secret, err := s.Secrets.Open(totp.Secret)
+	if err != nil {
+		return 0, err
+	}
+	step, ok := utils.ValidateTOTP(secret, strings.TrimSpace(code), time.Now(), s.Config.Skew)
+	if !ok {
+		return 0, apperrors.ErrInvalidTwoFactorCode
+	}
+	return step, nil
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// newRecoveryCodes genera los códigos en texto, con forma xxxxx-xxxxx, y las
+// filas con sus hashes.
+func newRecoveryCodes(userID int) ([]string, []models.RecoveryCode, error) {
+	now := time.Now()
+	codes := make([]string, 0, recoveryCodeCount)
+	rows := make([]models.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(buf))[:10]
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		rows = append(rows, models.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code), CreatedAt: now})
+	}
+	return codes, rows, nil
+}
+
+// hashRecoveryCode ignora mayúsculas, espacios y guiones para que el código
+// se pueda escribir como sea.
+func hashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"totesbackend/apperrors"
+	"totesbackend/config"
+	"totesbackend/models"
+	"totesbackend/services/utils"
+
+	"gorm.io/gorm"
+)
+
+// Dobles de las dependencias de LoginGuardService y TwoFactorService.
+type fakeLoginUsers struct {
+	users map[string]*models.User
+}
+
+func (f *fakeLoginUsers) GetUserByEmail(_ context.Context, email string) (*models.User, error) {
+	user, ok := f.users[email]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (f *fakeLoginUsers) GetUserByID(_ context.Context, id string) (*models.User, error) {
+	for _, user := range f.users {
+		if strconv.Itoa(user.ID) == id {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeLoginUsers) LockUser(_ context.Context, id int, until time.Time) error {
+	for _, user := range f.users {
+		if user.ID == id {
+			user.UserStateTypeID = models.UserStateLocked
+			user.LockedUntil = &until
+		}
+	}
+	return nil
+}
+
+func (f *fakeLoginUsers) UnlockUser(_ context.Context, id int, _ string) error {
+	for _, user := range f.users {
+		if user.ID == id {
+			user.UserStateTypeID = models.UserStateActive
+			user.LockedUntil = nil
+		}
+	}
+	return nil
+}
+
+func (f *fakeLoginUsers) RehashPassword(context.Context, int, string, string) error {
+	return nil
+}
+
+type fakeLoginAttempts struct {
+	attempts []models.LoginAttempt
+}
+
+func (f *fakeLoginAttempts) CreateLoginAttempt(_ context.Context, attempt *models.LoginAttempt) error {
+	f.attempts = append(f.attempts, *attempt)
+	return nil
+}
+
+func (f *fakeLoginAttempts) AccountFailures(_ context.Context, email string, since time.Time) (int64, *time.Time, error) {
+	return f.failures(func(a models.LoginAttempt) bool { return a.Email == email }, since)
+}
+
+func (f *fakeLoginAttempts) IPFailures(_ context.Context, ip string, since time.Time) (int64, *time.Time, error) {
+	return f.failures(func(a models.LoginAttempt) bool { return a.IP == ip }, since)
+}
+
+// failures cuenta los fallos desde el último éxito, como LoginAttemptRepository.
+func (f *fakeLoginAttempts) failures(match func(models.LoginAttempt) bool, since time.Time) (int64, *time.Time, error) {
+	var count int64
+	var last *time.Time
+	for _, attempt := range f.attempts {
+		if !match(attempt) || attempt.AttemptedAt.Before(since) {
+			continue
+		}
+		if attempt.Success {
+			count, last = 0, nil
+			continue
+		}
+		count++
+		at := attempt.AttemptedAt
+		last = &at
+	}
+	return count, last, nil
+}
+
+type fakeTOTPs struct {
+	totps map[int]*models.UserTOTP
+	steps map[int]int64
+	codes map[int][]models.RecoveryCode
+}
+
+func (f *fakeTOTPs) GetTOTP(_ context.Context, userID int) (*models.UserTOTP, error) {
+	totp, ok := f.totps[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *totp
+	return &copied, nil
+}
+
+func (f *fakeTOTPs) SavePendingTOTP(_ context.Context, totp *models.UserTOTP) error {
+	f.totps[totp.UserID] = totp
+	return nil
+}
+
+func (f *fakeTOTPs) ConfirmTOTP(_ context.Context, userID int, step int64, codes []models.RecoveryCode) error {
+	now := time.Now()
+	f.totps[userID].ConfirmedAt = &now
+	f.steps[userID] = step
+	f.codes[userID] = codes
+	return nil
+}
+
+func (f *fakeTOTPs) UseStep(_ context.Context, userID int, step int64) (bool, error) {
+	if step <= f.steps[userID] {
+		return false, nil
+	}
+	f.steps[userID] = step
+	return true, nil
+}
+
+func (f *fakeTOTPs) UseRecoveryCode(_ context.Context, userID int, codeHash string) (bool, error) {
+	for i, code := range f.codes[userID] {
+		if code.CodeHash == codeHash && code.UsedAt == nil {
+			now := time.Now()
+			f.codes[userID][i].UsedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeTOTPs) CountUnusedRecoveryCodes(_ context.Context, userID int) (int64, error) {
+	var count int64
+	for _, code := range f.codes[userID] {
+		if code.UsedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeTOTPs) ReplaceRecoveryCodes(_ context.Context, userID int, codes []models.RecoveryCode) error {
+	f.codes[userID] = codes
+	return nil
+}
+
+func (f *fakeTOTPs) DeleteTwoFactor(_ context.Context, userID int) error {
+	delete(f.totps, userID)
+	delete(f.steps, userID)
+	delete(f.codes, userID)
+	return nil
+}
+
+// totpAt calcula el código TOTP de secret para el instante t, como la
+// aplicación de autenticación.
+func totpAt(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decoding secret: %v", err)
+	}
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+// TestTwoFactorEnrollmentBeforeFirstLogin sigue a un administrador recién
+// creado con create-admin, cuyo tipo de usuario exige el segundo factor: no
+// puede iniciar sesión hasta configurarlo, y lo configura sin sesión con su
+// correo y contraseña.
+func TestTwoFactorEnrollmentBeforeFirstLogin(t *testing.T) {
+	ctx := context.Background()
+	const email, password, ip = "admin@example.com", "Correct-Horse-9", "10.0.0.1"
+
+	hasher := utils.NewPasswordHasher(config.HashingConfig{Algorithm: config.HashBcrypt, BcryptCost: 4})
+	hashed, err := hasher.Hash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := &fakeLoginUsers{users: map[string]*models.User{email: {
+		ID:              1,
+		Email:           email,
+		Password:        hashed,
+		UserStateTypeID: models.UserStateActive,
+		UserType:        models.UserType{Name: "Administrator", RequireTwoFactor: true},
+	}}}
+	events := &fakeEvents{}
+	guard := &LoginGuardService{
+		UserRepo:    users,
+		AttemptRepo: &fakeLoginAttempts{},
+		Events:      events,
+		Hasher:      hasher,
+		Limits:      config.LoginConfig{MaxFailures: 5, FailureWindow: 15 * time.Minute, LockDuration: 15 * time.Minute},
+	}
+	secrets, err := utils.NewSecretBox("test-encryption-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	twoFactor := &TwoFactorService{
+		Repo:     &fakeTOTPs{totps: map[int]*models.UserTOTP{}, steps: map[int]int64{}, codes: map[int][]models.RecoveryCode{}},
+		UserRepo: users,
+		Guard:    guard,
+		Events:   events,
+		Secrets:  secrets,
+		Config:   config.TwoFactorConfig{Issuer: "Totes", Skew: 1},
+	}
+	login := &UserCredentialValidationService{Guard: guard, TwoFactor: twoFactor, Sessions: fakeOIDCSessions{}}
+
+	if _, _, err := login.ValidateUserCredentials(ctx, email, password, "", ip, "test-agent"); !errors.Is(err, apperrors.ErrTwoFactorEnrollmentRequired) {
+		t.Fatalf("login before enrolling: err = %v, want ErrTwoFactorEnrollmentRequired", err)
+	}
+
+	if _, err := twoFactor.Enroll(ctx, email, "wrong-password", ip); !errors.Is(err, apperrors.ErrInvalidCredentials) {
+		t.Fatalf("enroll with a wrong password: err = %v, want ErrInvalidCredentials", err)
+	}
+	enrollment, err := twoFactor.Enroll(ctx, email, password, ip)
+	if err != nil {
+		t.Fatalf("Enroll: %v", err)
+	}
+	if !strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/") {
+		t.Errorf("provisioning URI = %q", enrollment.ProvisioningURI)
+	}
+
+	now := time.Now()
+	code := totpAt(t, enrollment.Secret, now)
+	if _, err := twoFactor.Confirm(ctx, email, "wrong-password", code, ip); !errors.Is(err, apperrors.ErrInvalidCredentials) {
+		t.Fatalf("confirm with a wrong password: err = %v, want ErrInvalidCredentials", err)
+	}
+	recoveryCodes, err := twoFactor.Confirm(ctx, email, password, code, ip)
+	if err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	if len(recoveryCodes) != recoveryCodeCount {
+		t.Errorf("got %d recovery codes, want %d", len(recoveryCodes), recoveryCodeCount)
+	}
+
+	if _, _, err := login.ValidateUserCredentials(ctx, email, password, "", ip, "test-agent"); !errors.Is(err, apperrors.ErrTwoFactorRequired) {
+		t.Fatalf("login without a code: err = %v, want ErrTwoFactorRequired", err)
+	}
+	// El código de la confirmación ya se usó
+	if _, _, err := login.ValidateUserCredentials(ctx, email, password, code, ip, "test-agent"); !errors.Is(err, apperrors.ErrInvalidTwoFactorCode) {
+		t.Fatalf("login with the confirmation code: err = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	session, _, err := login.ValidateUserCredentials(ctx, email, password, totpAt(t, enrollment.Secret, now.Add(30*time.Second)), ip, "test-agent")
+	if err != nil {
+		t.Fatalf("login with the next code: %v", err)
+	}
+	if session.User.Email != email || session.Method != "password" {
+		t.Errorf("session = user %q method %q", session.User.Email, session.Method)
+	}
+}
@@ -1,32 +1,44 @@
 package services
 
 import (
+	"context"
 	"errors"
-	"totesbackend/repositories"
-	"totesbackend/services/utils"
+	"totesbackend/apperrors"
+	"totesbackend/models"
 )
 
 type UserCredentialValidationService struct {
-	UserRepo *repositories.UserRepository
+	Guard     *LoginGuardService
+	TwoFactor *TwoFactorService
+	Sessions  SessionCreator
 }
 
-func NewUserCredentialValidationService(userRepo *repositories.UserRepository) *UserCredentialValidationService {
-	return &UserCredentialValidationService{UserRepo: userRepo}
+func NewUserCredentialValidationService(guard *LoginGuardService, twoFactor *TwoFactorService,
+	sessions *SessionService) *UserCredentialValidationService {
+	return &UserCredentialValidationService{Guard: guard, TwoFactor: twoFactor, Sessions: sessions}
 }
 
-func (s *UserCredentialValidationService) ValidateUserCredentials(email, password string) error {
-	user, err := s.UserRepo.GetUserByEmail(email)
+// ValidateUserCredentials valida el correo, la contraseña y, si el usuario lo
+// tiene, el segundo factor (twoFactorCode). La espera entre intentos y el
+// bloqueo de la cuenta los aplica LoginGuardService; un código de segundo
+// factor incorrecto cuenta como intento fallido. Con credenciales válidas abre
+// una sesión desde device y devuelve su token.
+func (s *UserCredentialValidationService) ValidateUserCredentials(ctx context.Context, email, password, twoFactorCode, ip,
+	device string) (*models.UserSession, string, error) {
+	user, err := s.Guard.CheckPassword(ctx, email, password, ip)
 	if err != nil {
-		return errors.New("invalid email or password")
+		return nil, "", err
 	}
 
-	if user.UserStateType.Name != "Active" {
-		return errors.New("user is not active")
+	if err := s.TwoFactor.VerifyLogin(ctx, user, twoFactorCode); err != nil {
+		if errors.Is(err, apperrors.ErrInvalidTwoFactorCode) {
+			return nil, "", s.Guard.Fail(ctx, email, ip, user, err)
+		}
+		return nil, "", err
 	}
 
-	if !utils.CheckPasswordHash(password, user.Password) {
-		return errors.New("invalid email or password")
+	if err := s.Guard.Succeed(ctx, email, ip, "password"); err != nil {
+		return nil, "", err
 	}
-
-	return nil
+	return s.Sessions.CreateSession(ctx, user, "password", ip, device)
 }
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"time"
+	"totesbackend/logging"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -14,12 +16,13 @@ func NewUserLogService(repo *repositories.UserLogRepository) *UserLogService {
 	return &UserLogService{Repo: repo}
 }
 
-func (s *UserLogService) CreateUserLog(userEmail, logMessage string) (*models.UserLog, error) {
+func (s *UserLogService) CreateUserLog(ctx context.Context, userEmail, logMessage string) (*models.UserLog, error) {
 	userLog := &models.UserLog{
 		UserEmail: userEmail,
 		Log:       logMessage,
 		DateTime:  time.Now(),
+		RequestID: logging.RequestID(ctx),
 	}
 
-	return s.Repo.CreateUserLog(userLog)
+	return s.Repo.CreateUserLog(ctx, userLog)
 }
@@ -1,55 +1,93 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"totesbackend/apperrors"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 	"totesbackend/services/utils"
 )
 
 type UserService struct {
-	Repo *repositories.UserRepository
+	Repo   *repositories.UserRepository
+	Events *SecurityEventService
+	Policy PasswordPolicy
+	Hasher utils.PasswordHasher
 }
 
-func NewUserService(repo *repositories.UserRepository) *UserService {
-	return &UserService{Repo: repo}
+func NewUserService(repo *repositories.UserRepository, events *SecurityEventService, policy PasswordPolicy,
+	hasher utils.PasswordHasher) *UserService {
+	return &UserService{Repo: repo, Events: events, Policy: policy, Hasher: hasher}
 }
 
-func (s *UserService) GetUserByID(id string) (*models.User, error) {
-	return s.Repo.GetUserByID(id)
+func (s *UserService) GetUserByID(ctx context.Context, id string) (*models.User, error) {
+	return s.Repo.GetUserByID(ctx, id)
 }
 
-func (s *UserService) GetUserByEmail(email string) (*models.User, error) {
-	return s.Repo.GetUserByEmail(email)
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.Repo.GetUserByEmail(ctx, email)
 }
 
-func (s *UserService) GetAllUsers() ([]models.User, error) {
-	return s.Repo.GetAllUsers()
+func (s *UserService) GetAllUsers(ctx context.Context, query dtos.ListQuery) ([]models.User, *dtos.PageInfo, error) {
+	return s.Repo.GetAllUsers(ctx, query)
 }
 
-func (s *UserService) SearchUsersByID(query string) ([]models.User, error) {
-	return s.Repo.SearchUsersByID(query)
+func (s *UserService) SearchUsersByID(ctx context.Context, query string) ([]models.User, error) {
+	return s.Repo.SearchUsersByID(ctx, query)
 }
 
-func (s *UserService) SearchUsersByEmail(query string) ([]models.User, error) {
-	return s.Repo.SearchUsersByEmail(query)
+func (s *UserService) SearchUsersByEmail(ctx context.Context, query string) ([]models.User, error) {
+	return s.Repo.SearchUsersByEmail(ctx, query)
 }
 
-func (s *UserService) UpdateUserState(id string, state int) (*models.User, error) {
-	return s.Repo.UpdateUserState(id, state)
+func (s *UserService) UpdateUserState(ctx context.Context, id string, state int) (*models.User, error) {
+	return s.Repo.UpdateUserState(ctx, id, state)
 }
 
-func (s *UserService) UpdateUser(user *models.User) error {
-	return s.Repo.UpdateUser(user)
+func (s *UserService) UpdateUser(ctx context.Context, user *models.User) error {
+	return s.Repo.UpdateUser(ctx, user)
 }
 
-func (s *UserService) CreateUser(user *models.User) (*models.User, error) {
-	hashedPassword, err := utils.HashPassword(user.Password)
+func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	if err := s.Policy.Check(user.Password, user.Email); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.Hasher.Hash(user.Password)
 	if err != nil {
 		return nil, fmt.Errorf("error hashing password: %w", err)
 	}
 
 	user.Password = hashedPassword
 
-	return s.Repo.CreateUser(user)
+	return s.Repo.CreateUser(ctx, user)
+}
+
+// SetPasswordLogin permite o no que el usuario entre con contraseña; sin ella
+// sólo puede entrar con el proveedor de identidad.
+func (s *UserService) SetPasswordLogin(ctx context.Context, id string, enabled bool) (*models.User, error) {
+	return s.Repo.SetPasswordLoginDisabled(ctx, id, !enabled)
+}
+
+// UnlockUser desbloquea antes de tiempo una cuenta bloqueada por intentos
+// fallidos. actor es el usuario que la desbloquea.
+func (s *UserService) UnlockUser(ctx context.Context, id, actor string) (*models.User, error) {
+	user, err := s.Repo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.UserStateTypeID != models.UserStateLocked {
+		return nil, apperrors.ErrUserNotLocked
+	}
+
+	if err := s.Repo.UnlockUser(ctx, user.ID, user.Email); err != nil {
+		return nil, err
+	}
+	s.Events.Record(ctx, models.SecurityEvent{Type: models.SecurityEventAccountUnlocked, UserEmail: strings.ToLower(user.Email),
+		Actor: actor, Detail: "unlocked by administrator"})
+
+	return s.Repo.GetUserByID(ctx, id)
 }
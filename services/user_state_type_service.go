@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,10 +15,10 @@ func NewUserStateTypeService(repo *repositories.UserStateTypeRepository) *UserSt
 	return &UserStateTypeService{Repo: repo}
 }
 
-func (s *UserStateTypeService) GetAllUserStateTypes() ([]models.UserStateType, error) {
-	return s.Repo.GetAllUserStateTypes()
+func (s *UserStateTypeService) GetAllUserStateTypes(ctx context.Context, query dtos.ListQuery) ([]models.UserStateType, *dtos.PageInfo, error) {
+	return s.Repo.GetAllUserStateTypes(ctx, query)
 }
 
-func (s *UserStateTypeService) GetUserStateTypeByID(id string) (*models.UserStateType, error) {
-	return s.Repo.GetUserStateTypeByID(id)
+func (s *UserStateTypeService) GetUserStateTypeByID(ctx context.Context, id string) (*models.UserStateType, error) {
+	return s.Repo.GetUserStateTypeByID(ctx, id)
 }
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"context"
+	"totesbackend/dtos"
 	"totesbackend/models"
 	"totesbackend/repositories"
 )
@@ -13,26 +15,30 @@ func NewUserTypeService(repo *repositories.UserTypeRepository) *UserTypeService
 	return &UserTypeService{Repo: repo}
 }
 
-func (s *UserTypeService) ObtainAllUserTypes() ([]models.UserType, error) {
-	return s.Repo.ObtainAllUserTypes()
+func (s *UserTypeService) ObtainAllUserTypes(ctx context.Context, query dtos.ListQuery) ([]models.UserType, *dtos.PageInfo, error) {
+	return s.Repo.ObtainAllUserTypes(ctx, query)
 }
 
-func (s *UserTypeService) GetUserTypeByID(id uint) (*models.UserType, error) {
-	return s.Repo.GetUserTypeByID(id)
+func (s *UserTypeService) GetUserTypeByID(ctx context.Context, id uint) (*models.UserType, error) {
+	return s.Repo.GetUserTypeByID(ctx, id)
 }
 
-func (s *UserTypeService) GetRolesForUserType(userTypeID uint) ([]uint, error) {
-	return s.Repo.GetRolesForUserType(userTypeID)
+func (s *UserTypeService) GetRolesForUserType(ctx context.Context, userTypeID uint) ([]uint, error) {
+	return s.Repo.GetRolesForUserType(ctx, userTypeID)
 }
 
-func (s *UserTypeService) Exists(userTypeID uint) (bool, error) {
-	return s.Repo.Exists(userTypeID)
+func (s *UserTypeService) Exists(ctx context.Context, userTypeID uint) (bool, error) {
+	return s.Repo.Exists(ctx, userTypeID)
 }
 
-func (s *UserTypeService) SearchUserTypesByID(query string) ([]models.UserType, error) {
-	return s.Repo.SearchUserTypesByID(query)
+func (s *UserTypeService) SearchUserTypesByID(ctx context.Context, query string) ([]models.UserType, error) {
+	return s.Repo.SearchUserTypesByID(ctx, query)
 }
 
-func (s *UserTypeService) SearchUserTypesByName(query string) ([]models.UserType, error) {
-	return s.Repo.SearchUserTypesByName(query)
+func (s *UserTypeService) SearchUserTypesByName(ctx context.Context, query string) ([]models.UserType, error) {
+	return s.Repo.SearchUserTypesByName(ctx, query)
+}
+
+func (s *UserTypeService) SetRequireTwoFactor(ctx context.Context, id uint, required bool) error {
+	return s.Repo.SetRequireTwoFactor(ctx, id, required)
 }
@@ -1,15 +1,127 @@
 package utils
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"totesbackend/config"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
+const (
+	argon2idPrefix = "$argon2id$"
+	argon2SaltLen  = 16
+	argon2KeyLen   = 32
+)
+
+// PasswordHasher genera y verifica hashes de contraseñas. Los servicios
+// dependen de esta interfaz para que el algoritmo y su costo salgan de la
+// configuración.
+type PasswordHasher interface {
+	// Hash genera el hash con el algoritmo y los parámetros configurados.
+	Hash(password string) (string, error)
+	// Verify reconoce el algoritmo por el prefijo del hash guardado, así que
+	// acepta también hashes hechos con otra configuración. needsRehash indica
+	// que la contraseña es correcta pero conviene volver a calcular el hash
+	// con la configuración actual.
+	Verify(password, hash string) (ok bool, needsRehash bool)
+}
+
+// NewPasswordHasher crea el hasher indicado en cfg.Algorithm.
+func NewPasswordHasher(cfg config.HashingConfig) PasswordHasher {
+	return passwordHasher{
+		algorithm:  cfg.Algorithm,
+		bcryptCost: cfg.BcryptCost,
+		argon2: argon2Params{
+			memory:  uint32(cfg.Argon2Memory),
+			time:    uint32(cfg.Argon2Time),
+			threads: uint8(cfg.Argon2Threads),
+		},
+	}
 }
 
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+type passwordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     argon2Params
+}
+
+func (h passwordHasher) Hash(password string) (string, error) {
+	if h.algorithm == config.HashBcrypt {
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		return string(bytes), err
+	}
+
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.argon2.time, h.argon2.memory, h.argon2.threads, argon2KeyLen)
+	return encodeArgon2id(h.argon2, salt, key), nil
+}
+
+func (h passwordHasher) Verify(password, hash string) (bool, bool) {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false, false
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(candidate, key) != 1 {
+			return false, false
+		}
+		return true, h.algorithm != config.HashArgon2id || params != h.argon2 || len(key) != argon2KeyLen
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, h.algorithm != config.HashBcrypt || err != nil || cost != h.bcryptCost
+}
+
+// encodeArgon2id usa el formato PHC que también entienden otras
+// implementaciones: $argon2id$v=19$m=...,t=...,p=...$sal$hash.
+func encodeArgon2id(params argon2Params, salt, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, params.memory, params.time, params.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func decodeArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, fmt.Errorf("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2id version %q", parts[2])
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, fmt.Errorf("malformed argon2id parameters: %w", err)
+	}
+	if params.time == 0 || params.threads == 0 {
+		return params, nil, nil, fmt.Errorf("malformed argon2id parameters %q", parts[3])
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, fmt.Errorf("malformed argon2id hash")
+	}
+	return params, salt, key, nil
 }
@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrUnknownSigningKey indica que el token está firmado con una llave que no
+// está en el JWKS; puede que el proveedor la haya rotado.
+var ErrUnknownSigningKey = errors.New("token is signed with an unknown key")
+
+// JWK es una llave pública de un JWKS (RFC 7517). Sólo se usan las RSA y EC.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// JWKS es el conjunto de llaves públicas con las que el proveedor firma.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// VerifyJWT valida la firma de un JWS compacto con las llaves de keys y
+// devuelve sus claims sin revisarlos. Sólo acepta algoritmos asimétricos; los
+// tokens sin firma o con HMAC se rechazan.
+func VerifyJWT(token string, keys JWKS) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+
+	hash, family, err := jwtAlgorithm(header.Alg)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keys.find(header.Kid, family)
+	if err != nil {
+		return nil, err
+	}
+	if key.Alg != "" && key.Alg != header.Alg {
+		return nil, fmt.Errorf("key %q is for %s, token uses %s", key.Kid, key.Alg, header.Alg)
+	}
+	public, err := key.publicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	if err := verifySignature(header.Alg, public, hash, h.Sum(nil), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, dest interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, dest)
+}
+
+// jwtAlgorithm devuelve el hash y el tipo de llave (RSA o EC) del algoritmo.
+func jwtAlgorithm(alg string) (crypto.Hash, string, error) {
+	switch alg {
+	case "RS256", "PS256":
+		return crypto.SHA256, "RSA", nil
+	case "RS384", "PS384":
+		return crypto.SHA384, "RSA", nil
+	case "RS512", "PS512":
+		return crypto.SHA512, "RSA", nil
+	case "ES256":
+		return crypto.SHA256, "EC", nil
+	case "ES384":
+		return crypto.SHA384, "EC", nil
+	case "ES512":
+		return crypto.SHA512, "EC", nil
+	}
+	return 0, "", fmt.Errorf("unsupported token algorithm %q", alg)
+}
+
+// find busca la llave por kid. Si el token no trae kid sólo sirve cuando hay
+// una única llave del tipo.
+func (s JWKS) find(kid, kty string) (*JWK, error) {
+	var candidates []*JWK
+	for i := range s.Keys {
+		key := &s.Keys[i]
+		if key.Kty != kty || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		if kid != "" && key.Kid == kid {
+			return key, nil
+		}
+		candidates = append(candidates, key)
+	}
+	if kid == "" && len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	return nil, ErrUnknownSigningKey
+}
+
+func (k *JWK) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid modulus: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("key %q: invalid exponent", k.Kid)
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+		if public.N.BitLen() < 2048 {
+			return nil, fmt.Errorf("key %q: RSA keys must have at least 2048 bits", k.Kid)
+		}
+		return public, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("key %q: unsupported curve %q", k.Kid, k.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("key %q: invalid coordinates", k.Kid)
+		}
+		public := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(public.X, public.Y) {
+			return nil, fmt.Errorf("key %q: point is not on the curve", k.Kid)
+		}
+		return public, nil
+	}
+	return nil, fmt.Errorf("key %q: unsupported key type %q", k.Kid, k.Kty)
+}
+
+func verifySignature(alg string, public crypto.PublicKey, hash crypto.Hash, digest, signature []byte) error {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if strings.HasPrefix(alg, "PS") {
+			return rsa.VerifyPSS(key, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.VerifyPKCS1v15(key, hash, digest, signature)
+	case *ecdsa.PublicKey:
+		// JWS usa r||s de tamaño fijo en lugar de DER
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return errors.New("invalid token signature")
+		}
+		return nil
+	}
+	return errors.New("unsupported key type")
+}
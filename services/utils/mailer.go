@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+	"totesbackend/config"
+)
+
+// ErrMailerNotConfigured indica que no se configuró el servidor SMTP.
+var ErrMailerNotConfigured = errors.New("smtp server is not configured")
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// MailSender envía correos. Los servicios dependen de esta interfaz para
+// poder cambiar el transporte (SMTP, log) según la configuración.
+type MailSender interface {
+	Send(to []string, subject string, body string, attachments ...Attachment) error
+}
+
+// NewMailSender crea el transporte indicado en cfg.Sender.
+func NewMailSender(cfg config.SMTPConfig) MailSender {
+	if cfg.Sender == "log" {
+		return LogMailer{}
+	}
+	return NewMailer(cfg)
+}
+
+// LogMailer escribe los correos en el log en lugar de enviarlos. Sólo para
+// desarrollo: el cuerpo puede llevar datos sensibles como tokens.
+type LogMailer struct{}
+
+func (LogMailer) Send(to []string, subject string, body string, attachments ...Attachment) error {
+	names := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		names = append(names, attachment.Filename)
+	}
+	slog.Info("mail not sent, MAIL_SENDER is log", "to", to, "subject", subject, "body", body, "attachments", names)
+	return nil
+}
+
+// Mailer envía correos a través de un servidor SMTP. Sin usuario se envía sin
+// autenticación, lo que permite apuntarlo a un servidor de correo local de pruebas.
+type Mailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// NewMailer crea el cliente SMTP con la configuración cargada al arrancar.
+func NewMailer(cfg config.SMTPConfig) *Mailer {
+	return &Mailer{
+		Host:     cfg.Host,
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: cfg.Password,
+		From:     cfg.From,
+	}
+}
+
+func (m *Mailer) Send(to []string, subject string, body string, attachments ...Attachment) error {
+	if m.Host == "" || m.From == "" {
+		return ErrMailerNotConfigured
+	}
+
+	message, err := m.buildMessage(to, subject, body, attachments)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	return smtp.SendMail(fmt.Sprintf("%s:%d", m.Host, m.Port), auth, m.From, to, message)
+}
+
+func (m *Mailer) buildMessage(to []string, subject string, body string, attachments []Attachment) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {`attachment; filename="` + attachment.Filename + `"`},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Las líneas codificadas en base64 no deben superar los 76 caracteres
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+				return nil, err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
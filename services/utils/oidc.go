@@ -0,0 +1,302 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"totesbackend/config"
+)
+
+const (
+	// oidcDiscoveryTTL es cada cuánto se vuelven a leer la configuración y
+	// las llaves del proveedor.
+	oidcDiscoveryTTL = time.Hour
+	// oidcKeysMinRefresh limita las lecturas extra del JWKS cuando llega un
+	// token con una llave desconocida.
+	oidcKeysMinRefresh = time.Minute
+	// oidcClockSkew es el desfase de reloj tolerado al revisar exp, iat y nbf.
+	oidcClockSkew = time.Minute
+)
+
+// OIDCProvider habla con un proveedor OpenID Connect: descubre sus endpoints,
+// arma la URL de autorización con PKCE, canjea el código y valida el ID token
+// con las llaves del JWKS. La configuración descubierta y las llaves se
+// guardan en memoria.
+type OIDCProvider struct {
+	Config config.OIDCConfig
+	Client *http.Client
+
+	mu           sync.Mutex
+	discovery    *oidcDiscovery
+	discoveredAt time.Time
+	keys         JWKS
+	keysAt       time.Time
+}
+
+type oidcDiscovery struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	CodeChallengeMethods  []string `json:"code_challenge_methods_supported"`
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func NewOIDCProvider(cfg config.OIDCConfig) *OIDCProvider {
+	return &OIDCProvider{Config: cfg, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// NewPKCEVerifier genera el code_verifier de PKCE (RFC 7636).
+func NewPKCEVerifier() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// PKCEChallenge es el code_challenge S256 del verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL arma la URL del proveedor a la que se envía al usuario.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	scopes := append([]string{"openid"}, p.Config.Scopes...)
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.Config.ClientID},
+		"redirect_uri":          {p.Config.RedirectURL},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange canjea el código de autorización y devuelve el ID token sin validar.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.Config.RedirectURL},
+		"client_id":     {p.Config.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.Config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.Config.ClientID), url.QueryEscape(p.Config.ClientSecret))
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token oidcTokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return "", fmt.Errorf("token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		return "", fmt.Errorf("token endpoint returned %d: %s %s", resp.StatusCode, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return "", errors.New("token response has no id_token")
+	}
+	return token.IDToken, nil
+}
+
+// VerifyIDToken valida la firma y los claims estándar del ID token (emisor,
+// audiencia, vigencia y nonce) y devuelve sus claims.
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, rawToken, nonce string, now time.Time) (map[string]interface{}, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := p.signingKeys(ctx, discovery, false)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := VerifyJWT(rawToken, keys)
+	if errors.Is(err, ErrUnknownSigningKey) {
+		// El proveedor pudo haber rotado las llaves
+		if keys, err = p.signingKeys(ctx, discovery, true); err == nil {
+			claims, err = VerifyJWT(rawToken, keys)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if issuer, _ := claims["iss"].(string); issuer != discovery.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", issuer)
+	}
+	audiences := ClaimStrings(claims["aud"])
+	if !containsString(audiences, p.Config.ClientID) {
+		return nil, errors.New("token is not intended for this client")
+	}
+	if azp, ok := claims["azp"].(string); (ok || len(audiences) > 1) && azp != p.Config.ClientID {
+		return nil, errors.New("token was issued to another client")
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.After(time.Unix(int64(exp), 0).Add(oidcClockSkew)) {
+		return nil, errors.New("token is expired")
+	}
+	iat, ok := claims["iat"].(float64)
+	if !ok || time.Unix(int64(iat), 0).After(now.Add(oidcClockSkew)) {
+		return nil, errors.New("token is issued in the future")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && time.Unix(int64(nbf), 0).After(now.Add(oidcClockSkew)) {
+		return nil, errors.New("token is not valid yet")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce == "" || tokenNonce != nonce {
+		return nil, errors.New("token nonce does not match")
+	}
+	return claims, nil
+}
+
+// discover lee la configuración del proveedor y la guarda una hora. Si falla
+// la lectura y hay una anterior se sigue usando esa.
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil && time.Since(p.discoveredAt) < oidcDiscoveryTTL {
+		return p.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(p.Config.IssuerURL, "/")
+	var discovery oidcDiscovery
+	err := p.getJSON(ctx, issuer+"/.well-known/openid-configuration", &discovery)
+	if err == nil {
+		err = discovery.validate(issuer)
+	}
+	if err != nil {
+		if p.discovery != nil {
+			return p.discovery, nil
+		}
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+
+	p.discovery = &discovery
+	p.discoveredAt = time.Now()
+	return p.discovery, nil
+}
+
+func (d *oidcDiscovery) validate(issuer string) error {
+	if strings.TrimSuffix(d.Issuer, "/") != issuer {
+		return fmt.Errorf("issuer %q does not match the configured %q", d.Issuer, issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return errors.New("authorization_endpoint, token_endpoint and jwks_uri are required")
+	}
+	if len(d.CodeChallengeMethods) > 0 && !containsString(d.CodeChallengeMethods, "S256") {
+		return errors.New("provider does not support PKCE with S256")
+	}
+	return nil
+}
+
+// signingKeys devuelve el JWKS guardado o lo vuelve a leer si venció. Con
+// refresh lo lee aunque no haya vencido, pero no más de una vez por minuto.
+func (p *OIDCProvider) signingKeys(ctx context.Context, discovery *oidcDiscovery, refresh bool) (JWKS, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	age := time.Since(p.keysAt)
+	if len(p.keys.Keys) > 0 && age < oidcDiscoveryTTL && (!refresh || age < oidcKeysMinRefresh) {
+		return p.keys, nil
+	}
+
+	var keys JWKS
+	if err := p.getJSON(ctx, discovery.JWKSURI, &keys); err != nil {
+		if len(p.keys.Keys) > 0 {
+			return p.keys, nil
+		}
+		return JWKS{}, fmt.Errorf("oidc jwks: %w", err)
+	}
+	p.keys = keys
+	p.keysAt = time.Now()
+	return keys, nil
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, target string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", target, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(dest)
+}
+
+// ClaimStrings lee un claim que puede ser un texto o una lista de textos,
+// como aud o los grupos.
+func ClaimStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"totesbackend/config"
+	"totesbackend/services/utils/oidctest"
+)
+
+const testClientID = "totes-backend"
+
+func newTestProvider(t *testing.T) (*OIDCProvider, *oidctest.IdP) {
+	t.Helper()
+	idp := oidctest.New(t, testClientID)
+	provider := NewOIDCProvider(config.OIDCConfig{
+		IssuerURL:   idp.Issuer,
+		ClientID:    testClientID,
+		RedirectURL: "https://app.example.com/sso",
+		Scopes:      []string{"email"},
+	})
+	return provider, idp
+}
+
+func TestOIDCProviderCodeFlowWithPKCE(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, err := NewPKCEVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", PKCEChallenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	if !strings.HasPrefix(authURL, idp.Issuer+"/authorize?") {
+		t.Fatalf("authorization URL %q does not use the discovered endpoint", authURL)
+	}
+	parsed, _ := url.Parse(authURL)
+	if got := parsed.Query().Get("scope"); got != "openid email" {
+		t.Errorf("scope = %q, want %q", got, "openid email")
+	}
+
+	code, state, err := idp.Authorize(authURL, map[string]interface{}{"email": "ana@example.com"})
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	if state != "state-1" {
+		t.Errorf("state = %q, want state-1", state)
+	}
+
+	rawToken, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, rawToken, "nonce-1", time.Now())
+	if err != nil {
+		t.Fatalf("VerifyIDToken: %v", err)
+	}
+	if claims["email"] != "ana@example.com" {
+		t.Errorf("email claim = %v", claims["email"])
+	}
+
+	if _, err := provider.Exchange(ctx, code, verifier); err == nil {
+		t.Error("a code must not be exchanged twice")
+	}
+}
+
+func TestOIDCProviderExchangeRejectsWrongVerifier(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := NewPKCEVerifier()
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", PKCEChallenge(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _, err := idp.Authorize(authURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherVerifier, _ := NewPKCEVerifier()
+	if _, err := provider.Exchange(ctx, code, otherVerifier); err == nil {
+		t.Fatal("exchange with another code_verifier must fail")
+	}
+}
+
+func TestOIDCProviderDiscoveryFailsForUnknownIssuer(t *testing.T) {
+	idp := oidctest.New(t, testClientID)
+	provider := NewOIDCProvider(config.OIDCConfig{IssuerURL: idp.Issuer + "/other", ClientID: testClientID})
+
+	if _, err := provider.AuthCodeURL(context.Background(), "s", "n", "c"); err == nil {
+		t.Fatal("discovery of an unknown issuer must fail")
+	}
+}
+
+func TestOIDCProviderVerifyIDTokenRejects(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	with := func(changes map[string]interface{}) map[string]interface{} {
+		claims := idp.StandardClaims("nonce")
+		for name, value := range changes {
+			if value == nil {
+				delete(claims, name)
+				continue
+			}
+			claims[name] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		nonce string
+		want  string
+	}{
+		{"wrong signature", oidctest.SignRS256(otherKey, idp.KeyID, with(nil)), "nonce", "verification error"},
+		{"unknown kid", oidctest.SignRS256(idp.Key, "rotated-away", with(nil)), "nonce", "unknown key"},
+		{"tampered payload", tamper(idp.Sign(with(nil))), "nonce", "verification error"},
+		{"wrong issuer", idp.Sign(with(map[string]interface{}{"iss": "https://evil.example.com"})), "nonce", "unexpected issuer"},
+		{"wrong audience", idp.Sign(with(map[string]interface{}{"aud": "another-client"})), "nonce", "not intended for this client"},
+		{"audience list without azp", idp.Sign(with(map[string]interface{}{"aud": []string{testClientID, "another-client"}})), "nonce", "issued to another client"},
+		{"expired", idp.Sign(with(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})), "nonce", "expired"},
+		{"without exp", idp.Sign(with(map[string]interface{}{"exp": nil})), "nonce", "expired"},
+		{"issued in the future", idp.Sign(with(map[string]interface{}{"iat": now.Add(time.Hour).Unix()})), "nonce", "in the future"},
+		{"nonce mismatch", idp.Sign(with(nil)), "another-nonce", "nonce does not match"},
+		{"without nonce", idp.Sign(with(map[string]interface{}{"nonce": nil})), "", "nonce does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := provider.VerifyIDToken(ctx, tt.token, tt.nonce, now)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := provider.VerifyIDToken(ctx, idp.Sign(with(nil)), "nonce", now); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+}
+
+func TestVerifyJWTRejectsUnsignedAndHMACTokens(t *testing.T) {
+	_, idp := newTestProvider(t)
+	valid := idp.Sign(idp.StandardClaims("nonce"))
+	parts := strings.Split(valid, ".")
+
+	keys := JWKS{Keys: []JWK{{Kty: "RSA", Kid: idp.KeyID}}}
+	for _, header := range []string{
+		`{"alg":"none","kid":"test-key"}`,
+		`{"alg":"HS256","kid":"test-key"}`,
+	} {
+		token := encodeSegment(header) + "." + parts[1] + "." + parts[2]
+		if _, err := VerifyJWT(token, keys); err == nil {
+			t.Errorf("token with header %s must be rejected", header)
+		}
+	}
+
+	if _, err := VerifyJWT(valid, JWKS{}); !errors.Is(err, ErrUnknownSigningKey) {
+		t.Errorf("empty key set: err = %v, want ErrUnknownSigningKey", err)
+	}
+}
+
+// tamper cambia el payload del token sin volver a firmarlo.
+func tamper(token string) string {
+	parts := strings.Split(token, ".")
+	parts[1] = encodeSegment(`{"iss":"x","aud":"totes-backend","nonce":"nonce"}`)
+	return strings.Join(parts, ".")
+}
+
+func encodeSegment(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
@@ -0,0 +1,195 @@
+// Package oidctest es un proveedor OpenID Connect de prueba que corre en un
+// httptest.Server. Publica la configuración de descubrimiento y el JWKS,
+// atiende la autorización y el canje del código con PKCE (S256) y firma ID
+// tokens con una llave RSA propia. Sirve para probar el inicio de sesión con
+// el proveedor sin depender de uno real.
+package oidctest
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// IdP es el proveedor de prueba. Issuer es la URL del servidor y ClientID el
+// único cliente que acepta.
+type IdP struct {
+	Server   *httptest.Server
+	Issuer   string
+	ClientID string
+	Key      *rsa.PrivateKey
+	KeyID    string
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+// grant es un código de autorización pendiente de canje.
+type grant struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	claims      map[string]interface{}
+}
+
+// New arranca el proveedor; se detiene al terminar la prueba.
+func New(t testing.TB, clientID string) *IdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating idp key: %v", err)
+	}
+
+	idp := &IdP{ClientID: clientID, Key: key, KeyID: "test-key", grants: map[string]grant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.handleDiscovery)
+	mux.HandleFunc("/jwks", idp.handleJWKS)
+	mux.HandleFunc("/token", idp.handleToken)
+	idp.Server = httptest.NewServer(mux)
+	idp.Issuer = idp.Server.URL
+	t.Cleanup(idp.Server.Close)
+	return idp
+}
+
+// Authorize hace lo que el proveedor hace al volver el usuario: lee la URL de
+// autorización armada por el cliente y devuelve un código cuyo ID token
+// llevará claims además de los estándar (iss, aud, exp, iat, nonce). Un
+// claim con valor nil quita el estándar del mismo nombre.
+func (idp *IdP) Authorize(authURL string, claims map[string]interface{}) (code, state string, err error) {
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := parsed.Query()
+	if query.Get("response_type") != "code" || query.Get("client_id") != idp.ClientID {
+		return "", "", errors.New("unexpected response_type or client_id")
+	}
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		return "", "", errors.New("authorization request without PKCE S256")
+	}
+
+	code = randomString()
+	idp.mu.Lock()
+	idp.grants[code] = grant{
+		redirectURI: query.Get("redirect_uri"),
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		claims:      claims,
+	}
+	idp.mu.Unlock()
+	return code, query.Get("state"), nil
+}
+
+// StandardClaims son los claims que el proveedor pone en todo ID token.
+func (idp *IdP) StandardClaims(nonce string) map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":   idp.Issuer,
+		"aud":   idp.ClientID,
+		"sub":   "subject-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": nonce,
+	}
+}
+
+// Sign firma claims con RS256 y la llave del proveedor.
+func (idp *IdP) Sign(claims map[string]interface{}) string {
+	return SignRS256(idp.Key, idp.KeyID, claims)
+}
+
+// SignRS256 firma claims como JWS compacto con key e indica kid en la
+// cabecera.
+func SignRS256(key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (idp *IdP) handleDiscovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                           idp.Issuer,
+		"authorization_endpoint":           idp.Issuer + "/authorize",
+		"token_endpoint":                   idp.Issuer + "/token",
+		"jwks_uri":                         idp.Issuer + "/jwks",
+		"code_challenge_methods_supported": []string{"S256"},
+	})
+}
+
+func (idp *IdP) handleJWKS(w http.ResponseWriter, _ *http.Request) {
+	public := idp.Key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": idp.KeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+// handleToken canjea el código una sola vez y sólo con el code_verifier que
+// corresponde al code_challenge de la autorización.
+func (idp *IdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	idp.mu.Lock()
+	g, ok := idp.grants[code]
+	delete(idp.grants, code)
+	idp.mu.Unlock()
+	if !ok || r.PostForm.Get("client_id") != idp.ClientID || r.PostForm.Get("redirect_uri") != g.redirectURI {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(verifier[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	claims := idp.StandardClaims(g.nonce)
+	for name, value := range g.claims {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": idp.Sign(claims), "token_type": "Bearer"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomString() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// encryptedPrefix marca los valores cifrados. Los que no lo llevan se
+// guardaron sin clave y se devuelven tal cual, así se puede configurar la
+// clave después sin migrar los datos.
+const encryptedPrefix = "enc:"
+
+// SecretBox cifra con AES-256-GCM secretos que hay que poder leer de vuelta,
+// como las semillas TOTP. Sin clave no cifra.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox deriva la clave AES de passphrase con SHA-256.
+func NewSecretBox(passphrase string) (*SecretBox, error) {
+	if passphrase == "" {
+		return &SecretBox{}, nil
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// IsSealed indica si value fue cifrado por Seal.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+func (b *SecretBox) Seal(plaintext string) (string, error) {
+	if b.aead == nil {
+		return plaintext, nil
+	}
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (b *SecretBox) Open(value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+	if b.aead == nil {
+		return "", errors.New("secret is encrypted but no encryption key is configured")
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < b.aead.NonceSize() {
+		return "", errors.New("encrypted secret is too short")
+	}
+	nonce, ciphertext := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,186 @@
+// Package validation registra las reglas de validación propias de la
+// aplicación en el validador de gin y traduce los errores de binding a errores
+// de dominio que listan todos los campos inválidos.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"totesbackend/apperrors"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// Options ajusta las reglas de negocio que dependen de la configuración.
+type Options struct {
+	// EnforcePriceFloor hace que la regla pricefloor rechace precios de venta
+	// inferiores al precio de compra. Si es false la regla no se aplica.
+	EnforcePriceFloor bool
+}
+
+// FieldError describe un campo que no cumple una regla.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Setup registra en el validador de gin:
+//   - exists=<tabla>: el valor es el id de una fila existente de la tabla.
+//   - pricefloor=<Campo>: el valor no es menor que el del campo indicado,
+//     sólo si opts.EnforcePriceFloor está activo.
+//
+// Además hace que los errores usen el nombre JSON de los campos.
+func Setup(db *gorm.DB, opts Options) error {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("gin validator engine is not go-playground/validator")
+	}
+
+	validate.RegisterTagNameFunc(jsonFieldName)
+
+	if err := validate.RegisterValidation("exists", existsRule(db)); err != nil {
+		return err
+	}
+	return validate.RegisterValidation("pricefloor", priceFloorRule(opts.EnforcePriceFloor))
+}
+
+// BindError traduce el error devuelto por ShouldBindJSON. Los errores de reglas
+// y de tipo de campo se devuelven juntos como ErrValidation (422); un cuerpo
+// que no es JSON válido se devuelve como ErrBadRequest (400).
+func BindError(err error) error {
+	if fields := fieldErrors(err, ""); len(fields) > 0 {
+		return apperrors.ErrValidation.WithDetail("fields", fields)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return apperrors.ErrValidation.WithDetail("fields", []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf("must be of type %s", typeErr.Type.String()),
+		}})
+	}
+
+	return apperrors.ErrBadRequest.Wrap(err)
+}
+
+// ParamError devuelve ErrValidation (422) para un parámetro de la URL o de la
+// consulta, con la misma forma que los errores del cuerpo.
+func ParamError(field, rule, param, message string) error {
+	return apperrors.ErrValidation.WithDetail("fields", []FieldError{{
+		Field:   field,
+		Rule:    rule,
+		Param:   param,
+		Message: message,
+	}})
+}
+
+// fieldErrors aplana los errores del validador. Cuando el cuerpo es un arreglo
+// gin devuelve un error por elemento, y el índice se antepone al campo.
+func fieldErrors(err error, prefix string) []FieldError {
+	var sliceErrs binding.SliceValidationError
+	if errors.As(err, &sliceErrs) {
+		var fields []FieldError
+		for i, elemErr := range sliceErrs {
+			fields = append(fields, fieldErrors(elemErr, fmt.Sprintf("%s[%d].", prefix, i))...)
+		}
+		return fields
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+	fields := make([]FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, FieldError{
+			Field:   prefix + fieldPath(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: describe(fe),
+		})
+	}
+	return fields
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// fieldPath quita el nombre del struct raíz: "CreateInvoiceDTO.items[0].stock"
+// queda como "items[0].stock".
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func existsRule(db *gorm.DB) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		var count int64
+		err := db.Table(fl.Param()).Where("id = ?", fl.Field().Interface()).Count(&count).Error
+		return err == nil && count > 0
+	}
+}
+
+func priceFloorRule(enforce bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		if !enforce {
+			return true
+		}
+		floor := fl.Parent().FieldByName(fl.Param())
+		if !floor.IsValid() || !floor.CanFloat() {
+			return true
+		}
+		return fl.Field().Float() >= floor.Float()
+	}
+}
+
+func describe(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be greater than or equal to " + fe.Param()
+	case "lte":
+		return "must be less than or equal to " + fe.Param()
+	case "min":
+		switch fe.Kind() {
+		case reflect.Slice, reflect.Map:
+			return "must contain at least " + fe.Param() + " item(s)"
+		case reflect.String:
+			return "must be at least " + fe.Param() + " characters long"
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of: " + fe.Param()
+	case "exists":
+		return "does not reference an existing record"
+	case "pricefloor":
+		return "must not be lower than the purchase price"
+	default:
+		return "failed the " + fe.Tag() + " rule"
+	}
+}
@@ -0,0 +1,287 @@
+// Package visibility quita de las respuestas los campos sensibles que quien
+// hace la petición no puede ver. Un campo sensible lleva la etiqueta
+// visible:"<clase>" y se omite sin permiso para la clase, o visible:"<clase>,mask"
+// y se enmascara (sólo cadenas; los demás tipos se omiten).
+package visibility
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Clases de campos sensibles. config.FieldPermissions asocia cada una con el
+// permiso que permite verla.
+const (
+	// ItemCosts es el precio de compra y los gastos adicionales de los ítems
+	ItemCosts = "item_costs"
+	// CustomerContact es el correo, el teléfono y la dirección de los clientes
+	CustomerContact = "customer_contact"
+	// InvoiceEnterpriseData son los datos de la empresa en las facturas
+	InvoiceEnterpriseData = "invoice_enterprise_data"
+)
+
+const tagName = "visible"
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	sensitiveTypes    sync.Map
+)
+
+// Filter devuelve una copia de v sin los campos cuya clase no permite can, o
+// con ellos enmascarados. can se consulta una sola vez por clase. Los valores
+// sin campos sensibles se devuelven tal cual.
+//
+// Los campos sin clase conservan su etiqueta json, así que el resultado se
+// serializa igual que v salvo por los campos ocultos. No se revisan los campos
+// embebidos ni las referencias cíclicas entre tipos, que quedan vacías.
+func Filter(v interface{}, can func(class string) bool) interface{} {
+	if v == nil || !sensitive(reflect.TypeOf(v)) {
+		return v
+	}
+	f := &filter{can: can, classes: map[string]bool{}, types: map[reflect.Type]reflect.Type{}}
+	return f.value(reflect.ValueOf(v)).Interface()
+}
+
+// Mask oculta una cadena dejando lo mínimo para reconocerla: en un correo la
+// primera letra y el dominio, en lo demás los últimos cuatro caracteres.
+func Mask(s string) string {
+	if s == "" {
+		return ""
+	}
+	if at := strings.LastIndex(s, "@"); at > 0 {
+		return s[:1] + "***" + s[at:]
+	}
+	runes := []rune(s)
+	if len(runes) <= 4 {
+		return "****"
+	}
+	return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+}
+
+// sensitive indica si un valor de tipo t puede tener campos sensibles. Las
+// interfaces cuentan como sensibles porque su contenido se conoce al filtrar.
+func sensitive(t reflect.Type) bool {
+	if cached, ok := sensitiveTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	result := sensitiveIn(t, map[reflect.Type]bool{})
+	sensitiveTypes.Store(t, result)
+	return result
+}
+
+func sensitiveIn(t reflect.Type, seen map[reflect.Type]bool) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return sensitiveIn(t.Elem(), seen)
+	case reflect.Struct:
+		if seen[t] || opaque(t) {
+			return false
+		}
+		seen[t] = true
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Anonymous {
+				continue
+			}
+			if field.Tag.Get(tagName) != "" || sensitiveIn(field.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// opaque indica si el tipo se serializa con su propio método, que se perdería
+// al reconstruirlo.
+func opaque(t reflect.Type) bool {
+	return t.Implements(marshalerType) || t.Implements(textMarshalerType) ||
+		reflect.PtrTo(t).Implements(marshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)
+}
+
+type filter struct {
+	can     func(class string) bool
+	classes map[string]bool
+	// types guarda el tipo filtrado de cada tipo; nil mientras se construye
+	types map[reflect.Type]reflect.Type
+}
+
+func (f *filter) allowed(class string) bool {
+	allowed, ok := f.classes[class]
+	if !ok {
+		allowed = f.can(class)
+		f.classes[class] = allowed
+	}
+	return allowed
+}
+
+// hidden indica si el campo se oculta y si se enmascara en lugar de omitirse.
+func (f *filter) hidden(field reflect.StructField) (hide, mask bool) {
+	tag := field.Tag.Get(tagName)
+	if tag == "" {
+		return false, false
+	}
+	class, option, _ := strings.Cut(tag, ",")
+	if f.allowed(class) {
+		return false, false
+	}
+	return true, option == "mask" && field.Type.Kind() == reflect.String
+}
+
+// typeOf devuelve el tipo con el que se serializa un valor de tipo t: el
+// mismo si no le oculta campos, o uno reconstruido sin ellos.
+func (f *filter) typeOf(t reflect.Type) reflect.Type {
+	if !sensitive(t) {
+		return t
+	}
+	if built, ok := f.types[t]; ok {
+		if built == nil {
+			// Referencia cíclica: no se puede reconstruir
+			return t
+		}
+		return built
+	}
+	f.types[t] = nil
+
+	var built reflect.Type
+	switch t.Kind() {
+	case reflect.Ptr:
+		built = reflect.PtrTo(f.typeOf(t.Elem()))
+	case reflect.Slice:
+		built = reflect.SliceOf(f.typeOf(t.Elem()))
+	case reflect.Array:
+		built = reflect.ArrayOf(t.Len(), f.typeOf(t.Elem()))
+	case reflect.Map:
+		built = reflect.MapOf(t.Key(), f.typeOf(t.Elem()))
+	case reflect.Struct:
+		built = f.structType(t)
+	default:
+		built = t
+	}
+	f.types[t] = built
+	return built
+}
+
+func (f *filter) structType(t reflect.Type) reflect.Type {
+	fields := make([]reflect.StructField, 0, t.NumField())
+	changed := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			changed = true
+			continue
+		}
+		if hide, mask := f.hidden(field); hide && !mask {
+			changed = true
+			continue
+		}
+		if !field.Anonymous {
+			fieldType := f.typeOf(field.Type)
+			changed = changed || fieldType != field.Type
+			field.Type = fieldType
+		}
+		fields = append(fields, reflect.StructField{Name: field.Name, Type: field.Type, Tag: field.Tag, Anonymous: field.Anonymous})
+	}
+	if !changed {
+		return t
+	}
+	return reflect.StructOf(fields)
+}
+
+// value copia v al tipo filtrado de su tipo.
+func (f *filter) value(v reflect.Value) reflect.Value {
+	t := v.Type()
+	if !sensitive(t) {
+		return v
+	}
+	target := f.typeOf(t)
+
+	switch t.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(t).Elem()
+		f.assign(out, v.Elem())
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return reflect.Zero(target)
+		}
+		out := reflect.New(target.Elem())
+		f.assign(out.Elem(), v.Elem())
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.Zero(target)
+		}
+		out := reflect.MakeSlice(target, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			f.assign(out.Index(i), v.Index(i))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(target).Elem()
+		for i := 0; i < v.Len(); i++ {
+			f.assign(out.Index(i), v.Index(i))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.Zero(target)
+		}
+		out := reflect.MakeMapWithSize(target, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(target.Elem()).Elem()
+			f.assign(value, iter.Value())
+			out.SetMapIndex(iter.Key(), value)
+		}
+		return out
+	case reflect.Struct:
+		return f.structValue(v, target)
+	}
+	return v
+}
+
+func (f *filter) structValue(v reflect.Value, target reflect.Type) reflect.Value {
+	t := v.Type()
+	out := reflect.New(target).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		outField, ok := target.FieldByName(field.Name)
+		if !ok {
+			continue
+		}
+		dest := out.FieldByIndex(outField.Index)
+		switch hide, mask := f.hidden(field); {
+		case hide && mask:
+			dest.SetString(Mask(v.Field(i).String()))
+		case field.Anonymous:
+			dest.Set(v.Field(i))
+		default:
+			f.assign(dest, v.Field(i))
+		}
+	}
+	return out
+}
+
+// assign guarda en dest el valor filtrado de v. Si dest quedó con el tipo sin
+// filtrar por una referencia cíclica, queda vacío para no exponer v.
+func (f *filter) assign(dest, v reflect.Value) {
+	if dest.Type() != f.typeOf(v.Type()) {
+		if filtered := f.value(v); dest.Kind() == reflect.Interface && filtered.Type().AssignableTo(dest.Type()) {
+			dest.Set(filtered)
+		}
+		return
+	}
+	dest.Set(f.value(v))
+}